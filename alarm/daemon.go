@@ -0,0 +1,153 @@
+package alarm
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+// DefaultPollInterval is how often the daemon reloads events and checks for due alarms
+const DefaultPollInterval = 30 * time.Second
+
+// LoadFunc returns the current set of events
+type LoadFunc func() ([]models.Event, error)
+
+// ExecFunc runs an alarm command
+type ExecFunc func(command string) error
+
+// Daemon fires event commands when their event time is reached
+type Daemon struct {
+	load      LoadFunc
+	exec      ExecFunc
+	logger    *log.Logger
+	lastCheck time.Time
+}
+
+// NewDaemon creates a daemon that only fires alarms scheduled after start,
+// so commands of events that are already past are never run on startup.
+func NewDaemon(load LoadFunc, exec ExecFunc, logger *log.Logger, start time.Time) *Daemon {
+	if exec == nil {
+		exec = ShellExec
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &Daemon{
+		load:      load,
+		exec:      exec,
+		logger:    logger,
+		lastCheck: start,
+	}
+}
+
+// ShellExec runs a command through the system shell (sh, or cmd.exe on Windows)
+func ShellExec(command string) error {
+	return ShellExecIn("", command)
+}
+
+// ShellExecIn runs a command through the system shell in dir (empty = current directory)
+func ShellExecIn(dir, command string) error {
+	cmd := shellCommand(command)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("command failed: %v (output: %s)", err, output)
+	}
+	return nil
+}
+
+// ShellOutput runs a command through the system shell and returns its standard output
+func ShellOutput(command string) (string, error) {
+	output, err := shellCommand(command).Output()
+	if err != nil {
+		return "", fmt.Errorf("command failed: %v", err)
+	}
+	return string(output), nil
+}
+
+// ShellInput runs a command through the system shell with input as its standard input
+func ShellInput(command, input string) error {
+	cmd := shellCommand(command)
+	cmd.Stdin = strings.NewReader(input)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("command failed: %v (output: %s)", err, output)
+	}
+	return nil
+}
+
+// FireTime returns the moment an event's alarm is due, in local time
+func FireTime(event models.Event) time.Time {
+	return time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(),
+		event.Time.Hour(), event.Time.Minute(), 0, 0, time.Local)
+}
+
+// WithCommands returns the events that carry an alarm command
+func WithCommands(events []models.Event) []models.Event {
+	var result []models.Event
+	for _, event := range events {
+		if event.Command != "" {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+// DueEvents returns events with commands firing in the interval (after, upTo], earliest first
+func DueEvents(events []models.Event, after, upTo time.Time) []models.Event {
+	var due []models.Event
+	for _, event := range WithCommands(events) {
+		fireTime := FireTime(event)
+		if fireTime.After(after) && !fireTime.After(upTo) {
+			due = append(due, event)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return FireTime(due[i]).Before(FireTime(due[j]))
+	})
+	return due
+}
+
+// Tick reloads events and runs the commands that became due since the previous tick.
+// It returns the number of commands that were started.
+func (d *Daemon) Tick(now time.Time) (int, error) {
+	events, err := d.load()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load events: %v", err)
+	}
+
+	due := DueEvents(events, d.lastCheck, now)
+	d.lastCheck = now
+
+	for _, event := range due {
+		d.logger.Printf("Alarm: %s %s %s -> %s", event.GetDateString(), event.GetTimeString(), event.Description, event.Command)
+		if err := d.exec(event.Command); err != nil {
+			d.logger.Printf("Alarm command for %q failed: %v", event.Description, err)
+		}
+	}
+
+	return len(due), nil
+}
+
+// Run polls for due alarms every interval until stop is closed
+func (d *Daemon) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			if _, err := d.Tick(now); err != nil {
+				d.logger.Printf("Alarm daemon: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package alarm
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+func testEvent(day, hour, minute int, description, command string) models.Event {
+	return models.Event{
+		Date:        time.Date(2025, 8, day, 0, 0, 0, 0, time.Local),
+		Time:        time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC),
+		Description: description,
+		Command:     command,
+	}
+}
+
+func TestDueEvents(t *testing.T) {
+	events := []models.Event{
+		testEvent(15, 9, 30, "Later", "echo later"),
+		testEvent(15, 9, 0, "Standup", "echo standup"),
+		testEvent(15, 9, 15, "No command", ""),
+		testEvent(15, 8, 0, "Already past", "echo past"),
+		testEvent(16, 9, 0, "Tomorrow", "echo tomorrow"),
+	}
+
+	after := time.Date(2025, 8, 15, 8, 0, 0, 0, time.Local)
+	upTo := time.Date(2025, 8, 15, 9, 30, 0, 0, time.Local)
+
+	due := DueEvents(events, after, upTo)
+	if len(due) != 2 {
+		t.Fatalf("DueEvents() returned %d events, want 2: %v", len(due), due)
+	}
+	if due[0].Description != "Standup" || due[1].Description != "Later" {
+		t.Errorf("DueEvents() order = [%s %s], want [Standup Later]", due[0].Description, due[1].Description)
+	}
+}
+
+func TestDaemon_Tick(t *testing.T) {
+	events := []models.Event{
+		testEvent(15, 9, 0, "Standup", "echo standup"),
+		testEvent(15, 10, 0, "Review", "echo review"),
+	}
+	load := func() ([]models.Event, error) { return events, nil }
+
+	var executed []string
+	exec := func(command string) error {
+		executed = append(executed, command)
+		return nil
+	}
+
+	var logOutput bytes.Buffer
+	start := time.Date(2025, 8, 15, 8, 59, 0, 0, time.Local)
+	daemon := NewDaemon(load, exec, log.New(&logOutput, "", 0), start)
+
+	// First tick reaches the standup alarm only
+	if fired, err := daemon.Tick(start.Add(2 * time.Minute)); err != nil || fired != 1 {
+		t.Fatalf("Tick() = %d, %v; want 1, nil", fired, err)
+	}
+
+	// A repeated tick must not fire the same alarm twice
+	if fired, _ := daemon.Tick(start.Add(3 * time.Minute)); fired != 0 {
+		t.Errorf("Second Tick() fired %d alarms, want 0", fired)
+	}
+
+	if fired, _ := daemon.Tick(start.Add(2 * time.Hour)); fired != 1 {
+		t.Errorf("Third Tick() fired %d alarms, want 1", fired)
+	}
+
+	if len(executed) != 2 || executed[0] != "echo standup" || executed[1] != "echo review" {
+		t.Errorf("Executed commands = %v, want [echo standup echo review]", executed)
+	}
+}
+
+func TestShellExec(t *testing.T) {
+	if err := ShellExec("exit 0"); err != nil {
+		t.Errorf("ShellExec(exit 0) failed: %v", err)
+	}
+	if err := ShellExec("exit 3"); err == nil {
+		t.Error("ShellExec(exit 3) should fail")
+	}
+}
+
+func TestShellExecIn(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "alarm_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := ShellExecIn(tempDir, "echo synced > marker.txt"); err != nil {
+		t.Fatalf("ShellExecIn() failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "marker.txt")); err != nil {
+		t.Errorf("Command should run in the given directory: %v", err)
+	}
+}
+
+func TestShellOutput(t *testing.T) {
+	output, err := ShellOutput("echo sunny")
+	if err != nil {
+		t.Fatalf("ShellOutput() failed: %v", err)
+	}
+	if strings.TrimSpace(output) != "sunny" {
+		t.Errorf("ShellOutput() = %q, want %q", output, "sunny")
+	}
+	if _, err := ShellOutput("exit 2"); err == nil {
+		t.Error("ShellOutput(exit 2) should fail")
+	}
+}
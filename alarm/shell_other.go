@@ -0,0 +1,10 @@
+//go:build !windows
+
+package alarm
+
+import "os/exec"
+
+// shellCommand builds a command run through the POSIX shell
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command("sh", "-c", command)
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package alarm
+
+import "os/exec"
+
+// shellCommand builds a command run through cmd.exe
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command("cmd", "/C", command)
+}
@@ -0,0 +1,229 @@
+package annotations
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/config"
+)
+
+// DefaultSymbol marks annotated days when a source does not configure its own symbol
+const DefaultSymbol = "*"
+
+// Annotation is a short note on a day from an external data source
+type Annotation struct {
+	Symbol string // Single character shown next to the day number
+	Text   string // Full note shown with the selected date's events
+	Color  string // Color string for the symbol and the note, e.g. "yellow"
+}
+
+// Provider supplies the annotations of a day
+type Provider interface {
+	Annotations(date time.Time) []Annotation
+}
+
+// Build loads the providers described in the configuration. Sources that cannot be
+// loaded are reported as an error but do not prevent the others from being built.
+func Build(sources []config.AnnotationSource) ([]Provider, error) {
+	var providers []Provider
+	var problems []string
+
+	for _, source := range sources {
+		provider, err := load(source)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", source.File, err))
+			continue
+		}
+		providers = append(providers, provider)
+	}
+
+	if len(problems) > 0 {
+		return providers, fmt.Errorf("annotation sources failed to load: %s", strings.Join(problems, "; "))
+	}
+	return providers, nil
+}
+
+// ForDate collects the annotations of all providers on a date
+func ForDate(providers []Provider, date time.Time) []Annotation {
+	var result []Annotation
+	for _, provider := range providers {
+		result = append(result, provider.Annotations(date)...)
+	}
+	return result
+}
+
+// load opens the file of a source and parses it with the provider for its type
+func load(source config.AnnotationSource) (Provider, error) {
+	file, err := os.Open(source.File)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	style := Annotation{Symbol: source.Symbol, Color: source.Color}
+	if style.Symbol == "" {
+		style.Symbol = DefaultSymbol
+	}
+
+	switch strings.ToLower(source.Type) {
+	case "rota":
+		return ParseRota(file, source.Title, style)
+	case "csv":
+		return ParseRangesCSV(file, source.Title, style)
+	default:
+		return nil, fmt.Errorf("unknown annotation source type %q", source.Type)
+	}
+}
+
+// parseDate parses a YYYY-MM-DD date in local time
+func parseDate(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", strings.TrimSpace(value), time.Local)
+}
+
+// dayOf normalizes a date to midnight in local time for comparisons
+func dayOf(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// note builds an annotation text, prefixed with the source title when one is set
+func note(title, text string) string {
+	if title == "" {
+		return text
+	}
+	return fmt.Sprintf("%s: %s", title, text)
+}
+
+// rotaShift is one handover of a rota: name is on duty from start until the next shift
+type rotaShift struct {
+	start time.Time
+	name  string
+}
+
+// RotaProvider annotates days with the person on duty according to a rota file
+type RotaProvider struct {
+	title  string
+	style  Annotation
+	shifts []rotaShift // Sorted by start date
+}
+
+// ParseRota reads a rota with one "YYYY-MM-DD name" handover per line, such as an
+// on-call schedule. Each person is on duty from their date until the next handover;
+// a final handover named "-" ends the rota. Blank lines and # comments are skipped.
+func ParseRota(r io.Reader, title string, style Annotation) (*RotaProvider, error) {
+	provider := &RotaProvider{title: title, style: style}
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected \"YYYY-MM-DD name\"", lineNum)
+		}
+		start, err := parseDate(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", lineNum, fields[0])
+		}
+		provider.shifts = append(provider.shifts, rotaShift{start: start, name: strings.TrimSpace(fields[1])})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(provider.shifts, func(i, j int) bool {
+		return provider.shifts[i].start.Before(provider.shifts[j].start)
+	})
+	return provider, nil
+}
+
+// Annotations returns the person on duty on date, if the rota covers it
+func (p *RotaProvider) Annotations(date time.Time) []Annotation {
+	day := dayOf(date)
+	index := sort.Search(len(p.shifts), func(i int) bool {
+		return p.shifts[i].start.After(day)
+	}) - 1
+	if index < 0 || p.shifts[index].name == "-" {
+		return nil
+	}
+
+	annotation := p.style
+	annotation.Text = note(p.title, p.shifts[index].name)
+	return []Annotation{annotation}
+}
+
+// dateRange is a labelled span of days, both ends included
+type dateRange struct {
+	start, end time.Time
+	label      string
+}
+
+// RangesProvider annotates the days inside labelled date ranges, such as school terms
+type RangesProvider struct {
+	title  string
+	style  Annotation
+	ranges []dateRange
+}
+
+// ParseRangesCSV reads "start,end,label" rows with YYYY-MM-DD dates; an empty end
+// makes a single-day range. A first row whose start is not a date is taken as a header.
+func ParseRangesCSV(r io.Reader, title string, style Annotation) (*RangesProvider, error) {
+	provider := &RangesProvider{title: title, style: style}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, record := range records {
+		if len(record) < 3 {
+			return nil, fmt.Errorf("row %d: expected start,end,label", i+1)
+		}
+		start, err := parseDate(record[0])
+		if err != nil {
+			if i == 0 {
+				continue // Header row
+			}
+			return nil, fmt.Errorf("row %d: invalid start date %q", i+1, record[0])
+		}
+		end := start
+		if strings.TrimSpace(record[1]) != "" {
+			if end, err = parseDate(record[1]); err != nil {
+				return nil, fmt.Errorf("row %d: invalid end date %q", i+1, record[1])
+			}
+		}
+		if end.Before(start) {
+			return nil, fmt.Errorf("row %d: end date is before start date", i+1)
+		}
+		provider.ranges = append(provider.ranges, dateRange{start: start, end: end, label: strings.TrimSpace(record[2])})
+	}
+	return provider, nil
+}
+
+// Annotations returns the labels of all ranges containing date
+func (p *RangesProvider) Annotations(date time.Time) []Annotation {
+	day := dayOf(date)
+	var result []Annotation
+	for _, r := range p.ranges {
+		if day.Before(r.start) || day.After(r.end) {
+			continue
+		}
+		annotation := p.style
+		annotation.Text = note(p.title, r.label)
+		result = append(result, annotation)
+	}
+	return result
+}
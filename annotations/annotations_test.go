@@ -0,0 +1,102 @@
+package annotations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestParseRota(t *testing.T) {
+	rota := "# on-call rota\n2025-08-11 Alice\n2025-08-18 Bob\n\n2025-08-25 -\n"
+	provider, err := ParseRota(strings.NewReader(rota), "On call", Annotation{Symbol: "!", Color: "yellow"})
+	if err != nil {
+		t.Fatalf("ParseRota() failed: %v", err)
+	}
+
+	tests := []struct {
+		date time.Time
+		want string
+	}{
+		{day(2025, 8, 10), ""},
+		{day(2025, 8, 11), "On call: Alice"},
+		{day(2025, 8, 17), "On call: Alice"},
+		{day(2025, 8, 18), "On call: Bob"},
+		{day(2025, 8, 25), ""},
+	}
+	for _, tt := range tests {
+		notes := provider.Annotations(tt.date)
+		got := ""
+		if len(notes) > 0 {
+			got = notes[0].Text
+			if notes[0].Symbol != "!" || notes[0].Color != "yellow" {
+				t.Errorf("Annotation style = %q %q, want the configured one", notes[0].Symbol, notes[0].Color)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("Annotations(%s) = %q, want %q", tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+
+	if _, err := ParseRota(strings.NewReader("next week Alice\n"), "", Annotation{}); err == nil {
+		t.Error("ParseRota() should reject lines without a date")
+	}
+}
+
+func TestParseRangesCSV(t *testing.T) {
+	csv := "start,end,label\n2025-09-01,2025-12-19,Autumn term\n2025-10-27,2025-10-31,Half term\n2025-11-14,,Inset day\n"
+	provider, err := ParseRangesCSV(strings.NewReader(csv), "", Annotation{Symbol: "T"})
+	if err != nil {
+		t.Fatalf("ParseRangesCSV() failed: %v", err)
+	}
+
+	if notes := provider.Annotations(day(2025, 8, 31)); len(notes) != 0 {
+		t.Errorf("Annotations before the first term = %v, want none", notes)
+	}
+	if notes := provider.Annotations(day(2025, 10, 28)); len(notes) != 2 || notes[1].Text != "Half term" {
+		t.Errorf("Annotations in half term = %v, want the term and the half term", notes)
+	}
+	if notes := provider.Annotations(day(2025, 11, 14)); len(notes) != 2 || notes[1].Text != "Inset day" {
+		t.Errorf("Annotations on a single-day range = %v", notes)
+	}
+
+	if _, err := ParseRangesCSV(strings.NewReader("2025-09-01,2025-08-01,Backwards\n"), "", Annotation{}); err == nil {
+		t.Error("ParseRangesCSV() should reject ranges ending before they start")
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "annotations_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	rotaFile := filepath.Join(tempDir, "oncall.txt")
+	if err := os.WriteFile(rotaFile, []byte("2025-08-11 Alice\n"), 0644); err != nil {
+		t.Fatalf("Failed to write rota: %v", err)
+	}
+
+	providers, err := Build([]config.AnnotationSource{
+		{Type: "rota", File: rotaFile},
+		{Type: "csv", File: filepath.Join(tempDir, "missing.csv")},
+	})
+	if err == nil {
+		t.Error("Build() should report the missing file")
+	}
+	if len(providers) != 1 {
+		t.Fatalf("Build() returned %d providers, want the rota", len(providers))
+	}
+
+	notes := ForDate(providers, day(2025, 8, 12))
+	if len(notes) != 1 || notes[0].Text != "Alice" || notes[0].Symbol != DefaultSymbol {
+		t.Errorf("ForDate() = %v, want Alice with the default symbol", notes)
+	}
+}
@@ -0,0 +1,265 @@
+// Package apitoken manages the access tokens of integrations reading the calendar over
+// HTTP. Each token has a scope limiting what it may do and, optionally, the networks it
+// may be used from; only a hash of the secret is stored.
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/storage"
+)
+
+// FileName is the file in the data directory that holds the tokens
+const FileName = "tokens.json"
+
+// QueryParameter is the query parameter carrying the token of a request without an
+// Authorization header, e.g. "?access_token=<secret>"
+const QueryParameter = "access_token"
+
+// Scope is what a token is allowed to do
+type Scope string
+
+// ScopeRead reads events, which is all the share server (-share-auth) serves. Scopes for
+// changing events are to be added with the first endpoint that checks them.
+const ScopeRead Scope = "read"
+
+// Scopes lists the valid scopes
+var Scopes = []Scope{ScopeRead}
+
+// ParseScope returns the scope named s
+func ParseScope(s string) (Scope, error) {
+	for _, scope := range Scopes {
+		if string(scope) == strings.ToLower(strings.TrimSpace(s)) {
+			return scope, nil
+		}
+	}
+	return "", fmt.Errorf("invalid scope %q: expected read", s)
+}
+
+// Allows reports whether a token of scope s may perform an operation that needs
+// scope need. A scope this version does not know, e.g. from an edited tokens file,
+// allows nothing.
+func (s Scope) Allows(need Scope) bool {
+	return s == need
+}
+
+// Errors of Authorize; a handler answers ErrUnauthorized with 401 and the others with 403
+var (
+	ErrUnauthorized = errors.New("missing or unknown access token")
+	ErrScope        = errors.New("access token scope does not allow this")
+	ErrAddress      = errors.New("access token is not allowed from this address")
+)
+
+// Token is a stored access token
+type Token struct {
+	ID      string    `json:"id"`              // Short identifier for listing and revoking
+	Name    string    `json:"name,omitempty"`  // What the token is for, e.g. "home dashboard"
+	Scope   Scope     `json:"scope"`           // What the token may do
+	Allow   []string  `json:"allow,omitempty"` // Networks (CIDR) or addresses the token may be used from; empty allows any
+	Hash    string    `json:"hash"`            // SHA-256 of the secret, hex encoded
+	Created time.Time `json:"created"`
+}
+
+// AllowsAddress reports whether the token may be used from ip
+func (t Token) AllowsAddress(ip net.IP) bool {
+	if len(t.Allow) == 0 {
+		return true
+	}
+	for _, allowed := range t.Allow {
+		if _, network, err := net.ParseCIDR(allowed); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+		} else if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAllowlist splits a comma-separated list of networks and addresses, rejecting
+// entries that are neither
+func ParseAllowlist(s string) ([]string, error) {
+	var allow []string
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(entry); err != nil && net.ParseIP(entry) == nil {
+			return nil, fmt.Errorf("invalid address or network %q: expected e.g. 192.168.1.0/24 or 10.0.0.5", entry)
+		}
+		allow = append(allow, entry)
+	}
+	return allow, nil
+}
+
+// Store holds the tokens of the data directory
+type Store struct {
+	path   string
+	tokens []Token
+}
+
+// NewStore returns the token store of dataDir; call Load before use
+func NewStore(dataDir string) *Store {
+	return &Store{path: filepath.Join(dataDir, FileName)}
+}
+
+// Load reads the tokens file; a missing file holds no tokens
+func (s *Store) Load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.tokens = nil
+			return nil
+		}
+		return fmt.Errorf("failed to read access tokens: %v", err)
+	}
+	var tokens []Token
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return fmt.Errorf("failed to decode access tokens: %v", err)
+	}
+	s.tokens = tokens
+	return nil
+}
+
+// Save writes the tokens file, readable by the owner only
+func (s *Store) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	data, err := json.MarshalIndent(s.tokens, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode access tokens: %v", err)
+	}
+	err = storage.WriteFileAtomic(s.path, 0600, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write access tokens: %v", err)
+	}
+	return nil
+}
+
+// Tokens returns the stored tokens, oldest first
+func (s *Store) Tokens() []Token {
+	return append([]Token(nil), s.tokens...)
+}
+
+// Create adds a token and returns it with its secret, which is not stored and cannot
+// be shown again
+func (s *Store) Create(name string, scope Scope, allow []string, now time.Time) (Token, string, error) {
+	secret, err := randomHex(24)
+	if err != nil {
+		return Token{}, "", err
+	}
+	id, err := randomHex(4)
+	if err != nil {
+		return Token{}, "", err
+	}
+	token := Token{ID: id, Name: name, Scope: scope, Allow: allow, Hash: hashSecret(secret), Created: now}
+	s.tokens = append(s.tokens, token)
+	return token, secret, nil
+}
+
+// Revoke removes the token with id, reporting whether there was one
+func (s *Store) Revoke(id string) bool {
+	for i, token := range s.tokens {
+		if token.ID == id {
+			s.tokens = append(s.tokens[:i], s.tokens[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the token whose secret is secret
+func (s *Store) Lookup(secret string) (Token, bool) {
+	hash := []byte(hashSecret(secret))
+	for _, token := range s.tokens {
+		if subtle.ConstantTimeCompare(hash, []byte(token.Hash)) == 1 {
+			return token, true
+		}
+	}
+	return Token{}, false
+}
+
+// Authorize checks the bearer token of r for an operation that needs scope need, and
+// that the client address is on the token's allowlist. The token is sent as an
+// "Authorization: Bearer" header or, by browsers opening a link, as the access_token
+// query parameter.
+func (s *Store) Authorize(r *http.Request, need Scope) (Token, error) {
+	secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		secret = r.URL.Query().Get(QueryParameter)
+	}
+	if secret == "" {
+		return Token{}, ErrUnauthorized
+	}
+	token, ok := s.Lookup(strings.TrimSpace(secret))
+	if !ok {
+		return Token{}, ErrUnauthorized
+	}
+	if !token.AllowsAddress(clientIP(r)) {
+		return Token{}, ErrAddress
+	}
+	if !token.Scope.Allows(need) {
+		return Token{}, ErrScope
+	}
+	return token, nil
+}
+
+// Require wraps next so that it only serves requests whose token allows need
+func (s *Store) Require(need Scope, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := s.Authorize(r, need); err != nil {
+			status := http.StatusForbidden
+			if errors.Is(err, ErrUnauthorized) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="calendar"`)
+				status = http.StatusUnauthorized
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the address r came from; forwarding headers are ignored since they
+// are set by the client
+func clientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// hashSecret returns the stored form of a secret
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomHex returns n random bytes, hex encoded
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create an access token: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,147 @@
+package apitoken
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScope_Allows(t *testing.T) {
+	tests := []struct {
+		scope, need Scope
+		want        bool
+	}{
+		{ScopeRead, ScopeRead, true},
+		{Scope("full"), ScopeRead, false},
+		{"", ScopeRead, false},
+	}
+	for _, tt := range tests {
+		if got := tt.scope.Allows(tt.need); got != tt.want {
+			t.Errorf("%s.Allows(%s) = %v, want %v", tt.scope, tt.need, got, tt.want)
+		}
+	}
+	for _, name := range []string{"admin", "create", "full"} {
+		if _, err := ParseScope(name); err == nil {
+			t.Errorf("ParseScope(%s) should fail", name)
+		}
+	}
+	if scope, err := ParseScope(" Read "); err != nil || scope != ScopeRead {
+		t.Errorf("ParseScope(Read) = %q, %v", scope, err)
+	}
+}
+
+func TestParseAllowlist(t *testing.T) {
+	allow, err := ParseAllowlist("192.168.1.0/24, 10.0.0.5,,::1")
+	if err != nil || strings.Join(allow, " ") != "192.168.1.0/24 10.0.0.5 ::1" {
+		t.Errorf("ParseAllowlist() = %v, %v", allow, err)
+	}
+	if _, err := ParseAllowlist("example.com"); err == nil {
+		t.Error("ParseAllowlist(example.com) should fail")
+	}
+
+	token := Token{Allow: allow}
+	for ip, want := range map[string]bool{"192.168.1.77": true, "10.0.0.5": true, "10.0.0.6": false, "::1": true} {
+		if got := token.AllowsAddress(net.ParseIP(ip)); got != want {
+			t.Errorf("AllowsAddress(%s) = %v, want %v", ip, got, want)
+		}
+	}
+	if !(Token{}).AllowsAddress(net.ParseIP("203.0.113.9")) {
+		t.Error("A token without allowlist should be usable from anywhere")
+	}
+}
+
+func TestStore_CreateRevoke(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	if err := store.Load(); err != nil {
+		t.Fatalf("Load() without file failed: %v", err)
+	}
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	token, secret, err := store.Create("dashboard", ScopeRead, nil, now)
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), secret) {
+		t.Error("The tokens file should not hold the secret")
+	}
+	if info, err := os.Stat(filepath.Join(dir, FileName)); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("Tokens file mode = %v, want 0600", info.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Data directory holds %d files after Save(), want only the tokens file", len(entries))
+	}
+
+	reloaded := NewStore(dir)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if got, ok := reloaded.Lookup(secret); !ok || got.ID != token.ID || got.Scope != ScopeRead {
+		t.Errorf("Lookup() = %+v, %v; want the created token", got, ok)
+	}
+	if _, ok := reloaded.Lookup(secret + "x"); ok {
+		t.Error("Lookup() of a wrong secret should fail")
+	}
+	if !reloaded.Revoke(token.ID) || reloaded.Revoke(token.ID) {
+		t.Error("Revoke() should remove the token once")
+	}
+	if _, ok := reloaded.Lookup(secret); ok {
+		t.Error("A revoked token should not be found")
+	}
+}
+
+func TestStore_Require(t *testing.T) {
+	store := NewStore(t.TempDir())
+	now := time.Now()
+	_, reader, _ := store.Create("", ScopeRead, nil, now)
+	_, unknown, _ := store.Create("", Scope("write"), nil, now)
+	_, local, _ := store.Create("", ScopeRead, []string{"192.168.1.0/24"}, now)
+
+	handler := store.Require(ScopeRead, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("events"))
+	}))
+	tests := []struct {
+		name, secret, addr string
+		want               int
+	}{
+		{"no token", "", "192.168.1.2:5000", http.StatusUnauthorized},
+		{"unknown token", "nope", "192.168.1.2:5000", http.StatusUnauthorized},
+		{"read scope", reader, "203.0.113.9:5000", http.StatusOK},
+		{"unknown scope", unknown, "192.168.1.2:5000", http.StatusForbidden},
+		{"allowed network", local, "192.168.1.2:5000", http.StatusOK},
+		{"other network", local, "10.0.0.2:5000", http.StatusForbidden},
+		{"query token", "?" + reader, "203.0.113.9:5000", http.StatusOK},
+		{"unknown query token", "?nope", "203.0.113.9:5000", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			req.RemoteAddr = tt.addr
+			if secret, ok := strings.CutPrefix(tt.secret, "?"); ok {
+				req = httptest.NewRequest(http.MethodGet, "/events?"+QueryParameter+"="+secret, nil)
+				req.RemoteAddr = tt.addr
+			} else if tt.secret != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.secret)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package banner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+// Section is one rendered block of the banner
+type Section struct {
+	Title string
+	Lines []string
+}
+
+// Widget produces a banner section for the given moment
+type Widget interface {
+	Section(now time.Time) Section
+}
+
+// EventsFunc returns the events on a date
+type EventsFunc func(date time.Time) []models.Event
+
+// OutputFunc runs a shell command and returns its output
+type OutputFunc func(command string) (string, error)
+
+// maxCommandLines limits how much command output a widget shows
+const maxCommandLines = 5
+
+// Build creates the widgets described in the configuration. Unknown widget types
+// are reported as an error but do not prevent the other widgets from being built.
+func Build(configs []config.BannerWidget, events EventsFunc, output OutputFunc) ([]Widget, error) {
+	var widgets []Widget
+	var unknown []string
+
+	for _, cfg := range configs {
+		switch strings.ToLower(cfg.Type) {
+		case "agenda":
+			widgets = append(widgets, &AgendaWidget{Title: titleOr(cfg.Title, "Today"), Events: events})
+		case "countdowns":
+			widgets = append(widgets, &CountdownWidget{Title: titleOr(cfg.Title, "Countdowns"), Countdowns: cfg.Countdowns})
+		case "weather":
+			widgets = append(widgets, &CommandWidget{Title: titleOr(cfg.Title, "Weather"), Command: cfg.Command, Output: output})
+		case "quote":
+			widgets = append(widgets, &QuoteWidget{Title: titleOr(cfg.Title, "Quote of the day"), File: cfg.File})
+		default:
+			unknown = append(unknown, cfg.Type)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return widgets, fmt.Errorf("unknown banner widget types: %s", strings.Join(unknown, ", "))
+	}
+	return widgets, nil
+}
+
+// Render produces the sections of all widgets, skipping empty ones
+func Render(widgets []Widget, now time.Time) []Section {
+	var sections []Section
+	for _, widget := range widgets {
+		section := widget.Section(now)
+		if len(section.Lines) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// titleOr returns title, or fallback when title is empty
+func titleOr(title, fallback string) string {
+	if title == "" {
+		return fallback
+	}
+	return title
+}
+
+// AgendaWidget lists today's events
+type AgendaWidget struct {
+	Title  string
+	Events EventsFunc
+}
+
+// Section lists today's events in time order
+func (w *AgendaWidget) Section(now time.Time) Section {
+	section := Section{Title: fmt.Sprintf("%s - %s", w.Title, now.Format("Monday, January 2"))}
+
+	events := w.Events(now)
+	if len(events) == 0 {
+		section.Lines = []string{"No events today"}
+		return section
+	}
+	for _, event := range events {
+		section.Lines = append(section.Lines, fmt.Sprintf("%s  %s", event.GetTimeString(), event.Description))
+	}
+	return section
+}
+
+// CountdownWidget shows the days left until pinned dates
+type CountdownWidget struct {
+	Title      string
+	Countdowns []config.Countdown
+}
+
+// Section lists the pinned dates that are today or still ahead
+func (w *CountdownWidget) Section(now time.Time) Section {
+	section := Section{Title: w.Title}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	for _, countdown := range w.Countdowns {
+		date, err := time.ParseInLocation("2006-01-02", countdown.Date, time.Local)
+		if err != nil {
+			section.Lines = append(section.Lines, fmt.Sprintf("%s: invalid date %q", countdown.Name, countdown.Date))
+			continue
+		}
+
+		days := DaysUntil(today, date)
+		switch {
+		case days < 0:
+			continue
+		case days == 0:
+			section.Lines = append(section.Lines, fmt.Sprintf("%s: today!", countdown.Name))
+		case days == 1:
+			section.Lines = append(section.Lines, fmt.Sprintf("%s: tomorrow", countdown.Name))
+		default:
+			section.Lines = append(section.Lines, fmt.Sprintf("%s: %d days", countdown.Name, days))
+		}
+	}
+	return section
+}
+
+// DaysUntil returns the number of calendar days from one date to another
+func DaysUntil(from, to time.Time) int {
+	fromUTC := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toUTC := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toUTC.Sub(fromUTC).Hours() / 24)
+}
+
+// CommandWidget shows the output of a shell command, e.g. a weather forecast
+// from `curl -s wttr.in?format=3`
+type CommandWidget struct {
+	Title   string
+	Command string
+	Output  OutputFunc
+}
+
+// Section runs the command and shows the first lines of its output
+func (w *CommandWidget) Section(now time.Time) Section {
+	section := Section{Title: w.Title}
+	if w.Command == "" || w.Output == nil {
+		return section
+	}
+
+	output, err := w.Output(w.Command)
+	if err != nil {
+		section.Lines = []string{fmt.Sprintf("unavailable: %v", err)}
+		return section
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(output, "\r\n"), "\n") {
+		if len(section.Lines) == maxCommandLines {
+			break
+		}
+		section.Lines = append(section.Lines, strings.TrimRight(line, "\r"))
+	}
+	return section
+}
+
+// QuoteWidget shows one line of a quotes file, changing once per day
+type QuoteWidget struct {
+	Title string
+	File  string
+}
+
+// Section picks the quote for the day of the year
+func (w *QuoteWidget) Section(now time.Time) Section {
+	section := Section{Title: w.Title}
+
+	quotes, err := readQuotes(w.File)
+	if err != nil {
+		section.Lines = []string{fmt.Sprintf("unavailable: %v", err)}
+		return section
+	}
+	if len(quotes) == 0 {
+		return section
+	}
+
+	section.Lines = []string{quotes[now.YearDay()%len(quotes)]}
+	return section
+}
+
+// readQuotes returns the non-empty lines of a quotes file
+func readQuotes(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open quotes file: %v", err)
+	}
+	defer file.Close()
+
+	var quotes []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			quotes = append(quotes, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read quotes file: %v", err)
+	}
+	return quotes, nil
+}
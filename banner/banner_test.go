@@ -0,0 +1,130 @@
+package banner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+func TestBuild(t *testing.T) {
+	configs := []config.BannerWidget{
+		{Type: "agenda"},
+		{Type: "Countdowns", Title: "Coming up"},
+		{Type: "weather", Command: "echo sunny"},
+		{Type: "horoscope"},
+	}
+
+	widgets, err := Build(configs, func(time.Time) []models.Event { return nil }, nil)
+	if err == nil || !strings.Contains(err.Error(), "horoscope") {
+		t.Errorf("Build() error = %v, want unknown type reported", err)
+	}
+	if len(widgets) != 3 {
+		t.Fatalf("Build() returned %d widgets, want 3", len(widgets))
+	}
+	if countdowns, ok := widgets[1].(*CountdownWidget); !ok || countdowns.Title != "Coming up" {
+		t.Errorf("Second widget = %#v, want countdowns titled 'Coming up'", widgets[1])
+	}
+}
+
+func TestAgendaWidget(t *testing.T) {
+	now := time.Date(2025, 8, 15, 8, 0, 0, 0, time.Local)
+	widget := &AgendaWidget{Title: "Today", Events: func(date time.Time) []models.Event {
+		if date.Day() != 15 {
+			return nil
+		}
+		return []models.Event{{
+			Date:        date,
+			Time:        time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			Description: "Standup",
+		}}
+	}}
+
+	section := widget.Section(now)
+	if section.Title != "Today - Friday, August 15" {
+		t.Errorf("Title = %q", section.Title)
+	}
+	if len(section.Lines) != 1 || section.Lines[0] != "09:00  Standup" {
+		t.Errorf("Lines = %v, want [09:00  Standup]", section.Lines)
+	}
+
+	if section := widget.Section(now.AddDate(0, 0, 1)); section.Lines[0] != "No events today" {
+		t.Errorf("Empty day lines = %v", section.Lines)
+	}
+}
+
+func TestCountdownWidget(t *testing.T) {
+	widget := &CountdownWidget{Title: "Countdowns", Countdowns: []config.Countdown{
+		{Name: "Vacation", Date: "2025-08-30"},
+		{Name: "Deadline", Date: "2025-08-16"},
+		{Name: "Launch", Date: "2025-08-15"},
+		{Name: "Past", Date: "2025-08-01"},
+		{Name: "Broken", Date: "next week"},
+	}}
+
+	section := widget.Section(time.Date(2025, 8, 15, 23, 0, 0, 0, time.Local))
+	expected := []string{"Vacation: 15 days", "Deadline: tomorrow", "Launch: today!", `Broken: invalid date "next week"`}
+	if strings.Join(section.Lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("Lines = %v, want %v", section.Lines, expected)
+	}
+}
+
+func TestCommandWidget(t *testing.T) {
+	widget := &CommandWidget{Title: "Weather", Command: "forecast", Output: func(command string) (string, error) {
+		return "Sunny +25°C\r\nWind 5 km/h\n", nil
+	}}
+
+	section := widget.Section(time.Now())
+	if len(section.Lines) != 2 || section.Lines[0] != "Sunny +25°C" {
+		t.Errorf("Lines = %q, want the command output", section.Lines)
+	}
+
+	widget.Output = func(string) (string, error) { return "", errors.New("offline") }
+	if section := widget.Section(time.Now()); len(section.Lines) != 1 || !strings.Contains(section.Lines[0], "offline") {
+		t.Errorf("Failed command lines = %v, want the error", section.Lines)
+	}
+}
+
+func TestQuoteWidget(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "banner_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "quotes.txt")
+	if err := os.WriteFile(path, []byte("First\n\nSecond\n"), 0644); err != nil {
+		t.Fatalf("Failed to write quotes: %v", err)
+	}
+
+	widget := &QuoteWidget{Title: "Quote", File: path}
+	jan1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local) // Day 1 of the year
+	if lines := widget.Section(jan1).Lines; len(lines) != 1 || lines[0] != "Second" {
+		t.Errorf("Quote on day 1 = %v, want Second", lines)
+	}
+	if lines := widget.Section(jan1.AddDate(0, 0, 1)).Lines; lines[0] != "First" {
+		t.Errorf("Quote on day 2 = %v, want First", lines)
+	}
+
+	widget.File = filepath.Join(tempDir, "missing.txt")
+	if lines := widget.Section(jan1).Lines; len(lines) != 1 || !strings.HasPrefix(lines[0], "unavailable") {
+		t.Errorf("Missing file lines = %v", lines)
+	}
+}
+
+func TestRender_SkipsEmptySections(t *testing.T) {
+	widgets := []Widget{
+		&CountdownWidget{Title: "Countdowns"},
+		&CommandWidget{Title: "Weather", Command: "x", Output: func(string) (string, error) { return "Rain", nil }},
+	}
+
+	sections := Render(widgets, time.Now())
+	if len(sections) != 1 || sections[0].Title != "Weather" {
+		t.Errorf("Render() = %v, want only the weather section", sections)
+	}
+}
@@ -0,0 +1,216 @@
+package caldav
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrPreconditionFailed is returned when a calendar object changed on the server since
+// it was listed, so a write or delete would overwrite someone else's change
+var ErrPreconditionFailed = errors.New("calendar object changed on the server")
+
+// Client talks to one calendar collection of a CalDAV server such as Nextcloud or
+// Fastmail, authenticating with HTTP basic auth
+type Client struct {
+	URL      string       // Calendar collection, e.g. https://cloud.example.com/remote.php/dav/calendars/me/personal/
+	Username string       // Basic auth user; empty sends no credentials
+	Password string       // Basic auth password, usually an app password
+	HTTP     *http.Client // Nil uses http.DefaultClient
+
+	// PasswordFunc is asked for the password on the first request when Password is empty,
+	// e.g. to read it from a password manager only when syncing
+	PasswordFunc func() (string, error)
+}
+
+// Object is a calendar object resource: one .ics file in the collection
+type Object struct {
+	Href string // Path of the object on the server
+	ETag string // Version of the object; changes with every write
+	Data string // iCalendar data
+}
+
+// calendarQuery asks for the ETag and data of every VEVENT object in the collection
+const calendarQuery = `<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/><c:calendar-data/></d:prop>
+  <c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT"/></c:comp-filter></c:filter>
+</c:calendar-query>
+`
+
+// multistatus is the response body of a REPORT request
+type multistatus struct {
+	XMLName   xml.Name `xml:"DAV: multistatus"`
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				ETag string `xml:"DAV: getetag"`
+				Data string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// List returns the event objects of the collection
+func (c *Client) List(ctx context.Context) ([]Object, error) {
+	req, err := c.request(ctx, "REPORT", "", strings.NewReader(calendarQuery))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("listing calendar failed: %s", resp.Status)
+	}
+
+	var status multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("invalid calendar listing: %v", err)
+	}
+	var objects []Object
+	for _, response := range status.Responses {
+		for _, propstat := range response.Propstat {
+			if !strings.Contains(propstat.Status, " 200 ") || propstat.Prop.Data == "" {
+				continue
+			}
+			objects = append(objects, Object{
+				Href: response.Href,
+				ETag: propstat.Prop.ETag,
+				Data: propstat.Prop.Data,
+			})
+		}
+	}
+	return objects, nil
+}
+
+// Put writes an object and returns its new ETag, which servers may leave out. With an
+// etag the object is only replaced if it is still that version; without one it is
+// only created if it does not exist yet.
+func (c *Client) Put(ctx context.Context, href, data, etag string) (string, error) {
+	req, err := c.request(ctx, http.MethodPut, href, strings.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	} else {
+		req.Header.Set("If-None-Match", "*")
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return resp.Header.Get("ETag"), nil
+	case http.StatusPreconditionFailed:
+		return "", ErrPreconditionFailed
+	default:
+		return "", fmt.Errorf("writing %s failed: %s", href, resp.Status)
+	}
+}
+
+// Delete removes an object if it is still the etag version; an object that is already
+// gone counts as deleted
+func (c *Client) Delete(ctx context.Context, href, etag string) error {
+	req, err := c.request(ctx, http.MethodDelete, href, nil)
+	if err != nil {
+		return err
+	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	case http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	default:
+		return fmt.Errorf("deleting %s failed: %s", href, resp.Status)
+	}
+}
+
+// ObjectHref returns the path of a new object named after uid in the collection
+func (c *Client) ObjectHref(uid string) (string, error) {
+	base, err := url.Parse(c.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid CalDAV URL: %v", err)
+	}
+	dir := base.Path
+	if !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+	return dir + url.PathEscape(uid) + ".ics", nil
+}
+
+// request creates a request for href, resolved against the collection URL; an empty
+// href is the collection itself
+func (c *Client) request(ctx context.Context, method, href string, body io.Reader) (*http.Request, error) {
+	base, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CalDAV URL: %v", err)
+	}
+	target := base
+	if href != "" {
+		ref, err := url.Parse(href)
+		if err != nil {
+			return nil, fmt.Errorf("invalid object path %q: %v", href, err)
+		}
+		target = base.ResolveReference(ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Password == "" && c.PasswordFunc != nil {
+		password, err := c.PasswordFunc()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get CalDAV password: %v", err)
+		}
+		c.Password = strings.TrimSpace(password)
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	return req, nil
+}
+
+// do sends a request; a rejected login is reported as such
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot reach CalDAV server: %v", err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		return nil, fmt.Errorf("CalDAV server rejected the login: %s", resp.Status)
+	}
+	return resp, nil
+}
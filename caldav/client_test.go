@@ -0,0 +1,157 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeServer is a calendar collection at /cal/ holding objects by path
+type fakeServer struct {
+	mu      sync.Mutex
+	objects map[string]Object
+	version int
+	puts    int
+}
+
+func newFakeServer(t *testing.T) (*fakeServer, *Client) {
+	fake := &fakeServer{objects: make(map[string]Object)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, &Client{URL: server.URL + "/cal/", Username: "me", Password: "secret"}
+}
+
+// store puts an object on the server as another client would
+func (f *fakeServer) store(href, data string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.version++
+	f.objects[href] = Object{Href: href, ETag: fmt.Sprintf(`"%d"`, f.version), Data: data}
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if user, password, _ := r.BasicAuth(); user != "me" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := r.URL.EscapedPath()
+	existing, exists := f.objects[path]
+	switch r.Method {
+	case "REPORT":
+		if r.Header.Get("Depth") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var paths []string
+		for href := range f.objects {
+			paths = append(paths, href)
+		}
+		sort.Strings(paths)
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">`)
+		for _, href := range paths {
+			object := f.objects[href]
+			fmt.Fprintf(w, `<d:response><d:href>%s</d:href><d:propstat><d:prop><d:getetag>%s</d:getetag><cal:calendar-data>%s</cal:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`,
+				href, html.EscapeString(object.ETag), html.EscapeString(object.Data))
+		}
+		fmt.Fprint(w, `</d:multistatus>`)
+	case http.MethodPut:
+		if match := r.Header.Get("If-Match"); match != "" && (!exists || match != existing.ETag) ||
+			r.Header.Get("If-None-Match") == "*" && exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		f.version++
+		f.puts++
+		object := Object{Href: path, ETag: fmt.Sprintf(`"%d"`, f.version), Data: string(data)}
+		f.objects[path] = object
+		w.Header().Set("ETag", object.ETag)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if match := r.Header.Get("If-Match"); exists && match != "" && match != existing.ETag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		delete(f.objects, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestClient_PutListDelete(t *testing.T) {
+	fake, client := newFakeServer(t)
+	ctx := context.Background()
+
+	href, err := client.ObjectHref("a b@example.com")
+	if err != nil || href != "/cal/a%20b@example.com.ics" {
+		t.Fatalf("ObjectHref() = %q, %v", href, err)
+	}
+	etag, err := client.Put(ctx, href, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", "")
+	if err != nil || etag == "" {
+		t.Fatalf("Put() new = %q, %v", etag, err)
+	}
+	if _, err := client.Put(ctx, href, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", ""); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Put() over an existing object without ETag = %v, want ErrPreconditionFailed", err)
+	}
+
+	objects, err := client.List(ctx)
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(objects) != 1 || objects[0].ETag != etag || !strings.Contains(objects[0].Data, "BEGIN:VCALENDAR") {
+		t.Fatalf("List() = %+v, want the stored object", objects)
+	}
+
+	fake.store("/cal/a%20b@example.com.ics", "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")
+	if err := client.Delete(ctx, objects[0].Href, etag); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Delete() of a changed object = %v, want ErrPreconditionFailed", err)
+	}
+	if err := client.Delete(ctx, objects[0].Href, ""); err != nil {
+		t.Errorf("Delete() failed: %v", err)
+	}
+	if objects, _ := client.List(ctx); len(objects) != 0 {
+		t.Errorf("List() after Delete() = %+v, want none", objects)
+	}
+}
+
+func TestClient_PasswordFunc(t *testing.T) {
+	_, client := newFakeServer(t)
+	calls := 0
+	client.Password = ""
+	client.PasswordFunc = func() (string, error) {
+		calls++
+		return "secret\n", nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.List(context.Background()); err != nil {
+			t.Fatalf("List() failed: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("PasswordFunc called %d times, want once", calls)
+	}
+}
+
+func TestClient_RejectedLogin(t *testing.T) {
+	_, client := newFakeServer(t)
+	client.Password = "wrong"
+
+	_, err := client.List(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "rejected the login") {
+		t.Errorf("List() with a wrong password = %v, want a login error", err)
+	}
+}
@@ -0,0 +1,413 @@
+package caldav
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+// StateFileName is the name of the sync state file inside the data directory
+const StateFileName = "caldav.json"
+
+// Store is the event collection a sync works on; *events.Manager implements it
+type Store interface {
+	GetAllEvents() []models.Event
+	RestoreEvent(event models.Event) error
+	ReplaceEvent(oldEvent, newEvent models.Event) error
+	DeleteEvent(event models.Event) error
+}
+
+// entry records an event as it was when it was last in sync with the server
+type entry struct {
+	Href     string    `json:"href"`
+	ETag     string    `json:"etag"`
+	Hash     string    `json:"hash"`               // Fingerprint of the synced fields
+	Modified time.Time `json:"modified,omitempty"` // Last change in the calendar since then
+}
+
+// syncState is the persisted state of a sync, keyed by the UID of the events
+type syncState struct {
+	LastSync time.Time         `json:"last_sync"`
+	Objects  map[string]*entry `json:"objects"`
+}
+
+// Result counts the changes of one sync
+type Result struct {
+	Pulled    int // Events added or updated in the calendar
+	Pushed    int // Events written to the server
+	Removed   int // Events deleted on either side
+	Conflicts int // Events changed on both sides, settled by the later change
+	Skipped   int // Objects the server changed during the sync, left for the next one
+}
+
+// String summarizes the result for the status line, e.g. "2 pulled, 1 pushed"
+func (r Result) String() string {
+	parts := []string{fmt.Sprintf("%d pulled", r.Pulled), fmt.Sprintf("%d pushed", r.Pushed)}
+	if r.Removed > 0 {
+		parts = append(parts, fmt.Sprintf("%d removed", r.Removed))
+	}
+	if r.Conflicts > 0 {
+		parts = append(parts, fmt.Sprintf("%d conflicts settled by the later change", r.Conflicts))
+	}
+	if r.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d left for the next sync", r.Skipped))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Sync keeps the events of a store and a CalDAV calendar in step. Changes on one side
+// since the last sync are copied to the other, including deletions; an event changed
+// on both sides takes the later change, comparing the time it was changed in the
+// calendar with the LAST-MODIFIED (or DTSTAMP) of the server's copy. A change always
+// wins over a deletion. Events imported from other sources, such as todo.txt tasks,
+// are not synced.
+type Sync struct {
+	client   *Client
+	path     string
+	loc      *time.Location
+	now      func() time.Time
+	state    syncState
+	applying bool // The sync is changing the store, so changes are not recorded
+}
+
+// New creates a sync against client with its state kept in dataDir; an empty dataDir
+// keeps the state in memory only. Event times are in loc.
+func New(client *Client, dataDir string, loc *time.Location) *Sync {
+	path := ""
+	if dataDir != "" {
+		path = filepath.Join(dataDir, StateFileName)
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+	return &Sync{
+		client: client,
+		path:   path,
+		loc:    loc,
+		now:    time.Now,
+		state:  syncState{Objects: make(map[string]*entry)},
+	}
+}
+
+// Load reads the state file; a missing file is not an error
+func (s *Sync) Load() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read CalDAV state: %v", err)
+	}
+	var state syncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to decode CalDAV state: %v", err)
+	}
+	if state.Objects == nil {
+		state.Objects = make(map[string]*entry)
+	}
+	s.state = state
+	return nil
+}
+
+// Save writes the state file
+func (s *Sync) Save() error {
+	if s.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode CalDAV state: %v", err)
+	}
+	err = storage.WriteFileAtomic(s.path, 0644, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write CalDAV state: %v", err)
+	}
+	return nil
+}
+
+// LastSync returns when the last sync finished, zero before the first one
+func (s *Sync) LastSync() time.Time {
+	return s.state.LastSync
+}
+
+// Changed records that an event was changed in the calendar, for deciding conflicts
+// at the next sync; pass it the events of a change listener
+func (s *Sync) Changed(before, after models.Event) {
+	if s.applying {
+		return
+	}
+	recorded := false
+	for _, event := range []models.Event{before, after} {
+		if uid, ok := syncedUID(event); ok {
+			if e := s.state.Objects[uid]; e != nil {
+				e.Modified = s.now()
+				recorded = true
+			}
+		}
+	}
+	if recorded {
+		// A lost time only makes the server win a conflict
+		_ = s.Save()
+	}
+}
+
+// remoteEvent is an event as stored on the server
+type remoteEvent struct {
+	object   Object
+	event    models.Event
+	modified time.Time
+}
+
+// Run syncs the store with the server and saves the state
+func (s *Sync) Run(ctx context.Context, store Store) (Result, error) {
+	var result Result
+	objects, err := s.client.List(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	remote := make(map[string]remoteEvent)
+	for _, object := range objects {
+		parsed, err := storage.ImportICS(strings.NewReader(object.Data))
+		if err != nil || len(parsed) == 0 {
+			continue // Unreadable or cancelled objects are left alone
+		}
+		if uid, ok := syncedUID(parsed[0]); ok {
+			remote[uid] = remoteEvent{object, parsed[0], lastModified(object.Data)}
+		}
+	}
+
+	s.applying = true
+	defer func() { s.applying = false }()
+
+	// Events created in the calendar get the UID they are stored under on the server
+	local := make(map[string]models.Event)
+	for _, event := range store.GetAllEvents() {
+		if event.Source == "" {
+			named := event
+			named.Source = storage.ICSSourcePrefix + storage.ICSUID(event)
+			if err := store.ReplaceEvent(event, named); err != nil {
+				return result, fmt.Errorf("failed to name %q for syncing: %v", event.Description, err)
+			}
+			event = named
+		}
+		if uid, ok := syncedUID(event); ok {
+			local[uid] = event
+		}
+	}
+
+	uids := make(map[string]bool)
+	for uid := range local {
+		uids[uid] = true
+	}
+	for uid := range remote {
+		uids[uid] = true
+	}
+	for uid := range s.state.Objects {
+		uids[uid] = true
+	}
+	var sorted []string
+	for uid := range uids {
+		sorted = append(sorted, uid)
+	}
+	sort.Strings(sorted)
+
+	for _, uid := range sorted {
+		event, hasLocal := local[uid]
+		theirs, hasRemote := remote[uid]
+		if err := s.syncEvent(ctx, store, uid, event, hasLocal, theirs, hasRemote, &result); err != nil {
+			if errors.Is(err, ErrPreconditionFailed) {
+				result.Skipped++
+				continue
+			}
+			_ = s.Save()
+			return result, err
+		}
+	}
+
+	s.state.LastSync = s.now()
+	return result, s.Save()
+}
+
+// syncEvent brings one event in step on both sides
+func (s *Sync) syncEvent(ctx context.Context, store Store, uid string, event models.Event, hasLocal bool, theirs remoteEvent, hasRemote bool, result *Result) error {
+	known := s.state.Objects[uid]
+	switch {
+	case hasLocal && hasRemote:
+		hash := s.hash(event)
+		if hash == s.hash(theirs.event) {
+			s.record(uid, theirs.object.Href, theirs.object.ETag, hash)
+			return nil
+		}
+		localChanged := known == nil || hash != known.Hash
+		remoteChanged := known == nil || !s.unchanged(known, theirs)
+		if localChanged && remoteChanged {
+			result.Conflicts++
+			remoteChanged = !s.localModified(known).After(theirs.modified)
+		}
+		if remoteChanged {
+			return s.pull(store, uid, event, theirs, result)
+		}
+		return s.push(ctx, uid, event, theirs.object.Href, theirs.object.ETag, result)
+
+	case hasLocal:
+		if known != nil && s.hash(event) == known.Hash {
+			// Deleted on the server and unchanged here
+			if err := store.DeleteEvent(event); err != nil {
+				return fmt.Errorf("failed to delete %q: %v", event.Description, err)
+			}
+			delete(s.state.Objects, uid)
+			result.Removed++
+			return nil
+		}
+		href, err := s.client.ObjectHref(uid)
+		if err != nil {
+			return err
+		}
+		return s.push(ctx, uid, event, href, "", result)
+
+	case hasRemote:
+		if known != nil && s.unchanged(known, theirs) {
+			// Deleted in the calendar and unchanged on the server
+			if err := s.client.Delete(ctx, theirs.object.Href, theirs.object.ETag); err != nil {
+				return err
+			}
+			delete(s.state.Objects, uid)
+			result.Removed++
+			return nil
+		}
+		if err := store.RestoreEvent(theirs.event); err != nil {
+			return fmt.Errorf("failed to add %q: %v", theirs.event.Description, err)
+		}
+		s.record(uid, theirs.object.Href, theirs.object.ETag, s.hash(theirs.event))
+		result.Pulled++
+		return nil
+
+	default:
+		// Gone on both sides
+		delete(s.state.Objects, uid)
+		return nil
+	}
+}
+
+// pull replaces the calendar's event with the server's version, keeping attributes
+// the server does not store
+func (s *Sync) pull(store Store, uid string, event models.Event, theirs remoteEvent, result *Result) error {
+	updated := theirs.event
+	updated.Command = event.Command
+	updated.Priority = event.Priority
+	updated.Flagged = event.Flagged
+	updated.TimeChecked = event.TimeChecked
+	updated.Calendar = event.Calendar
+	if err := store.ReplaceEvent(event, updated); err != nil {
+		return fmt.Errorf("failed to update %q: %v", event.Description, err)
+	}
+	s.record(uid, theirs.object.Href, theirs.object.ETag, s.hash(updated))
+	result.Pulled++
+	return nil
+}
+
+// push writes the calendar's event to href, replacing version etag or creating the
+// object when etag is empty
+func (s *Sync) push(ctx context.Context, uid string, event models.Event, href, etag string, result *Result) error {
+	stamp := s.localModified(s.state.Objects[uid])
+	if stamp.IsZero() {
+		stamp = s.now()
+	}
+	var data strings.Builder
+	if err := storage.ExportICS(&data, []models.Event{event}, stamp, s.loc); err != nil {
+		return err
+	}
+	newETag, err := s.client.Put(ctx, href, data.String(), etag)
+	if err != nil {
+		return err
+	}
+	s.record(uid, href, newETag, s.hash(event))
+	result.Pushed++
+	return nil
+}
+
+// record notes an event as in sync
+func (s *Sync) record(uid, href, etag, hash string) {
+	s.state.Objects[uid] = &entry{Href: href, ETag: etag, Hash: hash}
+}
+
+// unchanged reports whether the server's copy is still the one last synced; servers
+// that send no ETag on writes are recognized by the content
+func (s *Sync) unchanged(known *entry, theirs remoteEvent) bool {
+	return theirs.object.ETag == known.ETag || s.hash(theirs.event) == known.Hash
+}
+
+// localModified returns when an event was last changed in the calendar. Changes made
+// while the calendar was closed have no time, and like events never synced before
+// they count as older than the server's copy.
+func (s *Sync) localModified(known *entry) time.Time {
+	if known == nil {
+		return time.Time{}
+	}
+	return known.Modified
+}
+
+// hash fingerprints the fields of an event that are stored on the server
+func (s *Sync) hash(event models.Event) string {
+	var data strings.Builder
+	_ = storage.ExportICS(&data, []models.Event{event}, time.Time{}, s.loc)
+	sum := sha1.Sum([]byte(data.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// syncedUID returns the UID of an event that is synced: one imported from or written
+// to iCalendar
+func syncedUID(event models.Event) (string, bool) {
+	uid, ok := strings.CutPrefix(event.Source, storage.ICSSourcePrefix)
+	return uid, ok && uid != ""
+}
+
+// lastModified returns the LAST-MODIFIED time of the first event in iCalendar data,
+// or its DTSTAMP when it has none; zero when neither can be read
+func lastModified(data string) time.Time {
+	var stamp time.Time
+	for _, line := range strings.Split(data, "\n") {
+		name, value, ok := strings.Cut(strings.TrimRight(line, "\r"), ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ";")
+		switch strings.ToUpper(name) {
+		case "LAST-MODIFIED":
+			if t, err := time.Parse("20060102T150405Z", value); err == nil {
+				return t
+			}
+		case "DTSTAMP":
+			if t, err := time.Parse("20060102T150405Z", value); err == nil && stamp.IsZero() {
+				stamp = t
+			}
+		case "END":
+			if strings.EqualFold(value, "VEVENT") {
+				return stamp
+			}
+		}
+	}
+	return stamp
+}
@@ -0,0 +1,239 @@
+package caldav
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/events"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+// vevent returns an object as written by another CalDAV client
+func vevent(uid, summary, start, modified string) string {
+	return strings.Join([]string{
+		"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Other//EN",
+		"BEGIN:VEVENT", "UID:" + uid, "DTSTAMP:20250801T000000Z", "LAST-MODIFIED:" + modified,
+		"DTSTART:" + start, "SUMMARY:" + summary, "END:VEVENT", "END:VCALENDAR", "",
+	}, "\r\n")
+}
+
+func newTestSync(t *testing.T) (*fakeServer, *Sync, *events.Manager, *time.Time) {
+	fake, client := newFakeServer(t)
+	dir := t.TempDir()
+	manager := events.NewManagerWithConfig(&config.Config{EventsFilePath: filepath.Join(dir, "events.json")})
+	s := New(client, dir, time.UTC)
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	manager.AddChangeListener(func(_ events.ChangeKind, before, after models.Event) {
+		s.Changed(before, after)
+	})
+	return fake, s, manager, &now
+}
+
+func descriptions(manager *events.Manager) string {
+	var names []string
+	for _, event := range manager.GetAllEvents() {
+		names = append(names, event.Description)
+	}
+	return strings.Join(names, ",")
+}
+
+func findEvent(t *testing.T, manager *events.Manager, description string) models.Event {
+	t.Helper()
+	for _, event := range manager.GetAllEvents() {
+		if event.Description == description {
+			return event
+		}
+	}
+	t.Fatalf("event %q not found in %s", description, descriptions(manager))
+	return models.Event{}
+}
+
+func TestSync_PullAndPush(t *testing.T) {
+	fake, s, manager, _ := newTestSync(t)
+	ctx := context.Background()
+
+	day := time.Date(2025, 9, 2, 0, 0, 0, 0, time.Local)
+	if err := manager.AddEvent(day, "10:00", "Dentist"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	fake.store("/cal/standup.ics", vevent("standup", "Standup", "20250903T070000Z", "20250801T000000Z"))
+
+	result, err := s.Run(ctx, manager)
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if result.Pulled != 1 || result.Pushed != 1 {
+		t.Errorf("First Run() = %+v, want 1 pulled and 1 pushed", result)
+	}
+	if got := findEvent(t, manager, "Dentist"); !strings.HasPrefix(got.Source, "ics:") {
+		t.Errorf("Pushed event Source = %q, want its UID", got.Source)
+	}
+	findEvent(t, manager, "Standup")
+	if len(fake.objects) != 2 {
+		t.Errorf("Server holds %d objects, want 2", len(fake.objects))
+	}
+
+	// Nothing changed: nothing is written
+	puts := fake.puts
+	if result, err := s.Run(ctx, manager); err != nil || result != (Result{}) || fake.puts != puts {
+		t.Errorf("Run() without changes = %+v, %v with %d writes, want nothing to do", result, err, fake.puts-puts)
+	}
+
+	// A new sync with the saved state knows what was synced
+	reloaded := New(s.client, filepath.Dir(s.path), time.UTC)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if result, err := reloaded.Run(ctx, manager); err != nil || result != (Result{}) {
+		t.Errorf("Run() after Load() = %+v, %v, want nothing to do", result, err)
+	}
+
+	// Changes on one side reach the other
+	fake.store("/cal/standup.ics", vevent("standup", "Standup moved", "20250903T080000Z", "20250901T090000Z"))
+	dentist := findEvent(t, manager, "Dentist")
+	if err := manager.EditEvent(dentist, day, "11:00", "Dentist (moved)"); err != nil {
+		t.Fatalf("EditEvent() failed: %v", err)
+	}
+	result, err = s.Run(ctx, manager)
+	if err != nil || result.Pulled != 1 || result.Pushed != 1 || result.Conflicts != 0 {
+		t.Fatalf("Run() after changes = %+v, %v, want 1 pulled and 1 pushed", result, err)
+	}
+	findEvent(t, manager, "Standup moved")
+	pushed := false
+	for _, object := range fake.objects {
+		pushed = pushed || strings.Contains(object.Data, "SUMMARY:Dentist (moved)")
+	}
+	if !pushed {
+		t.Error("The edited event should be written to the server")
+	}
+}
+
+func TestSync_ConflictTakesLaterChange(t *testing.T) {
+	fake, s, manager, now := newTestSync(t)
+	ctx := context.Background()
+
+	fake.store("/cal/review.ics", vevent("review", "Review", "20250903T070000Z", "20250801T000000Z"))
+	fake.store("/cal/retro.ics", vevent("retro", "Retro", "20250904T070000Z", "20250801T000000Z"))
+	if _, err := s.Run(ctx, manager); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	// Both are edited in the calendar at 12:00; on the server the review was changed
+	// before and the retro after that
+	*now = time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	for _, name := range []string{"Review", "Retro"} {
+		event := findEvent(t, manager, name)
+		if err := manager.EditEvent(event, event.Date, event.GetTimeString(), name+" (local)"); err != nil {
+			t.Fatalf("EditEvent() failed: %v", err)
+		}
+	}
+	fake.store("/cal/review.ics", vevent("review", "Review (server)", "20250903T070000Z", "20250901T110000Z"))
+	fake.store("/cal/retro.ics", vevent("retro", "Retro (server)", "20250904T070000Z", "20250901T130000Z"))
+
+	*now = time.Date(2025, 9, 1, 14, 0, 0, 0, time.UTC)
+	result, err := s.Run(ctx, manager)
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if result.Conflicts != 2 || result.Pulled != 1 || result.Pushed != 1 {
+		t.Errorf("Run() = %+v, want 2 conflicts: 1 pulled, 1 pushed", result)
+	}
+	findEvent(t, manager, "Review (local)")
+	findEvent(t, manager, "Retro (server)")
+	if data := fake.objects["/cal/review.ics"].Data; !strings.Contains(data, "SUMMARY:Review (local)") || !strings.Contains(data, "DTSTAMP:20250901T120000Z") {
+		t.Errorf("The later local change should be written with its time, got:\n%s", data)
+	}
+}
+
+func TestSync_PullKeepsCalendar(t *testing.T) {
+	fake, client := newFakeServer(t)
+	dir := t.TempDir()
+	mainPath, workPath := filepath.Join(dir, "events.json"), filepath.Join(dir, "work.json")
+	manager := events.NewManagerWithConfig(&config.Config{
+		EventsFilePath: mainPath,
+		Calendars:      []config.EventCalendar{{Name: "work", File: workPath}},
+	})
+	s := New(client, dir, time.UTC)
+	s.now = func() time.Time { return time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC) }
+	manager.AddChangeListener(func(_ events.ChangeKind, before, after models.Event) {
+		s.Changed(before, after)
+	})
+	ctx := context.Background()
+
+	manager.SetAddCalendar("work")
+	if err := manager.AddEvent(time.Date(2025, 9, 2, 0, 0, 0, 0, time.Local), "10:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if _, err := s.Run(ctx, manager); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	// The event is renamed on the server
+	for href, object := range fake.objects {
+		fake.store(href, strings.Replace(object.Data, "SUMMARY:Standup", "SUMMARY:Standup (server)", 1))
+	}
+	if result, err := s.Run(ctx, manager); err != nil || result.Pulled != 1 {
+		t.Fatalf("Run() after the server change = %+v, %v, want 1 pulled", result, err)
+	}
+
+	if got := findEvent(t, manager, "Standup (server)"); got.Calendar != "work" {
+		t.Errorf("Pulled event Calendar = %q, want it to stay in work", got.Calendar)
+	}
+	if work, err := storage.LoadEventsJSON(workPath); err != nil || len(work) != 1 || work[0].Description != "Standup (server)" {
+		t.Errorf("Work calendar file = %v, %v; want the pulled event", work, err)
+	}
+	if main, err := storage.LoadEventsJSON(mainPath); err == nil && len(main) != 0 {
+		t.Errorf("Main events file = %v, want the pulled event left out", main)
+	}
+}
+
+func TestSync_Deletions(t *testing.T) {
+	fake, s, manager, _ := newTestSync(t)
+	ctx := context.Background()
+
+	fake.store("/cal/a.ics", vevent("a", "Deleted here", "20250903T070000Z", "20250801T000000Z"))
+	fake.store("/cal/b.ics", vevent("b", "Deleted there", "20250904T070000Z", "20250801T000000Z"))
+	fake.store("/cal/c.ics", vevent("c", "Changed there", "20250905T070000Z", "20250801T000000Z"))
+	if _, err := s.Run(ctx, manager); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	for _, name := range []string{"Deleted here", "Changed there"} {
+		if err := manager.DeleteEvent(findEvent(t, manager, name)); err != nil {
+			t.Fatalf("DeleteEvent() failed: %v", err)
+		}
+	}
+	delete(fake.objects, "/cal/b.ics")
+	fake.store("/cal/c.ics", vevent("c", "Changed there", "20250905T090000Z", "20250901T100000Z"))
+
+	result, err := s.Run(ctx, manager)
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if result.Removed != 2 || result.Pulled != 1 {
+		t.Errorf("Run() = %+v, want 2 removed and the changed event restored", result)
+	}
+	if _, ok := fake.objects["/cal/a.ics"]; ok {
+		t.Error("An event deleted in the calendar should be deleted on the server")
+	}
+	if got := descriptions(manager); got != "Changed there" {
+		t.Errorf("Events after sync = %q, want only the event changed on the server", got)
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	data := vevent("x", "X", "20250903T070000Z", "20250901T110000Z")
+	if got := lastModified(data); !got.Equal(time.Date(2025, 9, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("lastModified() = %v, want LAST-MODIFIED", got)
+	}
+	data = strings.Replace(data, "LAST-MODIFIED:20250901T110000Z\r\n", "", 1)
+	if got := lastModified(data); !got.Equal(time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("lastModified() without LAST-MODIFIED = %v, want DTSTAMP", got)
+	}
+}
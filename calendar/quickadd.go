@@ -0,0 +1,45 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseQuickAdd parses a one-line event such as "2025-12-24 18:00 Christmas dinner".
+// The line holds an optional date expression (anything ParseRelativeDate accepts,
+// such as "fri" or "next fri", defaulting to base), a time in HH:MM format and the
+// description.
+func ParseQuickAdd(line string, base time.Time) (date time.Time, timeStr, description string, err error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return time.Time{}, "", "", fmt.Errorf("empty line")
+	}
+
+	// The date expression is everything before the time
+	timeIndex := -1
+	for i, field := range fields {
+		if ValidateTimeString(field) {
+			timeIndex = i
+			break
+		}
+	}
+	if timeIndex < 0 {
+		return time.Time{}, "", "", fmt.Errorf("missing time: expected e.g. '2025-12-24 18:00 Christmas dinner'")
+	}
+
+	date = NormalizeDate(base)
+	if timeIndex > 0 {
+		date, err = ParseRelativeDate(strings.Join(fields[:timeIndex], " "), base)
+		if err != nil {
+			return time.Time{}, "", "", err
+		}
+	}
+	timeStr = fields[timeIndex]
+
+	description = strings.Join(fields[timeIndex+1:], " ")
+	if description == "" {
+		return time.Time{}, "", "", fmt.Errorf("missing description")
+	}
+	return date, timeStr, description, nil
+}
@@ -0,0 +1,48 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	// Friday, August 15, 2025
+	base := time.Date(2025, 8, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		line        string
+		date        time.Time
+		timeStr     string
+		description string
+	}{
+		{"Absolute date", "2025-12-24 18:00 Christmas dinner", time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC), "18:00", "Christmas dinner"},
+		{"Relative date", "tomorrow 09:30 Dentist", time.Date(2025, 8, 16, 0, 0, 0, 0, time.UTC), "09:30", "Dentist"},
+		{"Time only is today", "7:15 Run", time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC), "7:15", "Run"},
+		{"Two-word date", "next fri 18:00 Dinner", time.Date(2025, 8, 22, 0, 0, 0, 0, time.UTC), "18:00", "Dinner"},
+		{"Extra whitespace", "  mon   10:00  Team   sync ", time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC), "10:00", "Team sync"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, timeStr, description, err := ParseQuickAdd(tt.line, base)
+			if err != nil {
+				t.Fatalf("ParseQuickAdd(%q) returned error: %v", tt.line, err)
+			}
+			if !date.Equal(tt.date) || timeStr != tt.timeStr || description != tt.description {
+				t.Errorf("ParseQuickAdd(%q) = %v %q %q, want %v %q %q",
+					tt.line, date, timeStr, description, tt.date, tt.timeStr, tt.description)
+			}
+		})
+	}
+}
+
+func TestParseQuickAdd_Errors(t *testing.T) {
+	base := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)
+
+	for _, line := range []string{"", "2025-12-24 Christmas", "2025-12-24 18:00", "someday 18:00 Party", "25:00 Late"} {
+		if _, _, _, err := ParseQuickAdd(line, base); err == nil {
+			t.Errorf("ParseQuickAdd(%q) should fail", line)
+		}
+	}
+}
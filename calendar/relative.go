@@ -0,0 +1,240 @@
+package calendar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekdayNames maps full and abbreviated weekday names to time.Weekday
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// monthNames maps full and abbreviated month names to time.Month
+var monthNames = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+// periodOffsets maps the periods of "next week" or "last month" to an offset of one
+var periodOffsets = map[string]string{"week": "1w", "month": "1m", "year": "1y"}
+
+// ParseRelativeDate parses a natural-language date expression relative to base.
+// Supported forms:
+//   - "" or "today": the base date
+//   - "tomorrow", "yesterday"
+//   - weekday names ("fri", "friday", "next fri"): the next such weekday after base;
+//     "last fri" is the latest one before base
+//   - "next week", "last month", "next year": one week, month or year from base
+//   - "eom", "eoy": the last day of base's month or year
+//   - offsets "+10d", "-2w", "+1m", "+1y" (days, weeks, months, years); the sign
+//     may be left out of later dates, as in "2w"
+//   - a day and month name ("15 mar", "march 15"): the next such date from base on,
+//     or with a year ("15 mar 2027") that date
+//   - absolute dates in YYYY-MM-DD format
+//
+// The result is normalized to midnight in the base date's location.
+func ParseRelativeDate(expr string, base time.Time) (time.Time, error) {
+	base = NormalizeDate(base)
+	input := strings.Join(strings.Fields(strings.ToLower(expr)), " ")
+
+	switch input {
+	case "", "today":
+		return base, nil
+	case "tomorrow":
+		return base.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return base.AddDate(0, 0, -1), nil
+	case "eom":
+		return GetLastDayOfMonth(base), nil
+	case "eoy":
+		return time.Date(base.Year(), time.December, 31, 0, 0, 0, 0, base.Location()), nil
+	}
+
+	// "next" and "last" move forward or back to a weekday or by a period
+	if direction, rest, ok := strings.Cut(input, " "); ok && (direction == "next" || direction == "last") {
+		sign := "+"
+		if direction == "last" {
+			sign = "-"
+		}
+		if weekday, ok := weekdayNames[rest]; ok {
+			return nextWeekday(base, weekday, sign == "-"), nil
+		}
+		if offset, ok := periodOffsets[rest]; ok {
+			return parseDateOffset(sign+offset, base)
+		}
+		return time.Time{}, fmt.Errorf("unrecognized date '%s': expected e.g. next fri or last week", expr)
+	}
+
+	// Weekday names resolve to the next occurrence strictly after base
+	if weekday, ok := weekdayNames[input]; ok {
+		return nextWeekday(base, weekday, false), nil
+	}
+
+	// Day and month names, such as 15 mar or march 15 2027
+	if date, ok, err := parseDayMonth(input, base); ok {
+		return date, err
+	}
+
+	// Signed offsets such as +10d or -2w; a number and unit alone count forward
+	if input[0] == '+' || input[0] == '-' {
+		return parseDateOffset(input, base)
+	}
+	if len(input) >= 2 && input[0] >= '0' && input[0] <= '9' && strings.ContainsRune("dwmy", rune(input[len(input)-1])) {
+		return parseDateOffset("+"+input, base)
+	}
+
+	// Absolute date in the base date's location
+	date, err := time.ParseInLocation("2006-01-02", input, base.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognized date '%s'", expr)
+	}
+	return date, nil
+}
+
+// parseDayMonth parses a day and month name in either order with an optional year,
+// reporting whether input has that form. Without a year it is the next such date on
+// or after base.
+func parseDayMonth(input string, base time.Time) (time.Time, bool, error) {
+	fields := strings.Fields(input)
+	if len(fields) < 2 || len(fields) > 3 {
+		return time.Time{}, false, nil
+	}
+	dayField, monthField := fields[0], fields[1]
+	if _, ok := monthNames[dayField]; ok {
+		dayField, monthField = monthField, dayField
+	}
+	month, ok := monthNames[monthField]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	day, err := strconv.Atoi(dayField)
+	if err != nil {
+		return time.Time{}, false, nil
+	}
+
+	if day < 1 || day > 31 {
+		return time.Time{}, true, fmt.Errorf("%s has no day %d", month, day)
+	}
+	if len(fields) == 3 {
+		year, err := strconv.Atoi(fields[2])
+		if err != nil || len(fields[2]) != 4 {
+			return time.Time{}, true, fmt.Errorf("invalid year '%s': expected e.g. 15 mar 2027", fields[2])
+		}
+		date := time.Date(year, month, day, 0, 0, 0, 0, base.Location())
+		if date.Month() != month {
+			return time.Time{}, true, fmt.Errorf("%s %d has no day %d", month, year, day)
+		}
+		return date, true, nil
+	}
+
+	// The next year holding the date, which for 29 feb may take a few
+	for year := base.Year(); year <= base.Year()+8; year++ {
+		date := time.Date(year, month, day, 0, 0, 0, 0, base.Location())
+		if date.Month() == month && !date.Before(base) {
+			return date, true, nil
+		}
+	}
+	return time.Time{}, true, fmt.Errorf("%s has no day %d", month, day)
+}
+
+// nextWeekday returns the first date after base falling on weekday, or with back the
+// latest one before base
+func nextWeekday(base time.Time, weekday time.Weekday, back bool) time.Time {
+	if back {
+		days := (int(base.Weekday()) - int(weekday) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return base.AddDate(0, 0, -days)
+	}
+	days := (int(weekday) - int(base.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return base.AddDate(0, 0, days)
+}
+
+// dateWords are the words of date expressions offered by CompleteDateExpression
+var dateWords = []string{
+	"today", "tomorrow", "yesterday", "next", "last", "week", "month", "year", "eom", "eoy",
+	"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+	"january", "february", "march", "april", "june", "july", "august", "september",
+	"october", "november", "december",
+}
+
+// CompleteDateExpression completes the last word of a date expression being typed,
+// e.g. "next fr" to "next friday", as far as the words starting with it agree. It
+// reports whether the expression was extended.
+func CompleteDateExpression(expr string) (string, bool) {
+	start := strings.LastIndex(expr, " ") + 1
+	prefix := strings.ToLower(expr[start:])
+	if prefix == "" {
+		return expr, false
+	}
+
+	completion := ""
+	for _, word := range dateWords {
+		if !strings.HasPrefix(word, prefix) {
+			continue
+		}
+		if completion == "" {
+			completion = word
+			continue
+		}
+		for !strings.HasPrefix(word, completion) {
+			completion = completion[:len(completion)-1]
+		}
+	}
+	if len(completion) <= len(prefix) {
+		return expr, false
+	}
+	return expr[:start] + completion, true
+}
+
+// parseDateOffset parses a signed offset like "+10d" and applies it to base
+func parseDateOffset(input string, base time.Time) (time.Time, error) {
+	if len(input) < 3 {
+		return time.Time{}, fmt.Errorf("invalid date offset '%s': expected e.g. +3d", input)
+	}
+
+	unit := input[len(input)-1]
+	amount, err := strconv.Atoi(input[1 : len(input)-1])
+	if err != nil || amount < 0 {
+		return time.Time{}, fmt.Errorf("invalid date offset '%s': expected e.g. +3d", input)
+	}
+	if input[0] == '-' {
+		amount = -amount
+	}
+
+	switch unit {
+	case 'd':
+		return base.AddDate(0, 0, amount), nil
+	case 'w':
+		return base.AddDate(0, 0, 7*amount), nil
+	case 'm':
+		return base.AddDate(0, amount, 0), nil
+	case 'y':
+		return base.AddDate(amount, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid date offset unit '%c': expected d, w, m or y", unit)
+	}
+}
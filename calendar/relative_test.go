@@ -0,0 +1,102 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRelativeDate(t *testing.T) {
+	// Friday, August 15, 2025
+	base := time.Date(2025, 8, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected time.Time
+	}{
+		{"Empty is base", "", time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)},
+		{"Today", "today", time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)},
+		{"Tomorrow", "Tomorrow", time.Date(2025, 8, 16, 0, 0, 0, 0, time.UTC)},
+		{"Yesterday", "yesterday", time.Date(2025, 8, 14, 0, 0, 0, 0, time.UTC)},
+		{"Next Monday", "mon", time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)},
+		{"Same weekday is next week", "fri", time.Date(2025, 8, 22, 0, 0, 0, 0, time.UTC)},
+		{"Full weekday name", "Sunday", time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)},
+		{"Days offset", "+10d", time.Date(2025, 8, 25, 0, 0, 0, 0, time.UTC)},
+		{"Negative days offset", "-15d", time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)},
+		{"Weeks offset", "+2w", time.Date(2025, 8, 29, 0, 0, 0, 0, time.UTC)},
+		{"Months offset", "+1m", time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)},
+		{"Years offset", "+1y", time.Date(2026, 8, 15, 0, 0, 0, 0, time.UTC)},
+		{"Absolute date", "2025-12-24", time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC)},
+		{"Surrounding whitespace", "  tomorrow ", time.Date(2025, 8, 16, 0, 0, 0, 0, time.UTC)},
+		{"Next weekday", "next fri", time.Date(2025, 8, 22, 0, 0, 0, 0, time.UTC)},
+		{"Next weekday with extra spaces", "Next   Monday", time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)},
+		{"Last weekday", "last fri", time.Date(2025, 8, 8, 0, 0, 0, 0, time.UTC)},
+		{"Last earlier weekday", "last wed", time.Date(2025, 8, 13, 0, 0, 0, 0, time.UTC)},
+		{"Next week", "next week", time.Date(2025, 8, 22, 0, 0, 0, 0, time.UTC)},
+		{"Last month", "last month", time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)},
+		{"End of month", "eom", time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC)},
+		{"End of year", "EOY", time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"Unsigned weeks offset", "2w", time.Date(2025, 8, 29, 0, 0, 0, 0, time.UTC)},
+		{"Unsigned days offset", "10d", time.Date(2025, 8, 25, 0, 0, 0, 0, time.UTC)},
+		{"Day and month", "15 mar", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"Month and day", "September 3", time.Date(2025, 9, 3, 0, 0, 0, 0, time.UTC)},
+		{"Day and month is today", "15 aug", time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)},
+		{"Day, month and year", "15 mar 2027", time.Date(2027, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"Next leap day", "29 feb", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"Days offset with sign", "+30d", time.Date(2025, 9, 14, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseRelativeDate(tt.expr, base)
+			if err != nil {
+				t.Fatalf("ParseRelativeDate(%q) returned error: %v", tt.expr, err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("ParseRelativeDate(%q) = %v, want %v", tt.expr, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRelativeDate_Invalid(t *testing.T) {
+	base := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)
+
+	invalid := []string{"someday", "+d", "+10x", "+-3d", "2025-13-01", "fr", "next", "next fortnight", "10x", "d", "31 feb", "0 mar", "15 mar 27", "15 mar 2027 x"}
+
+	for _, expr := range invalid {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseRelativeDate(expr, base); err == nil {
+				t.Errorf("ParseRelativeDate(%q) should have failed", expr)
+			}
+		})
+	}
+}
+
+func TestCompleteDateExpression(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+		ok       bool
+	}{
+		{"tom", "tomorrow", true},
+		{"next fr", "next friday", true},
+		{"Next Mo", "Next mon", true},
+		{"next mond", "next monday", true},
+		{"la", "last", true},
+		{"t", "t", false},
+		{"to", "to", false},
+		{"th", "thursday", true},
+		{"tomorrow", "tomorrow", false},
+		{"next ", "next ", false},
+		{"2w", "2w", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		result, ok := CompleteDateExpression(tt.expr)
+		if result != tt.expected || ok != tt.ok {
+			t.Errorf("CompleteDateExpression(%q) = %q, %v; want %q, %v", tt.expr, result, ok, tt.expected, tt.ok)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package calendar
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
+
+	"go-ascii-calendar/locale"
 )
 
 // GetMonthName returns the full name of the month
@@ -11,6 +14,15 @@ func GetMonthName(month time.Time) string {
 	return month.Format("January")
 }
 
+// GetLocalizedMonthName returns the name of the month from twelve month names, January
+// first. Without exactly twelve names, or with an empty one, the English name is used.
+func GetLocalizedMonthName(names []string, month time.Time) string {
+	if len(names) != 12 || names[month.Month()-1] == "" {
+		return GetMonthName(month)
+	}
+	return names[month.Month()-1]
+}
+
 // GetYear returns the year as a string
 func GetYear(month time.Time) string {
 	return month.Format("2006")
@@ -68,6 +80,12 @@ func FormatDate(date time.Time) string {
 	return date.Format("2006-01-02")
 }
 
+// FormatLocalDate formats a date with a pattern of YYYY, MM and DD such as
+// "DD.MM.YYYY"; an empty pattern formats it as YYYY-MM-DD
+func FormatLocalDate(date time.Time, pattern string) string {
+	return date.Format(locale.DateLayout(pattern))
+}
+
 // FormatTime formats a time as HH:MM
 func FormatTime(t time.Time) string {
 	return t.Format("15:04")
@@ -90,14 +108,60 @@ func ValidateTimeString(timeStr string) bool {
 	return hour >= 0 && hour <= 23 && minute >= 0 && minute <= 59
 }
 
+// ParseTimeRange parses an event time with an optional end, "HH:MM" or "HH:MM-HH:MM",
+// returning the start as HH:MM and the duration up to the end, zero without one. An
+// end not after the start is on the next day, so "22:00-01:00" lasts three hours.
+func ParseTimeRange(input string) (string, time.Duration, error) {
+	start, end, hasEnd := strings.Cut(strings.TrimSpace(input), "-")
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	if !ValidateTimeString(start) {
+		return "", 0, fmt.Errorf("invalid time %q: expected HH:MM or HH:MM-HH:MM", input)
+	}
+	if !hasEnd {
+		return start, 0, nil
+	}
+	if !ValidateTimeString(end) {
+		return "", 0, fmt.Errorf("invalid end time %q: expected HH:MM", end)
+	}
+
+	startTime, _ := ParseTime(start)
+	endTime, _ := ParseTime(end)
+	duration := endTime.Sub(startTime)
+	if duration <= 0 {
+		duration += 24 * time.Hour
+	}
+	return start, duration, nil
+}
+
+// FormatTimeRange formats a start time and duration as ParseTimeRange reads them:
+// "HH:MM-HH:MM", or "HH:MM" without a duration
+func FormatTimeRange(start time.Time, duration time.Duration) string {
+	if duration <= 0 {
+		return FormatTime(start)
+	}
+	return FormatTime(start) + "-" + FormatTime(start.Add(duration))
+}
+
 // GetDayOfWeekHeaders returns the day-of-week headers
 // weekStartDay: 0 = Sunday first, 1 = Monday first
 func GetDayOfWeekHeaders(weekStartDay int) []string {
-	if weekStartDay == 1 { // Monday first
-		return []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"}
+	return GetLocalizedDayOfWeekHeaders(nil, weekStartDay)
+}
+
+// GetLocalizedDayOfWeekHeaders returns the day-of-week headers from seven weekday
+// names, Sunday first, cut or padded to two characters to fit the day columns.
+// Without exactly seven names the English ones are used.
+func GetLocalizedDayOfWeekHeaders(names []string, weekStartDay int) []string {
+	if len(names) != 7 {
+		names = locale.C.Weekdays[:]
+	}
+
+	headers := make([]string, 7)
+	for i := range headers {
+		name := []rune(names[(i+weekStartDay)%7] + "  ")
+		headers[i] = string(name[:2])
 	}
-	// Default: Sunday first
-	return []string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}
+	return headers
 }
 
 // IsToday checks if the given date is today
@@ -117,6 +181,14 @@ func NormalizeDate(date time.Time) time.Time {
 	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 }
 
+// DaysBetween returns the number of calendar days from one date to another, negative
+// when to is earlier; days shortened or lengthened by a DST change count as whole days
+func DaysBetween(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
 // GetCalendarWeeks returns the weeks needed to display a month's calendar
 // Each week is represented as an array of day numbers (0 for empty cells)
 // weekStartDay: 0 = Sunday first, 1 = Monday first
@@ -153,3 +225,30 @@ func GetCalendarWeeks(month time.Time, weekStartDay int) [][]int {
 
 	return weeks
 }
+
+// GetWeekStarts returns the first date of each row of GetCalendarWeeks; the first
+// row may start in the previous month
+func GetWeekStarts(month time.Time, weekStartDay int) []time.Time {
+	weeks := GetCalendarWeeks(month, weekStartDay)
+	firstDay := GetFirstDayOfMonth(month)
+	offset := int(firstDay.Weekday())
+	if weekStartDay == 1 {
+		offset = (offset + 6) % 7
+	}
+
+	starts := make([]time.Time, len(weeks))
+	for i := range weeks {
+		starts[i] = firstDay.AddDate(0, 0, i*7-offset)
+	}
+	return starts
+}
+
+// GetWeekStart returns the first day of the week holding date
+// weekStartDay: 0 = Sunday first, 1 = Monday first
+func GetWeekStart(date time.Time, weekStartDay int) time.Time {
+	offset := int(date.Weekday())
+	if weekStartDay == 1 {
+		offset = (offset + 6) % 7
+	}
+	return NormalizeDate(date).AddDate(0, 0, -offset)
+}
@@ -1,6 +1,7 @@
 package calendar
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -184,6 +185,38 @@ func TestParseTime(t *testing.T) {
 	}
 }
 
+func TestParseTimeRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		start    string
+		duration time.Duration
+		wantErr  bool
+	}{
+		{"14:00", "14:00", 0, false},
+		{"14:00-15:30", "14:00", 90 * time.Minute, false},
+		{" 09:15 - 10:00 ", "09:15", 45 * time.Minute, false},
+		{"22:00-01:00", "22:00", 3 * time.Hour, false},
+		{"10:00-10:00", "10:00", 24 * time.Hour, false},
+		{"14:00-", "", 0, true},
+		{"14:00-25:00", "", 0, true},
+		{"2pm", "", 0, true},
+	}
+	for _, tt := range tests {
+		start, duration, err := ParseTimeRange(tt.input)
+		if (err != nil) != tt.wantErr || start != tt.start || duration != tt.duration {
+			t.Errorf("ParseTimeRange(%q) = %q, %v, %v; want %q, %v, error %v", tt.input, start, duration, err, tt.start, tt.duration, tt.wantErr)
+		}
+	}
+
+	start := time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC)
+	if got := FormatTimeRange(start, 90*time.Minute); got != "14:00-15:30" {
+		t.Errorf("FormatTimeRange() = %q, want 14:00-15:30", got)
+	}
+	if got := FormatTimeRange(start, 0); got != "14:00" {
+		t.Errorf("FormatTimeRange() without a duration = %q, want 14:00", got)
+	}
+}
+
 func TestValidateTimeString(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -265,6 +298,32 @@ func TestIsSameDate(t *testing.T) {
 	}
 }
 
+func TestDaysBetween(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("Zone Europe/Berlin unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		expected int
+	}{
+		{"Same day", time.Date(2025, 8, 16, 23, 0, 0, 0, time.UTC), time.Date(2025, 8, 16, 1, 0, 0, 0, time.UTC), 0},
+		{"Across a month", time.Date(2025, 8, 30, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC), 3},
+		{"Backwards", time.Date(2025, 8, 16, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 9, 0, 0, 0, 0, time.UTC), -7},
+		{"Across a DST change", time.Date(2025, 10, 25, 0, 0, 0, 0, berlin), time.Date(2025, 10, 27, 0, 0, 0, 0, berlin), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := DaysBetween(tt.from, tt.to); result != tt.expected {
+				t.Errorf("DaysBetween() = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestGetCalendarWeeks(t *testing.T) {
 	// Test August 2025 (starts on Friday, 5th weekday, has 31 days)
 	aug2025 := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
@@ -365,3 +424,98 @@ func TestGetDayOfWeekHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestGetWeekStarts(t *testing.T) {
+	aug2025 := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	sunday := GetWeekStarts(aug2025, 0)
+	if len(sunday) != 6 {
+		t.Fatalf("GetWeekStarts(Aug 2025, 0) returned %d weeks, want 6", len(sunday))
+	}
+	if want := time.Date(2025, time.July, 27, 0, 0, 0, 0, time.UTC); !sunday[0].Equal(want) {
+		t.Errorf("First Sunday-first week starts %v, want %v", sunday[0], want)
+	}
+	if want := time.Date(2025, time.August, 31, 0, 0, 0, 0, time.UTC); !sunday[5].Equal(want) {
+		t.Errorf("Last Sunday-first week starts %v, want %v", sunday[5], want)
+	}
+
+	monday := GetWeekStarts(aug2025, 1)
+	if want := time.Date(2025, time.July, 28, 0, 0, 0, 0, time.UTC); len(monday) != 5 || !monday[0].Equal(want) {
+		t.Errorf("GetWeekStarts(Aug 2025, 1) = %v, want 5 weeks from %v", monday, want)
+	}
+}
+
+func TestGetWeekStart(t *testing.T) {
+	// Wednesday afternoon
+	date := time.Date(2025, time.August, 13, 15, 30, 0, 0, time.UTC)
+
+	if got, want := GetWeekStart(date, 0), time.Date(2025, time.August, 10, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("GetWeekStart(Sunday first) = %v, want %v", got, want)
+	}
+	if got, want := GetWeekStart(date, 1), time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("GetWeekStart(Monday first) = %v, want %v", got, want)
+	}
+
+	sunday := time.Date(2025, time.August, 17, 0, 0, 0, 0, time.UTC)
+	if got, want := GetWeekStart(sunday, 1), time.Date(2025, time.August, 11, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("GetWeekStart(Sunday, Monday first) = %v, want %v", got, want)
+	}
+}
+
+func TestGetLocalizedDayOfWeekHeaders(t *testing.T) {
+	german := []string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"}
+	tests := []struct {
+		name         string
+		names        []string
+		weekStartDay int
+		expected     []string
+	}{
+		{"German, Monday first", german, 1, []string{"Mo", "Di", "Mi", "Do", "Fr", "Sa", "So"}},
+		{"German, Sunday first", german, 0, german},
+		{"Long names are cut", []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}, 0, []string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}},
+		{"Short names are padded", []string{"S", "M", "T", "W", "T", "F", "S"}, 1, []string{"M ", "T ", "W ", "T ", "F ", "S ", "S "}},
+		{"Too few names", []string{"So", "Mo"}, 1, []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := GetLocalizedDayOfWeekHeaders(tt.names, tt.weekStartDay)
+			if strings.Join(headers, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("GetLocalizedDayOfWeekHeaders() = %v, want %v", headers, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetLocalizedMonthName(t *testing.T) {
+	german := []string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}
+	march := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		names    []string
+		expected string
+	}{
+		{"German", german, "März"},
+		{"No names", nil, "March"},
+		{"Too few names", german[:3], "March"},
+		{"Empty name", append(append([]string{}, german[:2]...), append([]string{""}, german[3:]...)...), "March"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetLocalizedMonthName(tt.names, march); got != tt.expected {
+				t.Errorf("GetLocalizedMonthName() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatLocalDate(t *testing.T) {
+	date := time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC)
+	if got := FormatLocalDate(date, "DD.MM.YYYY"); got != "24.12.2026" {
+		t.Errorf("FormatLocalDate() = %s, want 24.12.2026", got)
+	}
+	if got := FormatLocalDate(date, ""); got != FormatDate(date) {
+		t.Errorf("FormatLocalDate() without a pattern = %s, want %s", got, FormatDate(date))
+	}
+}
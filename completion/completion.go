@@ -0,0 +1,199 @@
+package completion
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Shells lists the shells a completion script can be generated for
+var Shells = []string{"bash", "zsh", "fish"}
+
+// Flag is a command line flag as offered by the completion
+type Flag struct {
+	Name       string // Without the leading dash
+	Usage      string // One-line description
+	TakesValue bool   // Followed by a value; false for boolean flags
+	Files      bool   // The value is a file path
+}
+
+// Command is a positional command with the words its first argument completes to
+type Command struct {
+	Name        string
+	Description string
+	Args        []string // Values of its first argument; empty for free text
+}
+
+// Spec describes the command line of a program
+type Spec struct {
+	Program  string
+	Flags    []Flag
+	Commands []Command
+}
+
+// FlagsOf returns the flags registered in fs sorted by name; those named in files take
+// a file path
+func FlagsOf(fs *flag.FlagSet, files []string) []Flag {
+	isFile := make(map[string]bool)
+	for _, name := range files {
+		isFile[name] = true
+	}
+
+	var flags []Flag
+	fs.VisitAll(func(f *flag.Flag) {
+		boolean, ok := f.Value.(interface{ IsBoolFlag() bool })
+		flags = append(flags, Flag{
+			Name:       f.Name,
+			Usage:      f.Usage,
+			TakesValue: !ok || !boolean.IsBoolFlag(),
+			Files:      isFile[f.Name],
+		})
+	})
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// Script returns the completion script of spec for shell
+func Script(shell string, spec Spec) (string, error) {
+	switch shell {
+	case "bash":
+		return bashScript(spec), nil
+	case "zsh":
+		return zshScript(spec), nil
+	case "fish":
+		return fishScript(spec), nil
+	default:
+		return "", fmt.Errorf("unknown shell %q: expected %s", shell, strings.Join(Shells, ", "))
+	}
+}
+
+// functionName returns the name of the shell function completing program
+func functionName(program string) string {
+	return "_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, program)
+}
+
+// flagNames returns the dashed names of the flags matching keep
+func flagNames(flags []Flag, keep func(Flag) bool) []string {
+	var names []string
+	for _, f := range flags {
+		if keep(f) {
+			names = append(names, "-"+f.Name)
+		}
+	}
+	return names
+}
+
+func bashScript(spec Spec) string {
+	var b strings.Builder
+	fn := functionName(spec.Program)
+	fmt.Fprintf(&b, "# bash completion for %s\n", spec.Program)
+	fmt.Fprintf(&b, "# Load it with: source <(%s completion bash)\n\n", spec.Program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur prev\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+	b.WriteString("    case \"$prev\" in\n")
+	if files := flagNames(spec.Flags, func(f Flag) bool { return f.Files }); len(files) > 0 {
+		fmt.Fprintf(&b, "        %s)\n            COMPREPLY=($(compgen -f -- \"$cur\"))\n            return ;;\n", strings.Join(files, "|"))
+	}
+	if values := flagNames(spec.Flags, func(f Flag) bool { return f.TakesValue && !f.Files }); len(values) > 0 {
+		fmt.Fprintf(&b, "        %s)\n            COMPREPLY=()\n            return ;;\n", strings.Join(values, "|"))
+	}
+	for _, command := range spec.Commands {
+		if len(command.Args) > 0 {
+			fmt.Fprintf(&b, "        %s)\n            COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n            return ;;\n", command.Name, strings.Join(command.Args, " "))
+		}
+	}
+	b.WriteString("    esac\n\n")
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(flagNames(spec.Flags, func(Flag) bool { return true }), " "))
+	b.WriteString("    else\n")
+	var names []string
+	for _, command := range spec.Commands {
+		names = append(names, command.Name)
+	}
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(names, " "))
+	b.WriteString("    fi\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, spec.Program)
+	return b.String()
+}
+
+// zshDescription escapes a description for an _arguments specification in single quotes
+func zshDescription(text string) string {
+	return strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`).Replace(text)
+}
+
+func zshScript(spec Spec) string {
+	var b strings.Builder
+	fn := functionName(spec.Program)
+	fmt.Fprintf(&b, "#compdef %s\n", spec.Program)
+	fmt.Fprintf(&b, "# zsh completion for %s\n", spec.Program)
+	fmt.Fprintf(&b, "# Load it with: source <(%s completion zsh)\n\n", spec.Program)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local state\n")
+	b.WriteString("    _arguments \\\n")
+	for _, f := range spec.Flags {
+		value := ""
+		switch {
+		case f.Files:
+			value = ":file:_files"
+		case f.TakesValue:
+			value = ":value: "
+		}
+		fmt.Fprintf(&b, "        '-%s[%s]%s' \\\n", f.Name, zshDescription(f.Usage), value)
+	}
+	var names []string
+	for _, command := range spec.Commands {
+		description := strings.NewReplacer("'", "", `"`, "", ":", `\:`).Replace(command.Description)
+		names = append(names, fmt.Sprintf("%s\\:'%s'", command.Name, description))
+	}
+	fmt.Fprintf(&b, "        \"1:command:((%s))\" \\\n", strings.Join(names, " "))
+	b.WriteString("        '*::argument:->argument'\n\n")
+	b.WriteString("    [[ $state == argument ]] || return\n")
+	b.WriteString("    case $words[1] in\n")
+	for _, command := range spec.Commands {
+		if len(command.Args) > 0 {
+			fmt.Fprintf(&b, "        %s)\n            (( CURRENT == 2 )) && compadd -- %s ;;\n", command.Name, strings.Join(command.Args, " "))
+		}
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "compdef %s %s\n", fn, spec.Program)
+	return b.String()
+}
+
+// fishDescription escapes a description for a fish string in single quotes
+func fishDescription(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(text)
+}
+
+func fishScript(spec Spec) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", spec.Program)
+	fmt.Fprintf(&b, "# Load it with: %s completion fish | source\n\n", spec.Program)
+	fmt.Fprintf(&b, "complete -c %s -f\n", spec.Program)
+	for _, command := range spec.Commands {
+		fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a %s -d '%s'\n", spec.Program, command.Name, fishDescription(command.Description))
+		if len(command.Args) > 0 {
+			fmt.Fprintf(&b, "complete -c %s -n '__fish_seen_subcommand_from %s' -a '%s'\n", spec.Program, command.Name, strings.Join(command.Args, " "))
+		}
+	}
+	for _, f := range spec.Flags {
+		value := ""
+		switch {
+		case f.Files:
+			value = " -r -F"
+		case f.TakesValue:
+			value = " -x"
+		}
+		fmt.Fprintf(&b, "complete -c %s -o %s -d '%s'%s\n", spec.Program, f.Name, fishDescription(f.Usage), value)
+	}
+	return b.String()
+}
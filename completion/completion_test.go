@@ -0,0 +1,99 @@
+package completion
+
+import (
+	"flag"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func testSpec() Spec {
+	fs := flag.NewFlagSet("cal", flag.ContinueOnError)
+	fs.String("f", "", "Path to events file")
+	fs.Bool("dry-run", false, "Don't write [anything]: it's a test")
+	fs.Int("share-days", 7, "Days shared")
+	return Spec{
+		Program: "my-cal",
+		Flags:   FlagsOf(fs, []string{"f"}),
+		Commands: []Command{
+			{Name: "add", Description: "Add events"},
+			{Name: "completion", Description: "Print a completion script", Args: Shells},
+		},
+	}
+}
+
+func TestFlagsOf(t *testing.T) {
+	flags := testSpec().Flags
+	if len(flags) != 3 || flags[0].Name != "dry-run" || flags[1].Name != "f" || flags[2].Name != "share-days" {
+		t.Fatalf("FlagsOf() = %+v, want the three flags sorted by name", flags)
+	}
+	if flags[0].TakesValue || !flags[1].TakesValue || !flags[1].Files || !flags[2].TakesValue || flags[2].Files {
+		t.Errorf("FlagsOf() = %+v, want -dry-run boolean, -f a file and -share-days a value", flags)
+	}
+}
+
+func TestScript(t *testing.T) {
+	tests := map[string][]string{
+		"bash": {"_my_cal() {", `-dry-run -f -share-days`, "-f)\n            COMPREPLY=($(compgen -f", `compgen -W "bash zsh fish"`, "complete -F _my_cal my-cal\n"},
+		"zsh":  {"#compdef my-cal\n", `'-dry-run[Don'\''t write \[anything\]\: it'\''s a test]'`, "'-f[Path to events file]:file:_files'", `add\:'Add events'`, "compadd -- bash zsh fish", "compdef _my_cal my-cal\n"},
+		"fish": {"complete -c my-cal -f\n", `-o dry-run -d 'Don\'t write [anything]: it\'s a test'` + "\n", "-o f -d 'Path to events file' -r -F\n", "-o share-days -d 'Days shared' -x\n", "'__fish_seen_subcommand_from completion' -a 'bash zsh fish'"},
+	}
+
+	for shell, wants := range tests {
+		script, err := Script(shell, testSpec())
+		if err != nil {
+			t.Fatalf("Script(%s) failed: %v", shell, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(script, want) {
+				t.Errorf("Script(%s) misses %q:\n%s", shell, want, script)
+			}
+		}
+	}
+
+	if _, err := Script("tcsh", testSpec()); err == nil {
+		t.Error("Script() should reject an unknown shell")
+	}
+}
+
+func TestScript_Bash(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not installed")
+	}
+	script, _ := Script("bash", testSpec())
+
+	tests := []struct {
+		words []string
+		want  string
+	}{
+		{[]string{"my-cal", "-"}, "-dry-run -f -share-days"},
+		{[]string{"my-cal", "-sh"}, "-share-days"},
+		{[]string{"my-cal", "co"}, "completion"},
+		{[]string{"my-cal", "completion", "z"}, "zsh"},
+		{[]string{"my-cal", "-share-days", ""}, ""},
+	}
+	for _, tt := range tests {
+		program := script + `
+COMP_WORDS=(` + strings.Join(quoteAll(tt.words), " ") + `)
+COMP_CWORD=$((${#COMP_WORDS[@]} - 1))
+_my_cal
+echo "${COMPREPLY[*]}"`
+		out, err := exec.Command(bash, "-c", program).Output()
+		if err != nil {
+			t.Fatalf("Running the bash completion failed: %v", err)
+		}
+		if got := strings.TrimSpace(string(out)); got != tt.want {
+			t.Errorf("Completing %q = %q, want %q", tt.words, got, tt.want)
+		}
+	}
+}
+
+// quoteAll quotes words for a bash array
+func quoteAll(words []string) []string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = "'" + word + "'"
+	}
+	return quoted
+}
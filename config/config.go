@@ -1,12 +1,21 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"go-ascii-calendar/locale"
+	"go-ascii-calendar/storage"
 
 	"github.com/nsf/termbox-go"
 )
@@ -21,6 +30,10 @@ const (
 
 // ColorTheme defines colors for all UI elements
 type ColorTheme struct {
+	// Palette names colors, e.g. "accent": "cyan|bold", for the fields below to use
+	// in place of a color; see ResolveColor
+	Palette map[string]string `json:"palette,omitempty"`
+
 	// Month headers (e.g., "August 2025")
 	MonthHeaderFg string `json:"month_header_fg"`
 	MonthHeaderBg string `json:"month_header_bg"`
@@ -49,6 +62,14 @@ type ColorTheme struct {
 	EventDayFg string `json:"event_day_fg"`
 	EventDayBg string `json:"event_day_bg"`
 
+	// Days before today, when past_days dims them
+	PastDayFg string `json:"past_day_fg"`
+	PastDayBg string `json:"past_day_bg"`
+
+	// Public holidays
+	HolidayFg string `json:"holiday_fg"`
+	HolidayBg string `json:"holiday_bg"`
+
 	// Event list section header
 	EventHeaderFg string `json:"event_header_fg"`
 	EventHeaderBg string `json:"event_header_bg"`
@@ -69,6 +90,14 @@ type ColorTheme struct {
 	MoreEventsFg string `json:"more_events_fg"`
 	MoreEventsBg string `json:"more_events_bg"`
 
+	// Warning messages
+	WarningFg string `json:"warning_fg"`
+	WarningBg string `json:"warning_bg"`
+
+	// De-emphasized entries, e.g. undone changes and finished events
+	MutedFg string `json:"muted_fg"`
+	MutedBg string `json:"muted_bg"`
+
 	// Error messages
 	ErrorFg string `json:"error_fg"`
 	ErrorBg string `json:"error_bg"`
@@ -92,7 +121,7 @@ type ColorTheme struct {
 
 // Predefined color themes
 var (
-	// DefaultTheme matches the current hardcoded colors in the renderer
+	// DefaultTheme is used when the configuration sets up no theme of its own
 	DefaultTheme = ColorTheme{
 		MonthHeaderFg:   "magenta|bold",
 		MonthHeaderBg:   "default",
@@ -108,6 +137,10 @@ var (
 		SelectedTodayBg: "cyan",
 		EventDayFg:      "green",
 		EventDayBg:      "default",
+		PastDayFg:       "default|dim",
+		PastDayBg:       "default",
+		HolidayFg:       "red",
+		HolidayBg:       "default",
 		EventHeaderFg:   "yellow|bold",
 		EventHeaderBg:   "default",
 		EventTextFg:     "white",
@@ -118,6 +151,10 @@ var (
 		NoEventsBg:      "default",
 		MoreEventsFg:    "magenta",
 		MoreEventsBg:    "default",
+		WarningFg:       "yellow|bold",
+		WarningBg:       "default",
+		MutedFg:         "black|bold",
+		MutedBg:         "default",
 		ErrorFg:         "red",
 		ErrorBg:         "default",
 		SuccessFg:       "green",
@@ -146,6 +183,10 @@ var (
 		SelectedTodayBg: "bright_cyan",
 		EventDayFg:      "bright_green",
 		EventDayBg:      "default",
+		PastDayFg:       "white|dim",
+		PastDayBg:       "default",
+		HolidayFg:       "bright_red",
+		HolidayBg:       "default",
 		EventHeaderFg:   "bright_yellow|bold",
 		EventHeaderBg:   "default",
 		EventTextFg:     "bright_white",
@@ -156,6 +197,10 @@ var (
 		NoEventsBg:      "default",
 		MoreEventsFg:    "bright_magenta",
 		MoreEventsBg:    "default",
+		WarningFg:       "bright_yellow|bold",
+		WarningBg:       "default",
+		MutedFg:         "white|dim",
+		MutedBg:         "default",
 		ErrorFg:         "bright_red",
 		ErrorBg:         "default",
 		SuccessFg:       "bright_green",
@@ -184,6 +229,10 @@ var (
 		SelectedTodayBg: "red",
 		EventDayFg:      "green|bold",
 		EventDayBg:      "default",
+		PastDayFg:       "black|dim",
+		PastDayBg:       "default",
+		HolidayFg:       "red|bold",
+		HolidayBg:       "default",
 		EventHeaderFg:   "blue|bold",
 		EventHeaderBg:   "default",
 		EventTextFg:     "black",
@@ -194,6 +243,10 @@ var (
 		NoEventsBg:      "default",
 		MoreEventsFg:    "blue",
 		MoreEventsBg:    "default",
+		WarningFg:       "magenta|bold",
+		WarningBg:       "default",
+		MutedFg:         "black|dim",
+		MutedBg:         "default",
 		ErrorFg:         "red|bold",
 		ErrorBg:         "default",
 		SuccessFg:       "green|bold",
@@ -207,7 +260,89 @@ var (
 	}
 )
 
-// ParseColor converts a color string like "magenta|bold" to termbox color attributes
+// colorNames maps the color names of color strings to termbox colors
+var colorNames = map[string]termbox.Attribute{
+	"default":        termbox.ColorDefault,
+	"black":          termbox.ColorBlack,
+	"red":            termbox.ColorRed,
+	"green":          termbox.ColorGreen,
+	"yellow":         termbox.ColorYellow,
+	"blue":           termbox.ColorBlue,
+	"magenta":        termbox.ColorMagenta,
+	"cyan":           termbox.ColorCyan,
+	"white":          termbox.ColorWhite,
+	"bright_black":   termbox.ColorBlack | termbox.AttrBold,
+	"bright_red":     termbox.ColorRed | termbox.AttrBold,
+	"bright_green":   termbox.ColorGreen | termbox.AttrBold,
+	"bright_yellow":  termbox.ColorYellow | termbox.AttrBold,
+	"bright_blue":    termbox.ColorBlue | termbox.AttrBold,
+	"bright_magenta": termbox.ColorMagenta | termbox.AttrBold,
+	"bright_cyan":    termbox.ColorCyan | termbox.AttrBold,
+	"bright_white":   termbox.ColorWhite | termbox.AttrBold,
+}
+
+// cubeLevels are the red, green and blue levels of the 6x6x6 color cube of the 256-color
+// palette, which starts at color 16 and is followed by 24 grays from color 232
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// ColorRGB returns the red, green and blue of color index of the 256-color palette,
+// from 16 on; the first 16 colors depend on the terminal's own theme
+func ColorRGB(index int) (r, g, b int) {
+	if index >= 232 {
+		gray := 8 + (index-232)*10
+		return gray, gray, gray
+	}
+	index -= 16
+	return cubeLevels[index/36], cubeLevels[index/6%6], cubeLevels[index%6]
+}
+
+// nearestColor returns the index of the color of the 256-color palette closest to an
+// RGB color, leaving out the first 16 colors as their look depends on the terminal
+func nearestColor(r, g, b int) int {
+	nearest, best := 16, -1
+	for index := 16; index < 256; index++ {
+		cr, cg, cb := ColorRGB(index)
+		distance := (r-cr)*(r-cr) + (g-cg)*(g-cg) + (b-cb)*(b-cb)
+		if best < 0 || distance < best {
+			nearest, best = index, distance
+		}
+	}
+	return nearest
+}
+
+// parseColorName converts a color name to a termbox color: one of colorNames, a color
+// of the 256-color palette like "color208", or an RGB color like "#ff8700", which is
+// drawn with the closest color of the palette
+func parseColorName(name string) (termbox.Attribute, bool) {
+	if color, ok := colorNames[name]; ok {
+		return color, true
+	}
+	if digits, ok := strings.CutPrefix(name, "color"); ok {
+		index, err := strconv.Atoi(digits)
+		if err != nil || index < 0 || index > 255 || digits != strconv.Itoa(index) {
+			return termbox.ColorDefault, false
+		}
+		// termbox numbers the colors of the 256-color output mode from 1
+		return termbox.Attribute(index + 1), true
+	}
+	if hex, ok := strings.CutPrefix(name, "#"); ok && len(hex) == 6 {
+		rgb, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return termbox.ColorDefault, false
+		}
+		return termbox.Attribute(nearestColor(int(rgb>>16), int(rgb>>8&0xff), int(rgb&0xff)) + 1), true
+	}
+	return termbox.ColorDefault, false
+}
+
+// isColorName reports whether name is a color rather than a palette entry
+func isColorName(name string) bool {
+	_, ok := parseColorName(name)
+	return ok
+}
+
+// ParseColor converts a color string like "magenta|bold", "color208|bold" or
+// "#ff8700|underline" to termbox color attributes
 func ParseColor(colorStr string) (termbox.Attribute, error) {
 	if colorStr == "" || colorStr == "default" {
 		return termbox.ColorDefault, nil
@@ -217,28 +352,7 @@ func ParseColor(colorStr string) (termbox.Attribute, error) {
 	parts := strings.Split(colorStr, "|")
 	colorName := strings.TrimSpace(parts[0])
 
-	// Color name mapping
-	colorMap := map[string]termbox.Attribute{
-		"default":        termbox.ColorDefault,
-		"black":          termbox.ColorBlack,
-		"red":            termbox.ColorRed,
-		"green":          termbox.ColorGreen,
-		"yellow":         termbox.ColorYellow,
-		"blue":           termbox.ColorBlue,
-		"magenta":        termbox.ColorMagenta,
-		"cyan":           termbox.ColorCyan,
-		"white":          termbox.ColorWhite,
-		"bright_black":   termbox.ColorBlack | termbox.AttrBold,
-		"bright_red":     termbox.ColorRed | termbox.AttrBold,
-		"bright_green":   termbox.ColorGreen | termbox.AttrBold,
-		"bright_yellow":  termbox.ColorYellow | termbox.AttrBold,
-		"bright_blue":    termbox.ColorBlue | termbox.AttrBold,
-		"bright_magenta": termbox.ColorMagenta | termbox.AttrBold,
-		"bright_cyan":    termbox.ColorCyan | termbox.AttrBold,
-		"bright_white":   termbox.ColorWhite | termbox.AttrBold,
-	}
-
-	color, exists := colorMap[colorName]
+	color, exists := parseColorName(colorName)
 	if !exists {
 		return termbox.ColorDefault, fmt.Errorf("unknown color: %s", colorName)
 	}
@@ -253,6 +367,8 @@ func ParseColor(colorStr string) (termbox.Attribute, error) {
 			color |= termbox.AttrUnderline
 		case "reverse":
 			color |= termbox.AttrReverse
+		case "dim":
+			color |= termbox.AttrDim
 		default:
 			return termbox.ColorDefault, fmt.Errorf("unknown attribute: %s", attr)
 		}
@@ -261,8 +377,56 @@ func ParseColor(colorStr string) (termbox.Attribute, error) {
 	return color, nil
 }
 
-// ValidateColorTheme validates that all colors in a theme are parseable
+// ResolveColor replaces a palette name at the start of a color string with its color,
+// following palette entries that name other entries, and keeps the attributes after
+// it: with "accent": "cyan|bold", "accent|underline" becomes "cyan|bold|underline".
+// Strings starting with a color name are returned unchanged. A palette entry that
+// leads back to itself is an error.
+func (t *ColorTheme) ResolveColor(colorStr string) (string, error) {
+	var seen []string
+	for {
+		name, attrs, hasAttrs := strings.Cut(colorStr, "|")
+		name = strings.TrimSpace(name)
+		value, ok := t.Palette[name]
+		if isColorName(name) || !ok {
+			return colorStr, nil
+		}
+
+		for _, previous := range seen {
+			if previous == name {
+				return "", fmt.Errorf("palette cycle: %s", strings.Join(append(seen, name), " -> "))
+			}
+		}
+		seen = append(seen, name)
+
+		if hasAttrs {
+			value += "|" + attrs
+		}
+		colorStr = value
+	}
+}
+
+// ValidateColorTheme validates that all colors in a theme are parseable, resolving
+// palette names, and that the palette has no cycles or names shadowing colors
 func ValidateColorTheme(theme *ColorTheme) error {
+	names := make([]string, 0, len(theme.Palette))
+	for name := range theme.Palette {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if isColorName(name) || name == "" {
+			return fmt.Errorf("invalid palette name '%s': it must differ from the color names", name)
+		}
+		resolved, err := theme.ResolveColor(name)
+		if err != nil {
+			return err
+		}
+		if _, err := ParseColor(resolved); err != nil {
+			return fmt.Errorf("invalid palette color '%s': %v", name, err)
+		}
+	}
+
 	colorFields := []string{
 		theme.MonthHeaderFg, theme.MonthHeaderBg,
 		theme.DayHeaderFg, theme.DayHeaderBg,
@@ -271,11 +435,15 @@ func ValidateColorTheme(theme *ColorTheme) error {
 		theme.SelectedFg, theme.SelectedBg,
 		theme.SelectedTodayFg, theme.SelectedTodayBg,
 		theme.EventDayFg, theme.EventDayBg,
+		theme.PastDayFg, theme.PastDayBg,
+		theme.HolidayFg, theme.HolidayBg,
 		theme.EventHeaderFg, theme.EventHeaderBg,
 		theme.EventTextFg, theme.EventTextBg,
 		theme.SelectedEventFg, theme.SelectedEventBg,
 		theme.NoEventsFg, theme.NoEventsBg,
 		theme.MoreEventsFg, theme.MoreEventsBg,
+		theme.WarningFg, theme.WarningBg,
+		theme.MutedFg, theme.MutedBg,
 		theme.ErrorFg, theme.ErrorBg,
 		theme.SuccessFg, theme.SuccessBg,
 		theme.InputFg, theme.InputBg,
@@ -284,7 +452,11 @@ func ValidateColorTheme(theme *ColorTheme) error {
 	}
 
 	for _, colorStr := range colorFields {
-		if _, err := ParseColor(colorStr); err != nil {
+		resolved, err := theme.ResolveColor(colorStr)
+		if err != nil {
+			return err
+		}
+		if _, err := ParseColor(resolved); err != nil {
 			return fmt.Errorf("invalid color '%s': %v", colorStr, err)
 		}
 	}
@@ -292,6 +464,12 @@ func ValidateColorTheme(theme *ColorTheme) error {
 	return nil
 }
 
+// ThemeNames lists the predefined themes in the order the in-app switcher cycles through them
+var ThemeNames = []string{"default", "dark", "light"}
+
+// ThemeCustom names the theme set up in ui_theme
+const ThemeCustom = "custom"
+
 // GetThemeByName returns a predefined theme by name
 func GetThemeByName(name string) (ColorTheme, error) {
 	switch strings.ToLower(name) {
@@ -306,29 +484,576 @@ func GetThemeByName(name string) (ColorTheme, error) {
 	}
 }
 
+// ThemeChoices returns the themes the in-app switcher cycles through: the predefined
+// ones, followed by the custom theme when ui_theme differs from the default theme
+func (c *Config) ThemeChoices() []string {
+	choices := append([]string(nil), ThemeNames...)
+	if !reflect.DeepEqual(c.UITheme, DefaultTheme) {
+		choices = append(choices, ThemeCustom)
+	}
+	return choices
+}
+
+// ThemeByName returns a predefined theme or, for "custom", the theme of ui_theme
+func (c *Config) ThemeByName(name string) (ColorTheme, error) {
+	if strings.EqualFold(name, ThemeCustom) {
+		return c.UITheme, nil
+	}
+	return GetThemeByName(name)
+}
+
+// CurrentTheme returns the theme named by the theme setting, or the theme of ui_theme
+// when the setting is empty or names no theme
+func (c *Config) CurrentTheme() ColorTheme {
+	if c.Theme == "" {
+		return c.UITheme
+	}
+	theme, err := c.ThemeByName(c.Theme)
+	if err != nil {
+		return c.UITheme
+	}
+	return theme
+}
+
+// ReadThemeFile reads a theme from a JSON file with the fields of ui_theme; fields the
+// file leaves out keep the colors of the default theme. The theme must pass
+// ValidateColorTheme.
+func ReadThemeFile(path string) (ColorTheme, error) {
+	theme := DefaultTheme
+	theme.Palette = nil
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return theme, fmt.Errorf("failed to read theme file: %v", err)
+	}
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return theme, fmt.Errorf("invalid theme file %s: %v", path, err)
+	}
+	if err := ValidateColorTheme(&theme); err != nil {
+		return theme, fmt.Errorf("invalid theme file %s: %v", path, err)
+	}
+	return theme, nil
+}
+
+// themeFilePath returns the path of ui_theme_file, taking a relative path from the
+// directory of the configuration file
+func (c *Config) themeFilePath() string {
+	if filepath.IsAbs(c.UIThemeFile) {
+		return c.UIThemeFile
+	}
+	return filepath.Join(filepath.Dir(c.ConfigFilePath), c.UIThemeFile)
+}
+
+// loadThemeFile makes the theme of ui_theme_file the custom theme, when one is set
+func (c *Config) loadThemeFile() error {
+	if c.UIThemeFile == "" {
+		return nil
+	}
+	theme, err := ReadThemeFile(c.themeFilePath())
+	if err != nil {
+		return err
+	}
+	c.UITheme = theme
+	return nil
+}
+
+// previewTheme uses the theme given with -theme for this session: a theme name, or a
+// JSON theme file that becomes the custom theme
+func (c *Config) previewTheme(value string) error {
+	if _, err := c.ThemeByName(value); err == nil {
+		c.Theme = strings.ToLower(value)
+		return nil
+	}
+	if !strings.HasSuffix(strings.ToLower(value), ".json") {
+		if _, err := os.Stat(value); err != nil {
+			return fmt.Errorf("unknown theme %q: expected one of %s, %s or a JSON theme file", value, strings.Join(ThemeNames, ", "), ThemeCustom)
+		}
+	}
+	theme, err := ReadThemeFile(value)
+	if err != nil {
+		return err
+	}
+	c.UITheme = theme
+	c.Theme = ThemeCustom
+	return nil
+}
+
+// Bell settings for feedback on rejected actions, such as unknown keys or moves
+// past the visible months
+const (
+	BellVisual  = "visual"  // Briefly flash the status bar (default)
+	BellAudible = "audible" // Ring the terminal bell
+	BellOff     = "off"     // Ignore rejected actions silently
+)
+
+// DefaultFreeEveningFrom is the start of the evening when free_evening_from is not a valid time
+const DefaultFreeEveningFrom = "18:00"
+
+// Search result orders
+const (
+	SearchOrderDate      = "date"      // Oldest first (default)
+	SearchOrderNearest   = "nearest"   // Nearest upcoming first, then the most recent past
+	SearchOrderRelevance = "relevance" // Best match first, see events.OrderByRelevance
+)
+
+// Goal periods
+const (
+	GoalWeekly  = "week"  // Counted from the first day of the week, see week_start_day
+	GoalMonthly = "month" // Counted from the first day of the month
+)
+
+// Past day dimming, see PastDaysConfig
+const (
+	PastDaysOff   = "off"   // Past days look like any other day (default)
+	PastDaysMonth = "month" // Dim the days before today in the current month
+	PastDaysAll   = "all"   // Dim every day before today in the months shown
+
+	PastDimLight  = "light"  // Dim days without events; event days keep their color (default)
+	PastDimStrong = "strong" // Dim event days as well
+)
+
+// PastDaysConfig dims the days before today so the rest of the month stands out
+type PastDaysConfig struct {
+	Dim       string `json:"dim"`       // "off", "month" or "all"
+	Intensity string `json:"intensity"` // "light" or "strong"
+}
+
+// NormalizationConfig controls how event descriptions are tidied up on save
+type NormalizationConfig struct {
+	TrimWhitespace bool `json:"trim_whitespace"` // Remove leading and trailing whitespace
+	CollapseSpaces bool `json:"collapse_spaces"` // Replace runs of whitespace with a single space
+	SentenceCase   bool `json:"sentence_case"`   // Capitalize the first letter
+}
+
+// DefaultNormalization trims and collapses whitespace but leaves casing alone
+var DefaultNormalization = NormalizationConfig{
+	TrimWhitespace: true,
+	CollapseSpaces: true,
+	SentenceCase:   false,
+}
+
+// EventCategory names a category, its display color and its quick-assign hotkey
+type EventCategory struct {
+	Name   string `json:"name"`
+	Color  string `json:"color"`  // Color string, e.g. "blue|bold"
+	Hotkey string `json:"hotkey"` // "1" to "9", pressed while an event is selected
+}
+
+// EventCalendar is a named events file shown along with the others in its own color,
+// e.g. "work". A calendar without a file names the main events file.
+type EventCalendar struct {
+	Name   string `json:"name"`
+	File   string `json:"file,omitempty"`   // Path of the events file; empty for the main events file
+	Color  string `json:"color,omitempty"`  // Color string of its events, e.g. "cyan"
+	Hidden bool   `json:"hidden,omitempty"` // Hidden on startup until shown in the calendars view
+}
+
+// MainCalendarName is the name of the main events file when no calendar names it
+const MainCalendarName = "main"
+
+// BannerWidget configures one section of the startup banner
+type BannerWidget struct {
+	Type       string      `json:"type"`                 // "agenda", "countdowns", "weather" or "quote"
+	Title      string      `json:"title,omitempty"`      // Section title; defaults to a name for the type
+	Command    string      `json:"command,omitempty"`    // weather: shell command printing the forecast
+	File       string      `json:"file,omitempty"`       // quote: file with one quote per line
+	Countdowns []Countdown `json:"countdowns,omitempty"` // countdowns: pinned dates
+}
+
+// Countdown is a pinned date shown with the number of days left
+type Countdown struct {
+	Name string `json:"name"`
+	Date string `json:"date"` // YYYY-MM-DD
+}
+
+// Decorations are optional ornaments of the calendar view. Each one is dropped
+// automatically when the terminal is too small to show it next to the events panel.
+type Decorations struct {
+	MonthBanner bool `json:"month_banner"` // Large ASCII-art month and year above the grids
+	Borders     bool `json:"borders"`      // Box-drawing borders around each month
+	Separators  bool `json:"separators"`   // Line between the calendar and the events panel
+	WeekTotals  bool `json:"week_totals"`  // Per-week event counts under each month grid
+}
+
+// TimeChecksConfig sets when the time or duration of a new event is unusual enough to
+// be a typo, such as 03:00 for an AM/PM mix-up or 14 hours for a mistyped end time
+type TimeChecksConfig struct {
+	UnusualFrom      string `json:"unusual_from"`       // Start of the unusual hours (HH:MM); empty turns the time check off
+	UnusualUntil     string `json:"unusual_until"`      // End of the unusual hours (HH:MM), exclusive; may be before unusual_from
+	MaxDurationHours int    `json:"max_duration_hours"` // Longer durations are unusual; 0 turns the duration check off
+}
+
+// DefaultTimeChecks warns about events starting from 01:00 until 06:00 and lasting
+// longer than 12 hours
+var DefaultTimeChecks = TimeChecksConfig{
+	UnusualFrom:      "01:00",
+	UnusualUntil:     "06:00",
+	MaxDurationHours: 12,
+}
+
+// WorkloadConfig sets when a day holds too much, so it is warned about in the month
+// view and the day panel
+type WorkloadConfig struct {
+	MaxEvents  int `json:"max_events"`  // More events on a day are too many; 0 turns the count check off
+	MaxMinutes int `json:"max_minutes"` // More scheduled minutes on a day are too many; 0 turns the duration check off
+}
+
+// RetentionConfig deletes old events automatically, keeping them in a trash for a grace period
+type RetentionConfig struct {
+	MaxAgeDays int `json:"max_age_days"` // Events dated longer ago are purged; 0 keeps events forever
+	TrashDays  int `json:"trash_days"`   // Days purged events stay restorable before they are dropped
+}
+
+// CalDAVConfig points the calendar at a CalDAV calendar (Nextcloud, Fastmail, ...) to sync events with
+type CalDAVConfig struct {
+	URL         string `json:"url"`                    // Calendar collection URL; empty turns CalDAV sync off
+	Username    string `json:"username"`               // Login name
+	Password    string `json:"password,omitempty"`     // Password, preferably an app password
+	PasswordCmd string `json:"password_cmd,omitempty"` // Shell command printing the password, used instead of password
+}
+
+// AnnotationSource configures an external data source of day annotations
+type AnnotationSource struct {
+	Type   string `json:"type"`             // "rota" (YYYY-MM-DD name handovers) or "csv" (start,end,label rows)
+	File   string `json:"file"`             // Path of the data file
+	Title  string `json:"title,omitempty"`  // Prefix of the notes, e.g. "On call"
+	Symbol string `json:"symbol,omitempty"` // Character next to the day number; defaults to "*"
+	Color  string `json:"color,omitempty"`  // Color string, e.g. "yellow"
+}
+
+// QuickFilter is a filter preset toggled with a function key. An event passes when it
+// has the category, if one is set, and matches the query, if one is set.
+type QuickFilter struct {
+	Key      string `json:"key"`                // Function key, "F1" to "F8"
+	Name     string `json:"name"`               // Shown in the header while the filter is active
+	Category string `json:"category,omitempty"` // Category name, compared case-insensitively
+	Query    string `json:"query,omitempty"`    // Search query, e.g. "standup" or "cmd:backup"
+}
+
+// Goal is a number of events to attend each week or month, e.g. three gym sessions.
+// Events count towards it like towards a quick filter: by category and search query.
+type Goal struct {
+	Name     string `json:"name"`               // Shown with the progress, e.g. "gym"
+	Period   string `json:"period"`             // "week" or "month"
+	Target   int    `json:"target"`             // Events needed in each period
+	Category string `json:"category,omitempty"` // Category name, compared case-insensitively
+	Query    string `json:"query,omitempty"`    // Search query, e.g. "gym" or "desc:run"
+}
+
+// Holiday is a named day off, shown in the holiday color and noted when adding events on it
+type Holiday struct {
+	Name string `json:"name"`
+	Date string `json:"date"` // YYYY-MM-DD, or MM-DD for a holiday on the same date every year
+}
+
+// HostProfile overrides settings on one machine
+type HostProfile struct {
+	EventsFilePath string `json:"events_file_path"`
+}
+
+// Hyperlink modes for URLs in event descriptions
+const (
+	HyperlinksAuto = "auto" // Clickable when the terminal is known to support OSC 8 (default)
+	HyperlinksOn   = "on"   // Always clickable
+	HyperlinksOff  = "off"  // Plain text
+)
+
+// DefaultCategories are available until the configuration file defines its own
+var DefaultCategories = []EventCategory{
+	{Name: "work", Color: "blue|bold", Hotkey: "1"},
+	{Name: "personal", Color: "green", Hotkey: "2"},
+	{Name: "health", Color: "red", Hotkey: "3"},
+	{Name: "social", Color: "magenta", Hotkey: "4"},
+	{Name: "travel", Color: "cyan", Hotkey: "5"},
+}
+
 // Config holds the application configuration
 type Config struct {
-	EventsFilePath string       `json:"events_file_path"`
-	ConfigFilePath string       `json:"-"` // Don't serialize this field
-	WeekStartDay   WeekStartDay `json:"week_start_day"`
-	UITheme        ColorTheme   `json:"ui_theme"`
+	EventsFilePath string              `json:"events_file_path"`
+	ConfigFilePath string              `json:"-"` // Don't serialize this field
+	WeekStartDay   WeekStartDay        `json:"week_start_day"`
+	UITheme        ColorTheme          `json:"ui_theme"`
+	Normalization  NormalizationConfig `json:"description_normalization"`
+
+	// UIThemeFile is a JSON file holding the custom theme in place of ui_theme, with the
+	// same fields; a relative path is taken from the directory of the configuration file
+	UIThemeFile string `json:"ui_theme_file,omitempty"`
+
+	// Theme names the theme in use: "default", "dark", "light" or "custom" for ui_theme,
+	// which is also used when it is empty; the in-app switcher saves its choice here
+	Theme string `json:"theme,omitempty"`
+
+	// DateFormat is the pattern of dates shown in the calendar, of YYYY, MM and DD (e.g. "DD.MM.YYYY")
+	DateFormat string `json:"date_format"`
+
+	// WeekdayNames are the seven day-of-week headers of the month grids, Sunday first
+	WeekdayNames []string `json:"weekday_names"`
+
+	// MonthNames are the twelve month names heading the month grids, January first
+	MonthNames []string `json:"month_names"`
+
+	// Locale names the locale picking the defaults above instead of LC_ALL, LC_TIME and LANG, e.g. "de_DE"
+	Locale string `json:"locale"`
+
+	// Timezone is the IANA zone event times are in, e.g. "Europe/Berlin" (empty = system zone)
+	Timezone string `json:"timezone"`
+
+	// MaxEventsPerDay caps events listed in the selected-date panel (0 = as many as fit)
+	MaxEventsPerDay int `json:"max_events_per_day"`
+
+	// UsageStats enables local-only usage statistics stored in the data directory
+	UsageStats bool `json:"usage_stats"`
+
+	// Categories available for events, with colors and quick-assign hotkeys
+	Categories []EventCategory `json:"categories"`
+
+	// Calendars are further events files, e.g. "work", shown with the main one in their own
+	// colors; each can be hidden, and new events go to the calendar picked when adding
+	Calendars []EventCalendar `json:"calendars"`
+
+	// AlternateScreen draws on the terminal's alternate screen so quitting restores the shell
+	AlternateScreen bool `json:"alternate_screen"`
+
+	// TerminalTitle shows the displayed month in the terminal window title
+	TerminalTitle bool `json:"terminal_title"`
+
+	// StartupBanner lists the widgets of the banner shown on startup and when idle (empty = no banner)
+	StartupBanner []BannerWidget `json:"startup_banner"`
+
+	// BannerIdleMinutes shows the banner again after this many minutes without input (0 = never)
+	BannerIdleMinutes int `json:"banner_idle_minutes"`
+
+	// ScreensaverMinutes switches to a clock with a ticker of upcoming events after this many
+	// minutes without input (0 = never)
+	ScreensaverMinutes int `json:"screensaver_minutes"`
+
+	// DeleteUndoSeconds keeps a deleted event undoable with U for this many seconds before
+	// the deletion is written (0 = write at once)
+	DeleteUndoSeconds int `json:"delete_undo_seconds"`
+
+	// BackupCount is how many rotating backups of the events file (events.json.bak.1 and up)
+	// are kept, each taken before a delete, edit or migration rewrites it (0 = no backups)
+	BackupCount int `json:"backup_count"`
+
+	// LiveReload reloads the events when another program, such as a sync tool, changes the
+	// events files while the calendar runs
+	LiveReload bool `json:"live_reload"`
+
+	// EmergencyFilePath is where a copy of the events is offered to be written while the
+	// events file cannot be written (empty = ascii-calendar-unsaved.json in the temp directory)
+	EmergencyFilePath string `json:"emergency_file_path"`
+
+	// SyncPullCmd is a shell command fetching the data directory from elsewhere (e.g. "git pull")
+	SyncPullCmd string `json:"sync_pull_cmd"`
+
+	// SyncPushCmd is a shell command publishing the data directory (e.g. an rclone copy)
+	SyncPushCmd string `json:"sync_push_cmd"`
+
+	// SyncIntervalMinutes is how often sync commands run while the calendar is open (0 = startup/shutdown only)
+	SyncIntervalMinutes int `json:"sync_interval_minutes"`
+
+	// CalDAV syncs events with a CalDAV server when the sync key is pressed
+	CalDAV CalDAVConfig `json:"caldav"`
+
+	// EventsWarnCount shows a startup hint when the events file holds more events than this (0 = no limit)
+	EventsWarnCount int `json:"events_warn_count"`
+
+	// EventsWarnBytes shows a startup hint when the events file is larger than this many bytes (0 = no limit)
+	EventsWarnBytes int64 `json:"events_warn_bytes"`
+
+	// Hyperlinks makes URLs in event descriptions clickable: "auto", "on" or "off"
+	Hyperlinks string `json:"hyperlinks"`
+
+	// ClipboardCmd is a shell command receiving copied events on standard input (e.g. "pbcopy");
+	// when empty they are sent to the terminal's clipboard with OSC 52
+	ClipboardCmd string `json:"clipboard_cmd"`
+
+	// ClipboardPasteCmd is a shell command printing the clipboard (e.g. "pbpaste") for pasting
+	// events; when empty they are pasted into a text box instead
+	ClipboardPasteCmd string `json:"clipboard_paste_cmd"`
+
+	// ArchiveCmd is a shell command moving old events out of the events file, offered by the size hint
+	ArchiveCmd string `json:"archive_cmd"`
+
+	// FocusMonth highlights the header of the month holding the selection and dims the others
+	FocusMonth bool `json:"focus_month"`
+
+	// PastDays dims the days before today in the calendar
+	PastDays PastDaysConfig `json:"past_days"`
+
+	// FreeEveningFrom is the time (HH:MM) from which a day without events counts as a free evening
+	FreeEveningFrom string `json:"free_evening_from"`
+
+	// TimeChecks warns when a new event starts at an unusual hour or lasts unusually long
+	TimeChecks TimeChecksConfig `json:"time_checks"`
+
+	// Workload warns about days with more events or scheduled time than wanted
+	Workload WorkloadConfig `json:"workload"`
+
+	// UIScale widens day cells and spaces out weeks for readability: 1 (normal) or 2
+	UIScale int `json:"ui_scale"`
+
+	// Decorations enables the month banner, month borders and separators
+	Decorations Decorations `json:"decorations"`
+
+	// FocusMode starts the calendar with only the month grids and the selected day's
+	// events, hiding the key legend, headers and decorations; Z toggles it
+	FocusMode bool `json:"focus_mode"`
+
+	// Holidays are noted when adding an event on one of them
+	Holidays []Holiday `json:"holidays"`
+
+	// HolidayCountry selects built-in public holidays by country code, e.g. "US"
+	HolidayCountry string `json:"holiday_country"`
+
+	// HolidayFile is an ICS file whose events are shown as holidays
+	HolidayFile string `json:"holiday_file"`
+
+	// Annotations are sources of short per-day notes, marked next to the day number
+	Annotations []AnnotationSource `json:"annotations"`
+
+	// WeekendNotes notes when an event is added on a Saturday or Sunday
+	WeekendNotes bool `json:"weekend_notes"`
+
+	// Retention purges old events on startup and daily in daemon mode
+	Retention RetentionConfig `json:"retention"`
+
+	// ListBackups prints the backups of the events files (-backups flag), and RestoreBackup
+	// replaces the events file with its backup of that number (-restore-backup flag)
+	ListBackups   bool `json:"-"`
+	RestoreBackup int  `json:"-"`
+
+	// RestorePurged moves events purged by the retention policy back from the trash (-restore-purged flag)
+	RestorePurged bool `json:"-"`
+
+	// Bell is the feedback for rejected actions: "visual", "audible" or "off"
+	Bell string `json:"bell"`
+
+	// ReminderBell rings the terminal bell when an event reminder comes due, in addition
+	// to the flash of the status bar
+	ReminderBell bool `json:"reminder_bell"`
+
+	// SearchOrder orders search results: "date", "nearest" to today or by "relevance"
+	SearchOrder string `json:"search_order"`
+
+	// QuickFilters are filter presets toggled with F1 to F8 in any view; F9 clears them
+	QuickFilters []QuickFilter `json:"quick_filters"`
+
+	// Goals are weekly or monthly event counts tracked in the statistics view
+	Goals []Goal `json:"goals"`
+
+	// GoalsHeader shows the progress of the goals on the top line of the calendar view
+	GoalsHeader bool `json:"goals_header"`
+
+	// Hosts override settings by hostname, so one synced configuration file works on several
+	// machines; the hostname may be given in full or without its domain
+	Hosts map[string]HostProfile `json:"hosts"`
+
+	// HostProfile is the key of Hosts applied at startup, empty when none matched
+	HostProfile string `json:"-"`
+
+	// DryRun shows what deletes, edits, imports and migrations would change without writing the events file (-dry-run flag)
+	DryRun bool `json:"dry_run"`
+
+	// Ephemeral keeps all data in memory and never writes to disk (-ephemeral flag), starting
+	// with the events of SeedFile when set (-seed flag)
+	Ephemeral bool   `json:"-"`
+	SeedFile  string `json:"-"`
+
+	// NormalizeEvents requests a one-shot normalization of the events file (-normalize flag)
+	NormalizeEvents bool `json:"-"`
+
+	// ImportFile is an events file to merge into the events file (-import flag)
+	ImportFile string `json:"-"`
+
+	// ImportTodoFile is a todo.txt file whose tasks with due dates are imported as events (-import-todo flag)
+	ImportTodoFile string `json:"-"`
+
+	// ReimportTodo updates previously imported todo.txt tasks instead of skipping them (-reimport flag)
+	ReimportTodo bool `json:"-"`
+
+	// ExportWeek is a date expression whose week is written as a printable planner page (-export-week flag),
+	// to ExportFile when set (-export-out flag)
+	ExportWeek string `json:"-"`
+	ExportFile string `json:"-"`
+
+	// ExportICSFile is an iCalendar file all events are written to (-export-ics flag), or only
+	// those matching the search query ExportQuery when set (-export-query flag)
+	ExportICSFile string `json:"-"`
+	ExportQuery   string `json:"-"`
+
+	// ShareFrom is a date expression whose range, ShareDays long, is served read-only over HTTP on
+	// ShareAddr for ShareMinutes (-share* flags); ShareBusy hides the descriptions and
+	// ShareAuth also requires an access token of read scope (see the token command)
+	ShareFrom    string `json:"-"`
+	ShareDays    int    `json:"-"`
+	ShareMinutes int    `json:"-"`
+	ShareAddr    string `json:"-"`
+	ShareBusy    bool   `json:"-"`
+	ShareAuth    bool   `json:"-"`
+
+	// RunDaemon starts the alarm daemon instead of the interactive calendar (-daemon flag)
+	RunDaemon bool `json:"-"`
+
+	// ShiftFrom and ShiftTo bound the dates (YYYY-MM-DD) whose event times ShiftBy moves (-shift-* flags)
+	ShiftFrom string        `json:"-"`
+	ShiftTo   string        `json:"-"`
+	ShiftBy   time.Duration `json:"-"`
+
+	// UndoShift reverts the most recent time shift (-undo-shift flag)
+	UndoShift bool `json:"-"`
+
+	// NoTUI selects the line-based interface instead of the full-screen one (-no-tui flag)
+	NoTUI bool `json:"-"`
+
+	// SafeMode starts with the custom theme, hooks and feeds disabled (-safe-mode flag)
+	SafeMode bool `json:"-"`
+
+	// AddArgs holds the arguments of the add command: event lines, or "-" to read them from standard input
+	AddArgs []string `json:"-"`
+
+	// TokenArgs holds the arguments of the token command, which creates, lists and revokes
+	// the access tokens of HTTP integrations
+	TokenArgs []string `json:"-"`
+
+	// CompletionShell is the shell of the completion command, whose script is printed instead of
+	// running the calendar
+	CompletionShell string `json:"-"`
 }
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		// Fallback to current directory if home directory is not accessible
-		homeDir = "."
-	}
-
-	configDir := filepath.Join(homeDir, ".ascii-calendar")
+	configDir := defaultConfigDir()
 
 	return &Config{
-		EventsFilePath: filepath.Join(configDir, "events.json"),
-		ConfigFilePath: filepath.Join(configDir, "configuration.json"),
-		WeekStartDay:   StartSunday, // Default to Sunday-first
-		UITheme:        DefaultTheme,
+		EventsFilePath:  filepath.Join(configDir, "events.json"),
+		ConfigFilePath:  filepath.Join(configDir, "configuration.json"),
+		WeekStartDay:    StartSunday, // Default to Sunday-first
+		DateFormat:      locale.C.DateFormat,
+		WeekdayNames:    append([]string(nil), locale.C.Weekdays[:]...),
+		MonthNames:      append([]string(nil), locale.C.Months[:]...),
+		UITheme:         DefaultTheme,
+		Normalization:   DefaultNormalization,
+		MaxEventsPerDay: 10,
+		Categories:      append([]EventCategory(nil), DefaultCategories...),
+		AlternateScreen: true,
+		TerminalTitle:   true,
+		WeekendNotes:    true,
+		FocusMonth:      true,
+		PastDays:        PastDaysConfig{Dim: PastDaysOff, Intensity: PastDimLight},
+		FreeEveningFrom: DefaultFreeEveningFrom,
+		TimeChecks:      DefaultTimeChecks,
+		Bell:            BellVisual,
+		Hyperlinks:      HyperlinksAuto,
+		UIScale:         1,
+		SearchOrder:     SearchOrderDate,
+		Retention:       RetentionConfig{TrashDays: 30},
+
+		SyncIntervalMinutes: 15,
+		DeleteUndoSeconds:   5,
+		BackupCount:         5,
+		LiveReload:          true,
+		EventsWarnCount:     5000,
+		EventsWarnBytes:     1 << 20,
 	}
 }
 
@@ -339,16 +1064,67 @@ func LoadConfig() (*Config, error) {
 	// Parse command line arguments
 	var configFileFlag string
 	var eventsFileFlag string
+	var dryRunFlag bool
+	var themeFlag string
 
 	flag.StringVar(&configFileFlag, "c", "", "Path to configuration file")
 	flag.StringVar(&eventsFileFlag, "f", "", "Path to events file")
+	flag.BoolVar(&config.NormalizeEvents, "normalize", false, "Normalize descriptions of all stored events and exit")
+	flag.StringVar(&config.ImportFile, "import", "", "Import events from a JSON, text or iCalendar (.ics) events file and exit")
+	flag.StringVar(&config.ImportTodoFile, "import-todo", "", "Import tasks with a due: date from a todo.txt file as events and exit")
+	flag.BoolVar(&config.ReimportTodo, "reimport", false, "With -import-todo, update previously imported tasks from the file")
+	flag.StringVar(&config.ExportWeek, "export-week", "", "Write the week holding this date (YYYY-MM-DD, today, +1w...) as a printable planner page and exit")
+	flag.StringVar(&config.ExportFile, "export-out", "", "With -export-week, the file to write (default: planner-<first day>.txt)")
+	flag.StringVar(&config.ExportICSFile, "export-ics", "", "Write the events to this iCalendar (.ics) file for Google Calendar, Outlook and others, then exit")
+	flag.StringVar(&config.ExportQuery, "export-query", "", "With -export-ics, export only the events matching this search query (e.g. cat:work)")
+	flag.StringVar(&config.ShareFrom, "share", "", "Serve the days from this date (YYYY-MM-DD, today, +1w...) read-only over HTTP on the local network, then exit")
+	flag.IntVar(&config.ShareDays, "share-days", 7, "With -share, the number of days shared")
+	flag.IntVar(&config.ShareMinutes, "share-minutes", 30, "With -share, the minutes after which the link stops working")
+	flag.StringVar(&config.ShareAddr, "share-addr", ":0", "With -share, the address to listen on; port 0 picks a free port")
+	flag.BoolVar(&config.ShareBusy, "share-busy", false, "With -share, show events as Busy without their descriptions")
+	flag.BoolVar(&config.ShareAuth, "share-auth", false, "With -share, also require an access token of read scope from an allowed address (see token create)")
+	flag.BoolVar(&config.RunDaemon, "daemon", false, "Run the alarm daemon that executes event commands at event time")
+	flag.StringVar(&config.ShiftFrom, "shift-from", "", "First date (YYYY-MM-DD) of events to time-shift with -shift-by")
+	flag.StringVar(&config.ShiftTo, "shift-to", "", "Last date (YYYY-MM-DD) of events to time-shift with -shift-by (default: -shift-from)")
+	flag.DurationVar(&config.ShiftBy, "shift-by", 0, "Shift event times by this amount (e.g. 1h, -30m) after a DST change or timezone move, then exit")
+	flag.BoolVar(&config.RestorePurged, "restore-purged", false, "Restore events purged by the retention policy from the trash and exit")
+	flag.BoolVar(&config.ListBackups, "backups", false, "List the backups of the events files and exit")
+	flag.IntVar(&config.RestoreBackup, "restore-backup", 0, "Replace the events file with its backup of this number (1 = latest) and exit")
+	flag.BoolVar(&config.UndoShift, "undo-shift", false, "Revert the most recent -shift-by and exit")
+	flag.BoolVar(&config.NoTUI, "no-tui", false, "Use a line-based interface with plain prompts instead of the full-screen calendar")
+	flag.BoolVar(&dryRunFlag, "dry-run", false, "Show what changes to events would be written without writing them")
+	flag.BoolVar(&config.Ephemeral, "ephemeral", false, "Keep events, bookmarks and statistics in memory only, never writing to disk (for demos)")
+	flag.StringVar(&config.SeedFile, "seed", "", "With -ephemeral, start with the events of this JSON or text events file")
+	flag.StringVar(&themeFlag, "theme", "", "Preview a theme for this session: default, dark, light, custom or a JSON theme file")
+	flag.BoolVar(&config.SafeMode, "safe-mode", false, "Start without the custom theme, sync, archive and clipboard commands, banner feeds and annotations")
+	flag.Usage = usage
 	flag.Parse()
 
+	// The positional commands are "add", creating events from its arguments or standard
+	// input, "token", managing access tokens, and "completion", which needs nothing else
+	if args := flag.Args(); len(args) > 0 {
+		switch {
+		case args[0] == "completion" && len(args) == 2:
+			config.CompletionShell = args[1]
+			return config, nil
+		case args[0] == "add" && len(args) > 1:
+			config.AddArgs = args[1:]
+		case args[0] == "token" && len(args) > 1:
+			config.TokenArgs = args[1:]
+		default:
+			return nil, fmt.Errorf("unknown command %q: expected add - | add \"<date> <HH:MM> <description>\" | token create|list|revoke | completion bash|zsh|fish", strings.Join(args, " "))
+		}
+	}
+
 	// Use command line config file path if provided
 	if configFileFlag != "" {
 		config.ConfigFilePath = configFileFlag
 	}
 
+	// The locale named in the file or by the environment picks the defaults of settings
+	// the file leaves out
+	config.applyLocale(config.fileLocale(os.Getenv))
+
 	// Try to load configuration file
 	if err := config.loadFromFile(); err != nil {
 		// If configuration file doesn't exist, that's okay - use defaults
@@ -357,12 +1133,45 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// The profile of this machine overrides the shared settings
+	if hostname, err := os.Hostname(); err == nil {
+		config.applyHostProfile(hostname)
+	}
+
 	// Command line arguments override configuration file
 	if eventsFileFlag != "" {
 		config.EventsFilePath = eventsFileFlag
 	}
+	if dryRunFlag {
+		config.DryRun = true
+	}
+
+	// Safe mode leaves out the custom theme, so a broken theme file cannot stop it
+	if !config.SafeMode {
+		if err := config.loadThemeFile(); err != nil {
+			return nil, err
+		}
+		if themeFlag != "" {
+			if err := config.previewTheme(themeFlag); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	// Ensure the directory exists
+	if config.SeedFile != "" && !config.Ephemeral {
+		return nil, fmt.Errorf("-seed requires -ephemeral")
+	}
+	if _, err := config.Location(); err != nil {
+		return nil, err
+	}
+	if err := config.validateCalendars(); err != nil {
+		return nil, err
+	}
+
+	// Ensure the directory exists; an ephemeral session never touches the disk
+	if config.Ephemeral {
+		return config, nil
+	}
 	if err := config.ensureDirectoryExists(); err != nil {
 		return nil, fmt.Errorf("failed to create configuration directory: %v", err)
 	}
@@ -370,6 +1179,98 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
+// fileLocale returns the locale named by the locale key of the configuration file, or
+// the locale of the environment when the file names none
+func (c *Config) fileLocale(getenv func(string) string) locale.Locale {
+	var settings struct {
+		Locale string `json:"locale"`
+	}
+	if data, err := os.ReadFile(c.ConfigFilePath); err == nil && json.Unmarshal(data, &settings) == nil && settings.Locale != "" {
+		return locale.Parse(settings.Locale)
+	}
+	return locale.FromEnv(getenv)
+}
+
+// applyLocale makes the first day of the week, weekday and month names and date format
+// of a locale the current settings
+func (c *Config) applyLocale(l locale.Locale) {
+	c.WeekStartDay = StartSunday
+	if l.FirstWeekday == time.Monday {
+		c.WeekStartDay = StartMonday
+	}
+	c.WeekdayNames = append([]string(nil), l.Weekdays[:]...)
+	c.MonthNames = append([]string(nil), l.Months[:]...)
+	c.DateFormat = l.DateFormat
+}
+
+// Location returns the zone of event times: Timezone, or the system zone when it is empty
+func (c *Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %v", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// Formatter returns the formatter of human-readable dates and times in exports, of the
+// date format, weekday names and time zone of the calendar
+func (c *Config) Formatter() locale.Formatter {
+	f := locale.Formatter{DateFormat: c.DateFormat, Weekdays: locale.C.Weekdays}
+	if len(c.WeekdayNames) == 7 {
+		copy(f.Weekdays[:], c.WeekdayNames)
+	}
+	if loc, err := c.Location(); err == nil {
+		f.Location = loc
+	}
+	return f
+}
+
+// applyHostProfile applies the profile of Hosts matching hostname, in full or without its
+// domain, ignoring case. It reports whether a profile matched.
+func (c *Config) applyHostProfile(hostname string) bool {
+	short, _, _ := strings.Cut(hostname, ".")
+	for _, name := range []string{hostname, short} {
+		for key, profile := range c.Hosts {
+			if !strings.EqualFold(key, name) {
+				continue
+			}
+			c.HostProfile = key
+			if profile.EventsFilePath != "" {
+				c.EventsFilePath = profile.EventsFilePath
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// HostProfileNote describes the host profile chosen at startup and the events file it
+// selects, or returns "" when no host profiles are configured
+func (c *Config) HostProfileNote() string {
+	if len(c.Hosts) == 0 {
+		return ""
+	}
+	if c.HostProfile == "" {
+		hostname, _ := os.Hostname()
+		return fmt.Sprintf("No host profile for %q; using events file %s", hostname, c.EventsFilePath)
+	}
+	return fmt.Sprintf("Using host profile %q: events file %s", c.HostProfile, c.EventsFilePath)
+}
+
+// usage prints the command line help, including the add and completion commands
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [options] [add - | add \"<date> <HH:MM> <description>\" | token create|list|revoke | completion bash|zsh|fish]\n\nOptions:\n", filepath.Base(os.Args[0]))
+	flag.PrintDefaults()
+}
+
+// FileFlags are the command line flags whose value is a file path, completed with file
+// names by the shell
+var FileFlags = []string{"c", "f", "import", "import-todo", "export-out", "export-ics", "seed", "theme"}
+
 // loadFromFile loads configuration from the configuration file
 func (c *Config) loadFromFile() error {
 	file, err := os.Open(c.ConfigFilePath)
@@ -389,15 +1290,102 @@ func (c *Config) SaveToFile() error {
 		return fmt.Errorf("failed to create configuration directory: %v", err)
 	}
 
-	file, err := os.Create(c.ConfigFilePath)
+	err := storage.WriteFileAtomic(c.ConfigFilePath, 0644, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ") // Pretty print JSON
+		return encoder.Encode(c)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create configuration file: %v", err)
+		return fmt.Errorf("failed to write configuration file: %v", err)
+	}
+	return nil
+}
+
+// SaveTheme records the theme in use in the configuration file, leaving the other
+// settings of the file as they are; the file is created when there is none
+func (c *Config) SaveTheme(name string) error {
+	settings, err := readSettings(c.ConfigFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %v", err)
+	}
+	value, _ := json.Marshal(name)
+	settings.set("theme", value)
+
+	if err := c.ensureDirectoryExists(); err != nil {
+		return fmt.Errorf("failed to create configuration directory: %v", err)
+	}
+	err = storage.WriteFileAtomic(c.ConfigFilePath, 0644, func(w io.Writer) error {
+		_, err := w.Write(settings.encode())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write configuration file: %v", err)
+	}
+	c.Theme = name
+	return nil
+}
+
+// settingsFile holds the top-level keys of a configuration file in their order, with
+// their values as written, so that one of them can be changed without touching the rest
+type settingsFile struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// readSettings reads the top-level keys of the configuration file at path; a missing
+// file has none
+func readSettings(path string) (*settingsFile, error) {
+	settings := &settingsFile{values: make(map[string]json.RawMessage)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
 	}
-	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ") // Pretty print JSON
-	return encoder.Encode(c)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("%s does not hold a JSON object", path)
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		settings.set(token.(string), value)
+	}
+	return settings, nil
+}
+
+// set changes the value of a key, adding the key at the end when it is new
+func (s *settingsFile) set(key string, value json.RawMessage) {
+	if _, ok := s.values[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.values[key] = value
+}
+
+// encode writes the settings as an indented JSON object, keeping the order of the keys
+func (s *settingsFile) encode() []byte {
+	var out bytes.Buffer
+	out.WriteString("{")
+	for i, key := range s.keys {
+		if i > 0 {
+			out.WriteString(",")
+		}
+		name, _ := json.Marshal(key)
+		fmt.Fprintf(&out, "\n  %s: ", name)
+		if err := json.Indent(&out, s.values[key], "  ", "  "); err != nil {
+			out.Write(s.values[key])
+		}
+	}
+	out.WriteString("\n}\n")
+	return out.Bytes()
 }
 
 // ensureDirectoryExists creates the configuration directory if it doesn't exist
@@ -419,6 +1407,154 @@ func (c *Config) GetEventsFilePath() string {
 	return c.EventsFilePath
 }
 
+// GetCategoryByHotkey returns the category bound to a hotkey such as "1"
+func (c *Config) GetCategoryByHotkey(hotkey string) (EventCategory, bool) {
+	for _, category := range c.Categories {
+		if category.Hotkey == hotkey {
+			return category, true
+		}
+	}
+	return EventCategory{}, false
+}
+
+// validateCalendars checks that the calendars have distinct names and files, and that
+// at most one of them names the main events file
+func (c *Config) validateCalendars() error {
+	names := map[string]bool{}
+	files := map[string]bool{}
+	for _, calendar := range c.Calendars {
+		name := strings.ToLower(calendar.Name)
+		if name == "" {
+			return fmt.Errorf("invalid calendar: a name is required")
+		}
+		if names[name] {
+			return fmt.Errorf("invalid calendar %q: the name is used twice", calendar.Name)
+		}
+		names[name] = true
+
+		if calendar.File != "" && calendar.File == c.EventsFilePath {
+			return fmt.Errorf("invalid calendar %q: leave out the file to name the main events file", calendar.Name)
+		}
+		file := calendar.File
+		if file == "" {
+			file = c.EventsFilePath
+		}
+		if files[file] {
+			return fmt.Errorf("invalid calendar %q: its events file %s belongs to another calendar", calendar.Name, file)
+		}
+		files[file] = true
+	}
+	return nil
+}
+
+// CalendarNames returns the names of the calendars, the main events file first
+func (c *Config) CalendarNames() []string {
+	main := MainCalendarName
+	var names []string
+	for _, calendar := range c.Calendars {
+		if calendar.File == "" {
+			main = calendar.Name
+		} else {
+			names = append(names, calendar.Name)
+		}
+	}
+	return append([]string{main}, names...)
+}
+
+// GetCalendar returns the calendar holding events whose Calendar is name; "" stands for
+// the main events file, found when a calendar without a file names it
+func (c *Config) GetCalendar(name string) (EventCalendar, bool) {
+	for _, calendar := range c.Calendars {
+		main := calendar.File == ""
+		if main && name == "" || !main && name != "" && strings.EqualFold(calendar.Name, name) {
+			return calendar, true
+		}
+	}
+	return EventCalendar{}, false
+}
+
+// CalendarKey returns the Calendar of the events in the calendar called name, "" for the
+// main events file, and whether there is such a calendar
+func (c *Config) CalendarKey(name string) (string, bool) {
+	if strings.EqualFold(name, c.CalendarNames()[0]) {
+		return "", true
+	}
+	for _, calendar := range c.Calendars {
+		if calendar.File != "" && strings.EqualFold(calendar.Name, name) {
+			return calendar.Name, true
+		}
+	}
+	return "", false
+}
+
+// CalendarFilePath returns the events file of the events whose Calendar is name
+func (c *Config) CalendarFilePath(name string) string {
+	if calendar, ok := c.GetCalendar(name); ok && calendar.File != "" {
+		return calendar.File
+	}
+	return c.EventsFilePath
+}
+
+// GetQuickFilter returns the quick filter bound to a function key such as "F1"
+func (c *Config) GetQuickFilter(key string) (QuickFilter, bool) {
+	for _, filter := range c.QuickFilters {
+		if strings.EqualFold(filter.Key, key) {
+			return filter, true
+		}
+	}
+	return QuickFilter{}, false
+}
+
+// GetCategory returns the category with the given name
+func (c *Config) GetCategory(name string) (EventCategory, bool) {
+	for _, category := range c.Categories {
+		if category.Name == name {
+			return category, true
+		}
+	}
+	return EventCategory{}, false
+}
+
+// ApplySafeMode turns off the parts of the configuration most likely to break a
+// session: the custom theme, the sync, archive and clipboard commands, CalDAV sync, the
+// banner feeds and the annotation sources
+func (c *Config) ApplySafeMode() {
+	c.SafeMode = true
+	c.UITheme = DefaultTheme
+	c.Theme = ""
+	c.SyncPullCmd = ""
+	c.SyncPushCmd = ""
+	c.CalDAV = CalDAVConfig{}
+	c.ArchiveCmd = ""
+	c.ClipboardCmd = ""
+	c.ClipboardPasteCmd = ""
+	c.StartupBanner = nil
+	c.Annotations = nil
+}
+
+// GetHoliday returns the configured holiday falling on date
+func (c *Config) GetHoliday(date time.Time) (Holiday, bool) {
+	for _, holiday := range c.Holidays {
+		if holiday.Date == date.Format("2006-01-02") || holiday.Date == date.Format("01-02") {
+			return holiday, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// GetDataDir returns the directory holding the events file and other application data
+func (c *Config) GetDataDir() string {
+	return filepath.Dir(c.EventsFilePath)
+}
+
+// GetEmergencyFilePath returns the file a copy of unsaved events is written to
+func (c *Config) GetEmergencyFilePath() string {
+	if c.EmergencyFilePath != "" {
+		return c.EmergencyFilePath
+	}
+	return filepath.Join(os.TempDir(), "ascii-calendar-unsaved.json")
+}
+
 // GetConfigFilePath returns the full path to the configuration file
 func (c *Config) GetConfigFilePath() string {
 	return c.ConfigFilePath
@@ -4,8 +4,14 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"go-ascii-calendar/locale"
+
+	"github.com/nsf/termbox-go"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -111,6 +117,146 @@ func TestConfig_SaveToFile(t *testing.T) {
 	}
 }
 
+func TestConfig_SaveTheme(t *testing.T) {
+	dir := t.TempDir()
+	config := &Config{
+		EventsFilePath: filepath.Join(dir, "events.json"),
+		ConfigFilePath: filepath.Join(dir, "config.json"),
+	}
+
+	// Without a file one is created holding just the theme
+	if err := config.SaveTheme("dark"); err != nil {
+		t.Fatalf("SaveTheme() failed: %v", err)
+	}
+	if data, _ := os.ReadFile(config.ConfigFilePath); string(data) != "{\n  \"theme\": \"dark\"\n}\n" {
+		t.Errorf("Config file = %q, want just the theme", data)
+	}
+
+	// The other settings keep their order and values
+	original := "{\"week_start_day\": 1, \"ui_theme\": {\"today_fg\": \"red\"}, \"theme\": \"dark\", \"zone\": \"UTC\"}"
+	if err := os.WriteFile(config.ConfigFilePath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveTheme("custom"); err != nil {
+		t.Fatalf("SaveTheme() failed: %v", err)
+	}
+	data, _ := os.ReadFile(config.ConfigFilePath)
+	want := "{\n  \"week_start_day\": 1,\n  \"ui_theme\": {\n    \"today_fg\": \"red\"\n  },\n  \"theme\": \"custom\",\n  \"zone\": \"UTC\"\n}\n"
+	if string(data) != want {
+		t.Errorf("Config file = %q, want %q", data, want)
+	}
+	if config.Theme != "custom" {
+		t.Errorf("Theme = %q, want the saved theme", config.Theme)
+	}
+
+	// A file that is not a JSON object is left alone
+	if err := os.WriteFile(config.ConfigFilePath, []byte("[1, 2]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveTheme("light"); err == nil {
+		t.Error("SaveTheme() should fail for a file that is not a JSON object")
+	}
+	if data, _ := os.ReadFile(config.ConfigFilePath); string(data) != "[1, 2]" {
+		t.Errorf("Config file = %q, want it unchanged", data)
+	}
+}
+
+func TestConfig_CurrentTheme(t *testing.T) {
+	config := DefaultConfig()
+	if choices := config.ThemeChoices(); !reflect.DeepEqual(choices, ThemeNames) {
+		t.Errorf("ThemeChoices() = %v without a custom theme, want %v", choices, ThemeNames)
+	}
+
+	config.UITheme.TodayFg = "magenta|bold"
+	if choices := config.ThemeChoices(); choices[len(choices)-1] != ThemeCustom {
+		t.Errorf("ThemeChoices() = %v, want the custom theme last", choices)
+	}
+
+	tests := []struct {
+		theme string
+		want  string // TodayFg of the theme in use
+	}{
+		{"", "magenta|bold"},
+		{"custom", "magenta|bold"},
+		{"Dark", DarkTheme.TodayFg},
+		{"light", LightTheme.TodayFg},
+		{"unknown", "magenta|bold"},
+	}
+	for _, tt := range tests {
+		config.Theme = tt.theme
+		if got := config.CurrentTheme().TodayFg; got != tt.want {
+			t.Errorf("CurrentTheme() with theme %q has today_fg %q, want %q", tt.theme, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_loadThemeFile(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.ConfigFilePath = filepath.Join(dir, "config.json")
+
+	// Without a theme file ui_theme stays
+	config.UITheme.TodayFg = "cyan"
+	if err := config.loadThemeFile(); err != nil || config.UITheme.TodayFg != "cyan" {
+		t.Fatalf("loadThemeFile() without a file = %v, today_fg %q; want ui_theme kept", err, config.UITheme.TodayFg)
+	}
+
+	// A relative path is found next to the configuration file, and fields it leaves out
+	// take the default colors
+	theme := `{"palette": {"accent": "#ff8700|bold"}, "today_fg": "accent", "error_fg": "color196"}`
+	if err := os.WriteFile(filepath.Join(dir, "sunset.json"), []byte(theme), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config.UIThemeFile = "sunset.json"
+	if err := config.loadThemeFile(); err != nil {
+		t.Fatalf("loadThemeFile() failed: %v", err)
+	}
+	if config.UITheme.TodayFg != "accent" || config.UITheme.ErrorFg != "color196" || config.UITheme.SelectedBg != DefaultTheme.SelectedBg {
+		t.Errorf("Theme from file = %+v, want its colors over the default theme", config.UITheme)
+	}
+
+	// Invalid colors and missing files stop the start with an error naming the file
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"today_fg": "chartreuse"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"broken.json", filepath.Join(dir, "missing.json")} {
+		config.UIThemeFile = file
+		if err := config.loadThemeFile(); err == nil {
+			t.Errorf("loadThemeFile() with %s should fail", file)
+		}
+	}
+}
+
+func TestConfig_previewTheme(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "mono.json")
+	if err := os.WriteFile(file, []byte(`{"today_fg": "default|reverse"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value     string
+		wantTheme string
+		wantToday string
+		wantErr   bool
+	}{
+		{"Dark", "dark", DefaultTheme.TodayFg, false},
+		{"custom", "custom", DefaultTheme.TodayFg, false},
+		{file, ThemeCustom, "default|reverse", false},
+		{"neon", "", DefaultTheme.TodayFg, true},
+		{filepath.Join(dir, "missing.json"), "", DefaultTheme.TodayFg, true},
+	}
+
+	for _, tt := range tests {
+		config := DefaultConfig()
+		err := config.previewTheme(tt.value)
+		if (err != nil) != tt.wantErr || config.Theme != tt.wantTheme || config.UITheme.TodayFg != tt.wantToday {
+			t.Errorf("previewTheme(%q) = %v with theme %q and today_fg %q; want theme %q, today_fg %q, error %v",
+				tt.value, err, config.Theme, config.UITheme.TodayFg, tt.wantTheme, tt.wantToday, tt.wantErr)
+		}
+	}
+}
+
 func TestConfig_SaveToFile_InvalidPath(t *testing.T) {
 	// Test save to invalid path (should fail)
 	config := &Config{
@@ -282,3 +428,398 @@ func TestLoadConfig_WithConfigFile(t *testing.T) {
 	// The LoadConfig function uses global flags which can't be easily reset in tests
 	t.Skip("Skipping LoadConfig config file test due to global flag limitations")
 }
+
+func TestConfig_GetCategoryByHotkey(t *testing.T) {
+	config := DefaultConfig()
+
+	category, ok := config.GetCategoryByHotkey("1")
+	if !ok || category.Name != "work" {
+		t.Errorf("GetCategoryByHotkey(\"1\") = %v, %v; want work", category, ok)
+	}
+
+	if _, ok := config.GetCategoryByHotkey("9"); ok {
+		t.Error("GetCategoryByHotkey(\"9\") should not match a default category")
+	}
+
+	if _, ok := config.GetCategory("travel"); !ok {
+		t.Error("GetCategory(\"travel\") should find a default category")
+	}
+
+	// Default categories must use valid colors
+	for _, category := range config.Categories {
+		if _, err := ParseColor(category.Color); err != nil {
+			t.Errorf("Category %s has invalid color %q: %v", category.Name, category.Color, err)
+		}
+	}
+}
+
+func TestConfig_GetQuickFilter(t *testing.T) {
+	config := DefaultConfig()
+	config.QuickFilters = []QuickFilter{{Key: "F1", Name: "work", Category: "work"}}
+
+	filter, ok := config.GetQuickFilter("f1")
+	if !ok || filter.Name != "work" {
+		t.Errorf("GetQuickFilter(\"f1\") = %v, %v; want work", filter, ok)
+	}
+
+	if _, ok := config.GetQuickFilter("F2"); ok {
+		t.Error("GetQuickFilter(\"F2\") should not match an unbound key")
+	}
+}
+
+func TestConfig_Calendars(t *testing.T) {
+	config := &Config{EventsFilePath: "events.json"}
+	if names := config.CalendarNames(); len(names) != 1 || names[0] != MainCalendarName {
+		t.Errorf("CalendarNames() without calendars = %v, want only %s", names, MainCalendarName)
+	}
+
+	config.Calendars = []EventCalendar{{Name: "work", File: "work.json", Color: "cyan"}, {Name: "personal", Color: "green"}}
+	if err := config.validateCalendars(); err != nil {
+		t.Fatalf("validateCalendars() failed: %v", err)
+	}
+	if names := config.CalendarNames(); len(names) != 2 || names[0] != "personal" || names[1] != "work" {
+		t.Errorf("CalendarNames() = %v, want personal first", names)
+	}
+	if key, ok := config.CalendarKey("Personal"); !ok || key != "" {
+		t.Errorf("CalendarKey(Personal) = %q, %v; want the main events file", key, ok)
+	}
+	if key, ok := config.CalendarKey("WORK"); !ok || key != "work" {
+		t.Errorf("CalendarKey(WORK) = %q, %v; want work", key, ok)
+	}
+	if _, ok := config.CalendarKey("family"); ok {
+		t.Error("CalendarKey(family) should not match")
+	}
+	if calendar, ok := config.GetCalendar(""); !ok || calendar.Color != "green" {
+		t.Errorf("GetCalendar(\"\") = %v, %v; want personal", calendar, ok)
+	}
+	if path := config.CalendarFilePath("work"); path != "work.json" {
+		t.Errorf("CalendarFilePath(work) = %q, want work.json", path)
+	}
+	if path := config.CalendarFilePath(""); path != "events.json" {
+		t.Errorf("CalendarFilePath(\"\") = %q, want events.json", path)
+	}
+
+	for _, calendars := range [][]EventCalendar{
+		{{Name: ""}},
+		{{Name: "work", File: "a.json"}, {Name: "Work", File: "b.json"}},
+		{{Name: "home"}, {Name: "personal"}},
+		{{Name: "home", File: "events.json"}},
+	} {
+		config.Calendars = calendars
+		if err := config.validateCalendars(); err == nil {
+			t.Errorf("validateCalendars(%v) should have failed", calendars)
+		}
+	}
+}
+
+func TestConfig_ApplySafeMode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.UITheme = LightTheme
+	cfg.SyncPullCmd = "git pull"
+	cfg.SyncPushCmd = "git push"
+	cfg.CalDAV = CalDAVConfig{URL: "https://dav.example.com/cal/", Username: "me"}
+	cfg.ArchiveCmd = "archive.sh"
+	cfg.ClipboardCmd = "pbcopy"
+	cfg.ClipboardPasteCmd = "pbpaste"
+	cfg.StartupBanner = []BannerWidget{{Type: "weather", Command: "curl wttr.in"}}
+	cfg.Annotations = []AnnotationSource{{Type: "rota", File: "oncall.txt"}}
+	cfg.WeekStartDay = StartMonday
+
+	cfg.ApplySafeMode()
+
+	if !cfg.SafeMode {
+		t.Error("SafeMode should be set")
+	}
+	if !reflect.DeepEqual(cfg.UITheme, DefaultTheme) {
+		t.Error("Safe mode should restore the default theme")
+	}
+	if cfg.SyncPullCmd != "" || cfg.SyncPushCmd != "" || cfg.ArchiveCmd != "" || cfg.ClipboardCmd != "" || cfg.ClipboardPasteCmd != "" {
+		t.Error("Safe mode should disable sync, archive and clipboard commands")
+	}
+	if cfg.CalDAV.URL != "" {
+		t.Error("Safe mode should disable CalDAV sync")
+	}
+	if len(cfg.StartupBanner) != 0 {
+		t.Error("Safe mode should disable the startup banner")
+	}
+	if len(cfg.Annotations) != 0 {
+		t.Error("Safe mode should disable annotation sources")
+	}
+	if cfg.WeekStartDay != StartMonday {
+		t.Error("Safe mode should keep unrelated settings")
+	}
+}
+
+func TestConfig_GetHoliday(t *testing.T) {
+	config := &Config{Holidays: []Holiday{
+		{Name: "Labor Day", Date: "2025-09-01"},
+		{Name: "Christmas Day", Date: "12-25"},
+	}}
+
+	tests := []struct {
+		date time.Time
+		want string
+	}{
+		{time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local), "Labor Day"},
+		{time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local), ""},
+		{time.Date(2025, 12, 25, 0, 0, 0, 0, time.Local), "Christmas Day"},
+		{time.Date(2031, 12, 25, 0, 0, 0, 0, time.Local), "Christmas Day"},
+		{time.Date(2025, 12, 24, 0, 0, 0, 0, time.Local), ""},
+	}
+
+	for _, tt := range tests {
+		holiday, ok := config.GetHoliday(tt.date)
+		if holiday.Name != tt.want || ok != (tt.want != "") {
+			t.Errorf("GetHoliday(%s) = %v, %v; want %q", tt.date.Format("2006-01-02"), holiday, ok, tt.want)
+		}
+	}
+}
+
+func TestDefaultConfig_PathsShareDirectory(t *testing.T) {
+	config := DefaultConfig()
+
+	// Events, configuration and state all live in the platform data directory
+	if filepath.Dir(config.EventsFilePath) != filepath.Dir(config.ConfigFilePath) {
+		t.Errorf("Events file %s and config file %s should share a directory", config.EventsFilePath, config.ConfigFilePath)
+	}
+	if config.GetDataDir() != defaultConfigDir() {
+		t.Errorf("GetDataDir() = %s, want %s", config.GetDataDir(), defaultConfigDir())
+	}
+}
+
+func TestConfig_applyHostProfile(t *testing.T) {
+	tests := []struct {
+		hostname string
+		profile  string
+		path     string
+	}{
+		{"laptop", "laptop", "/home/me/events.json"},
+		{"Laptop", "laptop", "/home/me/events.json"},
+		{"server.example.com", "server", "/srv/calendar/events.json"},
+		{"desktop", "", "/shared/events.json"},
+	}
+
+	for _, tt := range tests {
+		config := &Config{
+			EventsFilePath: "/shared/events.json",
+			Hosts: map[string]HostProfile{
+				"laptop": {EventsFilePath: "/home/me/events.json"},
+				"server": {EventsFilePath: "/srv/calendar/events.json"},
+			},
+		}
+		matched := config.applyHostProfile(tt.hostname)
+		if matched != (tt.profile != "") || config.HostProfile != tt.profile || config.EventsFilePath != tt.path {
+			t.Errorf("applyHostProfile(%q) = %v with profile %q and path %s; want profile %q and path %s",
+				tt.hostname, matched, config.HostProfile, config.EventsFilePath, tt.profile, tt.path)
+		}
+	}
+}
+
+func TestConfig_HostProfileNote(t *testing.T) {
+	config := &Config{EventsFilePath: "/shared/events.json"}
+	if note := config.HostProfileNote(); note != "" {
+		t.Errorf("Without host profiles the note should be empty, got %q", note)
+	}
+
+	config.Hosts = map[string]HostProfile{"laptop": {EventsFilePath: "/home/me/events.json"}}
+	config.applyHostProfile("laptop")
+	if note := config.HostProfileNote(); !strings.Contains(note, `"laptop"`) || !strings.Contains(note, "/home/me/events.json") {
+		t.Errorf("HostProfileNote() = %q, want the profile and its events file", note)
+	}
+}
+
+func TestConfig_applyLocale(t *testing.T) {
+	config := DefaultConfig()
+	config.applyLocale(locale.Parse("de_DE.UTF-8"))
+	if config.WeekStartDay != StartMonday || config.DateFormat != "DD.MM.YYYY" || config.WeekdayNames[1] != "Mo" || config.WeekdayNames[0] != "So" {
+		t.Errorf("applyLocale(de_DE) = %d, %q, %v; want Monday first, DD.MM.YYYY and German weekdays",
+			config.WeekStartDay, config.DateFormat, config.WeekdayNames)
+	}
+	if config.MonthNames[2] != "März" {
+		t.Errorf("applyLocale(de_DE) month names = %v, want German ones", config.MonthNames)
+	}
+
+	// Settings in the configuration file win over the locale
+	tempDir := t.TempDir()
+	config.ConfigFilePath = filepath.Join(tempDir, "configuration.json")
+	if err := os.WriteFile(config.ConfigFilePath, []byte(`{"week_start_day": 0}`), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+	if err := config.loadFromFile(); err != nil {
+		t.Fatalf("loadFromFile() failed: %v", err)
+	}
+	if config.WeekStartDay != StartSunday || config.DateFormat != "DD.MM.YYYY" {
+		t.Errorf("After loading the file = %d, %q; want Sunday first and the locale's DD.MM.YYYY", config.WeekStartDay, config.DateFormat)
+	}
+}
+
+func TestConfig_fileLocale(t *testing.T) {
+	config := DefaultConfig()
+	config.ConfigFilePath = filepath.Join(t.TempDir(), "configuration.json")
+	getenv := func(name string) string {
+		if name == "LANG" {
+			return "de_DE.UTF-8"
+		}
+		return ""
+	}
+
+	// Without a configuration file, or without the key, the environment names the locale
+	if got := config.fileLocale(getenv).Name; got != "de_DE" {
+		t.Errorf("fileLocale() without a file = %q, want the environment's de_DE", got)
+	}
+	if err := os.WriteFile(config.ConfigFilePath, []byte(`{"week_start_day": 0}`), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+	if got := config.fileLocale(getenv).Name; got != "de_DE" {
+		t.Errorf("fileLocale() without the key = %q, want the environment's de_DE", got)
+	}
+
+	// The locale key wins over the environment
+	if err := os.WriteFile(config.ConfigFilePath, []byte(`{"locale": "fr_FR.UTF-8"}`), 0644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+	l := config.fileLocale(getenv)
+	if l.Name != "fr_FR" || l.Months[7] != "Août" {
+		t.Errorf("fileLocale() = %q with %q, want fr_FR with French month names", l.Name, l.Months[7])
+	}
+}
+
+func TestConfig_Formatter(t *testing.T) {
+	config := DefaultConfig()
+	config.applyLocale(locale.Parse("de_DE.UTF-8"))
+	config.Timezone = "Europe/Berlin"
+	f := config.Formatter()
+	date := time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC)
+	if got := f.Day(date); got != "Mo 11.08.2025" {
+		t.Errorf("Formatter().Day() = %q, want Mo 11.08.2025", got)
+	}
+	if f.In().String() != "Europe/Berlin" {
+		t.Errorf("Formatter().In() = %v, want Europe/Berlin", f.In())
+	}
+
+	config.Timezone = "Mars/Olympus"
+	if _, err := config.Location(); err == nil {
+		t.Error("Location() accepted an unknown time zone")
+	}
+	if f := config.Formatter(); f.In() != time.Local {
+		t.Errorf("Formatter().In() = %v for an unknown zone, want the local zone", f.In())
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		color   string
+		want    termbox.Attribute
+		wantErr bool
+	}{
+		{"", termbox.ColorDefault, false},
+		{"magenta|bold", termbox.ColorMagenta | termbox.AttrBold, false},
+		{"bright_cyan", termbox.ColorCyan | termbox.AttrBold, false},
+		{"color0", termbox.ColorBlack, false},
+		{"color208|underline", termbox.Attribute(208+1) | termbox.AttrUnderline, false},
+		{"color255", termbox.Attribute(255 + 1), false},
+		{"#ff8700", termbox.Attribute(208 + 1), false},
+		{"#FF8700|bold", termbox.Attribute(208+1) | termbox.AttrBold, false},
+		{"#303030", termbox.Attribute(236 + 1), false},
+		{"#000000", termbox.Attribute(16 + 1), false},
+		{"color256", termbox.ColorDefault, true},
+		{"color08", termbox.ColorDefault, true},
+		{"color", termbox.ColorDefault, true},
+		{"#ff87", termbox.ColorDefault, true},
+		{"#gg8700", termbox.ColorDefault, true},
+		{"orange", termbox.ColorDefault, true},
+		{"red|blink", termbox.ColorDefault, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseColor(tt.color)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseColor(%q) = %v, %v; want %v, error %v", tt.color, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestColorRGB(t *testing.T) {
+	tests := []struct {
+		index   int
+		r, g, b int
+	}{
+		{16, 0, 0, 0},
+		{208, 255, 135, 0},
+		{231, 255, 255, 255},
+		{232, 8, 8, 8},
+		{255, 238, 238, 238},
+	}
+
+	for _, tt := range tests {
+		if r, g, b := ColorRGB(tt.index); r != tt.r || g != tt.g || b != tt.b {
+			t.Errorf("ColorRGB(%d) = %d, %d, %d; want %d, %d, %d", tt.index, r, g, b, tt.r, tt.g, tt.b)
+		}
+	}
+}
+
+func TestColorTheme_ResolveColor(t *testing.T) {
+	theme := ColorTheme{Palette: map[string]string{
+		"accent":    "cyan|bold",
+		"highlight": "accent|underline",
+		"ping":      "pong",
+		"pong":      "ping|bold",
+	}}
+
+	tests := []struct {
+		color   string
+		want    string
+		wantErr bool
+	}{
+		{"accent", "cyan|bold", false},
+		{"accent|reverse", "cyan|bold|reverse", false},
+		{"highlight", "cyan|bold|underline", false},
+		{"red|bold", "red|bold", false},
+		{"color208|bold", "color208|bold", false},
+		{"#ff8700", "#ff8700", false},
+		{"unknown", "unknown", false},
+		{"", "", false},
+		{"ping", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := theme.ResolveColor(tt.color)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ResolveColor(%q) = %q, %v; want %q, error %v", tt.color, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateColorTheme_Palette(t *testing.T) {
+	tests := []struct {
+		name    string
+		palette map[string]string
+		todayFg string
+		wantErr string
+	}{
+		{"palette color", map[string]string{"accent": "yellow|bold"}, "accent", ""},
+		{"unknown palette name", map[string]string{"accent": "yellow|bold"}, "acent", "unknown color"},
+		{"invalid palette color", map[string]string{"accent": "chartreuse"}, "yellow", "invalid palette color 'accent'"},
+		{"cycle", map[string]string{"a": "b", "b": "c", "c": "a|bold"}, "yellow", "palette cycle: a -> b -> c -> a"},
+		{"shadowed color", map[string]string{"red": "blue"}, "yellow", "invalid palette name 'red'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme := DefaultTheme
+			theme.Palette = tt.palette
+			theme.TodayFg = tt.todayFg
+
+			err := ValidateColorTheme(&theme)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateColorTheme() = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateColorTheme() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultConfigDir returns ~/.ascii-calendar, or ./.ascii-calendar when the home directory is unknown
+func defaultConfigDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		// Fallback to current directory if home directory is not accessible
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".ascii-calendar")
+}
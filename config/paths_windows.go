@@ -0,0 +1,26 @@
+//go:build windows
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultConfigDir returns %APPDATA%\ascii-calendar. An existing ~/.ascii-calendar
+// directory keeps being used so earlier installations find their events.
+func defaultConfigDir() string {
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		legacyDir := filepath.Join(homeDir, ".ascii-calendar")
+		if info, err := os.Stat(legacyDir); err == nil && info.IsDir() {
+			return legacyDir
+		}
+	}
+
+	appData, err := os.UserConfigDir() // %APPDATA% on Windows
+	if err != nil {
+		// Fallback to current directory if no profile directory is accessible
+		appData = "."
+	}
+	return filepath.Join(appData, "ascii-calendar")
+}
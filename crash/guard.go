@@ -0,0 +1,122 @@
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go-ascii-calendar/storage"
+)
+
+// FileName is the name of the session sentinel file inside the data directory
+const FileName = "session.lock"
+
+// SafeModeAfter is the number of consecutive crashes after which safe mode starts
+const SafeModeAfter = 2
+
+// record is the content of the sentinel file while a session is running
+type record struct {
+	Crashes   int    `json:"crashes"`   // Consecutive abnormal exits before this session
+	Subsystem string `json:"subsystem"` // Part of the application that was last active
+}
+
+// Guard detects abnormal exits with a sentinel file that exists while a session
+// runs and is removed on a clean exit. A sentinel left behind at startup means
+// the previous session crashed or was killed.
+type Guard struct {
+	path    string
+	started bool
+	current record
+	last    string // Subsystem active when the previous session crashed
+}
+
+// NewGuard creates a guard for the sentinel file in the given data directory.
+// An empty data directory gives a guard that never detects crashes.
+func NewGuard(dataDir string) *Guard {
+	path := ""
+	if dataDir != "" {
+		path = filepath.Join(dataDir, FileName)
+	}
+	return &Guard{path: path}
+}
+
+// Start reads the sentinel of the previous session and writes the one for this session
+func (g *Guard) Start() error {
+	if g.path == "" {
+		return nil
+	}
+
+	g.current = record{Subsystem: "startup"}
+	if data, err := os.ReadFile(g.path); err == nil {
+		var previous record
+		if err := json.Unmarshal(data, &previous); err != nil {
+			previous = record{Subsystem: "unknown"}
+		}
+		g.current.Crashes = previous.Crashes + 1
+		g.last = previous.Subsystem
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read session file: %v", err)
+	}
+
+	g.started = true
+	return g.write()
+}
+
+// Enter records the subsystem that is now active, so a crash can be attributed to it
+func (g *Guard) Enter(subsystem string) {
+	if !g.started || g.current.Subsystem == subsystem {
+		return
+	}
+	g.current.Subsystem = subsystem
+	// Failing to update the sentinel only makes the crash report less precise
+	_ = g.write()
+}
+
+// Stop removes the sentinel on a clean exit, resetting the crash count
+func (g *Guard) Stop() error {
+	if !g.started {
+		return nil
+	}
+	g.started = false
+	if err := os.Remove(g.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove session file: %v", err)
+	}
+	return nil
+}
+
+// Crashes returns the number of consecutive crashes before this session
+func (g *Guard) Crashes() int {
+	return g.current.Crashes
+}
+
+// LastSubsystem returns the subsystem that was active when the previous session crashed
+func (g *Guard) LastSubsystem() string {
+	return g.last
+}
+
+// SafeMode reports whether enough sessions crashed in a row to start in safe mode
+func (g *Guard) SafeMode() bool {
+	return g.current.Crashes >= SafeModeAfter
+}
+
+// write saves the sentinel for the running session
+func (g *Guard) write() error {
+	if err := os.MkdirAll(filepath.Dir(g.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	data, err := json.Marshal(g.current)
+	if err != nil {
+		return fmt.Errorf("failed to encode session file: %v", err)
+	}
+	err = storage.WriteFileAtomic(g.path, 0644, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write session file: %v", err)
+	}
+	return nil
+}
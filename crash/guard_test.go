@@ -0,0 +1,78 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGuard_ConsecutiveCrashes(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "crash_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// First session crashes while syncing: the sentinel is left behind
+	first := NewGuard(tempDir)
+	if err := first.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	if first.Crashes() != 0 || first.SafeMode() {
+		t.Errorf("Fresh start: crashes = %d, safe mode = %v", first.Crashes(), first.SafeMode())
+	}
+	first.Enter("sync")
+
+	second := NewGuard(tempDir)
+	if err := second.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	if second.Crashes() != 1 || second.SafeMode() {
+		t.Errorf("After one crash: crashes = %d, safe mode = %v", second.Crashes(), second.SafeMode())
+	}
+	if second.LastSubsystem() != "sync" {
+		t.Errorf("LastSubsystem() = %q, want %q", second.LastSubsystem(), "sync")
+	}
+	second.Enter("banner")
+
+	third := NewGuard(tempDir)
+	if err := third.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	if third.Crashes() != 2 || !third.SafeMode() {
+		t.Errorf("After two crashes: crashes = %d, safe mode = %v", third.Crashes(), third.SafeMode())
+	}
+	if third.LastSubsystem() != "banner" {
+		t.Errorf("LastSubsystem() = %q, want %q", third.LastSubsystem(), "banner")
+	}
+
+	// A clean exit resets the count
+	if err := third.Stop(); err != nil {
+		t.Fatalf("Stop() failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, FileName)); !os.IsNotExist(err) {
+		t.Errorf("Sentinel should be removed on a clean exit, stat error: %v", err)
+	}
+
+	fourth := NewGuard(tempDir)
+	if err := fourth.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	if fourth.Crashes() != 0 || fourth.SafeMode() {
+		t.Errorf("After a clean exit: crashes = %d, safe mode = %v", fourth.Crashes(), fourth.SafeMode())
+	}
+}
+
+func TestGuard_WithoutDataDir(t *testing.T) {
+	guard := NewGuard("")
+	if err := guard.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	guard.Enter("sync")
+	if guard.SafeMode() {
+		t.Error("A guard without data directory should never enter safe mode")
+	}
+	if err := guard.Stop(); err != nil {
+		t.Errorf("Stop() failed: %v", err)
+	}
+}
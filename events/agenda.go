@@ -0,0 +1,30 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/calendar"
+)
+
+// Agenda formats the visible events from start through end as plain text to paste
+// elsewhere: a line per day with its events indented below it, or "free" for a day
+// without events
+func (m *Manager) Agenda(start, end time.Time) string {
+	var b strings.Builder
+	end = calendar.NormalizeDate(end)
+	for date := calendar.NormalizeDate(start); !date.After(end); date = date.AddDate(0, 0, 1) {
+		b.WriteString(date.Format("Mon Jan 2, 2006"))
+		events := m.GetEventsForDate(date)
+		if len(events) == 0 {
+			b.WriteString(": free\n")
+			continue
+		}
+		b.WriteString("\n")
+		for _, event := range events {
+			fmt.Fprintf(&b, "  %s %s\n", event.GetTimeString(), event.Description)
+		}
+	}
+	return b.String()
+}
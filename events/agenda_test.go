@@ -0,0 +1,28 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+func TestManager_Agenda(t *testing.T) {
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true})
+	monday := time.Date(2025, 8, 11, 0, 0, 0, 0, time.Local)
+	manager.events = []models.Event{
+		{Date: monday, Time: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC), Description: "Dentist"},
+		{Date: monday, Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Standup"},
+		{Date: monday.AddDate(0, 0, 3), Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Outside the range"},
+	}
+
+	want := "Mon Aug 11, 2025\n  09:00 Standup\n  14:00 Dentist\nTue Aug 12, 2025: free\n"
+	if got := manager.Agenda(monday, monday.AddDate(0, 0, 1)); got != want {
+		t.Errorf("Agenda() = %q, want %q", got, want)
+	}
+
+	if got := manager.Agenda(monday, monday); got != "Mon Aug 11, 2025\n  09:00 Standup\n  14:00 Dentist\n" {
+		t.Errorf("Agenda() of a single day = %q", got)
+	}
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"fmt"
+
+	"go-ascii-calendar/storage"
+)
+
+// backup takes a backup of the events file at path before it is rewritten, keeping as
+// many as configured
+func (m *Manager) backup(path string) error {
+	if m.config == nil {
+		return nil
+	}
+	if err := storage.BackupFile(path, m.config.BackupCount); err != nil {
+		return fmt.Errorf("failed to back up %s: %v", path, err)
+	}
+	return nil
+}
+
+// eventFiles returns the events files of the main calendar and the further calendars
+func (m *Manager) eventFiles() []string {
+	files := []string{m.config.GetEventsFilePath()}
+	for _, calendar := range m.config.Calendars {
+		if calendar.File != "" {
+			files = append(files, calendar.File)
+		}
+	}
+	return files
+}
+
+// Backups returns the backups of the events files, those of the main file first and
+// the latest of each file first
+func (m *Manager) Backups() ([]storage.Backup, error) {
+	if m.config == nil {
+		return nil, nil
+	}
+	var backups []storage.Backup
+	for _, path := range m.eventFiles() {
+		list, err := storage.ListBackups(path)
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, list...)
+	}
+	return backups, nil
+}
+
+// RestoreBackup replaces an events file with one of its backups and reloads the events.
+// The replaced file is backed up first, so the restore can be undone in turn.
+func (m *Manager) RestoreBackup(backup storage.Backup) error {
+	if m.config == nil || m.ReadOnly() {
+		return fmt.Errorf("backups cannot be restored without writing the events file")
+	}
+	if m.unsaved != nil {
+		return fmt.Errorf("changes since %s are not saved yet", m.unsaved.Since.Format("15:04"))
+	}
+	if err := storage.RestoreBackup(backup.File, backup.Number, m.config.BackupCount); err != nil {
+		return err
+	}
+	// The restored file holds what it held, whatever was deleted since
+	m.pendingDeletes = nil
+	return m.LoadEvents()
+}
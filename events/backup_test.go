@@ -0,0 +1,47 @@
+package events
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+)
+
+func TestManager_Backups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.json")
+	manager := NewManagerWithConfig(&config.Config{EventsFilePath: path, BackupCount: 2})
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+
+	// Adding is not destructive, deleting is
+	for _, description := range []string{"Standup", "Review"} {
+		if err := manager.AddEvent(day, "09:00", description); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+	}
+	if backups, _ := manager.Backups(); len(backups) != 0 {
+		t.Fatalf("Backups() after adding = %v, want none", backups)
+	}
+	if err := manager.DeleteEvent(manager.GetEventsForDate(day)[0]); err != nil {
+		t.Fatalf("DeleteEvent() failed: %v", err)
+	}
+	backups, err := manager.Backups()
+	if err != nil || len(backups) != 1 || backups[0].Events != 2 {
+		t.Fatalf("Backups() after deleting = %v, %v; want one with both events", backups, err)
+	}
+
+	if err := manager.RestoreBackup(backups[0]); err != nil {
+		t.Fatalf("RestoreBackup() failed: %v", err)
+	}
+	if manager.GetEventCount() != 2 {
+		t.Errorf("Events after the restore = %d, want 2", manager.GetEventCount())
+	}
+	if backups, _ := manager.Backups(); len(backups) != 2 || backups[0].Events != 1 {
+		t.Errorf("Backups() after the restore = %v, want the replaced file first", backups)
+	}
+
+	dryRun := NewManagerWithConfig(&config.Config{EventsFilePath: path, BackupCount: 2, DryRun: true})
+	if err := dryRun.RestoreBackup(backups[0]); err == nil {
+		t.Error("RestoreBackup() in dry-run mode should fail")
+	}
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"os"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+// initiallyHidden returns the calendars configured to start hidden, by the Calendar of
+// their events
+func initiallyHidden(cfg *config.Config) map[string]bool {
+	hidden := map[string]bool{}
+	if cfg == nil {
+		return hidden
+	}
+	for _, calendar := range cfg.Calendars {
+		if !calendar.Hidden {
+			continue
+		}
+		if calendar.File == "" {
+			hidden[""] = true
+		} else {
+			hidden[calendar.Name] = true
+		}
+	}
+	return hidden
+}
+
+// loadCalendars adds the events of the configured calendars with their own files to the
+// events of the main file. A calendar whose file does not exist yet has no events.
+func (m *Manager) loadCalendars(events []models.Event) ([]models.Event, error) {
+	if m.config == nil {
+		return events, nil
+	}
+	for _, calendar := range m.config.Calendars {
+		if calendar.File == "" {
+			continue
+		}
+		if _, err := os.Stat(calendar.File); os.IsNotExist(err) {
+			continue
+		}
+		loaded, err := storage.LoadEventsJSON(calendar.File)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range loaded {
+			event.Calendar = calendar.Name
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// writeAll replaces the stored collection with events, each calendar's events in its
+// own file
+func (m *Manager) writeAll(events []models.Event) error {
+	if m.config == nil {
+		return storage.SaveAllEventsToFile(events, storage.EventsFileName)
+	}
+
+	byFile := map[string][]models.Event{m.config.GetEventsFilePath(): {}}
+	for _, calendar := range m.config.Calendars {
+		if calendar.File != "" {
+			byFile[calendar.File] = []models.Event{}
+		}
+	}
+	for _, event := range events {
+		path := m.config.CalendarFilePath(event.Calendar)
+		byFile[path] = append(byFile[path], event)
+	}
+	for path, list := range byFile {
+		if err := m.backup(path); err != nil {
+			return err
+		}
+		if err := storage.SaveEventsJSON(list, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveToCalendar stores an edited event in the file of its new calendar
+func (m *Manager) moveToCalendar(oldEvent, newEvent models.Event) error {
+	oldPath := m.config.CalendarFilePath(oldEvent.Calendar)
+	if err := m.backup(oldPath); err != nil {
+		return err
+	}
+	if err := storage.SaveEventWithConfig(newEvent, m.config.CalendarFilePath(newEvent.Calendar)); err != nil {
+		return err
+	}
+	return storage.DeleteEventWithConfig(oldEvent, oldPath)
+}
+
+// CalendarHidden reports whether the events of a calendar are hidden, by the Calendar
+// of its events
+func (m *Manager) CalendarHidden(name string) bool {
+	return m.hiddenCalendars[name]
+}
+
+// ToggleCalendar hides the events of a calendar, or shows them when they are hidden
+// already. It reports whether the calendar is shown afterwards.
+func (m *Manager) ToggleCalendar(name string) bool {
+	if m.hiddenCalendars == nil {
+		m.hiddenCalendars = map[string]bool{}
+	}
+	if m.hiddenCalendars[name] {
+		delete(m.hiddenCalendars, name)
+	} else {
+		m.hiddenCalendars[name] = true
+	}
+	m.version++
+	return !m.hiddenCalendars[name]
+}
+
+// AddCalendar returns the calendar new events are added to, "" for the main events file
+func (m *Manager) AddCalendar() string {
+	return m.addCalendar
+}
+
+// SetAddCalendar selects the calendar new events are added to by the Calendar of its
+// events, "" for the main events file
+func (m *Manager) SetAddCalendar(name string) {
+	m.addCalendar = name
+}
@@ -0,0 +1,123 @@
+package events
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+func TestManager_Calendars(t *testing.T) {
+	dir := t.TempDir()
+	mainPath, workPath := filepath.Join(dir, "events.json"), filepath.Join(dir, "work.json")
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	at := func(hour int, description string) models.Event {
+		return models.Event{Date: day, Time: time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC), Description: description}
+	}
+	if err := storage.SaveEventsJSON([]models.Event{at(9, "Gym")}, mainPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.SaveEventsJSON([]models.Event{at(10, "Standup")}, workPath); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		EventsFilePath: mainPath,
+		Calendars: []config.EventCalendar{
+			{Name: "personal"},
+			{Name: "work", File: workPath},
+			{Name: "family", File: filepath.Join(dir, "family.json"), Hidden: true},
+		},
+	}
+	manager := NewManagerWithConfig(cfg)
+	if err := manager.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	events := manager.GetEventsForDate(day)
+	if len(events) != 2 || events[0].Calendar != "" || events[1].Calendar != "work" {
+		t.Fatalf("GetEventsForDate() = %v, want the gym and the work standup", events)
+	}
+
+	// New events go to the file of the calendar picked
+	manager.SetAddCalendar("family")
+	if err := manager.AddEvent(day, "18:00", "Dinner"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if family, err := storage.LoadEventsJSON(filepath.Join(dir, "family.json")); err != nil || len(family) != 1 {
+		t.Errorf("Family calendar file = %v, %v; want the dinner", family, err)
+	}
+	if len(manager.GetEventsForDate(day)) != 2 {
+		t.Error("Events of a calendar starting hidden should not be shown")
+	}
+
+	// Hiding a calendar leaves its events out until it is shown again
+	if manager.ToggleCalendar("work") {
+		t.Error("ToggleCalendar(work) should report the calendar hidden")
+	}
+	if events := manager.GetEventsForDate(day); len(events) != 1 || events[0].Description != "Gym" {
+		t.Errorf("GetEventsForDate() with work hidden = %v, want the gym", events)
+	}
+	if !manager.ToggleCalendar("work") || manager.CalendarHidden("work") {
+		t.Error("ToggleCalendar(work) should show the calendar again")
+	}
+
+	// Writing the whole collection keeps each event in its own file
+	if err := manager.saveAll(manager.GetAllEvents()); err != nil {
+		t.Fatalf("saveAll() failed: %v", err)
+	}
+	for path, want := range map[string]string{mainPath: "Gym", workPath: "Standup"} {
+		if events, err := storage.LoadEventsJSON(path); err != nil || len(events) != 1 || events[0].Description != want {
+			t.Errorf("%s = %v, %v; want only %s", filepath.Base(path), events, err, want)
+		}
+	}
+}
+
+func TestManager_CalendarsKeepEvents(t *testing.T) {
+	dir := t.TempDir()
+	mainPath, workPath := filepath.Join(dir, "events.json"), filepath.Join(dir, "work.json")
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	standup := models.Event{Date: day, Time: time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC), Description: "Standup"}
+	task := models.Event{Date: day, Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Pay rent", Source: "todo:rent"}
+	if err := storage.SaveEventsJSON([]models.Event{standup}, mainPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.SaveEventsJSON([]models.Event{standup, task}, workPath); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManagerWithConfig(&config.Config{
+		EventsFilePath: mainPath,
+		Calendars:      []config.EventCalendar{{Name: "work", File: workPath}},
+	})
+	if err := manager.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+
+	// Re-importing a changed event keeps it in its calendar
+	changed := task
+	changed.Date = day.AddDate(0, 0, 1)
+	if _, updated, _, err := manager.ImportBySource([]models.Event{changed}, true); err != nil || updated != 1 {
+		t.Fatalf("ImportBySource() = %d updated, %v; want 1", updated, err)
+	}
+	if events := manager.GetEventsForDate(changed.Date); len(events) != 1 || events[0].Calendar != "work" {
+		t.Errorf("Re-imported event = %v, want it in work", events)
+	}
+
+	// Deleting the standup of one calendar leaves the identical one of the other
+	workStandup := standup
+	workStandup.Calendar = "work"
+	if err := manager.DeleteEvent(workStandup); err != nil {
+		t.Fatalf("DeleteEvent() failed: %v", err)
+	}
+	if events := manager.GetEventsForDate(day); len(events) != 1 || events[0].Calendar != "" {
+		t.Errorf("Events after deleting the work standup = %v, want the main one", events)
+	}
+	if events, err := storage.LoadEventsJSON(mainPath); err != nil || len(events) != 1 || events[0].Description != "Standup" {
+		t.Errorf("events.json = %v, %v; want only the standup", events, err)
+	}
+	if events, err := storage.LoadEventsJSON(workPath); err != nil || len(events) != 1 || events[0].Description != "Pay rent" {
+		t.Errorf("work.json = %v, %v; want only the re-imported task", events, err)
+	}
+}
@@ -0,0 +1,135 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+// ConflictField is a field an import source and the calendar can both change
+type ConflictField int
+
+const (
+	FieldDate ConflictField = iota
+	FieldTime
+	FieldDescription
+	FieldPriority
+)
+
+// ConflictFields lists the fields of a conflict in the order they are shown
+var ConflictFields = []ConflictField{FieldDate, FieldTime, FieldDescription, FieldPriority}
+
+// String returns the label of the field
+func (f ConflictField) String() string {
+	switch f {
+	case FieldDate:
+		return "Date"
+	case FieldTime:
+		return "Time"
+	case FieldDescription:
+		return "Description"
+	case FieldPriority:
+		return "Priority"
+	default:
+		return "Unknown"
+	}
+}
+
+// Value returns the field of an event as shown, "-" for an empty priority
+func (f ConflictField) Value(event models.Event) string {
+	switch f {
+	case FieldDate:
+		return event.GetDateString()
+	case FieldTime:
+		return event.GetTimeLabel()
+	case FieldDescription:
+		return event.Description
+	case FieldPriority:
+		if event.Priority == "" {
+			return "-"
+		}
+		return event.Priority
+	default:
+		return ""
+	}
+}
+
+// importBase returns the imported fields of an event, recorded as its ImportBase when
+// it is taken from the source
+func importBase(event models.Event) string {
+	return event.String() + "|" + event.Priority
+}
+
+// Conflicts returns the visible events whose source changed while they were edited in
+// the calendar, sorted by date and time
+func (m *Manager) Conflicts() []models.Event {
+	var conflicts []models.Event
+
+	for _, event := range m.events {
+		if event.Conflict != nil && m.visible(event) {
+			conflicts = append(conflicts, event)
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Date.Equal(conflicts[j].Date) {
+			return conflicts[i].Time.Before(conflicts[j].Time)
+		}
+		return conflicts[i].Date.Before(conflicts[j].Date)
+	})
+
+	return conflicts
+}
+
+// MergeConflict returns the local version of a conflicted event with the fields set in
+// fromSource taken from the source's version; other attributes stay local
+func MergeConflict(event models.Event, fromSource map[ConflictField]bool) models.Event {
+	merged := event
+	merged.Conflict = nil
+	if event.Conflict == nil {
+		return merged
+	}
+
+	remote := *event.Conflict
+	if fromSource[FieldDate] {
+		merged.Date = remote.Date
+		merged.EndDate = shiftEndDate(event, remote.Date)
+	}
+	if fromSource[FieldTime] {
+		merged.Time = remote.Time
+		merged.AllDay = remote.AllDay
+	}
+	if fromSource[FieldDescription] {
+		merged.Description = remote.Description
+	}
+	if fromSource[FieldPriority] {
+		merged.Priority = remote.Priority
+	}
+	return merged
+}
+
+// ResolveConflict settles a conflicted event with the fields set in fromSource taken
+// from the source's version and the others kept as edited in the calendar. The source's
+// version becomes the base of the next import, and the event leaves the review queue.
+func (m *Manager) ResolveConflict(event models.Event, fromSource map[ConflictField]bool) (models.Event, error) {
+	if event.Conflict == nil {
+		return models.Event{}, fmt.Errorf("event %q has no conflict to resolve", event.Description)
+	}
+
+	resolved := MergeConflict(event, fromSource)
+	resolved.ImportBase = importBase(*event.Conflict)
+	resolved.Unreviewed = false
+	if err := storage.ValidateEvent(resolved); err != nil {
+		return models.Event{}, fmt.Errorf("resolved event is invalid: %v", err)
+	}
+	if !sameEvent(resolved, event) && m.containsEvent(resolved) {
+		return models.Event{}, fmt.Errorf("an identical event already exists")
+	}
+
+	if err := m.replaceEvent(event, resolved); err != nil {
+		return models.Event{}, fmt.Errorf("failed to resolve conflict: %v", err)
+	}
+	return resolved, nil
+}
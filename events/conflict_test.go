@@ -0,0 +1,97 @@
+package events
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+func TestManager_ImportBySource_Conflicts(t *testing.T) {
+	cfg := &config.Config{EventsFilePath: filepath.Join(t.TempDir(), "events.json")}
+	manager := NewManagerWithConfig(cfg)
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	nine := time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+	task := func(days int, description, priority string) models.Event {
+		return models.Event{Date: day.AddDate(0, 0, days), Time: nine, Description: description, Priority: priority, Source: "todo:" + description}
+	}
+
+	if added, _, _, err := manager.ImportBySource([]models.Event{task(0, "Pay rent", "C"), task(0, "Call dentist", "")}, false); err != nil || added != 2 {
+		t.Fatalf("First import = %d added, %v; want 2", added, err)
+	}
+
+	// The rent is moved in the calendar; the dentist call only changes in the file
+	rent := manager.SearchEvents("desc:rent")[0]
+	if _, err := manager.MoveEvent(rent, 1); err != nil {
+		t.Fatalf("MoveEvent() failed: %v", err)
+	}
+	added, updated, conflicts, err := manager.ImportBySource([]models.Event{task(0, "Pay rent", "C"), task(2, "Call dentist", "")}, true)
+	if err != nil || added != 0 || updated != 1 || conflicts != 0 {
+		t.Fatalf("Re-import = %d, %d, %d, %v; want the dentist call updated", added, updated, conflicts, err)
+	}
+	if got := manager.GetEventsForDate(day.AddDate(0, 0, 1)); len(got) != 1 || got[0].Description != "Pay rent" {
+		t.Errorf("Local edits should be kept when the source did not change, got %v", got)
+	}
+
+	// Now the rent also changes in the file
+	added, updated, conflicts, err = manager.ImportBySource([]models.Event{task(4, "Pay rent", "A"), task(2, "Call dentist", "")}, true)
+	if err != nil || added != 0 || updated != 0 || conflicts != 1 {
+		t.Fatalf("Conflicting re-import = %d, %d, %d, %v; want 1 conflict", added, updated, conflicts, err)
+	}
+	if again, _, _, _ := manager.ImportBySource([]models.Event{task(4, "Pay rent", "A")}, true); again != 0 {
+		t.Errorf("Importing the same conflict twice added %d events", again)
+	}
+
+	reloaded := NewManagerWithConfig(cfg)
+	if err := reloaded.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	queued := reloaded.Conflicts()
+	if len(queued) != 1 || queued[0].Conflict.Priority != "A" || !queued[0].Unreviewed || !queued[0].Date.Equal(day.AddDate(0, 0, 1)) {
+		t.Fatalf("Conflicts() = %+v, want the locally moved rent with the file's version", queued)
+	}
+
+	// Keep the local date but take the file's priority
+	resolved, err := reloaded.ResolveConflict(queued[0], map[ConflictField]bool{FieldPriority: true})
+	if err != nil {
+		t.Fatalf("ResolveConflict() failed: %v", err)
+	}
+	if !resolved.Date.Equal(day.AddDate(0, 0, 1)) || resolved.Priority != "A" || resolved.Conflict != nil || resolved.Unreviewed {
+		t.Errorf("Resolved event = %+v, want the local date with priority A", resolved)
+	}
+	if len(reloaded.Conflicts()) != 0 {
+		t.Errorf("Conflicts() after resolving = %v, want none", reloaded.Conflicts())
+	}
+
+	// The file's version is the new base, so importing it again changes nothing
+	if _, updated, conflicts, _ := reloaded.ImportBySource([]models.Event{task(4, "Pay rent", "A")}, true); updated != 0 || conflicts != 0 {
+		t.Errorf("Re-import after resolving = %d updated, %d conflicts; want nothing", updated, conflicts)
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	remote := models.Event{Date: day.AddDate(0, 0, 3), Time: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC), Description: "Pay the rent", Priority: "A"}
+	local := models.Event{Date: day, Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Pay rent", Category: "personal", Conflict: &remote}
+
+	kept := MergeConflict(local, nil)
+	if kept.Description != "Pay rent" || kept.Conflict != nil {
+		t.Errorf("Keeping local = %+v", kept)
+	}
+
+	all := map[ConflictField]bool{}
+	for _, field := range ConflictFields {
+		all[field] = true
+	}
+	taken := MergeConflict(local, all)
+	for _, field := range ConflictFields {
+		if field.Value(taken) != field.Value(remote) {
+			t.Errorf("Taking the source: %s = %q, want %q", field, field.Value(taken), field.Value(remote))
+		}
+	}
+	if taken.Category != "personal" {
+		t.Errorf("Taking the source should keep the local category, got %q", taken.Category)
+	}
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/models"
+)
+
+// dateIndex maps each day to the events on it, so that the month grids and event lists
+// look up a day without scanning every stored event. Events spanning several days are
+// kept in a list of their own and checked for every day, as they are few. Like the
+// search index it is built on the first lookup and kept up to date from the manager's
+// change notifications.
+type dateIndex struct {
+	days     map[string][]models.Event // Single-day events by date key
+	spanning []models.Event            // Events spanning several days
+	count    int
+}
+
+// dateKey returns the key of the day of date, e.g. "2025-09-01"
+func dateKey(date time.Time) string {
+	return calendar.NormalizeDate(date).Format("2006-01-02")
+}
+
+// newDateIndex indexes all given events
+func newDateIndex(list []models.Event) *dateIndex {
+	index := &dateIndex{days: make(map[string][]models.Event)}
+	for _, event := range list {
+		index.add(event)
+	}
+	return index
+}
+
+// size returns the number of indexed events
+func (x *dateIndex) size() int {
+	return x.count
+}
+
+// add indexes an event under its day, or among the spanning events
+func (x *dateIndex) add(event models.Event) {
+	x.count++
+	if event.IsMultiDay() {
+		x.spanning = append(x.spanning, event)
+		return
+	}
+	key := dateKey(event.Date)
+	x.days[key] = append(x.days[key], event)
+}
+
+// remove drops one indexed event matching event; unknown events are ignored
+func (x *dateIndex) remove(event models.Event) {
+	if event.IsMultiDay() {
+		if list, ok := withoutEvent(x.spanning, event); ok {
+			x.spanning = list
+			x.count--
+		}
+		return
+	}
+	key := dateKey(event.Date)
+	list, ok := withoutEvent(x.days[key], event)
+	if !ok {
+		return
+	}
+	x.count--
+	if len(list) == 0 {
+		delete(x.days, key)
+	} else {
+		x.days[key] = list
+	}
+}
+
+// withoutEvent returns list without its first event matching event, and whether there was one
+func withoutEvent(list []models.Event, event models.Event) ([]models.Event, bool) {
+	for i, existing := range list {
+		if sameEvent(existing, event) {
+			return append(list[:i:i], list[i+1:]...), true
+		}
+	}
+	return list, false
+}
+
+// apply updates the index for a change reported by the manager
+func (x *dateIndex) apply(kind ChangeKind, before, after models.Event) {
+	if kind != ChangeAdded {
+		x.remove(before)
+	}
+	if kind != ChangeDeleted {
+		x.add(after)
+	}
+}
+
+// on calls fn for each event occurring on date, single-day events first in the order
+// they were added
+func (x *dateIndex) on(date time.Time, fn func(event models.Event) bool) {
+	for _, event := range x.days[dateKey(date)] {
+		if !fn(event) {
+			return
+		}
+	}
+	for _, event := range x.spanning {
+		if event.OccursOn(date) && !fn(event) {
+			return
+		}
+	}
+}
+
+// dateIndex returns the index of the events by day, building it on first use or after
+// the events were replaced as a whole
+func (m *Manager) dateIndex() *dateIndex {
+	if m.days == nil || m.days.size() != len(m.events) {
+		m.days = newDateIndex(m.events)
+	}
+	return m.days
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+func TestManager_DateIndexFollowsChanges(t *testing.T) {
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true})
+	monday := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	tuesday := monday.AddDate(0, 0, 1)
+	if err := manager.AddEvent(monday, "09:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+
+	// The first lookup builds the index; later changes must update it
+	if events := manager.GetEventsForDate(monday); len(events) != 1 {
+		t.Fatalf("GetEventsForDate(monday) = %v, want the standup", events)
+	}
+	if err := manager.AddEvent(monday, "08:00", "Gym"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if err := manager.AddMultiDayEvent(monday, monday.AddDate(0, 0, 2), "", "Conference", true); err != nil {
+		t.Fatalf("AddMultiDayEvent() failed: %v", err)
+	}
+	events := manager.GetEventsForDate(monday)
+	if len(events) != 3 || events[0].Description != "Conference" || events[1].Description != "Gym" {
+		t.Errorf("GetEventsForDate(monday) = %v, want the conference, then the gym and the standup by time", events)
+	}
+	if !manager.HasEventsForDate(tuesday) || len(manager.GetEventsForDate(tuesday)) != 1 {
+		t.Errorf("Tuesday should hold only the conference, got %v", manager.GetEventsForDate(tuesday))
+	}
+
+	gym := manager.GetEventsForDate(monday)[1]
+	if _, err := manager.MoveEvent(gym, 1); err != nil {
+		t.Fatalf("MoveEvent() failed: %v", err)
+	}
+	if events := manager.GetEventsForDate(tuesday); len(events) != 2 {
+		t.Errorf("GetEventsForDate(tuesday) after the move = %v, want the conference and the gym", events)
+	}
+	if err := manager.DeleteEvent(manager.GetEventsForDate(monday)[1]); err != nil {
+		t.Fatalf("DeleteEvent() failed: %v", err)
+	}
+	if events := manager.GetEventsForDate(monday); len(events) != 1 || events[0].Description != "Conference" {
+		t.Errorf("GetEventsForDate(monday) after the deletion = %v, want the conference", events)
+	}
+	if manager.HasEventsForDate(monday.AddDate(0, 0, 3)) {
+		t.Error("HasEventsForDate() after the conference ended = true, want false")
+	}
+}
+
+func BenchmarkManager_GetEventsForDate(b *testing.B) {
+	manager := NewManager()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)
+	for i := 0; i < 100000; i++ {
+		manager.events = append(manager.events, models.Event{
+			Date:        start.AddDate(0, 0, i%2000),
+			Time:        time.Date(0, 1, 1, i%24, 0, 0, 0, time.UTC),
+			Description: fmt.Sprintf("Event #%d", i),
+		})
+	}
+	day := start.AddDate(0, 0, 42)
+	manager.HasEventsForDate(day)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// One month grid looks up every day of the month
+		for d := 0; d < 31; d++ {
+			manager.HasEventsForDate(day.AddDate(0, 0, d))
+		}
+		manager.GetEventsForDate(day)
+	}
+}
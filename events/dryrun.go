@@ -0,0 +1,97 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+
+	"go-ascii-calendar/models"
+)
+
+// DryRun reports whether changes are kept in memory instead of being written
+func (m *Manager) DryRun() bool {
+	return m.dryRun
+}
+
+// Ephemeral reports whether events live in memory only, without an events file
+func (m *Manager) Ephemeral() bool {
+	return m.ephemeral
+}
+
+// ReadOnly reports whether changes stay in memory instead of being written, because of
+// dry-run or ephemeral mode
+func (m *Manager) ReadOnly() bool {
+	return m.dryRun || m.ephemeral
+}
+
+// DryRunDiff summarizes what the changes made in dry-run mode would have written,
+// diff-style: "- " lines for events that would be removed from the events file and
+// "+ " lines for events that would be added. An edit shows as the removal of the
+// old version and the addition of the new one.
+func (m *Manager) DryRunDiff() []string {
+	removed := eventCounts(m.loaded)
+	added := eventCounts(m.events)
+	for line, count := range removed {
+		common := count
+		if added[line] < common {
+			common = added[line]
+		}
+		removed[line] -= common
+		added[line] -= common
+	}
+
+	var diff []string
+	for _, event := range remainingEvents(m.loaded, removed) {
+		diff = append(diff, "- "+diffLine(event))
+	}
+	for _, event := range remainingEvents(m.events, added) {
+		diff = append(diff, "+ "+diffLine(event))
+	}
+	sort.SliceStable(diff, func(i, j int) bool {
+		// Order by date and time; a removal stays before an addition at the same moment
+		return diff[i][2:18] < diff[j][2:18]
+	})
+	return diff
+}
+
+// diffLine formats an event with all stored attributes for the dry-run diff
+func diffLine(event models.Event) string {
+	line := fmt.Sprintf("%s %s %s", event.GetDateString(), event.GetTimeString(), event.Description)
+	if event.Category != "" {
+		line += fmt.Sprintf(" [%s]", event.Category)
+	}
+	if event.Priority != "" {
+		line += fmt.Sprintf(" (%s)", event.Priority)
+	}
+	if event.Command != "" {
+		line += fmt.Sprintf(" !%s", event.Command)
+	}
+	if reminder := event.GetReminderLabel(); reminder != "" {
+		line += " @" + reminder
+	}
+	if event.Notes != "" {
+		line += fmt.Sprintf(" (notes: %q)", event.Notes)
+	}
+	return line
+}
+
+// eventCounts counts the events of list by their diff line
+func eventCounts(list []models.Event) map[string]int {
+	counts := make(map[string]int)
+	for _, event := range list {
+		counts[diffLine(event)]++
+	}
+	return counts
+}
+
+// remainingEvents returns the events of list still counted in counts, consuming the counts
+func remainingEvents(list []models.Event, counts map[string]int) []models.Event {
+	var result []models.Event
+	for _, event := range list {
+		line := diffLine(event)
+		if counts[line] > 0 {
+			counts[line]--
+			result = append(result, event)
+		}
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+func TestManager_DryRun(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "dryrun_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	eventsFile := filepath.Join(tempDir, "events.json")
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	stored := []models.Event{
+		{Date: date, Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Standup"},
+		{Date: date, Time: time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC), Description: "Lunch"},
+	}
+	if err := storage.SaveEventsJSON(stored, eventsFile); err != nil {
+		t.Fatalf("Failed to write events file: %v", err)
+	}
+	before, err := os.ReadFile(eventsFile)
+	if err != nil {
+		t.Fatalf("Failed to read events file: %v", err)
+	}
+
+	manager := NewManagerWithConfig(&config.Config{EventsFilePath: eventsFile, DryRun: true})
+	if !manager.DryRun() {
+		t.Fatal("DryRun() should follow the configuration")
+	}
+	if err := manager.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	if diff := manager.DryRunDiff(); len(diff) != 0 {
+		t.Errorf("DryRunDiff() without changes = %v, want none", diff)
+	}
+
+	if err := manager.AddEvent(date, "18:00", "Dinner"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if err := manager.DeleteEvent(stored[1]); err != nil {
+		t.Fatalf("DeleteEvent() failed: %v", err)
+	}
+	if err := manager.EditEvent(stored[0], date, "09:30", "Standup"); err != nil {
+		t.Fatalf("EditEvent() failed: %v", err)
+	}
+
+	// Changes are visible in memory but never written
+	if len(manager.GetEventsForDate(date)) != 2 {
+		t.Errorf("In-memory events = %v, want the edited standup and dinner", manager.GetEventsForDate(date))
+	}
+	after, err := os.ReadFile(eventsFile)
+	if err != nil {
+		t.Fatalf("Failed to read events file: %v", err)
+	}
+	if string(after) != string(before) {
+		t.Error("Dry run should not write the events file")
+	}
+
+	expected := []string{
+		"- 2025-08-15 09:00 Standup",
+		"+ 2025-08-15 09:30 Standup",
+		"- 2025-08-15 12:00 Lunch",
+		"+ 2025-08-15 18:00 Dinner",
+	}
+	if diff := manager.DryRunDiff(); !reflect.DeepEqual(diff, expected) {
+		t.Errorf("DryRunDiff() = %v, want %v", diff, expected)
+	}
+}
+
+func TestManager_Ephemeral(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "ephemeral_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	seedFile := filepath.Join(tempDir, "demo.json")
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	seed := []models.Event{{Date: date, Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Demo standup"}}
+	if err := storage.SaveEventsJSON(seed, seedFile); err != nil {
+		t.Fatalf("Failed to write seed file: %v", err)
+	}
+
+	eventsFile := filepath.Join(tempDir, "events.json")
+	manager := NewManagerWithConfig(&config.Config{EventsFilePath: eventsFile, Ephemeral: true, SeedFile: seedFile})
+	if !manager.Ephemeral() || !manager.ReadOnly() || manager.DryRun() {
+		t.Errorf("Ephemeral() = %v, ReadOnly() = %v, DryRun() = %v", manager.Ephemeral(), manager.ReadOnly(), manager.DryRun())
+	}
+	if err := manager.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	if manager.GetEventCount() != 1 {
+		t.Fatalf("Seeded %d events, want 1", manager.GetEventCount())
+	}
+
+	if err := manager.AddEvent(date, "18:00", "Demo dinner"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if err := manager.DeleteEvent(seed[0]); err != nil {
+		t.Fatalf("DeleteEvent() failed: %v", err)
+	}
+	if manager.GetEventCount() != 1 {
+		t.Errorf("In-memory events = %d, want 1", manager.GetEventCount())
+	}
+
+	if _, err := os.Stat(eventsFile); !os.IsNotExist(err) {
+		t.Errorf("An ephemeral session should not create the events file, stat error: %v", err)
+	}
+	stored, err := storage.LoadEventsJSON(seedFile)
+	if err != nil || len(stored) != 1 {
+		t.Errorf("The seed file should be left unchanged, got %v (%v)", stored, err)
+	}
+}
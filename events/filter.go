@@ -0,0 +1,85 @@
+package events
+
+import (
+	"strings"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+// FilterMatches reports whether an event passes a quick filter: it must have the
+// filter's category, if one is set, and match its search query, if one is set
+func FilterMatches(filter config.QuickFilter, event models.Event) bool {
+	return matchesCategoryAndQuery(event, filter.Category, filter.Query)
+}
+
+// matchesCategoryAndQuery reports whether an event has the category and matches the
+// search query; empty ones match every event
+func matchesCategoryAndQuery(event models.Event, category, query string) bool {
+	if category != "" && !strings.EqualFold(event.Category, category) {
+		return false
+	}
+	filter, query, err := ParseSearchFilter(query, time.Now())
+	if err != nil || !filter.Matches(event) {
+		return false
+	}
+	if query == "" {
+		return true
+	}
+
+	fields, text := ParseSearchQuery(query)
+	lowerText := strings.ToLower(text)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field.Value(event)), lowerText) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveFilters returns the quick filters currently limiting the visible events
+func (m *Manager) ActiveFilters() []config.QuickFilter {
+	return append([]config.QuickFilter(nil), m.filters...)
+}
+
+// ToggleFilter activates a quick filter, or deactivates it when it is active already.
+// It reports whether the filter is active afterwards.
+func (m *Manager) ToggleFilter(filter config.QuickFilter) bool {
+	for i, active := range m.filters {
+		if active.Key == filter.Key {
+			m.filters = append(m.filters[:i], m.filters[i+1:]...)
+			m.version++
+			return false
+		}
+	}
+	m.filters = append(m.filters, filter)
+	m.version++
+	return true
+}
+
+// ClearFilters deactivates all quick filters
+func (m *Manager) ClearFilters() {
+	if len(m.filters) == 0 {
+		return
+	}
+	m.filters = nil
+	m.version++
+}
+
+// visible reports whether an event is shown with the active quick filters: without
+// filters every event of a shown calendar is, otherwise those matching any active filter are
+func (m *Manager) visible(event models.Event) bool {
+	if m.hiddenCalendars[event.Calendar] {
+		return false
+	}
+	if len(m.filters) == 0 {
+		return true
+	}
+	for _, filter := range m.filters {
+		if FilterMatches(filter, event) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+func TestFilterMatches(t *testing.T) {
+	event := models.Event{
+		Date:        time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC),
+		Time:        time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+		Description: "Team standup",
+		Category:    "Work",
+	}
+
+	tests := []struct {
+		name   string
+		filter config.QuickFilter
+		want   bool
+	}{
+		{"empty filter", config.QuickFilter{Key: "F1"}, true},
+		{"category", config.QuickFilter{Key: "F1", Category: "work"}, true},
+		{"other category", config.QuickFilter{Key: "F1", Category: "family"}, false},
+		{"query", config.QuickFilter{Key: "F1", Query: "STANDUP"}, true},
+		{"field query", config.QuickFilter{Key: "F1", Query: "cat:wor"}, true},
+		{"query without match", config.QuickFilter{Key: "F1", Query: "dentist"}, false},
+		{"category and query", config.QuickFilter{Key: "F1", Category: "Work", Query: "team"}, true},
+		{"category without query match", config.QuickFilter{Key: "F1", Category: "Work", Query: "dentist"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FilterMatches(tt.filter, event); got != tt.want {
+				t.Errorf("FilterMatches(%+v) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_QuickFilters(t *testing.T) {
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true})
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)
+	manager.events = []models.Event{
+		{Date: date, Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Standup", Category: "Work"},
+		{Date: date, Time: time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC), Description: "Dinner", Category: "Family"},
+		{Date: date, Time: time.Date(0, 1, 1, 20, 0, 0, 0, time.UTC), Description: "Gym"},
+	}
+	work := config.QuickFilter{Key: "F1", Name: "work", Category: "work"}
+	family := config.QuickFilter{Key: "F2", Name: "family", Category: "family"}
+
+	if got := len(manager.GetEventsForDate(date)); got != 3 {
+		t.Fatalf("Without filters %d events are visible, want 3", got)
+	}
+
+	version := manager.Version()
+	if !manager.ToggleFilter(work) {
+		t.Error("ToggleFilter() should report the filter as active")
+	}
+	if manager.Version() == version {
+		t.Error("Toggling a filter should change the manager version")
+	}
+	if got := manager.GetEventsForDate(date); len(got) != 1 || got[0].Description != "Standup" {
+		t.Errorf("With the work filter the visible events are %v, want the standup", got)
+	}
+	if results := manager.SearchEvents("dinner"); len(results) != 0 {
+		t.Errorf("Search found %d filtered out events", len(results))
+	}
+
+	// Active filters combine: events matching any of them are visible
+	manager.ToggleFilter(family)
+	if got := len(manager.GetEventsForDate(date)); got != 2 {
+		t.Errorf("With two filters %d events are visible, want 2", got)
+	}
+	if got := len(manager.ActiveFilters()); got != 2 {
+		t.Errorf("ActiveFilters() = %d filters, want 2", got)
+	}
+
+	if manager.ToggleFilter(work) {
+		t.Error("Toggling an active filter should deactivate it")
+	}
+	if got := manager.GetEventsForDate(date); len(got) != 1 || got[0].Description != "Dinner" {
+		t.Errorf("With the family filter the visible events are %v, want the dinner", got)
+	}
+
+	manager.ClearFilters()
+	if len(manager.ActiveFilters()) != 0 || !manager.HasEventsForDate(date) || len(manager.GetEventsForDate(date)) != 3 {
+		t.Error("ClearFilters() should make all events visible again")
+	}
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-ascii-calendar/models"
+)
+
+// fuzzyMinLength is the shortest query matched fuzzily; shorter ones would match
+// nearly every event
+const fuzzyMinLength = 3
+
+// fuzzySpread bounds how far apart the characters of a fuzzy match may be: the match
+// may cover at most this many characters of the text per character of the query
+const fuzzySpread = 3
+
+// Scores of the characters of a match, see matchScore
+const (
+	scoreChar        = 1   // Each matched character
+	scoreConsecutive = 4   // A character right after the previous one
+	scoreWordStart   = 6   // A character starting a word
+	scoreSubstring   = 100 // The query appears as a whole
+)
+
+// matchScore scores how well lowerText matches lowerQuery, both lowercase: the
+// characters of the query must appear in the text in order, and the score is higher
+// when they run together and start words, and highest when the query appears as a
+// whole. Matches spread too far apart, see fuzzySpread, and texts not holding the
+// characters score 0.
+func matchScore(lowerText, lowerQuery string) int {
+	text, query := []rune(lowerText), []rune(lowerQuery)
+	if len(query) == 0 {
+		return 0
+	}
+
+	best := 0
+	for start := range text {
+		if text[start] != query[0] {
+			continue
+		}
+		score, prev, matched := 0, -1, 0
+		for i := start; i < len(text) && matched < len(query); i++ {
+			if text[i] != query[matched] {
+				continue
+			}
+			score += scoreChar
+			if prev >= 0 && i == prev+1 {
+				score += scoreConsecutive
+			}
+			if i == 0 || !unicode.IsLetter(text[i-1]) && !unicode.IsDigit(text[i-1]) {
+				score += scoreWordStart
+			}
+			prev = i
+			matched++
+		}
+		span := prev - start + 1
+		if matched < len(query) {
+			break // Later starts cannot match either
+		}
+		if span == len(query) {
+			score += scoreSubstring
+		} else if span > fuzzySpread*len(query) {
+			continue
+		}
+		if score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+// searchScore combines the scores of an event's fields for the query text, each scaled
+// by the weight of its field: an event matching in its description ranks above one
+// matching only in its notes, and one matching in several fields above one matching in
+// a single field as well
+func searchScore(event models.Event, fields []SearchField, lowerText string) int {
+	total := 0
+	for _, field := range fields {
+		total += field.Weight * matchScore(strings.ToLower(field.Value(event)), lowerText)
+	}
+	return total
+}
+
+// OrderByRelevance reorders search results by how well they match query, best first:
+// those containing the query as a whole before fuzzy matches, scored across all fields
+// searched, see searchScore. Results matching equally well keep their order.
+func OrderByRelevance(results []models.Event, query string) []models.Event {
+	_, query, _ = ParseSearchFilter(query, time.Now())
+	fields, text := ParseSearchQuery(query)
+	lowerText := strings.ToLower(text)
+
+	scores := make([]int, len(results))
+	order := make([]int, len(results))
+	for i, event := range results {
+		scores[i] = searchScore(event, fields, lowerText)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	ordered := make([]models.Event, len(results))
+	for i, index := range order {
+		ordered[i] = results[index]
+	}
+	return ordered
+}
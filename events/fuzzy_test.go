@@ -0,0 +1,81 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+func TestMatchScore(t *testing.T) {
+	tests := []struct {
+		text, query string
+		match       bool
+	}{
+		{"standup", "stdup", true},
+		{"standup", "stand", true},
+		{"team meeting", "tmm", true},
+		{"dentist appointment", "dntst", true},
+		{"standup", "pudnats", false},
+		{"lunch with the team", "team lunch", false},
+		{"a very long description that happens to hold b and then c", "abc", false},
+		{"standup", "", false},
+	}
+	for _, tt := range tests {
+		if got := matchScore(tt.text, tt.query); (got > 0) != tt.match {
+			t.Errorf("matchScore(%q, %q) = %d, want a match: %v", tt.text, tt.query, got, tt.match)
+		}
+	}
+
+	// Whole matches beat fuzzy ones, and runs starting words beat scattered letters
+	if matchScore("standup", "stand") <= matchScore("standup", "stdup") {
+		t.Error("A whole match should score higher than a fuzzy one")
+	}
+	if matchScore("team review", "tr") <= matchScore("tiara", "tr") {
+		t.Error("Letters starting words should score higher than letters inside one")
+	}
+}
+
+func TestManager_SearchEventsFuzzy(t *testing.T) {
+	manager := NewManager()
+	at := func(day int, description string) models.Event {
+		return models.Event{Date: time.Date(2025, 8, day, 0, 0, 0, 0, time.UTC), Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: description}
+	}
+	manager.events = []models.Event{at(17, "Standup"), at(15, "Stand-up rehearsal"), at(16, "Dentist")}
+
+	// Without a whole match, the query's letters in order match
+	results := manager.SearchEvents("stdup")
+	if len(results) != 2 || results[0].Description != "Stand-up rehearsal" || results[1].Description != "Standup" {
+		t.Errorf("SearchEvents(stdup) = %v, want both standups by date", results)
+	}
+	if results := manager.SearchEvents("desc:dnt"); len(results) != 1 {
+		t.Errorf("SearchEvents(desc:dnt) = %v, want the dentist", results)
+	}
+
+	// A whole match leaves out the fuzzy ones, and short queries are never fuzzy
+	if results := manager.SearchEvents("standup"); len(results) != 1 {
+		t.Errorf("SearchEvents(standup) = %v, want only the whole match", results)
+	}
+	if results := manager.SearchEvents("sd"); len(results) != 0 {
+		t.Errorf("SearchEvents(sd) = %v, want none", results)
+	}
+
+	ordered := OrderByRelevance(manager.events, "standup")
+	if ordered[0].Description != "Standup" || ordered[1].Description != "Stand-up rehearsal" || ordered[2].Description != "Dentist" {
+		t.Errorf("OrderByRelevance() = %v, want the whole match, the fuzzy one and then the rest", ordered)
+	}
+}
+
+func TestOrderByRelevance_CombinesFields(t *testing.T) {
+	date := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	results := []models.Event{
+		{Date: date, Description: "Call", Notes: "About the budget"},
+		{Date: date, Description: "Budget review", Category: "work"},
+		{Date: date, Description: "Budget review", Category: "budget"},
+	}
+
+	ordered := OrderByRelevance(results, "budget")
+	if ordered[0].Category != "budget" || ordered[1].Category != "work" || ordered[2].Description != "Call" {
+		t.Errorf("OrderByRelevance() = %v, want the match in two fields, then in the description, then in the notes", ordered)
+	}
+}
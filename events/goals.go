@@ -0,0 +1,57 @@
+package events
+
+import (
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/config"
+)
+
+// GoalProgress is how far a goal is reached in its current period
+type GoalProgress struct {
+	Goal  config.Goal
+	Done  int       // Matching events from the start of the period through today
+	Start time.Time // First day of the period
+	End   time.Time // Last day of the period
+}
+
+// Reached reports whether the goal's target is met
+func (p GoalProgress) Reached() bool {
+	return p.Done >= p.Goal.Target
+}
+
+// GoalPeriod returns the first and last day of the goal's period holding now. Weekly
+// goals start on weekStart, monthly goals on the first of the month.
+func GoalPeriod(goal config.Goal, now time.Time, weekStart config.WeekStartDay) (time.Time, time.Time) {
+	today := calendar.NormalizeDate(now)
+	if goal.Period == config.GoalMonthly {
+		start := calendar.GetFirstDayOfMonth(today)
+		return start, start.AddDate(0, 1, -1)
+	}
+
+	start := calendar.GetWeekStart(today, int(weekStart))
+	return start, start.AddDate(0, 0, 6)
+}
+
+// GoalProgress counts the events matching a goal from the start of its current period
+// through today. Quick filters do not apply: goals always count every event.
+func (m *Manager) GoalProgress(goal config.Goal, now time.Time) GoalProgress {
+	weekStart := config.StartSunday
+	if m.config != nil {
+		weekStart = m.config.WeekStartDay
+	}
+	start, end := GoalPeriod(goal, now, weekStart)
+	today := calendar.NormalizeDate(now)
+
+	progress := GoalProgress{Goal: goal, Start: start, End: end}
+	for _, event := range m.events {
+		date := calendar.NormalizeDate(event.Date)
+		if date.Before(start) || date.After(today) {
+			continue
+		}
+		if matchesCategoryAndQuery(event, goal.Category, goal.Query) {
+			progress.Done++
+		}
+	}
+	return progress
+}
@@ -0,0 +1,68 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+func TestGoalPeriod(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 8, 13, 15, 30, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name      string
+		period    string
+		weekStart config.WeekStartDay
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{"week from Sunday", config.GoalWeekly, config.StartSunday, day(8, 10), day(8, 16)},
+		{"week from Monday", config.GoalWeekly, config.StartMonday, day(8, 11), day(8, 17)},
+		{"month", config.GoalMonthly, config.StartMonday, day(8, 1), day(8, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := GoalPeriod(config.Goal{Period: tt.period}, now, tt.weekStart)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("GoalPeriod() = %v - %v, want %v - %v", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestManager_GoalProgress(t *testing.T) {
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true, WeekStartDay: config.StartMonday})
+	now := time.Date(2025, 8, 13, 12, 0, 0, 0, time.UTC)
+	event := func(day int, description, category string) models.Event {
+		return models.Event{Date: time.Date(2025, 8, day, 0, 0, 0, 0, time.UTC), Description: description, Category: category}
+	}
+	manager.events = []models.Event{
+		event(10, "Gym", "health"),       // Last week
+		event(11, "Gym", "health"),       // Monday
+		event(12, "Gym session", ""),     // Matches the query only
+		event(13, "Dentist", "health"),   // Today, other description
+		event(15, "Gym", "health"),       // Later this week, not done yet
+		event(4, "Morning run", "sport"), // Earlier this month
+	}
+
+	gym := config.Goal{Name: "gym", Period: config.GoalWeekly, Target: 3, Query: "gym"}
+	if progress := manager.GoalProgress(gym, now); progress.Done != 2 || progress.Reached() {
+		t.Errorf("Weekly gym goal: %d done, reached %v; want 2, false", progress.Done, progress.Reached())
+	}
+
+	health := config.Goal{Name: "health", Period: config.GoalMonthly, Target: 3, Category: "Health"}
+	if progress := manager.GoalProgress(health, now); progress.Done != 3 || !progress.Reached() {
+		t.Errorf("Monthly health goal: %d done, reached %v; want 3, true", progress.Done, progress.Reached())
+	}
+
+	// Quick filters hide events from views, not from goals
+	manager.ToggleFilter(config.QuickFilter{Key: "F1", Category: "sport"})
+	if progress := manager.GoalProgress(gym, now); progress.Done != 2 {
+		t.Errorf("With a quick filter the gym goal counts %d events, want 2", progress.Done)
+	}
+}
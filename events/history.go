@@ -0,0 +1,121 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+// Activity is one storage mutation recorded during the current session
+type Activity struct {
+	At     time.Time
+	Kind   ChangeKind
+	Before models.Event // Event before the change (zero for additions)
+	After  models.Event // Event after the change (zero for deletions)
+	Undone bool
+}
+
+// Subject returns the event an activity is about
+func (a Activity) Subject() models.Event {
+	if a.Kind == ChangeDeleted {
+		return a.Before
+	}
+	return a.After
+}
+
+// History records the mutations performed on a manager during one session
+type History struct {
+	manager *Manager
+	entries []Activity
+	undoing bool
+	now     func() time.Time
+}
+
+// NewHistory creates a session history and subscribes it to the manager's changes
+func NewHistory(manager *Manager) *History {
+	h := &History{
+		manager: manager,
+		now:     time.Now,
+	}
+	manager.AddChangeListener(h.record)
+	return h
+}
+
+// record is the change listener appending new activities
+func (h *History) record(kind ChangeKind, before, after models.Event) {
+	// Changes made while undoing are the undo itself, not new activity
+	if h.undoing {
+		return
+	}
+	h.entries = append(h.entries, Activity{At: h.now(), Kind: kind, Before: before, After: after})
+}
+
+// Entries returns the recorded activities, most recent first
+func (h *History) Entries() []Activity {
+	result := make([]Activity, len(h.entries))
+	for i, entry := range h.entries {
+		result[len(h.entries)-1-i] = entry
+	}
+	return result
+}
+
+// Len returns the number of recorded activities
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// Undo reverts the activity at index (as returned by Entries) and marks it undone
+func (h *History) Undo(index int) error {
+	if index < 0 || index >= len(h.entries) {
+		return fmt.Errorf("no activity at index %d", index)
+	}
+
+	entry := &h.entries[len(h.entries)-1-index]
+	if entry.Undone {
+		return fmt.Errorf("change was already undone")
+	}
+
+	h.undoing = true
+	defer func() { h.undoing = false }()
+
+	var err error
+	switch entry.Kind {
+	case ChangeAdded:
+		err = h.manager.DeleteEvent(entry.After)
+	case ChangeDeleted:
+		err = h.manager.RestoreEvent(entry.Before)
+	case ChangeEdited:
+		err = h.manager.replaceEvent(entry.After, entry.Before)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to undo %s event: %v", entry.Kind, err)
+	}
+
+	entry.Undone = true
+	return nil
+}
+
+// UndoLatest reverts the most recent activity that is not undone yet and returns it
+func (h *History) UndoLatest() (Activity, error) {
+	for index, entry := range h.Entries() {
+		if entry.Undone {
+			continue
+		}
+		if err := h.Undo(index); err != nil {
+			return Activity{}, err
+		}
+		return entry, nil
+	}
+	return Activity{}, fmt.Errorf("nothing to undo")
+}
+
+// UndoDeletion reverts the most recent deletion of event that is not undone yet
+func (h *History) UndoDeletion(event models.Event) error {
+	for index, entry := range h.Entries() {
+		if entry.Kind == ChangeDeleted && !entry.Undone && sameEvent(entry.Before, event) {
+			return h.Undo(index)
+		}
+	}
+	return fmt.Errorf("no deletion of %q to undo", event.Description)
+}
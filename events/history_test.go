@@ -0,0 +1,91 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+)
+
+func TestHistory_RecordAndUndo(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "history_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := NewManagerWithConfig(cfg)
+	history := NewHistory(manager)
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+
+	if err := manager.AddEvent(testDate, "09:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if err := manager.AddEvent(testDate, "12:00", "Lunch"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	standup := manager.GetEventsForDate(testDate)[0]
+	if err := manager.SetEventCategory(standup, "work"); err != nil {
+		t.Fatalf("SetEventCategory() failed: %v", err)
+	}
+	categorized := manager.GetEventsForDate(testDate)[0]
+	if err := manager.EditEvent(categorized, testDate, "09:30", "Standup moved"); err != nil {
+		t.Fatalf("EditEvent() failed: %v", err)
+	}
+	lunch := manager.GetEventsForDate(testDate)[1]
+	if err := manager.DeleteEvent(lunch); err != nil {
+		t.Fatalf("DeleteEvent() failed: %v", err)
+	}
+
+	entries := history.Entries()
+	if len(entries) != 5 {
+		t.Fatalf("History has %d entries, want 5", len(entries))
+	}
+	if entries[0].Kind != ChangeDeleted || entries[0].Subject().Description != "Lunch" {
+		t.Errorf("Most recent entry = %v %s, want deleted Lunch", entries[0].Kind, entries[0].Subject().Description)
+	}
+
+	// Undo the deletion: the event comes back
+	if err := history.Undo(0); err != nil {
+		t.Fatalf("Undo(delete) failed: %v", err)
+	}
+	if len(manager.GetEventsForDate(testDate)) != 2 {
+		t.Errorf("Event count after undoing delete = %d, want 2", len(manager.GetEventsForDate(testDate)))
+	}
+
+	// Undo the edit: the original time, description and category return
+	if err := history.Undo(1); err != nil {
+		t.Fatalf("Undo(edit) failed: %v", err)
+	}
+	restored := manager.GetEventsForDate(testDate)[0]
+	if restored.Description != "Standup" || restored.GetTimeString() != "09:00" || restored.Category != "work" {
+		t.Errorf("Event after undoing edit = %+v, want Standup at 09:00 in work", restored)
+	}
+
+	// Undoing is not recorded as new activity, and cannot be repeated
+	if history.Len() != 5 {
+		t.Errorf("History length after undo = %d, want 5", history.Len())
+	}
+	if err := history.Undo(0); err == nil {
+		t.Error("Undo() of an already undone entry should fail")
+	}
+
+	// Undo the first addition: the event is removed again
+	if err := history.Undo(4); err != nil {
+		t.Fatalf("Undo(add) failed: %v", err)
+	}
+	if err := history.Undo(5); err == nil {
+		t.Error("Undo() with out of range index should fail")
+	}
+
+	reloaded := NewManagerWithConfig(cfg)
+	if err := reloaded.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	if reloaded.GetEventCount() != 1 || reloaded.GetAllEvents()[0].Description != "Lunch" {
+		t.Errorf("Persisted events = %v, want only Lunch", reloaded.GetAllEvents())
+	}
+}
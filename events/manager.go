@@ -3,7 +3,6 @@ package events
 import (
 	"fmt"
 	"sort"
-	"strings"
 	"time"
 
 	"go-ascii-calendar/calendar"
@@ -12,10 +11,76 @@ import (
 	"go-ascii-calendar/storage"
 )
 
+// ChangeKind identifies the type of a persisted event mutation
+type ChangeKind int
+
+const (
+	ChangeAdded ChangeKind = iota
+	ChangeEdited
+	ChangeDeleted
+)
+
+// String returns a human-readable name for the change kind
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeEdited:
+		return "edited"
+	case ChangeDeleted:
+		return "deleted"
+	default:
+		return "unknown"
+	}
+}
+
+// ChangeListener is called after a mutation has been persisted, or kept in memory by a
+// failed write under write recovery.
+// For additions before is the zero Event; for deletions after is the zero Event.
+type ChangeListener func(kind ChangeKind, before, after models.Event)
+
 // Manager handles event operations and integrates with storage
 type Manager struct {
-	events []models.Event
-	config *config.Config
+	events    []models.Event
+	config    *config.Config
+	listeners []ChangeListener
+
+	// In dry-run mode nothing is written; changes are compared against the events as loaded
+	dryRun bool
+	loaded []models.Event
+
+	// An ephemeral manager never reads or writes the events file; it starts from the seed file, if any
+	ephemeral bool
+
+	// Built on the first search and updated with every change; nil until then
+	index *searchIndex
+
+	// Events by day, built on the first lookup and updated with every change like index
+	days *dateIndex
+
+	// Incremented with every change, so views can cache what they derive from the events
+	version uint64
+
+	// Quick filters limiting the events returned for display; all events are shown without any
+	filters []config.QuickFilter
+
+	// Calendars whose events are hidden from display, and the calendar new events are added to
+	hiddenCalendars map[string]bool
+	addCalendar     string
+
+	// Deleted events still in storage until their undo delay lapses, oldest first
+	pendingDeletes []PendingDelete
+
+	// With write recovery a failed write keeps the change in memory and is retried;
+	// unsaved describes the failure until a retry succeeds
+	writeRecovery bool
+	unsaved       *WriteFailure
+
+	// The events files as last read or written, to notice changes made by other programs
+	stamps map[string]fileStamp
+
+	// Called before every storage write, e.g. to tell a background sync about it
+	beforeWrite func()
 }
 
 // NewManager creates a new event manager (legacy function)
@@ -29,29 +94,102 @@ func NewManager() *Manager {
 // NewManagerWithConfig creates a new event manager with configuration
 func NewManagerWithConfig(cfg *config.Config) *Manager {
 	return &Manager{
-		events: make([]models.Event, 0),
-		config: cfg,
+		events:          make([]models.Event, 0),
+		config:          cfg,
+		dryRun:          cfg != nil && cfg.DryRun,
+		ephemeral:       cfg != nil && cfg.Ephemeral,
+		hiddenCalendars: initiallyHidden(cfg),
 	}
 }
 
+// AddChangeListener registers a listener notified after every add, edit and delete
+func (m *Manager) AddChangeListener(listener ChangeListener) {
+	m.listeners = append(m.listeners, listener)
+}
+
+// notifyChange informs all registered listeners about a persisted mutation
+func (m *Manager) notifyChange(kind ChangeKind, before, after models.Event) {
+	m.version++
+	if m.index != nil {
+		m.index.apply(kind, before, after)
+	}
+	if m.days != nil {
+		m.days.apply(kind, before, after)
+	}
+	for _, listener := range m.listeners {
+		listener(kind, before, after)
+	}
+}
+
+// replaced drops what was derived from the events after they were replaced as a whole
+func (m *Manager) replaced() {
+	m.version++
+	m.index = nil
+	m.days = nil
+}
+
+// Version returns a number that changes whenever the events change
+func (m *Manager) Version() uint64 {
+	return m.version
+}
+
 // LoadEvents loads all events from storage on application startup
 func (m *Manager) LoadEvents() error {
+	if m.unsaved != nil {
+		return fmt.Errorf("changes since %s are not saved yet", m.unsaved.Since.Format("15:04"))
+	}
+
 	var events []models.Event
 	var err error
 
-	if m.config != nil {
-		// Use configured path with automatic migration
-		events, err = storage.LoadEventsWithConfig(m.config.GetEventsFilePath())
+	migrating := false
+	if m.ephemeral {
+		// Start from the seed file, or with no events at all
+		events = []models.Event{}
+		if m.config.SeedFile != "" {
+			events, err = storage.LoadImportFile(m.config.SeedFile)
+		}
+	} else if m.config != nil && m.dryRun {
+		// Read a legacy file that would be migrated without writing the JSON file
+		var legacyFile string
+		if legacyFile, migrating = storage.PendingMigration(m.config.GetEventsFilePath()); migrating {
+			events, err = storage.LoadEventsFromFile(legacyFile)
+		} else {
+			events, err = storage.LoadEventsWithConfig(m.config.GetEventsFilePath())
+		}
+	} else if m.config != nil {
+		// Use configured path with automatic migration, backing up the legacy file first
+		if legacyFile, ok := storage.PendingMigration(m.config.GetEventsFilePath()); ok {
+			err = m.backup(legacyFile)
+		}
+		if err == nil {
+			events, err = storage.LoadEventsWithConfig(m.config.GetEventsFilePath())
+		}
 	} else {
 		// Fallback to legacy text format
 		events, err = storage.LoadEvents()
 	}
 
+	if err == nil && !m.ephemeral {
+		events, err = m.loadCalendars(events)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to load events: %v", err)
 	}
 
+	// Deletions not written yet stay deleted in the reloaded events
+	events = m.withoutPendingDeletes(events)
+
 	m.events = events
+	m.replaced()
+	m.recordStamps()
+	if m.dryRun {
+		// A pending migration would create the JSON file, so all its events are new
+		m.loaded = nil
+		if !migrating {
+			m.loaded = append([]models.Event(nil), events...)
+		}
+	}
 	return nil
 }
 
@@ -60,41 +198,76 @@ func (m *Manager) GetAllEvents() []models.Event {
 	return m.events
 }
 
-// GetEventsForDate returns all events for a specific date, sorted by time ascending
+// GetEventsForDate returns the visible events occurring on a specific date, including
+// events spanning several days, with all-day events first and the others sorted by
+// time ascending
 func (m *Manager) GetEventsForDate(date time.Time) []models.Event {
 	var dateEvents []models.Event
-	targetDate := calendar.NormalizeDate(date)
 
-	for _, event := range m.events {
-		eventDate := calendar.NormalizeDate(event.Date)
-		if eventDate.Equal(targetDate) {
+	m.dateIndex().on(date, func(event models.Event) bool {
+		if m.visible(event) {
 			dateEvents = append(dateEvents, event)
 		}
-	}
+		return true
+	})
 
-	// Sort events by time ascending
+	// Sort all-day events first, then events by time ascending
 	sort.Slice(dateEvents, func(i, j int) bool {
+		if dateEvents[i].AllDay != dateEvents[j].AllDay {
+			return dateEvents[i].AllDay
+		}
 		return dateEvents[i].Time.Before(dateEvents[j].Time)
 	})
 
 	return dateEvents
 }
 
-// HasEventsForDate checks if there are any events for a specific date
+// HasEventsForDate checks if there are any visible events occurring on a specific date
 func (m *Manager) HasEventsForDate(date time.Time) bool {
-	targetDate := calendar.NormalizeDate(date)
-
-	for _, event := range m.events {
-		eventDate := calendar.NormalizeDate(event.Date)
-		if eventDate.Equal(targetDate) {
-			return true
-		}
-	}
-	return false
+	found := false
+	m.dateIndex().on(date, func(event models.Event) bool {
+		found = m.visible(event)
+		return !found
+	})
+	return found
 }
 
 // AddEvent adds a new event with validation and persistence
 func (m *Manager) AddEvent(date time.Time, timeStr, description string) error {
+	return m.AddEventWithDuration(date, timeStr, description, 0)
+}
+
+// AddEventWithDuration adds an event lasting duration, validated like AddEvent
+func (m *Manager) AddEventWithDuration(date time.Time, timeStr, description string, duration time.Duration) error {
+	return m.addEvent(models.Event{Date: date, Duration: duration}, timeStr, description)
+}
+
+// AddConfirmedEvent adds an event like AddEventWithDuration whose unusual time or
+// duration was confirmed, so it is not warned about again, see TimeWarning
+func (m *Manager) AddConfirmedEvent(date time.Time, timeStr, description string, duration time.Duration) error {
+	return m.addEvent(models.Event{Date: date, Duration: duration, TimeChecked: true}, timeStr, description)
+}
+
+// AddMultiDayEvent adds an event occurring on every day from start to end. An all-day
+// event has no time; the others start at timeStr on the first day.
+func (m *Manager) AddMultiDayEvent(start, end time.Time, timeStr, description string, allDay bool) error {
+	if allDay {
+		timeStr = "00:00"
+	}
+	event := models.Event{Date: start, AllDay: allDay}
+	if !calendar.IsSameDate(start, end) {
+		// Validation rejects an end before the start
+		event.EndDate = calendar.NormalizeDate(end)
+	}
+	return m.addEvent(event, timeStr, description)
+}
+
+// addEvent validates, stores and adds an event with the time and description given,
+// taking its date and other attributes from event
+func (m *Manager) addEvent(event models.Event, timeStr, description string) error {
+	// Apply description normalization rules before validating
+	description = m.ApplyNormalization(description)
+
 	// Validate time string format
 	if !calendar.ValidateTimeString(timeStr) {
 		return fmt.Errorf("invalid time format '%s': expected HH:MM", timeStr)
@@ -111,12 +284,10 @@ func (m *Manager) AddEvent(date time.Time, timeStr, description string) error {
 		return fmt.Errorf("failed to parse time '%s': %v", timeStr, err)
 	}
 
-	// Create event
-	event := models.Event{
-		Date:        date,
-		Time:        eventTime,
-		Description: description,
-	}
+	// Complete the event
+	event.Time = eventTime
+	event.Description = description
+	event.Calendar = m.addCalendar
 
 	// Validate the complete event
 	if err := storage.ValidateEvent(event); err != nil {
@@ -124,20 +295,14 @@ func (m *Manager) AddEvent(date time.Time, timeStr, description string) error {
 	}
 
 	// Save to storage
-	if m.config != nil {
-		if err := storage.SaveEventWithConfig(event, m.config.GetEventsFilePath()); err != nil {
-			return fmt.Errorf("failed to save event: %v", err)
-		}
-	} else {
-		// Fallback to legacy format
-		if err := storage.SaveEvent(event); err != nil {
-			return fmt.Errorf("failed to save event: %v", err)
-		}
+	if err := m.saveEvent(event); err != nil {
+		return fmt.Errorf("failed to save event: %v", err)
 	}
 
 	// Add to in-memory collection
 	m.events = append(m.events, event)
 
+	m.notifyChange(ChangeAdded, models.Event{}, event)
 	return nil
 }
 
@@ -146,14 +311,14 @@ func (m *Manager) GetEventCount() int {
 	return len(m.events)
 }
 
-// GetEventsForMonth returns all events for a specific month, sorted by date and time
+// GetEventsForMonth returns the visible events for a specific month, sorted by date and time
 func (m *Manager) GetEventsForMonth(month time.Time) []models.Event {
 	var monthEvents []models.Event
 	targetYear := month.Year()
 	targetMonth := month.Month()
 
 	for _, event := range m.events {
-		if event.Date.Year() == targetYear && event.Date.Month() == targetMonth {
+		if event.Date.Year() == targetYear && event.Date.Month() == targetMonth && m.visible(event) {
 			monthEvents = append(monthEvents, event)
 		}
 	}
@@ -169,13 +334,13 @@ func (m *Manager) GetEventsForMonth(month time.Time) []models.Event {
 	return monthEvents
 }
 
-// GetEventsInDateRange returns all events within a date range, sorted by date and time
+// GetEventsInDateRange returns the visible events within a date range, sorted by date and time
 func (m *Manager) GetEventsInDateRange(startDate, endDate time.Time) []models.Event {
 	var rangeEvents []models.Event
 
 	for _, event := range m.events {
 		eventDate := calendar.NormalizeDate(event.Date)
-		if !eventDate.Before(startDate) && !eventDate.After(endDate) {
+		if !eventDate.Before(startDate) && !eventDate.After(endDate) && m.visible(event) {
 			rangeEvents = append(rangeEvents, event)
 		}
 	}
@@ -191,6 +356,68 @@ func (m *Manager) GetEventsInDateRange(startDate, endDate time.Time) []models.Ev
 	return rangeEvents
 }
 
+// GetEventsTouchingDateRange returns the visible events taking any day of a date range,
+// including those spanning several days that start before it, sorted by date and time
+func (m *Manager) GetEventsTouchingDateRange(startDate, endDate time.Time) []models.Event {
+	var rangeEvents []models.Event
+
+	for _, event := range m.events {
+		if !event.LastDate().Before(startDate) && !calendar.NormalizeDate(event.Date).After(endDate) && m.visible(event) {
+			rangeEvents = append(rangeEvents, event)
+		}
+	}
+
+	sort.Slice(rangeEvents, func(i, j int) bool {
+		if rangeEvents[i].Date.Equal(rangeEvents[j].Date) {
+			return rangeEvents[i].Time.Before(rangeEvents[j].Time)
+		}
+		return rangeEvents[i].Date.Before(rangeEvents[j].Date)
+	})
+
+	return rangeEvents
+}
+
+// FlaggedEvents returns the visible flagged events of all dates, sorted by date and time
+func (m *Manager) FlaggedEvents() []models.Event {
+	var flagged []models.Event
+
+	for _, event := range m.events {
+		if event.Flagged && m.visible(event) {
+			flagged = append(flagged, event)
+		}
+	}
+
+	sort.Slice(flagged, func(i, j int) bool {
+		if flagged[i].Date.Equal(flagged[j].Date) {
+			return flagged[i].Time.Before(flagged[j].Time)
+		}
+		return flagged[i].Date.Before(flagged[j].Date)
+	})
+
+	return flagged
+}
+
+// UnreviewedEvents returns the visible events waiting in the review queue, sorted by
+// date and time
+func (m *Manager) UnreviewedEvents() []models.Event {
+	var unreviewed []models.Event
+
+	for _, event := range m.events {
+		if event.Unreviewed && m.visible(event) {
+			unreviewed = append(unreviewed, event)
+		}
+	}
+
+	sort.Slice(unreviewed, func(i, j int) bool {
+		if unreviewed[i].Date.Equal(unreviewed[j].Date) {
+			return unreviewed[i].Time.Before(unreviewed[j].Time)
+		}
+		return unreviewed[i].Date.Before(unreviewed[j].Date)
+	})
+
+	return unreviewed
+}
+
 // ReloadEvents reloads events from storage (useful for external file changes)
 func (m *Manager) ReloadEvents() error {
 	return m.LoadEvents()
@@ -199,25 +426,15 @@ func (m *Manager) ReloadEvents() error {
 // DeleteEvent deletes an event from both storage and memory
 func (m *Manager) DeleteEvent(eventToDelete models.Event) error {
 	// Delete from storage first
-	if m.config != nil {
-		if err := storage.DeleteEventWithConfig(eventToDelete, m.config.GetEventsFilePath()); err != nil {
-			return fmt.Errorf("failed to delete event from storage: %v", err)
-		}
-	} else {
-		// Fallback to legacy format
-		if err := storage.DeleteEvent(eventToDelete); err != nil {
-			return fmt.Errorf("failed to delete event from storage: %v", err)
-		}
+	if err := m.deleteStored(eventToDelete); err != nil {
+		return fmt.Errorf("failed to delete event from storage: %v", err)
 	}
 
 	// Remove from in-memory collection
 	var updatedEvents []models.Event
 	found := false
 	for _, event := range m.events {
-		// Compare events by date, time, and description
-		if event.Date.Equal(eventToDelete.Date) &&
-			event.Time.Equal(eventToDelete.Time) &&
-			event.Description == eventToDelete.Description {
+		if sameEvent(event, eventToDelete) {
 			found = true
 			continue // Skip this event (delete it)
 		}
@@ -229,11 +446,47 @@ func (m *Manager) DeleteEvent(eventToDelete models.Event) error {
 	}
 
 	m.events = updatedEvents
+	m.notifyChange(ChangeDeleted, eventToDelete, models.Event{})
 	return nil
 }
 
+// DeleteEvents removes several events with a single storage write; events that are
+// not stored are ignored. It returns the number of removed events.
+func (m *Manager) DeleteEvents(toDelete []models.Event) (int, error) {
+	var kept, removed []models.Event
+	for _, event := range m.events {
+		if containsEvent(toDelete, event) {
+			removed = append(removed, event)
+			continue
+		}
+		kept = append(kept, event)
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+
+	if err := m.saveAll(kept); err != nil {
+		return 0, fmt.Errorf("failed to delete events from storage: %v", err)
+	}
+
+	m.events = kept
+	for _, event := range removed {
+		m.notifyChange(ChangeDeleted, event, models.Event{})
+	}
+	return len(removed), nil
+}
+
 // EditEvent replaces an existing event with a new one in both storage and memory
 func (m *Manager) EditEvent(oldEvent models.Event, date time.Time, timeStr, description string) error {
+	return m.EditEventWithDuration(oldEvent, date, timeStr, description, oldEvent.Duration)
+}
+
+// EditEventWithDuration edits an event like EditEvent and sets its duration, the time
+// up to its end; zero leaves it without an end
+func (m *Manager) EditEventWithDuration(oldEvent models.Event, date time.Time, timeStr, description string, duration time.Duration) error {
+	// Apply description normalization rules before validating
+	description = m.ApplyNormalization(description)
+
 	// Validate time string format
 	if !calendar.ValidateTimeString(timeStr) {
 		return fmt.Errorf("invalid time format '%s': expected HH:MM", timeStr)
@@ -250,37 +503,31 @@ func (m *Manager) EditEvent(oldEvent models.Event, date time.Time, timeStr, desc
 		return fmt.Errorf("failed to parse time '%s': %v", timeStr, err)
 	}
 
-	// Create new event
-	newEvent := models.Event{
-		Date:        date,
-		Time:        eventTime,
-		Description: description,
-	}
+	// Create new event, keeping attributes that are not edited here (such as category)
+	newEvent := oldEvent
+	newEvent.Date = date
+	newEvent.EndDate = shiftEndDate(oldEvent, date)
+	newEvent.Time = eventTime
+	newEvent.Description = description
+	newEvent.Duration = duration
 
 	// Validate the complete new event
 	if err := storage.ValidateEvent(newEvent); err != nil {
 		return fmt.Errorf("new event validation failed: %v", err)
 	}
+	if !sameEvent(oldEvent, newEvent) && m.containsEvent(newEvent) {
+		return fmt.Errorf("an identical event already exists on %s", newEvent.GetDateString())
+	}
 
 	// Update in storage first
-	if m.config != nil {
-		if err := storage.UpdateEventWithConfig(oldEvent, newEvent, m.config.GetEventsFilePath()); err != nil {
-			return fmt.Errorf("failed to update event in storage: %v", err)
-		}
-	} else {
-		// Fallback to legacy format
-		if err := storage.UpdateEvent(oldEvent, newEvent); err != nil {
-			return fmt.Errorf("failed to update event in storage: %v", err)
-		}
+	if err := m.updateEvent(oldEvent, newEvent); err != nil {
+		return fmt.Errorf("failed to update event in storage: %v", err)
 	}
 
 	// Update in-memory collection
 	found := false
 	for i, event := range m.events {
-		// Compare events by date, time, and description
-		if event.Date.Equal(oldEvent.Date) &&
-			event.Time.Equal(oldEvent.Time) &&
-			event.Description == oldEvent.Description {
+		if sameEvent(event, oldEvent) {
 			m.events[i] = newEvent
 			found = true
 			break
@@ -291,32 +538,380 @@ func (m *Manager) EditEvent(oldEvent models.Event, date time.Time, timeStr, desc
 		return fmt.Errorf("event not found in memory for update")
 	}
 
+	m.notifyChange(ChangeEdited, oldEvent, newEvent)
+	return nil
+}
+
+// SetEventCategory assigns a category to an existing event (empty clears it)
+func (m *Manager) SetEventCategory(event models.Event, category string) error {
+	newEvent := event
+	newEvent.Category = category
+
+	if err := m.replaceEvent(event, newEvent); err != nil {
+		return fmt.Errorf("failed to update event category: %v", err)
+	}
 	return nil
 }
 
-// SearchEvents searches for events containing the query string in their description
-func (m *Manager) SearchEvents(query string) []models.Event {
-	if query == "" {
-		return []models.Event{}
+// ToggleFlag flags an existing event for follow-up, or clears its flag, and returns
+// the updated event
+func (m *Manager) ToggleFlag(event models.Event) (models.Event, error) {
+	toggled := event
+	toggled.Flagged = !event.Flagged
+
+	if err := m.replaceEvent(event, toggled); err != nil {
+		return models.Event{}, fmt.Errorf("failed to update event flag: %v", err)
 	}
+	return toggled, nil
+}
 
-	var matchingEvents []models.Event
-	lowerQuery := strings.ToLower(query)
+// AcceptEvent takes an event out of the review queue with the given category and
+// returns the accepted event
+func (m *Manager) AcceptEvent(event models.Event, category string) (models.Event, error) {
+	accepted := event
+	accepted.Category = category
+	accepted.Unreviewed = false
 
-	for _, event := range m.events {
-		// Search in description (case-insensitive)
-		if strings.Contains(strings.ToLower(event.Description), lowerQuery) {
-			matchingEvents = append(matchingEvents, event)
+	if err := m.replaceEvent(event, accepted); err != nil {
+		return models.Event{}, fmt.Errorf("failed to accept event: %v", err)
+	}
+	return accepted, nil
+}
+
+// MoveEvent reschedules an existing event by a number of days, keeping its time and
+// all other attributes, and returns the moved event. It fails when the target date
+// already holds an identical event.
+func (m *Manager) MoveEvent(event models.Event, days int) (models.Event, error) {
+	moved := event
+	moved.Date = event.Date.AddDate(0, 0, days)
+	moved.EndDate = shiftEndDate(event, moved.Date)
+	if days != 0 && m.containsEvent(moved) {
+		return models.Event{}, fmt.Errorf("an identical event already exists on %s", moved.GetDateString())
+	}
+
+	if err := m.replaceEvent(event, moved); err != nil {
+		return models.Event{}, fmt.Errorf("failed to move event: %v", err)
+	}
+	return moved, nil
+}
+
+// shiftEndDate returns the end date of a multi-day event moved to start on date, so it
+// keeps its length; events on a single day keep no end date
+func shiftEndDate(event models.Event, date time.Time) time.Time {
+	if !event.IsMultiDay() {
+		return time.Time{}
+	}
+	days := calendar.DaysBetween(event.Date, date)
+	return event.LastDate().AddDate(0, 0, days)
+}
+
+// ReplaceEvent swaps an existing event for newEvent with all of its attributes, as when
+// a synced copy of it changed elsewhere
+func (m *Manager) ReplaceEvent(oldEvent, newEvent models.Event) error {
+	if err := storage.ValidateEvent(newEvent); err != nil {
+		return fmt.Errorf("invalid event: %v", err)
+	}
+	if !sameEvent(oldEvent, newEvent) && m.containsEvent(newEvent) {
+		return fmt.Errorf("an identical event already exists")
+	}
+	return m.replaceEvent(oldEvent, newEvent)
+}
+
+// replaceEvent swaps an existing event for newEvent as-is in both storage and memory
+func (m *Manager) replaceEvent(oldEvent, newEvent models.Event) error {
+	// Update in storage first (the legacy format only persists date, time, and description)
+	if err := m.updateEvent(oldEvent, newEvent); err != nil {
+		return fmt.Errorf("failed to update event in storage: %v", err)
+	}
+
+	// Update in-memory collection
+	for i, existing := range m.events {
+		if sameEvent(existing, oldEvent) {
+			m.events[i] = newEvent
+			m.notifyChange(ChangeEdited, oldEvent, newEvent)
+			return nil
 		}
 	}
 
-	// Sort events by date, then by time
-	sort.Slice(matchingEvents, func(i, j int) bool {
-		if matchingEvents[i].Date.Equal(matchingEvents[j].Date) {
-			return matchingEvents[i].Time.Before(matchingEvents[j].Time)
+	return fmt.Errorf("event not found in memory for update")
+}
+
+// RestoreEvent stores a previously removed event again, keeping all of its attributes
+func (m *Manager) RestoreEvent(event models.Event) error {
+	if m.containsEvent(event) {
+		return fmt.Errorf("event already exists")
+	}
+
+	// A deletion that was not written yet only needs to be dropped
+	if m.takePendingDelete(event) {
+		m.events = append(m.events, event)
+		m.notifyChange(ChangeAdded, models.Event{}, event)
+		return nil
+	}
+
+	if err := m.saveEvent(event); err != nil {
+		return fmt.Errorf("failed to save event: %v", err)
+	}
+
+	m.events = append(m.events, event)
+	m.notifyChange(ChangeAdded, models.Event{}, event)
+	return nil
+}
+
+// ImportEvents adds events that are not already stored and persists them.
+// Duplicates (same date, time and description) are skipped; the number of imported events is returned.
+func (m *Manager) ImportEvents(imported []models.Event) (int, error) {
+	var added []models.Event
+	for _, event := range imported {
+		event.Description = m.ApplyNormalization(event.Description)
+		if err := storage.ValidateEvent(event); err != nil {
+			return 0, fmt.Errorf("invalid imported event: %v", err)
+		}
+		if m.containsEvent(event) || containsEvent(added, event) {
+			continue
+		}
+		added = append(added, event)
+	}
+
+	if len(added) == 0 {
+		return 0, nil
+	}
+
+	// Persist the whole collection in one write
+	all := append(append([]models.Event(nil), m.events...), added...)
+	if err := m.saveAll(all); err != nil {
+		return 0, fmt.Errorf("failed to save imported events: %v", err)
+	}
+
+	m.events = all
+	for _, event := range added {
+		m.notifyChange(ChangeAdded, models.Event{}, event)
+	}
+	return len(added), nil
+}
+
+// ImportBySource imports events that carry a Source identity, such as tasks from a
+// todo.txt file. Events whose source is not stored yet are added; already imported
+// ones are left alone unless update is set. With update, a change at the source is
+// applied when the event was not edited in the calendar since its last import, local
+// edits are kept when the source did not change, and when both changed the source's
+// version is queued on the event as a conflict for the user to resolve. All changes
+// are persisted in one write.
+func (m *Manager) ImportBySource(imported []models.Event, update bool) (added, updated, conflicts int, err error) {
+	all := append([]models.Event(nil), m.events...)
+	bySource := make(map[string]int)
+	for i, event := range all {
+		if event.Source != "" {
+			bySource[event.Source] = i
+		}
+	}
+
+	type change struct{ before, after models.Event }
+	var changes []change
+	for _, event := range imported {
+		event.Description = m.ApplyNormalization(event.Description)
+		if err := storage.ValidateEvent(event); err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid imported event: %v", err)
 		}
-		return matchingEvents[i].Date.Before(matchingEvents[j].Date)
+
+		index, exists := bySource[event.Source]
+		if !exists || event.Source == "" {
+			event.ImportBase = importBase(event)
+			bySource[event.Source] = len(all)
+			all = append(all, event)
+			changes = append(changes, change{models.Event{}, event})
+			added++
+			continue
+		}
+
+		existing := all[index]
+		if !update || sameImportedFields(existing, event) {
+			continue
+		}
+		if existing.Conflict != nil && sameImportedFields(*existing.Conflict, event) {
+			continue
+		}
+
+		// Without a base, events imported before bases were recorded follow the source
+		base := existing.ImportBase
+		if base != "" && base == importBase(event) {
+			continue // Only the calendar changed
+		}
+		if base != "" && base != importBase(existing) {
+			remote := event
+			conflicted := existing
+			conflicted.Conflict = &remote
+			conflicted.Unreviewed = true
+			all[index] = conflicted
+			changes = append(changes, change{existing, conflicted})
+			conflicts++
+			continue
+		}
+
+		// Keep attributes that only exist in the calendar, such as the category, and
+		// the calendar the event was filed in
+		event.Category = existing.Category
+		event.Calendar = existing.Calendar
+		event.Command = existing.Command
+		if event.Reminder == 0 {
+			// Sources without alarms, such as todo.txt, keep the reminder set here
+			event.Reminder = existing.Reminder
+		}
+		if event.Notes == "" {
+			// Likewise for notes, which todo.txt has none of
+			event.Notes = existing.Notes
+		}
+		event.ImportBase = importBase(event)
+		all[index] = event
+		changes = append(changes, change{existing, event})
+		updated++
+	}
+
+	if len(changes) == 0 {
+		return 0, 0, 0, nil
+	}
+
+	// Persist the whole collection in one write
+	if err := m.saveAll(all); err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to save imported events: %v", err)
+	}
+
+	m.events = all
+	for _, c := range changes {
+		if c.before.Source == "" {
+			m.notifyChange(ChangeAdded, models.Event{}, c.after)
+		} else {
+			m.notifyChange(ChangeEdited, c.before, c.after)
+		}
+	}
+	return added, updated, conflicts, nil
+}
+
+// persist runs a storage write; in dry-run and ephemeral mode it is skipped and changes
+// stay in memory. With write recovery a failed write is kept in memory too, and while
+// one is unsaved writes wait for RetryWrite.
+func (m *Manager) persist(write func() error) error {
+	if m.ReadOnly() || m.unsaved != nil {
+		return nil
+	}
+	if m.beforeWrite != nil {
+		m.beforeWrite()
+	}
+	err := write()
+	if err == nil {
+		m.recordStamps()
+	}
+	if err != nil && m.writeRecovery {
+		m.recordWriteFailure(err, time.Now())
+		return nil
+	}
+	return err
+}
+
+// SetBeforeWrite registers hook to be called before every write of the events files
+func (m *Manager) SetBeforeWrite(hook func()) {
+	m.beforeWrite = hook
+}
+
+// Rewrite writes the events in memory back to their files, e.g. after a sync replaced
+// files holding changes made meanwhile. Deletions waiting for their undo delay stay
+// pending, so their events are written too.
+func (m *Manager) Rewrite() error {
+	all := m.storedEvents()
+	return m.persist(func() error {
+		return m.writeAll(all)
+	})
+}
+
+// storedEvents returns the events that belong in the events files: the events in memory
+// and those whose deletion is still pending
+func (m *Manager) storedEvents() []models.Event {
+	all := append([]models.Event(nil), m.events...)
+	for _, pending := range m.pendingDeletes {
+		all = append(all, pending.Event)
+	}
+	return all
+}
+
+// saveEvent appends a single event to storage
+func (m *Manager) saveEvent(event models.Event) error {
+	return m.persist(func() error {
+		if m.config != nil {
+			return storage.SaveEventWithConfig(event, m.config.CalendarFilePath(event.Calendar))
+		}
+		return storage.SaveEvent(event) // Fallback to legacy format
+	})
+}
+
+// updateEvent replaces a single event in storage
+func (m *Manager) updateEvent(oldEvent, newEvent models.Event) error {
+	return m.persist(func() error {
+		if m.config != nil && oldEvent.Calendar != newEvent.Calendar {
+			return m.moveToCalendar(oldEvent, newEvent)
+		}
+		if m.config != nil {
+			path := m.config.CalendarFilePath(newEvent.Calendar)
+			if err := m.backup(path); err != nil {
+				return err
+			}
+			return storage.UpdateEventWithConfig(oldEvent, newEvent, path)
+		}
+		return storage.UpdateEvent(oldEvent, newEvent) // Fallback to legacy format
+	})
+}
+
+// deleteStored removes a single event from storage
+func (m *Manager) deleteStored(event models.Event) error {
+	return m.persist(func() error {
+		if m.config != nil {
+			path := m.config.CalendarFilePath(event.Calendar)
+			if err := m.backup(path); err != nil {
+				return err
+			}
+			return storage.DeleteEventWithConfig(event, path)
+		}
+		return storage.DeleteEvent(event) // Fallback to legacy format
 	})
+}
+
+// saveAll replaces the stored collection with events in one write. The collection is
+// built from memory, so pending deletions are written along with it.
+func (m *Manager) saveAll(events []models.Event) error {
+	err := m.persist(func() error {
+		return m.writeAll(events)
+	})
+	if err == nil {
+		m.pendingDeletes = nil
+	}
+	return err
+}
+
+// sameImportedFields reports whether an import would leave an event unchanged
+func sameImportedFields(existing, imported models.Event) bool {
+	return existing.Date.Equal(imported.Date) &&
+		existing.Time.Equal(imported.Time) &&
+		existing.Description == imported.Description &&
+		existing.Priority == imported.Priority
+}
+
+// containsEvent reports whether the manager already holds an identical event
+func (m *Manager) containsEvent(event models.Event) bool {
+	return containsEvent(m.events, event)
+}
+
+// containsEvent reports whether list holds the same event, see sameEvent
+func containsEvent(list []models.Event, event models.Event) bool {
+	for _, existing := range list {
+		if sameEvent(existing, event) {
+			return true
+		}
+	}
+	return false
+}
 
-	return matchingEvents
+// sameEvent reports whether two events are the same event: they have the same date,
+// time and description and belong to the same calendar, as identical events of two
+// calendars are kept apart
+func sameEvent(a, b models.Event) bool {
+	return a.Date.Equal(b.Date) && a.Time.Equal(b.Time) && a.Description == b.Description && a.Calendar == b.Calendar
 }
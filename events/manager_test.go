@@ -1,6 +1,7 @@
 package events
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +12,27 @@ import (
 	"go-ascii-calendar/models"
 )
 
+// TestMain runs the tests in a temporary directory: managers created without a
+// configuration keep their events in events.txt in the working directory
+func TestMain(m *testing.M) {
+	os.Exit(runInTempDir(m))
+}
+
+// runInTempDir runs the tests with a temporary working directory, removed afterwards
+func runInTempDir(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "events_test")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create a temp dir: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to enter %s: %v\n", dir, err)
+		return 1
+	}
+	return m.Run()
+}
+
 func TestNewManager(t *testing.T) {
 	manager := NewManager()
 
@@ -253,6 +275,27 @@ func TestManager_GetEventsInDateRange(t *testing.T) {
 	}
 }
 
+func TestManager_GetEventsTouchingDateRange(t *testing.T) {
+	manager := NewManager()
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.Local) }
+	manager.events = []models.Event{
+		{Date: day(1), EndDate: day(9), Description: "Ends before range"},
+		{Date: day(12), Description: "In range"},
+		{Date: day(5), EndDate: day(11), AllDay: true, Description: "Started before range"},
+		{Date: day(20), EndDate: day(24), Description: "Starts on last day"},
+		{Date: day(21), Description: "After range"},
+	}
+
+	events := manager.GetEventsTouchingDateRange(day(10), day(20))
+	var got []string
+	for _, event := range events {
+		got = append(got, event.Description)
+	}
+	if want := "Started before range,In range,Starts on last day"; strings.Join(got, ",") != want {
+		t.Errorf("GetEventsTouchingDateRange() = %v, want %s", got, want)
+	}
+}
+
 func TestManager_AddEvent(t *testing.T) {
 	manager := NewManager()
 	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)
@@ -395,3 +438,366 @@ func TestManager_ReloadEvents(t *testing.T) {
 		t.Errorf("ReloadEvents() failed with unexpected error: %v", err)
 	}
 }
+
+func TestManager_ChangeListeners(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "events_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := NewManagerWithConfig(cfg)
+
+	var kinds []ChangeKind
+	manager.AddChangeListener(func(kind ChangeKind, before, after models.Event) {
+		kinds = append(kinds, kind)
+	})
+
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	if err := manager.AddEvent(testDate, "10:00", "Listener test"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	added := manager.GetEventsForDate(testDate)[0]
+
+	if err := manager.EditEvent(added, testDate, "11:00", "Listener test edited"); err != nil {
+		t.Fatalf("EditEvent() failed: %v", err)
+	}
+	edited := manager.GetEventsForDate(testDate)[0]
+
+	if err := manager.DeleteEvent(edited); err != nil {
+		t.Fatalf("DeleteEvent() failed: %v", err)
+	}
+
+	// Failed mutations must not notify listeners
+	manager.AddEvent(testDate, "99:00", "Invalid")
+
+	expected := []ChangeKind{ChangeAdded, ChangeEdited, ChangeDeleted}
+	if len(kinds) != len(expected) {
+		t.Fatalf("Listener called %d times, want %d", len(kinds), len(expected))
+	}
+	for i, kind := range expected {
+		if kinds[i] != kind {
+			t.Errorf("Change %d = %v, want %v", i, kinds[i], kind)
+		}
+	}
+}
+
+func TestManager_SetEventCategory(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "events_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := NewManagerWithConfig(cfg)
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+
+	if err := manager.AddEvent(testDate, "10:00", "Planning"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	event := manager.GetEventsForDate(testDate)[0]
+
+	if err := manager.SetEventCategory(event, "work"); err != nil {
+		t.Fatalf("SetEventCategory() failed: %v", err)
+	}
+	if got := manager.GetEventsForDate(testDate)[0].Category; got != "work" {
+		t.Errorf("Category = %q, want 'work'", got)
+	}
+
+	// Editing time or description keeps the category
+	categorized := manager.GetEventsForDate(testDate)[0]
+	if err := manager.EditEvent(categorized, testDate, "11:00", "Planning session"); err != nil {
+		t.Fatalf("EditEvent() failed: %v", err)
+	}
+
+	reloaded := NewManagerWithConfig(cfg)
+	if err := reloaded.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	if got := reloaded.GetEventsForDate(testDate)[0].Category; got != "work" {
+		t.Errorf("Persisted category = %q, want 'work'", got)
+	}
+}
+
+func TestManager_ToggleFlag(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "events_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := NewManagerWithConfig(cfg)
+	laterDate := time.Date(2025, 8, 20, 0, 0, 0, 0, time.Local)
+	earlierDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+
+	for _, date := range []time.Time{laterDate, earlierDate} {
+		if err := manager.AddEvent(date, "10:00", "Call back"); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+		if _, err := manager.ToggleFlag(manager.GetEventsForDate(date)[0]); err != nil {
+			t.Fatalf("ToggleFlag() failed: %v", err)
+		}
+	}
+	if err := manager.AddEvent(earlierDate, "09:00", "Not flagged"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+
+	flagged := manager.FlaggedEvents()
+	if len(flagged) != 2 || !flagged[0].Date.Equal(earlierDate) || !flagged[1].Date.Equal(laterDate) {
+		t.Fatalf("FlaggedEvents() = %v, want the two flagged events, earliest first", flagged)
+	}
+
+	reloaded := NewManagerWithConfig(cfg)
+	if err := reloaded.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	if got := len(reloaded.FlaggedEvents()); got != 2 {
+		t.Errorf("Persisted flags: %d flagged events, want 2", got)
+	}
+
+	// Toggling again clears the flag
+	cleared, err := manager.ToggleFlag(flagged[0])
+	if err != nil || cleared.Flagged {
+		t.Fatalf("ToggleFlag() on a flagged event = %v, %v; want the flag cleared", cleared, err)
+	}
+	if got := manager.FlaggedEvents(); len(got) != 1 || !got[0].Date.Equal(laterDate) {
+		t.Errorf("FlaggedEvents() after clearing = %v, want the later event only", got)
+	}
+}
+
+func TestManager_AcceptEvent(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "events_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := NewManagerWithConfig(cfg)
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	at := func(hour int) time.Time { return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC) }
+
+	if err := manager.AddEvent(date, "08:00", "Typed in"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	imported := []models.Event{
+		{Date: date, Time: at(14), Description: "Imported later", Category: "work", Unreviewed: true},
+		{Date: date, Time: at(9), Description: "Imported earlier", Unreviewed: true},
+	}
+	if _, err := manager.ImportEvents(imported); err != nil {
+		t.Fatalf("ImportEvents() failed: %v", err)
+	}
+
+	queue := manager.UnreviewedEvents()
+	if len(queue) != 2 || queue[0].Description != "Imported earlier" || queue[1].Description != "Imported later" {
+		t.Fatalf("UnreviewedEvents() = %v, want the two imported events, earliest first", queue)
+	}
+
+	accepted, err := manager.AcceptEvent(queue[1], "personal")
+	if err != nil || accepted.Unreviewed || accepted.Category != "personal" {
+		t.Fatalf("AcceptEvent() = %v, %v; want a reviewed event in the personal category", accepted, err)
+	}
+
+	reloaded := NewManagerWithConfig(cfg)
+	if err := reloaded.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	if got := reloaded.UnreviewedEvents(); len(got) != 1 || got[0].Description != "Imported earlier" {
+		t.Errorf("Persisted queue = %v, want only the event not accepted yet", got)
+	}
+	for _, event := range reloaded.GetEventsForDate(date) {
+		if event.Description == "Imported later" && event.Category != "personal" {
+			t.Errorf("Accepted event has category %q, want personal", event.Category)
+		}
+	}
+}
+
+func TestManager_ImportEvents(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "events_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := NewManagerWithConfig(cfg)
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	testTime := time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	if err := manager.AddEvent(testDate, "09:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+
+	imported, err := manager.ImportEvents([]models.Event{
+		{Date: testDate, Time: testTime, Description: "Standup"}, // Duplicate of stored event
+		{Date: testDate, Time: testTime, Description: "Backup", Command: "echo backup"},
+		{Date: testDate, Time: testTime, Description: "Backup"}, // Duplicate within the import
+	})
+	if err != nil {
+		t.Fatalf("ImportEvents() failed: %v", err)
+	}
+	if imported != 1 {
+		t.Errorf("ImportEvents() = %d, want 1", imported)
+	}
+
+	reloaded := NewManagerWithConfig(cfg)
+	if err := reloaded.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	if reloaded.GetEventCount() != 2 {
+		t.Errorf("Persisted event count = %d, want 2", reloaded.GetEventCount())
+	}
+	for _, event := range reloaded.GetAllEvents() {
+		if event.Description == "Backup" && event.Command != "echo backup" {
+			t.Errorf("Imported command = %q, want 'echo backup'", event.Command)
+		}
+	}
+}
+
+func TestManager_ImportBySource(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "events_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := NewManagerWithConfig(cfg)
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	nine := time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	added, updated, _, err := manager.ImportBySource([]models.Event{
+		{Date: day, Time: nine, Description: "Pay rent", Priority: "C", Source: "todo:rent"},
+		{Date: day, Time: nine, Description: "Call dentist", Source: "todo:dentist"},
+	}, false)
+	if err != nil || added != 2 || updated != 0 {
+		t.Fatalf("First import = %d added, %d updated, %v; want 2, 0", added, updated, err)
+	}
+	rent := manager.SearchEvents("desc:rent")[0]
+	if err := manager.SetEventCategory(rent, "personal"); err != nil {
+		t.Fatalf("SetEventCategory() failed: %v", err)
+	}
+
+	// The rent moved and got a higher priority in the todo.txt file
+	changed := []models.Event{
+		{Date: day.AddDate(0, 0, 4), Time: nine, Description: "Pay rent", Priority: "A", Source: "todo:rent"},
+		{Date: day, Time: nine, Description: "Call dentist", Source: "todo:dentist"},
+	}
+	if added, updated, _, _ := manager.ImportBySource(changed, false); added != 0 || updated != 0 {
+		t.Errorf("Import without update = %d added, %d updated; want nothing", added, updated)
+	}
+
+	added, updated, _, err = manager.ImportBySource(changed, true)
+	if err != nil || added != 0 || updated != 1 {
+		t.Fatalf("Re-import = %d added, %d updated, %v; want 0, 1", added, updated, err)
+	}
+
+	reloaded := NewManagerWithConfig(cfg)
+	if err := reloaded.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	moved := reloaded.GetEventsForDate(day.AddDate(0, 0, 4))
+	if len(moved) != 1 || moved[0].Priority != "A" || moved[0].Category != "personal" {
+		t.Errorf("Re-imported rent = %+v, want moved with priority A and its category kept", moved)
+	}
+	if reloaded.GetEventCount() != 2 {
+		t.Errorf("Persisted event count = %d, want 2", reloaded.GetEventCount())
+	}
+}
+
+func TestManager_MoveEvent(t *testing.T) {
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true})
+	history := NewHistory(manager)
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+
+	if err := manager.AddEvent(testDate, "10:00", "Planning"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if err := manager.SetEventCategory(manager.GetEventsForDate(testDate)[0], "work"); err != nil {
+		t.Fatalf("SetEventCategory() failed: %v", err)
+	}
+
+	moved, err := manager.MoveEvent(manager.GetEventsForDate(testDate)[0], 7)
+	if err != nil {
+		t.Fatalf("MoveEvent() failed: %v", err)
+	}
+	nextWeek := testDate.AddDate(0, 0, 7)
+	if !moved.Date.Equal(nextWeek) || moved.Category != "work" || moved.GetTimeString() != "10:00" {
+		t.Errorf("MoveEvent() = %+v, want the categorized event a week later", moved)
+	}
+	if len(manager.GetEventsForDate(testDate)) != 0 || len(manager.GetEventsForDate(nextWeek)) != 1 {
+		t.Error("The event should only be on its new date")
+	}
+
+	// The move is undone as a single change
+	if _, err := history.UndoLatest(); err != nil {
+		t.Fatalf("UndoLatest() failed: %v", err)
+	}
+	if events := manager.GetEventsForDate(testDate); len(events) != 1 || events[0].Category != "work" {
+		t.Errorf("Events after undo = %v, want the categorized event back", events)
+	}
+	if _, err := history.UndoLatest(); err != nil {
+		t.Fatalf("UndoLatest() should go on with the category change: %v", err)
+	}
+	if events := manager.GetEventsForDate(testDate); len(events) != 1 || events[0].Category != "" {
+		t.Errorf("Events after the second undo = %v, want the uncategorized event", events)
+	}
+}
+
+func TestManager_AddEventWithDuration(t *testing.T) {
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true})
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)
+
+	if err := manager.AddEventWithDuration(date, "09:00", "Workshop", 3*time.Hour); err != nil {
+		t.Fatalf("AddEventWithDuration() failed: %v", err)
+	}
+	events := manager.GetEventsForDate(date)
+	if len(events) != 1 || events[0].Duration != 3*time.Hour || events[0].GetEndTimeString() != "12:00" {
+		t.Errorf("Added events = %+v, want a workshop until 12:00", events)
+	}
+
+	if err := manager.AddEventWithDuration(date, "10:00", "Negative", -time.Hour); err == nil {
+		t.Error("AddEventWithDuration() should reject a negative duration")
+	}
+}
+
+func TestManager_AddMultiDayEvent(t *testing.T) {
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true})
+	start := time.Date(2025, 8, 20, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, 4)
+
+	if err := manager.AddEvent(start.AddDate(0, 0, 2), "09:00", "Conference talk"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if err := manager.AddMultiDayEvent(start, end, "", "Trip to Lisbon", true); err != nil {
+		t.Fatalf("AddMultiDayEvent() failed: %v", err)
+	}
+
+	for offset := -1; offset <= 5; offset++ {
+		date := start.AddDate(0, 0, offset)
+		want := offset >= 0 && offset <= 4
+		if got := manager.HasEventsForDate(date); got != want {
+			t.Errorf("Events on %s = %v, want %v", date.Format("2006-01-02"), got, want)
+		}
+	}
+	if events := manager.GetEventsForDate(start.AddDate(0, 0, 2)); len(events) != 2 || !events[0].AllDay || events[1].Description != "Conference talk" {
+		t.Errorf("Events of a day within the trip = %+v, want the all-day trip before the talk", events)
+	}
+
+	// Moving keeps the length of the span
+	trip := manager.GetEventsForDate(start)[0]
+	moved, err := manager.MoveEvent(trip, 7)
+	if err != nil {
+		t.Fatalf("MoveEvent() failed: %v", err)
+	}
+	if !moved.LastDate().Equal(end.AddDate(0, 0, 7)) {
+		t.Errorf("Moved trip ends %s, want %s", moved.LastDate().Format("2006-01-02"), end.AddDate(0, 0, 7).Format("2006-01-02"))
+	}
+
+	if err := manager.AddMultiDayEvent(start, start.AddDate(0, 0, -1), "10:00", "Backwards", false); err == nil {
+		t.Error("AddMultiDayEvent() should reject an event ending before it starts")
+	}
+}
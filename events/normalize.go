@@ -0,0 +1,107 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+// NormalizeDescription applies the configured normalization rules to a description
+func NormalizeDescription(description string, rules config.NormalizationConfig) string {
+	result := description
+
+	if rules.CollapseSpaces {
+		result = collapseWhitespace(result)
+	}
+
+	if rules.TrimWhitespace {
+		result = strings.TrimSpace(result)
+	}
+
+	if rules.SentenceCase {
+		result = capitalizeFirstLetter(result)
+	}
+
+	return result
+}
+
+// collapseWhitespace replaces every run of whitespace with a single space
+func collapseWhitespace(s string) string {
+	var builder strings.Builder
+	inSpace := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if !inSpace {
+				builder.WriteRune(' ')
+			}
+			inSpace = true
+			continue
+		}
+		inSpace = false
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// capitalizeFirstLetter upper-cases the first letter, leaving the rest untouched
+func capitalizeFirstLetter(s string) string {
+	for i, r := range s {
+		if unicode.IsLetter(r) {
+			if unicode.IsUpper(r) {
+				return s
+			}
+			return s[:i] + string(unicode.ToUpper(r)) + s[i+utf8.RuneLen(r):]
+		}
+		if !unicode.IsSpace(r) {
+			// Leading punctuation or digits: leave the text as-is
+			return s
+		}
+	}
+	return s
+}
+
+// normalizationRules returns the rules from configuration, or the defaults without one
+func (m *Manager) normalizationRules() config.NormalizationConfig {
+	if m.config == nil {
+		return config.DefaultNormalization
+	}
+	return m.config.Normalization
+}
+
+// ApplyNormalization normalizes a description using the manager's configured rules
+func (m *Manager) ApplyNormalization(description string) string {
+	return NormalizeDescription(description, m.normalizationRules())
+}
+
+// NormalizeAllEvents rewrites stored descriptions using the configured rules.
+// It returns the number of events whose description changed.
+func (m *Manager) NormalizeAllEvents() (int, error) {
+	rules := m.normalizationRules()
+
+	changed := 0
+	normalized := make([]models.Event, len(m.events))
+	for i, event := range m.events {
+		normalized[i] = event
+		normalized[i].Description = NormalizeDescription(event.Description, rules)
+		if normalized[i].Description != event.Description {
+			changed++
+		}
+	}
+
+	if changed == 0 {
+		return 0, nil
+	}
+
+	// Persist the whole collection in one write
+	if err := m.saveAll(normalized); err != nil {
+		return 0, fmt.Errorf("failed to save normalized events: %v", err)
+	}
+
+	m.events = normalized
+	m.replaced()
+	return changed, nil
+}
@@ -0,0 +1,118 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+func TestNormalizeDescription(t *testing.T) {
+	all := config.NormalizationConfig{TrimWhitespace: true, CollapseSpaces: true, SentenceCase: true}
+
+	tests := []struct {
+		name        string
+		description string
+		rules       config.NormalizationConfig
+		expected    string
+	}{
+		{"No rules", "  team  sync ", config.NormalizationConfig{}, "  team  sync "},
+		{"Trim only", "  team  sync ", config.NormalizationConfig{TrimWhitespace: true}, "team  sync"},
+		{"Collapse only", "  team  \t sync ", config.NormalizationConfig{CollapseSpaces: true}, " team sync "},
+		{"Default rules", "  team  sync ", config.DefaultNormalization, "team sync"},
+		{"Sentence case", "team sync", config.NormalizationConfig{SentenceCase: true}, "Team sync"},
+		{"Sentence case keeps rest", "dentist at ACME", all, "Dentist at ACME"},
+		{"Leading digit untouched", "3pm call", all, "3pm call"},
+		{"Already capitalized", "Lunch", all, "Lunch"},
+		{"Unicode letter", "  élan  vital", all, "Élan vital"},
+		{"Only whitespace", "   ", all, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NormalizeDescription(tt.description, tt.rules)
+			if result != tt.expected {
+				t.Errorf("NormalizeDescription(%q) = %q, want %q", tt.description, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestManager_AddEvent_Normalizes(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "normalize_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{
+		EventsFilePath: filepath.Join(tempDir, "events.json"),
+		Normalization:  config.NormalizationConfig{TrimWhitespace: true, CollapseSpaces: true, SentenceCase: true},
+	}
+	manager := NewManagerWithConfig(cfg)
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+
+	if err := manager.AddEvent(testDate, "10:00", "  weekly   review "); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+
+	events := manager.GetEventsForDate(testDate)
+	if len(events) != 1 || events[0].Description != "Weekly review" {
+		t.Errorf("Stored description = %v, want 'Weekly review'", events)
+	}
+
+	// Whitespace-only descriptions must still be rejected after normalization
+	if err := manager.AddEvent(testDate, "11:00", "   "); err == nil {
+		t.Error("AddEvent() should fail with whitespace-only description")
+	}
+}
+
+func TestManager_NormalizeAllEvents(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "normalize_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	eventsPath := filepath.Join(tempDir, "events.json")
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	testTime := time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	stored := []models.Event{
+		{Date: testDate, Time: testTime, Description: "standup  meeting "},
+		{Date: testDate, Time: testTime, Description: "Already tidy"},
+	}
+	if err := storage.SaveEventsJSON(stored, eventsPath); err != nil {
+		t.Fatalf("Failed to seed events: %v", err)
+	}
+
+	cfg := &config.Config{
+		EventsFilePath: eventsPath,
+		Normalization:  config.NormalizationConfig{TrimWhitespace: true, CollapseSpaces: true, SentenceCase: true},
+	}
+	manager := NewManagerWithConfig(cfg)
+	if err := manager.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+
+	changed, err := manager.NormalizeAllEvents()
+	if err != nil {
+		t.Fatalf("NormalizeAllEvents() failed: %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("NormalizeAllEvents() changed = %d, want 1", changed)
+	}
+
+	// Changes must be persisted
+	reloaded, err := storage.LoadEventsJSON(eventsPath)
+	if err != nil {
+		t.Fatalf("Failed to reload events: %v", err)
+	}
+	if reloaded[0].Description != "Standup meeting" {
+		t.Errorf("Persisted description = %q, want 'Standup meeting'", reloaded[0].Description)
+	}
+}
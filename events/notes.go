@@ -0,0 +1,30 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+
+	"go-ascii-calendar/models"
+)
+
+// CleanNotes tidies notes as typed or pasted: Windows line breaks become plain ones and
+// trailing spaces and blank lines are dropped, so notes of only whitespace are empty
+func CleanNotes(notes string) string {
+	lines := strings.Split(strings.ReplaceAll(notes, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// SetEventNotes replaces the notes of an existing event, cleaned with CleanNotes, and
+// returns the updated event
+func (m *Manager) SetEventNotes(event models.Event, notes string) (models.Event, error) {
+	updated := event
+	updated.Notes = CleanNotes(notes)
+
+	if err := m.replaceEvent(event, updated); err != nil {
+		return models.Event{}, fmt.Errorf("failed to update event notes: %v", err)
+	}
+	return updated, nil
+}
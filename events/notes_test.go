@@ -0,0 +1,59 @@
+package events
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/storage"
+)
+
+func TestCleanNotes(t *testing.T) {
+	tests := []struct {
+		notes string
+		want  string
+	}{
+		{"", ""},
+		{"  \n\t\n", ""},
+		{"Agenda:\r\n- budget  \r\n- hiring\r\n\r\n", "Agenda:\n- budget\n- hiring"},
+		{"\n\nCall in first\n\n\nhttps://meet.example.com/abc", "Call in first\n\n\nhttps://meet.example.com/abc"},
+		{"  indented first line", "  indented first line"},
+	}
+
+	for _, tt := range tests {
+		if got := CleanNotes(tt.notes); got != tt.want {
+			t.Errorf("CleanNotes(%q) = %q, want %q", tt.notes, got, tt.want)
+		}
+	}
+}
+
+func TestManager_SetEventNotes(t *testing.T) {
+	cfg := &config.Config{EventsFilePath: filepath.Join(t.TempDir(), "events.json")}
+	manager := NewManagerWithConfig(cfg)
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	if err := manager.AddEvent(day, "10:00", "Planning"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+
+	updated, err := manager.SetEventNotes(manager.GetEventsForDate(day)[0], "Agenda:\n- budget \n")
+	if err != nil {
+		t.Fatalf("SetEventNotes() failed: %v", err)
+	}
+	if updated.Notes != "Agenda:\n- budget" {
+		t.Errorf("Notes = %q, want them cleaned", updated.Notes)
+	}
+
+	stored, err := storage.LoadEventsJSON(cfg.EventsFilePath)
+	if err != nil || len(stored) != 1 || stored[0].Notes != "Agenda:\n- budget" {
+		t.Fatalf("Stored events = %+v (%v), want the notes saved", stored, err)
+	}
+
+	// Notes are searched like the other fields
+	if results := manager.SearchEvents("budget"); len(results) != 1 {
+		t.Errorf("SearchEvents(budget) found %d events, want the event with the notes", len(results))
+	}
+	if results := manager.SearchEvents("notes:planning"); len(results) != 0 {
+		t.Errorf("SearchEvents(notes:planning) found %d events, want none", len(results))
+	}
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+// interval returns when an event starts and ends; an event without a duration takes
+// a minute, so two events starting at the same time overlap
+func interval(event models.Event) (time.Time, time.Time) {
+	start := event.Start(time.Local)
+	if event.Duration <= 0 {
+		return start, start.Add(time.Minute)
+	}
+	return start, start.Add(event.Duration)
+}
+
+// overlap reports whether two timed events share some of their time; all-day events
+// overlap nothing
+func overlap(a, b models.Event) bool {
+	if a.AllDay || b.AllDay {
+		return false
+	}
+	aStart, aEnd := interval(a)
+	bStart, bEnd := interval(b)
+	return aStart.Before(bEnd) && bStart.Before(aEnd)
+}
+
+// Overlapping reports for each of events, such as the events of one date, whether it
+// overlaps another one of them
+func Overlapping(events []models.Event) []bool {
+	overlapping := make([]bool, len(events))
+	for i := range events {
+		for j := i + 1; j < len(events); j++ {
+			if overlap(events[i], events[j]) {
+				overlapping[i], overlapping[j] = true, true
+			}
+		}
+	}
+	return overlapping
+}
+
+// Overlaps returns the other events on the date of event that overlap it, sorted by
+// time
+func (m *Manager) Overlaps(event models.Event) []models.Event {
+	var overlaps []models.Event
+	for _, other := range m.GetEventsForDate(event.Date) {
+		if !sameEvent(other, event) && overlap(other, event) {
+			overlaps = append(overlaps, other)
+		}
+	}
+	return overlaps
+}
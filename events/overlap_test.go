@@ -0,0 +1,45 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+func TestOverlapping(t *testing.T) {
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	at := func(clock string, duration time.Duration, description string) models.Event {
+		start, _ := time.Parse("15:04", clock)
+		return models.Event{Date: day, Time: start, Duration: duration, Description: description}
+	}
+	list := []models.Event{
+		{Date: day, Description: "Holiday", AllDay: true},
+		at("09:00", 30*time.Minute, "Standup"),
+		at("09:30", time.Hour, "Review"),
+		at("10:00", 0, "Call"),
+		at("12:00", 0, "Lunch"),
+		at("12:00", 0, "Delivery"),
+		at("15:00", time.Hour, "Workshop"),
+	}
+	want := []bool{false, false, true, true, true, true, false}
+	got := Overlapping(list)
+	for i := range list {
+		if got[i] != want[i] {
+			t.Errorf("Overlapping() of %s = %v, want %v", list[i].Description, got[i], want[i])
+		}
+	}
+
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true})
+	if err := manager.AddEventWithDuration(day, "09:30", "Review", time.Hour); err != nil {
+		t.Fatalf("AddEventWithDuration() failed: %v", err)
+	}
+	if err := manager.AddEvent(day, "10:00", "Call"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	overlaps := manager.Overlaps(manager.GetEventsForDate(day)[1])
+	if len(overlaps) != 1 || overlaps[0].Description != "Review" {
+		t.Errorf("Overlaps() of the call = %v, want the review", overlaps)
+	}
+}
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/models"
+)
+
+// PasteLine is one line of pasted text read as a quick-add event
+type PasteLine struct {
+	Text     string       // The line as pasted
+	Event    models.Event // The event it describes, when Err is nil
+	Err      error        // Why the line cannot be added
+	Accepted bool         // Whether the event is added when the paste is saved
+}
+
+// ParsePaste reads each line of text as a quick-add event such as "fri 18:00 Dinner",
+// dates relative to base. Blank lines and lines starting with # are skipped. Events
+// that parse start accepted; duplicates of stored or earlier pasted events are
+// reported as errors.
+func (m *Manager) ParsePaste(text string, base time.Time) []PasteLine {
+	var lines []PasteLine
+	var parsed []models.Event
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pasted := PasteLine{Text: line}
+		pasted.Event, pasted.Err = m.parsePasteLine(line, base)
+		if pasted.Err == nil && (m.containsEvent(pasted.Event) || containsEvent(parsed, pasted.Event)) {
+			pasted.Err = fmt.Errorf("already in the calendar")
+		}
+		if pasted.Err == nil {
+			pasted.Accepted = true
+			parsed = append(parsed, pasted.Event)
+		}
+		lines = append(lines, pasted)
+	}
+	return lines
+}
+
+// parsePasteLine turns a quick-add line into an event with a normalized description
+func (m *Manager) parsePasteLine(line string, base time.Time) (models.Event, error) {
+	date, timeStr, description, err := calendar.ParseQuickAdd(line, base)
+	if err != nil {
+		return models.Event{}, err
+	}
+	eventTime, err := calendar.ParseTime(timeStr)
+	if err != nil {
+		return models.Event{}, fmt.Errorf("invalid time '%s': %v", timeStr, err)
+	}
+	return models.Event{Date: date, Time: eventTime, Description: m.ApplyNormalization(description)}, nil
+}
+
+// SavePaste adds the accepted events of pasted lines in one write and returns how
+// many were added
+func (m *Manager) SavePaste(lines []PasteLine) (int, error) {
+	var accepted []models.Event
+	for _, line := range lines {
+		if line.Accepted && line.Err == nil {
+			accepted = append(accepted, line.Event)
+		}
+	}
+	return m.ImportEvents(accepted)
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+)
+
+func TestManager_ParsePaste(t *testing.T) {
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true})
+	base := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC) // A Friday
+	if err := manager.AddEvent(base, "09:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+
+	text := "next fri 18:00 Dinner\n\n# a comment\n09:00 Standup\ntomorrow Brunch\n  mon 10:00 Review  \nnext fri 18:00 Dinner\n"
+	lines := manager.ParsePaste(text, base)
+
+	want := []struct {
+		text     string
+		accepted bool
+		date     time.Time
+	}{
+		{"next fri 18:00 Dinner", true, time.Date(2025, 8, 22, 0, 0, 0, 0, time.UTC)},
+		{"09:00 Standup", false, time.Time{}},
+		{"tomorrow Brunch", false, time.Time{}},
+		{"mon 10:00 Review", true, time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC)},
+		{"next fri 18:00 Dinner", false, time.Time{}},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("ParsePaste() returned %d lines, want %d: %+v", len(lines), len(want), lines)
+	}
+	for i, w := range want {
+		line := lines[i]
+		if line.Text != w.text || line.Accepted != w.accepted || (line.Err == nil) != w.accepted {
+			t.Errorf("Line %d = %q accepted %v (error %v), want %q accepted %v", i, line.Text, line.Accepted, line.Err, w.text, w.accepted)
+		}
+		if w.accepted && !line.Event.Date.Equal(w.date) {
+			t.Errorf("Line %d date = %v, want %v", i, line.Event.Date, w.date)
+		}
+	}
+
+	// Only accepted lines are saved
+	lines[3].Accepted = false
+	added, err := manager.SavePaste(lines)
+	if err != nil {
+		t.Fatalf("SavePaste() failed: %v", err)
+	}
+	if added != 1 || manager.GetEventCount() != 2 {
+		t.Errorf("SavePaste() added %d events, leaving %d; want 1 and 2", added, manager.GetEventCount())
+	}
+	if events := manager.GetEventsForDate(want[0].date); len(events) != 1 || events[0].Description != "Dinner" {
+		t.Errorf("Events on %v = %v, want the dinner", want[0].date, events)
+	}
+}
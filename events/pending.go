@@ -0,0 +1,122 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+// PendingDelete is an event removed from the calendar whose deletion is only written
+// to storage once Due has passed, so it can still be taken back
+type PendingDelete struct {
+	Event models.Event
+	Due   time.Time
+}
+
+// DeferDelete removes an event from memory at once, notifying listeners as for any
+// deletion, and leaves it in storage until CommitDeletes runs after due. Restoring the
+// event before then drops the deletion without writing anything.
+func (m *Manager) DeferDelete(event models.Event, due time.Time) error {
+	var kept []models.Event
+	found := false
+	for _, existing := range m.events {
+		if !found && sameEvent(existing, event) {
+			found = true
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	if !found {
+		return fmt.Errorf("event not found in memory for deletion")
+	}
+
+	m.events = kept
+	m.pendingDeletes = append(m.pendingDeletes, PendingDelete{Event: event, Due: due})
+	m.notifyChange(ChangeDeleted, event, models.Event{})
+	return nil
+}
+
+// PendingDeletes returns the deletions not written yet, oldest first
+func (m *Manager) PendingDeletes() []PendingDelete {
+	return append([]PendingDelete(nil), m.pendingDeletes...)
+}
+
+// CommitDeletes writes the pending deletions that are due at now; a deletion whose
+// write fails stays pending
+func (m *Manager) CommitDeletes(now time.Time) error {
+	var stillPending []PendingDelete
+	var firstErr error
+	for _, pending := range m.pendingDeletes {
+		if pending.Due.After(now) {
+			stillPending = append(stillPending, pending)
+			continue
+		}
+		if err := m.deleteStored(pending.Event); err != nil {
+			stillPending = append(stillPending, pending)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to delete event from storage: %v", err)
+			}
+		}
+	}
+	m.pendingDeletes = stillPending
+	return firstErr
+}
+
+// FlushDeletes writes all pending deletions, e.g. before exiting
+func (m *Manager) FlushDeletes() error {
+	var last time.Time
+	for _, pending := range m.pendingDeletes {
+		if pending.Due.After(last) {
+			last = pending.Due
+		}
+	}
+	return m.CommitDeletes(last)
+}
+
+// takePendingDelete drops the pending deletion of event, reporting whether there was one
+func (m *Manager) takePendingDelete(event models.Event) bool {
+	index := m.pendingIndex(event)
+	if index < 0 {
+		return false
+	}
+	m.pendingDeletes = append(m.pendingDeletes[:index:index], m.pendingDeletes[index+1:]...)
+	return true
+}
+
+// withoutPendingDeletes removes the events deleted but not written yet from events
+// loaded from storage. Deletions whose event is no longer stored are done already.
+func (m *Manager) withoutPendingDeletes(loaded []models.Event) []models.Event {
+	if len(m.pendingDeletes) == 0 {
+		return loaded
+	}
+
+	var kept []models.Event
+	stored := make([]bool, len(m.pendingDeletes))
+	for _, event := range loaded {
+		if index := m.pendingIndex(event); index >= 0 {
+			stored[index] = true
+			continue
+		}
+		kept = append(kept, event)
+	}
+
+	var stillPending []PendingDelete
+	for i, pending := range m.pendingDeletes {
+		if stored[i] {
+			stillPending = append(stillPending, pending)
+		}
+	}
+	m.pendingDeletes = stillPending
+	return kept
+}
+
+// pendingIndex returns the index of the pending deletion of event, or -1
+func (m *Manager) pendingIndex(event models.Event) int {
+	for i, pending := range m.pendingDeletes {
+		if sameEvent(pending.Event, event) {
+			return i
+		}
+	}
+	return -1
+}
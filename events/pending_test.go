@@ -0,0 +1,162 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+// newPendingTestManager returns a manager holding a standup and a lunch on date
+func newPendingTestManager(t *testing.T, date time.Time) (*Manager, *config.Config) {
+	t.Helper()
+	tempDir, err := os.MkdirTemp("", "pending_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := NewManagerWithConfig(cfg)
+	for _, e := range [][2]string{{"09:00", "Standup"}, {"12:00", "Lunch"}} {
+		if err := manager.AddEvent(date, e[0], e[1]); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+	}
+	return manager, cfg
+}
+
+// storedCount returns the number of events in the events file
+func storedCount(t *testing.T, cfg *config.Config) int {
+	t.Helper()
+	stored, err := storage.LoadEventsJSON(cfg.EventsFilePath)
+	if err != nil {
+		t.Fatalf("LoadEventsJSON() failed: %v", err)
+	}
+	return len(stored)
+}
+
+func TestManager_DeferDelete(t *testing.T) {
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	manager, cfg := newPendingTestManager(t, date)
+	now := time.Date(2025, 8, 15, 10, 0, 0, 0, time.Local)
+
+	var deleted []models.Event
+	manager.AddChangeListener(func(kind ChangeKind, before, after models.Event) {
+		if kind == ChangeDeleted {
+			deleted = append(deleted, before)
+		}
+	})
+
+	standup := manager.GetEventsForDate(date)[0]
+	if err := manager.DeferDelete(standup, now.Add(5*time.Second)); err != nil {
+		t.Fatalf("DeferDelete() failed: %v", err)
+	}
+	if got := manager.GetEventsForDate(date); len(got) != 1 || got[0].Description != "Lunch" {
+		t.Errorf("Events after DeferDelete() = %v, want only Lunch", got)
+	}
+	if len(deleted) != 1 {
+		t.Errorf("Listeners saw %d deletions, want 1", len(deleted))
+	}
+	if got := storedCount(t, cfg); got != 2 {
+		t.Errorf("Stored events before the delay lapsed = %d, want 2", got)
+	}
+
+	// Nothing is written before the delay lapses
+	if err := manager.CommitDeletes(now.Add(4 * time.Second)); err != nil || len(manager.PendingDeletes()) != 1 {
+		t.Fatalf("CommitDeletes() early = %v with %d pending, want the deletion still pending", err, len(manager.PendingDeletes()))
+	}
+
+	// A reload keeps the event deleted
+	if err := manager.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	if got := manager.GetEventCount(); got != 1 {
+		t.Errorf("Events after reloading = %d, want 1", got)
+	}
+
+	if err := manager.CommitDeletes(now.Add(5 * time.Second)); err != nil {
+		t.Fatalf("CommitDeletes() failed: %v", err)
+	}
+	if len(manager.PendingDeletes()) != 0 || storedCount(t, cfg) != 1 {
+		t.Errorf("After the delay: %d pending, %d stored; want 0 and 1", len(manager.PendingDeletes()), storedCount(t, cfg))
+	}
+}
+
+func TestManager_DeferDelete_Undo(t *testing.T) {
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	manager, cfg := newPendingTestManager(t, date)
+	history := NewHistory(manager)
+	due := time.Date(2025, 8, 15, 10, 0, 5, 0, time.Local)
+
+	lunch := manager.GetEventsForDate(date)[1]
+	if err := manager.DeferDelete(lunch, due); err != nil {
+		t.Fatalf("DeferDelete() failed: %v", err)
+	}
+	if err := history.UndoDeletion(lunch); err != nil {
+		t.Fatalf("UndoDeletion() failed: %v", err)
+	}
+	if got := manager.GetEventCount(); got != 2 || len(manager.PendingDeletes()) != 0 {
+		t.Errorf("After undoing: %d events, %d pending; want 2 and 0", got, len(manager.PendingDeletes()))
+	}
+	if err := manager.FlushDeletes(); err != nil {
+		t.Fatalf("FlushDeletes() failed: %v", err)
+	}
+	if got := storedCount(t, cfg); got != 2 {
+		t.Errorf("Stored events after an undone deletion = %d, want 2", got)
+	}
+	if err := history.UndoDeletion(lunch); err == nil {
+		t.Error("UndoDeletion() should fail once the deletion is undone")
+	}
+}
+
+func TestManager_FlushDeletes(t *testing.T) {
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	manager, cfg := newPendingTestManager(t, date)
+	due := time.Now().Add(time.Hour)
+
+	for _, event := range manager.GetEventsForDate(date) {
+		if err := manager.DeferDelete(event, due); err != nil {
+			t.Fatalf("DeferDelete() failed: %v", err)
+		}
+	}
+	if err := manager.FlushDeletes(); err != nil {
+		t.Fatalf("FlushDeletes() failed: %v", err)
+	}
+	if len(manager.PendingDeletes()) != 0 || storedCount(t, cfg) != 0 {
+		t.Errorf("After flushing: %d pending, %d stored; want none", len(manager.PendingDeletes()), storedCount(t, cfg))
+	}
+}
+
+func TestManager_Rewrite(t *testing.T) {
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	manager, cfg := newPendingTestManager(t, date)
+	writes := 0
+	manager.SetBeforeWrite(func() { writes++ })
+
+	lunch := manager.GetEventsForDate(date)[1]
+	if err := manager.DeferDelete(lunch, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("DeferDelete() failed: %v", err)
+	}
+
+	// A sync pull replaces the file; the events in memory are written back over it
+	if err := storage.SaveEventsJSON(nil, cfg.EventsFilePath); err != nil {
+		t.Fatalf("SaveEventsJSON() failed: %v", err)
+	}
+	if err := manager.Rewrite(); err != nil {
+		t.Fatalf("Rewrite() failed: %v", err)
+	}
+	if got := storedCount(t, cfg); got != 2 {
+		t.Errorf("Stored events after Rewrite() = %d, want the standup and the pending deletion", got)
+	}
+	if len(manager.PendingDeletes()) != 1 || len(manager.GetAllEvents()) != 1 {
+		t.Errorf("Rewrite() should leave the deletion pending, got %d pending and %d events", len(manager.PendingDeletes()), len(manager.GetAllEvents()))
+	}
+	if writes != 1 {
+		t.Errorf("Before-write hook called %d times, want 1", writes)
+	}
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+// SetEventReminder sets how long before the start of an existing event its reminder
+// is shown, zero for none, and returns the updated event
+func (m *Manager) SetEventReminder(event models.Event, reminder time.Duration) (models.Event, error) {
+	if reminder < 0 {
+		return models.Event{}, fmt.Errorf("reminder cannot be negative")
+	}
+	updated := event
+	updated.Reminder = reminder
+
+	if err := m.replaceEvent(event, updated); err != nil {
+		return models.Event{}, fmt.Errorf("failed to update event reminder: %v", err)
+	}
+	return updated, nil
+}
+
+// DueReminders returns the events whose reminder is due at now: the reminder time has
+// come but the event has not started yet. They are sorted by start, and filters do
+// not hide them. next is the next time the result changes, zero when no reminder is
+// left to come.
+func (m *Manager) DueReminders(now time.Time) (due []models.Event, next time.Time) {
+	later := func(t time.Time) {
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+
+	for _, event := range m.events {
+		if event.Reminder <= 0 {
+			continue
+		}
+		start := event.Start(now.Location())
+		if !start.After(now) {
+			continue
+		}
+		if remindAt := start.Add(-event.Reminder); remindAt.After(now) {
+			later(remindAt)
+			continue
+		}
+		due = append(due, event)
+		later(start)
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].Start(now.Location()).Before(due[j].Start(now.Location()))
+	})
+	return due, next
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/storage"
+)
+
+func TestManager_DueReminders(t *testing.T) {
+	cfg := &config.Config{EventsFilePath: filepath.Join(t.TempDir(), "events.json")}
+	manager := NewManagerWithConfig(cfg)
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	for _, e := range [][2]string{{"09:00", "Standup"}, {"10:00", "Review"}, {"11:00", "Lunch"}} {
+		if err := manager.AddEvent(day, e[0], e[1]); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+	}
+	for i, reminder := range []time.Duration{15 * time.Minute, time.Hour} {
+		if _, err := manager.SetEventReminder(manager.GetEventsForDate(day)[i], reminder); err != nil {
+			t.Fatalf("SetEventReminder() failed: %v", err)
+		}
+	}
+	if _, err := manager.SetEventReminder(manager.GetEventsForDate(day)[2], -time.Minute); err == nil {
+		t.Error("SetEventReminder() should reject a negative reminder")
+	}
+
+	stored, err := storage.LoadEventsJSON(cfg.EventsFilePath)
+	if err != nil || stored[0].Reminder != 15*time.Minute {
+		t.Fatalf("Stored reminder = %v (%v), want 15m", stored[0].Reminder, err)
+	}
+
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	tests := []struct {
+		now  time.Time
+		due  []string
+		next time.Time
+	}{
+		{at(8, 0), nil, at(8, 45)},
+		{at(8, 45), []string{"Standup"}, at(9, 0)},
+		{at(8, 59), []string{"Standup"}, at(9, 0)},
+		{at(9, 0), []string{"Review"}, at(10, 0)},
+		{at(10, 0), nil, time.Time{}},
+	}
+	for _, tt := range tests {
+		due, next := manager.DueReminders(tt.now)
+		var names []string
+		for _, event := range due {
+			names = append(names, event.Description)
+		}
+		if len(names) != len(tt.due) || (len(names) > 0 && names[0] != tt.due[0]) || !next.Equal(tt.next) {
+			t.Errorf("DueReminders(%s) = %v, next %v; want %v, next %v", tt.now.Format("15:04"), names, next, tt.due, tt.next)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/models"
+)
+
+// SearchField is an event field that a query can be restricted to with its
+// prefix, e.g. "cat:work", or one of its aliases
+type SearchField struct {
+	Prefix  string
+	Aliases []string
+	// Weight scales the score of a match in the field when results are ordered by
+	// relevance, so a match in the description counts for more than one in the notes
+	Weight int
+	Value  func(event models.Event) string
+}
+
+// SearchFields lists the fields a plain query searches
+var SearchFields = []SearchField{
+	{Prefix: "desc", Weight: 3, Value: func(event models.Event) string { return event.Description }},
+	{Prefix: "cat", Weight: 2, Value: func(event models.Event) string { return event.Category }},
+	{Prefix: "cmd", Weight: 1, Value: func(event models.Event) string { return event.Command }},
+	{Prefix: "notes", Aliases: []string{"note"}, Weight: 1, Value: func(event models.Event) string { return event.Notes }},
+}
+
+// named reports whether prefix names the field, ignoring case
+func (f SearchField) named(prefix string) bool {
+	if strings.EqualFold(prefix, f.Prefix) {
+		return true
+	}
+	for _, alias := range f.Aliases {
+		if strings.EqualFold(prefix, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSearchQuery splits a "field:text" query into the field to search and the text.
+// Queries without a known field prefix search all fields for the whole query.
+func ParseSearchQuery(query string) ([]SearchField, string) {
+	if prefix, text, found := strings.Cut(query, ":"); found {
+		for _, field := range SearchFields {
+			if field.named(strings.TrimSpace(prefix)) {
+				return []SearchField{field}, strings.TrimSpace(text)
+			}
+		}
+	}
+	return SearchFields, query
+}
+
+// SearchFilter narrows a search to a date range and category, written in the query as
+// "after:2025-09-01", "before:2025-10-01" and "tag:work"
+type SearchFilter struct {
+	After  string // First date included, YYYY-MM-DD; empty for no limit
+	Before string // First date excluded, YYYY-MM-DD; empty for no limit
+	Tag    string // Category, compared case-insensitively; empty for any
+}
+
+// IsZero reports whether the filter lets every event pass
+func (f SearchFilter) IsZero() bool {
+	return f == SearchFilter{}
+}
+
+// Matches reports whether an event passes the filter; an event spanning several days
+// passes when one of its days is in the range
+func (f SearchFilter) Matches(event models.Event) bool {
+	if f.After != "" && event.LastDate().Format("2006-01-02") < f.After {
+		return false
+	}
+	if f.Before != "" && event.Date.Format("2006-01-02") >= f.Before {
+		return false
+	}
+	return f.Tag == "" || strings.EqualFold(event.Category, f.Tag)
+}
+
+// ParseSearchFilter takes the "after:", "before:" and "tag:" terms out of a query and
+// returns the filter they make with the rest of the query. Dates accept the
+// expressions of the add dialog, such as "2025-09-01" or "eom", relative to now. The
+// rest is returned even when a term is invalid.
+func ParseSearchFilter(query string, now time.Time) (SearchFilter, string, error) {
+	var filter SearchFilter
+	var rest []string
+	var problems []string
+
+	for _, word := range strings.Fields(query) {
+		name, value, _ := strings.Cut(word, ":")
+		switch strings.ToLower(name) {
+		case "after", "before":
+			date, err := calendar.ParseRelativeDate(value, now)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", word, err))
+			} else if strings.EqualFold(name, "after") {
+				filter.After = date.Format("2006-01-02")
+			} else {
+				filter.Before = date.Format("2006-01-02")
+			}
+		case "tag":
+			if value == "" {
+				problems = append(problems, "tag: needs a category")
+			}
+			filter.Tag = value
+		default:
+			rest = append(rest, word)
+		}
+	}
+
+	if len(problems) > 0 {
+		return filter, strings.Join(rest, " "), fmt.Errorf("invalid search filter %s", strings.Join(problems, "; "))
+	}
+	return filter, strings.Join(rest, " "), nil
+}
+
+// SearchEvents searches the visible events for the query string in their description,
+// category or command, or in the single field named by a "field:" prefix. When no event
+// contains the query, events holding its characters in order match fuzzily, e.g.
+// "stdup" finds "Standup"; see matchScore. Filter terms narrow the results to a date
+// range or category, see ParseSearchFilter; a query of only filter terms lists all
+// events passing them, and an invalid one finds nothing.
+func (m *Manager) SearchEvents(query string) []models.Event {
+	filter, query, err := ParseSearchFilter(query, time.Now())
+	if err != nil {
+		return []models.Event{}
+	}
+	fields, text := ParseSearchQuery(query)
+	if text == "" {
+		if filter.IsZero() {
+			return []models.Event{}
+		}
+		return sortByDate(m.matchEvents(m.events, filter, fields, func(string) bool { return true }))
+	}
+
+	lowerText := strings.ToLower(text)
+
+	// Only check the events the index cannot rule out
+	candidates, ok := m.searchIndex().candidates(lowerText)
+	if !ok {
+		candidates = m.events
+	}
+	matchingEvents := m.matchEvents(candidates, filter, fields, func(value string) bool {
+		return strings.Contains(value, lowerText)
+	})
+
+	// The index only knows whole parts of words, so fuzzy matching checks every event
+	if len(matchingEvents) == 0 && utf8.RuneCountInString(lowerText) >= fuzzyMinLength {
+		matchingEvents = m.matchEvents(m.events, filter, fields, func(value string) bool {
+			return matchScore(value, lowerText) > 0
+		})
+	}
+
+	return sortByDate(matchingEvents)
+}
+
+// sortByDate sorts events by date, then by time, and returns them
+func sortByDate(list []models.Event) []models.Event {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Date.Equal(list[j].Date) {
+			return list[i].Time.Before(list[j].Time)
+		}
+		return list[i].Date.Before(list[j].Date)
+	})
+	return list
+}
+
+// matchEvents returns the visible events of list passing filter with a field whose
+// lowercase value matches
+func (m *Manager) matchEvents(list []models.Event, filter SearchFilter, fields []SearchField, matches func(lowerValue string) bool) []models.Event {
+	var matchingEvents []models.Event
+	for _, event := range list {
+		if !m.visible(event) || !filter.Matches(event) {
+			continue
+		}
+		for _, field := range fields {
+			if matches(strings.ToLower(field.Value(event))) {
+				matchingEvents = append(matchingEvents, event)
+				break
+			}
+		}
+	}
+	return matchingEvents
+}
+
+// searchIndex returns the index of the searchable fields, building it on first use or
+// after the events were replaced as a whole
+func (m *Manager) searchIndex() *searchIndex {
+	if m.index == nil || m.index.size() != len(m.events) {
+		m.index = newSearchIndex(m.events)
+	}
+	return m.index
+}
+
+// OrderByNearest reorders search results by their distance from now: upcoming events
+// first, nearest first, then past events, most recent first. It returns the number of
+// upcoming events at the start of the ordered results.
+func OrderByNearest(results []models.Event, now time.Time) ([]models.Event, int) {
+	// Event moments carry their wall clock in UTC, so compare against now's wall clock
+	now = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, time.UTC)
+
+	var upcoming, past []models.Event
+	for _, event := range results {
+		if eventMoment(event).Before(now) {
+			past = append(past, event)
+		} else {
+			upcoming = append(upcoming, event)
+		}
+	}
+
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return eventMoment(upcoming[i]).Before(eventMoment(upcoming[j]))
+	})
+	sort.SliceStable(past, func(i, j int) bool {
+		return eventMoment(past[i]).After(eventMoment(past[j]))
+	})
+
+	return append(upcoming, past...), len(upcoming)
+}
@@ -0,0 +1,165 @@
+package events
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantFields int
+		wantPrefix string
+		wantText   string
+	}{
+		{"team", len(SearchFields), "desc", "team"},
+		{"cat:work", 1, "cat", "work"},
+		{"CMD: notify-send", 1, "cmd", "notify-send"},
+		{"desc:", 1, "desc", ""},
+		{"note:call back", 1, "notes", "call back"},
+		{"NOTES:call", 1, "notes", "call"},
+		{"loc:home", len(SearchFields), "desc", "loc:home"},
+		{"10:30 sync", len(SearchFields), "desc", "10:30 sync"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			fields, text := ParseSearchQuery(tt.query)
+			if len(fields) != tt.wantFields || fields[0].Prefix != tt.wantPrefix || text != tt.wantText {
+				t.Errorf("ParseSearchQuery(%q) = %d fields starting with %q, %q; want %d, %q, %q",
+					tt.query, len(fields), fields[0].Prefix, text, tt.wantFields, tt.wantPrefix, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestManager_SearchEventsFields(t *testing.T) {
+	manager := NewManager()
+	manager.events = []models.Event{
+		{Date: time.Date(2025, 8, 16, 0, 0, 0, 0, time.UTC), Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Backup", Command: "rsync -a ~/work /backup"},
+		{Date: time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC), Time: time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC), Description: "Standup", Category: "Work"},
+		{Date: time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC), Time: time.Date(0, 1, 1, 11, 0, 0, 0, time.UTC), Description: "Work on slides", Category: "personal"},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"work", []string{"Standup", "Backup", "Work on slides"}},
+		{"cat:work", []string{"Standup"}},
+		{"desc:work", []string{"Work on slides"}},
+		{"cmd:rsync", []string{"Backup"}},
+		{"cat:", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results := manager.SearchEvents(tt.query)
+			if len(results) != len(tt.want) {
+				t.Fatalf("SearchEvents(%q) returned %d results, want %d", tt.query, len(results), len(tt.want))
+			}
+			for i, event := range results {
+				if event.Description != tt.want[i] {
+					t.Errorf("Result %d = %q, want %q", i, event.Description, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOrderByNearest(t *testing.T) {
+	at := func(day, hour int, description string) models.Event {
+		return models.Event{
+			Date:        time.Date(2025, 8, day, 0, 0, 0, 0, time.Local),
+			Time:        time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC),
+			Description: description,
+		}
+	}
+	results := []models.Event{
+		at(1, 9, "Long ago"),
+		at(14, 9, "Yesterday"),
+		at(15, 9, "This morning"),
+		at(15, 18, "This evening"),
+		at(20, 9, "Next week"),
+		at(16, 9, "Tomorrow"),
+	}
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.Local)
+
+	ordered, upcoming := OrderByNearest(results, now)
+	if upcoming != 3 {
+		t.Errorf("OrderByNearest() reported %d upcoming events, want 3", upcoming)
+	}
+	want := []string{"This evening", "Tomorrow", "Next week", "This morning", "Yesterday", "Long ago"}
+	for i, event := range ordered {
+		if event.Description != want[i] {
+			t.Errorf("Result %d = %q, want %q", i, event.Description, want[i])
+		}
+	}
+}
+
+func TestParseSearchFilter(t *testing.T) {
+	now := time.Date(2025, 8, 20, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		query    string
+		want     SearchFilter
+		wantRest string
+		wantErr  bool
+	}{
+		{"after:2025-09-01 before:2025-10-01 tag:work meeting", SearchFilter{After: "2025-09-01", Before: "2025-10-01", Tag: "work"}, "meeting", false},
+		{"desc:review AFTER:tomorrow", SearchFilter{After: "2025-08-21"}, "desc:review", false},
+		{"lunch  with the team", SearchFilter{}, "lunch with the team", false},
+		{"after:someday lunch", SearchFilter{}, "lunch", true},
+		{"tag: lunch", SearchFilter{}, "lunch", true},
+	}
+	for _, tt := range tests {
+		filter, rest, err := ParseSearchFilter(tt.query, now)
+		if filter != tt.want || rest != tt.wantRest || (err != nil) != tt.wantErr {
+			t.Errorf("ParseSearchFilter(%q) = %+v, %q, %v; want %+v, %q, error %v", tt.query, filter, rest, err, tt.want, tt.wantRest, tt.wantErr)
+		}
+	}
+}
+
+func TestManager_SearchEventsFilters(t *testing.T) {
+	manager := NewManager()
+	at := func(month time.Month, day int, description, category string) models.Event {
+		return models.Event{Date: time.Date(2025, month, day, 0, 0, 0, 0, time.Local), Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: description, Category: category}
+	}
+	trip := at(8, 30, "Team offsite", "work")
+	trip.EndDate = time.Date(2025, 9, 2, 0, 0, 0, 0, time.Local)
+	manager.events = []models.Event{
+		at(9, 1, "Team meeting", "work"),
+		at(9, 30, "Team meeting", "Work"),
+		at(10, 1, "Team meeting", "work"),
+		at(9, 15, "Team meeting", "personal"),
+		trip,
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"after:2025-09-01 before:2025-10-01 tag:work meeting", []string{"2025-09-01", "2025-09-30"}},
+		{"after:2025-09-01 before:2025-10-01 team", []string{"2025-08-30", "2025-09-01", "2025-09-15", "2025-09-30"}},
+		{"tag:personal", []string{"2025-09-15"}},
+		{"before:2025-09-01", []string{"2025-08-30"}},
+		{"after:nonsense meeting", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, event := range manager.SearchEvents(tt.query) {
+			got = append(got, event.GetDateString())
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("SearchEvents(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	filter := config.QuickFilter{Query: "tag:work after:2025-09-15"}
+	if !FilterMatches(filter, manager.events[1]) || FilterMatches(filter, manager.events[0]) {
+		t.Error("Quick filter queries should honor search filter terms")
+	}
+}
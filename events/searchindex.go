@@ -0,0 +1,196 @@
+package events
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"go-ascii-calendar/models"
+)
+
+// searchIndex maps the lowercase words of the searchable fields to the events holding
+// them, so a search only checks the events sharing a word with the query instead of
+// every stored event. It is built on the first search and kept up to date from the
+// manager's change notifications.
+type searchIndex struct {
+	events   map[int]models.Event           // Indexed events by id
+	ids      map[string][]int               // Ids of the events with the same date, time and description
+	postings map[string]map[int]struct{}    // Ids of the events holding each word
+	grams    map[string]map[string]struct{} // Indexed words containing each three-letter sequence
+	nextID   int
+}
+
+// newSearchIndex indexes all given events
+func newSearchIndex(list []models.Event) *searchIndex {
+	index := &searchIndex{
+		events:   make(map[int]models.Event, len(list)),
+		ids:      make(map[string][]int, len(list)),
+		postings: make(map[string]map[int]struct{}),
+		grams:    make(map[string]map[string]struct{}),
+	}
+	for _, event := range list {
+		index.add(event)
+	}
+	return index
+}
+
+// searchWords splits lowercase text into its runs of letters and digits
+func searchWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// trigrams returns the three-letter sequences of a word; shorter words have none
+func trigrams(word string) []string {
+	runes := []rune(word)
+	var grams []string
+	for i := 0; i+3 <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+3]))
+	}
+	return grams
+}
+
+// indexKey identifies an event the way the manager matches events for edits and deletions
+func indexKey(event models.Event) string {
+	return event.GetDateString() + " " + event.GetTimeString() + " " + event.Description
+}
+
+// size returns the number of indexed events
+func (x *searchIndex) size() int {
+	return len(x.events)
+}
+
+// add indexes an event under the words of all searchable fields
+func (x *searchIndex) add(event models.Event) {
+	id := x.nextID
+	x.nextID++
+	x.events[id] = event
+	key := indexKey(event)
+	x.ids[key] = append(x.ids[key], id)
+
+	for _, field := range SearchFields {
+		for _, word := range searchWords(strings.ToLower(field.Value(event))) {
+			ids, ok := x.postings[word]
+			if !ok {
+				ids = make(map[int]struct{})
+				x.postings[word] = ids
+				for _, gram := range trigrams(word) {
+					if x.grams[gram] == nil {
+						x.grams[gram] = make(map[string]struct{})
+					}
+					x.grams[gram][word] = struct{}{}
+				}
+			}
+			ids[id] = struct{}{}
+		}
+	}
+}
+
+// remove drops one indexed event matching event; unknown events are ignored
+func (x *searchIndex) remove(event models.Event) {
+	key := indexKey(event)
+	ids := x.ids[key]
+	if len(ids) == 0 {
+		return
+	}
+	id := ids[len(ids)-1]
+	if len(ids) == 1 {
+		delete(x.ids, key)
+	} else {
+		x.ids[key] = ids[:len(ids)-1]
+	}
+
+	indexed := x.events[id]
+	delete(x.events, id)
+	for _, field := range SearchFields {
+		for _, word := range searchWords(strings.ToLower(field.Value(indexed))) {
+			if postings, ok := x.postings[word]; ok {
+				delete(postings, id)
+				if len(postings) == 0 {
+					delete(x.postings, word)
+					for _, gram := range trigrams(word) {
+						delete(x.grams[gram], word)
+						if len(x.grams[gram]) == 0 {
+							delete(x.grams, gram)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// apply updates the index for a change reported by the manager
+func (x *searchIndex) apply(kind ChangeKind, before, after models.Event) {
+	if kind != ChangeAdded {
+		x.remove(before)
+	}
+	if kind != ChangeDeleted {
+		x.add(after)
+	}
+}
+
+// candidates returns the events that may contain lowerText: those holding a word that
+// contains the longest word of the query. Any event containing the query holds such a
+// word, so the candidates only need to be checked with strings.Contains. Queries
+// without letters or digits cannot be narrowed down, which ok reports as false.
+func (x *searchIndex) candidates(lowerText string) (events []models.Event, ok bool) {
+	longest := ""
+	for _, word := range searchWords(lowerText) {
+		if len(word) > len(longest) {
+			longest = word
+		}
+	}
+	if longest == "" {
+		return nil, false
+	}
+
+	seen := make(map[int]struct{})
+	for _, word := range x.wordsContaining(longest) {
+		for id := range x.postings[word] {
+			seen[id] = struct{}{}
+		}
+	}
+
+	// Return the candidates in a stable order, oldest indexed first
+	ordered := make([]int, 0, len(seen))
+	for id := range seen {
+		ordered = append(ordered, id)
+	}
+	sort.Ints(ordered)
+	events = make([]models.Event, len(ordered))
+	for i, id := range ordered {
+		events[i] = x.events[id]
+	}
+	return events, true
+}
+
+// wordsContaining returns the indexed words that contain part. Only the words holding
+// the rarest of the part's three-letter sequences are checked; parts shorter than
+// three letters check the whole vocabulary.
+func (x *searchIndex) wordsContaining(part string) []string {
+	var words []string
+	grams := trigrams(part)
+	if len(grams) == 0 {
+		for word := range x.postings {
+			if strings.Contains(word, part) {
+				words = append(words, word)
+			}
+		}
+		return words
+	}
+
+	rarest := x.grams[grams[0]]
+	for _, gram := range grams[1:] {
+		if len(x.grams[gram]) < len(rarest) {
+			rarest = x.grams[gram]
+		}
+	}
+	for word := range rarest {
+		if strings.Contains(word, part) {
+			words = append(words, word)
+		}
+	}
+	return words
+}
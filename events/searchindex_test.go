@@ -0,0 +1,80 @@
+package events
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+func TestManager_SearchIndexFollowsChanges(t *testing.T) {
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true})
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	if err := manager.AddEvent(date, "09:00", "Dentist appointment"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+
+	// The first search builds the index; later changes must update it
+	if results := manager.SearchEvents("ntist"); len(results) != 1 {
+		t.Fatalf("SearchEvents(ntist) = %v, want the dentist", results)
+	}
+	if err := manager.AddEvent(date, "12:00", "Lunch with the team"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if results := manager.SearchEvents("team lunch"); len(results) != 0 {
+		t.Errorf("SearchEvents(team lunch) = %v, want none: the words are in another order", results)
+	}
+	if results := manager.SearchEvents("with the"); len(results) != 1 {
+		t.Errorf("SearchEvents(with the) = %v, want the lunch", results)
+	}
+
+	dentist := manager.SearchEvents("dentist")[0]
+	if err := manager.EditEvent(dentist, date, "09:00", "Orthodontist"); err != nil {
+		t.Fatalf("EditEvent() failed: %v", err)
+	}
+	if results := manager.SearchEvents("appointment"); len(results) != 0 {
+		t.Errorf("SearchEvents(appointment) after the edit = %v, want none", results)
+	}
+	if results := manager.SearchEvents("ORTHO"); len(results) != 1 {
+		t.Errorf("SearchEvents(ORTHO) = %v, want the edited event", results)
+	}
+	if err := manager.SetEventCategory(manager.SearchEvents("ortho")[0], "health"); err != nil {
+		t.Fatalf("SetEventCategory() failed: %v", err)
+	}
+	if results := manager.SearchEvents("cat:heal"); len(results) != 1 {
+		t.Errorf("SearchEvents(cat:heal) = %v, want the categorized event", results)
+	}
+
+	if err := manager.DeleteEvent(manager.SearchEvents("lunch")[0]); err != nil {
+		t.Fatalf("DeleteEvent() failed: %v", err)
+	}
+	if results := manager.SearchEvents("lunch"); len(results) != 0 {
+		t.Errorf("SearchEvents(lunch) after the deletion = %v, want none", results)
+	}
+
+	// Queries without letters or digits cannot use the index
+	if _, ok := manager.searchIndex().candidates("?!"); ok {
+		t.Error("candidates(?!) should fall back to a scan of all events")
+	}
+}
+
+func BenchmarkManager_SearchEvents(b *testing.B) {
+	manager := NewManager()
+	words := []string{"standup", "dentist", "review", "lunch", "gym", "flight", "dinner", "call", "release", "retro"}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 100000; i++ {
+		manager.events = append(manager.events, models.Event{
+			Date:        start.AddDate(0, 0, i%2000),
+			Time:        time.Date(0, 1, 1, i%24, 0, 0, 0, time.UTC),
+			Description: fmt.Sprintf("%s %s #%d", words[i%len(words)], words[(i/7)%len(words)], i),
+		})
+	}
+	manager.SearchEvents("warm-up")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		manager.SearchEvents("#4242")
+	}
+}
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"fmt"
+	"os"
+
+	"go-ascii-calendar/storage"
+)
+
+// StoreSize is the number of events and the size of the events file
+type StoreSize struct {
+	Events int
+	Bytes  int64
+}
+
+// Size reports how large the events store is; a missing file has zero bytes
+func (m *Manager) Size() StoreSize {
+	path := storage.EventsFileName
+	if m.config != nil {
+		path = m.config.GetEventsFilePath()
+	}
+
+	size := StoreSize{Events: len(m.events)}
+	if info, err := os.Stat(path); err == nil {
+		size.Bytes = info.Size()
+	}
+	return size
+}
+
+// Exceeds reports whether the store is over either limit; a limit of 0 is ignored
+func (s StoreSize) Exceeds(maxEvents int, maxBytes int64) bool {
+	return (maxEvents > 0 && s.Events > maxEvents) || (maxBytes > 0 && s.Bytes > maxBytes)
+}
+
+// String describes the size, e.g. "6123 events, 2.1 MB"
+func (s StoreSize) String() string {
+	return fmt.Sprintf("%d events, %s", s.Events, formatBytes(s.Bytes))
+}
+
+// formatBytes formats a byte count with a binary unit
+func formatBytes(bytes int64) string {
+	switch {
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+)
+
+func TestStoreSize_Exceeds(t *testing.T) {
+	size := StoreSize{Events: 100, Bytes: 2048}
+
+	tests := []struct {
+		name      string
+		maxEvents int
+		maxBytes  int64
+		want      bool
+	}{
+		{"Below both limits", 200, 4096, false},
+		{"Over event limit", 99, 4096, true},
+		{"Over byte limit", 200, 1024, true},
+		{"Limits disabled", 0, 0, false},
+		{"At the limits", 100, 2048, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := size.Exceeds(tt.maxEvents, tt.maxBytes); got != tt.want {
+				t.Errorf("Exceeds(%d, %d) = %v, want %v", tt.maxEvents, tt.maxBytes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreSize_String(t *testing.T) {
+	tests := []struct {
+		size StoreSize
+		want string
+	}{
+		{StoreSize{Events: 3, Bytes: 512}, "3 events, 512 B"},
+		{StoreSize{Events: 40, Bytes: 1536}, "40 events, 1.5 KB"},
+		{StoreSize{Events: 6123, Bytes: 2202010}, "6123 events, 2.1 MB"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.size.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestManager_Size(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "size_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := NewManagerWithConfig(cfg)
+	if size := manager.Size(); size.Events != 0 || size.Bytes != 0 {
+		t.Errorf("Size() without a file = %v, want zero", size)
+	}
+
+	if err := manager.AddEvent(time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local), "09:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	info, err := os.Stat(cfg.EventsFilePath)
+	if err != nil {
+		t.Fatalf("Events file missing: %v", err)
+	}
+	if size := manager.Size(); size.Events != 1 || size.Bytes != info.Size() {
+		t.Errorf("Size() = %v, want 1 event and %d bytes", size, info.Size())
+	}
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/models"
+)
+
+// TimeWarning describes why the time or duration of an event looks like a typo: a start
+// in the configured unusual hours, often an AM/PM mix-up, or a duration longer than
+// configured, often a mistyped end time. It is empty for usual times and for events
+// whose time was confirmed.
+func (m *Manager) TimeWarning(event models.Event) string {
+	if event.TimeChecked || event.AllDay || m.config == nil {
+		return ""
+	}
+	checks := m.config.TimeChecks
+
+	var warnings []string
+	if inUnusualHours(event.Time, checks.UnusualFrom, checks.UnusualUntil) {
+		warning := fmt.Sprintf("%s is an unusual hour", event.GetTimeString())
+		if event.Time.Hour() < 12 {
+			warning += fmt.Sprintf(", did you mean %s?", event.Time.Add(12*time.Hour).Format("15:04"))
+		}
+		warnings = append(warnings, warning)
+	}
+	if checks.MaxDurationHours > 0 && event.Duration > time.Duration(checks.MaxDurationHours)*time.Hour {
+		hours, minutes := int(event.Duration/time.Hour), int(event.Duration%time.Hour/time.Minute)
+		length := fmt.Sprintf("%dh", hours)
+		if minutes > 0 {
+			length += fmt.Sprintf("%02d", minutes)
+		}
+		warnings = append(warnings, fmt.Sprintf("lasting %s is longer than %d hours, check the end time", length, checks.MaxDurationHours))
+	}
+	return strings.Join(warnings, "; ")
+}
+
+// inUnusualHours reports whether a time of day is from from until until, a range that
+// wraps past midnight when until is earlier. Invalid bounds never match.
+func inUnusualHours(t time.Time, from, until string) bool {
+	start, err := calendar.ParseTime(from)
+	if err != nil {
+		return false
+	}
+	end, err := calendar.ParseTime(until)
+	if err != nil {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	first, last := start.Hour()*60+start.Minute(), end.Hour()*60+end.Minute()
+	if first <= last {
+		return minute >= first && minute < last
+	}
+	return minute >= first || minute < last
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+func TestManager_TimeWarning(t *testing.T) {
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true, TimeChecks: config.DefaultTimeChecks})
+	at := func(hhmm string, duration time.Duration) models.Event {
+		eventTime, _ := time.Parse("15:04", hhmm)
+		return models.Event{Time: eventTime, Duration: duration}
+	}
+
+	tests := []struct {
+		name  string
+		event models.Event
+		want  string
+	}{
+		{"Usual time", at("09:00", time.Hour), ""},
+		{"Midnight is not unusual", at("00:30", 0), ""},
+		{"Small hours", at("03:00", 0), "03:00 is an unusual hour, did you mean 15:00?"},
+		{"End of the unusual hours", at("06:00", 0), ""},
+		{"Long duration", at("09:00", 14*time.Hour+30*time.Minute), "lasting 14h30 is longer than 12 hours, check the end time"},
+		{"Twelve hours", at("08:00", 12*time.Hour), ""},
+		{"Both", at("05:00", 13*time.Hour), "05:00 is an unusual hour, did you mean 17:00?; lasting 13h is longer than 12 hours, check the end time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manager.TimeWarning(tt.event); got != tt.want {
+				t.Errorf("TimeWarning() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	confirmed := at("03:00", 0)
+	confirmed.TimeChecked = true
+	if got := manager.TimeWarning(confirmed); got != "" {
+		t.Errorf("TimeWarning() of a confirmed event = %q, want none", got)
+	}
+
+	// The unusual hours may wrap past midnight; empty bounds turn the check off
+	manager.config.TimeChecks = config.TimeChecksConfig{UnusualFrom: "23:00", UnusualUntil: "05:00"}
+	if manager.TimeWarning(at("23:30", 0)) == "" || manager.TimeWarning(at("04:59", 0)) == "" || manager.TimeWarning(at("22:00", 0)) != "" {
+		t.Error("Unusual hours from 23:00 until 05:00 should cover 23:30 and 04:59 but not 22:00")
+	}
+	manager.config.TimeChecks = config.TimeChecksConfig{}
+	if got := manager.TimeWarning(at("03:00", 48*time.Hour)); got != "" {
+		t.Errorf("TimeWarning() with the checks off = %q, want none", got)
+	}
+}
+
+func TestManager_AddConfirmedEvent(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json"), TimeChecks: config.DefaultTimeChecks}
+	manager := NewManagerWithConfig(cfg)
+	date := time.Date(2025, 8, 20, 0, 0, 0, 0, time.Local)
+
+	if err := manager.AddConfirmedEvent(date, "04:30", "Airport", 0); err != nil {
+		t.Fatalf("AddConfirmedEvent() failed: %v", err)
+	}
+
+	reloaded := NewManagerWithConfig(cfg)
+	if err := reloaded.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	events := reloaded.GetEventsForDate(date)
+	if len(events) != 1 || !events[0].TimeChecked || reloaded.TimeWarning(events[0]) != "" {
+		t.Errorf("Reloaded events = %+v, want the confirmed event without a warning", events)
+	}
+}
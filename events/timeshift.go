@@ -0,0 +1,132 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+// TimeShift is an event before and after a batch time shift
+type TimeShift struct {
+	Before models.Event
+	After  models.Event
+}
+
+// ShiftEventTime moves an event by delta, changing its date when the time crosses midnight.
+// An event spanning several days keeps its length, and all-day events have no time to
+// shift and are returned unchanged. Date and time are treated as plain wall-clock
+// values, so no DST rules are applied.
+func ShiftEventTime(event models.Event, delta time.Duration) models.Event {
+	if event.AllDay {
+		return event
+	}
+	wallClock := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(),
+		event.Time.Hour(), event.Time.Minute(), 0, 0, time.UTC).Add(delta)
+
+	shifted := event
+	shifted.Date = time.Date(wallClock.Year(), wallClock.Month(), wallClock.Day(), 0, 0, 0, 0, time.Local)
+	shifted.Time = time.Date(0, 1, 1, wallClock.Hour(), wallClock.Minute(), 0, 0, time.UTC)
+	if !event.EndDate.IsZero() {
+		shifted.EndDate = event.EndDate.AddDate(0, 0, calendar.DaysBetween(event.Date, shifted.Date))
+	}
+	return shifted
+}
+
+// PlanTimeShift returns the shifts for all timed events dated from through to
+// (inclusive), in chronological order, without changing anything. All-day events are
+// left out, as they have no time to shift.
+func (m *Manager) PlanTimeShift(from, to time.Time, delta time.Duration) []TimeShift {
+	from = calendar.NormalizeDate(from)
+	to = calendar.NormalizeDate(to)
+
+	var shifts []TimeShift
+	for _, event := range m.events {
+		date := calendar.NormalizeDate(event.Date)
+		if event.AllDay || date.Before(from) || date.After(to) {
+			continue
+		}
+		shifts = append(shifts, TimeShift{Before: event, After: ShiftEventTime(event, delta)})
+	}
+
+	sort.SliceStable(shifts, func(i, j int) bool {
+		return eventMoment(shifts[i].Before).Before(eventMoment(shifts[j].Before))
+	})
+	return shifts
+}
+
+// eventMoment combines an event's date and time for ordering
+func eventMoment(event models.Event) time.Time {
+	return time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(),
+		event.Time.Hour(), event.Time.Minute(), 0, 0, time.UTC)
+}
+
+// InvertTimeShifts returns the shifts that undo the given ones
+func InvertTimeShifts(shifts []TimeShift) []TimeShift {
+	inverted := make([]TimeShift, len(shifts))
+	for i, shift := range shifts {
+		inverted[i] = TimeShift{Before: shift.After, After: shift.Before}
+	}
+	return inverted
+}
+
+// ApplyTimeShifts replaces every shifted event and saves the collection in one write.
+// All events are located before any is changed, so shifting one event onto the
+// former time of another cannot mix them up. It fails without changes when an
+// event is missing, e.g. because the events file was edited since the shift was planned.
+func (m *Manager) ApplyTimeShifts(shifts []TimeShift) error {
+	updated := make([]models.Event, len(m.events))
+	copy(updated, m.events)
+
+	used := make(map[int]bool)
+	for _, shift := range shifts {
+		index := -1
+		for i, existing := range m.events {
+			if !used[i] && sameEvent(existing, shift.Before) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("event not found: %s %s %s", shift.Before.GetDateString(), shift.Before.GetTimeString(), shift.Before.Description)
+		}
+		used[index] = true
+		updated[index] = shift.After
+	}
+
+	// Persist the whole collection in one write
+	if err := m.saveAll(updated); err != nil {
+		return fmt.Errorf("failed to save shifted events: %v", err)
+	}
+
+	m.events = updated
+	m.replaced()
+	return nil
+}
+
+// SaveTimeShifts writes shifts to an undo file
+func SaveTimeShifts(shifts []TimeShift, filename string) error {
+	before := make([]models.Event, len(shifts))
+	after := make([]models.Event, len(shifts))
+	for i, shift := range shifts {
+		before[i], after[i] = shift.Before, shift.After
+	}
+	return storage.SaveEventChangesJSON(before, after, filename)
+}
+
+// LoadTimeShifts reads shifts written by SaveTimeShifts
+func LoadTimeShifts(filename string) ([]TimeShift, error) {
+	before, after, err := storage.LoadEventChangesJSON(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	shifts := make([]TimeShift, len(before))
+	for i := range before {
+		shifts[i] = TimeShift{Before: before[i], After: after[i]}
+	}
+	return shifts, nil
+}
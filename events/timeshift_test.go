@@ -0,0 +1,152 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+func TestShiftEventTime(t *testing.T) {
+	tests := []struct {
+		date, time string
+		delta      time.Duration
+		wantDate   string
+		wantTime   string
+	}{
+		{"2025-03-30", "09:00", time.Hour, "2025-03-30", "10:00"},
+		{"2025-03-30", "23:30", time.Hour, "2025-03-31", "00:30"},
+		{"2025-03-01", "00:15", -time.Hour, "2025-02-28", "23:15"},
+		{"2025-10-26", "12:00", -90 * time.Minute, "2025-10-26", "10:30"},
+	}
+
+	for _, tt := range tests {
+		date, _ := time.ParseInLocation("2006-01-02", tt.date, time.Local)
+		eventTime, _ := time.Parse("15:04", tt.time)
+		event := models.Event{Date: date, Time: eventTime, Description: "Meeting", Category: "work"}
+
+		shifted := ShiftEventTime(event, tt.delta)
+		if shifted.GetDateString() != tt.wantDate || shifted.GetTimeString() != tt.wantTime {
+			t.Errorf("ShiftEventTime(%s %s, %v) = %s %s, want %s %s", tt.date, tt.time, tt.delta,
+				shifted.GetDateString(), shifted.GetTimeString(), tt.wantDate, tt.wantTime)
+		}
+		if shifted.Category != "work" || shifted.Description != "Meeting" {
+			t.Errorf("ShiftEventTime() should keep the other fields, got %+v", shifted)
+		}
+	}
+}
+
+func TestShiftEventTime_AllDay(t *testing.T) {
+	trip := models.Event{
+		Date:        time.Date(2025, 11, 2, 0, 0, 0, 0, time.Local),
+		EndDate:     time.Date(2025, 11, 4, 0, 0, 0, 0, time.Local),
+		AllDay:      true,
+		Description: "Trip",
+	}
+	if shifted := ShiftEventTime(trip, -time.Hour); !shifted.Date.Equal(trip.Date) || !shifted.EndDate.Equal(trip.EndDate) || !shifted.Time.Equal(trip.Time) {
+		t.Errorf("ShiftEventTime() moved an all-day event to %s %s - %s", shifted.GetDateString(), shifted.GetTimeString(), shifted.EndDate.Format("2006-01-02"))
+	}
+
+	manager := NewManager()
+	manager.events = []models.Event{trip}
+	if shifts := manager.PlanTimeShift(trip.Date, trip.EndDate, -time.Hour); len(shifts) != 0 {
+		t.Errorf("PlanTimeShift() = %v, want all-day events left out", shifts)
+	}
+}
+
+func TestShiftEventTime_MultiDay(t *testing.T) {
+	night := models.Event{
+		Date:        time.Date(2025, 11, 2, 0, 0, 0, 0, time.Local),
+		Time:        time.Date(0, 1, 1, 0, 30, 0, 0, time.UTC),
+		EndDate:     time.Date(2025, 11, 3, 0, 0, 0, 0, time.Local),
+		Description: "Night shift",
+	}
+	shifted := ShiftEventTime(night, -time.Hour)
+	if shifted.GetDateString() != "2025-11-01" || shifted.GetTimeString() != "23:30" || shifted.EndDate.Format("2006-01-02") != "2025-11-02" {
+		t.Errorf("ShiftEventTime() = %s %s until %s, want 2025-11-01 23:30 until 2025-11-02",
+			shifted.GetDateString(), shifted.GetTimeString(), shifted.EndDate.Format("2006-01-02"))
+	}
+	if same := ShiftEventTime(night, time.Hour); !same.EndDate.Equal(night.EndDate) {
+		t.Errorf("ShiftEventTime() within the day moved the end to %s", same.EndDate.Format("2006-01-02"))
+	}
+}
+
+func TestManager_TimeShiftAndUndo(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "timeshift_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := NewManagerWithConfig(cfg)
+
+	day := time.Date(2025, 3, 30, 0, 0, 0, 0, time.Local)
+	// Shifting 09:00 onto 10:00 while the 10:00 event moves on must not mix them up
+	for _, e := range []struct {
+		date              time.Time
+		time, description string
+	}{
+		{day, "10:00", "Review"},
+		{day, "09:00", "Review"},
+		{day.AddDate(0, 0, 1), "08:00", "Outside range"},
+	} {
+		if err := manager.AddEvent(e.date, e.time, e.description); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+	}
+
+	shifts := manager.PlanTimeShift(day, day, time.Hour)
+	if len(shifts) != 2 || shifts[0].Before.GetTimeString() != "09:00" {
+		t.Fatalf("PlanTimeShift() = %v, want the two events of the day, earliest first", shifts)
+	}
+	if manager.GetEventsForDate(day)[0].GetTimeString() != "09:00" {
+		t.Error("PlanTimeShift() must not change events")
+	}
+
+	if err := manager.ApplyTimeShifts(shifts); err != nil {
+		t.Fatalf("ApplyTimeShifts() failed: %v", err)
+	}
+	assertTimes(t, manager.GetEventsForDate(day), "10:00", "11:00")
+
+	stored, err := storage.LoadEventsJSON(cfg.EventsFilePath)
+	if err != nil || len(stored) != 3 {
+		t.Fatalf("Stored events = %v, %v; want 3 events", stored, err)
+	}
+
+	undoFile := filepath.Join(tempDir, "undo.json")
+	if err := SaveTimeShifts(shifts, undoFile); err != nil {
+		t.Fatalf("SaveTimeShifts() failed: %v", err)
+	}
+	loaded, err := LoadTimeShifts(undoFile)
+	if err != nil {
+		t.Fatalf("LoadTimeShifts() failed: %v", err)
+	}
+
+	if err := manager.ApplyTimeShifts(InvertTimeShifts(loaded)); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	assertTimes(t, manager.GetEventsForDate(day), "09:00", "10:00")
+
+	// Applying the same undo again finds nothing to revert
+	if err := manager.ApplyTimeShifts(InvertTimeShifts(loaded)); err == nil {
+		t.Error("ApplyTimeShifts() should fail when shifted events are missing")
+	}
+}
+
+// assertTimes checks the times of a list of events
+func assertTimes(t *testing.T, list []models.Event, times ...string) {
+	t.Helper()
+	if len(list) != len(times) {
+		t.Fatalf("Got %d events, want %d", len(list), len(times))
+	}
+	for i, event := range list {
+		if event.GetTimeString() != times[i] {
+			t.Errorf("Event %d time = %s, want %s", i, event.GetTimeString(), times[i])
+		}
+	}
+}
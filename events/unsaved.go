@@ -0,0 +1,95 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+// Backoff between retries of a failed write: the delay doubles with every attempt
+const (
+	firstRetryDelay = 5 * time.Second
+	maxRetryDelay   = 5 * time.Minute
+)
+
+// WriteFailure describes a storage write that failed while write recovery is on; the
+// events in memory are ahead of the events file until a retry succeeds
+type WriteFailure struct {
+	Err       error     // Error of the most recent attempt
+	Since     time.Time // When the first write failed
+	Attempts  int       // Retries made so far
+	NextRetry time.Time // When the next retry is due
+	CopyPath  string    // File the events were last copied to, empty before any copy
+}
+
+// retryDelay returns the wait before retry number attempt, starting at 0
+func retryDelay(attempt int) time.Duration {
+	delay := firstRetryDelay
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// EnableWriteRecovery keeps changes whose write fails in memory instead of rejecting
+// them, for interactive sessions that can retry the write later. Until RetryWrite
+// succeeds no further writes are attempted: the retry writes the whole collection.
+func (m *Manager) EnableWriteRecovery() {
+	m.writeRecovery = true
+}
+
+// Unsaved reports the failed write the events in memory are waiting on, if any
+func (m *Manager) Unsaved() (WriteFailure, bool) {
+	if m.unsaved == nil {
+		return WriteFailure{}, false
+	}
+	return *m.unsaved, true
+}
+
+// RetryWrite writes all events in memory, including those whose deletion is pending, to
+// the events file, ending the failure when it succeeds and otherwise scheduling the next
+// retry with a longer delay
+func (m *Manager) RetryWrite(now time.Time) error {
+	if m.unsaved == nil {
+		return nil
+	}
+
+	if err := m.writeAll(m.storedEvents()); err != nil {
+		m.unsaved.Err = err
+		m.unsaved.NextRetry = now.Add(retryDelay(m.unsaved.Attempts + 1))
+		m.unsaved.Attempts++
+		return err
+	}
+
+	// Deletions waiting for their undo delay are written along with the other events,
+	// so they stay pending and can still be undone
+	m.unsaved = nil
+	m.recordStamps()
+	return nil
+}
+
+// WriteCopy writes all events in memory to path as JSON, such as an emergency file on
+// another disk, while the events file cannot be written. The events file stays
+// behind, so retries go on.
+func (m *Manager) WriteCopy(path string) error {
+	if err := storage.SaveEventsJSON(append([]models.Event(nil), m.events...), path); err != nil {
+		return fmt.Errorf("failed to write a copy of the events: %v", err)
+	}
+	if m.unsaved != nil {
+		m.unsaved.CopyPath = path
+	}
+	return nil
+}
+
+// recordWriteFailure starts or continues waiting on a failed write
+func (m *Manager) recordWriteFailure(err error, now time.Time) {
+	if m.unsaved == nil {
+		m.unsaved = &WriteFailure{Since: now, NextRetry: now.Add(retryDelay(0))}
+	}
+	m.unsaved.Err = err
+}
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/storage"
+)
+
+func TestManager_WriteRecovery(t *testing.T) {
+	dir := t.TempDir()
+	// A file where the data directory should be makes every write fail
+	dataDir := filepath.Join(dir, "mount")
+	if err := os.WriteFile(dataDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManagerWithConfig(&config.Config{EventsFilePath: filepath.Join(dataDir, "events.json")})
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+
+	if err := manager.AddEvent(day, "09:00", "Standup"); err == nil {
+		t.Fatal("AddEvent() should fail without write recovery")
+	}
+
+	manager.EnableWriteRecovery()
+	for _, description := range []string{"Standup", "Review"} {
+		if err := manager.AddEvent(day, "10:00", description); err != nil {
+			t.Fatalf("AddEvent() with write recovery failed: %v", err)
+		}
+	}
+	if got := len(manager.GetEventsForDate(day)); got != 2 {
+		t.Errorf("Events in memory = %d, want both kept", got)
+	}
+	failure, ok := manager.Unsaved()
+	if !ok || failure.Err == nil || failure.Attempts != 0 {
+		t.Fatalf("Unsaved() = %+v, %v; want the failed write", failure, ok)
+	}
+	if err := manager.LoadEvents(); err == nil {
+		t.Error("LoadEvents() should refuse to drop unsaved changes")
+	}
+
+	now := failure.NextRetry
+	if err := manager.RetryWrite(now); err == nil {
+		t.Fatal("RetryWrite() should fail while the data directory is missing")
+	}
+	failure, _ = manager.Unsaved()
+	if failure.Attempts != 1 || !failure.NextRetry.Equal(now.Add(10*time.Second)) {
+		t.Errorf("After a failed retry Unsaved() = %+v, want the next retry in 10s", failure)
+	}
+
+	copyPath := filepath.Join(dir, "unsaved.json")
+	if err := manager.WriteCopy(copyPath); err != nil {
+		t.Fatalf("WriteCopy() failed: %v", err)
+	}
+	if copied, err := storage.LoadEventsJSON(copyPath); err != nil || len(copied) != 2 {
+		t.Errorf("Copy holds %d events (%v), want 2", len(copied), err)
+	}
+	if failure, _ := manager.Unsaved(); failure.CopyPath != copyPath {
+		t.Errorf("CopyPath = %q, want %q", failure.CopyPath, copyPath)
+	}
+
+	// The mount comes back
+	if err := os.Remove(dataDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(dataDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RetryWrite(now); err != nil {
+		t.Fatalf("RetryWrite() failed: %v", err)
+	}
+	if _, ok := manager.Unsaved(); ok {
+		t.Error("Unsaved() should report nothing after a successful retry")
+	}
+	stored, err := storage.LoadEventsJSON(filepath.Join(dataDir, "events.json"))
+	if err != nil || len(stored) != 2 {
+		t.Errorf("Events file holds %d events (%v), want 2", len(stored), err)
+	}
+}
+
+func TestManager_RetryWriteKeepsPendingDeletes(t *testing.T) {
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "mount")
+	if err := os.WriteFile(dataDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	eventsPath := filepath.Join(dataDir, "events.json")
+	manager := NewManagerWithConfig(&config.Config{EventsFilePath: eventsPath})
+	manager.EnableWriteRecovery()
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	for _, description := range []string{"Standup", "Review"} {
+		if err := manager.AddEvent(day, "10:00", description); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+	}
+
+	now := time.Now()
+	due := now.Add(time.Minute)
+	review := manager.GetEventsForDate(day)[1]
+	if err := manager.DeferDelete(review, due); err != nil {
+		t.Fatalf("DeferDelete() failed: %v", err)
+	}
+
+	// The retry writes the review too, as its deletion can still be undone
+	if err := os.Remove(dataDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(dataDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.RetryWrite(now); err != nil {
+		t.Fatalf("RetryWrite() failed: %v", err)
+	}
+	if stored, err := storage.LoadEventsJSON(eventsPath); err != nil || len(stored) != 2 {
+		t.Errorf("Events file holds %d events (%v), want both until the deletion is due", len(stored), err)
+	}
+	if pending := manager.PendingDeletes(); len(pending) != 1 || pending[0].Event.Description != "Review" {
+		t.Fatalf("PendingDeletes() after the retry = %v, want the review", pending)
+	}
+
+	if err := manager.CommitDeletes(due); err != nil {
+		t.Fatalf("CommitDeletes() failed: %v", err)
+	}
+	if stored, err := storage.LoadEventsJSON(eventsPath); err != nil || len(stored) != 1 || stored[0].Description != "Standup" {
+		t.Errorf("Events file after the deletion = %v (%v), want only the standup", stored, err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 5 * time.Second},
+		{1, 10 * time.Second},
+		{3, 40 * time.Second},
+		{6, 5 * time.Minute},
+		{50, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempt); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"os"
+	"time"
+)
+
+// fileStamp identifies the content of an events file as last read or written by the
+// manager, so that its own writes are told apart from those of other programs
+type fileStamp struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// stampOf returns the current stamp of the file at path
+func stampOf(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
+
+// recordStamps remembers the events files as they are now, after a load or a write
+func (m *Manager) recordStamps() {
+	if m.config == nil || m.ReadOnly() {
+		return
+	}
+	m.stamps = make(map[string]fileStamp)
+	for _, path := range m.eventFiles() {
+		m.stamps[path] = stampOf(path)
+	}
+}
+
+// WatchedFiles returns the events files another program may change while the calendar
+// runs; none in dry-run and ephemeral mode, whose events in memory are not the file's
+func (m *Manager) WatchedFiles() []string {
+	if m.config == nil || m.ReadOnly() {
+		return nil
+	}
+	return m.eventFiles()
+}
+
+// ChangedOnDisk reports whether an events file differs from the one the manager last
+// read or wrote, meaning another program changed it and the events should be reloaded
+func (m *Manager) ChangedOnDisk() bool {
+	if m.stamps == nil {
+		return false
+	}
+	for path, stamp := range m.stamps {
+		if stampOf(path) != stamp {
+			return true
+		}
+	}
+	return false
+}
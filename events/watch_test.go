@@ -0,0 +1,59 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+func TestManager_ChangedOnDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.json")
+	// An existing events file keeps the legacy events.txt from being migrated
+	if err := storage.SaveEventsJSON(nil, path); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManagerWithConfig(&config.Config{EventsFilePath: path})
+	if err := manager.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	day := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+
+	// The manager's own writes are not changes on disk
+	if err := manager.AddEvent(day, "09:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if manager.ChangedOnDisk() {
+		t.Error("ChangedOnDisk() after the manager's own write = true, want false")
+	}
+
+	// Another program adds an event
+	events := append(manager.GetAllEvents(), models.Event{Date: day, Time: time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC), Description: "Synced review"})
+	if err := storage.SaveEventsJSON(events, path); err != nil {
+		t.Fatal(err)
+	}
+	// Make sure the file differs even where modification times are coarse
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if !manager.ChangedOnDisk() {
+		t.Fatal("ChangedOnDisk() after another program's write = false, want true")
+	}
+	if err := manager.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	if manager.GetEventCount() != 2 || manager.ChangedOnDisk() {
+		t.Errorf("After reloading: %d events, changed on disk %v; want 2 and false", manager.GetEventCount(), manager.ChangedOnDisk())
+	}
+
+	// Dry-run sessions keep their own events in memory and watch nothing
+	dryRun := NewManagerWithConfig(&config.Config{EventsFilePath: path, DryRun: true})
+	if files := dryRun.WatchedFiles(); len(files) != 0 {
+		t.Errorf("WatchedFiles() in dry-run mode = %v, want none", files)
+	}
+}
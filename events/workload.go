@@ -0,0 +1,81 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"go-ascii-calendar/calendar"
+)
+
+// DayLoad is what one day holds: its events and the time they take
+type DayLoad struct {
+	Events    int
+	Scheduled time.Duration
+}
+
+// DayLoad counts the events occurring on date and sums the time they take that day.
+// Quick filters do not apply, so it is the load of the whole calendar. All-day events
+// count without taking time, and a timed event only counts the part of it on date.
+func (m *Manager) DayLoad(date time.Time) DayLoad {
+	dayStart := calendar.NormalizeDate(date)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	var load DayLoad
+	for _, event := range m.events {
+		if !event.OccursOn(dayStart) {
+			continue
+		}
+		load.Events++
+		if event.AllDay || event.Duration <= 0 {
+			continue
+		}
+
+		start := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(),
+			event.Time.Hour(), event.Time.Minute(), 0, 0, dayStart.Location())
+		end := start.Add(event.Duration)
+		if start.Before(dayStart) {
+			start = dayStart
+		}
+		if end.After(dayEnd) {
+			end = dayEnd
+		}
+		if end.After(start) {
+			load.Scheduled += end.Sub(start)
+		}
+	}
+	return load
+}
+
+// WorkloadWarning returns a note such as "Overbooked: 7h 30m scheduled" when date holds
+// more events or scheduled time than the configured workload limits, and "" otherwise
+func (m *Manager) WorkloadWarning(date time.Time) string {
+	if m.config == nil {
+		return ""
+	}
+	limits := m.config.Workload
+	if limits.MaxEvents <= 0 && limits.MaxMinutes <= 0 {
+		return ""
+	}
+
+	load := m.DayLoad(date)
+	if limits.MaxMinutes > 0 && load.Scheduled > time.Duration(limits.MaxMinutes)*time.Minute {
+		return fmt.Sprintf("Overbooked: %s scheduled", formatWorkload(load.Scheduled))
+	}
+	if limits.MaxEvents > 0 && load.Events > limits.MaxEvents {
+		return fmt.Sprintf("Overbooked: %d events scheduled", load.Events)
+	}
+	return ""
+}
+
+// formatWorkload formats a duration as hours and minutes, e.g. "7h 30m", "8h" or "45m"
+func formatWorkload(d time.Duration) string {
+	hours, minutes := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+}
@@ -0,0 +1,73 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+)
+
+func TestManager_DayLoad(t *testing.T) {
+	manager := NewManagerWithConfig(&config.Config{Ephemeral: true})
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	manager.AddEventWithDuration(date, "09:00", "Workshop", 3*time.Hour)
+	manager.AddEventWithDuration(date, "13:00", "Review", 90*time.Minute)
+	manager.AddEvent(date, "18:00", "Call")
+	manager.AddEventWithDuration(date, "23:00", "Night shift", 8*time.Hour)
+	manager.AddMultiDayEvent(date.AddDate(0, 0, -1), date.AddDate(0, 0, 1), "", "Conference", true)
+
+	// A filter hiding the events does not change the load
+	manager.ToggleFilter(config.QuickFilter{Key: "F1", Query: "nothing matches"})
+
+	load := manager.DayLoad(date)
+	if load.Events != 5 {
+		t.Errorf("DayLoad().Events = %d, want 5", load.Events)
+	}
+	if want := 3*time.Hour + 90*time.Minute + time.Hour; load.Scheduled != want {
+		t.Errorf("DayLoad().Scheduled = %v, want %v counting the night shift up to midnight", load.Scheduled, want)
+	}
+	if got := manager.DayLoad(date.AddDate(0, 0, 1)); got.Events != 1 || got.Scheduled != 0 {
+		t.Errorf("DayLoad() of the next day = %+v, want the all-day conference without time", got)
+	}
+}
+
+func TestManager_WorkloadWarning(t *testing.T) {
+	cfg := &config.Config{Ephemeral: true}
+	manager := NewManagerWithConfig(cfg)
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	manager.AddEventWithDuration(date, "09:00", "Workshop", 6*time.Hour)
+	manager.AddEventWithDuration(date, "16:00", "Review", 90*time.Minute)
+	manager.AddEvent(date, "18:00", "Call")
+
+	tests := []struct {
+		name     string
+		workload config.WorkloadConfig
+		want     string
+	}{
+		{"no limits", config.WorkloadConfig{}, ""},
+		{"within limits", config.WorkloadConfig{MaxEvents: 3, MaxMinutes: 480}, ""},
+		{"too much time", config.WorkloadConfig{MaxMinutes: 420}, "Overbooked: 7h 30m scheduled"},
+		{"too many events", config.WorkloadConfig{MaxEvents: 2}, "Overbooked: 3 events scheduled"},
+		{"both", config.WorkloadConfig{MaxEvents: 2, MaxMinutes: 60}, "Overbooked: 7h 30m scheduled"},
+	}
+	for _, tt := range tests {
+		cfg.Workload = tt.workload
+		if got := manager.WorkloadWarning(date); got != tt.want {
+			t.Errorf("WorkloadWarning(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatWorkload(t *testing.T) {
+	tests := map[time.Duration]string{
+		45 * time.Minute:             "45m",
+		8 * time.Hour:                "8h",
+		7*time.Hour + 30*time.Minute: "7h 30m",
+		25*time.Hour + 5*time.Minute: "25h 5m",
+	}
+	for d, want := range tests {
+		if got := formatWorkload(d); got != want {
+			t.Errorf("formatWorkload(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -2,7 +2,12 @@ module go-ascii-calendar
 
 go 1.22
 
+require (
+	github.com/fsnotify/fsnotify v1.8.0
+	github.com/nsf/termbox-go v1.1.1
+)
+
 require (
 	github.com/mattn/go-runewidth v0.0.9 // indirect
-	github.com/nsf/termbox-go v1.1.1 // indirect
+	golang.org/x/sys v0.13.0 // indirect
 )
@@ -0,0 +1,219 @@
+// Package holidays provides the public holidays shown in the calendar: a built-in set
+// for a country, holidays from an ICS file and the holidays listed in the configuration.
+package holidays
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/storage"
+)
+
+// rule computes the date of a holiday in a year
+type rule struct {
+	name string
+	date func(year int) time.Time
+}
+
+// Calendar answers which holidays fall on a date
+type Calendar struct {
+	rules  []rule              // Built-in holidays of the country
+	dated  map[string][]string // Names by YYYY-MM-DD
+	yearly map[string][]string // Names by MM-DD, repeated every year
+	years  map[int]map[string][]string
+}
+
+// Countries returns the codes of the countries with built-in holidays, sorted
+func Countries() []string {
+	codes := make([]string, 0, len(builtin))
+	for code := range builtin {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Load builds the holiday calendar of the configuration. A country or file that cannot
+// be loaded is reported as an error, and the calendar holds the other holidays.
+func Load(cfg *config.Config) (*Calendar, error) {
+	c := &Calendar{dated: map[string][]string{}, yearly: map[string][]string{}, years: map[int]map[string][]string{}}
+	if cfg == nil {
+		return c, nil
+	}
+	for _, holiday := range cfg.Holidays {
+		if len(holiday.Date) == len("01-02") {
+			c.yearly[holiday.Date] = append(c.yearly[holiday.Date], holiday.Name)
+		} else {
+			c.dated[holiday.Date] = append(c.dated[holiday.Date], holiday.Name)
+		}
+	}
+
+	var problems []string
+	if cfg.HolidayCountry != "" {
+		if rules, ok := builtin[strings.ToUpper(cfg.HolidayCountry)]; ok {
+			c.rules = rules
+		} else {
+			problems = append(problems, fmt.Sprintf("no built-in holidays for %q (available: %s)",
+				cfg.HolidayCountry, strings.Join(Countries(), ", ")))
+		}
+	}
+	if cfg.HolidayFile != "" {
+		if err := c.loadICS(cfg.HolidayFile); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", cfg.HolidayFile, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return c, fmt.Errorf("holidays failed to load: %s", strings.Join(problems, "; "))
+	}
+	return c, nil
+}
+
+// loadICS adds the events of an ICS file as holidays, each day of one spanning several
+func (c *Calendar) loadICS(path string) error {
+	events, err := storage.LoadICSFile(path)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		for day := event.Date; !day.After(event.LastDate()); day = day.AddDate(0, 0, 1) {
+			key := day.Format("2006-01-02")
+			c.dated[key] = append(c.dated[key], event.Description)
+		}
+	}
+	return nil
+}
+
+// Names returns the names of the holidays on date; a nil calendar has none
+func (c *Calendar) Names(date time.Time) []string {
+	if c == nil {
+		return nil
+	}
+	var names []string
+	names = append(names, c.forYear(date.Year())[date.Format("01-02")]...)
+	names = append(names, c.yearly[date.Format("01-02")]...)
+	names = append(names, c.dated[date.Format("2006-01-02")]...)
+	return names
+}
+
+// forYear returns the built-in holidays of a year by MM-DD, computing them once
+func (c *Calendar) forYear(year int) map[string][]string {
+	if days, ok := c.years[year]; ok {
+		return days
+	}
+	days := make(map[string][]string, len(c.rules))
+	for _, r := range c.rules {
+		if date := r.date(year); !date.IsZero() {
+			key := date.Format("01-02")
+			days[key] = append(days[key], r.name)
+		}
+	}
+	c.years[year] = days
+	return days
+}
+
+// fixed is a holiday on the same date every year
+func fixed(month time.Month, day int) func(int) time.Time {
+	return func(year int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+}
+
+// since is a holiday first held in the year from
+func since(from int, date func(int) time.Time) func(int) time.Time {
+	return func(year int) time.Time {
+		if year < from {
+			return time.Time{}
+		}
+		return date(year)
+	}
+}
+
+// nthWeekday is the nth weekday of a month, or the last one for n = -1
+func nthWeekday(month time.Month, weekday time.Weekday, n int) func(int) time.Time {
+	return func(year int) time.Time {
+		if n < 0 {
+			last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.Local)
+			return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7))
+		}
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+		return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(n-1))
+	}
+}
+
+// easter is a holiday offset days from Easter Sunday
+func easter(offset int) func(int) time.Time {
+	return func(year int) time.Time {
+		return easterSunday(year).AddDate(0, 0, offset)
+	}
+}
+
+// easterSunday returns the date of Easter in the Gregorian calendar
+func easterSunday(year int) time.Time {
+	a, b, c := year%19, year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
+}
+
+// builtin holds the public holidays of each country by ISO code, on their actual
+// dates; days in lieu of holidays falling on a weekend are not included
+var builtin = map[string][]rule{
+	"US": {
+		{"New Year's Day", fixed(time.January, 1)},
+		{"Martin Luther King Jr. Day", nthWeekday(time.January, time.Monday, 3)},
+		{"Washington's Birthday", nthWeekday(time.February, time.Monday, 3)},
+		{"Memorial Day", nthWeekday(time.May, time.Monday, -1)},
+		{"Juneteenth", since(2021, fixed(time.June, 19))},
+		{"Independence Day", fixed(time.July, 4)},
+		{"Labor Day", nthWeekday(time.September, time.Monday, 1)},
+		{"Columbus Day", nthWeekday(time.October, time.Monday, 2)},
+		{"Veterans Day", fixed(time.November, 11)},
+		{"Thanksgiving Day", nthWeekday(time.November, time.Thursday, 4)},
+		{"Christmas Day", fixed(time.December, 25)},
+	},
+	"GB": {
+		{"New Year's Day", fixed(time.January, 1)},
+		{"Good Friday", easter(-2)},
+		{"Easter Monday", easter(1)},
+		{"Early May Bank Holiday", nthWeekday(time.May, time.Monday, 1)},
+		{"Spring Bank Holiday", nthWeekday(time.May, time.Monday, -1)},
+		{"Summer Bank Holiday", nthWeekday(time.August, time.Monday, -1)},
+		{"Christmas Day", fixed(time.December, 25)},
+		{"Boxing Day", fixed(time.December, 26)},
+	},
+	"DE": {
+		{"New Year's Day", fixed(time.January, 1)},
+		{"Good Friday", easter(-2)},
+		{"Easter Monday", easter(1)},
+		{"Labour Day", fixed(time.May, 1)},
+		{"Ascension Day", easter(39)},
+		{"Whit Monday", easter(50)},
+		{"German Unity Day", fixed(time.October, 3)},
+		{"Christmas Day", fixed(time.December, 25)},
+		{"Second Day of Christmas", fixed(time.December, 26)},
+	},
+	"FR": {
+		{"New Year's Day", fixed(time.January, 1)},
+		{"Easter Monday", easter(1)},
+		{"Labour Day", fixed(time.May, 1)},
+		{"Victory in Europe Day", fixed(time.May, 8)},
+		{"Ascension Day", easter(39)},
+		{"Whit Monday", easter(50)},
+		{"Bastille Day", fixed(time.July, 14)},
+		{"Assumption of Mary", fixed(time.August, 15)},
+		{"All Saints' Day", fixed(time.November, 1)},
+		{"Armistice Day", fixed(time.November, 11)},
+		{"Christmas Day", fixed(time.December, 25)},
+	},
+}
@@ -0,0 +1,105 @@
+package holidays
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestEasterSunday(t *testing.T) {
+	for year, want := range map[int]time.Time{
+		2024: day(2024, 3, 31),
+		2025: day(2025, 4, 20),
+		2026: day(2026, 4, 5),
+		2038: day(2038, 4, 25),
+	} {
+		if got := easterSunday(year); !got.Equal(want) {
+			t.Errorf("easterSunday(%d) = %s, want %s", year, got.Format("2006-01-02"), want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestLoad_Builtin(t *testing.T) {
+	tests := []struct {
+		country string
+		date    time.Time
+		want    string
+	}{
+		{"US", day(2025, 1, 20), "Martin Luther King Jr. Day"},
+		{"us", day(2025, 5, 26), "Memorial Day"},
+		{"US", day(2025, 9, 1), "Labor Day"},
+		{"US", day(2025, 11, 27), "Thanksgiving Day"},
+		{"US", day(2020, 6, 19), ""},
+		{"US", day(2025, 6, 19), "Juneteenth"},
+		{"GB", day(2025, 4, 18), "Good Friday"},
+		{"GB", day(2025, 8, 25), "Summer Bank Holiday"},
+		{"DE", day(2025, 5, 29), "Ascension Day"},
+		{"DE", day(2025, 6, 9), "Whit Monday"},
+		{"FR", day(2025, 7, 14), "Bastille Day"},
+		{"FR", day(2025, 7, 15), ""},
+	}
+	for _, tt := range tests {
+		calendar, err := Load(&config.Config{HolidayCountry: tt.country})
+		if err != nil {
+			t.Fatalf("Load(%s) failed: %v", tt.country, err)
+		}
+		if got := strings.Join(calendar.Names(tt.date), ", "); got != tt.want {
+			t.Errorf("%s holidays on %s = %q, want %q", tt.country, tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+
+	if _, err := Load(&config.Config{HolidayCountry: "XX"}); err == nil || !strings.Contains(err.Error(), "DE, FR, GB, US") {
+		t.Errorf("Load(XX) error = %v, want the available countries", err)
+	}
+}
+
+func TestLoad_FileAndConfigured(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.ics")
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20251027\r\nDTEND;VALUE=DATE:20251101\r\n" +
+		"SUMMARY:Autumn half term\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	if err := os.WriteFile(path, []byte(ics), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	calendar, err := Load(&config.Config{
+		HolidayCountry: "GB",
+		HolidayFile:    path,
+		Holidays:       []config.Holiday{{Name: "Office closed", Date: "12-24"}, {Name: "Company day", Date: "2025-10-31"}},
+	})
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	tests := []struct {
+		date time.Time
+		want string
+	}{
+		{day(2025, 10, 26), ""},
+		{day(2025, 10, 27), "Autumn half term"},
+		{day(2025, 10, 31), "Company day, Autumn half term"},
+		{day(2025, 11, 1), ""},
+		{day(2026, 12, 24), "Office closed"},
+		{day(2025, 12, 25), "Christmas Day"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(calendar.Names(tt.date), ", "); got != tt.want {
+			t.Errorf("Names(%s) = %q, want %q", tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+
+	if _, err := Load(&config.Config{HolidayFile: filepath.Join(t.TempDir(), "missing.ics")}); err == nil {
+		t.Error("Load() with a missing file should fail")
+	}
+	var none *Calendar
+	if names := none.Names(day(2025, 12, 25)); names != nil {
+		t.Errorf("Names() of a nil calendar = %v, want none", names)
+	}
+}
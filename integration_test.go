@@ -220,7 +220,6 @@ func TestTerminalCompatibility(t *testing.T) {
 			{"Navigation up", 'k', terminal.ActionMoveUp},
 			{"Navigation down", 'j', terminal.ActionMoveDown},
 			{"Navigation left", 'h', terminal.ActionMoveLeft},
-			{"Navigation right", 'l', terminal.ActionMoveRight},
 			{"Previous month", 'b', terminal.ActionMonthPrev},
 			{"Next month", 'n', terminal.ActionMonthNext},
 			{"Add event", 'a', terminal.ActionAddEvent},
@@ -245,6 +244,14 @@ func TestTerminalCompatibility(t *testing.T) {
 				}
 			})
 		}
+
+		// Lowercase l moves right while Shift+L opens the activity log
+		if action := ih.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: 'l'}); action != terminal.ActionMoveRight {
+			t.Errorf("Lowercase l: expected %v, got %v", terminal.ActionMoveRight, action)
+		}
+		if action := ih.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: 'L'}); action != terminal.ActionShowActivityLog {
+			t.Errorf("Uppercase L: expected %v, got %v", terminal.ActionShowActivityLog, action)
+		}
 	})
 
 	t.Run("ValidationAndEdgeCases", func(t *testing.T) {
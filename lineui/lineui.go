@@ -0,0 +1,336 @@
+package lineui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/events"
+	"go-ascii-calendar/models"
+)
+
+// UI is a line-based interactive calendar for terminals where the full-screen
+// interface cannot run (CI, serial consoles). It uses the same event manager as the TUI.
+type UI struct {
+	events       *events.Manager
+	weekStartDay int
+	weekdays     []string
+	months       []string
+	dateFormat   string
+	searchOrder  string
+	selected     time.Time
+	scanner      *bufio.Scanner
+	out          io.Writer
+}
+
+// New creates a line-based UI reading commands from in and writing to out
+func New(cfg *config.Config, manager *events.Manager, in io.Reader, out io.Writer) *UI {
+	weekStartDay := 0
+	var weekdays, months []string
+	dateFormat := ""
+	searchOrder := config.SearchOrderDate
+	if cfg != nil {
+		weekStartDay = int(cfg.WeekStartDay)
+		weekdays = cfg.WeekdayNames
+		months = cfg.MonthNames
+		dateFormat = cfg.DateFormat
+		searchOrder = cfg.SearchOrder
+	}
+
+	return &UI{
+		events:       manager,
+		weekStartDay: weekStartDay,
+		weekdays:     weekdays,
+		months:       months,
+		dateFormat:   dateFormat,
+		searchOrder:  searchOrder,
+		selected:     calendar.NormalizeDate(time.Now()),
+		scanner:      bufio.NewScanner(in),
+		out:          out,
+	}
+}
+
+// menu lists the available actions by number
+const menu = `  1) Previous month   2) Next month    3) Go to date
+  4) Add event        5) Edit event    6) Delete event
+  7) Search           8) Today         0) Quit`
+
+// Run shows the calendar and processes menu choices until the user quits or input ends
+func (u *UI) Run() error {
+	for {
+		u.printOverview()
+
+		choice, ok := u.prompt("Choice:")
+		if !ok {
+			return nil // End of input
+		}
+
+		switch choice {
+		case "1":
+			u.selected = shiftMonth(u.selected, -1)
+		case "2":
+			u.selected = shiftMonth(u.selected, 1)
+		case "3":
+			u.goToDate()
+		case "4":
+			u.addEvent()
+		case "5":
+			u.editEvent()
+		case "6":
+			u.deleteEvent()
+		case "7":
+			u.search()
+		case "8":
+			u.selected = calendar.NormalizeDate(time.Now())
+		case "0", "q", "quit":
+			return nil
+		default:
+			fmt.Fprintf(u.out, "Unknown choice %q\n", choice)
+		}
+	}
+}
+
+// SetSelectedDate changes the date the UI starts on
+func (u *UI) SetSelectedDate(date time.Time) {
+	u.selected = calendar.NormalizeDate(date)
+}
+
+// shiftMonth moves date by months, keeping the day within the target month
+func shiftMonth(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location()).AddDate(0, months, 0)
+	day := date.Day()
+	if days := calendar.GetDaysInMonth(first); day > days {
+		day = days
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, date.Location())
+}
+
+// printOverview prints the month of the selected date, its events and the menu
+func (u *UI) printOverview() {
+	fmt.Fprintln(u.out)
+	fmt.Fprint(u.out, RenderMonth(u.selected, u.selected, u.weekStartDay, u.weekdays, u.months, u.events.HasEventsForDate))
+	fmt.Fprintln(u.out)
+
+	fmt.Fprintf(u.out, "Events for %s:\n", calendar.FormatLocalDate(u.selected, u.dateFormat))
+	u.printEvents(u.events.GetEventsForDate(u.selected))
+	fmt.Fprintln(u.out)
+	fmt.Fprintln(u.out, menu)
+}
+
+// RenderMonth returns a plain-text month grid. The selected day is shown in brackets
+// and days with events are marked with an asterisk. Weekdays holds the day-of-week
+// headers, Sunday first, and months the month names, January first; without them the
+// English ones are used.
+func RenderMonth(month, selected time.Time, weekStartDay int, weekdays, months []string, hasEvents func(time.Time) bool) string {
+	var b strings.Builder
+
+	title := fmt.Sprintf("%s %d", calendar.GetLocalizedMonthName(months, month), month.Year())
+	b.WriteString(fmt.Sprintf("%*s\n", (28+utf8.RuneCountInString(title))/2, title))
+
+	for _, header := range calendar.GetLocalizedDayOfWeekHeaders(weekdays, weekStartDay) {
+		b.WriteString(fmt.Sprintf(" %s ", header))
+	}
+	b.WriteString("\n")
+
+	for _, week := range calendar.GetCalendarWeeks(month, weekStartDay) {
+		for _, day := range week {
+			if day == 0 {
+				b.WriteString("    ")
+				continue
+			}
+
+			date := time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, month.Location())
+			marker := " "
+			if hasEvents != nil && hasEvents(date) {
+				marker = "*"
+			}
+
+			if calendar.IsSameDate(date, selected) {
+				b.WriteString(fmt.Sprintf("[%2d]", day))
+			} else {
+				b.WriteString(fmt.Sprintf(" %2d%s", day, marker))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// printEvents prints events as a numbered list
+func (u *UI) printEvents(list []models.Event) {
+	if len(list) == 0 {
+		fmt.Fprintln(u.out, "  No events scheduled")
+		return
+	}
+	for i, event := range list {
+		fmt.Fprintf(u.out, "  %d. %s - %s\n", i+1, event.GetTimeString(), event.Description)
+	}
+}
+
+// prompt prints a label and reads one trimmed line; ok is false at end of input
+func (u *UI) prompt(label string) (string, bool) {
+	fmt.Fprintf(u.out, "%s ", label)
+	if !u.scanner.Scan() {
+		fmt.Fprintln(u.out)
+		return "", false
+	}
+	return strings.TrimSpace(u.scanner.Text()), true
+}
+
+// chooseEvent asks for an event number on the selected date
+func (u *UI) chooseEvent(action string) (models.Event, bool) {
+	list := u.events.GetEventsForDate(u.selected)
+	if len(list) == 0 {
+		fmt.Fprintln(u.out, "No events on this date")
+		return models.Event{}, false
+	}
+
+	u.printEvents(list)
+	answer, ok := u.prompt(fmt.Sprintf("Event number to %s (empty = cancel):", action))
+	if !ok || answer == "" {
+		return models.Event{}, false
+	}
+
+	number, err := strconv.Atoi(answer)
+	if err != nil || number < 1 || number > len(list) {
+		fmt.Fprintf(u.out, "Invalid event number %q\n", answer)
+		return models.Event{}, false
+	}
+	return list[number-1], true
+}
+
+// goToDate selects a date given as YYYY-MM-DD or a relative expression
+func (u *UI) goToDate() {
+	answer, ok := u.prompt("Date (YYYY-MM-DD, today, +3d, next fri, eom, ...):")
+	if !ok {
+		return
+	}
+
+	date, err := calendar.ParseRelativeDate(answer, u.selected)
+	if err != nil {
+		fmt.Fprintf(u.out, "Error: %v\n", err)
+		return
+	}
+	u.selected = date
+}
+
+// addEvent adds an event to the selected date
+func (u *UI) addEvent() {
+	timeStr, ok := u.prompt("Time (HH:MM):")
+	if !ok || timeStr == "" {
+		return
+	}
+	description, ok := u.prompt("Description:")
+	if !ok || description == "" {
+		return
+	}
+
+	if err := u.events.AddEvent(u.selected, timeStr, description); err != nil {
+		fmt.Fprintf(u.out, "Error adding event: %v\n", err)
+		return
+	}
+	fmt.Fprintln(u.out, "Event added successfully!")
+}
+
+// editEvent changes the time and description of an event; empty answers keep the current value
+func (u *UI) editEvent() {
+	event, ok := u.chooseEvent("edit")
+	if !ok {
+		return
+	}
+
+	timeStr, ok := u.prompt(fmt.Sprintf("Time [%s]:", event.GetTimeString()))
+	if !ok {
+		return
+	}
+	if timeStr == "" {
+		timeStr = event.GetTimeString()
+	}
+
+	description, ok := u.prompt(fmt.Sprintf("Description [%s]:", event.Description))
+	if !ok {
+		return
+	}
+	if description == "" {
+		description = event.Description
+	}
+
+	if err := u.events.EditEvent(event, event.Date, timeStr, description); err != nil {
+		fmt.Fprintf(u.out, "Error editing event: %v\n", err)
+		return
+	}
+	fmt.Fprintln(u.out, "Event updated successfully!")
+}
+
+// deleteEvent removes an event after confirmation
+func (u *UI) deleteEvent() {
+	event, ok := u.chooseEvent("delete")
+	if !ok {
+		return
+	}
+
+	answer, ok := u.prompt(fmt.Sprintf("Delete %s - %s? [y/N]:", event.GetTimeString(), event.Description))
+	if !ok || strings.ToLower(answer) != "y" {
+		return
+	}
+
+	if err := u.events.DeleteEvent(event); err != nil {
+		fmt.Fprintf(u.out, "Error deleting event: %v\n", err)
+		return
+	}
+	fmt.Fprintln(u.out, "Event deleted successfully!")
+}
+
+// search lists events matching a query and optionally selects one's date
+func (u *UI) search() {
+	query, ok := u.prompt("Search for:")
+	if !ok || query == "" {
+		return
+	}
+
+	if _, _, err := events.ParseSearchFilter(query, time.Now()); err != nil {
+		fmt.Fprintf(u.out, "Error: %v\n", err)
+		return
+	}
+	results := u.events.SearchEvents(query)
+	if len(results) == 0 {
+		fmt.Fprintf(u.out, "No events found for %q\n", query)
+		return
+	}
+
+	upcoming := -1
+	switch u.searchOrder {
+	case config.SearchOrderNearest:
+		results, upcoming = events.OrderByNearest(results, time.Now())
+	case config.SearchOrderRelevance:
+		results = events.OrderByRelevance(results, query)
+	}
+
+	for i, event := range results {
+		if upcoming > 0 && i == 0 {
+			fmt.Fprintln(u.out, "Upcoming:")
+		}
+		if upcoming >= 0 && i == upcoming {
+			fmt.Fprintln(u.out, "Past:")
+		}
+		fmt.Fprintf(u.out, "  %d. %s %s - %s\n", i+1, event.GetDateString(), event.GetTimeString(), event.Description)
+	}
+
+	answer, ok := u.prompt("Result number to go to (empty = stay):")
+	if !ok || answer == "" {
+		return
+	}
+	number, err := strconv.Atoi(answer)
+	if err != nil || number < 1 || number > len(results) {
+		fmt.Fprintf(u.out, "Invalid result number %q\n", answer)
+		return
+	}
+	u.selected = calendar.NormalizeDate(results[number-1].Date)
+}
@@ -0,0 +1,107 @@
+package lineui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/events"
+)
+
+func TestRenderMonth(t *testing.T) {
+	month := time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local)
+	selected := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	hasEvents := func(date time.Time) bool { return date.Day() == 20 }
+
+	output := RenderMonth(month, selected, 0, nil, nil, hasEvents)
+	lines := strings.Split(output, "\n")
+
+	if strings.TrimSpace(lines[0]) != "August 2025" {
+		t.Errorf("Title line = %q, want August 2025", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], " Su  Mo") {
+		t.Errorf("Header line = %q, want Sunday first", lines[1])
+	}
+	if !strings.Contains(output, "[15]") {
+		t.Error("Selected day should be shown in brackets")
+	}
+	if !strings.Contains(output, " 20*") {
+		t.Error("Day with events should be marked with an asterisk")
+	}
+	// August 1st 2025 is a Friday: five empty cells precede it
+	if !strings.HasPrefix(lines[2], strings.Repeat("    ", 5)+"  1 ") {
+		t.Errorf("First week line = %q, want the 1st under Friday", lines[2])
+	}
+
+	// Localized month names head the grid
+	french := []string{"Janvier", "Février", "Mars", "Avril", "Mai", "Juin", "Juillet", "Août", "Septembre", "Octobre", "Novembre", "Décembre"}
+	if title := strings.Split(RenderMonth(month, selected, 1, nil, french, hasEvents), "\n")[0]; strings.TrimSpace(title) != "Août 2025" {
+		t.Errorf("Title line = %q, want Août 2025", title)
+	}
+}
+
+func TestShiftMonth(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		months   int
+		expected time.Time
+	}{
+		{time.Date(2025, 1, 31, 0, 0, 0, 0, time.Local), 1, time.Date(2025, 2, 28, 0, 0, 0, 0, time.Local)},
+		{time.Date(2025, 3, 15, 0, 0, 0, 0, time.Local), -1, time.Date(2025, 2, 15, 0, 0, 0, 0, time.Local)},
+		{time.Date(2025, 12, 31, 0, 0, 0, 0, time.Local), 1, time.Date(2026, 1, 31, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		if result := shiftMonth(tt.date, tt.months); !result.Equal(tt.expected) {
+			t.Errorf("shiftMonth(%v, %d) = %v, want %v", tt.date, tt.months, result, tt.expected)
+		}
+	}
+}
+
+func TestUI_ScriptedSession(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "lineui_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := events.NewManagerWithConfig(cfg)
+
+	script := strings.Join([]string{
+		"3", "2025-08-15", // Go to date
+		"4", "09:00", "Standup", // Add event
+		"5", "1", "09:30", "", // Edit time, keep description
+		"4", "12:00", "Lunch", // Add another event
+		"6", "2", "y", // Delete lunch
+		"9", // Unknown choice
+		"0", // Quit
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	ui := New(cfg, manager, strings.NewReader(script), &out)
+	if err := ui.Run(); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	list := manager.GetEventsForDate(date)
+	if len(list) != 1 || list[0].Description != "Standup" || list[0].GetTimeString() != "09:30" {
+		t.Errorf("Events after session = %v, want Standup at 09:30", list)
+	}
+	if !strings.Contains(out.String(), `Unknown choice "9"`) {
+		t.Error("Unknown menu choices should be reported")
+	}
+}
+
+func TestUI_EndOfInput(t *testing.T) {
+	manager := events.NewManagerWithConfig(&config.Config{EventsFilePath: filepath.Join(os.TempDir(), "lineui-unused.json")})
+	ui := New(nil, manager, strings.NewReader(""), &bytes.Buffer{})
+	if err := ui.Run(); err != nil {
+		t.Errorf("Run() at end of input should return cleanly, got %v", err)
+	}
+}
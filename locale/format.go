@@ -0,0 +1,72 @@
+package locale
+
+import (
+	"strings"
+	"time"
+)
+
+// Formatter writes the dates and times of exports that people read: in the date
+// pattern and weekday names of the calendar, stating the time zone its times are in.
+// Machine-readable fields, such as iCalendar timestamps, stay ISO 8601.
+type Formatter struct {
+	DateFormat string         // Date pattern of YYYY, MM and DD; empty for YYYY-MM-DD
+	Weekdays   [7]string      // Weekday abbreviations, Sunday first
+	Location   *time.Location // Zone of the calendar's times; nil for the local zone
+}
+
+// NewFormatter returns the formatter of a locale's conventions in the local zone
+func NewFormatter(l Locale) Formatter {
+	return Formatter{DateFormat: l.DateFormat, Weekdays: l.Weekdays}
+}
+
+// Date formats a date with the date pattern, e.g. "11.08.2025"
+func (f Formatter) Date(date time.Time) string {
+	return date.Format(DateLayout(f.DateFormat))
+}
+
+// Day formats a date after its weekday, e.g. "Mo 11.08.2025"
+func (f Formatter) Day(date time.Time) string {
+	return f.weekday(date) + " " + f.Date(date)
+}
+
+// ShortDay formats a date after its weekday but without the year, e.g. "Mo 11.08"
+func (f Formatter) ShortDay(date time.Time) string {
+	pattern := f.DateFormat
+	if pattern == "" {
+		pattern = C.DateFormat
+	}
+	// Drop the year together with the separator next to it
+	for _, sep := range []string{"-", ".", "/", " "} {
+		pattern = strings.Replace(pattern, "YYYY"+sep, "", 1)
+		pattern = strings.Replace(pattern, sep+"YYYY", "", 1)
+	}
+	pattern = strings.Replace(pattern, "YYYY", "", 1)
+	return f.weekday(date) + " " + date.Format(DateLayout(pattern))
+}
+
+// Zone names the time zone of the calendar's times at t, e.g. "Europe/Berlin (CEST)",
+// or only its abbreviation for the local zone
+func (f Formatter) Zone(t time.Time) string {
+	loc := f.In()
+	abbreviation, _ := t.In(loc).Zone()
+	if loc == time.Local || loc.String() == abbreviation {
+		return abbreviation
+	}
+	return loc.String() + " (" + abbreviation + ")"
+}
+
+// In returns the zone of the calendar's times
+func (f Formatter) In() *time.Location {
+	if f.Location == nil {
+		return time.Local
+	}
+	return f.Location
+}
+
+// weekday returns the abbreviation of a date's weekday, falling back to C's
+func (f Formatter) weekday(date time.Time) string {
+	if name := f.Weekdays[date.Weekday()]; name != "" {
+		return name
+	}
+	return C.Weekdays[date.Weekday()]
+}
@@ -0,0 +1,60 @@
+package locale
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatter(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("Time zone data unavailable: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("Time zone data unavailable: %v", err)
+	}
+	date := time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale   string
+		location *time.Location
+		date     string
+		day      string
+		shortDay string
+		zone     string
+	}{
+		{"C", time.UTC, "2025-08-11", "Mo 2025-08-11", "Mo 08-11", "UTC"},
+		{"de_DE.UTF-8", berlin, "11.08.2025", "Mo 11.08.2025", "Mo 11.08", "Europe/Berlin (CEST)"},
+		{"en_US.UTF-8", newYork, "08/11/2025", "Mo 08/11/2025", "Mo 08/11", "America/New_York (EDT)"},
+		{"fr_FR.UTF-8", berlin, "11/08/2025", "Lu 11/08/2025", "Lu 11/08", "Europe/Berlin (CEST)"},
+	}
+
+	for _, tt := range tests {
+		f := NewFormatter(Parse(tt.locale))
+		f.Location = tt.location
+		if got := f.Date(date); got != tt.date {
+			t.Errorf("%s: Date() = %q, want %q", tt.locale, got, tt.date)
+		}
+		if got := f.Day(date); got != tt.day {
+			t.Errorf("%s: Day() = %q, want %q", tt.locale, got, tt.day)
+		}
+		if got := f.ShortDay(date); got != tt.shortDay {
+			t.Errorf("%s: ShortDay() = %q, want %q", tt.locale, got, tt.shortDay)
+		}
+		if got := f.Zone(date); got != tt.zone {
+			t.Errorf("%s: Zone() = %q, want %q", tt.locale, got, tt.zone)
+		}
+	}
+}
+
+func TestFormatter_Defaults(t *testing.T) {
+	var f Formatter
+	date := time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC)
+	if got := f.Day(date); got != "Mo 2025-08-11" {
+		t.Errorf("Day() = %q, want the conventions of C", got)
+	}
+	if f.In() != time.Local {
+		t.Errorf("In() = %v, want the local zone", f.In())
+	}
+}
@@ -0,0 +1,156 @@
+package locale
+
+import (
+	"strings"
+	"time"
+)
+
+// Locale holds the calendar conventions of a language and territory
+type Locale struct {
+	Name         string       // Language and territory, e.g. "de_DE", or "C"
+	FirstWeekday time.Weekday // time.Sunday or time.Monday
+	Weekdays     [7]string    // Two-letter weekday abbreviations, Sunday first
+	Months       [12]string   // Month names as headers of the month grids, January first
+	DateFormat   string       // Date pattern of YYYY, MM and DD, e.g. "DD.MM.YYYY"
+}
+
+// C is the locale used when the environment names none or an unknown one
+var C = Locale{
+	Name:         "C",
+	FirstWeekday: time.Sunday,
+	Weekdays:     weekdays["en"],
+	Months:       months["en"],
+	DateFormat:   "YYYY-MM-DD",
+}
+
+// weekdays holds the weekday abbreviations of each language, Sunday first
+var weekdays = map[string][7]string{
+	"cs": {"Ne", "Po", "Út", "St", "Čt", "Pá", "So"},
+	"da": {"Sø", "Ma", "Ti", "On", "To", "Fr", "Lø"},
+	"de": {"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+	"en": {"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"},
+	"es": {"Do", "Lu", "Ma", "Mi", "Ju", "Vi", "Sá"},
+	"fi": {"Su", "Ma", "Ti", "Ke", "To", "Pe", "La"},
+	"fr": {"Di", "Lu", "Ma", "Me", "Je", "Ve", "Sa"},
+	"it": {"Do", "Lu", "Ma", "Me", "Gi", "Ve", "Sa"},
+	"nb": {"Sø", "Ma", "Ti", "On", "To", "Fr", "Lø"},
+	"nl": {"Zo", "Ma", "Di", "Wo", "Do", "Vr", "Za"},
+	"nn": {"Sø", "Må", "Ty", "On", "To", "Fr", "La"},
+	"no": {"Sø", "Ma", "Ti", "On", "To", "Fr", "Lø"},
+	"pl": {"Nd", "Pn", "Wt", "Śr", "Cz", "Pt", "So"},
+	"pt": {"Do", "Se", "Te", "Qu", "Qu", "Se", "Sá"},
+	"ru": {"Вс", "Пн", "Вт", "Ср", "Чт", "Пт", "Сб"},
+	"sv": {"Sö", "Må", "Ti", "On", "To", "Fr", "Lö"},
+	"uk": {"Нд", "Пн", "Вт", "Ср", "Чт", "Пт", "Сб"},
+}
+
+// months holds the month names of each language as they head a month grid, January first
+var months = map[string][12]string{
+	"cs": {"Leden", "Únor", "Březen", "Duben", "Květen", "Červen", "Červenec", "Srpen", "Září", "Říjen", "Listopad", "Prosinec"},
+	"da": {"Januar", "Februar", "Marts", "April", "Maj", "Juni", "Juli", "August", "September", "Oktober", "November", "December"},
+	"de": {"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	"en": {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	"es": {"Enero", "Febrero", "Marzo", "Abril", "Mayo", "Junio", "Julio", "Agosto", "Septiembre", "Octubre", "Noviembre", "Diciembre"},
+	"fi": {"Tammikuu", "Helmikuu", "Maaliskuu", "Huhtikuu", "Toukokuu", "Kesäkuu", "Heinäkuu", "Elokuu", "Syyskuu", "Lokakuu", "Marraskuu", "Joulukuu"},
+	"fr": {"Janvier", "Février", "Mars", "Avril", "Mai", "Juin", "Juillet", "Août", "Septembre", "Octobre", "Novembre", "Décembre"},
+	"it": {"Gennaio", "Febbraio", "Marzo", "Aprile", "Maggio", "Giugno", "Luglio", "Agosto", "Settembre", "Ottobre", "Novembre", "Dicembre"},
+	"nb": {"Januar", "Februar", "Mars", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Desember"},
+	"nl": {"Januari", "Februari", "Maart", "April", "Mei", "Juni", "Juli", "Augustus", "September", "Oktober", "November", "December"},
+	"nn": {"Januar", "Februar", "Mars", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Desember"},
+	"no": {"Januar", "Februar", "Mars", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Desember"},
+	"pl": {"Styczeń", "Luty", "Marzec", "Kwiecień", "Maj", "Czerwiec", "Lipiec", "Sierpień", "Wrzesień", "Październik", "Listopad", "Grudzień"},
+	"pt": {"Janeiro", "Fevereiro", "Março", "Abril", "Maio", "Junho", "Julho", "Agosto", "Setembro", "Outubro", "Novembro", "Dezembro"},
+	"ru": {"Январь", "Февраль", "Март", "Апрель", "Май", "Июнь", "Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь"},
+	"sv": {"Januari", "Februari", "Mars", "April", "Maj", "Juni", "Juli", "Augusti", "September", "Oktober", "November", "December"},
+	"uk": {"Січень", "Лютий", "Березень", "Квітень", "Травень", "Червень", "Липень", "Серпень", "Вересень", "Жовтень", "Листопад", "Грудень"},
+}
+
+// sundayTerritories are the territories whose weeks start on Sunday; elsewhere they
+// start on Monday
+var sundayTerritories = map[string]bool{
+	"BR": true, "CA": true, "CN": true, "CO": true, "HK": true, "IL": true, "IN": true,
+	"JP": true, "KR": true, "MX": true, "PE": true, "PH": true, "PT": true, "SA": true,
+	"SG": true, "TH": true, "TW": true, "US": true, "VE": true, "ZA": true,
+}
+
+// dateFormats holds the date pattern of each territory that does not write dates as
+// YYYY-MM-DD
+var dateFormats = map[string]string{
+	"US": "MM/DD/YYYY", "PH": "MM/DD/YYYY",
+
+	"AT": "DD.MM.YYYY", "CH": "DD.MM.YYYY", "CZ": "DD.MM.YYYY", "DE": "DD.MM.YYYY",
+	"DK": "DD.MM.YYYY", "FI": "DD.MM.YYYY", "NO": "DD.MM.YYYY", "PL": "DD.MM.YYYY",
+	"RU": "DD.MM.YYYY", "SK": "DD.MM.YYYY", "TR": "DD.MM.YYYY", "UA": "DD.MM.YYYY",
+
+	"AR": "DD/MM/YYYY", "AU": "DD/MM/YYYY", "BE": "DD/MM/YYYY", "BR": "DD/MM/YYYY",
+	"ES": "DD/MM/YYYY", "FR": "DD/MM/YYYY", "GB": "DD/MM/YYYY", "GR": "DD/MM/YYYY",
+	"IE": "DD/MM/YYYY", "IN": "DD/MM/YYYY", "IT": "DD/MM/YYYY", "MX": "DD/MM/YYYY",
+	"NZ": "DD/MM/YYYY", "PT": "DD/MM/YYYY",
+
+	"NL": "DD-MM-YYYY",
+
+	"CN": "YYYY/MM/DD", "JP": "YYYY/MM/DD", "ZA": "YYYY/MM/DD",
+}
+
+// FromEnv returns the locale of dates and times named by the environment: LC_ALL,
+// then LC_TIME, then LANG, the first one that is set
+func FromEnv(getenv func(string) string) Locale {
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if value := getenv(name); value != "" {
+			return Parse(value)
+		}
+	}
+	return C
+}
+
+// Parse returns the locale of a POSIX locale name such as "de_DE.UTF-8" or
+// "fr_CA@euro". Unknown languages keep the weekday and month names of C but still take
+// the first weekday and date format of their territory.
+func Parse(name string) Locale {
+	// Drop the encoding and modifier
+	if i := strings.IndexAny(name, ".@"); i >= 0 {
+		name = name[:i]
+	}
+	if name == "" || name == "C" || name == "POSIX" {
+		return C
+	}
+
+	language, territory, _ := strings.Cut(name, "_")
+	language = strings.ToLower(language)
+	territory = strings.ToUpper(territory)
+
+	l := C
+	l.Name = name
+	if names, ok := weekdays[language]; ok {
+		l.Weekdays = names
+	}
+	if names, ok := months[language]; ok {
+		l.Months = names
+	}
+
+	// Without a territory English keeps the conventions of C and other languages
+	// start their weeks on Monday
+	if territory == "" {
+		if language != "en" {
+			l.FirstWeekday = time.Monday
+		}
+		return l
+	}
+
+	if !sundayTerritories[territory] {
+		l.FirstWeekday = time.Monday
+	}
+	if format, ok := dateFormats[territory]; ok {
+		l.DateFormat = format
+	}
+	return l
+}
+
+// DateLayout converts a date pattern of YYYY, MM and DD into a time.Format layout;
+// an empty pattern gives YYYY-MM-DD
+func DateLayout(pattern string) string {
+	if pattern == "" {
+		pattern = C.DateFormat
+	}
+	return strings.NewReplacer("YYYY", "2006", "MM", "01", "DD", "02").Replace(pattern)
+}
@@ -0,0 +1,98 @@
+package locale
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		firstWeekday time.Weekday
+		monday       string
+		dateFormat   string
+	}{
+		{"", time.Sunday, "Mo", "YYYY-MM-DD"},
+		{"C", time.Sunday, "Mo", "YYYY-MM-DD"},
+		{"POSIX", time.Sunday, "Mo", "YYYY-MM-DD"},
+		{"C.UTF-8", time.Sunday, "Mo", "YYYY-MM-DD"},
+		{"en_US.UTF-8", time.Sunday, "Mo", "MM/DD/YYYY"},
+		{"en_GB.UTF-8", time.Monday, "Mo", "DD/MM/YYYY"},
+		{"de_DE.UTF-8", time.Monday, "Mo", "DD.MM.YYYY"},
+		{"de_AT@euro", time.Monday, "Mo", "DD.MM.YYYY"},
+		{"fr_FR", time.Monday, "Lu", "DD/MM/YYYY"},
+		{"fr_CA.UTF-8", time.Sunday, "Lu", "YYYY-MM-DD"},
+		{"pt_BR.UTF-8", time.Sunday, "Se", "DD/MM/YYYY"},
+		{"sv_SE.UTF-8", time.Monday, "Må", "YYYY-MM-DD"},
+		{"ru_RU.UTF-8", time.Monday, "Пн", "DD.MM.YYYY"},
+		{"nl", time.Monday, "Ma", "YYYY-MM-DD"},
+		{"en", time.Sunday, "Mo", "YYYY-MM-DD"},
+		{"xx_DE", time.Monday, "Mo", "DD.MM.YYYY"},
+	}
+
+	for _, tt := range tests {
+		l := Parse(tt.name)
+		if l.FirstWeekday != tt.firstWeekday || l.Weekdays[time.Monday] != tt.monday || l.DateFormat != tt.dateFormat {
+			t.Errorf("Parse(%q) = %v, %q, %q; want %v, %q, %q", tt.name,
+				l.FirstWeekday, l.Weekdays[time.Monday], l.DateFormat, tt.firstWeekday, tt.monday, tt.dateFormat)
+		}
+	}
+}
+
+func TestParse_Months(t *testing.T) {
+	tests := map[string]string{
+		"C":           "March",
+		"de_DE.UTF-8": "März",
+		"fr_FR":       "Mars",
+		"ru_RU.UTF-8": "Март",
+		"xx_DE":       "March",
+	}
+
+	for name, want := range tests {
+		if got := Parse(name).Months[time.March-1]; got != want {
+			t.Errorf("Parse(%q).Months[March] = %q, want %q", name, got, want)
+		}
+	}
+
+	// Every language with weekday names has month names too
+	for language := range weekdays {
+		if _, ok := months[language]; !ok {
+			t.Errorf("Language %q has weekday names but no month names", language)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{}, "C"},
+		{map[string]string{"LANG": "de_DE.UTF-8"}, "de_DE"},
+		{map[string]string{"LANG": "de_DE.UTF-8", "LC_TIME": "en_GB.UTF-8"}, "en_GB"},
+		{map[string]string{"LANG": "de_DE.UTF-8", "LC_TIME": "en_GB.UTF-8", "LC_ALL": "fr_FR.UTF-8"}, "fr_FR"},
+	}
+
+	for _, tt := range tests {
+		getenv := func(name string) string { return tt.env[name] }
+		if got := FromEnv(getenv).Name; got != tt.want {
+			t.Errorf("FromEnv(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestDateLayout(t *testing.T) {
+	date := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	tests := map[string]string{
+		"":           "2026-03-07",
+		"YYYY-MM-DD": "2026-03-07",
+		"DD.MM.YYYY": "07.03.2026",
+		"MM/DD/YYYY": "03/07/2026",
+	}
+
+	for pattern, want := range tests {
+		if got := date.Format(DateLayout(pattern)); got != want {
+			t.Errorf("DateLayout(%q) formats %s, want %s", pattern, got, want)
+		}
+	}
+}
@@ -1,15 +1,49 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/nsf/termbox-go"
+	"go-ascii-calendar/alarm"
+	"go-ascii-calendar/annotations"
+	"go-ascii-calendar/apitoken"
+	"go-ascii-calendar/banner"
+	"go-ascii-calendar/caldav"
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/completion"
 	"go-ascii-calendar/config"
+	"go-ascii-calendar/crash"
 	"go-ascii-calendar/events"
+	"go-ascii-calendar/holidays"
+	"go-ascii-calendar/lineui"
+	"go-ascii-calendar/locale"
+	"go-ascii-calendar/messages"
 	"go-ascii-calendar/models"
+	"go-ascii-calendar/planner"
+	"go-ascii-calendar/retention"
+	"go-ascii-calendar/share"
+	"go-ascii-calendar/state"
+	"go-ascii-calendar/stats"
+	"go-ascii-calendar/storage"
+	"go-ascii-calendar/syncer"
 	"go-ascii-calendar/terminal"
+	"go-ascii-calendar/watcher"
 )
 
 // AppState represents the current state of the application
@@ -23,8 +57,78 @@ const (
 	StateSearch                          // New state for search functionality
 	StateEventList
 	StateAddEvent
+	StateStats       // Local usage statistics view
+	StateActivityLog // Changes made during this session
+	StateBookmarks   // Bookmark picker
+	StateBanner      // Startup/idle banner with today's agenda and widgets
+	StateFollowUps   // Flagged events of all dates
+	StateHelp        // Key bindings, with the way back into the tour
+	StateTour        // Onboarding tour over the calendar view
+	StateDayView     // Hour-by-hour timeline of the selected date
+	StatePaste       // Events read from pasted lines, before they are added
+	StateReview      // Imported events waiting to be accepted or deleted
+	StateConflict    // Calendar and source versions of an imported event, side by side
+	StateScreensaver // Large clock and a ticker of upcoming events, shown when idle
+	StateCalendars   // Calendars shown or hidden
+	StateBackups     // Backups of the events files, to restore one
+	StateMessages    // Latest status line messages
+	StateDetails     // All fields and the notes of the event selected in the events list
 )
 
+// String returns the view name used in usage statistics
+func (s AppState) String() string {
+	switch s {
+	case StateCalendar:
+		return "calendar"
+	case StateCalendarEventSelection:
+		return "delete selection"
+	case StateCalendarEventAdd:
+		return "add event"
+	case StateCalendarEventEdit:
+		return "edit selection"
+	case StateSearch:
+		return "search"
+	case StateEventList:
+		return "event list"
+	case StateAddEvent:
+		return "add event prompt"
+	case StateStats:
+		return "statistics"
+	case StateActivityLog:
+		return "activity log"
+	case StateBookmarks:
+		return "bookmarks"
+	case StateBanner:
+		return "banner"
+	case StateFollowUps:
+		return "follow-ups"
+	case StateHelp:
+		return "help"
+	case StateTour:
+		return "tour"
+	case StateDayView:
+		return "day view"
+	case StatePaste:
+		return "paste"
+	case StateReview:
+		return "review"
+	case StateConflict:
+		return "conflict"
+	case StateScreensaver:
+		return "screensaver"
+	case StateCalendars:
+		return "calendars"
+	case StateBackups:
+		return "backups"
+	case StateMessages:
+		return "messages"
+	case StateDetails:
+		return "event details"
+	default:
+		return "unknown"
+	}
+}
+
 // Application holds the main application components
 type Application struct {
 	config             *config.Config
@@ -41,7 +145,86 @@ type Application struct {
 	searchQuery         string         // Current search query
 	searchResults       []models.Event // Search results
 	searchResultDates   []string       // Unique dates from search results for grouping
+	searchUpcoming      int            // Number of upcoming results before the past ones, or -1 when ordered by date
 	selectedResultIndex int            // Index of currently selected search result
+	// Local usage statistics (opt-in)
+	stats *stats.Tracker
+	// Session activity log with undo
+	history               *events.History
+	selectedActivityIndex int // Index of currently selected activity log entry
+	// Named bookmark dates, persisted in the state file
+	bookmarks             *state.Store
+	selectedBookmarkIndex int // Index of currently selected bookmark in the picker
+	selectedFollowUpIndex int // Index of currently selected event in the follow-up list
+	selectedReviewIndex   int // Index of currently selected event in the review queue
+	selectedCalendarIndex int // Index of currently selected calendar in the calendars view
+	// Backups listed when the backups view was opened, and the selected one
+	backups             []storage.Backup
+	selectedBackupIndex int
+	// Conflict being resolved, with the selected field and the fields taken from the source
+	conflictEvent         models.Event
+	selectedConflictField int
+	conflictFromSource    map[events.ConflictField]bool
+	// Event shown in the details view and the rows its details are scrolled by
+	detailsEvent  models.Event
+	detailsScroll int
+	// Onboarding tour, started on the first run and from the help screen
+	tourStep int  // Index of the shown tour step
+	firstRun bool // The events file did not exist before this session
+	// Day view: the selected hour, and the hour where marking a new event started (-1 when not marking)
+	dayViewHour   int
+	dayViewAnchor int
+	// Pasted lines waiting to be added, and the selected one
+	pasteLines         []events.PasteLine
+	selectedPasteIndex int
+	// Messages for the status line, the timer redrawing it when the shown one expires
+	// and the selected message in the history view
+	messages             *messages.Queue
+	messageTick          *time.Timer
+	selectedMessageIndex int
+	// Wakes the event loop each second while a deletion can still be undone
+	deleteTick *time.Timer
+	// Wakes the event loop to retry a failed write of the events file, and whether
+	// copying the events to the emergency file was offered for the failure
+	writeRetry       *time.Timer
+	writeCopyOffered bool
+	// External sync commands for the data directory
+	sync *syncer.Syncer
+	// Watches the events files for changes by other programs; nil when live_reload is off.
+	// reloadStatus tells in the status bar how the latest change was taken in.
+	watcher      *watcher.Watcher
+	reloadStatus string
+	// CalDAV sync run with R; nil when no server is configured
+	caldav    *caldav.Sync
+	caldavErr error // Configuration problem reported when syncing
+	// Startup banner widgets, also shown again after idling
+	banner         []banner.Widget
+	bannerErr      error            // Configuration problem reported once the UI is up
+	bannerSections []banner.Section // Sections rendered when the banner was opened
+	lastInput      time.Time        // Time of the most recent key press, for the idle banner
+	// Screensaver: the view it returns to, when it started and the timer moving its ticker
+	screensaverReturn AppState
+	screensaverStart  time.Time
+	screensaverTick   *time.Timer
+	// Events whose reminder is due, the reminders already announced and the timer
+	// waking the event loop when the next reminder comes due or an event starts
+	dueReminders   []models.Event
+	remindersShown map[string]bool
+	reminderTimer  *time.Timer
+	// Problem loading the annotation sources, reported once the UI is up
+	annotationsErr error
+
+	// Public holidays shown in the calendar and noted when adding events
+	holidays    *holidays.Calendar
+	holidaysErr error
+	// Predefined theme selected with the theme switcher; empty while the configured theme is shown
+	themeName string
+	// Sentinel file detecting crashed sessions, and the safe mode notice shown once the UI is up
+	crashGuard   *crash.Guard
+	safeModeNote string
+	// Outcome of the startup retention pass, reported once the UI is up
+	retentionNote string
+	retentionErr  error
 }
 
 // NewApplication creates a new application instance with configuration
@@ -50,18 +233,293 @@ func NewApplication(cfg *config.Config) *Application {
 	eventManager := events.NewManagerWithConfig(cfg)
 	cal := models.NewCalendar()
 	sel := models.NewSelection(cal)
+	tracker := newStatsTracker(cfg)
+
+	// Count event mutations per week for the statistics view
+	eventManager.AddChangeListener(func(kind events.ChangeKind, before, after models.Event) {
+		now := time.Now()
+		switch kind {
+		case events.ChangeAdded:
+			tracker.RecordCreated(now)
+		case events.ChangeEdited:
+			tracker.RecordEdited(now)
+		case events.ChangeDeleted:
+			tracker.RecordDeleted(now)
+		}
+	})
 
-	return &Application{
+	bookmarks := newStateStore(cfg)
+	sync := newSyncer(cfg)
+	renderer := terminal.NewRenderer(term, eventManager, cfg)
+	renderer.SetBookmarks(bookmarks)
+
+	app := &Application{
 		config:     cfg,
 		terminal:   term,
-		renderer:   terminal.NewRenderer(term, eventManager, cfg),
+		renderer:   renderer,
 		input:      terminal.NewInputHandler(term),
 		navigation: terminal.NewNavigationController(cal, sel),
 		events:     eventManager,
 		calendar:   cal,
 		selection:  sel,
 		state:      StateCalendar,
+		stats:      tracker,
+		history:    events.NewHistory(eventManager),
+		bookmarks:  bookmarks,
+		sync:       sync,
+		crashGuard: newCrashGuard(cfg),
+		messages:   messages.NewQueue(),
+	}
+	if cfg != nil {
+		app.themeName = cfg.Theme
+	}
+	// A background sync round must know about writes made while it runs
+	eventManager.SetBeforeWrite(func() { app.sync.NoteLocalWrite() })
+	app.caldav, app.caldavErr = newCalDAV(cfg)
+	// Remember when synced events change, so a conflict goes to the later change
+	eventManager.AddChangeListener(func(_ events.ChangeKind, before, after models.Event) {
+		if app.caldav != nil {
+			app.caldav.Changed(before, after)
+		}
+	})
+	renderer.SetStatus(app.statusText)
+	app.banner, app.bannerErr = newBanner(cfg, eventManager)
+	app.annotationsErr = app.loadAnnotations()
+	app.holidays, app.holidaysErr = holidays.Load(cfg)
+	renderer.SetHolidays(app.holidays)
+	return app
+}
+
+// loadAnnotations builds the configured annotation providers and hands them to the renderer
+func (app *Application) loadAnnotations() error {
+	if app.config == nil {
+		return nil
+	}
+	providers, err := annotations.Build(app.config.Annotations)
+	app.renderer.SetAnnotations(providers)
+	return err
+}
+
+// newBanner builds the startup banner widgets from the configuration
+func newBanner(cfg *config.Config, eventManager *events.Manager) ([]banner.Widget, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	return banner.Build(cfg.StartupBanner, eventManager.GetEventsForDate, alarm.ShellOutput)
+}
+
+// bannerIdleTimeout returns after how long without input the banner reappears (0 = never)
+func (app *Application) bannerIdleTimeout() time.Duration {
+	if app.config == nil || len(app.banner) == 0 {
+		return 0
+	}
+	return time.Duration(app.config.BannerIdleMinutes) * time.Minute
+}
+
+// screensaverTimeout returns after how long without input the screensaver starts (0 = never)
+func (app *Application) screensaverTimeout() time.Duration {
+	if app.config == nil {
+		return 0
+	}
+	return time.Duration(app.config.ScreensaverMinutes) * time.Minute
+}
+
+// screensaverStep is how often the screensaver ticker moves by one character
+const screensaverStep = 400 * time.Millisecond
+
+// startScreensaver covers the current view with the screensaver, remembering the view
+// to return to
+func (app *Application) startScreensaver() {
+	app.input.CancelPendingChord()
+	app.screensaverReturn = app.state
+	app.screensaverStart = time.Now()
+	app.state = StateScreensaver
+	app.crashGuard.Enter(app.state.String())
+	app.scheduleScreensaverTick()
+}
+
+// scheduleScreensaverTick wakes the event loop to move the ticker on
+func (app *Application) scheduleScreensaverTick() {
+	if app.screensaverTick != nil {
+		app.screensaverTick.Stop()
+	}
+	app.screensaverTick = time.AfterFunc(screensaverStep, app.terminal.Interrupt)
+}
+
+// stopScreensaver returns to the view the screensaver covered
+func (app *Application) stopScreensaver() {
+	if app.screensaverTick != nil {
+		app.screensaverTick.Stop()
+	}
+	app.state = app.screensaverReturn
+}
+
+// formatDate formats a date for messages with the configured date format
+func (app *Application) formatDate(date time.Time) string {
+	if app.config == nil {
+		return calendar.FormatDate(date)
+	}
+	return calendar.FormatLocalDate(date, app.config.DateFormat)
+}
+
+// showBanner switches to the banner view, rendering its widgets once for this display
+func (app *Application) showBanner() {
+	app.crashGuard.Enter("banner")
+	app.bannerSections = banner.Render(app.banner, time.Now())
+	app.state = StateBanner
+}
+
+// statusText returns the status bar text: a pending chord key, a marked range, due
+// reminders, dry-run mode, unsaved changes and the sync state
+func (app *Application) statusText() string {
+	var parts []string
+	if keys := app.input.PendingKeys(); keys != "" {
+		parts = append(parts, keys+"-")
+	}
+	if app.selection != nil && app.selection.HasRange() {
+		start, end := app.selection.Range()
+		parts = append(parts, fmt.Sprintf("Range %s - %s (A: add event, Y: copy, V/Esc: cancel)", start.Format("Jan 2"), end.Format("Jan 2")))
+	}
+	if len(app.dueReminders) > 0 {
+		parts = append(parts, reminderText(app.dueReminders, time.Now()))
+	}
+	if app.events.DryRun() {
+		parts = append(parts, "DRY RUN")
+	}
+	if failure, ok := app.events.Unsaved(); ok {
+		text := fmt.Sprintf("UNSAVED since %s, retry %s", failure.Since.Format("15:04"), failure.NextRetry.Format("15:04:05"))
+		if failure.CopyPath != "" {
+			text += ", copy in " + failure.CopyPath
+		}
+		parts = append(parts, text)
+	}
+	if status := app.sync.StatusText(); status != "" {
+		parts = append(parts, status)
+	}
+	if app.reloadStatus != "" {
+		parts = append(parts, app.reloadStatus)
+	}
+	return strings.Join(parts, "  ")
+}
+
+// newStatsTracker creates the usage tracker; without configuration or in an ephemeral
+// session tracking stays off
+func newStatsTracker(cfg *config.Config) *stats.Tracker {
+	if cfg == nil || cfg.Ephemeral {
+		return stats.NewTracker("", false)
+	}
+	return stats.NewTracker(cfg.GetDataDir(), cfg.UsageStats)
+}
+
+// newStateStore creates the application state store; without configuration or in an
+// ephemeral session nothing is persisted
+func newStateStore(cfg *config.Config) *state.Store {
+	if cfg == nil || cfg.Ephemeral {
+		return state.NewStore("")
+	}
+	return state.NewStore(cfg.GetDataDir())
+}
+
+// newSyncer creates the syncer running the configured commands in the data directory.
+// Sync commands may change the data directory, so they are not run in dry-run mode
+// or in an ephemeral session.
+func newSyncer(cfg *config.Config) *syncer.Syncer {
+	if cfg == nil || cfg.DryRun || cfg.Ephemeral {
+		return syncer.New("", "", nil)
+	}
+	dataDir := cfg.GetDataDir()
+	return syncer.New(cfg.SyncPullCmd, cfg.SyncPushCmd, func(command string) error {
+		return alarm.ShellExecIn(dataDir, command)
+	})
+}
+
+// newCalDAV creates the sync with the configured CalDAV calendar, nil when none is set.
+// Like sync commands it is not run in dry-run mode or in an ephemeral session. A
+// password command only runs on the first sync.
+func newCalDAV(cfg *config.Config) (*caldav.Sync, error) {
+	if cfg == nil || cfg.DryRun || cfg.Ephemeral || cfg.CalDAV.URL == "" {
+		return nil, nil
+	}
+	client := &caldav.Client{URL: cfg.CalDAV.URL, Username: cfg.CalDAV.Username, Password: cfg.CalDAV.Password}
+	if command := cfg.CalDAV.PasswordCmd; command != "" && client.Password == "" {
+		client.PasswordFunc = func() (string, error) { return alarm.ShellOutput(command) }
 	}
+	loc, err := cfg.Location()
+	if err != nil {
+		return nil, err
+	}
+	davSync := caldav.New(client, cfg.GetDataDir(), loc)
+	if err := davSync.Load(); err != nil {
+		return nil, err
+	}
+	return davSync, nil
+}
+
+// caldavTimeout limits how long a CalDAV sync may wait for the server
+const caldavTimeout = 30 * time.Second
+
+// syncCalDAV syncs events with the CalDAV server and reports the outcome
+func (app *Application) syncCalDAV() {
+	if app.caldavErr != nil {
+		app.showError(fmt.Sprintf("CalDAV sync is off: %v", app.caldavErr))
+		return
+	}
+	if app.caldav == nil {
+		app.showWarning("No CalDAV server configured - set caldav in the configuration file")
+		return
+	}
+
+	app.showMessage("Syncing with CalDAV...")
+	app.crashGuard.Enter("caldav")
+	ctx, cancel := context.WithTimeout(context.Background(), caldavTimeout)
+	defer cancel()
+	result, err := app.caldav.Run(ctx, app.events)
+	if err != nil {
+		app.showError(fmt.Sprintf("CalDAV sync failed: %v", err))
+		return
+	}
+	app.showMessage("CalDAV: " + result.String())
+}
+
+// newCrashGuard creates the crash detection guard; without configuration or in an
+// ephemeral session crashes are not tracked
+func newCrashGuard(cfg *config.Config) *crash.Guard {
+	if cfg == nil || cfg.Ephemeral {
+		return crash.NewGuard("")
+	}
+	return crash.NewGuard(cfg.GetDataDir())
+}
+
+// startCrashGuard marks the session as running and switches to safe mode when
+// it was requested or the previous sessions crashed repeatedly
+func (app *Application) startCrashGuard() {
+	// An unwritable data directory only loses crash detection
+	_ = app.crashGuard.Start()
+
+	if app.config == nil {
+		return
+	}
+	if app.crashGuard.SafeMode() {
+		app.safeModeNote = fmt.Sprintf("Safe mode after %d crashes in a row (last active: %s) - theme, sync, archive, banner feeds and annotations are off",
+			app.crashGuard.Crashes(), app.crashGuard.LastSubsystem())
+	} else if app.config.SafeMode {
+		app.safeModeNote = "Safe mode - theme, sync, archive, banner feeds and annotations are off"
+	} else {
+		return
+	}
+	app.enterSafeMode()
+}
+
+// enterSafeMode disables the custom theme, hooks and feeds for this session
+func (app *Application) enterSafeMode() {
+	app.config.ApplySafeMode()
+	app.renderer.SetTheme(app.config.UITheme)
+	app.themeName = ""
+	app.sync = newSyncer(app.config)
+	app.caldav, app.caldavErr = nil, nil
+	app.banner, app.bannerErr = nil, nil
+	app.renderer.SetAnnotations(nil)
+	app.annotationsErr = nil
 }
 
 // Initialize initializes the application
@@ -71,16 +529,52 @@ func (app *Application) Initialize() error {
 		return fmt.Errorf("failed to initialize terminal: %v", err)
 	}
 
+	// Stay on the main screen if the user prefers to keep the calendar visible after quitting
+	app.terminal.SetAlternateScreen(app.config == nil || app.config.AlternateScreen)
+
+	// Make URLs in event descriptions clickable where the terminal supports it
+	if app.config != nil {
+		app.terminal.SetHyperlinks(app.config.Hyperlinks, os.Getenv)
+	}
+
+	// Draw theme colors like "color208" and "#ff8700" as they are where the terminal can
+	app.terminal.SetColors256(terminal.Colors256Supported(os.Getenv))
+
 	// Check terminal size
 	if !app.terminal.CheckSize() {
 		app.terminal.Close()
-		return fmt.Errorf("terminal too small - minimum 80x24 required")
+		return fmt.Errorf("terminal too small - minimum %dx%d required", terminal.MinWidth, terminal.MinHeight)
+	}
+
+	// Fetch the latest data first; a failed pull is shown in the status bar and
+	// the calendar continues with the local copy
+	app.crashGuard.Enter("sync")
+	_ = app.sync.Pull()
+
+	// A missing events file means the calendar was never used here: offer the tour
+	if app.config != nil && !app.config.Ephemeral {
+		_, err := os.Stat(app.config.GetEventsFilePath())
+		app.firstRun = os.IsNotExist(err)
 	}
 
 	// Load events from storage
+	app.crashGuard.Enter("events")
 	if err := app.events.LoadEvents(); err != nil {
 		app.terminal.Close()
-		return fmt.Errorf("failed to load events: %v", err)
+		return fmt.Errorf("failed to load events: %v%s", err, backupHint(app.events))
+	}
+
+	// Purge events past the retention period into the trash
+	app.crashGuard.Enter("retention")
+	app.retentionNote, _, app.retentionErr = enforceRetention(app.config, app.events, time.Now())
+
+	// Load usage statistics; an unreadable file leaves fresh counters in place
+	_ = app.stats.Load()
+
+	// Load bookmarks and other application state
+	if err := app.bookmarks.Load(); err != nil {
+		app.terminal.Close()
+		return fmt.Errorf("failed to load application state: %v", err)
 	}
 
 	return nil
@@ -88,24 +582,182 @@ func (app *Application) Initialize() error {
 
 // Run starts the main application loop
 func (app *Application) Run() error {
+	defer app.stopSync()
 	defer app.terminal.Close()
+	defer app.stats.Save()
+	defer app.saveUnsaved()
+	defer app.flushDeletes()
+
+	// Keep changes in memory when the events file cannot be written, retrying the write
+	app.events.EnableWriteRecovery()
 
-	// Initial render
+	// Sync periodically in the background, waking up the event loop afterwards
+	if app.config != nil {
+		app.sync.Start(time.Duration(app.config.SyncIntervalMinutes)*time.Minute, app.terminal.Interrupt)
+	}
+
+	// Reload the events when another program changes the events files
+	app.startWatching()
+	defer app.stopWatching()
+
+	// Show the banner again when idle; the timer wakes up the event loop to check
+	app.lastInput = time.Now()
+	idle := app.bannerIdleTimeout()
+	var idleTimer *time.Timer
+	if idle > 0 {
+		idleTimer = time.AfterFunc(idle, app.terminal.Interrupt)
+		defer idleTimer.Stop()
+	}
+	saver := app.screensaverTimeout()
+	var saverTimer *time.Timer
+	if saver > 0 {
+		saverTimer = time.AfterFunc(saver, app.terminal.Interrupt)
+		defer saverTimer.Stop()
+	}
+	defer func() {
+		if app.screensaverTick != nil {
+			app.screensaverTick.Stop()
+		}
+		if app.reminderTimer != nil {
+			app.reminderTimer.Stop()
+		}
+	}()
+
+	// Initial render, starting with the tour on the first run or with the banner when
+	// one is configured
+	if app.firstRun && !app.bookmarks.TourDone() {
+		app.startTour()
+	} else if len(app.banner) > 0 {
+		app.showBanner()
+	}
+	app.updateTitle()
+	app.checkReminders(time.Now())
 	if err := app.renderCurrentView(); err != nil {
 		return fmt.Errorf("initial render failed: %v", err)
 	}
+	if app.bannerErr != nil {
+		app.showError(fmt.Sprintf("Banner: %v", app.bannerErr))
+	}
+	if app.annotationsErr != nil {
+		app.showError(fmt.Sprintf("Annotations: %v", app.annotationsErr))
+	}
+	if app.holidaysErr != nil {
+		app.showError(fmt.Sprintf("Holidays: %v", app.holidaysErr))
+	}
+	if app.safeModeNote != "" {
+		app.showWarning(app.safeModeNote)
+	}
+	if app.retentionErr != nil {
+		app.showError(fmt.Sprintf("Retention: %v", app.retentionErr))
+	} else if app.retentionNote != "" {
+		app.showMessage(app.retentionNote)
+	}
+	app.checkStoreSize()
 
 	// Main event loop
 	for {
 		// Wait for user input
 		event := app.input.WaitForKey()
-		action := app.input.ProcessKeyEvent(event)
+
+		if event.Type == termbox.EventKey {
+			app.lastInput = time.Now()
+			if idleTimer != nil {
+				idleTimer.Reset(idle)
+			}
+			if saverTimer != nil {
+				saverTimer.Reset(saver)
+			}
+
+			// Any key ends the screensaver without acting, back in the view it covered
+			if app.state == StateScreensaver {
+				app.stopScreensaver()
+				if err := app.renderCurrentView(); err != nil {
+					app.showError(fmt.Sprintf("Render error: %v", err))
+				}
+				continue
+			}
+
+			// Any key leaves the banner; A adds an event for today first
+			if app.state == StateBanner {
+				if app.input.ProcessKeyEvent(event) == terminal.ActionAddEvent {
+					app.quickAddEvent(app.quickAddDate())
+				}
+				app.state = StateCalendar
+				if err := app.renderCurrentView(); err != nil {
+					app.showError(fmt.Sprintf("Render error: %v", err))
+				}
+				continue
+			}
+		}
+
+		var action terminal.KeyAction
+		if event.Type == termbox.EventInterrupt {
+			// Woken up by a background sync, a change to the events files, a chord timeout,
+			// the idle timers, a reminder or the screensaver ticker: pick up pulled and
+			// changed events, and let a lone chord key act on its own
+			app.reloadAfterSync()
+			app.reloadChangedFiles()
+			app.commitDueDeletes()
+			app.watchWrites()
+			app.remind()
+			if idle > 0 && app.state == StateCalendar && time.Since(app.lastInput) >= idle {
+				app.showBanner()
+			}
+			if app.state == StateScreensaver {
+				app.scheduleScreensaverTick()
+			} else if saver > 0 && time.Since(app.lastInput) >= saver {
+				app.startScreensaver()
+			}
+			action = app.input.ExpirePendingChord(time.Now())
+			if action == terminal.ActionNone {
+				if err := app.renderCurrentView(); err != nil {
+					app.showError(fmt.Sprintf("Render error: %v", err))
+				}
+				continue
+			}
+		} else if digit, ok := app.input.GetDigitKey(event); ok && app.state == StateReview {
+			// Digits accept the selected imported event into a category
+			app.acceptReviewedEvent(digit)
+			if err := app.renderCurrentView(); err != nil {
+				app.showError(fmt.Sprintf("Render error: %v", err))
+			}
+			continue
+		} else if digit, ok := app.input.GetDigitKey(event); ok && app.isEventSelectionState() {
+			// Digit hotkeys assign categories while an event is selected
+			app.assignCategoryHotkey(digit)
+			if err := app.renderCurrentView(); err != nil {
+				app.showError(fmt.Sprintf("Render error: %v", err))
+			}
+			continue
+		} else if app.state == StateCalendar {
+			// Chords such as gg and dd are only available while navigating the calendar
+			action = app.input.ProcessChordKeyEvent(event, time.Now())
+		} else {
+			app.input.CancelPendingChord()
+			app.input.SetView(app.keyView())
+			action = app.input.ProcessKeyEvent(event)
+		}
+
+		if action != terminal.ActionNone {
+			app.stats.RecordKey(app.input.GetKeyDescription(action))
+		} else if event.Type == termbox.EventKey && app.input.PendingKeys() == "" {
+			// Unknown keys are rejected; the first key of a chord is still pending
+			app.renderer.Reject()
+		}
 
 		// Handle the action based on current state
+		previousState := app.state
+		app.crashGuard.Enter(app.state.String())
 		shouldExit := app.handleAction(action)
 		if shouldExit {
 			break
 		}
+		app.watchWrites()
+		app.remind()
+		if app.state != previousState {
+			app.stats.RecordView(app.state.String())
+		}
+		app.updateTitle()
 
 		// Re-render the current view
 		if err := app.renderCurrentView(); err != nil {
@@ -116,415 +768,2553 @@ func (app *Application) Run() error {
 	return nil
 }
 
-// handleAction handles the given action based on current state
-func (app *Application) handleAction(action terminal.KeyAction) bool {
-	switch app.state {
-	case StateCalendar:
-		return app.handleCalendarAction(action)
-	case StateCalendarEventSelection:
-		return app.handleCalendarEventSelectionAction(action)
-	case StateCalendarEventAdd:
-		return app.handleCalendarEventAddAction(action)
-	case StateCalendarEventEdit:
-		return app.handleCalendarEventEditAction(action)
-	case StateSearch:
-		return app.handleSearchAction(action)
-	case StateEventList:
-		return app.handleEventListAction(action)
-	case StateAddEvent:
-		return app.handleAddEventAction(action)
+// deleteEvent deletes an event. With delete_undo_seconds set the event disappears at
+// once but the deletion is only written when the delay lapses, so U can still take it
+// back cheaply; the event loop is woken each second to count down and commit it.
+func (app *Application) deleteEvent(event models.Event) error {
+	if app.config == nil || app.config.DeleteUndoSeconds <= 0 {
+		return app.events.DeleteEvent(event)
 	}
-	return false
+	due := time.Now().Add(time.Duration(app.config.DeleteUndoSeconds) * time.Second)
+	if err := app.events.DeferDelete(event, due); err != nil {
+		return err
+	}
+	app.scheduleDeleteTick()
+	return nil
 }
 
-// handleCalendarAction handles actions when in calendar view
-func (app *Application) handleCalendarAction(action terminal.KeyAction) bool {
-	switch action {
-	case terminal.ActionQuit:
-		return app.confirmExit() // Exit application with confirmation
-
-	case terminal.ActionBack:
-		return app.confirmExit() // Exit application when Esc is pressed on main screen
-
-	case terminal.ActionMonthPrev:
-		app.navigation.NavigateMonthBackward()
+// scheduleDeleteTick wakes the event loop in a second
+func (app *Application) scheduleDeleteTick() {
+	if app.deleteTick != nil {
+		app.deleteTick.Stop()
+	}
+	app.deleteTick = time.AfterFunc(time.Second, app.terminal.Interrupt)
+}
 
-	case terminal.ActionMonthNext:
-		app.navigation.NavigateMonthForward()
+// commitDueDeletes writes the deletions whose undo delay has lapsed and keeps the
+// countdown running while others remain
+func (app *Application) commitDueDeletes() {
+	if err := app.events.CommitDeletes(time.Now()); err != nil {
+		app.showError(err.Error())
+	}
+	if len(app.events.PendingDeletes()) > 0 {
+		app.scheduleDeleteTick()
+	}
+}
 
-	case terminal.ActionMoveLeft:
-		app.navigation.NavigateDayLeft()
+// flushDeletes writes all pending deletions on exit
+func (app *Application) flushDeletes() {
+	if app.deleteTick != nil {
+		app.deleteTick.Stop()
+	}
+	if err := app.events.FlushDeletes(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
 
-	case terminal.ActionMoveRight:
-		app.navigation.NavigateDayRight()
+// watchWrites follows up on a failed write of the events file: the first failure
+// offers to copy the events to the emergency file, and the write is retried with
+// backoff, the event loop being woken when the next retry is due
+func (app *Application) watchWrites() {
+	failure, ok := app.events.Unsaved()
+	if !ok {
+		return
+	}
 
-	case terminal.ActionMoveUp:
-		app.navigation.NavigateDayUp()
+	now := time.Now()
+	if !now.Before(failure.NextRetry) {
+		if err := app.events.RetryWrite(now); err == nil {
+			app.writeCopyOffered = false
+			app.showMessage(fmt.Sprintf("Events saved again after %d retries", failure.Attempts+1))
+			return
+		}
+		failure, _ = app.events.Unsaved()
+	}
 
-	case terminal.ActionMoveDown:
-		app.navigation.NavigateDayDown()
+	if !app.writeCopyOffered {
+		app.writeCopyOffered = true
+		app.offerWriteCopy(failure)
+	}
+	if app.writeRetry != nil {
+		app.writeRetry.Stop()
+	}
+	app.writeRetry = time.AfterFunc(time.Until(failure.NextRetry), app.terminal.Interrupt)
+}
 
-	case terminal.ActionShowEvents:
-		app.state = StateEventList
-		app.selectedEventIndex = 0 // Initialize event selection
+// remind shows the reminders that came due, flashing the status bar for new ones and,
+// with reminder_bell, ringing the terminal bell
+func (app *Application) remind() {
+	if app.checkReminders(time.Now()) {
+		app.renderer.Notify(app.config != nil && app.config.ReminderBell)
+	}
+}
 
-	case terminal.ActionAddEvent:
-		// Directly start adding event from calendar view
-		app.processAddEventFromCalendar()
+// checkReminders updates the due reminders at now, reporting whether any of them is
+// new, and sets the timer for the next change. While reminders are due the timer
+// fires every minute to count down to their start.
+func (app *Application) checkReminders(now time.Time) bool {
+	due, next := app.events.DueReminders(now)
+	app.dueReminders = due
 
-	case terminal.ActionDeleteEvent:
-		// Enter event selection mode in calendar view
-		selectedDate := app.navigation.GetCurrentSelection()
-		events := app.events.GetEventsForDate(selectedDate)
-		if len(events) > 0 {
-			app.state = StateCalendarEventSelection
-			app.selectedEventIndex = 0
-		} else {
-			app.showError("No events to delete on this date")
+	if app.remindersShown == nil {
+		app.remindersShown = make(map[string]bool)
+	}
+	announce := false
+	for _, event := range due {
+		// A changed reminder or event is announced again
+		key := event.String() + "@" + event.GetReminderLabel()
+		if !app.remindersShown[key] {
+			app.remindersShown[key] = true
+			announce = true
 		}
+	}
 
-	case terminal.ActionEditEvent:
-		// Enter event edit selection mode in calendar view
-		selectedDate := app.navigation.GetCurrentSelection()
-		events := app.events.GetEventsForDate(selectedDate)
-		if len(events) > 0 {
-			app.state = StateCalendarEventEdit
-			app.selectedEventIndex = 0
-		} else {
-			app.showError("No events to edit on this date")
+	if len(due) > 0 {
+		if minute := now.Truncate(time.Minute).Add(time.Minute); minute.Before(next) {
+			next = minute
 		}
+	}
+	if app.reminderTimer != nil {
+		app.reminderTimer.Stop()
+		app.reminderTimer = nil
+	}
+	if !next.IsZero() {
+		app.reminderTimer = time.AfterFunc(next.Sub(now), app.terminal.Interrupt)
+	}
+	return announce
+}
 
-	case terminal.ActionResetCurrent:
-		app.navigation.ResetToCurrent()
+// reminderText describes the first of the due reminders for the status bar, e.g.
+// "Reminder: 10:00 Standup in 15 min (+1 more)"
+func reminderText(due []models.Event, now time.Time) string {
+	event := due[0]
+	minutes := int(math.Ceil(event.Start(now.Location()).Sub(now).Minutes()))
+	text := fmt.Sprintf("Reminder: %s %s in %d min", event.GetTimeLabel(), event.Description, minutes)
+	if len(due) > 1 {
+		text += fmt.Sprintf(" (+%d more)", len(due)-1)
+	}
+	return text
+}
 
-	case terminal.ActionSearch:
-		app.processSearch()
+// setSelectedEventReminder asks how long before the start of the selected event its
+// reminder is shown, removing the reminder for 0
+func (app *Application) setSelectedEventReminder() {
+	events := app.events.GetEventsForDate(app.navigation.GetCurrentSelection())
+	if app.selectedEventIndex >= len(events) {
+		app.renderer.Reject()
+		return
 	}
+	event := events[app.selectedEventIndex]
 
-	return false
+	prompt := "Remind before the start (minutes or e.g. 1h30m, 0: none):"
+	if current := event.GetReminderLabel(); current != "" {
+		prompt = fmt.Sprintf("Remind before the start, now %s (minutes or e.g. 1h30m, 0: none):", current)
+	}
+	input, ok := app.input.GetTextInputWithPrompt(prompt, 10, app.renderer)
+	if !ok {
+		return
+	}
+	reminder, err := parseReminder(input)
+	if err != nil {
+		app.showError(err.Error())
+		return
+	}
+	updated, err := app.events.SetEventReminder(event, reminder)
+	if err != nil {
+		app.showError(fmt.Sprintf("Error setting reminder: %v", err))
+		return
+	}
+	if reminder == 0 {
+		app.showMessage("Reminder removed")
+	} else {
+		app.showMessage(fmt.Sprintf("Reminder %s before %s", updated.GetReminderLabel(), updated.Description))
+	}
 }
 
-// handleSearchAction handles actions when in search mode
-func (app *Application) handleSearchAction(action terminal.KeyAction) bool {
-	switch action {
-	case terminal.ActionQuit:
-		return app.confirmExit() // Exit application with confirmation
+// parseReminder reads a reminder offset: whole minutes such as "15", or a duration
+// such as "1h30m"
+func parseReminder(input string) (time.Duration, error) {
+	input = strings.TrimSpace(input)
+	if minutes, err := strconv.Atoi(input); err == nil {
+		if minutes < 0 {
+			return 0, fmt.Errorf("reminder cannot be negative")
+		}
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	reminder, err := time.ParseDuration(input)
+	if err != nil || reminder < 0 || reminder%time.Minute != 0 {
+		return 0, fmt.Errorf("invalid reminder %q: expected minutes such as 15 or a duration such as 1h30m", input)
+	}
+	return reminder, nil
+}
 
-	case terminal.ActionBack:
-		// Exit search mode and return to calendar
-		app.state = StateCalendar
-		app.searchQuery = ""
-		app.searchResults = nil
-		app.searchResultDates = nil
-		app.selectedResultIndex = 0
-
-	case terminal.ActionMoveUp:
-		app.navigateSearchResultUp()
+// offerWriteCopy asks whether to copy the events to the emergency file while the
+// events file cannot be written
+func (app *Application) offerWriteCopy(failure events.WriteFailure) {
+	path := app.emergencyFilePath()
+	if err := app.renderCurrentView(); err != nil {
+		app.showError(fmt.Sprintf("Render error: %v", err))
+	}
+	if !app.confirmAction(fmt.Sprintf("Saving events failed: %v - Enter: write a copy to %s, Esc: keep retrying", failure.Err, path)) {
+		return
+	}
+	if err := app.events.WriteCopy(path); err != nil {
+		app.showError(err.Error())
+		return
+	}
+	app.showMessage(fmt.Sprintf("Events copied to %s - saving is retried until it works", path))
+}
 
-	case terminal.ActionMoveDown:
-		app.navigateSearchResultDown()
+// saveUnsaved retries a failed write of the events file on exit; when it still fails
+// the events are copied to the emergency file so no change is lost
+func (app *Application) saveUnsaved() {
+	if app.writeRetry != nil {
+		app.writeRetry.Stop()
+	}
+	if _, ok := app.events.Unsaved(); !ok {
+		return
+	}
+	if err := app.events.RetryWrite(time.Now()); err == nil {
+		return
+	}
+	path := app.emergencyFilePath()
+	if err := app.events.WriteCopy(path); err != nil {
+		log.Printf("Warning: unsaved changes are lost: %v", err)
+		return
+	}
+	log.Printf("Warning: the events file could not be written; your events were saved to %s", path)
+}
 
-	case terminal.ActionShowEvents:
-		// Enter key - navigate to selected date and close search
-		app.processSearchResultSelection()
+// emergencyFilePath returns where a copy of unsaved events is written
+func (app *Application) emergencyFilePath() string {
+	if app.config == nil {
+		return (&config.Config{}).GetEmergencyFilePath()
+	}
+	return app.config.GetEmergencyFilePath()
+}
 
-	default:
-		// For other keys, ignore them in search mode
+// undoPendingDelete takes back the most recent deletion that is not written yet,
+// reporting whether there was one
+func (app *Application) undoPendingDelete() bool {
+	pending := app.events.PendingDeletes()
+	if len(pending) == 0 {
 		return false
 	}
+	event := pending[len(pending)-1].Event
+	if err := app.history.UndoDeletion(event); err != nil {
+		app.showError(fmt.Sprintf("Cannot undo: %v", err))
+	}
+	return true
+}
 
-	return false
+// deleteToast returns the countdown shown while the latest deletion can be undone,
+// e.g. `Deleted "Standup" - press U to undo (4s)`, or "" without one
+func (app *Application) deleteToast(now time.Time) string {
+	pending := app.events.PendingDeletes()
+	if len(pending) == 0 {
+		return ""
+	}
+	latest := pending[len(pending)-1]
+	seconds := int(math.Ceil(latest.Due.Sub(now).Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return fmt.Sprintf("Deleted %q - press U to undo (%ds)", latest.Event.Description, seconds)
 }
 
-// handleCalendarEventSelectionAction handles actions when selecting events in calendar view
-func (app *Application) handleCalendarEventSelectionAction(action terminal.KeyAction) bool {
-	switch action {
-	case terminal.ActionQuit:
-		return app.confirmExit() // Exit application with confirmation
+// reloadAfterSync reloads events and bookmarks if a sync pull changed the data directory.
+// When the pull may have replaced events written while it ran, the events in memory are
+// written back instead, and the next round pushes them.
+func (app *Application) reloadAfterSync() {
+	if !app.sync.TakePulled() {
+		return
+	}
+	app.crashGuard.Enter("sync")
+	if app.sync.TakeOverwritten() {
+		if err := app.events.Rewrite(); err != nil {
+			app.showError(fmt.Sprintf("Failed to keep changes made during sync: %v", err))
+		}
+		return
+	}
+	if err := app.events.LoadEvents(); err != nil {
+		app.showError(fmt.Sprintf("Failed to reload synced events: %v", err))
+		return
+	}
+	_ = app.bookmarks.Load()
+}
 
-	case terminal.ActionBack:
-		// Exit event selection mode and return to calendar navigation
-		app.state = StateCalendar
-		app.selectedEventIndex = 0
+// startWatching watches the events files for changes made by other programs, such as
+// a sync tool or an editor, waking up the event loop to reload them
+func (app *Application) startWatching() {
+	if app.config == nil || !app.config.LiveReload {
+		return
+	}
+	files := app.events.WatchedFiles()
+	if len(files) == 0 {
+		return
+	}
+	w, err := watcher.New(files, app.terminal.Interrupt)
+	if err != nil {
+		app.showError(fmt.Sprintf("Cannot watch the events file for changes: %v", err))
+		return
+	}
+	app.watcher = w
+}
 
-	case terminal.ActionMoveUp:
-		app.navigateCalendarEventUp()
+// stopWatching ends watching the events files
+func (app *Application) stopWatching() {
+	if app.watcher != nil {
+		app.watcher.Close()
+		app.watcher = nil
+	}
+}
 
-	case terminal.ActionMoveDown:
-		app.navigateCalendarEventDown()
+// reloadChangedFiles reloads the events after another program changed an events file,
+// ignoring the notifications of the calendar's own writes
+func (app *Application) reloadChangedFiles() {
+	if app.watcher == nil || !app.watcher.TakeChanged() || !app.events.ChangedOnDisk() {
+		return
+	}
+	now := time.Now().Format("15:04")
+	if _, unsaved := app.events.Unsaved(); unsaved {
+		// The retried write would overwrite the change, which the status bar points out
+		app.reloadStatus = "changed on disk " + now + ", not reloaded"
+		return
+	}
+	app.crashGuard.Enter("reload")
+	if err := app.events.LoadEvents(); err != nil {
+		app.reloadStatus = fmt.Sprintf("reload failed %s: %v", now, err)
+		return
+	}
+	app.reloadStatus = "reloaded " + now
+}
 
-	case terminal.ActionShowEvents:
-		// Enter key - confirm deletion of selected event
-		app.processDeleteSelectedCalendarEvent()
+// checkStoreSize hints at archiving when the events file has grown past the
+// configured limits, offering to run the archive command with a single key
+func (app *Application) checkStoreSize() {
+	if app.config == nil {
+		return
+	}
+	size := app.events.Size()
+	if !size.Exceeds(app.config.EventsWarnCount, app.config.EventsWarnBytes) {
+		return
+	}
 
-	default:
-		// For other keys, ignore them in event selection mode
-		return false
+	if app.config.ArchiveCmd == "" {
+		app.showWarning(fmt.Sprintf("Large events file (%s) - consider archiving old events", size))
+		return
+	}
+	archive := app.confirmAction(fmt.Sprintf("Large events file (%s) - Enter: archive, Esc: later", size))
+	if err := app.renderCurrentView(); err != nil {
+		app.showError(fmt.Sprintf("Render error: %v", err))
+	}
+	if archive {
+		app.archiveEvents()
 	}
+}
 
-	return false
+// archiveEvents runs the archive command in the data directory and reloads the events it left
+func (app *Application) archiveEvents() {
+	if app.events.ReadOnly() {
+		app.showError("The archive command only runs on the events file")
+		return
+	}
+	if err := alarm.ShellExecIn(app.config.GetDataDir(), app.config.ArchiveCmd); err != nil {
+		app.showError(fmt.Sprintf("Archive failed: %v", err))
+		return
+	}
+	if err := app.events.LoadEvents(); err != nil {
+		app.showError(fmt.Sprintf("Failed to reload archived events: %v", err))
+		return
+	}
+	app.showMessage(fmt.Sprintf("Archive finished: %s left", app.events.Size()))
 }
 
-// handleCalendarEventAddAction handles actions when adding events in calendar view
-func (app *Application) handleCalendarEventAddAction(action terminal.KeyAction) bool {
-	switch action {
-	case terminal.ActionQuit:
-		return app.confirmExit() // Exit application with confirmation
+// stopSync stops background syncing and pushes local changes on shutdown
+func (app *Application) stopSync() {
+	app.sync.Stop()
+	if err := app.sync.Push(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
 
-	case terminal.ActionBack:
-		// Exit event add mode and return to calendar navigation
-		app.state = StateCalendar
-		app.selectedEventIndex = 0
+// windowTitle returns the terminal title for the displayed month
+func (app *Application) windowTitle() string {
+	month := app.calendar.CurrentMonth
+	var names []string
+	if app.config != nil {
+		names = app.config.MonthNames
+	}
+	return fmt.Sprintf("ascii-calendar — %s %d", calendar.GetLocalizedMonthName(names, month), month.Year())
+}
 
-	case terminal.ActionShowEvents:
-		// Enter key - start adding the event
-		app.processAddEventFromCalendar()
+// updateTitle shows the displayed month in the terminal title when enabled
+func (app *Application) updateTitle() {
+	if app.config == nil || !app.config.TerminalTitle {
+		return
+	}
+	app.terminal.SetTitle(app.windowTitle())
+}
 
-	default:
-		// For other keys, ignore them in event add mode
+// handleAction handles the given action based on current state
+func (app *Application) handleAction(action terminal.KeyAction) bool {
+	// Quick filters apply in every view
+	if action == terminal.ActionClearFilters {
+		app.events.ClearFilters()
+		app.renderer.SetFreeEvenings(false)
+		app.refreshFilteredView()
+		return false
+	}
+	if key, ok := terminal.QuickFilterKey(action); ok {
+		app.toggleQuickFilter(key)
+		return false
+	}
+	if action == terminal.ActionToggleFlag && app.isEventSelectionState() {
+		app.toggleSelectedEventFlag()
+		return false
+	}
+	if action == terminal.ActionSetReminder && app.isEventSelectionState() {
+		app.setSelectedEventReminder()
+		return false
+	}
+	// While a deletion can be undone, U takes it back in every view
+	if action == terminal.ActionUndo && app.undoPendingDelete() {
 		return false
 	}
 
+	switch app.state {
+	case StateCalendar:
+		return app.handleCalendarAction(action)
+	case StateCalendarEventSelection:
+		return app.handleCalendarEventSelectionAction(action)
+	case StateCalendarEventAdd:
+		return app.handleCalendarEventAddAction(action)
+	case StateCalendarEventEdit:
+		return app.handleCalendarEventEditAction(action)
+	case StateSearch:
+		return app.handleSearchAction(action)
+	case StateEventList:
+		return app.handleEventListAction(action)
+	case StateAddEvent:
+		return app.handleAddEventAction(action)
+	case StateStats:
+		return app.handleStatsAction(action)
+	case StateActivityLog:
+		return app.handleActivityLogAction(action)
+	case StateBookmarks:
+		return app.handleBookmarksAction(action)
+	case StateFollowUps:
+		return app.handleFollowUpsAction(action)
+	case StateHelp:
+		return app.handleHelpAction(action)
+	case StateTour:
+		return app.handleTourAction(action)
+	case StateDayView:
+		return app.handleDayViewAction(action)
+	case StatePaste:
+		return app.handlePasteAction(action)
+	case StateReview:
+		return app.handleReviewAction(action)
+	case StateConflict:
+		return app.handleConflictAction(action)
+	case StateCalendars:
+		return app.handleCalendarsAction(action)
+	case StateBackups:
+		return app.handleBackupsAction(action)
+	case StateMessages:
+		return app.handleMessagesAction(action)
+	case StateDetails:
+		return app.handleEventDetailsAction(action)
+	}
 	return false
 }
 
-// handleCalendarEventEditAction handles actions when editing events in calendar view
-func (app *Application) handleCalendarEventEditAction(action terminal.KeyAction) bool {
+// toggleQuickFilter toggles the quick filter bound to a function key
+func (app *Application) toggleQuickFilter(key string) {
+	filter, ok := app.config.GetQuickFilter(key)
+	if !ok {
+		app.renderer.Reject()
+		return
+	}
+	app.events.ToggleFilter(filter)
+	app.refreshFilteredView()
+}
+
+// refreshFilteredView updates the current view after the quick filters changed
+func (app *Application) refreshFilteredView() {
+	switch app.state {
+	case StateSearch:
+		app.runSearch(app.searchQuery)
+	case StateEventList, StateCalendarEventSelection, StateCalendarEventEdit:
+		count := len(app.events.GetEventsForDate(app.navigation.GetCurrentSelection()))
+		if app.selectedEventIndex >= count {
+			app.selectedEventIndex = max(count-1, 0)
+		}
+		if count == 0 && app.state != StateEventList {
+			app.state = StateCalendar // No event left to select
+		}
+	}
+}
+
+// handleCalendarAction handles actions when in calendar view
+func (app *Application) handleCalendarAction(action terminal.KeyAction) bool {
 	switch action {
 	case terminal.ActionQuit:
 		return app.confirmExit() // Exit application with confirmation
 
 	case terminal.ActionBack:
-		// Exit event edit mode and return to calendar navigation
-		app.state = StateCalendar
-		app.selectedEventIndex = 0
+		if app.selection.HasRange() {
+			app.selection.RangeStart = time.Time{} // Esc cancels a marked range first
+			return false
+		}
+		return app.confirmExit() // Exit application when Esc is pressed on main screen
+
+	case terminal.ActionMonthPrev:
+		app.navigation.NavigateMonthBackward()
+
+	case terminal.ActionMonthNext:
+		app.navigation.NavigateMonthForward()
+
+	case terminal.ActionMoveLeft:
+		if !app.navigation.NavigateDayLeft() {
+			app.renderer.Reject()
+		}
+
+	case terminal.ActionMoveRight:
+		if !app.navigation.NavigateDayRight() {
+			app.renderer.Reject()
+		}
 
 	case terminal.ActionMoveUp:
-		app.navigateCalendarEventEditUp()
+		if !app.navigation.NavigateDayUp() {
+			app.renderer.Reject()
+		}
 
 	case terminal.ActionMoveDown:
-		app.navigateCalendarEventEditDown()
+		if !app.navigation.NavigateDayDown() {
+			app.renderer.Reject()
+		}
 
 	case terminal.ActionShowEvents:
-		// Enter key - confirm editing of selected event
-		app.processEditSelectedCalendarEvent()
+		app.state = StateEventList
+		app.selectedEventIndex = 0 // Initialize event selection
 
-	default:
-		// For other keys, ignore them in event edit mode
-		return false
+	case terminal.ActionAddEvent:
+		// Directly start adding event from calendar view
+		app.processAddEventFromCalendar()
+
+	case terminal.ActionDeleteEvent:
+		// Enter event selection mode in calendar view
+		selectedDate := app.navigation.GetCurrentSelection()
+		events := app.events.GetEventsForDate(selectedDate)
+		if len(events) > 0 {
+			app.state = StateCalendarEventSelection
+			app.selectedEventIndex = 0
+		} else {
+			app.showError("No events to delete on this date")
+		}
+
+	case terminal.ActionEditEvent:
+		// Enter event edit selection mode in calendar view
+		selectedDate := app.navigation.GetCurrentSelection()
+		events := app.events.GetEventsForDate(selectedDate)
+		if len(events) > 0 {
+			app.state = StateCalendarEventEdit
+			app.selectedEventIndex = 0
+		} else {
+			app.showError("No events to edit on this date")
+		}
+
+	case terminal.ActionMoveEvent:
+		// Shift+M moves the only event of the date, or selects the event to move
+		events := app.events.GetEventsForDate(app.navigation.GetCurrentSelection())
+		switch {
+		case len(events) == 0:
+			app.showError("No events to move on this date")
+		case len(events) == 1:
+			app.selectedEventIndex = 0
+			app.processMoveSelectedCalendarEvent()
+		default:
+			app.state = StateCalendarEventEdit
+			app.selectedEventIndex = 0
+			app.showMessage("Select the event to move, then press Shift+M")
+		}
+
+	case terminal.ActionResetCurrent:
+		app.navigation.ResetToCurrent()
+
+	case terminal.ActionDeleteSelected:
+		app.deleteSelectedDateEvent()
+
+	case terminal.ActionSearch:
+		app.processSearch()
+
+	case terminal.ActionShowStats:
+		app.state = StateStats
+
+	case terminal.ActionShowActivityLog:
+		app.selectedActivityIndex = 0
+		app.state = StateActivityLog
+
+	case terminal.ActionBookmark:
+		app.processBookmarkSelectedDate()
+
+	case terminal.ActionShowBookmarks:
+		app.selectedBookmarkIndex = 0
+		app.state = StateBookmarks
+
+	case terminal.ActionShowCalendars:
+		app.selectedCalendarIndex = 0
+		app.state = StateCalendars
+
+	case terminal.ActionShowMessages:
+		app.selectedMessageIndex = 0
+		app.state = StateMessages
+
+	case terminal.ActionShowFollowUps:
+		app.selectedFollowUpIndex = 0
+		app.state = StateFollowUps
+
+	case terminal.ActionShowInbox:
+		app.selectedReviewIndex = 0
+		app.state = StateReview
+
+	case terminal.ActionSyncNow:
+		app.syncCalDAV()
+
+	case terminal.ActionCycleTheme:
+		app.cycleTheme()
+
+	case terminal.ActionMarkRange:
+		if app.selection.HasRange() {
+			app.selection.RangeStart = time.Time{}
+		} else {
+			app.selection.RangeStart = app.navigation.GetCurrentSelection()
+		}
+
+	case terminal.ActionCopyEvents:
+		app.copyEvents()
+
+	case terminal.ActionShowHelp:
+		app.state = StateHelp
+
+	case terminal.ActionShowDayView:
+		app.openDayView()
+
+	case terminal.ActionToggleFreeEvenings:
+		app.renderer.SetFreeEvenings(!app.renderer.FreeEvenings())
+
+	case terminal.ActionGoToDate:
+		app.processGoToDate()
+
+	case terminal.ActionPasteEvents:
+		app.pasteEvents()
+
+	case terminal.ActionToggleFocus:
+		app.renderer.SetFocus(!app.renderer.Focus())
+
+	case terminal.ActionScrollEventsUp:
+		if !app.renderer.ScrollEvents(app.selection.SelectedDate, -1) {
+			app.renderer.Reject()
+		}
+
+	case terminal.ActionScrollEventsDown:
+		if !app.renderer.ScrollEvents(app.selection.SelectedDate, 1) {
+			app.renderer.Reject()
+		}
 	}
 
 	return false
 }
 
-// handleEventListAction handles actions when viewing events
-func (app *Application) handleEventListAction(action terminal.KeyAction) bool {
+// openDayView shows the selected date hour by hour, starting at the current hour
+// today, at the first event on other days and at 09:00 on days without events
+func (app *Application) openDayView() {
+	date := app.navigation.GetCurrentSelection()
+	app.dayViewHour = 9
+	if calendar.IsToday(date) {
+		app.dayViewHour = time.Now().Hour()
+	} else if events := app.events.GetEventsForDate(date); len(events) > 0 {
+		app.dayViewHour = events[0].Time.Hour()
+	}
+	app.dayViewAnchor = -1
+	app.state = StateDayView
+}
+
+// handleDayViewAction handles actions in the day view. Enter marks the selected hour
+// as the start of a new event, J/K then move its end and a second Enter creates it.
+func (app *Application) handleDayViewAction(action terminal.KeyAction) bool {
 	switch action {
 	case terminal.ActionQuit:
 		return app.confirmExit() // Exit application with confirmation
 
 	case terminal.ActionBack:
+		if app.dayViewAnchor >= 0 {
+			app.dayViewAnchor = -1 // Esc cancels marking first
+		} else {
+			app.state = StateCalendar
+		}
+
+	case terminal.ActionShowDayView:
 		app.state = StateCalendar
-		app.selectedEventIndex = 0 // Reset event selection
 
 	case terminal.ActionMoveUp:
-		app.navigateEventUp()
+		if app.dayViewHour > 0 {
+			app.dayViewHour--
+		} else {
+			app.renderer.Reject()
+		}
 
 	case terminal.ActionMoveDown:
-		app.navigateEventDown()
+		if app.dayViewHour < terminal.DayViewHours-1 {
+			app.dayViewHour++
+		} else {
+			app.renderer.Reject()
+		}
+
+	case terminal.ActionMoveLeft, terminal.ActionMoveRight:
+		moved := app.navigation.NavigateDayLeft
+		if action == terminal.ActionMoveRight {
+			moved = app.navigation.NavigateDayRight
+		}
+		if !moved() {
+			app.renderer.Reject()
+		}
+		app.dayViewAnchor = -1
+
+	case terminal.ActionShowEvents:
+		if app.dayViewAnchor < 0 {
+			app.dayViewAnchor = app.dayViewHour
+		} else {
+			app.createDayViewEvent()
+		}
 
 	case terminal.ActionAddEvent:
-		app.processAddEventFromEventsList()
+		app.quickAddEvent(app.quickAddDate())
+	}
+
+	return false
+}
+
+// createDayViewEvent asks for the description of the event marked in the day view and
+// adds it, lasting from the first through the last marked hour
+func (app *Application) createDayViewEvent() {
+	first, last := min(app.dayViewAnchor, app.dayViewHour), max(app.dayViewAnchor, app.dayViewHour)
+	app.dayViewAnchor = -1
+	date := app.navigation.GetCurrentSelection()
+
+	prompt := fmt.Sprintf("Description for %02d:00-%02d:00:", first, (last+1)%terminal.DayViewHours)
+	description, ok := app.input.GetTextInputWithPrompt(prompt, 100, app.renderer)
+	if !ok {
+		return // User cancelled
+	}
+
+	duration := time.Duration(last-first+1) * time.Hour
+	added, err := app.addEvent(date, fmt.Sprintf("%02d:00", first), description, duration)
+	if err != nil {
+		app.showError(fmt.Sprintf("Error adding event: %v", err))
+		return
+	}
+	if !added {
+		return
+	}
+	app.showMessage(fmt.Sprintf("Event added for %s", app.formatDate(date)))
+}
+
+// handleHelpAction handles actions on the help screen
+func (app *Application) handleHelpAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack, terminal.ActionShowHelp:
+		app.state = StateCalendar
+
+	case terminal.ActionShowEvents:
+		app.startTour()
+	}
+
+	return false
+}
+
+// startTour shows the first step of the onboarding tour, leaving focus mode so the
+// legend it points out is shown
+func (app *Application) startTour() {
+	app.renderer.SetFocus(false)
+	app.tourStep = 0
+	app.state = StateTour
+}
+
+// handleTourAction handles actions during the onboarding tour: Enter continues, the
+// action a step asks to try is performed on the calendar and continues too, and Esc
+// ends the tour
+func (app *Application) handleTourAction(action terminal.KeyAction) bool {
+	step := terminal.Tour[app.tourStep]
+
+	switch {
+	case action == terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case action == terminal.ActionBack:
+		app.endTour()
+
+	case action == terminal.ActionShowEvents:
+		app.nextTourStep()
+
+	case step.Practice != terminal.ActionNone && action == step.Practice:
+		app.handleCalendarAction(action)
+		app.nextTourStep()
+
+	case action != terminal.ActionNone:
+		app.renderer.Reject() // Other keys would leave the tour's view behind
+	}
+
+	return false
+}
+
+// nextTourStep moves on to the next tour step, ending the tour after the last one
+func (app *Application) nextTourStep() {
+	app.tourStep++
+	if app.tourStep >= len(terminal.Tour) {
+		app.endTour()
+	}
+}
+
+// endTour returns to the calendar and remembers that the tour was taken, so it is not
+// offered again on the next start
+func (app *Application) endTour() {
+	app.state = StateCalendar
+	if err := app.bookmarks.SetTourDone(); err != nil {
+		app.showError(fmt.Sprintf("Error saving state: %v", err))
+	}
+}
+
+// copyEvents copies the events of the marked range, or of the selected date, to the
+// clipboard as text and ends the range
+func (app *Application) copyEvents() {
+	start, end := app.selection.Range()
+	app.selection.RangeStart = time.Time{}
+
+	if err := copyToClipboard(app.config, app.terminal, app.events.Agenda(start, end)); err != nil {
+		app.showError(fmt.Sprintf("Copy failed: %v", err))
+		return
+	}
+	if start.Equal(end) {
+		app.showMessage(fmt.Sprintf("Copied the events of %s", start.Format("Mon Jan 2")))
+	} else {
+		app.showMessage(fmt.Sprintf("Copied the events of %s - %s", start.Format("Mon Jan 2"), end.Format("Mon Jan 2")))
+	}
+}
+
+// copyToClipboard hands text to the configured clipboard command, or to the terminal
+// with OSC 52 when none is set
+func copyToClipboard(cfg *config.Config, term *terminal.Terminal, text string) error {
+	if cfg != nil && cfg.ClipboardCmd != "" {
+		return alarm.ShellInput(cfg.ClipboardCmd, text)
+	}
+	term.CopyToClipboard(text)
+	return nil
+}
+
+// pasteEvents reads events, one per line, from the configured clipboard command or
+// the paste box and lists them for review; dates are relative to the selected date
+func (app *Application) pasteEvents() {
+	var text string
+	if app.config.ClipboardPasteCmd != "" {
+		output, err := alarm.ShellOutput(app.config.ClipboardPasteCmd)
+		if err != nil {
+			app.showError(fmt.Sprintf("Paste failed: %v", err))
+			return
+		}
+		text = output
+	} else {
+		input, ok := app.input.GetPasteInput(app.renderer)
+		if !ok {
+			return
+		}
+		text = input
+	}
+
+	lines := app.events.ParsePaste(text, app.navigation.GetCurrentSelection())
+	if len(lines) == 0 {
+		app.showMessage("Nothing to paste")
+		return
+	}
+	app.pasteLines = lines
+	app.selectedPasteIndex = 0
+	app.state = StatePaste
+}
+
+// handlePasteAction handles actions while reviewing pasted lines. X accepts or
+// rejects the selected line and Enter adds the accepted events together.
+func (app *Application) handlePasteAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack:
+		app.pasteLines = nil
+		app.state = StateCalendar
+
+	case terminal.ActionMoveUp:
+		if app.selectedPasteIndex > 0 {
+			app.selectedPasteIndex--
+		}
+
+	case terminal.ActionMoveDown:
+		if app.selectedPasteIndex < len(app.pasteLines)-1 {
+			app.selectedPasteIndex++
+		}
+
+	case terminal.ActionTogglePasteLine:
+		line := &app.pasteLines[app.selectedPasteIndex]
+		if line.Err != nil {
+			app.renderer.Reject()
+			break
+		}
+		line.Accepted = !line.Accepted
+
+	case terminal.ActionShowEvents: // Enter adds the accepted events
+		added, err := app.events.SavePaste(app.pasteLines)
+		if err != nil {
+			app.showError(fmt.Sprintf("Error adding pasted events: %v", err))
+			break
+		}
+		app.pasteLines = nil
+		app.state = StateCalendar
+		app.showMessage(fmt.Sprintf("Added %d pasted events", added))
+	}
+
+	return false
+}
+
+// handleStatsAction handles actions when viewing usage statistics
+func (app *Application) handleStatsAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack, terminal.ActionShowStats:
+		app.state = StateCalendar
+
+	case terminal.ActionAddEvent:
+		app.quickAddEvent(app.quickAddDate())
+	}
+
+	return false
+}
+
+// handleActivityLogAction handles actions when viewing the session activity log
+func (app *Application) handleActivityLogAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack, terminal.ActionShowActivityLog:
+		app.state = StateCalendar
+
+	case terminal.ActionMoveUp:
+		if app.selectedActivityIndex > 0 {
+			app.selectedActivityIndex--
+		}
+
+	case terminal.ActionMoveDown:
+		if app.selectedActivityIndex < app.history.Len()-1 {
+			app.selectedActivityIndex++
+		}
+
+	case terminal.ActionAddEvent:
+		app.quickAddEvent(app.quickAddDate())
+
+	case terminal.ActionUndo:
+		if app.history.Len() == 0 {
+			break
+		}
+		if err := app.history.Undo(app.selectedActivityIndex); err != nil {
+			app.showError(fmt.Sprintf("Cannot undo: %v", err))
+		}
+
+	case terminal.ActionShowBackups:
+		app.openBackups()
+	}
+
+	return false
+}
+
+// handleBookmarksAction handles actions in the bookmark picker
+func (app *Application) handleBookmarksAction(action terminal.KeyAction) bool {
+	bookmarks := app.bookmarks.Bookmarks()
+
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack, terminal.ActionShowBookmarks:
+		app.state = StateCalendar
+
+	case terminal.ActionMoveUp:
+		if app.selectedBookmarkIndex > 0 {
+			app.selectedBookmarkIndex--
+		}
+
+	case terminal.ActionMoveDown:
+		if app.selectedBookmarkIndex < len(bookmarks)-1 {
+			app.selectedBookmarkIndex++
+		}
+
+	case terminal.ActionShowEvents: // Enter jumps to the bookmarked date
+		if len(bookmarks) == 0 {
+			break
+		}
+		date, err := bookmarks[app.selectedBookmarkIndex].GetDate()
+		if err != nil {
+			app.showError(fmt.Sprintf("Invalid bookmark date: %v", err))
+			break
+		}
+		app.navigation.JumpToDate(date)
+		app.state = StateCalendar
+
+	case terminal.ActionAddEvent:
+		app.quickAddEvent(app.quickAddDate())
 
 	case terminal.ActionDeleteEvent:
-		app.processDeleteEventFromList()
+		if len(bookmarks) == 0 {
+			break
+		}
+		bookmark := bookmarks[app.selectedBookmarkIndex]
+		date, err := bookmark.GetDate()
+		if err == nil {
+			err = app.bookmarks.RemoveBookmark(date)
+		}
+		if err != nil {
+			app.showError(fmt.Sprintf("Error deleting bookmark: %v", err))
+			break
+		}
+		if app.selectedBookmarkIndex >= len(bookmarks)-1 && app.selectedBookmarkIndex > 0 {
+			app.selectedBookmarkIndex--
+		}
+	}
+
+	return false
+}
+
+// handleCalendarsAction handles actions in the calendars view
+func (app *Application) handleCalendarsAction(action terminal.KeyAction) bool {
+	names := []string{config.MainCalendarName}
+	if app.config != nil {
+		names = app.config.CalendarNames()
+	}
+
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack, terminal.ActionShowCalendars:
+		app.state = StateCalendar
+
+	case terminal.ActionMoveUp:
+		if app.selectedCalendarIndex > 0 {
+			app.selectedCalendarIndex--
+		}
+
+	case terminal.ActionMoveDown:
+		if app.selectedCalendarIndex < len(names)-1 {
+			app.selectedCalendarIndex++
+		}
+
+	case terminal.ActionToggleCalendar, terminal.ActionShowEvents: // Enter works like X
+		name := names[app.selectedCalendarIndex]
+		key := ""
+		if app.config != nil {
+			key, _ = app.config.CalendarKey(name)
+		}
+		if app.events.ToggleCalendar(key) {
+			app.showMessage(fmt.Sprintf("Showing calendar %s", name))
+		} else {
+			app.showMessage(fmt.Sprintf("Hiding calendar %s", name))
+		}
+	}
+
+	return false
+}
+
+// openBackups lists the backups of the events files to restore one
+func (app *Application) openBackups() {
+	backups, err := app.events.Backups()
+	if err != nil {
+		app.showError(fmt.Sprintf("Error listing backups: %v", err))
+		return
+	}
+	app.backups = backups
+	app.selectedBackupIndex = 0
+	app.state = StateBackups
+}
+
+// handleMessagesAction handles actions in the list of the latest status line messages
+func (app *Application) handleMessagesAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack, terminal.ActionShowMessages:
+		app.state = StateCalendar
+
+	case terminal.ActionMoveUp:
+		if app.selectedMessageIndex > 0 {
+			app.selectedMessageIndex--
+		}
+
+	case terminal.ActionMoveDown:
+		if app.selectedMessageIndex < len(app.messages.History())-1 {
+			app.selectedMessageIndex++
+		}
+	}
+
+	return false
+}
+
+// handleBackupsAction handles actions in the backups view, opened from the activity log
+func (app *Application) handleBackupsAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack:
+		app.state = StateActivityLog
+
+	case terminal.ActionMoveUp:
+		if app.selectedBackupIndex > 0 {
+			app.selectedBackupIndex--
+		}
+
+	case terminal.ActionMoveDown:
+		if app.selectedBackupIndex < len(app.backups)-1 {
+			app.selectedBackupIndex++
+		}
+
+	case terminal.ActionShowEvents: // Enter restores the selected backup
+		if app.selectedBackupIndex >= len(app.backups) {
+			break
+		}
+		backup := app.backups[app.selectedBackupIndex]
+		if backup.Events < 0 {
+			app.showError("This backup cannot be read; pick another one")
+			break
+		}
+		message := fmt.Sprintf("Replace %s with the backup of %s (%s)? (Enter: restore, Esc: cancel)",
+			filepath.Base(backup.File), backup.ModTime.Format("2006-01-02 15:04"), backupEvents(backup))
+		if !app.confirmAction(message) {
+			break
+		}
+		app.flushDeletes()
+		if err := app.events.RestoreBackup(backup); err != nil {
+			app.showError(fmt.Sprintf("Error restoring backup: %v", err))
+			break
+		}
+		app.state = StateCalendar
+		app.showMessage(fmt.Sprintf("Restored %s; the replaced file is now backup 1", filepath.Base(backup.File)))
+	}
+
+	return false
+}
+
+// handleFollowUpsAction handles actions in the follow-up list
+func (app *Application) handleFollowUpsAction(action terminal.KeyAction) bool {
+	flagged := app.events.FlaggedEvents()
+
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack, terminal.ActionShowFollowUps:
+		app.state = StateCalendar
+
+	case terminal.ActionMoveUp:
+		if app.selectedFollowUpIndex > 0 {
+			app.selectedFollowUpIndex--
+		}
+
+	case terminal.ActionMoveDown:
+		if app.selectedFollowUpIndex < len(flagged)-1 {
+			app.selectedFollowUpIndex++
+		}
+
+	case terminal.ActionShowEvents: // Enter opens the events of the flagged event's date
+		if app.selectedFollowUpIndex >= len(flagged) {
+			break
+		}
+		event := flagged[app.selectedFollowUpIndex]
+		app.navigation.JumpToDate(event.Date)
+		app.selectedEventIndex = 0
+		for i, dayEvent := range app.events.GetEventsForDate(event.Date) {
+			if dayEvent.Time.Equal(event.Time) && dayEvent.Description == event.Description && dayEvent.Calendar == event.Calendar {
+				app.selectedEventIndex = i
+				break
+			}
+		}
+		app.state = StateEventList
+
+	case terminal.ActionToggleFlag: // Clearing the flag takes the event off the list
+		if app.selectedFollowUpIndex >= len(flagged) {
+			break
+		}
+		if _, err := app.events.ToggleFlag(flagged[app.selectedFollowUpIndex]); err != nil {
+			app.showError(fmt.Sprintf("Error clearing flag: %v", err))
+			break
+		}
+		if app.selectedFollowUpIndex >= len(flagged)-1 && app.selectedFollowUpIndex > 0 {
+			app.selectedFollowUpIndex--
+		}
+
+	case terminal.ActionAddEvent:
+		app.quickAddEvent(app.quickAddDate())
+	}
+
+	return false
+}
+
+// handleReviewAction handles actions in the review queue of imported events
+func (app *Application) handleReviewAction(action terminal.KeyAction) bool {
+	unreviewed := app.events.UnreviewedEvents()
+
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack, terminal.ActionShowInbox:
+		app.state = StateCalendar
+
+	case terminal.ActionMoveUp:
+		if app.selectedReviewIndex > 0 {
+			app.selectedReviewIndex--
+		}
+
+	case terminal.ActionMoveDown:
+		if app.selectedReviewIndex < len(unreviewed)-1 {
+			app.selectedReviewIndex++
+		}
+
+	case terminal.ActionShowEvents: // Enter keeps the event as imported, or opens its conflict
+		if app.selectedReviewIndex >= len(unreviewed) {
+			break
+		}
+		event := unreviewed[app.selectedReviewIndex]
+		if event.Conflict != nil {
+			app.conflictEvent = event
+			app.selectedConflictField = 0
+			app.conflictFromSource = make(map[events.ConflictField]bool)
+			app.state = StateConflict
+			break
+		}
+		if _, err := app.events.AcceptEvent(event, event.Category); err != nil {
+			app.showError(fmt.Sprintf("Error accepting event: %v", err))
+			break
+		}
+		app.clampReviewIndex(len(unreviewed) - 1)
+
+	case terminal.ActionDeleteEvent:
+		if app.selectedReviewIndex >= len(unreviewed) {
+			break
+		}
+		event := unreviewed[app.selectedReviewIndex]
+		if !app.confirmDelete("Delete imported event: "+event.GetDateString(), event) {
+			break
+		}
+		if err := app.deleteEvent(event); err != nil {
+			app.showError(fmt.Sprintf("Error deleting event: %v", err))
+			break
+		}
+		app.clampReviewIndex(len(unreviewed) - 1)
+	}
+
+	return false
+}
+
+// acceptReviewedEvent accepts the selected event of the review queue into the category
+// bound to digit; 0 accepts it without a category
+func (app *Application) acceptReviewedEvent(digit rune) {
+	unreviewed := app.events.UnreviewedEvents()
+	if app.selectedReviewIndex >= len(unreviewed) {
+		return
+	}
+
+	if unreviewed[app.selectedReviewIndex].Conflict != nil {
+		app.showError("Resolve the conflict first (Enter)")
+		return
+	}
+
+	category := ""
+	if digit != '0' {
+		assigned, ok := app.config.GetCategoryByHotkey(string(digit))
+		if !ok {
+			app.showError(fmt.Sprintf("No category bound to key %c", digit))
+			return
+		}
+		category = assigned.Name
+	}
+
+	if _, err := app.events.AcceptEvent(unreviewed[app.selectedReviewIndex], category); err != nil {
+		app.showError(fmt.Sprintf("Error accepting event: %v", err))
+		return
+	}
+	app.clampReviewIndex(len(unreviewed) - 1)
+}
+
+// handleConflictAction handles keys in the conflict view: each field is taken from the
+// calendar's or the source's version, or the whole event from either
+func (app *Application) handleConflictAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack:
+		app.state = StateReview
+
+	case terminal.ActionMoveUp:
+		if app.selectedConflictField > 0 {
+			app.selectedConflictField--
+		}
+
+	case terminal.ActionMoveDown:
+		if app.selectedConflictField < len(events.ConflictFields)-1 {
+			app.selectedConflictField++
+		}
+
+	case terminal.ActionMoveLeft:
+		app.conflictFromSource[events.ConflictFields[app.selectedConflictField]] = false
+
+	case terminal.ActionMoveRight:
+		app.conflictFromSource[events.ConflictFields[app.selectedConflictField]] = true
+
+	case terminal.ActionShowEvents: // Enter resolves with the fields as chosen
+		app.resolveConflict(app.conflictFromSource)
+
+	case terminal.ActionMoveEventWeekEarlier: // < keeps the calendar's version
+		app.resolveConflict(nil)
+
+	case terminal.ActionMoveEventWeekLater: // > takes the source's version
+		all := make(map[events.ConflictField]bool)
+		for _, field := range events.ConflictFields {
+			all[field] = true
+		}
+		app.resolveConflict(all)
+	}
+
+	return false
+}
+
+// resolveConflict settles the conflict being shown with the fields in fromSource taken
+// from the source, and returns to the review queue
+func (app *Application) resolveConflict(fromSource map[events.ConflictField]bool) {
+	if _, err := app.events.ResolveConflict(app.conflictEvent, fromSource); err != nil {
+		app.showError(fmt.Sprintf("Error resolving conflict: %v", err))
+		return
+	}
+	app.state = StateReview
+	app.clampReviewIndex(len(app.events.UnreviewedEvents()))
+}
+
+// clampReviewIndex keeps the selection of the review queue on one of its remaining
+// events after one left it
+func (app *Application) clampReviewIndex(remaining int) {
+	if app.selectedReviewIndex >= remaining && app.selectedReviewIndex > 0 {
+		app.selectedReviewIndex = remaining - 1
+	}
+}
+
+// toggleSelectedEventFlag flags the selected event for follow-up, or clears its flag
+func (app *Application) toggleSelectedEventFlag() {
+	events := app.events.GetEventsForDate(app.navigation.GetCurrentSelection())
+	if app.selectedEventIndex >= len(events) {
+		app.renderer.Reject()
+		return
+	}
+	if _, err := app.events.ToggleFlag(events[app.selectedEventIndex]); err != nil {
+		app.showError(fmt.Sprintf("Error flagging event: %v", err))
+	}
+}
+
+// handleSearchAction handles actions when in search mode
+func (app *Application) handleSearchAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack:
+		// Exit search mode and return to calendar
+		app.state = StateCalendar
+		app.searchQuery = ""
+		app.searchResults = nil
+		app.searchResultDates = nil
+		app.selectedResultIndex = 0
+
+	case terminal.ActionMoveUp:
+		app.navigateSearchResultUp()
+
+	case terminal.ActionMoveDown:
+		app.navigateSearchResultDown()
+
+	case terminal.ActionShowEvents:
+		// Enter key - navigate to selected date and close search
+		app.processSearchResultSelection()
+
+	case terminal.ActionSearch:
+		// Refine the query, starting from the current one
+		app.processSearch()
+
+	case terminal.ActionAddEvent:
+		app.quickAddEvent(app.quickAddDate())
+
+	default:
+		// For other keys, ignore them in search mode
+		return false
+	}
+
+	return false
+}
+
+// handleCalendarEventSelectionAction handles actions when selecting events in calendar view
+func (app *Application) handleCalendarEventSelectionAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack:
+		// Exit event selection mode and return to calendar navigation
+		app.state = StateCalendar
+		app.selectedEventIndex = 0
+
+	case terminal.ActionMoveUp:
+		app.navigateCalendarEventUp()
+
+	case terminal.ActionMoveDown:
+		app.navigateCalendarEventDown()
+
+	case terminal.ActionShowEvents:
+		// Enter key - confirm deletion of selected event
+		app.processDeleteSelectedCalendarEvent()
+
+	default:
+		// For other keys, ignore them in event selection mode
+		return false
+	}
+
+	return false
+}
+
+// handleCalendarEventAddAction handles actions when adding events in calendar view
+func (app *Application) handleCalendarEventAddAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack:
+		// Exit event add mode and return to calendar navigation
+		app.state = StateCalendar
+		app.selectedEventIndex = 0
+
+	case terminal.ActionShowEvents:
+		// Enter key - start adding the event
+		app.processAddEventFromCalendar()
+
+	default:
+		// For other keys, ignore them in event add mode
+		return false
+	}
+
+	return false
+}
+
+// handleCalendarEventEditAction handles actions when editing events in calendar view
+func (app *Application) handleCalendarEventEditAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack:
+		// Exit event edit mode and return to calendar navigation
+		app.state = StateCalendar
+		app.selectedEventIndex = 0
+
+	case terminal.ActionMoveUp:
+		app.navigateCalendarEventEditUp()
+
+	case terminal.ActionMoveDown:
+		app.navigateCalendarEventEditDown()
+
+	case terminal.ActionShowEvents:
+		// Enter key - confirm editing of selected event
+		app.processEditSelectedCalendarEvent()
+
+	case terminal.ActionMoveEvent:
+		app.processMoveSelectedCalendarEvent()
+
+	default:
+		// For other keys, ignore them in event edit mode
+		return false
+	}
+
+	return false
+}
+
+// handleEventListAction handles actions when viewing events
+func (app *Application) handleEventListAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack:
+		app.state = StateCalendar
+		app.selectedEventIndex = 0 // Reset event selection
+
+	case terminal.ActionMoveUp:
+		app.navigateEventUp()
+
+	case terminal.ActionMoveDown:
+		app.navigateEventDown()
+
+	case terminal.ActionAddEvent:
+		app.processAddEventFromEventsList()
+
+	case terminal.ActionDeleteEvent:
+		app.processDeleteEventFromList()
+
+	case terminal.ActionEditEvent:
+		app.processEditEventFromList()
+
+	case terminal.ActionMoveEventDayLater:
+		app.moveSelectedListEvent(1)
+
+	case terminal.ActionMoveEventDayEarlier:
+		app.moveSelectedListEvent(-1)
+
+	case terminal.ActionMoveEventWeekLater:
+		app.moveSelectedListEvent(7)
+
+	case terminal.ActionMoveEventWeekEarlier:
+		app.moveSelectedListEvent(-7)
+
+	case terminal.ActionMoveEvent:
+		app.processMoveEventFromList()
+
+	case terminal.ActionUndo:
+		app.undoLatestChange()
+
+	case terminal.ActionCopyEvents:
+		app.copyEvents()
+
+	case terminal.ActionShowEvents: // Enter shows all details of the selected event
+		app.showEventDetails()
+	}
+
+	return false
+}
+
+// showEventDetails opens the details of the selected event of the events list
+func (app *Application) showEventDetails() {
+	events := app.events.GetEventsForDate(app.navigation.GetCurrentSelection())
+	if app.selectedEventIndex >= len(events) {
+		app.renderer.Reject()
+		return
+	}
+	app.detailsEvent = events[app.selectedEventIndex]
+	app.detailsScroll = 0
+	app.state = StateDetails
+}
+
+// handleEventDetailsAction handles keys in the event details: J/K scroll long notes
+// and E edits the notes
+func (app *Application) handleEventDetailsAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack:
+		app.state = StateEventList
+
+	case terminal.ActionMoveUp, terminal.ActionMoveDown:
+		delta := 1
+		if action == terminal.ActionMoveUp {
+			delta = -1
+		}
+		scrolled, moved := app.renderer.ScrollEventDetails(app.detailsEvent, app.detailsScroll, delta)
+		if !moved {
+			app.renderer.Reject()
+		}
+		app.detailsScroll = scrolled
+
+	case terminal.ActionEditEvent:
+		app.editEventNotes()
+	}
+
+	return false
+}
+
+// editEventNotes edits the notes of the event shown in the details view
+func (app *Application) editEventNotes() {
+	notes, ok := app.input.GetNotesInput(app.detailsEvent, app.detailsEvent.Notes, app.renderer)
+	if !ok || events.CleanNotes(notes) == app.detailsEvent.Notes {
+		return
+	}
+	updated, err := app.events.SetEventNotes(app.detailsEvent, notes)
+	if err != nil {
+		app.showError(fmt.Sprintf("Error saving notes: %v", err))
+		return
+	}
+	app.detailsEvent = updated
+	app.showMessage("Notes saved")
+}
+
+// processMoveEventFromList moves the selected event of the events list to a date typed
+// or picked on its row
+func (app *Application) processMoveEventFromList() {
+	selectedDate := app.navigation.GetCurrentSelection()
+	events := app.events.GetEventsForDate(selectedDate)
+	if len(events) == 0 {
+		app.renderer.Reject()
+		return
+	}
+	if app.selectedEventIndex >= len(events) {
+		app.selectedEventIndex = len(events) - 1
+	}
+
+	app.moveEventToDate(events[app.selectedEventIndex], selectedDate, 2, app.renderer.EventListRowY(events, app.selectedEventIndex))
+	if count := len(app.events.GetEventsForDate(selectedDate)); app.selectedEventIndex >= count {
+		app.selectedEventIndex = max(count-1, 0)
+	}
+}
+
+// moveSelectedListEvent reschedules the selected event of the events list by a number
+// of days without opening the edit form. The list stays on its date, so the next event
+// can be triaged right away.
+func (app *Application) moveSelectedListEvent(days int) {
+	events := app.events.GetEventsForDate(app.navigation.GetCurrentSelection())
+	if len(events) == 0 {
+		app.renderer.Reject()
+		return
+	}
+	if app.selectedEventIndex >= len(events) {
+		app.selectedEventIndex = len(events) - 1
+	}
+
+	moved, err := app.events.MoveEvent(events[app.selectedEventIndex], days)
+	if err != nil {
+		app.showError(fmt.Sprintf("Error moving event: %v", err))
+		return
+	}
+	if app.selectedEventIndex >= len(events)-1 && app.selectedEventIndex > 0 {
+		app.selectedEventIndex--
+	}
+	app.showMessage(fmt.Sprintf("Moved %q to %s (U: undo)", moved.Description, moved.Date.Format("Mon Jan 2")))
+}
+
+// undoLatestChange reverts the most recent change of the session, such as a move
+func (app *Application) undoLatestChange() {
+	activity, err := app.history.UndoLatest()
+	if err != nil {
+		app.showError(fmt.Sprintf("Cannot undo: %v", err))
+		return
+	}
+	app.showMessage(fmt.Sprintf("Undid the change to %q", activity.Subject().Description))
+}
+
+// handleAddEventAction handles actions when adding events
+func (app *Application) handleAddEventAction(action terminal.KeyAction) bool {
+	switch action {
+	case terminal.ActionQuit:
+		return app.confirmExit() // Exit application with confirmation
+
+	case terminal.ActionBack:
+		app.state = StateCalendar
+	}
+
+	// For adding events, we handle the input differently
+	app.processAddEvent()
+	app.state = StateCalendar
+
+	return false
+}
+
+// renderCurrentView renders the appropriate view based on current state, with the
+// countdown of a deletion that can still be undone on the bottom line
+func (app *Application) renderCurrentView() error {
+	if err := app.renderView(); err != nil {
+		return err
+	}
+	if toast := app.deleteToast(time.Now()); toast != "" {
+		app.renderer.RenderMessage(toast, false)
+		app.terminal.Flush()
+	} else if app.renderPostedMessage() {
+		app.terminal.Flush()
+	}
+	return nil
+}
+
+// renderView renders the view of the current state
+func (app *Application) renderView() error {
+	switch app.state {
+	case StateCalendar:
+		return app.renderer.RenderCalendar(app.calendar, app.selection)
+
+	case StateCalendarEventSelection:
+		// Render calendar with event selection highlighting
+		return app.renderer.RenderCalendarWithEventSelection(app.calendar, app.selection, app.selectedEventIndex)
+
+	case StateCalendarEventAdd:
+		// Render calendar with event add highlighting
+		return app.renderer.RenderCalendarWithEventAdd(app.calendar, app.selection)
+
+	case StateCalendarEventEdit:
+		// Render calendar with event edit highlighting
+		return app.renderer.RenderCalendarWithEventEdit(app.calendar, app.selection, app.selectedEventIndex)
+
+	case StateSearch:
+		// Render calendar with search results
+		return app.renderer.RenderCalendarWithSearch(app.calendar, app.selection, app.searchQuery, app.searchResults, app.searchResultDates, app.searchUpcoming, app.selectedResultIndex)
+
+	case StateEventList:
+		selectedDate := app.navigation.GetCurrentSelection()
+		eventList := app.events.GetEventsForDate(selectedDate)
+		return app.renderer.RenderEventList(selectedDate, eventList, app.selectedEventIndex)
+
+	case StateAddEvent:
+		// This state is handled differently - we don't render here
+		// but in processAddEvent()
+		return nil
+
+	case StateStats:
+		return app.renderer.RenderStats(app.stats)
+
+	case StateActivityLog:
+		return app.renderer.RenderActivityLog(app.history.Entries(), app.selectedActivityIndex)
+
+	case StateBookmarks:
+		return app.renderer.RenderBookmarks(app.bookmarks.Bookmarks(), app.selectedBookmarkIndex)
+
+	case StateCalendars:
+		return app.renderer.RenderCalendars(app.selectedCalendarIndex)
+
+	case StateBackups:
+		return app.renderer.RenderBackups(app.backups, app.selectedBackupIndex)
+
+	case StateMessages:
+		return app.renderer.RenderMessageHistory(app.messages.History(), app.selectedMessageIndex)
+
+	case StateFollowUps:
+		return app.renderer.RenderFollowUps(app.events.FlaggedEvents(), app.selectedFollowUpIndex)
+
+	case StateReview:
+		return app.renderer.RenderReviewQueue(app.events.UnreviewedEvents(), app.selectedReviewIndex)
+
+	case StateConflict:
+		return app.renderer.RenderConflict(app.conflictEvent, app.selectedConflictField, app.conflictFromSource)
+
+	case StateDetails:
+		return app.renderer.RenderEventDetails(app.detailsEvent, app.detailsScroll)
+
+	case StateHelp:
+		return app.renderer.RenderHelp()
+
+	case StateTour:
+		return app.renderer.RenderTour(app.calendar, app.selection, app.tourStep)
+
+	case StateDayView:
+		date := app.navigation.GetCurrentSelection()
+		return app.renderer.RenderDayView(date, app.events.GetEventsForDate(date), app.dayViewHour, app.dayViewAnchor)
+
+	case StatePaste:
+		return app.renderer.RenderPastePreview(app.pasteLines, app.selectedPasteIndex)
+
+	case StateBanner:
+		return app.renderer.RenderBanner(app.bannerSections, time.Now())
+
+	case StateScreensaver:
+		now := time.Now()
+		today := calendar.NormalizeDate(now)
+		upcoming := app.events.GetEventsInDateRange(today, today.AddDate(0, 0, terminal.ScreensaverDays))
+		return app.renderer.RenderScreensaver(now, upcoming, int(now.Sub(app.screensaverStart)/screensaverStep))
+	}
+
+	return nil
+}
+
+// processAddEvent handles the event addition workflow
+func (app *Application) processAddEvent() {
+	app.quickAddEvent(app.navigation.GetCurrentSelection())
+}
+
+// quickAddDate infers the date a new event is meant for from the current view: the
+// date of the selected search result, bookmark, activity or follow-up, today on the banner, and
+// the selected calendar date everywhere else
+func (app *Application) quickAddDate() time.Time {
+	switch app.state {
+	case StateSearch:
+		if app.selectedResultIndex < len(app.searchResults) {
+			return app.searchResults[app.selectedResultIndex].Date
+		}
+
+	case StateBookmarks:
+		bookmarks := app.bookmarks.Bookmarks()
+		if app.selectedBookmarkIndex < len(bookmarks) {
+			if date, err := bookmarks[app.selectedBookmarkIndex].GetDate(); err == nil {
+				return date
+			}
+		}
+
+	case StateActivityLog:
+		entries := app.history.Entries()
+		if app.selectedActivityIndex < len(entries) {
+			return entries[app.selectedActivityIndex].Subject().Date
+		}
+
+	case StateFollowUps:
+		flagged := app.events.FlaggedEvents()
+		if app.selectedFollowUpIndex < len(flagged) {
+			return flagged[app.selectedFollowUpIndex].Date
+		}
+
+	case StateReview:
+		unreviewed := app.events.UnreviewedEvents()
+		if app.selectedReviewIndex < len(unreviewed) {
+			return unreviewed[app.selectedReviewIndex].Date
+		}
+
+	case StateConflict:
+		return app.conflictEvent.Date
+
+	case StateBanner:
+		return calendar.NormalizeDate(time.Now())
+	}
+	return app.navigation.GetCurrentSelection()
+}
+
+// quickAddEvent is the add flow shared by all views: it asks for the time and the
+// description on the prompt line and adds the event on date, leaving the current view
+// open. Views with an events panel of their own use their inline flows instead.
+func (app *Application) quickAddEvent(date time.Time) {
+	app.showDayNote(date)
+
+	// Get time input with validation
+	input, ok := app.input.GetTimeInput(fmt.Sprintf("Enter time for %s (HH:MM or HH:MM-HH:MM):", calendar.FormatDate(date)), app.renderer)
+	if !ok {
+		return // User cancelled
+	}
+	timeStr, duration := splitTimeRange(input)
+
+	// Get description input
+	description, ok := app.input.GetTextInputWithPrompt("Enter description:", 100, app.renderer)
+	if !ok {
+		return // User cancelled
+	}
+
+	// Add the event
+	added, err := app.addEvent(date, timeStr, description, duration)
+	if err != nil {
+		app.showError(fmt.Sprintf("Error adding event: %v", err))
+		return
+	}
+	if !added {
+		return
+	}
+	if app.state == StateSearch {
+		// Keep the results current; the new event shows up if it matches the query
+		app.runSearch(app.searchQuery)
+	}
+	app.showMessage(fmt.Sprintf("Event added for %s", app.formatDate(date)) + app.overlapNote(date, timeStr, description))
+}
+
+// addEvent adds an event after warning about an unusual time or duration, see
+// events.Manager.TimeWarning. Adding anyway stores the event as confirmed so it is not
+// warned about again; cancelling reports false without adding it.
+func (app *Application) addEvent(date time.Time, timeStr, description string, duration time.Duration) (bool, error) {
+	eventTime, err := calendar.ParseTime(timeStr)
+	if err != nil {
+		// Rejected with the usual message below
+		return true, app.events.AddEventWithDuration(date, timeStr, description, duration)
+	}
+
+	warning := app.events.TimeWarning(models.Event{Date: date, Time: eventTime, Duration: duration})
+	if warning == "" {
+		return true, app.events.AddEventWithDuration(date, timeStr, description, duration)
+	}
+	if !app.confirmAction(fmt.Sprintf("%s%s (Enter: add anyway, Esc: cancel)", strings.ToUpper(warning[:1]), warning[1:])) {
+		return false, nil
+	}
+	return true, app.events.AddConfirmedEvent(date, timeStr, description, duration)
+}
+
+// splitTimeRange splits an entered event time, HH:MM or HH:MM-HH:MM, into the start
+// and the duration up to the end; the time inputs only return complete times
+func splitTimeRange(input string) (string, time.Duration) {
+	start, duration, err := calendar.ParseTimeRange(input)
+	if err != nil {
+		return input, 0 // Rejected with the usual message when the event is saved
+	}
+	return start, duration
+}
+
+// editTimeDefault returns the time an edit starts from: the range of a timed event,
+// or just its start when it lasts a day or more, which a range cannot express
+func editTimeDefault(event models.Event) string {
+	if event.Duration > 0 && event.Duration < 24*time.Hour {
+		return calendar.FormatTimeRange(event.Time, event.Duration)
+	}
+	return event.GetTimeString()
+}
+
+// editedTimeRange returns the start and duration of an edited event; entering only a
+// start keeps a duration of a day or more, which the form does not show
+func editedTimeRange(event models.Event, input string) (string, time.Duration) {
+	start, duration := splitTimeRange(input)
+	if duration == 0 && !strings.Contains(input, "-") && event.Duration >= 24*time.Hour {
+		duration = event.Duration
+	}
+	return start, duration
+}
+
+// overlapNote returns a note on the events overlapping the event just saved with
+// timeStr and description on date, e.g. " (overlaps 09:00 Standup)", or "" for none
+func (app *Application) overlapNote(date time.Time, timeStr, description string) string {
+	normalized := app.events.ApplyNormalization(description)
+	for _, event := range app.events.GetEventsForDate(date) {
+		if event.GetTimeString() != timeStr || (event.Description != normalized && event.Description != description) {
+			continue
+		}
+		overlaps := app.events.Overlaps(event)
+		if len(overlaps) == 0 {
+			return ""
+		}
+		note := fmt.Sprintf(" (overlaps %s %s", overlaps[0].GetTimeString(), overlaps[0].Description)
+		if len(overlaps) > 1 {
+			note += fmt.Sprintf(" +%d more", len(overlaps)-1)
+		}
+		return note + ")"
+	}
+	return ""
+}
+
+// processDeleteEvent handles the event deletion workflow
+func (app *Application) processDeleteEvent() {
+	selectedDate := app.navigation.GetCurrentSelection()
+	events := app.events.GetEventsForDate(selectedDate)
+
+	if len(events) == 0 {
+		app.showError("No events to delete on this date")
+		return
+	}
+
+	if len(events) == 1 {
+		// Only one event, delete it directly after confirmation
+		event := events[0]
+		if app.confirmDelete("Delete event", event) {
+			err := app.deleteEvent(event)
+			if err != nil {
+				app.showError(fmt.Sprintf("Error deleting event: %v", err))
+			} else {
+				app.showMessage("Event deleted successfully!")
+			}
+		}
+		return
+	}
+
+	// Multiple events - let user select which one to delete
+	selectedEvent := app.selectEventFromList(events, "Select event to delete:")
+	if selectedEvent != nil {
+		if app.confirmDelete("Delete event", *selectedEvent) {
+			err := app.deleteEvent(*selectedEvent)
+			if err != nil {
+				app.showError(fmt.Sprintf("Error deleting event: %v", err))
+			} else {
+				app.showMessage("Event deleted successfully!")
+			}
+		}
+	}
+}
+
+// processEditEvent handles the event editing workflow
+func (app *Application) processEditEvent() {
+	selectedDate := app.navigation.GetCurrentSelection()
+	events := app.events.GetEventsForDate(selectedDate)
+
+	if len(events) == 0 {
+		app.showError("No events to edit on this date")
+		return
+	}
+
+	var eventToEdit *models.Event
+	if len(events) == 1 {
+		// Only one event, edit it directly
+		eventToEdit = &events[0]
+	} else {
+		// Multiple events - let user select which one to edit
+		eventToEdit = app.selectEventFromList(events, "Select event to edit:")
+		if eventToEdit == nil {
+			return // User cancelled selection
+		}
+	}
+
+	// Get new time input with validation (default to current time)
+	currentTime := editTimeDefault(*eventToEdit)
+	prompt := fmt.Sprintf("Enter new time, HH:MM or HH:MM-HH:MM (current: %s):", currentTime)
+	input, ok := app.input.GetTimeInput(prompt, app.renderer)
+	if !ok {
+		return // User cancelled
+	}
+
+	// If user entered empty time, keep the current time
+	if input == "" {
+		input = currentTime
+	}
+	timeStr, duration := editedTimeRange(*eventToEdit, input)
+
+	// Get new description input (default to current description)
+	currentDesc := eventToEdit.Description
+	prompt = fmt.Sprintf("Enter new description (current: %s):", currentDesc)
+	description, ok := app.input.GetTextInputWithPrompt(prompt, 100, app.renderer)
+	if !ok {
+		return // User cancelled
+	}
+
+	// If user entered empty description, keep the current description
+	if description == "" {
+		description = currentDesc
+	}
+
+	// Update the event
+	err := app.events.EditEventWithDuration(*eventToEdit, selectedDate, timeStr, description, duration)
+	if err != nil {
+		app.showError(fmt.Sprintf("Error editing event: %v", err))
+	} else {
+		app.showMessage("Event edited successfully!" + app.overlapNote(selectedDate, timeStr, description))
+	}
+}
+
+// navigateEventUp moves selection up in the event list
+func (app *Application) navigateEventUp() {
+	selectedDate := app.navigation.GetCurrentSelection()
+	events := app.events.GetEventsForDate(selectedDate)
+
+	if len(events) > 0 && app.selectedEventIndex > 0 {
+		app.selectedEventIndex--
+	}
+}
+
+// navigateEventDown moves selection down in the event list
+func (app *Application) navigateEventDown() {
+	selectedDate := app.navigation.GetCurrentSelection()
+	events := app.events.GetEventsForDate(selectedDate)
+
+	if len(events) > 0 && app.selectedEventIndex < len(events)-1 {
+		app.selectedEventIndex++
+	}
+}
+
+// deleteSelectedDateEvent deletes the event on the selected date directly (dd chord).
+// With several events there is no single event to delete, so the event selection
+// used by D is entered instead.
+func (app *Application) deleteSelectedDateEvent() {
+	selectedDate := app.navigation.GetCurrentSelection()
+	events := app.events.GetEventsForDate(selectedDate)
+
+	switch len(events) {
+	case 0:
+		app.showError("No events to delete on this date")
+	case 1:
+		app.selectedEventIndex = 0
+		app.processDeleteEventFromList()
+	default:
+		app.state = StateCalendarEventSelection
+		app.selectedEventIndex = 0
+	}
+}
+
+// processDeleteEventFromList deletes the currently selected event from the events list
+func (app *Application) processDeleteEventFromList() {
+	selectedDate := app.navigation.GetCurrentSelection()
+	events := app.events.GetEventsForDate(selectedDate)
+
+	if len(events) == 0 {
+		app.showError("No events to delete on this date")
+		return
+	}
 
-	case terminal.ActionEditEvent:
-		app.processEditEventFromList()
+	if app.selectedEventIndex >= len(events) {
+		app.selectedEventIndex = len(events) - 1
 	}
 
-	return false
+	event := events[app.selectedEventIndex]
+	if app.confirmDelete("Delete event", event) {
+		err := app.deleteEvent(event)
+		if err != nil {
+			app.showError(fmt.Sprintf("Error deleting event: %v", err))
+		} else {
+			app.showMessage("Event deleted successfully!")
+			// Adjust selection if we deleted the last event
+			if app.selectedEventIndex >= len(events)-1 && app.selectedEventIndex > 0 {
+				app.selectedEventIndex--
+			}
+		}
+	}
 }
 
-// handleAddEventAction handles actions when adding events
-func (app *Application) handleAddEventAction(action terminal.KeyAction) bool {
-	switch action {
-	case terminal.ActionQuit:
-		return app.confirmExit() // Exit application with confirmation
+// processEditEventFromList edits the currently selected event from the events list using inline input
+func (app *Application) processEditEventFromList() {
+	selectedDate := app.navigation.GetCurrentSelection()
+	events := app.events.GetEventsForDate(selectedDate)
 
-	case terminal.ActionBack:
-		app.state = StateCalendar
+	if len(events) == 0 {
+		app.showError("No events to edit on this date")
+		return
 	}
 
-	// For adding events, we handle the input differently
-	app.processAddEvent()
-	app.state = StateCalendar
+	if app.selectedEventIndex >= len(events) {
+		app.selectedEventIndex = len(events) - 1
+	}
 
-	return false
+	eventToEdit := events[app.selectedEventIndex]
+
+	// Calculate coordinates for inline input on the selected (possibly scrolled) event
+	editEventY := app.renderer.EventListRowY(events, app.selectedEventIndex)
+	eventsLeftX := 2 // Use left margin like the event list
+
+	// Edit the event; the form stays open until it is saved or cancelled
+	if note, saved := app.editEventInline(eventToEdit, selectedDate, eventsLeftX, editEventY); saved {
+		app.showMessage("Event edited successfully!" + note)
+	}
 }
 
-// renderCurrentView renders the appropriate view based on current state
-func (app *Application) renderCurrentView() error {
-	switch app.state {
-	case StateCalendar:
-		return app.renderer.RenderCalendar(app.calendar, app.selection)
+// Fields of the inline edit form
+const (
+	editFieldTime = iota
+	editFieldDescription
+	editFieldDate
+)
 
-	case StateCalendarEventSelection:
-		// Render calendar with event selection highlighting
-		return app.renderer.RenderCalendarWithEventSelection(app.calendar, app.selection, app.selectedEventIndex)
+// editEventInline runs the inline edit form for an event at x, y and reports whether
+// the event was saved, with a note on where it moved and the events it overlaps then.
+// The last field moves the event to another day; left empty the event stays on date.
+// When saving fails the form stays open with the entered values, starting again at the
+// offending field, which is highlighted with the error below it; Esc cancels the edit.
+func (app *Application) editEventInline(eventToEdit models.Event, date time.Time, x, y int) (string, bool) {
+	defer app.renderer.SetInputError("")
+
+	timeStr := editTimeDefault(eventToEdit)
+	description := eventToEdit.Description
+	dateInput := ""
+	field, message := editFieldTime, ""
+	for {
+		if field == editFieldTime {
+			app.renderer.SetInputError(message)
+			input, ok := app.input.GetInlineTimeInputWithDefault(x, y, "Time:", timeStr, app.renderer)
+			if !ok {
+				return "", false // User cancelled
+			}
+			// If user entered empty time, keep the current time
+			if input != "" {
+				timeStr = input
+			}
+			message = ""
+		}
 
-	case StateCalendarEventAdd:
-		// Render calendar with event add highlighting
-		return app.renderer.RenderCalendarWithEventAdd(app.calendar, app.selection)
+		if field <= editFieldDescription {
+			app.renderer.SetInputError(message)
+			input, ok := app.input.GetInlineTextInputWithDefault(x, y, "Description:", 100, description, app.renderer)
+			if !ok {
+				return "", false // User cancelled
+			}
+			// If user entered empty description, keep the current description
+			if input != "" {
+				description = input
+			}
+			message = ""
+		}
 
-	case StateCalendarEventEdit:
-		// Render calendar with event edit highlighting
-		return app.renderer.RenderCalendarWithEventEdit(app.calendar, app.selection, app.selectedEventIndex)
+		app.renderer.SetInputError(message)
+		input, ok := app.input.GetInlineDateInput(x, y, "Move to date (empty = keep, Tab: complete/pick):", dateInput, date, app.renderer, app.renderCurrentView)
+		if !ok {
+			return "", false // User cancelled
+		}
+		dateInput = input
+		newDate, err := calendar.ParseRelativeDate(dateInput, date)
+		if err != nil {
+			field, message = editFieldDate, fmt.Sprintf("Invalid date: %v", err)
+			continue
+		}
 
-	case StateSearch:
-		// Render calendar with search results
-		return app.renderer.RenderCalendarWithSearch(app.calendar, app.selection, app.searchQuery, app.searchResults, app.searchResultDates, app.selectedResultIndex)
+		field, message = app.editFieldError(timeStr, description)
+		if message == "" {
+			start, duration := editedTimeRange(eventToEdit, timeStr)
+			err := app.events.EditEventWithDuration(eventToEdit, newDate, start, description, duration)
+			if err == nil {
+				note := app.overlapNote(newDate, start, description)
+				if !calendar.IsSameDate(newDate, date) {
+					note = fmt.Sprintf(" Moved to %s.", app.formatDate(newDate)) + note
+				}
+				return note, true
+			}
+			// Storage errors are not about a field; retry from the last one
+			field, message = editFieldDate, fmt.Sprintf("Error editing event: %v (Enter: retry, Esc: cancel)", err)
+		}
+	}
+}
 
-	case StateEventList:
-		selectedDate := app.navigation.GetCurrentSelection()
-		eventList := app.events.GetEventsForDate(selectedDate)
-		return app.renderer.RenderEventList(selectedDate, eventList, app.selectedEventIndex)
+// moveEventToDate asks at x, y for the date to move an event to, as a date expression
+// relative to date or picked from a month grid with Tab, and moves it there keeping its
+// time and length
+func (app *Application) moveEventToDate(event models.Event, date time.Time, x, y int) {
+	defer app.renderer.SetInputError("")
 
-	case StateAddEvent:
-		// This state is handled differently - we don't render here
-		// but in processAddEvent()
-		return nil
+	input := ""
+	for {
+		var ok bool
+		input, ok = app.input.GetInlineDateInput(x, y, "Move to date (Tab: complete/pick):", input, date, app.renderer, app.renderCurrentView)
+		if !ok {
+			return // User cancelled
+		}
+		newDate, err := calendar.ParseRelativeDate(input, date)
+		if err != nil {
+			app.renderer.SetInputError(fmt.Sprintf("Invalid date: %v", err))
+			continue
+		}
+		if calendar.IsSameDate(newDate, date) {
+			return // Nothing to move
+		}
+
+		moved, err := app.events.MoveEvent(event, calendar.DaysBetween(date, newDate))
+		if err != nil {
+			app.showError(fmt.Sprintf("Error moving event: %v", err))
+			return
+		}
+		app.showMessage(fmt.Sprintf("Moved %q to %s (U: undo)", moved.Description, app.formatDate(newDate)) +
+			app.overlapNote(moved.Date, moved.GetTimeString(), moved.Description))
+		return
 	}
+}
 
-	return nil
+// editFieldError checks the values of the edit form and returns the first rejected
+// field with the reason, or an empty message when both are valid
+func (app *Application) editFieldError(timeStr, description string) (int, string) {
+	if _, _, err := calendar.ParseTimeRange(timeStr); err != nil {
+		return editFieldTime, fmt.Sprintf("Invalid time %q: expected HH:MM or HH:MM-HH:MM", timeStr)
+	}
+	if strings.TrimSpace(app.events.ApplyNormalization(description)) == "" {
+		return editFieldDescription, "The description is empty after normalization"
+	}
+	return editFieldDescription, ""
 }
 
-// processAddEvent handles the event addition workflow
-func (app *Application) processAddEvent() {
+// processAddEventFromEventsList handles adding an event from the events view with inline input
+func (app *Application) processAddEventFromEventsList() {
 	selectedDate := app.navigation.GetCurrentSelection()
+	app.showDayNote(selectedDate)
 
-	// Get time input with validation
-	timeStr, ok := app.input.GetTimeInput("Enter time (HH:MM):", app.renderer)
+	// Calculate coordinates for inline input in events view
+	// Events view has title at Y=2, separator at Y=4, events start at Y=6
+	// We want to add the new event at the bottom of the existing events list
+	events := app.events.GetEventsForDate(selectedDate)
+	startY := 6
+	addEventY := startY + len(events) // Position after existing events
+
+	// Use left margin like the event list (X=2)
+	eventsLeftX := 2
+
+	// Get time input using inline input with validation
+	input, ok := app.input.GetInlineTimeInput(eventsLeftX, addEventY, "Time:", app.renderer)
 	if !ok {
-		return // User cancelled
+		// User cancelled
+		return
 	}
+	timeStr, duration := splitTimeRange(input)
 
-	// Get description input
-	description, ok := app.input.GetTextInputWithPrompt("Enter description:", 100, app.renderer)
+	// Get description input using inline input
+	description, ok := app.input.GetInlineTextInput(eventsLeftX, addEventY, "Description:", 100, app.renderer)
 	if !ok {
-		return // User cancelled
+		// User cancelled
+		return
+	}
+
+	// Pick the calendar when there are several
+	if !app.pickCalendar(eventsLeftX, addEventY) {
+		// User cancelled
+		return
+	}
+
+	// Get optional date input (defaults to the selected date)
+	eventDate, ok := app.promptEventDate(eventsLeftX, addEventY, selectedDate, input, description)
+	if !ok {
+		// User cancelled
+		return
 	}
 
 	// Add the event
-	err := app.events.AddEvent(selectedDate, timeStr, description)
+	added, err := app.addEvent(eventDate, timeStr, description, duration)
 	if err != nil {
 		app.showError(fmt.Sprintf("Error adding event: %v", err))
-	} else {
-		app.showMessage("Event added successfully!")
+	} else if added && !calendar.IsSameDate(eventDate, selectedDate) {
+		app.showMessage(fmt.Sprintf("Event added for %s", app.formatDate(eventDate)) + app.overlapNote(eventDate, timeStr, description))
+	} else if added {
+		app.showMessage("Event added successfully!" + app.overlapNote(eventDate, timeStr, description))
+
+		// After adding the event, select and highlight the newly added event
+		// Get the updated events list
+		updatedEvents := app.events.GetEventsForDate(selectedDate)
+
+		// Find the newly added event (it should be the one with matching time and description)
+		storedDescription := app.events.ApplyNormalization(description)
+		for i, event := range updatedEvents {
+			if event.GetTimeString() == timeStr && event.Description == storedDescription {
+				app.selectedEventIndex = i
+				break
+			}
+		}
+
+		// If we couldn't find it (unlikely), select the last event
+		if app.selectedEventIndex >= len(updatedEvents) {
+			app.selectedEventIndex = len(updatedEvents) - 1
+		}
 	}
 }
 
-// processDeleteEvent handles the event deletion workflow
-func (app *Application) processDeleteEvent() {
+// processAddEventFromCalendar handles adding an event from the calendar view with inline input
+func (app *Application) processAddEventFromCalendar() {
 	selectedDate := app.navigation.GetCurrentSelection()
-	events := app.events.GetEventsForDate(selectedDate)
+	app.showDayNote(selectedDate)
 
-	if len(events) == 0 {
-		app.showError("No events to delete on this date")
+	// A marked range becomes the days of the event
+	rangeStart, rangeEnd := app.selection.Range()
+	multiDay := !rangeStart.Equal(rangeEnd)
+	app.selection.RangeStart = time.Time{}
+
+	// Calculate coordinates for inline input (same as renderSelectedDateEventsWithAddMode)
+	eventsLeftX := app.renderer.EventsLeftX()
+
+	// The new event row follows the visible existing events
+	addEventY := app.renderer.NewEventRowY(selectedDate)
+
+	// Get time input using inline input with validation; no time makes an all-day event
+	input, ok := app.input.GetInlineEventTimeInput(eventsLeftX, addEventY, "Time (Enter: all day):", app.renderer)
+	if !ok {
+		// User cancelled, return to calendar
+		app.state = StateCalendar
+		app.selectedEventIndex = 0
 		return
 	}
+	allDay := input == ""
+	timeStr, duration := splitTimeRange(input)
 
-	if len(events) == 1 {
-		// Only one event, delete it directly after confirmation
-		event := events[0]
-		confirmMsg := fmt.Sprintf("Delete event: %s - %s? (Enter: confirm, Esc: cancel)", event.GetTimeString(), event.Description)
+	// Get description input using inline input
+	description, ok := app.input.GetInlineTextInput(eventsLeftX, addEventY, "Description:", 100, app.renderer)
+	if !ok {
+		// User cancelled, return to calendar
+		app.state = StateCalendar
+		app.selectedEventIndex = 0
+		return
+	}
 
-		if app.confirmAction(confirmMsg) {
-			err := app.events.DeleteEvent(event)
-			if err != nil {
-				app.showError(fmt.Sprintf("Error deleting event: %v", err))
-			} else {
-				app.showMessage("Event deleted successfully!")
-			}
+	// Pick the calendar when there are several
+	if !app.pickCalendar(eventsLeftX, addEventY) {
+		// User cancelled, return to calendar
+		app.state = StateCalendar
+		app.selectedEventIndex = 0
+		return
+	}
+
+	if multiDay {
+		if err := app.events.AddMultiDayEvent(rangeStart, rangeEnd, timeStr, description, allDay); err != nil {
+			app.showError(fmt.Sprintf("Error adding event: %v", err))
+		} else {
+			app.showMessage(fmt.Sprintf("Event added for %s - %s", app.formatDate(rangeStart), app.formatDate(rangeEnd)))
 		}
+		app.state = StateCalendar
+		app.selectedEventIndex = 0
 		return
 	}
 
-	// Multiple events - let user select which one to delete
-	selectedEvent := app.selectEventFromList(events, "Select event to delete:")
-	if selectedEvent != nil {
-		confirmMsg := fmt.Sprintf("Delete event: %s - %s? (Enter: confirm, Esc: cancel)", selectedEvent.GetTimeString(), selectedEvent.Description)
+	// Get optional date input (defaults to the selected date)
+	timeLabel := input
+	if allDay {
+		timeLabel = "all day"
+	}
+	eventDate, ok := app.promptEventDate(eventsLeftX, addEventY, selectedDate, timeLabel, description)
+	if !ok {
+		// User cancelled, return to calendar
+		app.state = StateCalendar
+		app.selectedEventIndex = 0
+		return
+	}
 
-		if app.confirmAction(confirmMsg) {
-			err := app.events.DeleteEvent(*selectedEvent)
-			if err != nil {
-				app.showError(fmt.Sprintf("Error deleting event: %v", err))
-			} else {
-				app.showMessage("Event deleted successfully!")
-			}
+	// Add the event
+	var added bool
+	var err error
+	if allDay {
+		added, err = true, app.events.AddMultiDayEvent(eventDate, eventDate, "", description, true)
+	} else {
+		added, err = app.addEvent(eventDate, timeStr, description, duration)
+	}
+	if err != nil {
+		app.showError(fmt.Sprintf("Error adding event: %v", err))
+	} else if added && !calendar.IsSameDate(eventDate, selectedDate) {
+		app.showMessage(fmt.Sprintf("Event added for %s", app.formatDate(eventDate)) + app.overlapNote(eventDate, timeStr, description))
+	} else if added {
+		app.showMessage("Event added successfully!" + app.overlapNote(eventDate, timeStr, description))
+	}
+
+	// Return to calendar view
+	app.state = StateCalendar
+	app.selectedEventIndex = 0
+}
+
+// pickCalendar asks which calendar a new event goes to when further calendars are
+// configured, offering the one picked last; other add flows keep adding to that one.
+// It reports false when cancelled.
+func (app *Application) pickCalendar(x, y int) bool {
+	if app.config == nil || len(app.config.Calendars) == 0 {
+		return true
+	}
+	names := app.config.CalendarNames()
+	if len(names) < 2 {
+		return true
+	}
+
+	current := names[0]
+	if key := app.events.AddCalendar(); key != "" {
+		current = key
+	}
+	for {
+		input, ok := app.input.GetInlineChoiceInput(x, y, "Calendar (Tab: next):", names, current, app.renderer)
+		if !ok {
+			return false
+		}
+		if input == "" {
+			input = current
+		}
+		key, found := app.config.CalendarKey(input)
+		if !found {
+			app.showError(fmt.Sprintf("No calendar %q: expected one of %s", input, strings.Join(names, ", ")))
+			continue
 		}
+		app.events.SetAddCalendar(key)
+		return true
 	}
 }
 
-// processEditEvent handles the event editing workflow
-func (app *Application) processEditEvent() {
+// processGoToDate asks for a date expression such as "next fri", "eom" or "2w" over the
+// events header and selects the date it resolves to from the selected date; the theme
+// command is taken there as well
+func (app *Application) processGoToDate() {
 	selectedDate := app.navigation.GetCurrentSelection()
-	events := app.events.GetEventsForDate(selectedDate)
+	input := ""
+	for {
+		var ok bool
+		input, ok = app.input.GetInlineDateInput(app.renderer.EventsLeftX(), app.renderer.EventsHeaderY(),
+			"Go to (Tab: complete/pick):", input, selectedDate, app.renderer, app.renderCurrentView)
+		if !ok {
+			return
+		}
 
-	if len(events) == 0 {
-		app.showError("No events to edit on this date")
+		// ":theme" cycles the color themes and ":theme dark" picks one
+		if fields := strings.Fields(input); len(fields) > 0 && strings.EqualFold(fields[0], "theme") {
+			if len(fields) == 1 {
+				app.cycleTheme()
+			} else {
+				app.switchTheme(fields[1])
+			}
+			return
+		}
+
+		date, err := calendar.ParseRelativeDate(input, selectedDate)
+		if err != nil {
+			app.showError(fmt.Sprintf("Invalid date: %v", err))
+			continue
+		}
+		app.navigation.JumpToDate(date)
 		return
 	}
+}
 
-	var eventToEdit *models.Event
-	if len(events) == 1 {
-		// Only one event, edit it directly
-		eventToEdit = &events[0]
-	} else {
-		// Multiple events - let user select which one to edit
-		eventToEdit = app.selectEventFromList(events, "Select event to edit:")
-		if eventToEdit == nil {
-			return // User cancelled selection
+// promptEventDate asks for an optional date expression such as "tomorrow", "fri" or "+10d".
+// An empty answer keeps the selected date; any other date is previewed and must be confirmed.
+func (app *Application) promptEventDate(x, y int, selectedDate time.Time, timeStr, description string) (time.Time, bool) {
+	input := ""
+	for {
+		var ok bool
+		input, ok = app.input.GetInlineDateInput(x, y, "Date (empty = selected, Tab: complete/pick):", input, selectedDate, app.renderer, app.renderCurrentView)
+		if !ok {
+			return time.Time{}, false
+		}
+
+		eventDate, err := calendar.ParseRelativeDate(input, selectedDate)
+		if err != nil {
+			app.showError(fmt.Sprintf("Invalid date: %v", err))
+			continue
 		}
-	}
 
-	// Get new time input with validation (default to current time)
-	currentTime := eventToEdit.GetTimeString()
-	prompt := fmt.Sprintf("Enter new time (current: %s):", currentTime)
-	timeStr, ok := app.input.GetTimeInput(prompt, app.renderer)
-	if !ok {
-		return // User cancelled
+		if calendar.IsSameDate(eventDate, selectedDate) {
+			return eventDate, true
+		}
+
+		// Preview the resolved date before confirming, naming a holiday or weekend
+		day := eventDate.Format("Mon 2006-01-02")
+		if note := app.dayNote(eventDate); note != "" {
+			day = fmt.Sprintf("%s (%s)", day, note)
+		}
+		confirmMsg := fmt.Sprintf("Add %s - %s on %s? (Enter: confirm, Esc: edit date)",
+			timeStr, description, day)
+		if app.confirmAction(confirmMsg) {
+			return eventDate, true
+		}
 	}
+}
 
-	// If user entered empty time, keep the current time
-	if timeStr == "" {
-		timeStr = currentTime
+// dayNote names the holiday or weekend day a date falls on, or returns "" for a regular day
+func (app *Application) dayNote(date time.Time) string {
+	if app.config == nil {
+		return ""
+	}
+	if names := app.holidays.Names(date); len(names) > 0 {
+		return strings.Join(names, " and ")
 	}
+	if app.config.WeekendNotes && (date.Weekday() == time.Saturday || date.Weekday() == time.Sunday) {
+		return date.Weekday().String()
+	}
+	return ""
+}
 
-	// Get new description input (default to current description)
-	currentDesc := eventToEdit.Description
-	prompt = fmt.Sprintf("Enter new description (current: %s):", currentDesc)
-	description, ok := app.input.GetTextInputWithPrompt(prompt, 100, app.renderer)
-	if !ok {
-		return // User cancelled
+// showDayNote shows an informational note when adding an event on a holiday or weekend
+func (app *Application) showDayNote(date time.Time) {
+	note := app.dayNote(date)
+	if note == "" {
+		return
+	}
+	if len(app.holidays.Names(date)) > 0 {
+		app.showMessage(fmt.Sprintf("This is %s", note))
+	} else {
+		app.showMessage(fmt.Sprintf("This is a %s", note))
 	}
+}
 
-	// If user entered empty description, keep the current description
-	if description == "" {
-		description = currentDesc
+// navigateCalendarEventUp moves selection up in the calendar events list
+func (app *Application) navigateCalendarEventUp() {
+	selectedDate := app.navigation.GetCurrentSelection()
+	events := app.events.GetEventsForDate(selectedDate)
+
+	if len(events) > 0 && app.selectedEventIndex > 0 {
+		app.selectedEventIndex--
 	}
+}
 
-	// Update the event
-	err := app.events.EditEvent(*eventToEdit, selectedDate, timeStr, description)
-	if err != nil {
-		app.showError(fmt.Sprintf("Error editing event: %v", err))
-	} else {
-		app.showMessage("Event edited successfully!")
+// navigateCalendarEventDown moves selection down in the calendar events list
+func (app *Application) navigateCalendarEventDown() {
+	selectedDate := app.navigation.GetCurrentSelection()
+	events := app.events.GetEventsForDate(selectedDate)
+
+	if len(events) > 0 && app.selectedEventIndex < len(events)-1 {
+		app.selectedEventIndex++
 	}
 }
 
-// navigateEventUp moves selection up in the event list
-func (app *Application) navigateEventUp() {
+// navigateCalendarEventEditUp moves selection up in the calendar events list for editing
+func (app *Application) navigateCalendarEventEditUp() {
 	selectedDate := app.navigation.GetCurrentSelection()
 	events := app.events.GetEventsForDate(selectedDate)
 
@@ -533,8 +3323,8 @@ func (app *Application) navigateEventUp() {
 	}
 }
 
-// navigateEventDown moves selection down in the event list
-func (app *Application) navigateEventDown() {
+// navigateCalendarEventEditDown moves selection down in the calendar events list for editing
+func (app *Application) navigateCalendarEventEditDown() {
 	selectedDate := app.navigation.GetCurrentSelection()
 	events := app.events.GetEventsForDate(selectedDate)
 
@@ -543,13 +3333,15 @@ func (app *Application) navigateEventDown() {
 	}
 }
 
-// processDeleteEventFromList deletes the currently selected event from the events list
-func (app *Application) processDeleteEventFromList() {
+// processDeleteSelectedCalendarEvent deletes the currently selected event in calendar view
+func (app *Application) processDeleteSelectedCalendarEvent() {
 	selectedDate := app.navigation.GetCurrentSelection()
 	events := app.events.GetEventsForDate(selectedDate)
 
 	if len(events) == 0 {
-		app.showError("No events to delete on this date")
+		// No events to delete, exit selection mode
+		app.state = StateCalendar
+		app.selectedEventIndex = 0
 		return
 	}
 
@@ -558,10 +3350,8 @@ func (app *Application) processDeleteEventFromList() {
 	}
 
 	event := events[app.selectedEventIndex]
-	confirmMsg := fmt.Sprintf("Delete event: %s - %s? (Enter: confirm, Esc: cancel)", event.GetTimeString(), event.Description)
-
-	if app.confirmAction(confirmMsg) {
-		err := app.events.DeleteEvent(event)
+	if app.confirmDelete("Delete event", event) {
+		err := app.deleteEvent(event)
 		if err != nil {
 			app.showError(fmt.Sprintf("Error deleting event: %v", err))
 		} else {
@@ -572,15 +3362,21 @@ func (app *Application) processDeleteEventFromList() {
 			}
 		}
 	}
+
+	// Return to calendar navigation after deletion attempt
+	app.state = StateCalendar
+	app.selectedEventIndex = 0
 }
 
-// processEditEventFromList edits the currently selected event from the events list using inline input
-func (app *Application) processEditEventFromList() {
+// processEditSelectedCalendarEvent edits the currently selected event in calendar view
+func (app *Application) processEditSelectedCalendarEvent() {
 	selectedDate := app.navigation.GetCurrentSelection()
 	events := app.events.GetEventsForDate(selectedDate)
 
 	if len(events) == 0 {
-		app.showError("No events to edit on this date")
+		// No events to edit, exit edit mode
+		app.state = StateCalendar
+		app.selectedEventIndex = 0
 		return
 	}
 
@@ -590,468 +3386,1231 @@ func (app *Application) processEditEventFromList() {
 
 	eventToEdit := events[app.selectedEventIndex]
 
-	// Calculate coordinates for inline input on the selected event
-	// Events view has title at Y=2, separator at Y=4, events start at Y=6
-	startY := 6
-	editEventY := startY + app.selectedEventIndex // Position of the selected event
-	eventsLeftX := 2                              // Use left margin like the event list
+	// Calculate coordinates for inline input (same as add mode)
+	eventsLeftX := app.renderer.EventsLeftX()
+
+	// Calculate Y position for the selected event (the panel scrolls to keep it visible)
+	editEventY := app.renderer.CalendarEventRowY(selectedDate, app.selectedEventIndex)
+
+	// Edit the event; the form stays open until it is saved or cancelled
+	if note, saved := app.editEventInline(eventToEdit, selectedDate, eventsLeftX, editEventY); saved {
+		app.showMessage("Event edited successfully!" + note)
+	}
+
+	// Return to calendar view
+	app.state = StateCalendar
+	app.selectedEventIndex = 0
+}
+
+// processMoveSelectedCalendarEvent moves the event selected in edit mode to a date
+// typed or picked on its row, then returns to calendar navigation
+func (app *Application) processMoveSelectedCalendarEvent() {
+	selectedDate := app.navigation.GetCurrentSelection()
+	events := app.events.GetEventsForDate(selectedDate)
+	if len(events) == 0 {
+		app.state = StateCalendar
+		app.selectedEventIndex = 0
+		return
+	}
+	if app.selectedEventIndex >= len(events) {
+		app.selectedEventIndex = len(events) - 1
+	}
+
+	eventY := app.renderer.CalendarEventRowY(selectedDate, app.selectedEventIndex)
+	app.moveEventToDate(events[app.selectedEventIndex], selectedDate, app.renderer.EventsLeftX(), eventY)
+
+	app.state = StateCalendar
+	app.selectedEventIndex = 0
+}
+
+// showError displays an error message
+func (app *Application) showError(message string) {
+	app.postMessage(message, messages.Error)
+}
+
+// showWarning displays a warning
+func (app *Application) showWarning(message string) {
+	app.postMessage(message, messages.Warning)
+}
+
+// showMessage displays a success message
+func (app *Application) showMessage(message string) {
+	app.postMessage(message, messages.Success)
+}
+
+// postMessage queues a message for the status line, where it stays across redraws until
+// its time is up, and shows it straight away unless an earlier one is still on screen
+func (app *Application) postMessage(message string, severity messages.Severity) {
+	app.messages.Post(message, severity, time.Now())
+	if app.renderPostedMessage() {
+		app.terminal.Flush()
+	}
+}
+
+// renderPostedMessage draws the current status line message over the view, reporting
+// whether there was one, and wakes the event loop to redraw when it expires
+func (app *Application) renderPostedMessage() bool {
+	now := time.Now()
+	if app.messageTick != nil {
+		app.messageTick.Stop()
+	}
+	if until, ok := app.messages.NextChange(now); ok {
+		app.messageTick = time.AfterFunc(until.Sub(now), app.terminal.Interrupt)
+	}
+
+	message, ok := app.messages.Current(now)
+	if !ok || app.state == StateScreensaver {
+		return false
+	}
+	app.renderer.RenderPostedMessage(message)
+	return true
+}
+
+// confirmAction prompts the user for confirmation (Enter/Esc)
+func (app *Application) confirmAction(message string) bool {
+	// Display the confirmation message
+	app.renderer.RenderMessage(message, false)
+	app.terminal.Flush()
+
+	// Wait for a key; timers waking the event loop, such as the sync ticker or a
+	// chord timeout, must not answer the question
+	event := app.input.WaitForKey()
+	for event.Type != termbox.EventKey {
+		event = app.input.WaitForKey()
+	}
+
+	// Check for Enter key (confirm) or Esc key (cancel)
+	if event.Key == termbox.KeyEnter {
+		return true
+	}
+
+	return false // Any other key (including Esc) cancels
+}
+
+// deleteTypedDays is how many days an event may span before deleting it takes typing
+// "yes" rather than pressing Enter
+const deleteTypedDays = 7
+
+// deleteSummary describes what deleting event removes, after prefix: the event, the
+// days it spans and its notes, e.g. "Delete event: all day - Trip, 5 days from Aug 20
+// to Aug 24, with 2 lines of notes"
+func deleteSummary(prefix string, event models.Event) string {
+	summary := fmt.Sprintf("%s: %s - %s", prefix, event.GetTimeLabel(), event.Description)
+	if days := eventDays(event); days > 1 {
+		summary += fmt.Sprintf(", %d days from %s to %s", days, event.Date.Format("Jan 2"), event.LastDate().Format("Jan 2"))
+	}
+	if event.Notes != "" {
+		if lines := strings.Count(event.Notes, "\n") + 1; lines > 1 {
+			summary += fmt.Sprintf(", with %d lines of notes", lines)
+		} else {
+			summary += ", with its notes"
+		}
+	}
+	return summary
+}
+
+// eventDays returns the number of days event takes
+func eventDays(event models.Event) int {
+	return calendar.DaysBetween(event.Date, event.LastDate()) + 1
+}
+
+// confirmDelete asks whether to delete event, naming everything the deletion removes.
+// Deleting an event spanning more than deleteTypedDays days takes typing "yes".
+func (app *Application) confirmDelete(prefix string, event models.Event) bool {
+	summary := deleteSummary(prefix, event)
+	if eventDays(event) <= deleteTypedDays {
+		return app.confirmAction(summary + "? (Enter: confirm, Esc: cancel)")
+	}
 
-	// Get new time input with current value as default using inline input with validation
-	currentTime := eventToEdit.GetTimeString()
-	timeStr, ok := app.input.GetInlineTimeInputWithDefault(eventsLeftX, editEventY, "Time:", currentTime, app.renderer)
+	answer, ok := app.input.GetTextInputWithPrompt(summary+" - type yes to delete:", 3, app.renderer)
 	if !ok {
-		return // User cancelled
+		return false
+	}
+	if !strings.EqualFold(strings.TrimSpace(answer), "yes") {
+		app.showMessage("Nothing deleted")
+		return false
 	}
+	return true
+}
 
-	// If user entered empty time, keep the current time
-	if timeStr == "" {
-		timeStr = currentTime
+// confirmExit exits straight away when nothing would be lost, and otherwise asks for
+// confirmation with a message naming what exiting would lose
+func (app *Application) confirmExit() bool {
+	warning := app.exitWarning()
+	if warning == "" {
+		return true
+	}
+	return app.confirmAction(warning + " (Enter: exit, Esc: cancel)")
+}
+
+// exitWarning describes what exiting now would lose: an event being added or edited,
+// changes kept in memory by dry-run or ephemeral mode or by a failed write, or changes
+// whose last sync push failed. It is empty when the session is clean.
+func (app *Application) exitWarning() string {
+	switch app.state {
+	case StateCalendarEventAdd:
+		return "Discard the new event and exit?"
+	case StateCalendarEventEdit:
+		return "Discard the event being edited and exit?"
+	case StatePaste:
+		return "Discard the pasted events and exit?"
 	}
 
-	// Get new description input with current value as default using inline input
-	currentDesc := eventToEdit.Description
-	description, ok := app.input.GetInlineTextInputWithDefault(eventsLeftX, editEventY, "Description:", 100, currentDesc, app.renderer)
+	if app.events.ReadOnly() {
+		if len(app.events.DryRunDiff()) > 0 {
+			if app.events.Ephemeral() {
+				return "Ephemeral session: your changes will be discarded. Exit?"
+			}
+			return "Dry run: your changes will not be saved. Exit?"
+		}
+	}
+
+	if failure, ok := app.events.Unsaved(); ok {
+		return fmt.Sprintf("Events not saved since %s; exiting retries once more, then writes them to %s. Exit?", failure.Since.Format("15:04"), app.emergencyFilePath())
+	}
+
+	if status := app.sync.Status(); status.Operation == "push" && status.Err != nil {
+		return fmt.Sprintf("Last sync push failed at %s; it is retried on exit. Exit?", status.At.Format("15:04"))
+	}
+	return ""
+}
+
+// selectEventFromList allows the user to select an event from a list
+func (app *Application) selectEventFromList(events []models.Event, title string) *models.Event {
+	if len(events) == 0 {
+		return nil
+	}
+
+	// Simple implementation: show list with numbers and get user input
+	app.terminal.Clear()
+
+	fg, bg := app.terminal.GetDefaultColors()
+
+	// Display title
+	app.terminal.PrintCentered(2, title, termbox.AttrBold, bg)
+
+	// Display events with numbers
+	startY := 4
+	for i, event := range events {
+		eventText := fmt.Sprintf("%d. %s - %s", i+1, event.GetTimeString(), event.Description)
+		// Truncate if too long
+		if len(eventText) > 70 {
+			eventText = eventText[:67] + "..."
+		}
+		app.terminal.PrintCentered(startY+i, eventText, fg, bg)
+	}
+
+	// Instructions
+	instrY := startY + len(events) + 2
+	app.terminal.PrintCentered(instrY, "Enter event number (1-9) or Esc to cancel:", fg, bg)
+
+	app.terminal.Flush()
+
+	// Wait for user selection
+	for {
+		event := app.input.WaitForKey()
+
+		if event.Type != termbox.EventKey {
+			continue
+		}
+
+		// Handle Esc key
+		if event.Key == termbox.KeyEsc {
+			return nil
+		}
+
+		// Handle number keys
+		if event.Ch >= '1' && event.Ch <= '9' {
+			choice := int(event.Ch - '1') // Convert to 0-based index
+			if choice < len(events) {
+				return &events[choice]
+			}
+		}
+	}
+}
+
+// processBookmarkSelectedDate names the selected date; an empty name removes its bookmark
+func (app *Application) processBookmarkSelectedDate() {
+	selectedDate := app.navigation.GetCurrentSelection()
+
+	// Prompt inline in the events panel, pre-filled with the current name
+	eventsLeftX := app.renderer.EventsLeftX()
+	promptY := app.renderer.NewEventRowY(selectedDate)
+
+	current, bookmarked := app.bookmarks.BookmarkFor(selectedDate)
+	name, ok := app.input.GetInlineTextInputWithDefault(eventsLeftX, promptY, "Bookmark name (empty = remove):", 60, current.Name, app.renderer)
 	if !ok {
 		return // User cancelled
 	}
 
-	// If user entered empty description, keep the current description
-	if description == "" {
-		description = currentDesc
+	if strings.TrimSpace(name) == "" {
+		if bookmarked {
+			if err := app.bookmarks.RemoveBookmark(selectedDate); err != nil {
+				app.showError(fmt.Sprintf("Error removing bookmark: %v", err))
+			}
+		}
+		return
+	}
+
+	if err := app.bookmarks.SetBookmark(selectedDate, name); err != nil {
+		app.showError(fmt.Sprintf("Error saving bookmark: %v", err))
+	}
+}
+
+// cycleTheme switches to the next of the predefined themes and the custom one
+func (app *Application) cycleTheme() {
+	choices := config.ThemeNames
+	if app.config != nil {
+		choices = app.config.ThemeChoices()
+	}
+	next := 0
+	for i, name := range choices {
+		if name == app.themeName {
+			next = (i + 1) % len(choices)
+		}
+	}
+	app.switchTheme(choices[next])
+}
+
+// switchTheme switches the renderer to a theme by name and saves the choice to the
+// configuration file, unless the session must not write it
+func (app *Application) switchTheme(name string) {
+	name = strings.ToLower(name)
+	theme, err := config.GetThemeByName(name)
+	if app.config != nil {
+		theme, err = app.config.ThemeByName(name)
+	}
+	if err != nil {
+		choices := config.ThemeNames
+		if app.config != nil {
+			choices = app.config.ThemeChoices()
+		}
+		app.showError(fmt.Sprintf("Unknown theme %q: expected one of %s", name, strings.Join(choices, ", ")))
+		return
+	}
+
+	app.themeName = name
+	app.renderer.SetTheme(theme)
+	if app.config == nil || app.config.Ephemeral || app.config.DryRun || app.config.SafeMode {
+		app.showMessage(fmt.Sprintf("Theme: %s (this session only)", name))
+		return
+	}
+	if err := app.config.SaveTheme(name); err != nil {
+		app.showError(fmt.Sprintf("Theme: %s, but it was not saved: %v", name, err))
+		return
+	}
+	app.showMessage(fmt.Sprintf("Theme: %s", name))
+}
+
+// isEventSelectionState reports whether the current view has a selected event
+func (app *Application) isEventSelectionState() bool {
+	switch app.state {
+	case StateCalendarEventSelection, StateCalendarEventEdit, StateEventList:
+		return true
 	}
+	return false
+}
 
-	// Update the event
-	err := app.events.EditEvent(eventToEdit, selectedDate, timeStr, description)
-	if err != nil {
-		app.showError(fmt.Sprintf("Error editing event: %v", err))
-	} else {
-		app.showMessage("Event edited successfully!")
+// keyView returns the screen of the current state, for the letters that mean something
+// else there than in the calendar
+func (app *Application) keyView() terminal.KeyView {
+	switch app.state {
+	case StateEventList, StateBookmarks, StateReview:
+		return terminal.ViewList
+	case StateActivityLog:
+		return terminal.ViewActivityLog
+	case StateCalendars:
+		return terminal.ViewCalendars
 	}
+	return terminal.ViewCalendar
 }
 
-// processAddEventFromEventsList handles adding an event from the events view with inline input
-func (app *Application) processAddEventFromEventsList() {
-	selectedDate := app.navigation.GetCurrentSelection()
+// assignCategoryHotkey assigns the category bound to digit to the selected event.
+// Pressing 0, or the hotkey of the event's current category, clears the category.
+func (app *Application) assignCategoryHotkey(digit rune) {
+	if app.config == nil {
+		return
+	}
 
-	// Calculate coordinates for inline input in events view
-	// Events view has title at Y=2, separator at Y=4, events start at Y=6
-	// We want to add the new event at the bottom of the existing events list
+	selectedDate := app.navigation.GetCurrentSelection()
 	events := app.events.GetEventsForDate(selectedDate)
-	startY := 6
-	addEventY := startY + len(events) // Position after existing events
-
-	// Use left margin like the event list (X=2)
-	eventsLeftX := 2
-
-	// Get time input using inline input with validation
-	timeStr, ok := app.input.GetInlineTimeInput(eventsLeftX, addEventY, "Time:", app.renderer)
-	if !ok {
-		// User cancelled
+	if len(events) == 0 || app.selectedEventIndex >= len(events) {
 		return
 	}
+	event := events[app.selectedEventIndex]
 
-	// Get description input using inline input
-	description, ok := app.input.GetInlineTextInput(eventsLeftX, addEventY, "Description:", 100, app.renderer)
-	if !ok {
-		// User cancelled
-		return
+	category := ""
+	if digit != '0' {
+		assigned, ok := app.config.GetCategoryByHotkey(string(digit))
+		if !ok {
+			app.showError(fmt.Sprintf("No category bound to key %c", digit))
+			return
+		}
+		if assigned.Name != event.Category {
+			category = assigned.Name
+		}
 	}
 
-	// Add the event
-	err := app.events.AddEvent(selectedDate, timeStr, description)
-	if err != nil {
-		app.showError(fmt.Sprintf("Error adding event: %v", err))
-	} else {
-		app.showMessage("Event added successfully!")
+	if err := app.events.SetEventCategory(event, category); err != nil {
+		app.showError(fmt.Sprintf("Error assigning category: %v", err))
+	}
+}
 
-		// After adding the event, select and highlight the newly added event
-		// Get the updated events list
-		updatedEvents := app.events.GetEventsForDate(selectedDate)
+// processSearch searches as the query is typed: the results below the calendar follow
+// the prompt line and ↑/↓ select among them. Enter keeps the results for navigation;
+// Esc returns to the view the search started from, or to the results before a refined
+// query.
+func (app *Application) processSearch() {
+	previousState, previousQuery := app.state, app.searchQuery
+	if previousState != StateSearch {
+		previousQuery = ""
+	}
+	app.state = StateSearch
 
-		// Find the newly added event (it should be the one with matching time and description)
-		for i, event := range updatedEvents {
-			if event.GetTimeString() == timeStr && event.Description == description {
-				app.selectedEventIndex = i
-				break
-			}
+	changed := func(query string) {
+		app.selectedResultIndex = 0
+		app.runSearch(query)
+		app.renderCurrentView()
+	}
+	move := func(delta int) {
+		if delta < 0 {
+			app.navigateSearchResultUp()
+		} else {
+			app.navigateSearchResultDown()
 		}
-
-		// If we couldn't find it (unlikely), select the last event
-		if app.selectedEventIndex >= len(updatedEvents) {
-			app.selectedEventIndex = len(updatedEvents) - 1
+		app.renderCurrentView()
+	}
+	query, ok := app.input.GetIncrementalInput("Search (↑↓: select, Enter: keep results, Esc: cancel):", previousQuery, 100, app.renderer, changed, move)
+	if ok {
+		app.runSearch(query)
+		if _, _, err := events.ParseSearchFilter(query, time.Now()); err != nil {
+			app.showError(err.Error())
 		}
+		return
+	}
+
+	// User cancelled
+	app.state = previousState
+	if previousState == StateSearch {
+		app.runSearch(previousQuery)
+		return
 	}
+	app.searchQuery = ""
+	app.searchResults = nil
+	app.searchResultDates = nil
+	app.selectedResultIndex = 0
 }
 
-// processAddEventFromCalendar handles adding an event from the calendar view with inline input
-func (app *Application) processAddEventFromCalendar() {
-	selectedDate := app.navigation.GetCurrentSelection()
+// runSearch fills the search results for a query, keeping the selected result index
+// within the new results
+func (app *Application) runSearch(query string) {
+	app.searchQuery = query
+	app.searchResults = app.events.SearchEvents(query)
+	app.searchUpcoming = -1
+	switch app.config.SearchOrder {
+	case config.SearchOrderNearest:
+		app.searchResults, app.searchUpcoming = events.OrderByNearest(app.searchResults, time.Now())
+	case config.SearchOrderRelevance:
+		app.searchResults = events.OrderByRelevance(app.searchResults, query)
+	}
+	if app.selectedResultIndex >= len(app.searchResults) {
+		app.selectedResultIndex = 0
+	}
 
-	// Calculate coordinates for inline input (same as renderSelectedDateEventsWithAddMode)
-	width, _ := app.terminal.GetSize()
-	totalWidth := 3*24 + 2*2 // monthWidth=24, monthSpacing=2 (from renderer)
-	startX := (width - totalWidth) / 2
-	eventsLeftX := startX + 1
-	eventsStartY := 13
+	// Build unique dates list for grouping
+	app.searchResultDates = make([]string, 0)
+	datesSeen := make(map[string]bool)
+	for _, event := range app.searchResults {
+		dateStr := event.Date.Format("2006-01-02")
+		if !datesSeen[dateStr] {
+			app.searchResultDates = append(app.searchResultDates, dateStr)
+			datesSeen[dateStr] = true
+		}
+	}
+}
 
-	// Get existing events to calculate the Y position
-	events := app.events.GetEventsForDate(selectedDate)
-	maxExistingEvents := 9
-	if len(events) > maxExistingEvents {
-		maxExistingEvents = 9
+// navigateSearchResultUp moves selection up in the search results
+func (app *Application) navigateSearchResultUp() {
+	if len(app.searchResults) > 0 && app.selectedResultIndex > 0 {
+		app.selectedResultIndex--
 	}
-	addEventY := eventsStartY + 1 + maxExistingEvents
+}
 
-	// Get time input using inline input with validation
-	timeStr, ok := app.input.GetInlineTimeInput(eventsLeftX, addEventY, "Time:", app.renderer)
-	if !ok {
-		// User cancelled, return to calendar
-		app.state = StateCalendar
-		app.selectedEventIndex = 0
-		return
+// navigateSearchResultDown moves selection down in the search results
+func (app *Application) navigateSearchResultDown() {
+	if len(app.searchResults) > 0 && app.selectedResultIndex < len(app.searchResults)-1 {
+		app.selectedResultIndex++
 	}
+}
 
-	// Get description input using inline input
-	description, ok := app.input.GetInlineTextInput(eventsLeftX, addEventY, "Description:", 100, app.renderer)
-	if !ok {
-		// User cancelled, return to calendar
-		app.state = StateCalendar
-		app.selectedEventIndex = 0
+// processSearchResultSelection handles Enter key in search mode
+func (app *Application) processSearchResultSelection() {
+	if len(app.searchResults) == 0 {
 		return
 	}
 
-	// Add the event
-	err := app.events.AddEvent(selectedDate, timeStr, description)
-	if err != nil {
-		app.showError(fmt.Sprintf("Error adding event: %v", err))
-	} else {
-		app.showMessage("Event added successfully!")
-	}
+	// Get the selected search result
+	selectedEvent := app.searchResults[app.selectedResultIndex]
 
-	// Return to calendar view
+	// Navigate calendar to the event's date
+	app.navigation.SetSelection(selectedEvent.Date)
+
+	// Update calendar to show the month containing this date
+	eventMonth := selectedEvent.Date
+	app.calendar.CurrentMonth = time.Date(eventMonth.Year(), eventMonth.Month(), 1, 0, 0, 0, 0, eventMonth.Location())
+
+	// Clear search state and return to calendar
+	app.searchQuery = ""
+	app.searchResults = nil
+	app.searchResultDates = nil
+	app.selectedResultIndex = 0
 	app.state = StateCalendar
-	app.selectedEventIndex = 0
 }
 
-// navigateCalendarEventUp moves selection up in the calendar events list
-func (app *Application) navigateCalendarEventUp() {
-	selectedDate := app.navigation.GetCurrentSelection()
-	events := app.events.GetEventsForDate(selectedDate)
-
-	if len(events) > 0 && app.selectedEventIndex > 0 {
-		app.selectedEventIndex--
+func main() {
+	// Load configuration from command line and config file
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
-}
 
-// navigateCalendarEventDown moves selection down in the calendar events list
-func (app *Application) navigateCalendarEventDown() {
-	selectedDate := app.navigation.GetCurrentSelection()
-	events := app.events.GetEventsForDate(selectedDate)
+	// Completion command: print the shell completion script and exit
+	if cfg.CompletionShell != "" {
+		if err := printCompletion(cfg.CompletionShell, filepath.Base(os.Args[0]), flag.CommandLine, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if note := cfg.HostProfileNote(); note != "" {
+		log.Print(note)
+	}
 
-	if len(events) > 0 && app.selectedEventIndex < len(events)-1 {
-		app.selectedEventIndex++
+	// Token command: manage the access tokens of HTTP integrations and exit
+	if len(cfg.TokenArgs) > 0 {
+		if err := runTokenCommand(apitoken.NewStore(cfg.GetDataDir()), cfg.TokenArgs, os.Stdout, time.Now()); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
-}
 
-// navigateCalendarEventEditUp moves selection up in the calendar events list for editing
-func (app *Application) navigateCalendarEventEditUp() {
-	selectedDate := app.navigation.GetCurrentSelection()
-	events := app.events.GetEventsForDate(selectedDate)
+	// Create application with configuration
+	app := NewApplication(cfg)
 
-	if len(events) > 0 && app.selectedEventIndex > 0 {
-		app.selectedEventIndex--
+	// One-shot normalization command: tidy stored descriptions and exit
+	if cfg.NormalizeEvents {
+		if err := app.events.LoadEvents(); err != nil {
+			log.Fatalf("Failed to load events: %v", err)
+		}
+		changed, err := app.events.NormalizeAllEvents()
+		if err != nil {
+			log.Fatalf("Failed to normalize events: %v", err)
+		}
+		fmt.Printf("Normalized %d of %d event descriptions\n", changed, app.events.GetEventCount())
+		printDryRunDiff(app.events, os.Stdout)
+		return
 	}
-}
-
-// navigateCalendarEventEditDown moves selection down in the calendar events list for editing
-func (app *Application) navigateCalendarEventEditDown() {
-	selectedDate := app.navigation.GetCurrentSelection()
-	events := app.events.GetEventsForDate(selectedDate)
 
-	if len(events) > 0 && app.selectedEventIndex < len(events)-1 {
-		app.selectedEventIndex++
+	// One-shot import command: merge events from another file and exit
+	if cfg.ImportFile != "" {
+		if err := app.events.LoadEvents(); err != nil {
+			log.Fatalf("Failed to load events: %v", err)
+		}
+		imported, err := importEvents(app.events, cfg.ImportFile, os.Stdin, os.Stdout)
+		if err != nil {
+			log.Fatalf("Failed to import events: %v", err)
+		}
+		fmt.Printf("Imported %d new events from %s\n", imported, cfg.ImportFile)
+		if imported > 0 {
+			fmt.Println("They wait in the inbox for review; press I in the calendar to accept or delete them.")
+		}
+		printDryRunDiff(app.events, os.Stdout)
+		return
 	}
-}
 
-// processDeleteSelectedCalendarEvent deletes the currently selected event in calendar view
-func (app *Application) processDeleteSelectedCalendarEvent() {
-	selectedDate := app.navigation.GetCurrentSelection()
-	events := app.events.GetEventsForDate(selectedDate)
+	// One-shot todo.txt import: add tasks with due dates, optionally updating earlier imports
+	if cfg.ImportTodoFile != "" {
+		if err := app.events.LoadEvents(); err != nil {
+			log.Fatalf("Failed to load events: %v", err)
+		}
+		added, updated, conflicts, err := importTodoFile(app.events, cfg.ImportTodoFile, cfg.ReimportTodo)
+		if err != nil {
+			log.Fatalf("Failed to import todo.txt: %v", err)
+		}
+		fmt.Printf("Imported %d new and updated %d events from %s\n", added, updated, cfg.ImportTodoFile)
+		if conflicts > 0 {
+			fmt.Printf("%d tasks changed both in the file and in the calendar; resolve them in the inbox.\n", conflicts)
+		}
+		if added+updated+conflicts > 0 {
+			fmt.Println("They wait in the inbox for review; press I in the calendar to accept or delete them.")
+		}
+		printDryRunDiff(app.events, os.Stdout)
+		return
+	}
 
-	if len(events) == 0 {
-		// No events to delete, exit selection mode
-		app.state = StateCalendar
-		app.selectedEventIndex = 0
+	// One-shot export: write a week as a printable planner page
+	if cfg.ExportWeek != "" {
+		if err := app.events.LoadEvents(); err != nil {
+			log.Fatalf("Failed to load events: %v", err)
+		}
+		path, err := exportWeekPlanner(app.events, cfg.ExportWeek, cfg.ExportFile, int(cfg.WeekStartDay), cfg.Formatter(), time.Now())
+		if err != nil {
+			log.Fatalf("Failed to export the week: %v", err)
+		}
+		fmt.Printf("Wrote the week planner to %s\n", path)
 		return
 	}
 
-	if app.selectedEventIndex >= len(events) {
-		app.selectedEventIndex = len(events) - 1
+	// One-shot export: write events as an iCalendar file
+	if cfg.ExportICSFile != "" {
+		if err := app.events.LoadEvents(); err != nil {
+			log.Fatalf("Failed to load events: %v", err)
+		}
+		exported, err := exportICS(app.events, cfg.ExportICSFile, cfg.ExportQuery, cfg.Formatter().In())
+		if err != nil {
+			log.Fatalf("Failed to export events: %v", err)
+		}
+		fmt.Printf("Exported %d events to %s\n", exported, cfg.ExportICSFile)
+		return
 	}
 
-	event := events[app.selectedEventIndex]
-	confirmMsg := fmt.Sprintf("Delete event: %s - %s? (Enter: confirm, Esc: cancel)", event.GetTimeString(), event.Description)
+	// One-shot share: serve a read-only snapshot of a date range until the link expires
+	if cfg.ShareFrom != "" {
+		if err := app.events.LoadEvents(); err != nil {
+			log.Fatalf("Failed to load events: %v", err)
+		}
+		if err := shareRange(app.events, cfg, time.Now(), os.Stdout); err != nil {
+			log.Fatalf("Failed to share the calendar: %v", err)
+		}
+		return
+	}
 
-	if app.confirmAction(confirmMsg) {
-		err := app.events.DeleteEvent(event)
+	// One-shot add command: create events from quick-add lines, e.g. piped by other tools
+	if len(cfg.AddArgs) > 0 {
+		if err := app.events.LoadEvents(); err != nil {
+			log.Fatalf("Failed to load events: %v", err)
+		}
+		var in io.Reader = os.Stdin
+		if len(cfg.AddArgs) != 1 || cfg.AddArgs[0] != "-" {
+			in = strings.NewReader(strings.Join(cfg.AddArgs, " "))
+		}
+		added, failed, err := addEventLines(app.events, in, os.Stdout, time.Now())
 		if err != nil {
-			app.showError(fmt.Sprintf("Error deleting event: %v", err))
-		} else {
-			app.showMessage("Event deleted successfully!")
-			// Adjust selection if we deleted the last event
-			if app.selectedEventIndex >= len(events)-1 && app.selectedEventIndex > 0 {
-				app.selectedEventIndex--
+			log.Fatalf("Failed to read events: %v", err)
+		}
+		fmt.Printf("Added %d events\n", added)
+		printDryRunDiff(app.events, os.Stdout)
+		if failed > 0 {
+			log.Fatalf("%d lines could not be added", failed)
+		}
+		return
+	}
+
+	// Maintenance command: shift event times after a DST change or timezone move
+	if cfg.ShiftBy != 0 || cfg.UndoShift {
+		if err := app.events.LoadEvents(); err != nil {
+			log.Fatalf("Failed to load events: %v", err)
+		}
+		undoFile := filepath.Join(cfg.GetDataDir(), timeShiftUndoFile)
+		if cfg.UndoShift {
+			reverted, err := undoTimeShift(app.events, undoFile, os.Stdin, os.Stdout)
+			if err != nil {
+				log.Fatalf("Failed to undo time shift: %v", err)
 			}
+			fmt.Printf("Reverted %d events\n", reverted)
+			printDryRunDiff(app.events, os.Stdout)
+			return
+		}
+		shifted, err := shiftEventTimes(app.events, cfg.ShiftFrom, cfg.ShiftTo, cfg.ShiftBy, undoFile, os.Stdin, os.Stdout)
+		if err != nil {
+			log.Fatalf("Failed to shift events: %v", err)
+		}
+		fmt.Printf("Shifted %d events\n", shifted)
+		if app.events.DryRun() {
+			printDryRunDiff(app.events, os.Stdout)
+		} else if shifted > 0 {
+			fmt.Println("Run with -undo-shift to revert")
 		}
+		return
 	}
 
-	// Return to calendar navigation after deletion attempt
-	app.state = StateCalendar
-	app.selectedEventIndex = 0
-}
+	// Maintenance command: list the backups of the events files, or restore one; the
+	// events file may be unreadable, so it is not loaded first
+	if cfg.ListBackups || cfg.RestoreBackup != 0 {
+		if cfg.RestoreBackup != 0 {
+			if err := restoreBackup(app.events, cfg.GetEventsFilePath(), cfg.RestoreBackup, os.Stdout); err != nil {
+				log.Fatalf("Failed to restore the backup: %v", err)
+			}
+			return
+		}
+		if err := printBackups(app.events, os.Stdout); err != nil {
+			log.Fatalf("Failed to list backups: %v", err)
+		}
+		return
+	}
 
-// processEditSelectedCalendarEvent edits the currently selected event in calendar view
-func (app *Application) processEditSelectedCalendarEvent() {
-	selectedDate := app.navigation.GetCurrentSelection()
-	events := app.events.GetEventsForDate(selectedDate)
+	// Maintenance command: bring back events purged by the retention policy
+	if cfg.RestorePurged {
+		if err := app.events.LoadEvents(); err != nil {
+			log.Fatalf("Failed to load events: %v", err)
+		}
+		restored, err := retention.Restore(app.events, filepath.Join(cfg.GetDataDir(), retention.TrashFileName))
+		if err != nil {
+			log.Fatalf("Failed to restore purged events: %v", err)
+		}
+		fmt.Printf("Restored %d events from the trash\n", restored)
+		printDryRunDiff(app.events, os.Stdout)
+		return
+	}
 
-	if len(events) == 0 {
-		// No events to edit, exit edit mode
-		app.state = StateCalendar
-		app.selectedEventIndex = 0
+	// Alarm daemon: run event commands at event time until interrupted
+	if cfg.RunDaemon {
+		runAlarmDaemon(cfg, app.events)
 		return
 	}
 
-	if app.selectedEventIndex >= len(events) {
-		app.selectedEventIndex = len(events) - 1
+	// Line-based interface for terminals where termbox cannot run
+	if cfg.NoTUI {
+		if err := app.sync.Pull(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		if err := app.events.LoadEvents(); err != nil {
+			log.Fatalf("Failed to load events: %v%s", err, backupHint(app.events))
+		}
+		if note, _, err := enforceRetention(cfg, app.events, time.Now()); err != nil {
+			log.Printf("Warning: retention: %v", err)
+		} else if note != "" {
+			fmt.Println(note)
+		}
+		if err := lineui.New(cfg, app.events, os.Stdin, os.Stdout).Run(); err != nil {
+			log.Fatalf("Application error: %v", err)
+		}
+		app.stopSync()
+		printDryRunDiff(app.events, os.Stdout)
+		return
 	}
 
-	eventToEdit := events[app.selectedEventIndex]
+	// Detect crashed sessions; only a normal return from Run counts as a clean exit
+	app.startCrashGuard()
+	if err := app.Initialize(); err != nil {
+		_ = app.crashGuard.Stop()
+		log.Fatalf("Failed to initialize application: %v (run with -no-tui for a line-based interface)", err)
+	}
 
-	// Calculate coordinates for inline input (same as add mode)
-	width, _ := app.terminal.GetSize()
-	totalWidth := 3*24 + 2*2 // monthWidth=24, monthSpacing=2 (from renderer)
-	startX := (width - totalWidth) / 2
-	eventsLeftX := startX + 1
-	eventsStartY := 13
-
-	// Calculate Y position for the selected event
-	editEventY := eventsStartY + 1 + app.selectedEventIndex
-
-	// Get new time input with current value as default using validation
-	currentTime := eventToEdit.GetTimeString()
-	timeStr, ok := app.input.GetInlineTimeInputWithDefault(eventsLeftX, editEventY, "Time:", currentTime, app.renderer)
-	if !ok {
-		// User cancelled, return to calendar
-		app.state = StateCalendar
-		app.selectedEventIndex = 0
+	err = app.Run()
+	_ = app.crashGuard.Stop()
+	if err != nil {
+		log.Fatalf("Application error: %v", err)
+	}
+
+	printDryRunDiff(app.events, os.Stdout)
+	fmt.Println("ASCII Calendar - Goodbye!")
+}
+
+// printDryRunDiff lists the changes a dry run kept from the events file
+func printDryRunDiff(manager *events.Manager, out io.Writer) {
+	if !manager.DryRun() {
+		return
+	}
+	diff := manager.DryRunDiff()
+	if len(diff) == 0 {
+		fmt.Fprintln(out, "Dry run: the events file would not change")
 		return
 	}
+	fmt.Fprintf(out, "Dry run: nothing was written. The events file would change as follows:\n")
+	for _, line := range diff {
+		fmt.Fprintf(out, "  %s\n", line)
+	}
+}
 
-	// If user entered empty time, keep the current time
-	if timeStr == "" {
-		timeStr = currentTime
+// importEvents merges events from path into the manager. Imported alarm commands are
+// only kept after the user explicitly confirms them; otherwise they are stripped. The
+// new events wait in the review queue until they are accepted in the calendar.
+func importEvents(manager *events.Manager, path string, in io.Reader, out io.Writer) (int, error) {
+	imported, err := storage.LoadImportFile(path)
+	if err != nil {
+		return 0, err
 	}
 
-	// Get new description input with current value as default
-	currentDesc := eventToEdit.Description
-	description, ok := app.input.GetInlineTextInputWithDefault(eventsLeftX, editEventY, "Description:", 100, currentDesc, app.renderer)
-	if !ok {
-		// User cancelled, return to calendar
-		app.state = StateCalendar
-		app.selectedEventIndex = 0
-		return
+	if commandEvents := alarm.WithCommands(imported); len(commandEvents) > 0 {
+		if !confirmImportCommands(commandEvents, in, out) {
+			for i := range imported {
+				imported[i].Command = ""
+			}
+			fmt.Fprintln(out, "Commands removed; events will be imported without them.")
+		}
 	}
 
-	// If user entered empty description, keep the current description
-	if description == "" {
-		description = currentDesc
+	for i := range imported {
+		imported[i].Unreviewed = true
 	}
+	return manager.ImportEvents(imported)
+}
 
-	// Update the event
-	err := app.events.EditEvent(eventToEdit, selectedDate, timeStr, description)
+// importTodoFile imports the tasks with due dates of a todo.txt file; with update set,
+// tasks imported before are updated to their current due date, time and priority, or
+// queued as conflicts when they were also edited in the calendar. Added, updated and
+// conflicting tasks wait in the review queue until they are accepted or resolved.
+func importTodoFile(manager *events.Manager, path string, update bool) (added, updated, conflicts int, err error) {
+	tasks, err := storage.LoadTodoTxtFile(path)
 	if err != nil {
-		app.showError(fmt.Sprintf("Error editing event: %v", err))
-	} else {
-		app.showMessage("Event edited successfully!")
+		return 0, 0, 0, err
 	}
-
-	// Return to calendar view
-	app.state = StateCalendar
-	app.selectedEventIndex = 0
+	for i := range tasks {
+		tasks[i].Unreviewed = true
+	}
+	return manager.ImportBySource(tasks, update)
 }
 
-// showError displays an error message
-func (app *Application) showError(message string) {
-	app.renderer.RenderMessage(message, true)
-	app.terminal.Flush()
+// completionCommands are the positional commands offered by shell completion
+var completionCommands = []completion.Command{
+	{Name: "add", Description: "Add events from quick-add lines, - reads them from standard input"},
+	{Name: "token", Description: "Create, list or revoke access tokens of HTTP integrations", Args: []string{"create", "list", "revoke"}},
+	{Name: "completion", Description: "Print a shell completion script", Args: completion.Shells},
 }
 
-// showMessage displays a success message
-func (app *Application) showMessage(message string) {
-	app.renderer.RenderMessage(message, false)
-	app.terminal.Flush()
+// printCompletion writes the completion script of shell for program, offering the
+// flags registered in fs and the positional commands
+func printCompletion(shell, program string, fs *flag.FlagSet, out io.Writer) error {
+	script, err := completion.Script(shell, completion.Spec{
+		Program:  program,
+		Flags:    completion.FlagsOf(fs, config.FileFlags),
+		Commands: completionCommands,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(out, script)
+	return err
 }
 
-// confirmAction prompts the user for confirmation (Enter/Esc)
-func (app *Application) confirmAction(message string) bool {
-	// Display the confirmation message
-	app.renderer.RenderMessage(message, false)
-	app.terminal.Flush()
+// exportWeekPlanner writes the week holding the date expression dateExpr, such as
+// "2025-08-11" or "+1w", as a planner page to path, by default to
+// planner-<first day>.txt, its dates written by format. It returns the path written.
+func exportWeekPlanner(manager *events.Manager, dateExpr, path string, weekStartDay int, format locale.Formatter, today time.Time) (string, error) {
+	date, err := calendar.ParseRelativeDate(dateExpr, today)
+	if err != nil {
+		return "", err
+	}
+	start := calendar.GetWeekStart(date, weekStartDay)
+	if path == "" {
+		path = fmt.Sprintf("planner-%s.txt", start.Format("2006-01-02"))
+	}
 
-	// Wait for user input
-	event := app.input.WaitForKey()
+	week := planner.NewWeek(start, manager.GetEventsInDateRange(start, start.AddDate(0, 0, 6)))
+	opts := planner.DefaultOptions
+	opts.Format = format
+	page := strings.Join(planner.Render(week, opts), "\n") + "\n"
+	err = storage.WriteFileAtomic(path, 0644, func(w io.Writer) error {
+		_, err := io.WriteString(w, page)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return path, nil
+}
 
-	// Check for Enter key (confirm) or Esc key (cancel)
-	if event.Key == termbox.KeyEnter {
-		return true
+// exportICS writes the events matching the search query, or all events when it is
+// empty, to path as iCalendar, their times being in loc. It returns the number of events
+// written.
+func exportICS(manager *events.Manager, path, query string, loc *time.Location) (int, error) {
+	var exported []models.Event
+	for _, event := range manager.GetAllEvents() {
+		if events.FilterMatches(config.QuickFilter{Query: query}, event) {
+			exported = append(exported, event)
+		}
 	}
+	sort.Slice(exported, func(i, j int) bool { return exported[i].String() < exported[j].String() })
 
-	return false // Any other key (including Esc) cancels
+	if err := storage.SaveICSFile(exported, path, loc); err != nil {
+		return 0, err
+	}
+	return len(exported), nil
 }
 
-// confirmExit prompts the user to confirm application exit
-func (app *Application) confirmExit() bool {
-	return app.confirmAction("Exit ASCII Calendar? (Enter: confirm, Esc: cancel)")
+// newShareSnapshot takes the snapshot of the days days starting at the date of dateExpr,
+// valid for minutes minutes from now
+func newShareSnapshot(manager *events.Manager, dateExpr string, days, minutes int, busyOnly bool, now time.Time) (share.Snapshot, error) {
+	if days < 1 || minutes < 1 {
+		return share.Snapshot{}, fmt.Errorf("-share-days and -share-minutes must be at least 1")
+	}
+	start, err := calendar.ParseRelativeDate(dateExpr, now)
+	if err != nil {
+		return share.Snapshot{}, err
+	}
+	end := start.AddDate(0, 0, days-1)
+	lifetime := time.Duration(minutes) * time.Minute
+	return share.NewSnapshot(start, end, manager.GetEventsTouchingDateRange(start, end), busyOnly, now, lifetime), nil
 }
 
-// selectEventFromList allows the user to select an event from a list
-func (app *Application) selectEventFromList(events []models.Event, title string) *models.Event {
-	if len(events) == 0 {
-		return nil
+// shareRange serves a snapshot of the configured range on the local network under a
+// random link, printing the link to out, until it expires or the user interrupts
+func shareRange(manager *events.Manager, cfg *config.Config, now time.Time, out io.Writer) error {
+	snapshot, err := newShareSnapshot(manager, cfg.ShareFrom, cfg.ShareDays, cfg.ShareMinutes, cfg.ShareBusy, now)
+	if err != nil {
+		return err
+	}
+	snapshot.Format = cfg.Formatter()
+	token, err := share.NewToken()
+	if err != nil {
+		return err
+	}
+	handler, err := shareHandler(snapshot, token, cfg)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", cfg.ShareAddr)
+	if err != nil {
+		return err
 	}
 
-	// Simple implementation: show list with numbers and get user input
-	app.terminal.Clear()
-
-	fg, bg := app.terminal.GetDefaultColors()
+	fmt.Fprintf(out, "Sharing %s - %s until %s (Ctrl+C stops sooner):\n",
+		calendar.FormatDate(snapshot.From), calendar.FormatDate(snapshot.To), snapshot.Expires.Format("15:04"))
+	for _, url := range share.URLs(listener.Addr().(*net.TCPAddr).Port, token) {
+		fmt.Fprintf(out, "  %s\n", url)
+	}
+	if cfg.ShareAuth {
+		fmt.Fprintf(out, "Requests need an access token of read scope: open the link with ?%s=<secret> in a browser, or send Authorization: Bearer <secret>\n", apitoken.QueryParameter)
+	}
 
-	// Display title
-	app.terminal.PrintCentered(2, title, termbox.AttrBold, bg)
+	ctx, cancel := context.WithDeadline(context.Background(), snapshot.Expires)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := share.Serve(ctx, listener, handler); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "The share link has stopped working")
+	return nil
+}
 
-	// Display events with numbers
-	startY := 4
-	for i, event := range events {
-		eventText := fmt.Sprintf("%d. %s - %s", i+1, event.GetTimeString(), event.Description)
-		// Truncate if too long
-		if len(eventText) > 70 {
-			eventText = eventText[:67] + "..."
-		}
-		app.terminal.PrintCentered(startY+i, eventText, fg, bg)
+// shareHandler serves snapshot under the link token. With -share-auth requests also
+// need an access token of read scope used from an address it allows.
+func shareHandler(snapshot share.Snapshot, token string, cfg *config.Config) (http.Handler, error) {
+	handler := share.Handler(snapshot, token)
+	if !cfg.ShareAuth {
+		return handler, nil
+	}
+	store := apitoken.NewStore(cfg.GetDataDir())
+	if err := store.Load(); err != nil {
+		return nil, err
 	}
+	if len(store.Tokens()) == 0 {
+		return nil, fmt.Errorf("-share-auth needs an access token; create one with: token create --scope read")
+	}
+	return store.Require(apitoken.ScopeRead, handler), nil
+}
 
-	// Instructions
-	instrY := startY + len(events) + 2
-	app.terminal.PrintCentered(instrY, "Enter event number (1-9) or Esc to cancel:", fg, bg)
+// addEventLines adds one event per quick-add line read from in, such as
+// "2025-12-24 18:00 Christmas dinner", reporting the result of each line to out.
+// Blank lines and lines starting with # are skipped.
+func addEventLines(manager *events.Manager, in io.Reader, out io.Writer, today time.Time) (added, failed int, err error) {
+	scanner := bufio.NewScanner(in)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
 
-	app.terminal.Flush()
+		date, timeStr, description, err := calendar.ParseQuickAdd(line, today)
+		if err == nil {
+			err = manager.AddEvent(date, timeStr, description)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "line %d: error: %v\n", lineNum, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "line %d: added %s %s %s\n", lineNum, calendar.FormatDate(date), timeStr, manager.ApplyNormalization(description))
+		added++
+	}
+	return added, failed, scanner.Err()
+}
 
-	// Wait for user selection
-	for {
-		event := app.input.WaitForKey()
+// runTokenCommand runs the token command with args: "create [--scope read]
+// [--allow CIDR,...] [--name NAME]" prints the new secret once, "list" shows the tokens
+// and "revoke ID" removes one
+func runTokenCommand(store *apitoken.Store, args []string, out io.Writer, now time.Time) error {
+	if err := store.Load(); err != nil {
+		return err
+	}
 
-		if event.Type != termbox.EventKey {
-			continue
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("token create", flag.ContinueOnError)
+		fs.SetOutput(out)
+		scopeName := fs.String("scope", string(apitoken.ScopeRead), "What the token may do: read")
+		allowList := fs.String("allow", "", "Comma-separated networks or addresses the token may be used from (default: any)")
+		name := fs.String("name", "", "What the token is for")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() > 0 {
+			return fmt.Errorf("unexpected token create arguments: %s", strings.Join(fs.Args(), " "))
+		}
+		scope, err := apitoken.ParseScope(*scopeName)
+		if err != nil {
+			return err
+		}
+		allow, err := apitoken.ParseAllowlist(*allowList)
+		if err != nil {
+			return err
+		}
+		token, secret, err := store.Create(*name, scope, allow, now)
+		if err != nil {
+			return err
+		}
+		if err := store.Save(); err != nil {
+			return err
 		}
+		fmt.Fprintf(out, "Created %s token %s. Its secret is shown only once:\n%s\n", token.Scope, token.ID, secret)
+		return nil
 
-		// Handle Esc key
-		if event.Key == termbox.KeyEsc {
+	case "list":
+		if len(args) != 1 {
+			return fmt.Errorf("unexpected token list arguments: %s", strings.Join(args[1:], " "))
+		}
+		tokens := store.Tokens()
+		if len(tokens) == 0 {
+			fmt.Fprintln(out, "No access tokens")
 			return nil
 		}
-
-		// Handle number keys
-		if event.Ch >= '1' && event.Ch <= '9' {
-			choice := int(event.Ch - '1') // Convert to 0-based index
-			if choice < len(events) {
-				return &events[choice]
+		for _, token := range tokens {
+			allow := "any address"
+			if len(token.Allow) > 0 {
+				allow = strings.Join(token.Allow, ",")
 			}
+			fmt.Fprintf(out, "%s  %-6s  %s  %s  %s\n", token.ID, token.Scope, token.Created.Format("2006-01-02"), allow, token.Name)
+		}
+		return nil
+
+	case "revoke":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: token revoke ID")
+		}
+		if !store.Revoke(args[1]) {
+			return fmt.Errorf("no access token %q", args[1])
 		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Revoked token %s\n", args[1])
+		return nil
+
+	default:
+		return fmt.Errorf("unknown token command %q: expected create, list or revoke", args[0])
 	}
 }
 
-// processSearch handles the search functionality workflow
-func (app *Application) processSearch() {
-	// Get search query input
-	query, ok := app.input.GetTextInputWithPrompt("Enter search query:", 100, app.renderer)
-	if !ok {
-		return // User cancelled
+// printBackups lists the backups of the events files, latest first
+func printBackups(manager *events.Manager, out io.Writer) error {
+	backups, err := manager.Backups()
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		fmt.Fprintln(out, "No backups yet; one is taken before each delete, edit or migration")
+		return nil
 	}
+	for _, backup := range backups {
+		fmt.Fprintf(out, "%s  %-10s  %s\n", backup.ModTime.Format("2006-01-02 15:04"), backupEvents(backup), backup.Path())
+	}
+	fmt.Fprintln(out, "Restore the events file from one with -restore-backup N")
+	return nil
+}
 
-	// Perform search
-	app.searchQuery = query
-	app.searchResults = app.events.SearchEvents(query)
-	app.selectedResultIndex = 0
+// backupEvents describes the number of events in a backup, e.g. "42 events"
+func backupEvents(backup storage.Backup) string {
+	if backup.Events < 0 {
+		return "unreadable"
+	}
+	return fmt.Sprintf("%d events", backup.Events)
+}
 
-	// Build unique dates list for grouping
-	app.searchResultDates = make([]string, 0)
-	datesSeen := make(map[string]bool)
-	for _, event := range app.searchResults {
-		dateStr := event.Date.Format("2006-01-02")
-		if !datesSeen[dateStr] {
-			app.searchResultDates = append(app.searchResultDates, dateStr)
-			datesSeen[dateStr] = true
+// restoreBackup replaces the events file at path with its backup of that number
+func restoreBackup(manager *events.Manager, path string, number int, out io.Writer) error {
+	backups, err := manager.Backups()
+	if err != nil {
+		return err
+	}
+	for _, backup := range backups {
+		if backup.File != path || backup.Number != number {
+			continue
+		}
+		if err := manager.RestoreBackup(backup); err != nil {
+			return err
 		}
+		fmt.Fprintf(out, "Restored %s from the backup of %s (%s); the replaced file is now backup 1\n",
+			path, backup.ModTime.Format("2006-01-02 15:04"), backupEvents(backup))
+		return nil
 	}
-
-	// Switch to search mode
-	app.state = StateSearch
+	return fmt.Errorf("no backup %d of %s (run with -backups to list them)", number, path)
 }
 
-// navigateSearchResultUp moves selection up in the search results
-func (app *Application) navigateSearchResultUp() {
-	if len(app.searchResults) > 0 && app.selectedResultIndex > 0 {
-		app.selectedResultIndex--
+// backupHint points at the backups when the events cannot be loaded, or returns ""
+// when there are none
+func backupHint(manager *events.Manager) string {
+	if backups, err := manager.Backups(); err != nil || len(backups) == 0 {
+		return ""
 	}
+	return " (list the backups with -backups and restore one with -restore-backup N)"
 }
 
-// navigateSearchResultDown moves selection down in the search results
-func (app *Application) navigateSearchResultDown() {
-	if len(app.searchResults) > 0 && app.selectedResultIndex < len(app.searchResults)-1 {
-		app.selectedResultIndex++
+// confirmImportCommands lists the commands found in imported events and asks whether to keep them
+func confirmImportCommands(commandEvents []models.Event, in io.Reader, out io.Writer) bool {
+	fmt.Fprintf(out, "WARNING: %d imported events carry commands that the alarm daemon will execute:\n", len(commandEvents))
+	for _, event := range commandEvents {
+		fmt.Fprintf(out, "  %s %s %s: %s\n", event.GetDateString(), event.GetTimeString(), event.Description, event.Command)
 	}
+	fmt.Fprint(out, "Keep these commands? [y/N]: ")
+	return readYesNo(in)
 }
 
-// processSearchResultSelection handles Enter key in search mode
-func (app *Application) processSearchResultSelection() {
-	if len(app.searchResults) == 0 {
-		return
+// readYesNo reads one answer line and reports whether it is yes
+func readYesNo(in io.Reader) bool {
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// enforceRetention runs a retention pass over the loaded events, moving expired ones to
+// the trash in the data directory. It returns the report summary, empty when nothing
+// changed, and the purged events; ephemeral sessions have no data directory and are
+// left alone.
+func enforceRetention(cfg *config.Config, manager *events.Manager, now time.Time) (string, []models.Event, error) {
+	if cfg == nil || cfg.Ephemeral {
+		return "", nil, nil
+	}
+	report, err := retention.Enforce(manager, cfg.Retention, filepath.Join(cfg.GetDataDir(), retention.TrashFileName), now)
+	if err != nil {
+		return "", nil, err
 	}
+	return report.Summary(cfg.Retention), report.Purged, nil
+}
 
-	// Get the selected search result
-	selectedEvent := app.searchResults[app.selectedResultIndex]
+// timeShiftUndoFile records the most recent time shift in the data directory
+const timeShiftUndoFile = "timeshift-undo.json"
 
-	// Navigate calendar to the event's date
-	app.navigation.SetSelection(selectedEvent.Date)
+// shiftEventTimes moves the times of all events dated fromStr through toStr by delta
+// after showing a preview and asking for confirmation. The shift is recorded in
+// undoFile so that undoTimeShift can revert it. It returns the number of shifted events.
+func shiftEventTimes(manager *events.Manager, fromStr, toStr string, delta time.Duration, undoFile string, in io.Reader, out io.Writer) (int, error) {
+	if fromStr == "" {
+		return 0, fmt.Errorf("-shift-by requires -shift-from")
+	}
+	from, err := time.ParseInLocation("2006-01-02", fromStr, time.Local)
+	if err != nil {
+		return 0, fmt.Errorf("invalid -shift-from date %q: %v", fromStr, err)
+	}
+	to := from
+	if toStr != "" {
+		to, err = time.ParseInLocation("2006-01-02", toStr, time.Local)
+		if err != nil {
+			return 0, fmt.Errorf("invalid -shift-to date %q: %v", toStr, err)
+		}
+	}
+	if to.Before(from) {
+		return 0, fmt.Errorf("-shift-to %s is before -shift-from %s", toStr, fromStr)
+	}
 
-	// Update calendar to show the month containing this date
-	eventMonth := selectedEvent.Date
-	app.calendar.CurrentMonth = time.Date(eventMonth.Year(), eventMonth.Month(), 1, 0, 0, 0, 0, eventMonth.Location())
+	shifts := manager.PlanTimeShift(from, to, delta)
+	if len(shifts) == 0 {
+		fmt.Fprintln(out, "No events in the given date range")
+		return 0, nil
+	}
 
-	// Clear search state and return to calendar
-	app.searchQuery = ""
-	app.searchResults = nil
-	app.searchResultDates = nil
-	app.selectedResultIndex = 0
-	app.state = StateCalendar
+	fmt.Fprintf(out, "Shifting %d events by %v:\n", len(shifts), delta)
+	if !confirmTimeShifts(shifts, in, out) {
+		return 0, nil
+	}
+
+	if err := manager.ApplyTimeShifts(shifts); err != nil {
+		return 0, err
+	}
+	if manager.ReadOnly() {
+		return len(shifts), nil
+	}
+	if err := events.SaveTimeShifts(shifts, undoFile); err != nil {
+		return len(shifts), fmt.Errorf("events were shifted, but the undo file could not be written: %v", err)
+	}
+	return len(shifts), nil
 }
 
-func main() {
-	// Load configuration from command line and config file
-	cfg, err := config.LoadConfig()
+// undoTimeShift reverts the shift recorded in undoFile after confirmation and removes the file
+func undoTimeShift(manager *events.Manager, undoFile string, in io.Reader, out io.Writer) (int, error) {
+	if _, err := os.Stat(undoFile); os.IsNotExist(err) {
+		return 0, fmt.Errorf("no time shift to undo")
+	}
+	shifts, err := events.LoadTimeShifts(undoFile)
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		return 0, err
 	}
 
-	// Create application with configuration
-	app := NewApplication(cfg)
+	reverts := events.InvertTimeShifts(shifts)
+	fmt.Fprintf(out, "Reverting %d events:\n", len(reverts))
+	if !confirmTimeShifts(reverts, in, out) {
+		return 0, nil
+	}
 
-	if err := app.Initialize(); err != nil {
-		log.Fatalf("Failed to initialize application: %v", err)
+	if err := manager.ApplyTimeShifts(reverts); err != nil {
+		return 0, err
+	}
+	if manager.ReadOnly() {
+		return len(reverts), nil
 	}
+	if err := os.Remove(undoFile); err != nil {
+		return len(reverts), fmt.Errorf("events were reverted, but the undo file could not be removed: %v", err)
+	}
+	return len(reverts), nil
+}
 
-	if err := app.Run(); err != nil {
-		log.Fatalf("Application error: %v", err)
+// confirmTimeShifts previews each shift and asks whether to apply them
+func confirmTimeShifts(shifts []events.TimeShift, in io.Reader, out io.Writer) bool {
+	for _, shift := range shifts {
+		fmt.Fprintf(out, "  %s %s -> %s %s  %s\n",
+			shift.Before.GetDateString(), shift.Before.GetTimeString(),
+			shift.After.GetDateString(), shift.After.GetTimeString(), shift.Before.Description)
 	}
+	fmt.Fprint(out, "Apply these changes? [y/N]: ")
+	return readYesNo(in)
+}
 
-	fmt.Println("ASCII Calendar - Goodbye!")
+// runAlarmDaemon executes event commands at their event time until interrupted
+func runAlarmDaemon(cfg *config.Config, manager *events.Manager) {
+	var lastMaintenance time.Time
+	load := func() ([]models.Event, error) {
+		// Reload every poll so edits made in the calendar are picked up
+		if err := manager.ReloadEvents(); err != nil {
+			return nil, err
+		}
+		// Enforce the retention policy on the first poll and then once a day
+		if now := time.Now(); now.Sub(lastMaintenance) >= 24*time.Hour {
+			lastMaintenance = now
+			note, purged, err := enforceRetention(cfg, manager, now)
+			if err != nil {
+				log.Printf("Retention: %v", err)
+			}
+			for _, event := range purged {
+				log.Printf("Retention: purged %s %s %s", event.GetDateString(), event.GetTimeString(), event.Description)
+			}
+			if note != "" {
+				log.Print(note)
+			}
+		}
+		return manager.GetAllEvents(), nil
+	}
+	daemon := alarm.NewDaemon(load, nil, nil, time.Now())
+
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		close(stop)
+	}()
+
+	fmt.Println("Alarm daemon running, press Ctrl+C to stop")
+	daemon.Run(alarm.DefaultPollInterval, stop)
 }
@@ -1,14 +1,53 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"go-ascii-calendar/apitoken"
+	"go-ascii-calendar/calendar"
 	"go-ascii-calendar/config"
+	"go-ascii-calendar/events"
+	"go-ascii-calendar/locale"
+	"go-ascii-calendar/messages"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/share"
+	"go-ascii-calendar/storage"
+	"go-ascii-calendar/terminal"
+
+	"github.com/nsf/termbox-go"
 )
 
+// TestMain runs the tests in a temporary directory: managers created without a
+// configuration keep their events in events.txt in the working directory
+func TestMain(m *testing.M) {
+	os.Exit(runInTempDir(m))
+}
+
+// runInTempDir runs the tests with a temporary working directory, removed afterwards
+func runInTempDir(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "main_test")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create a temp dir: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to enter %s: %v\n", dir, err)
+		return 1
+	}
+	return m.Run()
+}
+
 func TestNewApplication(t *testing.T) {
 	// Create temporary config for testing
 	tempDir, err := os.MkdirTemp("", "main_test")
@@ -305,3 +344,1507 @@ func TestApplication_ConfigHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestImportEvents_CommandConfirmation(t *testing.T) {
+	tests := []struct {
+		name        string
+		answer      string
+		wantCommand string
+	}{
+		{"Confirmed", "y\n", "notify-send standup"},
+		{"Declined", "n\n", ""},
+		{"No answer", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, err := os.MkdirTemp("", "import_test")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tempDir)
+
+			importPath := filepath.Join(tempDir, "import.json")
+			testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+			testTime := time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+			toImport := []models.Event{
+				{Date: testDate, Time: testTime, Description: "Standup", Command: "notify-send standup"},
+			}
+			if err := storage.SaveEventsJSON(toImport, importPath); err != nil {
+				t.Fatalf("Failed to write import file: %v", err)
+			}
+
+			cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+			manager := events.NewManagerWithConfig(cfg)
+
+			var out bytes.Buffer
+			imported, err := importEvents(manager, importPath, strings.NewReader(tt.answer), &out)
+			if err != nil {
+				t.Fatalf("importEvents() failed: %v", err)
+			}
+			if imported != 1 {
+				t.Errorf("importEvents() imported %d events, want 1", imported)
+			}
+			if !strings.Contains(out.String(), "notify-send standup") {
+				t.Errorf("Confirmation prompt should list the command, got %q", out.String())
+			}
+
+			stored, err := storage.LoadEventsJSON(cfg.EventsFilePath)
+			if err != nil {
+				t.Fatalf("Failed to reload events: %v", err)
+			}
+			if len(stored) != 1 || stored[0].Command != tt.wantCommand {
+				t.Errorf("Stored events = %v, want command %q", stored, tt.wantCommand)
+			}
+			if !stored[0].Unreviewed {
+				t.Error("Imported events should wait in the review queue")
+			}
+		})
+	}
+}
+
+func TestApplication_ActivityLogUndo(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "activity_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	app := NewApplication(&config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")})
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	if err := app.events.AddEvent(testDate, "09:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+
+	app.handleAction(terminal.ActionShowActivityLog)
+	if app.state != StateActivityLog {
+		t.Fatalf("State after Shift+L = %v, want activity log", app.state)
+	}
+
+	app.handleAction(terminal.ActionUndo)
+	if app.events.GetEventCount() != 0 {
+		t.Errorf("Event count after undo = %d, want 0", app.events.GetEventCount())
+	}
+	if entries := app.history.Entries(); len(entries) != 1 || !entries[0].Undone {
+		t.Errorf("Activity log after undo = %v, want one undone entry", entries)
+	}
+
+	// B opens the backups here, not the previous month
+	if app.keyView() != terminal.ViewActivityLog {
+		t.Errorf("Key view of the activity log = %v, want ViewActivityLog", app.keyView())
+	}
+	app.input.SetView(app.keyView())
+	app.handleAction(app.input.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: 'b'}))
+	if app.state != StateBackups {
+		t.Fatalf("State after B = %v, want backups", app.state)
+	}
+	app.handleAction(terminal.ActionBack)
+	if app.state != StateActivityLog {
+		t.Errorf("State after Esc in the backups = %v, want activity log", app.state)
+	}
+
+	app.handleAction(terminal.ActionBack)
+	if app.state != StateCalendar {
+		t.Errorf("State after Esc = %v, want calendar", app.state)
+	}
+}
+
+func TestApplication_ReviewQueue(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "review_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	app := NewApplication(&config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")})
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	imported := []models.Event{
+		{Date: testDate, Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Standup", Category: "work", Unreviewed: true},
+		{Date: testDate, Time: time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC), Description: "Lunch", Category: "food", Unreviewed: true},
+	}
+	if _, err := app.events.ImportEvents(imported); err != nil {
+		t.Fatalf("ImportEvents() failed: %v", err)
+	}
+
+	app.handleAction(terminal.ActionShowInbox)
+	if app.state != StateReview {
+		t.Fatalf("State after I = %v, want review", app.state)
+	}
+
+	// Enter keeps the first event as imported
+	app.handleAction(terminal.ActionShowEvents)
+	if queue := app.events.UnreviewedEvents(); len(queue) != 1 || queue[0].Description != "Lunch" {
+		t.Fatalf("Queue after accepting = %v, want only Lunch", queue)
+	}
+
+	// 0 accepts the last one without its category
+	app.acceptReviewedEvent('0')
+	if queue := app.events.UnreviewedEvents(); len(queue) != 0 {
+		t.Fatalf("Queue after accepting all = %v, want it empty", queue)
+	}
+	for _, event := range app.events.GetEventsForDate(testDate) {
+		if want := map[string]string{"Standup": "work", "Lunch": ""}[event.Description]; event.Category != want {
+			t.Errorf("%s has category %q, want %q", event.Description, event.Category, want)
+		}
+	}
+
+	app.handleAction(terminal.ActionBack)
+	if app.state != StateCalendar {
+		t.Errorf("State after Esc = %v, want calendar", app.state)
+	}
+}
+
+func TestApplication_ConflictResolution(t *testing.T) {
+	app := NewApplication(&config.Config{EventsFilePath: filepath.Join(t.TempDir(), "events.json")})
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	task := func(days int, priority string) models.Event {
+		return models.Event{Date: testDate.AddDate(0, 0, days), Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Pay rent", Priority: priority, Source: "todo:rent", Unreviewed: true}
+	}
+	if _, _, _, err := app.events.ImportBySource([]models.Event{task(0, "C")}, true); err != nil {
+		t.Fatalf("ImportBySource() failed: %v", err)
+	}
+	if _, err := app.events.MoveEvent(app.events.GetEventsForDate(testDate)[0], 1); err != nil {
+		t.Fatalf("MoveEvent() failed: %v", err)
+	}
+	if _, _, conflicts, err := app.events.ImportBySource([]models.Event{task(3, "A")}, true); err != nil || conflicts != 1 {
+		t.Fatalf("ImportBySource() = %d conflicts, %v; want 1", conflicts, err)
+	}
+
+	app.handleAction(terminal.ActionShowInbox)
+	app.handleAction(terminal.ActionShowEvents)
+	if app.state != StateConflict {
+		t.Fatalf("State after Enter on a conflict = %v, want conflict", app.state)
+	}
+
+	// Keep the calendar's date and take the priority from the source
+	for range events.ConflictFields[1:] {
+		app.handleAction(terminal.ActionMoveDown)
+	}
+	app.handleAction(terminal.ActionMoveRight)
+	app.handleAction(terminal.ActionShowEvents)
+	if app.state != StateReview {
+		t.Fatalf("State after resolving = %v, want review", app.state)
+	}
+	resolved := app.events.GetEventsForDate(testDate.AddDate(0, 0, 1))
+	if len(resolved) != 1 || resolved[0].Priority != "A" || resolved[0].Conflict != nil || resolved[0].Unreviewed {
+		t.Errorf("Resolved event = %+v, want the calendar's date with the source's priority", resolved)
+	}
+	if len(app.events.Conflicts()) != 0 || len(app.events.UnreviewedEvents()) != 0 {
+		t.Errorf("Conflicts and inbox should be empty after resolving")
+	}
+}
+
+func TestApplication_EventDetails(t *testing.T) {
+	app := NewApplication(&config.Config{EventsFilePath: filepath.Join(t.TempDir(), "events.json")})
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	for _, description := range []string{"Standup", "Planning"} {
+		if err := app.events.AddEvent(testDate, "09:00", description); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+	}
+	planning := app.events.GetEventsForDate(testDate)[1]
+	if _, err := app.events.SetEventNotes(planning, "Agenda:\n- roadmap\n- hiring"); err != nil {
+		t.Fatalf("SetEventNotes() failed: %v", err)
+	}
+	app.navigation.JumpToDate(testDate)
+
+	app.handleAction(terminal.ActionShowEvents)
+	app.handleAction(terminal.ActionMoveDown)
+	app.handleAction(terminal.ActionShowEvents)
+	if app.state != StateDetails {
+		t.Fatalf("State after Enter in the events list = %v, want event details", app.state)
+	}
+	if app.detailsEvent.Description != "Planning" || app.detailsEvent.Notes != "Agenda:\n- roadmap\n- hiring" {
+		t.Errorf("Details show %+v, want Planning with its notes", app.detailsEvent)
+	}
+
+	app.handleAction(terminal.ActionMoveDown)
+	if app.detailsScroll != 1 {
+		t.Errorf("Scroll after J = %d, want 1", app.detailsScroll)
+	}
+	app.handleAction(terminal.ActionMoveUp)
+	app.handleAction(terminal.ActionMoveUp)
+	if app.detailsScroll != 0 {
+		t.Errorf("Scroll after K past the top = %d, want 0", app.detailsScroll)
+	}
+
+	app.handleAction(terminal.ActionBack)
+	if app.state != StateEventList || app.selectedEventIndex != 1 {
+		t.Errorf("After Esc: state %v, selected %d; want the events list on Planning", app.state, app.selectedEventIndex)
+	}
+}
+
+func TestApplication_DeferredDelete(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "delete_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json"), DeleteUndoSeconds: 5}
+	app := NewApplication(cfg)
+	defer app.flushDeletes()
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	if err := app.events.AddEvent(testDate, "09:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	stored := func() int {
+		events, err := storage.LoadEventsJSON(cfg.EventsFilePath)
+		if err != nil {
+			t.Fatalf("Failed to reload events: %v", err)
+		}
+		return len(events)
+	}
+
+	standup := app.events.GetEventsForDate(testDate)[0]
+	if err := app.deleteEvent(standup); err != nil {
+		t.Fatalf("deleteEvent() failed: %v", err)
+	}
+	if app.events.GetEventCount() != 0 || stored() != 1 {
+		t.Errorf("After deleting: %d events shown, %d stored; want 0 and 1", app.events.GetEventCount(), stored())
+	}
+	now := time.Now()
+	if got, want := app.deleteToast(now), `Deleted "Standup" - press U to undo (5s)`; got != want {
+		t.Errorf("deleteToast() = %q, want %q", got, want)
+	}
+
+	// U brings the event back from any view
+	app.state = StateFollowUps
+	app.handleAction(terminal.ActionUndo)
+	if app.events.GetEventCount() != 1 || app.deleteToast(now) != "" {
+		t.Errorf("After undoing: %d events, toast %q; want the event back and no toast", app.events.GetEventCount(), app.deleteToast(now))
+	}
+
+	// Exiting writes deletions that are still pending
+	if err := app.deleteEvent(standup); err != nil {
+		t.Fatalf("deleteEvent() failed: %v", err)
+	}
+	app.flushDeletes()
+	if got := stored(); got != 0 {
+		t.Errorf("Stored events after flushing = %d, want 0", got)
+	}
+}
+
+func TestApplication_BookmarkPicker(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "bookmark_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	app := NewApplication(&config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")})
+	deadline := time.Date(2027, 3, 9, 0, 0, 0, 0, time.Local)
+	if err := app.bookmarks.SetBookmark(deadline, "project deadline review"); err != nil {
+		t.Fatalf("SetBookmark() failed: %v", err)
+	}
+
+	app.handleAction(terminal.ActionShowBookmarks)
+	if app.state != StateBookmarks {
+		t.Fatalf("State after G = %v, want bookmarks", app.state)
+	}
+
+	// Enter jumps to the bookmarked date, even outside the visible months
+	app.handleAction(terminal.ActionShowEvents)
+	if app.state != StateCalendar {
+		t.Errorf("State after jumping = %v, want calendar", app.state)
+	}
+	if !app.navigation.GetCurrentSelection().Equal(deadline) {
+		t.Errorf("Selected date = %v, want %v", app.navigation.GetCurrentSelection(), deadline)
+	}
+
+	// D removes the selected bookmark, and so does Shift+D, as there is no day to show
+	for _, key := range []rune{'d', 'D'} {
+		if err := app.bookmarks.SetBookmark(deadline, "project deadline review"); err != nil {
+			t.Fatalf("SetBookmark() failed: %v", err)
+		}
+		app.handleAction(terminal.ActionShowBookmarks)
+		app.input.SetView(app.keyView())
+		app.handleAction(app.input.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: key}))
+		if len(app.bookmarks.Bookmarks()) != 0 {
+			t.Errorf("Bookmarks after %c = %v, want none", key, app.bookmarks.Bookmarks())
+		}
+		app.handleAction(terminal.ActionBack)
+	}
+}
+
+func TestApplication_Calendars(t *testing.T) {
+	app := NewApplication(&config.Config{
+		EventsFilePath: filepath.Join(t.TempDir(), "events.json"),
+		Calendars:      []config.EventCalendar{{Name: "work", File: filepath.Join(t.TempDir(), "work.json")}},
+	})
+
+	app.handleAction(terminal.ActionShowCalendars)
+	if app.state != StateCalendars {
+		t.Fatalf("State after Shift+C = %v, want calendars", app.state)
+	}
+
+	// X hides the selected calendar and shows it again
+	app.handleAction(terminal.ActionMoveDown)
+	app.input.SetView(app.keyView())
+	press := func() { app.handleAction(app.input.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: 'x'})) }
+	press()
+	if !app.events.CalendarHidden("work") {
+		t.Error("work should be hidden after X")
+	}
+	press()
+	if app.events.CalendarHidden("work") {
+		t.Error("work should be shown after a second X")
+	}
+	if app.state != StateCalendars {
+		t.Errorf("State after X = %v, want calendars", app.state)
+	}
+}
+
+func TestApplication_MessageHistory(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "messages_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	app := NewApplication(&config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")})
+	app.showMessage("Event added")
+	app.showError("Sync failed")
+
+	// The first message stays on the status line while the error waits its turn
+	if message, ok := app.messages.Current(time.Now()); !ok || message.Text != "Event added" {
+		t.Errorf("Shown message = %q, %v, want the first one", message.Text, ok)
+	}
+
+	app.handleAction(terminal.ActionShowMessages)
+	if app.state != StateMessages {
+		t.Fatalf("State after \" = %v, want messages", app.state)
+	}
+	history := app.messages.History()
+	if len(history) != 2 || history[0].Text != "Sync failed" || history[0].Severity != messages.Error {
+		t.Errorf("History = %+v, want the error first", history)
+	}
+
+	app.handleAction(terminal.ActionMoveDown)
+	app.handleAction(terminal.ActionMoveDown)
+	if app.selectedMessageIndex != 1 {
+		t.Errorf("Selected message = %d, want the last one", app.selectedMessageIndex)
+	}
+	app.handleAction(terminal.ActionBack)
+	if app.state != StateCalendar {
+		t.Errorf("State after Esc = %v, want calendar", app.state)
+	}
+}
+
+func TestApplication_Tour(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "tour_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	app := NewApplication(&config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")})
+
+	// The help screen starts the tour with Enter
+	app.handleAction(terminal.ActionShowHelp)
+	if app.state != StateHelp {
+		t.Fatalf("State after ? = %v, want help", app.state)
+	}
+	app.handleAction(terminal.ActionShowEvents)
+	if app.state != StateTour || app.tourStep != 0 {
+		t.Fatalf("State after Enter on help = %v step %d, want the first tour step", app.state, app.tourStep)
+	}
+
+	// Enter continues; the practiced key is performed on the calendar and continues too
+	app.handleAction(terminal.ActionShowEvents)
+	if terminal.Tour[app.tourStep].Practice != terminal.ActionMoveRight {
+		t.Fatalf("Tour step %d should practice moving right", app.tourStep)
+	}
+	selected := app.navigation.GetCurrentSelection()
+	app.handleAction(terminal.ActionMoveRight)
+	if app.tourStep != 2 {
+		t.Errorf("Tour step after the practiced key = %d, want 2", app.tourStep)
+	}
+	if !app.navigation.GetCurrentSelection().Equal(selected.AddDate(0, 0, 1)) {
+		t.Error("The practiced key should move the selection")
+	}
+
+	// Esc ends the tour and remembers it for the next start
+	app.handleAction(terminal.ActionBack)
+	if app.state != StateCalendar {
+		t.Errorf("State after Esc in the tour = %v, want calendar", app.state)
+	}
+	if !app.bookmarks.TourDone() {
+		t.Error("Ending the tour should record it in the state file")
+	}
+
+	// Going through every step also ends it
+	app.startTour()
+	for range terminal.Tour {
+		app.handleAction(terminal.ActionShowEvents)
+	}
+	if app.state != StateCalendar {
+		t.Errorf("State after the last tour step = %v, want calendar", app.state)
+	}
+}
+
+func TestApplication_DayView(t *testing.T) {
+	app := NewApplication(&config.Config{Ephemeral: true})
+	date := time.Date(2030, 5, 14, 0, 0, 0, 0, time.Local)
+	app.navigation.JumpToDate(date)
+	if err := app.events.AddEvent(date, "14:30", "Dentist"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+
+	// Shift+D opens the day at its first event
+	app.handleAction(terminal.ActionShowDayView)
+	if app.state != StateDayView || app.dayViewHour != 14 || app.dayViewAnchor != -1 {
+		t.Fatalf("Day view opened in state %v at hour %d, anchor %d; want hour 14 unmarked", app.state, app.dayViewHour, app.dayViewAnchor)
+	}
+
+	// Enter marks the start, J moves the end and Esc cancels marking before leaving
+	app.handleAction(terminal.ActionShowEvents)
+	app.handleAction(terminal.ActionMoveDown)
+	app.handleAction(terminal.ActionMoveDown)
+	if app.dayViewAnchor != 14 || app.dayViewHour != 16 {
+		t.Errorf("Marking = anchor %d, hour %d; want 14 through 16", app.dayViewAnchor, app.dayViewHour)
+	}
+	app.handleAction(terminal.ActionBack)
+	if app.state != StateDayView || app.dayViewAnchor != -1 {
+		t.Error("Esc while marking should only cancel the marking")
+	}
+
+	// L moves to the next day
+	app.handleAction(terminal.ActionMoveRight)
+	if !app.navigation.GetCurrentSelection().Equal(date.AddDate(0, 0, 1)) {
+		t.Errorf("Selected date after L = %v, want the next day", app.navigation.GetCurrentSelection())
+	}
+
+	app.handleAction(terminal.ActionBack)
+	if app.state != StateCalendar {
+		t.Errorf("State after Esc = %v, want calendar", app.state)
+	}
+}
+
+func TestApplication_WindowTitle(t *testing.T) {
+	app := NewApplication(config.DefaultConfig())
+	app.calendar.CurrentMonth = time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+
+	if title := app.windowTitle(); title != "ascii-calendar — September 2025" {
+		t.Errorf("windowTitle() = %q, want %q", title, "ascii-calendar — September 2025")
+	}
+}
+
+func TestApplication_SyncPullReloadsEvents(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "main_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	remote := filepath.Join(tempDir, "remote.json")
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	event := models.Event{
+		Date:        date,
+		Time:        time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+		Description: "Synced standup",
+	}
+	if err := storage.SaveEventsJSON([]models.Event{event}, remote); err != nil {
+		t.Fatalf("Failed to write remote events: %v", err)
+	}
+
+	cfg := &config.Config{
+		EventsFilePath: filepath.Join(tempDir, "test_events.json"),
+		SyncPullCmd:    "cp remote.json test_events.json",
+	}
+	app := NewApplication(cfg)
+
+	// Without a pull there is nothing to reload
+	app.reloadAfterSync()
+	if len(app.events.GetEventsForDate(date)) != 0 {
+		t.Fatal("Events should not change before a pull")
+	}
+
+	if err := app.sync.Pull(); err != nil {
+		t.Fatalf("Pull() failed: %v", err)
+	}
+	app.reloadAfterSync()
+
+	list := app.events.GetEventsForDate(date)
+	if len(list) != 1 || list[0].Description != "Synced standup" {
+		t.Errorf("Events after pull = %v, want the synced standup", list)
+	}
+	if status := app.sync.StatusText(); !strings.HasPrefix(status, "sync: pulled") {
+		t.Errorf("StatusText() = %q, want a pulled status", status)
+	}
+}
+
+func TestNewCalDAV(t *testing.T) {
+	dir := t.TempDir()
+	server := config.CalDAVConfig{URL: "https://dav.example.com/cal/", Username: "me", PasswordCmd: "echo secret"}
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		want bool
+	}{
+		{"no configuration", nil, false},
+		{"no server", &config.Config{EventsFilePath: filepath.Join(dir, "events.json")}, false},
+		{"server", &config.Config{EventsFilePath: filepath.Join(dir, "events.json"), CalDAV: server}, true},
+		{"dry run", &config.Config{EventsFilePath: filepath.Join(dir, "events.json"), CalDAV: server, DryRun: true}, false},
+		{"ephemeral", &config.Config{CalDAV: server, Ephemeral: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			davSync, err := newCalDAV(tt.cfg)
+			if err != nil {
+				t.Fatalf("newCalDAV() failed: %v", err)
+			}
+			if got := davSync != nil; got != tt.want {
+				t.Errorf("newCalDAV() enabled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A broken state file turns the sync off with the reason
+	if err := os.WriteFile(filepath.Join(dir, "caldav.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newCalDAV(&config.Config{EventsFilePath: filepath.Join(dir, "events.json"), CalDAV: server}); err == nil {
+		t.Error("newCalDAV() should report an unreadable state file")
+	}
+}
+
+func TestApplication_DeleteChordWithSeveralEvents(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "chord_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	app := NewApplication(&config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")})
+	testDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	app.navigation.JumpToDate(testDate)
+	for _, description := range []string{"Standup", "Review"} {
+		if err := app.events.AddEvent(testDate, "09:00", description); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+	}
+
+	// dd cannot tell which of several events to delete, so it falls back to picking one
+	app.handleAction(terminal.ActionDeleteSelected)
+	if app.state != StateCalendarEventSelection {
+		t.Errorf("State after dd = %v, want event selection", app.state)
+	}
+	if app.events.GetEventCount() != 2 {
+		t.Errorf("Event count after dd = %d, want 2", app.events.GetEventCount())
+	}
+}
+
+func TestApplication_StatusText(t *testing.T) {
+	app := NewApplication(nil)
+	if status := app.statusText(); status != "" {
+		t.Errorf("statusText() = %q, want empty", status)
+	}
+
+	app.input.ProcessChordKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: 'g'}, time.Now())
+	if status := app.statusText(); status != "g-" {
+		t.Errorf("statusText() with a pending chord = %q, want %q", status, "g-")
+	}
+}
+
+func TestApplication_ArchiveEvents(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "main_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{
+		EventsFilePath: filepath.Join(tempDir, "events.json"),
+		ArchiveCmd:     `cp events.json archive.json && echo '{"events": []}' > events.json`,
+	}
+	app := NewApplication(cfg)
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	for _, description := range []string{"Standup", "Review"} {
+		if err := app.events.AddEvent(date, "09:00", description); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+	}
+
+	if size := app.events.Size(); !size.Exceeds(1, 0) {
+		t.Fatalf("Size() = %v, want over a limit of 1 event", size)
+	}
+
+	app.archiveEvents()
+	if count := app.events.GetEventCount(); count != 0 {
+		t.Errorf("GetEventCount() after archiving = %d, want 0", count)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "archive.json")); err != nil {
+		t.Errorf("Archive command did not run: %v", err)
+	}
+}
+
+func TestApplication_DayNote(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Holidays = []config.Holiday{{Name: "Labor Day", Date: "2025-09-01"}}
+	app := NewApplication(cfg)
+
+	tests := []struct {
+		date time.Time
+		want string
+	}{
+		{time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local), "Labor Day"},
+		{time.Date(2025, 9, 2, 0, 0, 0, 0, time.Local), ""},
+		{time.Date(2025, 9, 6, 0, 0, 0, 0, time.Local), "Saturday"},
+	}
+	for _, tt := range tests {
+		if note := app.dayNote(tt.date); note != tt.want {
+			t.Errorf("dayNote(%s) = %q, want %q", tt.date.Format("2006-01-02"), note, tt.want)
+		}
+	}
+
+	cfg.WeekendNotes = false
+	if note := app.dayNote(time.Date(2025, 9, 6, 0, 0, 0, 0, time.Local)); note != "" {
+		t.Errorf("dayNote() with weekend notes off = %q, want empty", note)
+	}
+}
+
+func TestApplication_CycleTheme(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.EventsFilePath = filepath.Join(dir, "events.json")
+	cfg.ConfigFilePath = filepath.Join(dir, "config.json")
+	app := NewApplication(cfg)
+
+	for _, want := range []string{"default", "dark", "light", "default"} {
+		app.handleAction(terminal.ActionCycleTheme)
+		if app.themeName != want {
+			t.Errorf("themeName = %q, want %q", app.themeName, want)
+		}
+	}
+	if app.state != StateCalendar {
+		t.Errorf("state = %v, want calendar", app.state)
+	}
+
+	// The choice is saved for the next start
+	loaded := config.DefaultConfig()
+	if data, err := os.ReadFile(cfg.ConfigFilePath); err != nil || json.Unmarshal(data, loaded) != nil || loaded.Theme != "default" {
+		t.Errorf("Saved theme = %q (%v), want default", loaded.Theme, err)
+	}
+
+	// A custom theme joins the cycle after the predefined ones
+	app.config.UITheme.TodayFg = "magenta|bold"
+	for _, want := range []string{"dark", "light", "custom", "default"} {
+		app.handleAction(terminal.ActionCycleTheme)
+		if app.themeName != want {
+			t.Errorf("themeName = %q, want %q", app.themeName, want)
+		}
+	}
+}
+
+func TestApplication_SwitchTheme(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ConfigFilePath = filepath.Join(t.TempDir(), "config.json")
+	cfg.Ephemeral = true
+	app := NewApplication(cfg)
+
+	app.switchTheme("Light")
+	if app.themeName != "light" {
+		t.Errorf("themeName = %q, want light", app.themeName)
+	}
+	if _, err := os.Stat(cfg.ConfigFilePath); !os.IsNotExist(err) {
+		t.Error("An ephemeral session should not save the theme")
+	}
+
+	app.switchTheme("neon")
+	if app.themeName != "light" {
+		t.Errorf("themeName = %q after an unknown theme, want light kept", app.themeName)
+	}
+	if history := app.messages.History(); len(history) == 0 || !strings.Contains(history[0].Text, "Unknown theme") {
+		t.Errorf("Messages = %v, want the unknown theme named", history)
+	}
+}
+
+func TestApplication_Banner(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "banner_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{
+		EventsFilePath: filepath.Join(tempDir, "events.json"),
+		StartupBanner: []config.BannerWidget{
+			{Type: "agenda"},
+			{Type: "countdowns", Countdowns: []config.Countdown{{Name: "Someday", Date: "2999-01-01"}}},
+		},
+		BannerIdleMinutes: 5,
+	}
+	app := NewApplication(cfg)
+	if app.bannerErr != nil {
+		t.Fatalf("Banner configuration error: %v", app.bannerErr)
+	}
+	if err := app.events.AddEvent(time.Now(), "09:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+
+	app.showBanner()
+	if app.state != StateBanner {
+		t.Errorf("State after showBanner() = %v, want banner", app.state)
+	}
+	if len(app.bannerSections) != 2 || app.bannerSections[0].Lines[0] != "09:00  Standup" {
+		t.Errorf("Banner sections = %v, want today's agenda and the countdown", app.bannerSections)
+	}
+	if idle := app.bannerIdleTimeout(); idle != 5*time.Minute {
+		t.Errorf("bannerIdleTimeout() = %v, want 5m", idle)
+	}
+
+	// Without widgets there is no banner to show when idle
+	if idle := NewApplication(nil).bannerIdleTimeout(); idle != 0 {
+		t.Errorf("bannerIdleTimeout() without banner = %v, want 0", idle)
+	}
+}
+
+func TestShiftEventTimes_PreviewAndUndo(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "shift_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	manager := events.NewManagerWithConfig(&config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")})
+	day := time.Date(2025, 3, 30, 0, 0, 0, 0, time.Local)
+	if err := manager.AddEvent(day, "09:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	undoFile := filepath.Join(tempDir, timeShiftUndoFile)
+
+	// Declining the preview changes nothing
+	var out bytes.Buffer
+	shifted, err := shiftEventTimes(manager, "2025-03-30", "", time.Hour, undoFile, strings.NewReader("n\n"), &out)
+	if err != nil || shifted != 0 {
+		t.Fatalf("shiftEventTimes() declined = %d, %v; want 0, nil", shifted, err)
+	}
+	if !strings.Contains(out.String(), "2025-03-30 09:00 -> 2025-03-30 10:00  Standup") {
+		t.Errorf("Preview = %q, want the planned shift", out.String())
+	}
+
+	shifted, err = shiftEventTimes(manager, "2025-03-30", "2025-03-31", time.Hour, undoFile, strings.NewReader("y\n"), &out)
+	if err != nil || shifted != 1 {
+		t.Fatalf("shiftEventTimes() = %d, %v; want 1, nil", shifted, err)
+	}
+	if got := manager.GetEventsForDate(day)[0].GetTimeString(); got != "10:00" {
+		t.Errorf("Time after shift = %s, want 10:00", got)
+	}
+
+	reverted, err := undoTimeShift(manager, undoFile, strings.NewReader("yes\n"), &out)
+	if err != nil || reverted != 1 {
+		t.Fatalf("undoTimeShift() = %d, %v; want 1, nil", reverted, err)
+	}
+	if got := manager.GetEventsForDate(day)[0].GetTimeString(); got != "09:00" {
+		t.Errorf("Time after undo = %s, want 09:00", got)
+	}
+	if _, err := undoTimeShift(manager, undoFile, strings.NewReader("y\n"), &out); err == nil {
+		t.Error("undoTimeShift() should fail once the shift was reverted")
+	}
+
+	// Invalid ranges are rejected before anything is shown
+	if _, err := shiftEventTimes(manager, "2025-03-30", "2025-03-01", time.Hour, undoFile, strings.NewReader("y\n"), &out); err == nil {
+		t.Error("shiftEventTimes() should reject an end date before the start date")
+	}
+	if _, err := shiftEventTimes(manager, "", "", time.Hour, undoFile, strings.NewReader("y\n"), &out); err == nil {
+		t.Error("shiftEventTimes() should require a start date")
+	}
+}
+
+func TestExportWeekPlanner(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "export_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	manager := events.NewManagerWithConfig(&config.Config{Ephemeral: true})
+	if err := manager.AddEvent(time.Date(2025, 8, 13, 0, 0, 0, 0, time.Local), "10:00", "Review"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if err := manager.AddEvent(time.Date(2025, 8, 18, 0, 0, 0, 0, time.Local), "10:00", "Retro"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+
+	out := filepath.Join(tempDir, "week.txt")
+	today := time.Date(2025, 8, 15, 12, 0, 0, 0, time.Local)
+	path, err := exportWeekPlanner(manager, "today", out, 1, locale.Formatter{}, today)
+	if err != nil || path != out {
+		t.Fatalf("exportWeekPlanner() = %q, %v; want %q", path, err, out)
+	}
+	page, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Failed to read the planner page: %v", err)
+	}
+	if !strings.Contains(string(page), "Mo 2025-08-11 - Su 2025-08-17") || !strings.Contains(string(page), "10:00 Review") {
+		t.Errorf("Planner page misses the week or its event:\n%s", page)
+	}
+	if strings.Contains(string(page), "Retro") {
+		t.Errorf("Planner page shows an event of the following week:\n%s", page)
+	}
+
+	if _, err := exportWeekPlanner(manager, "someday", out, 1, locale.Formatter{}, today); err == nil {
+		t.Error("exportWeekPlanner() should reject an unknown date")
+	}
+}
+
+func TestExportICS(t *testing.T) {
+	manager := events.NewManagerWithConfig(&config.Config{Ephemeral: true})
+	for _, description := range []string{"Review", "Gym", "Review follow-up"} {
+		if err := manager.AddEvent(time.Date(2025, 8, 14, 0, 0, 0, 0, time.Local), "10:00", description); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "review.ics")
+	exported, err := exportICS(manager, path, "review", time.Local)
+	if err != nil || exported != 2 {
+		t.Fatalf("exportICS() = %d, %v; want 2 events", exported, err)
+	}
+	imported, err := storage.LoadICSFile(path)
+	if err != nil {
+		t.Fatalf("LoadICSFile() failed: %v", err)
+	}
+	if len(imported) != 2 || imported[0].Description != "Review" || imported[1].Description != "Review follow-up" {
+		t.Errorf("Exported file holds %v, want the two review events", imported)
+	}
+
+	if exported, err := exportICS(manager, path, "", time.Local); err != nil || exported != 3 {
+		t.Errorf("exportICS() without a query = %d, %v; want all 3 events", exported, err)
+	}
+}
+
+func TestNewShareSnapshot(t *testing.T) {
+	manager := events.NewManagerWithConfig(&config.Config{Ephemeral: true})
+	for _, day := range []int{14, 16, 18} {
+		if err := manager.AddEvent(time.Date(2025, 8, day, 0, 0, 0, 0, time.Local), "10:00", "Review"); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+	}
+
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.Local)
+	snapshot, err := newShareSnapshot(manager, "today", 3, 30, true, now)
+	if err != nil {
+		t.Fatalf("newShareSnapshot() failed: %v", err)
+	}
+	if !snapshot.To.Equal(time.Date(2025, 8, 17, 0, 0, 0, 0, time.Local)) || len(snapshot.Events) != 1 {
+		t.Errorf("Snapshot covers %v - %v with %d events, want Aug 15 - Aug 17 with 1", snapshot.From, snapshot.To, len(snapshot.Events))
+	}
+	if snapshot.Events[0].Description != share.BusyDescription {
+		t.Errorf("Busy-only snapshot shows %q", snapshot.Events[0].Description)
+	}
+
+	if _, err := newShareSnapshot(manager, "someday", 3, 30, false, now); err == nil {
+		t.Error("newShareSnapshot() should reject an unknown date")
+	}
+	if _, err := newShareSnapshot(manager, "today", 0, 30, false, now); err == nil {
+		t.Error("newShareSnapshot() should reject an empty range")
+	}
+}
+
+func TestShareHandler_Auth(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{EventsFilePath: filepath.Join(dir, "events.json"), ShareAuth: true}
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.Local)
+	snapshot := share.NewSnapshot(now, now.AddDate(0, 0, 2), nil, true, now, time.Hour)
+
+	if _, err := shareHandler(snapshot, "link", cfg); err == nil {
+		t.Fatal("shareHandler() with -share-auth should fail without access tokens")
+	}
+
+	store := apitoken.NewStore(dir)
+	_, readSecret, err := store.Create("dashboard", apitoken.ScopeRead, nil, now)
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	_, otherSecret, err := store.Create("phone", apitoken.Scope("write"), nil, now)
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	handler, err := shareHandler(snapshot, "link", cfg)
+	if err != nil {
+		t.Fatalf("shareHandler() failed: %v", err)
+	}
+	for _, tt := range []struct {
+		secret string
+		want   int
+	}{
+		{"", http.StatusUnauthorized},
+		{otherSecret, http.StatusForbidden},
+		{readSecret, http.StatusOK},
+	} {
+		request := httptest.NewRequest(http.MethodGet, "/link/calendar.ics", nil)
+		if tt.secret != "" {
+			request.Header.Set("Authorization", "Bearer "+tt.secret)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != tt.want {
+			t.Errorf("Request with secret %q = %d, want %d", tt.secret, recorder.Code, tt.want)
+		}
+	}
+
+	// A browser opening the link sends the token in the query, which the page keeps on
+	// its link to calendar.ics
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/link/?access_token="+readSecret, nil))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `href="calendar.ics?access_token=`+readSecret+`"`) {
+		t.Errorf("Page opened with the token in the query = %d:\n%s", recorder.Code, recorder.Body.String())
+	}
+	for secret, want := range map[string]int{readSecret: http.StatusOK, otherSecret: http.StatusForbidden, "nope": http.StatusUnauthorized} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/link/calendar.ics?access_token="+secret, nil))
+		if recorder.Code != want {
+			t.Errorf("calendar.ics with secret %q in the query = %d, want %d", secret, recorder.Code, want)
+		}
+	}
+}
+
+func TestAddEventLines(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "add_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{EventsFilePath: filepath.Join(tempDir, "events.json")}
+	manager := events.NewManagerWithConfig(cfg)
+
+	input := "2025-12-24 18:00 Christmas dinner\n\n# comment\ntomorrow 09:00 Dentist\nnot an event\n"
+	today := time.Date(2025, 8, 15, 12, 0, 0, 0, time.Local)
+
+	var out bytes.Buffer
+	added, failed, err := addEventLines(manager, strings.NewReader(input), &out, today)
+	if err != nil {
+		t.Fatalf("addEventLines() failed: %v", err)
+	}
+	if added != 2 || failed != 1 {
+		t.Errorf("addEventLines() = %d added, %d failed, want 2 and 1", added, failed)
+	}
+	if !strings.Contains(out.String(), "line 5: error") {
+		t.Errorf("Output should report the failing line, got %q", out.String())
+	}
+
+	dentist := manager.GetEventsForDate(time.Date(2025, 8, 16, 0, 0, 0, 0, time.Local))
+	if len(dentist) != 1 || dentist[0].Description != "Dentist" {
+		t.Errorf("Events on 2025-08-16 = %v, want the dentist appointment", dentist)
+	}
+}
+
+func TestRunTokenCommand(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+	err := runTokenCommand(apitoken.NewStore(dir), []string{"create", "--scope", "read", "--allow", "192.168.1.0/24", "--name", "phone shortcut"}, &out, now)
+	if err != nil {
+		t.Fatalf("token create failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	secret := lines[len(lines)-1]
+
+	store := apitoken.NewStore(dir)
+	if err := store.Load(); err != nil {
+		t.Fatal(err)
+	}
+	token, ok := store.Lookup(secret)
+	if !ok || token.Scope != apitoken.ScopeRead || token.Name != "phone shortcut" || len(token.Allow) != 1 {
+		t.Fatalf("Created token = %+v, %v; want the read token of the phone shortcut", token, ok)
+	}
+	if err := runTokenCommand(apitoken.NewStore(dir), []string{"create", "--scope", "full"}, &out, now); err == nil {
+		t.Error("token create --scope full should fail: no endpoint checks such a scope")
+	}
+
+	out.Reset()
+	if err := runTokenCommand(apitoken.NewStore(dir), []string{"list"}, &out, now); err != nil {
+		t.Fatalf("token list failed: %v", err)
+	}
+	if !strings.Contains(out.String(), token.ID) || strings.Contains(out.String(), secret) {
+		t.Errorf("token list = %q, want the token ID without its secret", out.String())
+	}
+
+	for _, args := range [][]string{{"create", "--scope", "admin"}, {"create", "--allow", "example.com"}, {"revoke", "missing"}, {"rotate"}} {
+		if err := runTokenCommand(apitoken.NewStore(dir), args, io.Discard, now); err == nil {
+			t.Errorf("token %v should fail", args)
+		}
+	}
+
+	if err := runTokenCommand(apitoken.NewStore(dir), []string{"revoke", token.ID}, io.Discard, now); err != nil {
+		t.Fatalf("token revoke failed: %v", err)
+	}
+	if err := store.Load(); err != nil || len(store.Tokens()) != 0 {
+		t.Errorf("Tokens after revoke = %v (%v), want none", store.Tokens(), err)
+	}
+}
+
+func TestApplication_PasteEvents(t *testing.T) {
+	app := NewApplication(&config.Config{Ephemeral: true, ClipboardPasteCmd: "printf 'fri 18:00 Dinner\\nnonsense\\n+1d 09:00 Dentist\\n'"})
+	if err := app.events.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	date := time.Date(2030, 5, 14, 0, 0, 0, 0, time.Local) // A Tuesday
+	app.navigation.JumpToDate(date)
+
+	app.handleAction(terminal.ActionPasteEvents)
+	if app.state != StatePaste || len(app.pasteLines) != 3 {
+		t.Fatalf("Paste opened in state %v with %d lines; want the paste review of 3 lines", app.state, len(app.pasteLines))
+	}
+	if app.pasteLines[1].Err == nil || app.pasteLines[1].Accepted {
+		t.Errorf("Unreadable line = %+v, want it rejected with an error", app.pasteLines[1])
+	}
+
+	// X rejects the dentist; the unreadable line cannot be accepted
+	app.handleAction(terminal.ActionMoveDown)
+	app.handleAction(terminal.ActionTogglePasteLine)
+	if app.pasteLines[1].Accepted {
+		t.Error("X accepted a line that cannot be read")
+	}
+	app.handleAction(terminal.ActionMoveDown)
+	app.handleAction(terminal.ActionTogglePasteLine)
+
+	app.handleAction(terminal.ActionShowEvents)
+	if app.state != StateCalendar {
+		t.Errorf("State after Enter = %v, want calendar", app.state)
+	}
+	if events := app.events.GetEventsForDate(date.AddDate(0, 0, 3)); len(events) != 1 || events[0].Description != "Dinner" {
+		t.Errorf("Events on Friday = %v, want the pasted dinner", events)
+	}
+	if events := app.events.GetEventsForDate(date.AddDate(0, 0, 1)); len(events) != 0 {
+		t.Errorf("Events on Wednesday = %v, want the rejected dentist left out", events)
+	}
+}
+
+func TestApplication_FocusMode(t *testing.T) {
+	app := NewApplication(&config.Config{Ephemeral: true, FocusMode: true})
+	if !app.renderer.Focus() {
+		t.Fatal("focus_mode should start the calendar in focus mode")
+	}
+
+	app.handleAction(terminal.ActionToggleFocus)
+	if app.renderer.Focus() {
+		t.Error("Z should leave focus mode")
+	}
+	app.handleAction(terminal.ActionToggleFocus)
+	app.startTour()
+	if app.renderer.Focus() {
+		t.Error("The tour should leave focus mode to show the legend")
+	}
+}
+
+func TestApplication_ExitWarning(t *testing.T) {
+	app := NewApplication(&config.Config{Ephemeral: true})
+	if err := app.events.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	if warning := app.exitWarning(); warning != "" {
+		t.Errorf("exitWarning() in a clean session = %q, want none", warning)
+	}
+
+	app.state = StateCalendarEventAdd
+	if warning := app.exitWarning(); !strings.Contains(warning, "new event") {
+		t.Errorf("exitWarning() while adding = %q, want the new event warning", warning)
+	}
+	app.state = StateCalendar
+
+	if err := app.events.AddEvent(time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local), "09:00", "Demo"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if warning := app.exitWarning(); !strings.Contains(warning, "discarded") {
+		t.Errorf("exitWarning() with in-memory changes = %q, want the discard warning", warning)
+	}
+}
+
+func TestApplication_UnsavedWrites(t *testing.T) {
+	dir := t.TempDir()
+	// A file where the data directory should be makes every write fail
+	dataDir := filepath.Join(dir, "mount")
+	if err := os.WriteFile(dataDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	emergency := filepath.Join(dir, "unsaved.json")
+	app := NewApplication(&config.Config{EventsFilePath: filepath.Join(dataDir, "events.json"), EmergencyFilePath: emergency})
+	app.events.EnableWriteRecovery()
+
+	if err := app.events.AddEvent(time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local), "09:00", "Standup"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if status := app.statusText(); !strings.Contains(status, "UNSAVED since") {
+		t.Errorf("statusText() = %q, want the unsaved state", status)
+	}
+	if warning := app.exitWarning(); !strings.Contains(warning, emergency) {
+		t.Errorf("exitWarning() = %q, want the emergency file named", warning)
+	}
+
+	// Exiting while the events file still cannot be written leaves a copy behind
+	app.saveUnsaved()
+	copied, err := storage.LoadEventsJSON(emergency)
+	if err != nil || len(copied) != 1 || copied[0].Description != "Standup" {
+		t.Errorf("Emergency copy = %v (%v), want the unsaved event", copied, err)
+	}
+}
+
+func TestApplication_Screensaver(t *testing.T) {
+	app := NewApplication(&config.Config{Ephemeral: true, ScreensaverMinutes: 10})
+	if got := app.screensaverTimeout(); got != 10*time.Minute {
+		t.Errorf("screensaverTimeout() = %v, want 10m", got)
+	}
+
+	app.state = StateFollowUps
+	app.selectedFollowUpIndex = 2
+	app.startScreensaver()
+	if app.state != StateScreensaver {
+		t.Fatalf("state = %v, want the screensaver", app.state)
+	}
+	app.stopScreensaver()
+	if app.state != StateFollowUps || app.selectedFollowUpIndex != 2 {
+		t.Errorf("After the screensaver state = %v with follow-up %d, want the follow-up list as it was", app.state, app.selectedFollowUpIndex)
+	}
+
+	if got := NewApplication(&config.Config{Ephemeral: true}).screensaverTimeout(); got != 0 {
+		t.Errorf("screensaverTimeout() without configuration = %v, want 0", got)
+	}
+}
+
+func TestApplication_QuickAddDate(t *testing.T) {
+	app := NewApplication(&config.Config{Ephemeral: true})
+	if err := app.events.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	selected := app.navigation.GetCurrentSelection()
+
+	app.state = StateSearch
+	if date := app.quickAddDate(); !calendar.IsSameDate(date, selected) {
+		t.Errorf("quickAddDate() without results = %v, want the selected date", date)
+	}
+	resultDate := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	app.searchResults = []models.Event{{Date: resultDate.AddDate(0, 0, -1)}, {Date: resultDate}}
+	app.selectedResultIndex = 1
+	if date := app.quickAddDate(); !calendar.IsSameDate(date, resultDate) {
+		t.Errorf("quickAddDate() in search = %v, want the selected result's date", date)
+	}
+
+	app.state = StateFollowUps
+	if err := app.events.AddEvent(resultDate, "10:00", "Call back"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	if _, err := app.events.ToggleFlag(app.events.GetEventsForDate(resultDate)[0]); err != nil {
+		t.Fatalf("ToggleFlag() failed: %v", err)
+	}
+	if date := app.quickAddDate(); !calendar.IsSameDate(date, resultDate) {
+		t.Errorf("quickAddDate() in follow-ups = %v, want the flagged event's date", date)
+	}
+
+	app.state = StateBanner
+	if date := app.quickAddDate(); !calendar.IsSameDate(date, time.Now()) {
+		t.Errorf("quickAddDate() on the banner = %v, want today", date)
+	}
+
+	app.state = StateStats
+	if date := app.quickAddDate(); !calendar.IsSameDate(date, selected) {
+		t.Errorf("quickAddDate() in statistics = %v, want the selected date", date)
+	}
+}
+
+func TestApplication_EditFieldError(t *testing.T) {
+	app := NewApplication(&config.Config{Ephemeral: true})
+
+	tests := []struct {
+		name        string
+		timeStr     string
+		description string
+		wantField   int
+		wantError   bool
+	}{
+		{"Valid", "09:30", "Standup", editFieldDescription, false},
+		{"Bad time", "25:00", "Standup", editFieldTime, true},
+		{"Bad time and description", "9", "", editFieldTime, true},
+		{"Empty description", "09:30", "   ", editFieldDescription, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, message := app.editFieldError(tt.timeStr, tt.description)
+			if field != tt.wantField || (message != "") != tt.wantError {
+				t.Errorf("editFieldError(%q, %q) = %d, %q; want field %d, error %v", tt.timeStr, tt.description, field, message, tt.wantField, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestApplication_Reminders(t *testing.T) {
+	app := NewApplication(&config.Config{Ephemeral: true})
+	if err := app.events.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	day := time.Date(2030, 5, 14, 0, 0, 0, 0, time.Local)
+	for _, e := range [][2]string{{"09:00", "Standup"}, {"09:30", "Review"}} {
+		if err := app.events.AddEvent(day, e[0], e[1]); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+	}
+	for _, event := range app.events.GetEventsForDate(day) {
+		if _, err := app.events.SetEventReminder(event, time.Hour); err != nil {
+			t.Fatalf("SetEventReminder() failed: %v", err)
+		}
+	}
+	defer func() {
+		if app.reminderTimer != nil {
+			app.reminderTimer.Stop()
+		}
+	}()
+
+	if app.checkReminders(day.Add(7*time.Hour)) || len(app.dueReminders) != 0 || app.reminderTimer == nil {
+		t.Fatalf("Before the reminders %d are due, want none and a timer for the first", len(app.dueReminders))
+	}
+	now := day.Add(8*time.Hour + 45*time.Minute)
+	if !app.checkReminders(now) {
+		t.Error("checkReminders() should announce the reminders coming due")
+	}
+	if got, want := reminderText(app.dueReminders, now), "Reminder: 09:00 Standup in 15 min (+1 more)"; got != want {
+		t.Errorf("reminderText() = %q, want %q", got, want)
+	}
+	if app.checkReminders(now.Add(time.Minute)) {
+		t.Error("checkReminders() should announce each reminder once")
+	}
+	if app.checkReminders(day.Add(9*time.Hour)) || len(app.dueReminders) != 1 || app.dueReminders[0].Description != "Review" {
+		t.Errorf("Once the standup started the due reminders = %v, want the review only", app.dueReminders)
+	}
+}
+
+func TestParseReminder(t *testing.T) {
+	tests := map[string]time.Duration{"15": 15 * time.Minute, " 0 ": 0, "1h30m": 90 * time.Minute, "2h": 2 * time.Hour}
+	for input, want := range tests {
+		if got, err := parseReminder(input); err != nil || got != want {
+			t.Errorf("parseReminder(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"-5", "soon", "30s", ""} {
+		if _, err := parseReminder(input); err == nil {
+			t.Errorf("parseReminder(%q) should fail", input)
+		}
+	}
+}
+
+func TestEditedTimeRange(t *testing.T) {
+	start, _ := calendar.ParseTime("09:00")
+	meeting := models.Event{Time: start, Duration: 90 * time.Minute}
+	trip := models.Event{Time: start, Duration: 48 * time.Hour}
+	if got := editTimeDefault(meeting); got != "09:00-10:30" {
+		t.Errorf("editTimeDefault(meeting) = %q, want 09:00-10:30", got)
+	}
+	if got := editTimeDefault(trip); got != "09:00" {
+		t.Errorf("editTimeDefault(trip) = %q, want 09:00", got)
+	}
+
+	tests := []struct {
+		event models.Event
+		input string
+		start string
+		want  time.Duration
+	}{
+		{meeting, "10:00-11:00", "10:00", time.Hour},
+		{meeting, "10:00", "10:00", 0},
+		{trip, "10:00", "10:00", 48 * time.Hour},
+		{trip, "10:00-12:00", "10:00", 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		if start, duration := editedTimeRange(tt.event, tt.input); start != tt.start || duration != tt.want {
+			t.Errorf("editedTimeRange(%v, %q) = %s, %v; want %s, %v", tt.event.Duration, tt.input, start, duration, tt.start, tt.want)
+		}
+	}
+}
+
+func TestDeleteSummary(t *testing.T) {
+	date := time.Date(2025, 8, 20, 0, 0, 0, 0, time.Local)
+	event := models.Event{Date: date, Time: time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC), Description: "Standup"}
+	if got, want := deleteSummary("Delete event", event), "Delete event: 09:30 - Standup"; got != want {
+		t.Errorf("deleteSummary() = %q, want %q", got, want)
+	}
+
+	trip := models.Event{Date: date, Description: "Trip", AllDay: true, EndDate: date.AddDate(0, 0, 8), Notes: "Flight AB123\nHotel booked"}
+	want := "Delete event: all day - Trip, 9 days from Aug 20 to Aug 28, with 2 lines of notes"
+	if got := deleteSummary("Delete event", trip); got != want {
+		t.Errorf("deleteSummary() = %q, want %q", got, want)
+	}
+	if days := eventDays(trip); days <= deleteTypedDays {
+		t.Errorf("eventDays() = %d, want more than %d so deleting takes typing yes", days, deleteTypedDays)
+	}
+}
+
+// newHeadlessApp returns an application drawing on a headless screen, whose prompts read
+// the keys typed on the screen
+func newHeadlessApp(cfg *config.Config) (*Application, *terminal.HeadlessScreen) {
+	app := NewApplication(cfg)
+	screen := terminal.NewHeadlessScreen(100, 30)
+	term := terminal.NewHeadlessTerminal(screen)
+	app.terminal = term
+	app.renderer = terminal.NewRenderer(term, app.events, cfg)
+	app.renderer.SetStatus(app.statusText)
+	app.input = terminal.NewInputHandler(term)
+	return app, screen
+}
+
+func TestApplication_MoveEvent(t *testing.T) {
+	date := time.Date(2030, 5, 14, 0, 0, 0, 0, time.Local)
+	newApp := func(t *testing.T, keys string) (*Application, *terminal.HeadlessScreen) {
+		app, screen := newHeadlessApp(&config.Config{Ephemeral: true})
+		app.navigation.JumpToDate(date)
+		if err := app.events.AddEvent(date, "09:00", "Dentist"); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+		app.state = StateEventList
+		screen.Type(keys)
+		return app, screen
+	}
+	dentistOn := func(app *Application, day time.Time) bool {
+		for _, event := range app.events.GetEventsForDate(day) {
+			if event.Description == "Dentist" {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("moves to the typed date", func(t *testing.T) {
+		app, _ := newApp(t, "+2d\n")
+		app.handleAction(terminal.ActionMoveEvent)
+		if dentistOn(app, date) || !dentistOn(app, date.AddDate(0, 0, 2)) {
+			t.Fatal("Shift+M with +2d should move the event two days later")
+		}
+		if message, _ := app.messages.Current(time.Now()); !strings.HasPrefix(message.Text, `Moved "Dentist"`) {
+			t.Errorf("Message = %q, want the move confirmed", message.Text)
+		}
+	})
+
+	t.Run("keeps asking after a date that does not parse", func(t *testing.T) {
+		app, screen := newApp(t, "someday\n")
+		app.handleAction(terminal.ActionMoveEvent)
+		if !dentistOn(app, date) {
+			t.Fatal("An invalid date should leave the event where it is")
+		}
+		if !strings.Contains(screen.Text(), "Invalid date") {
+			t.Errorf("The prompt should show why the date was rejected:\n%s", screen.Text())
+		}
+
+		// A valid date typed after the error moves the event
+		app, _ = newApp(t, "someday\n"+strings.Repeat("\x7f", len("someday"))+"+1d\n")
+		app.handleAction(terminal.ActionMoveEvent)
+		if !dentistOn(app, date.AddDate(0, 0, 1)) {
+			t.Error("A valid date typed after an invalid one should move the event")
+		}
+	})
+
+	t.Run("refuses a date with an identical event", func(t *testing.T) {
+		app, _ := newApp(t, "tomorrow\n")
+		if err := app.events.AddEvent(date.AddDate(0, 0, 1), "09:00", "Dentist"); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+		app.handleAction(terminal.ActionMoveEvent)
+		if !dentistOn(app, date) || len(app.events.GetEventsForDate(date.AddDate(0, 0, 1))) != 1 {
+			t.Error("Moving onto an identical event should leave both events as they were")
+		}
+		if history := app.messages.History(); len(history) == 0 || !strings.Contains(history[0].Text, "identical event") {
+			t.Errorf("Messages = %+v, want the clash reported", history)
+		}
+	})
+
+	t.Run("names the events a move overlaps", func(t *testing.T) {
+		app, _ := newApp(t, "tomorrow\n")
+		if err := app.events.AddEventWithDuration(date.AddDate(0, 0, 1), "08:30", "Standup", time.Hour); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+		app.handleAction(terminal.ActionMoveEvent)
+		if message, _ := app.messages.Current(time.Now()); !dentistOn(app, date.AddDate(0, 0, 1)) || !strings.Contains(message.Text, "overlaps 08:30 Standup") {
+			t.Errorf("Message = %q, want the move with the overlapping standup named", message.Text)
+		}
+	})
+
+	t.Run("the edit form moves the event with its date field", func(t *testing.T) {
+		app, _ := newApp(t, "\n\ntomorrow\n") // Keep the time and description
+		note, saved := app.editEventInline(app.events.GetEventsForDate(date)[0], date, 2, 6)
+		if !saved || !dentistOn(app, date.AddDate(0, 0, 1)) || !strings.Contains(note, "Moved to") {
+			t.Errorf("editEventInline() = %q, %v; want the event moved to the next day", note, saved)
+		}
+
+		app, screen := newApp(t, "\n\nsomeday\n")
+		if _, saved := app.editEventInline(app.events.GetEventsForDate(date)[0], date, 2, 6); saved || !dentistOn(app, date) {
+			t.Error("editEventInline() with a date that does not parse should not save")
+		}
+		if !strings.Contains(screen.Text(), "Invalid date") {
+			t.Errorf("The form should show why the date was rejected:\n%s", screen.Text())
+		}
+
+		app, screen = newApp(t, "\n\ntomorrow\n")
+		if err := app.events.AddEvent(date.AddDate(0, 0, 1), "09:00", "Dentist"); err != nil {
+			t.Fatalf("AddEvent() failed: %v", err)
+		}
+		if _, saved := app.editEventInline(app.events.GetEventsForDate(date)[0], date, 2, 6); saved || !dentistOn(app, date) {
+			t.Error("editEventInline() onto an identical event should not save")
+		}
+		if !strings.Contains(screen.Text(), "identical event") {
+			t.Errorf("The form should show the clash:\n%s", screen.Text())
+		}
+	})
+
+	t.Run("Shift+M in the calendar moves the only event of the date", func(t *testing.T) {
+		app, _ := newApp(t, "+1w\n")
+		app.state = StateCalendar
+		app.handleAction(terminal.ActionMoveEvent)
+		if !dentistOn(app, date.AddDate(0, 0, 7)) || app.state != StateCalendar {
+			t.Errorf("Shift+M in the calendar should move the event a week later and stay in the calendar, state %v", app.state)
+		}
+	})
+}
+
+func TestApplication_PromptEventDate(t *testing.T) {
+	selected := time.Date(2030, 5, 14, 0, 0, 0, 0, time.Local) // A Tuesday
+	prompt := func(keys string) (*Application, *terminal.HeadlessScreen, time.Time, bool) {
+		app, screen := newHeadlessApp(&config.Config{Ephemeral: true})
+		app.navigation.JumpToDate(selected)
+		screen.Type(keys)
+		date, ok := app.promptEventDate(1, 20, selected, "09:00", "Dentist")
+		return app, screen, date, ok
+	}
+
+	t.Run("empty keeps the selected date", func(t *testing.T) {
+		if _, _, date, ok := prompt("\n"); !ok || !date.Equal(selected) {
+			t.Errorf("promptEventDate() = %v, %v; want the selected date", date, ok)
+		}
+	})
+
+	t.Run("relative date is confirmed first", func(t *testing.T) {
+		_, screen, date, ok := prompt("+2d\n")
+		if !strings.Contains(screen.Text(), "Add 09:00 - Dentist on Thu 2030-05-16?") {
+			t.Errorf("The prompt should preview the resolved date:\n%s", screen.Text())
+		}
+
+		// Esc at the preview goes back to the date, the queue then cancels it
+		if ok {
+			t.Errorf("promptEventDate() = %v, true; want it cancelled without a confirmation", date)
+		}
+		if _, _, date, ok := prompt("+2d\n\n"); !ok || !date.Equal(selected.AddDate(0, 0, 2)) {
+			t.Errorf("promptEventDate() = %v, %v; want two days after the selected date", date, ok)
+		}
+	})
+
+	t.Run("date that does not parse is asked again", func(t *testing.T) {
+		app, _, date, ok := prompt("someday\n" + strings.Repeat("\x7f", len("someday")) + "fri\n\n")
+		if !ok || !date.Equal(selected.AddDate(0, 0, 3)) {
+			t.Errorf("promptEventDate() = %v, %v; want the Friday typed after the error", date, ok)
+		}
+		if history := app.messages.History(); len(history) == 0 || !strings.HasPrefix(history[len(history)-1].Text, "Invalid date") {
+			t.Errorf("Messages = %+v, want the invalid date reported", history)
+		}
+	})
+
+	t.Run("Esc cancels", func(t *testing.T) {
+		if _, _, _, ok := prompt("+1d\x1b"); ok {
+			t.Error("Esc at the date prompt should cancel")
+		}
+	})
+}
@@ -0,0 +1,126 @@
+// Package messages keeps the messages shown on the status line: each stays on screen
+// for a while instead of until the next redraw, messages posted in a burst are shown
+// one after another, and the latest ones are kept for a history view.
+package messages
+
+import "time"
+
+// Severity tells how a message is colored and how long it stays on screen
+type Severity int
+
+const (
+	Success Severity = iota // A change went through, e.g. "Event added"
+	Warning                 // Worth a look although nothing failed, e.g. a large events file
+	Error                   // Something failed
+)
+
+// String returns the label of the severity in the history view
+func (s Severity) String() string {
+	switch s {
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return "ok"
+	}
+}
+
+const (
+	// ShowFor is how long a message stays on the status line
+	ShowFor = 4 * time.Second
+	// ShowErrorsFor is how long an error stays, long enough to read it in full
+	ShowErrorsFor = 8 * time.Second
+	// MinShowFor is how long a message stays at least before a queued one replaces it
+	MinShowFor = time.Second
+	// HistorySize is the number of messages kept for the history view
+	HistorySize = 50
+)
+
+// Message is a message posted to the status line
+type Message struct {
+	Text     string
+	Severity Severity
+	Posted   time.Time
+}
+
+// showFor returns how long the message stays on screen when no other one is waiting
+func (m Message) showFor() time.Duration {
+	if m.Severity == Error {
+		return ShowErrorsFor
+	}
+	return ShowFor
+}
+
+// Queue holds the messages waiting for the status line, the first of which is shown,
+// and the history of all messages posted
+type Queue struct {
+	pending []Message
+	shownAt time.Time // When the first pending message went on screen
+	history []Message // Oldest first, at most HistorySize
+}
+
+// NewQueue creates an empty queue
+func NewQueue() *Queue {
+	return &Queue{}
+}
+
+// Post adds a message to show after those already waiting
+func (q *Queue) Post(text string, severity Severity, now time.Time) {
+	q.advance(now)
+	message := Message{Text: text, Severity: severity, Posted: now}
+	if len(q.pending) == 0 {
+		q.shownAt = now
+	}
+	q.pending = append(q.pending, message)
+
+	q.history = append(q.history, message)
+	if len(q.history) > HistorySize {
+		q.history = append([]Message(nil), q.history[len(q.history)-HistorySize:]...)
+	}
+}
+
+// Current returns the message to show at now, if any
+func (q *Queue) Current(now time.Time) (Message, bool) {
+	q.advance(now)
+	if len(q.pending) == 0 {
+		return Message{}, false
+	}
+	return q.pending[0], true
+}
+
+// NextChange returns when the shown message expires or makes way for the next one, so
+// the status line can be redrawn then; false when no message is shown
+func (q *Queue) NextChange(now time.Time) (time.Time, bool) {
+	q.advance(now)
+	if len(q.pending) == 0 {
+		return time.Time{}, false
+	}
+	return q.until(), true
+}
+
+// until returns when the shown message leaves the screen
+func (q *Queue) until() time.Time {
+	if len(q.pending) > 1 {
+		return q.shownAt.Add(MinShowFor)
+	}
+	return q.shownAt.Add(q.pending[0].showFor())
+}
+
+// advance drops the shown message once its time is up; the next one is shown from now,
+// as it may have waited for the screen to be redrawn
+func (q *Queue) advance(now time.Time) {
+	for len(q.pending) > 0 && !now.Before(q.until()) {
+		q.pending = q.pending[1:]
+		q.shownAt = now
+	}
+}
+
+// History returns the messages posted, most recent first
+func (q *Queue) History() []Message {
+	history := make([]Message, len(q.history))
+	for i, message := range q.history {
+		history[len(q.history)-1-i] = message
+	}
+	return history
+}
@@ -0,0 +1,82 @@
+package messages
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestQueue_ShowsMessagesInTurn(t *testing.T) {
+	queue := NewQueue()
+	start := time.Date(2025, 8, 15, 9, 0, 0, 0, time.UTC)
+
+	if _, ok := queue.Current(start); ok {
+		t.Fatal("An empty queue should show no message")
+	}
+
+	queue.Post("Event added", Success, start)
+	queue.Post("Cannot sync", Error, start.Add(100*time.Millisecond))
+
+	// The first message stays until the next one has waited for its minimum time
+	if message, ok := queue.Current(start.Add(500 * time.Millisecond)); !ok || message.Text != "Event added" {
+		t.Errorf("Current() = %q, %v, want the first message", message.Text, ok)
+	}
+	if until, _ := queue.NextChange(start); !until.Equal(start.Add(MinShowFor)) {
+		t.Errorf("NextChange() = %v, want after the minimum time", until)
+	}
+
+	// The error then takes over and stays for its own, longer time
+	next := start.Add(MinShowFor)
+	if message, ok := queue.Current(next); !ok || message.Text != "Cannot sync" || message.Severity != Error {
+		t.Errorf("Current() = %+v, %v, want the error", message, ok)
+	}
+	if message, ok := queue.Current(next.Add(ShowErrorsFor - time.Millisecond)); !ok || message.Text != "Cannot sync" {
+		t.Errorf("Current() before the error expired = %q, %v, want the error", message.Text, ok)
+	}
+	if _, ok := queue.Current(next.Add(ShowErrorsFor)); ok {
+		t.Error("The error should be gone once its time is up")
+	}
+	if _, ok := queue.NextChange(next.Add(ShowErrorsFor)); ok {
+		t.Error("NextChange() should report nothing once all messages are gone")
+	}
+}
+
+func TestQueue_MessageShownAfterWaiting(t *testing.T) {
+	queue := NewQueue()
+	start := time.Date(2025, 8, 15, 9, 0, 0, 0, time.UTC)
+	queue.Post("Syncing", Success, start)
+	queue.Post("Synced", Success, start)
+
+	// A redraw long after the first one expired still shows the second in full
+	late := start.Add(time.Minute)
+	if message, ok := queue.Current(late); !ok || message.Text != "Synced" {
+		t.Errorf("Current() = %q, %v, want the waiting message", message.Text, ok)
+	}
+	if until, _ := queue.NextChange(late); !until.Equal(late.Add(ShowFor)) {
+		t.Errorf("NextChange() = %v, want %v", until, late.Add(ShowFor))
+	}
+}
+
+func TestQueue_History(t *testing.T) {
+	queue := NewQueue()
+	start := time.Date(2025, 8, 15, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < HistorySize+5; i++ {
+		queue.Post(fmt.Sprintf("Message %d", i), Warning, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	history := queue.History()
+	if len(history) != HistorySize {
+		t.Fatalf("History() holds %d messages, want %d", len(history), HistorySize)
+	}
+	if history[0].Text != fmt.Sprintf("Message %d", HistorySize+4) || history[HistorySize-1].Text != "Message 5" {
+		t.Errorf("History() = %q ... %q, want the latest messages, most recent first", history[0].Text, history[HistorySize-1].Text)
+	}
+}
+
+func TestSeverity_String(t *testing.T) {
+	for severity, want := range map[Severity]string{Success: "ok", Warning: "warning", Error: "error"} {
+		if got := severity.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", severity, got, want)
+		}
+	}
+}
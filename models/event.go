@@ -1,14 +1,32 @@
 package models
 
 import (
+	"fmt"
 	"time"
+
+	"go-ascii-calendar/calendar"
 )
 
 // Event represents a calendar event with date, time, and description
 type Event struct {
-	Date        time.Time // The date of the event (YYYY-MM-DD)
-	Time        time.Time // The time of the event (HH:MM) - date part will be ignored
-	Description string    // The event description
+	Date        time.Time     // The date of the event (YYYY-MM-DD)
+	Time        time.Time     // The time of the event (HH:MM) - date part will be ignored
+	Description string        // The event description
+	Category    string        // Optional category name (see config categories)
+	Command     string        // Optional shell command run by the alarm daemon at event time
+	Priority    string        // Optional priority from "A" (highest) to "Z"
+	Source      string        // Identity of the entry an imported event came from, e.g. "todo:1a2b3c4d5e6f"
+	Flagged     bool          // Queued in the follow-up list for action
+	Duration    time.Duration // Optional length of the event; zero when it has no end
+	TimeChecked bool          // An unusual time or duration was confirmed and is not warned about again
+	EndDate     time.Time     // Last day of an event spanning several days; zero for a single day
+	AllDay      bool          // Takes the whole day, or each day up to EndDate, rather than starting at Time
+	Unreviewed  bool          // Arrived from an import and waits in the review queue
+	ImportBase  string        // Imported fields as last taken from the source, telling local edits from changes there
+	Conflict    *Event        // Version from the source clashing with local edits, waiting to be resolved
+	Reminder    time.Duration // How long before the start a reminder is shown; zero for none
+	Calendar    string        // Named calendar whose file holds the event (see config calendars); empty for the main events file
+	Notes       string        // Optional notes of several lines, e.g. an agenda or links
 }
 
 // GetTimeString returns the time in HH:MM format
@@ -16,6 +34,49 @@ func (e *Event) GetTimeString() string {
 	return e.Time.Format("15:04")
 }
 
+// GetTimeLabel returns "all day" for an all-day event, otherwise its time in HH:MM format
+func (e *Event) GetTimeLabel() string {
+	if e.AllDay {
+		return "all day"
+	}
+	return e.GetTimeString()
+}
+
+// GetEndTimeString returns the end time in HH:MM format, or "" for an event without
+// a duration
+func (e *Event) GetEndTimeString() string {
+	if e.Duration <= 0 {
+		return ""
+	}
+	return e.Time.Add(e.Duration).Format("15:04")
+}
+
+// Start returns the moment the event begins in loc: midnight of its date for an
+// all-day event
+func (e *Event) Start(loc *time.Location) time.Time {
+	if e.AllDay {
+		return time.Date(e.Date.Year(), e.Date.Month(), e.Date.Day(), 0, 0, 0, 0, loc)
+	}
+	return time.Date(e.Date.Year(), e.Date.Month(), e.Date.Day(), e.Time.Hour(), e.Time.Minute(), 0, 0, loc)
+}
+
+// GetReminderLabel returns the reminder offset in a short form such as "15m", "1h" or
+// "1h30m", or "" for an event without a reminder
+func (e *Event) GetReminderLabel() string {
+	if e.Reminder <= 0 {
+		return ""
+	}
+	hours, minutes := int(e.Reminder/time.Hour), int(e.Reminder%time.Hour/time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+}
+
 // GetDateString returns the date in YYYY-MM-DD format
 func (e *Event) GetDateString() string {
 	return e.Date.Format("2006-01-02")
@@ -25,3 +86,24 @@ func (e *Event) GetDateString() string {
 func (e *Event) String() string {
 	return e.GetDateString() + "|" + e.GetTimeString() + "|" + e.Description
 }
+
+// LastDate returns the last day of the event: EndDate for an event spanning several
+// days, otherwise its date
+func (e *Event) LastDate() time.Time {
+	if e.EndDate.After(e.Date) {
+		return calendar.NormalizeDate(e.EndDate)
+	}
+	return calendar.NormalizeDate(e.Date)
+}
+
+// IsMultiDay reports whether the event spans more than one day
+func (e *Event) IsMultiDay() bool {
+	return e.LastDate().After(calendar.NormalizeDate(e.Date))
+}
+
+// OccursOn reports whether date falls on the event's date or, for an event spanning
+// several days, between its date and EndDate
+func (e *Event) OccursOn(date time.Time) bool {
+	date = calendar.NormalizeDate(date)
+	return !date.Before(calendar.NormalizeDate(e.Date)) && !date.After(e.LastDate())
+}
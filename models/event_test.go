@@ -331,3 +331,42 @@ func indexOf(s, substr string) int {
 	}
 	return -1
 }
+
+func TestEvent_GetEndTimeString(t *testing.T) {
+	event := Event{Time: time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC)}
+	if got := event.GetEndTimeString(); got != "" {
+		t.Errorf("GetEndTimeString() without a duration = %q, want empty", got)
+	}
+
+	event.Duration = 90 * time.Minute
+	if got := event.GetEndTimeString(); got != "11:00" {
+		t.Errorf("GetEndTimeString() = %q, want 11:00", got)
+	}
+}
+
+func TestEvent_GetReminderLabel(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                "",
+		15 * time.Minute: "15m",
+		time.Hour:        "1h",
+		90 * time.Minute: "1h30m",
+		24 * time.Hour:   "24h",
+	}
+	for reminder, want := range tests {
+		event := Event{Reminder: reminder}
+		if got := event.GetReminderLabel(); got != want {
+			t.Errorf("GetReminderLabel() of %v = %q, want %q", reminder, got, want)
+		}
+	}
+}
+
+func TestEvent_Start(t *testing.T) {
+	event := Event{Date: time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local), Time: time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC)}
+	if got, want := event.Start(time.UTC), time.Date(2025, 9, 1, 9, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Start() = %v, want %v", got, want)
+	}
+	event.AllDay = true
+	if got, want := event.Start(time.UTC), time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Start() of an all-day event = %v, want %v", got, want)
+	}
+}
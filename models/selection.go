@@ -10,6 +10,7 @@ import (
 type Selection struct {
 	SelectedDate time.Time // The currently selected date
 	Calendar     *Calendar // Reference to the calendar for boundary checking
+	RangeStart   time.Time // Date where a date range being marked starts, zero when none
 }
 
 // NewSelection creates a new selection with today's date as the initial selection
@@ -87,6 +88,34 @@ func (s *Selection) AdjustForMonthChange() {
 	}
 }
 
+// HasRange reports whether a date range is being marked
+func (s *Selection) HasRange() bool {
+	return !s.RangeStart.IsZero()
+}
+
+// Range returns the first and last date of the marked range, which spans from
+// RangeStart to the selected date in either direction; without a range both are the
+// selected date
+func (s *Selection) Range() (time.Time, time.Time) {
+	if !s.HasRange() {
+		return s.SelectedDate, s.SelectedDate
+	}
+	if s.RangeStart.After(s.SelectedDate) {
+		return s.SelectedDate, s.RangeStart
+	}
+	return s.RangeStart, s.SelectedDate
+}
+
+// InRange reports whether date falls in the marked range
+func (s *Selection) InRange(date time.Time) bool {
+	if !s.HasRange() {
+		return false
+	}
+	start, end := s.Range()
+	date = calendar.NormalizeDate(date)
+	return !date.Before(start) && !date.After(end)
+}
+
 // isDateWithinBounds checks if a date is within the visible three-month range
 func (s *Selection) isDateWithinBounds(date time.Time) bool {
 	prevMonth := s.Calendar.GetPreviousMonth()
@@ -357,3 +357,26 @@ func TestSelection_LeapYearHandling(t *testing.T) {
 		t.Errorf("AdjustForMonthChange() in non-leap year = %v, want %v", selection.SelectedDate, expected)
 	}
 }
+
+func TestSelection_Range(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.Local) }
+	selection := &Selection{SelectedDate: day(12)}
+
+	if selection.HasRange() || selection.InRange(day(12)) {
+		t.Error("A selection without RangeStart should not mark a range")
+	}
+	if start, end := selection.Range(); !start.Equal(day(12)) || !end.Equal(day(12)) {
+		t.Errorf("Range() without a range = %v - %v, want the selected date", start, end)
+	}
+
+	// The range runs backwards when the selection moved before its start
+	selection.RangeStart = day(15)
+	if start, end := selection.Range(); !start.Equal(day(12)) || !end.Equal(day(15)) {
+		t.Errorf("Range() = %v - %v, want Aug 12 - Aug 15", start, end)
+	}
+	for d, want := range map[int]bool{11: false, 12: true, 14: true, 15: true, 16: false} {
+		if got := selection.InRange(day(d)); got != want {
+			t.Errorf("InRange(Aug %d) = %v, want %v", d, got, want)
+		}
+	}
+}
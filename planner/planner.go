@@ -0,0 +1,152 @@
+package planner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/locale"
+	"go-ascii-calendar/models"
+)
+
+// Options size the week planner page
+type Options struct {
+	ColumnWidth int // Characters of each day column, without the separators
+	RowsPerHour int // Lines of each hour slot; further events are summed up as "+N more"
+	FirstHour   int // First hour shown; earlier events move it earlier
+	LastHour    int // Last hour shown; later events move it later
+
+	Format locale.Formatter // Writes the dates of the page and names the zone of its times
+}
+
+// DefaultOptions fit a landscape page: 112 characters wide, office hours plus any
+// hours holding events
+var DefaultOptions = Options{ColumnWidth: 14, RowsPerHour: 2, FirstHour: 8, LastHour: 18}
+
+// hourLabelWidth is the width of the hour column, e.g. " 08:00 "
+const hourLabelWidth = 7
+
+// Week holds the events of seven consecutive days
+type Week struct {
+	Start time.Time         // First day of the week
+	Days  [7][]models.Event // Events of each day from Start, sorted by time
+}
+
+// NewWeek sorts the events dated in the seven days from start into their days;
+// other events are ignored
+func NewWeek(start time.Time, events []models.Event) Week {
+	week := Week{Start: calendar.NormalizeDate(start)}
+	for _, event := range events {
+		for day := range week.Days {
+			if calendar.IsSameDate(event.Date, week.Start.AddDate(0, 0, day)) {
+				week.Days[day] = append(week.Days[day], event)
+				break
+			}
+		}
+	}
+	for day := range week.Days {
+		events := week.Days[day]
+		sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	}
+	return week
+}
+
+// Date returns the date of a day of the week, 0 being the first
+func (w Week) Date(day int) time.Time {
+	return w.Start.AddDate(0, 0, day)
+}
+
+// HourRange returns the first and last hour to show: the hours of opts, widened to
+// hold every event of the week
+func (w Week) HourRange(opts Options) (int, int) {
+	first, last := opts.FirstHour, opts.LastHour
+	for _, events := range w.Days {
+		for _, event := range events {
+			first = min(first, event.Time.Hour())
+			last = max(last, event.Time.Hour())
+		}
+	}
+	return first, last
+}
+
+// Slot returns the events of a day starting within an hour
+func (w Week) Slot(day, hour int) []models.Event {
+	var slot []models.Event
+	for _, event := range w.Days[day] {
+		if event.Time.Hour() == hour {
+			slot = append(slot, event)
+		}
+	}
+	return slot
+}
+
+// Render lays the week out as a planner page: a column per day and a slot of
+// opts.RowsPerHour lines per hour, each event written in its day's slot
+func Render(week Week, opts Options) []string {
+	last := week.Date(6)
+	lines := []string{
+		fmt.Sprintf("Week %d: %s - %s, times in %s", calendar.GetWeekOfYear(week.Date(3)),
+			opts.Format.Day(week.Start), opts.Format.Day(last), opts.Format.Zone(week.Start)),
+		"",
+	}
+
+	rule := strings.Repeat("-", hourLabelWidth) + strings.Repeat("+"+strings.Repeat("-", opts.ColumnWidth), 7) + "+"
+	cells := make([]string, 7)
+	for day := range cells {
+		cells[day] = opts.Format.ShortDay(week.Date(day))
+	}
+	lines = append(lines, rule, row("", cells, opts.ColumnWidth), rule)
+
+	first, lastHour := week.HourRange(opts)
+	for hour := first; hour <= lastHour; hour++ {
+		for line := 0; line < opts.RowsPerHour; line++ {
+			label := ""
+			if line == 0 {
+				label = fmt.Sprintf("%02d:00", hour)
+			}
+			for day := range cells {
+				cells[day] = slotLine(week.Slot(day, hour), line, opts.RowsPerHour)
+			}
+			lines = append(lines, row(label, cells, opts.ColumnWidth))
+		}
+		lines = append(lines, rule)
+	}
+	return lines
+}
+
+// slotLine returns line number line of an hour slot: the event at that position, or
+// on the slot's last line a count of the events that do not fit
+func slotLine(slot []models.Event, line, rows int) string {
+	if line == rows-1 && len(slot) > rows {
+		return fmt.Sprintf("+%d more", len(slot)-line)
+	}
+	if line >= len(slot) {
+		return ""
+	}
+	event := slot[line]
+	return event.GetTimeString() + " " + event.Description
+}
+
+// row joins a label and seven cells into one line of the page, fitting each cell
+// into its column with a space on either side
+func row(label string, cells []string, width int) string {
+	var b strings.Builder
+	b.WriteString(fit(" "+label, hourLabelWidth))
+	for _, cell := range cells {
+		b.WriteString("|")
+		b.WriteString(fit(" "+cell, width-1) + " ")
+	}
+	b.WriteString("|")
+	return b.String()
+}
+
+// fit pads text with spaces or cuts it to exactly width characters
+func fit(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return text + strings.Repeat(" ", width-len(runes))
+}
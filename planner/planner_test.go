@@ -0,0 +1,130 @@
+package planner
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/locale"
+	"go-ascii-calendar/models"
+)
+
+var weekStart = time.Date(2025, 8, 11, 0, 0, 0, 0, time.Local)
+
+func event(day, hour, minute int, description string) models.Event {
+	return models.Event{
+		Date:        weekStart.AddDate(0, 0, day),
+		Time:        time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC),
+		Description: description,
+	}
+}
+
+func TestNewWeek(t *testing.T) {
+	week := NewWeek(weekStart, []models.Event{
+		event(2, 14, 0, "Review"),
+		event(2, 9, 0, "Standup"),
+		event(7, 9, 0, "Next week"),
+		event(-1, 9, 0, "Last week"),
+	})
+
+	for day, events := range week.Days {
+		want := 0
+		if day == 2 {
+			want = 2
+		}
+		if len(events) != want {
+			t.Errorf("Day %d has %d events, want %d", day, len(events), want)
+		}
+	}
+	if week.Days[2][0].Description != "Standup" {
+		t.Errorf("Events of a day should be sorted by time, got %v", week.Days[2])
+	}
+	if slot := week.Slot(2, 14); len(slot) != 1 || slot[0].Description != "Review" {
+		t.Errorf("Slot(2, 14) = %v, want the review", slot)
+	}
+}
+
+func TestWeek_HourRange(t *testing.T) {
+	opts := Options{ColumnWidth: 14, RowsPerHour: 2, FirstHour: 8, LastHour: 18}
+
+	if first, last := NewWeek(weekStart, nil).HourRange(opts); first != 8 || last != 18 {
+		t.Errorf("HourRange() of an empty week = %d-%d, want 8-18", first, last)
+	}
+
+	week := NewWeek(weekStart, []models.Event{event(0, 6, 30, "Run"), event(4, 21, 0, "Concert")})
+	if first, last := week.HourRange(opts); first != 6 || last != 21 {
+		t.Errorf("HourRange() = %d-%d, want 6-21", first, last)
+	}
+}
+
+func TestRender(t *testing.T) {
+	opts := Options{ColumnWidth: 14, RowsPerHour: 2, FirstHour: 9, LastHour: 10}
+	week := NewWeek(weekStart, []models.Event{
+		event(0, 9, 0, "Sync"),
+		event(0, 9, 15, "Coffee"),
+		event(0, 9, 45, "Mail"),
+		event(3, 10, 30, "A very long description that is cut"),
+	})
+	lines := Render(week, opts)
+
+	if !strings.HasPrefix(lines[0], "Week 33: Mo 2025-08-11 - Su 2025-08-17, times in ") {
+		t.Errorf("Title = %q", lines[0])
+	}
+
+	// Every line of the grid is as wide as the rules
+	width := len(lines[2])
+	for i, line := range lines[2:] {
+		if len([]rune(line)) != width {
+			t.Errorf("Line %d is %d characters wide, want %d: %q", i+2, len([]rune(line)), width, line)
+		}
+	}
+
+	// Title, blank line, three header lines, then two lines and a rule per hour
+	if want := 5 + 2*3; len(lines) != want {
+		t.Fatalf("Render() returned %d lines, want %d:\n%s", len(lines), want, strings.Join(lines, "\n"))
+	}
+	nine, nineMore, ten := lines[5], lines[6], lines[8]
+	if !strings.HasPrefix(nine, " 09:00 | 09:00 Sync   |") {
+		t.Errorf("First 09:00 line = %q", nine)
+	}
+	if !strings.HasPrefix(nineMore, "       | +2 more      |") {
+		t.Errorf("Second 09:00 line should count the events that do not fit, got %q", nineMore)
+	}
+	columns := strings.Split(ten, "|")
+	if got := columns[4]; got != " 10:30 A very " {
+		t.Errorf("Thursday 10:00 cell = %q, want the event cut to the column", got)
+	}
+}
+
+func TestRender_Locales(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("Time zone data unavailable: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("Time zone data unavailable: %v", err)
+	}
+	tests := []struct {
+		locale   string
+		location *time.Location
+		title    string
+		monday   string
+	}{
+		{"de_DE.UTF-8", berlin, "Week 33: Mo 11.08.2025 - So 17.08.2025, times in Europe/Berlin (CEST)", " Mo 11.08 "},
+		{"en_US.UTF-8", newYork, "Week 33: Mo 08/11/2025 - Su 08/17/2025, times in America/New_York (EDT)", " Mo 08/11 "},
+	}
+
+	for _, tt := range tests {
+		opts := DefaultOptions
+		opts.Format = locale.NewFormatter(locale.Parse(tt.locale))
+		opts.Format.Location = tt.location
+		lines := Render(NewWeek(weekStart, nil), opts)
+		if lines[0] != tt.title {
+			t.Errorf("%s: title = %q, want %q", tt.locale, lines[0], tt.title)
+		}
+		if header := strings.Split(lines[3], "|"); header[1] != fit(tt.monday, opts.ColumnWidth) {
+			t.Errorf("%s: Monday header = %q, want %q", tt.locale, header[1], tt.monday)
+		}
+	}
+}
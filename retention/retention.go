@@ -0,0 +1,140 @@
+package retention
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/events"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+// TrashFileName is the name of the trash file inside the data directory
+const TrashFileName = "trash.json"
+
+// KeepPrefix marks events that are never purged when a word of their description
+// starts with it, e.g. "Wedding keep:forever"
+const KeepPrefix = "keep:"
+
+// Report describes what a maintenance pass changed
+type Report struct {
+	Purged  []models.Event // Events moved to the trash by this pass
+	Dropped int            // Trash entries deleted for good after the grace period
+}
+
+// Summary returns a one-line description of the pass, empty when nothing changed
+func (r Report) Summary(policy config.RetentionConfig) string {
+	var parts []string
+	if len(r.Purged) > 0 {
+		parts = append(parts, fmt.Sprintf("moved %d events older than %d days to the trash (restorable for %d days with -restore-purged)",
+			len(r.Purged), policy.MaxAgeDays, policy.TrashDays))
+	}
+	if r.Dropped > 0 {
+		parts = append(parts, fmt.Sprintf("emptied %d expired trash entries", r.Dropped))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Retention: " + strings.Join(parts, ", ")
+}
+
+// IsKept reports whether an event is exempt from the retention policy
+func IsKept(event models.Event) bool {
+	for _, word := range strings.Fields(event.Description) {
+		if strings.HasPrefix(strings.ToLower(word), KeepPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired returns the events dated more than maxAgeDays before now that are not kept.
+// A maxAgeDays of 0 or less keeps all events.
+func Expired(list []models.Event, maxAgeDays int, now time.Time) []models.Event {
+	if maxAgeDays <= 0 {
+		return nil
+	}
+
+	cutoff := calendar.NormalizeDate(now).AddDate(0, 0, -maxAgeDays)
+	var expired []models.Event
+	for _, event := range list {
+		if calendar.NormalizeDate(event.Date).Before(cutoff) && !IsKept(event) {
+			expired = append(expired, event)
+		}
+	}
+	return expired
+}
+
+// Enforce runs one maintenance pass: trash entries older than the grace period are
+// dropped, then expired events are moved from the manager to the trash. The trash is
+// written before the events are removed, so an interrupted pass never loses events.
+// In dry-run and ephemeral mode the trash file is left alone.
+func Enforce(manager *events.Manager, policy config.RetentionConfig, trashFile string, now time.Time) (Report, error) {
+	var report Report
+
+	trash, err := storage.LoadTrashJSON(trashFile)
+	if err != nil {
+		return report, err
+	}
+
+	kept := trash[:0]
+	graceStart := now.AddDate(0, 0, -policy.TrashDays)
+	for _, entry := range trash {
+		if entry.PurgedAt.Before(graceStart) {
+			report.Dropped++
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	trash = kept
+
+	expired := Expired(manager.GetAllEvents(), policy.MaxAgeDays, now)
+	for _, event := range expired {
+		trash = append(trash, storage.TrashEntry{PurgedAt: now, Event: event})
+	}
+	if report.Dropped == 0 && len(expired) == 0 {
+		return report, nil
+	}
+
+	if !manager.ReadOnly() {
+		if err := storage.SaveTrashJSON(trash, trashFile); err != nil {
+			return report, err
+		}
+	}
+	if _, err := manager.DeleteEvents(expired); err != nil {
+		return report, err
+	}
+	report.Purged = expired
+	return report, nil
+}
+
+// Restore moves all events of the trash back to the manager and empties the trash.
+// Events that are stored again already are skipped. It returns the number restored.
+func Restore(manager *events.Manager, trashFile string) (int, error) {
+	trash, err := storage.LoadTrashJSON(trashFile)
+	if err != nil {
+		return 0, err
+	}
+	if len(trash) == 0 {
+		return 0, nil
+	}
+
+	purged := make([]models.Event, len(trash))
+	for i, entry := range trash {
+		purged[i] = entry.Event
+	}
+	restored, err := manager.ImportEvents(purged)
+	if err != nil {
+		return 0, err
+	}
+
+	if !manager.ReadOnly() {
+		if err := storage.SaveTrashJSON(nil, trashFile); err != nil {
+			return restored, err
+		}
+	}
+	return restored, nil
+}
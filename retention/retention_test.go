@@ -0,0 +1,99 @@
+package retention
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/events"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+func event(year int, month time.Month, day int, description string) models.Event {
+	return models.Event{
+		Date:        time.Date(year, month, day, 0, 0, 0, 0, time.Local),
+		Time:        time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+		Description: description,
+	}
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.Local)
+	list := []models.Event{
+		event(2022, 8, 14, "Old"),
+		event(2022, 8, 15, "Exactly at the limit"),
+		event(2021, 1, 1, "Graduation Keep:forever"),
+		event(2025, 8, 1, "Recent"),
+	}
+
+	expired := Expired(list, 1096, now)
+	if len(expired) != 1 || expired[0].Description != "Old" {
+		t.Errorf("Expired() = %v, want only the old event", expired)
+	}
+	if expired := Expired(list, 0, now); len(expired) != 0 {
+		t.Errorf("Expired() without a maximum age = %v, want none", expired)
+	}
+}
+
+func TestEnforceAndRestore(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "retention_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	eventsFile := filepath.Join(tempDir, "events.json")
+	trashFile := filepath.Join(tempDir, TrashFileName)
+	stored := []models.Event{event(2020, 3, 1, "Old dentist"), event(2025, 8, 1, "Recent dentist")}
+	if err := storage.SaveEventsJSON(stored, eventsFile); err != nil {
+		t.Fatalf("Failed to write events file: %v", err)
+	}
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.Local)
+	stale := storage.TrashEntry{PurgedAt: now.AddDate(0, 0, -31), Event: event(2019, 1, 1, "Long gone")}
+	if err := storage.SaveTrashJSON([]storage.TrashEntry{stale}, trashFile); err != nil {
+		t.Fatalf("Failed to write trash file: %v", err)
+	}
+
+	manager := events.NewManagerWithConfig(&config.Config{EventsFilePath: eventsFile})
+	if err := manager.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	policy := config.RetentionConfig{MaxAgeDays: 365, TrashDays: 30}
+
+	report, err := Enforce(manager, policy, trashFile, now)
+	if err != nil {
+		t.Fatalf("Enforce() failed: %v", err)
+	}
+	if len(report.Purged) != 1 || report.Purged[0].Description != "Old dentist" || report.Dropped != 1 {
+		t.Errorf("Enforce() report = %+v, want the old dentist purged and the stale entry dropped", report)
+	}
+	if report.Summary(policy) == "" {
+		t.Error("Summary() should describe the pass")
+	}
+	if manager.GetEventCount() != 1 {
+		t.Errorf("%d events left, want 1", manager.GetEventCount())
+	}
+	trash, err := storage.LoadTrashJSON(trashFile)
+	if err != nil || len(trash) != 1 || trash[0].Event.Description != "Old dentist" {
+		t.Errorf("Trash = %v (%v), want the old dentist", trash, err)
+	}
+
+	// A second pass has nothing to do
+	if report, err := Enforce(manager, policy, trashFile, now); err != nil || report.Summary(policy) != "" {
+		t.Errorf("Second Enforce() = %+v, %v; want no changes", report, err)
+	}
+
+	restored, err := Restore(manager, trashFile)
+	if err != nil || restored != 1 {
+		t.Fatalf("Restore() = %d, %v; want 1", restored, err)
+	}
+	if manager.GetEventCount() != 2 {
+		t.Errorf("%d events after restore, want 2", manager.GetEventCount())
+	}
+	if trash, _ := storage.LoadTrashJSON(trashFile); len(trash) != 0 {
+		t.Errorf("Trash after restore = %v, want empty", trash)
+	}
+}
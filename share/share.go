@@ -0,0 +1,244 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/locale"
+	"go-ascii-calendar/models"
+	"go-ascii-calendar/storage"
+)
+
+// BusyDescription replaces the descriptions of a snapshot that only shows availability
+const BusyDescription = "Busy"
+
+// Snapshot is the read-only copy of a date range that is shared
+type Snapshot struct {
+	From    time.Time      // First day shared
+	To      time.Time      // Last day shared
+	Events  []models.Event // Events of the range, sorted by date and time
+	Taken   time.Time      // When the snapshot was taken
+	Expires time.Time      // When the link stops working
+
+	// Format writes the dates and times of the page the way the calendar does
+	Format locale.Formatter
+}
+
+// Day is a date of a snapshot with its events
+type Day struct {
+	Date   time.Time
+	Events []models.Event
+}
+
+// NewSnapshot copies the events taking any day from from through to, including those
+// spanning several days that start before from. With busyOnly every description is
+// replaced by BusyDescription and nothing else about the events is shared beyond
+// their times and days.
+func NewSnapshot(from, to time.Time, events []models.Event, busyOnly bool, now time.Time, lifetime time.Duration) Snapshot {
+	s := Snapshot{From: calendar.NormalizeDate(from), To: calendar.NormalizeDate(to), Taken: now, Expires: now.Add(lifetime)}
+	for _, event := range events {
+		if event.LastDate().Before(s.From) || calendar.NormalizeDate(event.Date).After(s.To) {
+			continue
+		}
+		if busyOnly {
+			event = models.Event{Date: event.Date, Time: event.Time, Duration: event.Duration, EndDate: event.EndDate, AllDay: event.AllDay, Description: BusyDescription}
+		}
+		s.Events = append(s.Events, event)
+	}
+	return s
+}
+
+// Days returns every date of the snapshot with its events, including days without any.
+// An event spanning several days is listed on each of them.
+func (s Snapshot) Days() []Day {
+	var days []Day
+	for date := s.From; !date.After(s.To); date = date.AddDate(0, 0, 1) {
+		day := Day{Date: date}
+		for _, event := range s.Events {
+			if event.OccursOn(date) {
+				day.Events = append(day.Events, event)
+			}
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+var pageTemplate = template.Must(template.New("page").Funcs(template.FuncMap{
+	"when": eventTimes,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Calendar {{.Format.Day .From}} - {{.Format.Day .To}}</title>
+<style>
+body { font-family: ui-monospace, monospace; max-width: 40em; margin: 2em auto; padding: 0 1em; }
+h2 { font-size: 1em; margin: 1.5em 0 0.3em; border-bottom: 1px solid #ccc; }
+ul { list-style: none; padding: 0; margin: 0; }
+.free { color: #2a7d2a; }
+footer { margin-top: 2em; color: #777; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Calendar {{.Format.Day .From}} - {{.Format.Day .To}}</h1>
+{{range .Days}}<h2>{{$.Format.Day .Date}}</h2>
+{{if .Events}}<ul>
+{{range .Events}}<li>{{when .}} {{.Description}}</li>
+{{end}}</ul>
+{{else}}<p class="free">free</p>
+{{end}}{{end}}<footer>
+<p><a href="{{.ICSLink}}">Add to your calendar (.ics)</a></p>
+<p>Times are in {{.Format.Zone .Taken}}.</p>
+<p>Snapshot taken {{.Format.Day .Taken}} {{.Taken.Format "15:04"}}; this link stops working at {{.Expires.Format "15:04"}}.</p>
+</footer>
+</body>
+</html>
+`))
+
+// eventTimes returns the start of an event, with its end when it has a duration, or
+// "all day" for an event without a time
+func eventTimes(event models.Event) string {
+	if event.AllDay {
+		return event.GetTimeLabel()
+	}
+	if end := event.GetEndTimeString(); end != "" {
+		return event.GetTimeString() + "-" + end
+	}
+	return event.GetTimeString()
+}
+
+// HTML renders the snapshot as a page listing each day of the range, with the moments
+// it was taken and expires in the zone of the calendar
+func (s Snapshot) HTML() []byte {
+	return s.html("")
+}
+
+// html renders the page with query added to its link to calendar.ics, so that the
+// link carries the access token the page was opened with
+func (s Snapshot) html(query string) []byte {
+	page := s
+	page.Taken, page.Expires = s.Taken.In(s.Format.In()), s.Expires.In(s.Format.In())
+	link := "calendar.ics"
+	if query != "" {
+		link += "?" + query
+	}
+	var b bytes.Buffer
+	if err := pageTemplate.Execute(&b, struct {
+		Snapshot
+		Days    []Day
+		ICSLink string
+	}{page, s.Days(), link}); err != nil {
+		// The template only formats fields of the snapshot
+		panic(err)
+	}
+	return b.Bytes()
+}
+
+// ICS renders the events of the snapshot as an iCalendar file
+func (s Snapshot) ICS() []byte {
+	var b bytes.Buffer
+	// Writing to a buffer cannot fail
+	storage.ExportICS(&b, s.Events, s.Taken, s.Format.In())
+	return b.Bytes()
+}
+
+// NewToken returns a random token for the path of a share link
+func NewToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to create a share token: %v", err)
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// Handler serves the snapshot as /<token>/ and /<token>/calendar.ics. Every other path,
+// including a wrong token, is not found, and only GET and HEAD are allowed. The query
+// of a page request, such as an access token, is kept on its link to calendar.ics.
+func Handler(s Snapshot, token string) http.Handler {
+	page, ics := s.HTML(), s.ICS()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, "/")
+		requested, file, _ := strings.Cut(rest, "/")
+		if !ok || subtle.ConstantTimeCompare([]byte(requested), []byte(token)) != 1 {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "read-only snapshot", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Robots-Tag", "noindex")
+		switch file {
+		case "":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			if r.URL.RawQuery != "" {
+				w.Write(s.html(r.URL.RawQuery))
+				break
+			}
+			w.Write(page)
+		case "calendar.ics":
+			w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+			w.Write(ics)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// Serve serves handler on listener until ctx is done, then shuts the server down
+func Serve(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(listener) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-done; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// URLs returns the links of a share served on port: one per IPv4 address of the
+// machine's network interfaces, or localhost when it has none
+func URLs(port int, token string) []string {
+	var urls []string
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLoopback() || ipNet.IP.To4() == nil {
+				continue
+			}
+			urls = append(urls, fmt.Sprintf("http://%s:%d/%s/", ipNet.IP, port, token))
+		}
+	}
+	if len(urls) == 0 {
+		urls = append(urls, fmt.Sprintf("http://localhost:%d/%s/", port, token))
+	}
+	return urls
+}
@@ -0,0 +1,242 @@
+package share
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/locale"
+	"go-ascii-calendar/models"
+)
+
+var shareStart = time.Date(2025, 8, 11, 0, 0, 0, 0, time.Local)
+
+func event(day, hour, minute int, description string, duration time.Duration) models.Event {
+	return models.Event{
+		Date:        shareStart.AddDate(0, 0, day),
+		Time:        time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC),
+		Description: description,
+		Duration:    duration,
+	}
+}
+
+func testSnapshot(busyOnly bool) Snapshot {
+	events := []models.Event{
+		event(-1, 9, 0, "Last week", 0),
+		event(0, 9, 0, "Standup", 15*time.Minute),
+		event(2, 14, 0, "Review, part 1; <draft>", 0),
+		event(3, 9, 0, "Next week", 0),
+	}
+	now := time.Date(2025, 8, 10, 12, 0, 0, 0, time.Local)
+	return NewSnapshot(shareStart, shareStart.AddDate(0, 0, 2), events, busyOnly, now, 30*time.Minute)
+}
+
+func TestNewSnapshot(t *testing.T) {
+	s := testSnapshot(false)
+	if len(s.Events) != 2 || s.Events[0].Description != "Standup" {
+		t.Fatalf("Snapshot should hold only the events of the range, got %v", s.Events)
+	}
+	if want := time.Date(2025, 8, 10, 12, 30, 0, 0, time.Local); !s.Expires.Equal(want) {
+		t.Errorf("Expires = %v, want %v", s.Expires, want)
+	}
+
+	days := s.Days()
+	if len(days) != 3 || len(days[0].Events) != 1 || len(days[1].Events) != 0 || len(days[2].Events) != 1 {
+		t.Errorf("Days() should list every day of the range with its events, got %v", days)
+	}
+
+	for _, e := range testSnapshot(true).Events {
+		if e.Description != BusyDescription {
+			t.Errorf("A busy-only snapshot shows the description %q", e.Description)
+		}
+	}
+}
+
+func TestNewSnapshot_MultiDay(t *testing.T) {
+	trip := event(-2, 0, 0, "Trip", 0)
+	trip.AllDay = true
+	trip.EndDate = shareStart.AddDate(0, 0, 1)
+	over := event(-5, 0, 0, "Over before", 0)
+	over.EndDate = shareStart.AddDate(0, 0, -1)
+	now := time.Date(2025, 8, 10, 12, 0, 0, 0, time.Local)
+
+	s := NewSnapshot(shareStart, shareStart.AddDate(0, 0, 2), []models.Event{over, trip}, true, now, 30*time.Minute)
+	if len(s.Events) != 1 || !s.Events[0].AllDay || !s.Events[0].EndDate.Equal(trip.EndDate) {
+		t.Fatalf("Snapshot should hold the event started before the range with its span, got %v", s.Events)
+	}
+	days := s.Days()
+	if len(days[0].Events) != 1 || len(days[1].Events) != 1 || len(days[2].Events) != 0 {
+		t.Errorf("Days() should list the event on each of its days in the range, got %v", days)
+	}
+	if page := string(s.HTML()); !strings.Contains(page, "all day Busy") {
+		t.Errorf("The page should show the event as all day:\n%s", page)
+	}
+}
+
+func TestSnapshot_HTML(t *testing.T) {
+	page := string(testSnapshot(false).HTML())
+	for _, want := range []string{"Mo 2025-08-11", "09:00-09:15 Standup", "Tu 2025-08-12</h2>\n<p class=\"free\">free</p>", "&lt;draft&gt;", `href="calendar.ics"`} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Page misses %q:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "<draft>") {
+		t.Error("Descriptions should be escaped")
+	}
+}
+
+func TestSnapshot_HTML_Locales(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("Time zone data unavailable: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("Time zone data unavailable: %v", err)
+	}
+	taken := time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale   string
+		location *time.Location
+		want     []string
+	}{
+		{"de_DE.UTF-8", berlin, []string{"Calendar Mo 11.08.2025 - Mi 13.08.2025", "<h2>Di 12.08.2025</h2>",
+			"Times are in Europe/Berlin (CEST).", "Snapshot taken So 10.08.2025 12:00", "stops working at 12:30"}},
+		{"en_US.UTF-8", newYork, []string{"Calendar Mo 08/11/2025 - We 08/13/2025", "<h2>Tu 08/12/2025</h2>",
+			"Times are in America/New_York (EDT).", "Snapshot taken Su 08/10/2025 06:00", "stops working at 06:30"}},
+	}
+
+	for _, tt := range tests {
+		s := NewSnapshot(shareStart, shareStart.AddDate(0, 0, 2), nil, false, taken, 30*time.Minute)
+		s.Format = locale.NewFormatter(locale.Parse(tt.locale))
+		s.Format.Location = tt.location
+		page := string(s.HTML())
+		for _, want := range tt.want {
+			if !strings.Contains(page, want) {
+				t.Errorf("%s: page misses %q:\n%s", tt.locale, want, page)
+			}
+		}
+	}
+}
+
+func TestSnapshot_ICS(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("Time zone data unavailable: %v", err)
+	}
+	s := testSnapshot(false)
+	s.Format.Location = berlin
+	ics := string(s.ICS())
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTART:20250811T070000Z\r\nDTEND:20250811T071500Z\r\nSUMMARY:Standup\r\n",
+		`SUMMARY:Review\, part 1\; <draft>` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("ICS misses %q:\n%s", want, ics)
+		}
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != 2 {
+		t.Errorf("ICS should hold 2 events:\n%s", ics)
+	}
+}
+
+func TestNewToken(t *testing.T) {
+	a, err := NewToken()
+	if err != nil {
+		t.Fatalf("NewToken() failed: %v", err)
+	}
+	b, _ := NewToken()
+	if len(a) != 32 || a == b {
+		t.Errorf("NewToken() = %q, %q; want two different 32-character tokens", a, b)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	handler := Handler(testSnapshot(false), "secret")
+	tests := []struct {
+		method, path string
+		status       int
+		contentType  string
+	}{
+		{http.MethodGet, "/secret/", http.StatusOK, "text/html; charset=utf-8"},
+		{http.MethodHead, "/secret/", http.StatusOK, "text/html; charset=utf-8"},
+		{http.MethodGet, "/secret/calendar.ics", http.StatusOK, "text/calendar; charset=utf-8"},
+		{http.MethodGet, "/", http.StatusNotFound, ""},
+		{http.MethodGet, "/wrong/", http.StatusNotFound, ""},
+		{http.MethodGet, "/secre/", http.StatusNotFound, ""},
+		{http.MethodGet, "/secret/other", http.StatusNotFound, ""},
+		{http.MethodPost, "/secret/", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.path, rec.Code, tt.status)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%s %s has Content-Type %q, want %q", tt.method, tt.path, got, tt.contentType)
+		}
+		if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+			t.Errorf("%s %s has Cache-Control %q, want no-store", tt.method, tt.path, got)
+		}
+	}
+}
+
+func TestServe_StopsWhenDone(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, listener, Handler(testSnapshot(false), "secret")) }()
+
+	url := "http://" + listener.Addr().String() + "/secret/"
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "Standup") {
+		t.Errorf("Served page misses the events:\n%s", body)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve() = %v, want nil after shutting down", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve() did not return after its context was done")
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Error("The server should no longer answer after shutting down")
+	}
+}
+
+func TestURLs(t *testing.T) {
+	urls := URLs(8080, "secret")
+	if len(urls) == 0 {
+		t.Fatal("URLs() should return at least one link")
+	}
+	for _, url := range urls {
+		if !strings.HasPrefix(url, "http://") || !strings.HasSuffix(url, ":8080/secret/") {
+			t.Errorf("Unexpected link %q", url)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/storage"
+)
+
+// FileName is the name of the application state file inside the data directory
+const FileName = "state.json"
+
+// Bookmark is a named date the user can jump back to
+type Bookmark struct {
+	Name string `json:"name"`
+	Date string `json:"date"` // YYYY-MM-DD format
+}
+
+// GetDate returns the bookmarked date in local time
+func (b Bookmark) GetDate() (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", b.Date, time.Local)
+}
+
+// State is the persisted application state, kept separate from events
+type State struct {
+	Bookmarks []Bookmark `json:"bookmarks"`
+	TourDone  bool       `json:"tour_done,omitempty"` // The onboarding tour was finished or dismissed
+}
+
+// Store loads and saves the application state file
+type Store struct {
+	path    string
+	state   State
+	version uint64 // Incremented with every change to the bookmarks
+}
+
+// NewStore creates a store for the state file in the given data directory.
+// An empty data directory gives an in-memory store that is never persisted.
+func NewStore(dataDir string) *Store {
+	path := ""
+	if dataDir != "" {
+		path = filepath.Join(dataDir, FileName)
+	}
+	return &Store{path: path}
+}
+
+// Load reads the state file; a missing file is not an error
+func (s *Store) Load() error {
+	if s.path == "" {
+		return nil
+	}
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open state file: %v", err)
+	}
+	defer file.Close()
+
+	var state State
+	if err := json.NewDecoder(file).Decode(&state); err != nil {
+		return fmt.Errorf("failed to decode state file: %v", err)
+	}
+
+	s.state = state
+	s.version++
+	return nil
+}
+
+// Save writes the state file to the data directory
+func (s *Store) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	err := storage.WriteFileAtomic(s.path, 0644, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ") // Pretty print JSON
+		return encoder.Encode(s.state)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+
+	return nil
+}
+
+// Version returns a number that changes whenever the bookmarks change
+func (s *Store) Version() uint64 {
+	return s.version
+}
+
+// Bookmarks returns all bookmarks ordered by date
+func (s *Store) Bookmarks() []Bookmark {
+	bookmarks := append([]Bookmark(nil), s.state.Bookmarks...)
+	sort.Slice(bookmarks, func(i, j int) bool {
+		return bookmarks[i].Date < bookmarks[j].Date
+	})
+	return bookmarks
+}
+
+// BookmarkFor returns the bookmark on the given date, if any
+func (s *Store) BookmarkFor(date time.Time) (Bookmark, bool) {
+	key := date.Format("2006-01-02")
+	for _, bookmark := range s.state.Bookmarks {
+		if bookmark.Date == key {
+			return bookmark, true
+		}
+	}
+	return Bookmark{}, false
+}
+
+// SetBookmark names a date, replacing any existing bookmark on that date, and saves the state
+func (s *Store) SetBookmark(date time.Time, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("bookmark name cannot be empty")
+	}
+
+	key := date.Format("2006-01-02")
+	for i, bookmark := range s.state.Bookmarks {
+		if bookmark.Date == key {
+			s.state.Bookmarks[i].Name = name
+			s.version++
+			return s.Save()
+		}
+	}
+
+	s.state.Bookmarks = append(s.state.Bookmarks, Bookmark{Name: name, Date: key})
+	s.version++
+	return s.Save()
+}
+
+// TourDone reports whether the onboarding tour was finished or dismissed before
+func (s *Store) TourDone() bool {
+	return s.state.TourDone
+}
+
+// SetTourDone records that the onboarding tour was finished or dismissed and saves the state
+func (s *Store) SetTourDone() error {
+	if s.state.TourDone {
+		return nil
+	}
+	s.state.TourDone = true
+	return s.Save()
+}
+
+// RemoveBookmark deletes the bookmark on the given date and saves the state
+func (s *Store) RemoveBookmark(date time.Time) error {
+	key := date.Format("2006-01-02")
+	for i, bookmark := range s.state.Bookmarks {
+		if bookmark.Date == key {
+			s.state.Bookmarks = append(s.state.Bookmarks[:i], s.state.Bookmarks[i+1:]...)
+			s.version++
+			return s.Save()
+		}
+	}
+	return fmt.Errorf("no bookmark on %s", key)
+}
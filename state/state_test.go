@@ -0,0 +1,99 @@
+package state
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStore_Bookmarks(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "state_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	review := time.Date(2025, 9, 30, 0, 0, 0, 0, time.Local)
+	kickoff := time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local)
+
+	store := NewStore(tempDir)
+	if err := store.SetBookmark(review, "project deadline review"); err != nil {
+		t.Fatalf("SetBookmark() failed: %v", err)
+	}
+	if err := store.SetBookmark(kickoff, "kickoff"); err != nil {
+		t.Fatalf("SetBookmark() failed: %v", err)
+	}
+	// Renaming keeps a single bookmark per date
+	if err := store.SetBookmark(kickoff, "  project kickoff "); err != nil {
+		t.Fatalf("SetBookmark() rename failed: %v", err)
+	}
+	if err := store.SetBookmark(kickoff, "   "); err == nil {
+		t.Error("SetBookmark() should reject an empty name")
+	}
+
+	loaded := NewStore(tempDir)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	bookmarks := loaded.Bookmarks()
+	if len(bookmarks) != 2 {
+		t.Fatalf("Bookmarks() returned %d bookmarks, want 2", len(bookmarks))
+	}
+	if bookmarks[0].Name != "project kickoff" || bookmarks[1].Name != "project deadline review" {
+		t.Errorf("Bookmarks() = %v, want kickoff first (date order)", bookmarks)
+	}
+	if date, err := bookmarks[1].GetDate(); err != nil || !date.Equal(review) {
+		t.Errorf("GetDate() = %v, %v; want %v", date, err, review)
+	}
+
+	if _, ok := loaded.BookmarkFor(review); !ok {
+		t.Error("BookmarkFor() should find the review bookmark")
+	}
+	if err := loaded.RemoveBookmark(review); err != nil {
+		t.Fatalf("RemoveBookmark() failed: %v", err)
+	}
+	if _, ok := loaded.BookmarkFor(review); ok {
+		t.Error("BookmarkFor() should not find a removed bookmark")
+	}
+	if err := loaded.RemoveBookmark(review); err == nil {
+		t.Error("RemoveBookmark() of a missing bookmark should fail")
+	}
+}
+
+func TestStore_InMemory(t *testing.T) {
+	store := NewStore("")
+	if err := store.Load(); err != nil {
+		t.Errorf("Load() without data directory should not fail: %v", err)
+	}
+	if err := store.SetBookmark(time.Now(), "today"); err != nil {
+		t.Errorf("SetBookmark() without data directory should not fail: %v", err)
+	}
+	if len(store.Bookmarks()) != 1 {
+		t.Error("In-memory store should keep bookmarks for the session")
+	}
+}
+
+func TestStore_TourDone(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "state_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	store := NewStore(tempDir)
+	if store.TourDone() {
+		t.Error("A new store should not have the tour done")
+	}
+	if err := store.SetTourDone(); err != nil {
+		t.Fatalf("SetTourDone() failed: %v", err)
+	}
+
+	loaded := NewStore(tempDir)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !loaded.TourDone() {
+		t.Error("TourDone() should be saved in the state file")
+	}
+}
@@ -0,0 +1,230 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go-ascii-calendar/storage"
+)
+
+// UsageFileName is the name of the usage statistics file inside the data directory
+const UsageFileName = "usage.json"
+
+// WeekCounts holds the number of event mutations performed during one ISO week
+type WeekCounts struct {
+	Created int `json:"created"`
+	Edited  int `json:"edited"`
+	Deleted int `json:"deleted"`
+}
+
+// Usage is the persisted usage statistics document
+type Usage struct {
+	Weeks map[string]*WeekCounts `json:"weeks"` // Keyed by ISO week, e.g. "2025-W33"
+	Keys  map[string]int         `json:"keys"`  // Key action name -> press count
+	Views map[string]int         `json:"views"` // View name -> times entered
+}
+
+// Count is a name/value pair used for ranked listings
+type Count struct {
+	Name  string
+	Value int
+}
+
+// Tracker records usage statistics locally when enabled
+type Tracker struct {
+	enabled bool
+	path    string
+	usage   Usage
+}
+
+// NewTracker creates a tracker storing statistics in the given data directory.
+// A disabled tracker ignores all recordings and never touches the file system.
+func NewTracker(dataDir string, enabled bool) *Tracker {
+	return &Tracker{
+		enabled: enabled,
+		path:    filepath.Join(dataDir, UsageFileName),
+		usage:   newUsage(),
+	}
+}
+
+// newUsage returns an empty usage document with initialized maps
+func newUsage() Usage {
+	return Usage{
+		Weeks: make(map[string]*WeekCounts),
+		Keys:  make(map[string]int),
+		Views: make(map[string]int),
+	}
+}
+
+// IsEnabled reports whether usage tracking is turned on
+func (t *Tracker) IsEnabled() bool {
+	return t.enabled
+}
+
+// Load reads previously saved statistics; a missing file is not an error
+func (t *Tracker) Load() error {
+	if !t.enabled {
+		return nil
+	}
+
+	file, err := os.Open(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open usage file: %v", err)
+	}
+	defer file.Close()
+
+	usage := newUsage()
+	if err := json.NewDecoder(file).Decode(&usage); err != nil {
+		return fmt.Errorf("failed to decode usage file: %v", err)
+	}
+
+	// Guard against files with missing sections
+	if usage.Weeks == nil {
+		usage.Weeks = make(map[string]*WeekCounts)
+	}
+	if usage.Keys == nil {
+		usage.Keys = make(map[string]int)
+	}
+	if usage.Views == nil {
+		usage.Views = make(map[string]int)
+	}
+
+	t.usage = usage
+	return nil
+}
+
+// Save writes the statistics to the data directory
+func (t *Tracker) Save() error {
+	if !t.enabled {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	err := storage.WriteFileAtomic(t.path, 0644, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ") // Pretty print JSON
+		return encoder.Encode(t.usage)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write usage file: %v", err)
+	}
+
+	return nil
+}
+
+// RecordKey counts a key action by name
+func (t *Tracker) RecordKey(name string) {
+	if !t.enabled {
+		return
+	}
+	t.usage.Keys[name]++
+}
+
+// RecordView counts entering a view by name
+func (t *Tracker) RecordView(name string) {
+	if !t.enabled {
+		return
+	}
+	t.usage.Views[name]++
+}
+
+// RecordCreated counts an event creation in the week of at
+func (t *Tracker) RecordCreated(at time.Time) {
+	if week := t.week(at); week != nil {
+		week.Created++
+	}
+}
+
+// RecordEdited counts an event edit in the week of at
+func (t *Tracker) RecordEdited(at time.Time) {
+	if week := t.week(at); week != nil {
+		week.Edited++
+	}
+}
+
+// RecordDeleted counts an event deletion in the week of at
+func (t *Tracker) RecordDeleted(at time.Time) {
+	if week := t.week(at); week != nil {
+		week.Deleted++
+	}
+}
+
+// week returns the counters for the ISO week of at, or nil when disabled
+func (t *Tracker) week(at time.Time) *WeekCounts {
+	if !t.enabled {
+		return nil
+	}
+
+	key := WeekKey(at)
+	counts, ok := t.usage.Weeks[key]
+	if !ok {
+		counts = &WeekCounts{}
+		t.usage.Weeks[key] = counts
+	}
+	return counts
+}
+
+// WeekKey returns the ISO week identifier (e.g. "2025-W33") for a date
+func WeekKey(at time.Time) string {
+	year, week := at.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// RecentWeeks returns counts for the n ISO weeks ending with the week of now, oldest first
+func (t *Tracker) RecentWeeks(now time.Time, n int) ([]string, []WeekCounts) {
+	keys := make([]string, n)
+	counts := make([]WeekCounts, n)
+	for i := 0; i < n; i++ {
+		key := WeekKey(now.AddDate(0, 0, -7*(n-1-i)))
+		keys[i] = key
+		if week, ok := t.usage.Weeks[key]; ok {
+			counts[i] = *week
+		}
+	}
+	return keys, counts
+}
+
+// TopKeys returns the most used key actions, highest count first
+func (t *Tracker) TopKeys(n int) []Count {
+	return topCounts(t.usage.Keys, n)
+}
+
+// TopViews returns the most visited views, highest count first
+func (t *Tracker) TopViews(n int) []Count {
+	return topCounts(t.usage.Views, n)
+}
+
+// topCounts sorts a count map by value (then name) and returns at most n entries,
+// none when n is not positive
+func topCounts(counts map[string]int, n int) []Count {
+	if n <= 0 {
+		return nil
+	}
+	result := make([]Count, 0, len(counts))
+	for name, value := range counts {
+		result = append(result, Count{Name: name, Value: value})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Value == result[j].Value {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Value > result[j].Value
+	})
+
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
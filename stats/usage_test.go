@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTracker_Disabled(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "stats_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tracker := NewTracker(tempDir, false)
+	tracker.RecordKey("Quit application")
+	tracker.RecordView("calendar")
+	tracker.RecordCreated(time.Now())
+
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// Nothing may be written when tracking is disabled
+	if _, err := os.Stat(filepath.Join(tempDir, UsageFileName)); !os.IsNotExist(err) {
+		t.Error("Disabled tracker should not create a usage file")
+	}
+
+	if len(tracker.TopKeys(5)) != 0 {
+		t.Error("Disabled tracker should not record keys")
+	}
+}
+
+func TestTracker_SaveAndLoad(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "stats_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	now := time.Date(2025, 8, 15, 10, 0, 0, 0, time.UTC)
+
+	tracker := NewTracker(tempDir, true)
+	tracker.RecordCreated(now)
+	tracker.RecordCreated(now)
+	tracker.RecordEdited(now)
+	tracker.RecordDeleted(now.AddDate(0, 0, -7))
+	tracker.RecordKey("Next month")
+	tracker.RecordKey("Next month")
+	tracker.RecordKey("Add new event")
+	tracker.RecordView("search")
+
+	if err := tracker.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	loaded := NewTracker(tempDir, true)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	keys, counts := loaded.RecentWeeks(now, 2)
+	if keys[0] != "2025-W32" || keys[1] != "2025-W33" {
+		t.Errorf("RecentWeeks() keys = %v, want [2025-W32 2025-W33]", keys)
+	}
+	if counts[0].Deleted != 1 {
+		t.Errorf("Previous week deleted = %d, want 1", counts[0].Deleted)
+	}
+	if counts[1].Created != 2 || counts[1].Edited != 1 {
+		t.Errorf("Current week counts = %+v, want created 2, edited 1", counts[1])
+	}
+
+	topKeys := loaded.TopKeys(5)
+	if len(topKeys) != 2 || topKeys[0].Name != "Next month" || topKeys[0].Value != 2 {
+		t.Errorf("TopKeys() = %v, want Next month first with 2", topKeys)
+	}
+
+	if views := loaded.TopViews(5); len(views) != 1 || views[0].Name != "search" {
+		t.Errorf("TopViews() = %v, want [search]", views)
+	}
+	for _, n := range []int{0, -3} {
+		if top := loaded.TopKeys(n); len(top) != 0 {
+			t.Errorf("TopKeys(%d) = %v, want none", n, top)
+		}
+	}
+}
+
+func TestTracker_LoadMissingFile(t *testing.T) {
+	tracker := NewTracker(filepath.Join(os.TempDir(), "nonexistent-stats-dir"), true)
+	if err := tracker.Load(); err != nil {
+		t.Errorf("Load() with missing file should not fail: %v", err)
+	}
+}
+
+func TestWeekKey(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		expected string
+	}{
+		{time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC), "2025-W33"},
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), "2025-W01"},
+		{time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), "2025-W01"},
+	}
+
+	for _, tt := range tests {
+		if result := WeekKey(tt.date); result != tt.expected {
+			t.Errorf("WeekKey(%v) = %s, want %s", tt.date, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic replaces the file at filename with what write produces, so that a crash
+// or a failed write leaves either the old file or the new one and never a truncated mix.
+// The content goes to a temporary file in the same directory, which is synced to disk and
+// then renamed over filename. The file keeps the permissions of the file it replaces, or
+// gets perm when it is new, and a symlinked file is replaced at the target of the link.
+func WriteFileAtomic(filename string, perm os.FileMode, write func(io.Writer) error) error {
+	if target, err := filepath.EvalSymlinks(filename); err == nil {
+		filename = target
+	}
+	if info, err := os.Stat(filename); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(filename)
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tempName := temp.Name()
+	// Until the rename the temporary file is ours to drop
+	cleanup := func() {
+		temp.Close()
+		os.Remove(tempName)
+	}
+
+	if err := write(temp); err != nil {
+		cleanup()
+		return err
+	}
+	if err := temp.Chmod(perm); err != nil {
+		cleanup()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(tempName)
+		return err
+	}
+	if err := os.Rename(tempName, filename); err != nil {
+		os.Remove(tempName)
+		return err
+	}
+
+	// Sync the directory so the rename itself survives a crash; not every platform can
+	// open a directory for this, and the file is in place either way
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// writeFileAtomic is WriteFileAtomic for content already in memory
+func writeFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	return WriteFileAtomic(filename, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// appendFileAtomic adds data to the end of the file at filename, creating it when missing,
+// by rewriting it with WriteFileAtomic
+func appendFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	existing, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %v", filename, err)
+	}
+	return writeFileAtomic(filename, append(existing, data...), perm)
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.json")
+	if err := os.WriteFile(path, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failing write leaves the old file in place and no temporary file behind
+	err := WriteFileAtomic(path, 0644, func(w io.Writer) error {
+		io.WriteString(w, "half")
+		return errors.New("disk full")
+	})
+	if err == nil {
+		t.Fatal("WriteFileAtomic() should report the failed write")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "old" {
+		t.Errorf("File after a failed write = %q, want the old content", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Directory after a failed write holds %d files, want only the events file", len(entries))
+	}
+
+	if err := writeFileAtomic(path, []byte("new"), 0644); err != nil {
+		t.Fatalf("writeFileAtomic() failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new" {
+		t.Errorf("File = %q, want the new content", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("Permissions = %v, want those of the replaced file", info.Mode().Perm())
+	}
+
+	if err := appendFileAtomic(path, []byte(" line"), 0644); err != nil {
+		t.Fatalf("appendFileAtomic() failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new line" {
+		t.Errorf("File after append = %q, want %q", data, "new line")
+	}
+}
+
+func TestWriteFileAtomic_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles-events.json")
+	link := filepath.Join(dir, "events.json")
+	if err := os.WriteFile(target, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("Symlinks not available: %v", err)
+	}
+
+	if err := writeFileAtomic(link, []byte("new"), 0644); err != nil {
+		t.Fatalf("writeFileAtomic() failed: %v", err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Error("The symlink should be kept")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "new" {
+		t.Errorf("Link target = %q, want the new content", data)
+	}
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BackupSuffix precedes the number of a backup of an events file, e.g. events.json.bak.1
+const BackupSuffix = ".bak."
+
+// Backup is an earlier copy of an events file, numbered from 1 for the latest
+type Backup struct {
+	File    string    // Events file the backup was taken of
+	Number  int       // 1 for the latest backup
+	ModTime time.Time // When the backup was taken
+	Events  int       // Events in the backup, -1 when it cannot be read
+}
+
+// Path returns the file holding the backup
+func (b Backup) Path() string {
+	return b.File + BackupSuffix + strconv.Itoa(b.Number)
+}
+
+// BackupFile copies the file at path to path.bak.1 before it is overwritten, shifting
+// the older backups up by one and dropping those beyond keep. A missing file, or keep
+// of 0, takes no backup.
+func BackupFile(path string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s for a backup: %v", path, err)
+	}
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		return err
+	}
+	// Shift the oldest first so no backup overwrites another
+	for i := len(backups) - 1; i >= 0; i-- {
+		backup := backups[i]
+		if backup.Number >= keep {
+			if err := os.Remove(backup.Path()); err != nil {
+				return fmt.Errorf("failed to drop old backup: %v", err)
+			}
+			continue
+		}
+		next := Backup{File: path, Number: backup.Number + 1}
+		if err := os.Rename(backup.Path(), next.Path()); err != nil {
+			return fmt.Errorf("failed to rotate backups: %v", err)
+		}
+	}
+
+	latest := Backup{File: path, Number: 1}
+	if err := writeFileAtomic(latest.Path(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write backup: %v", err)
+	}
+	return nil
+}
+
+// ListBackups returns the backups of the events file at path, latest first
+func ListBackups(path string) ([]Backup, error) {
+	matches, err := filepath.Glob(path + BackupSuffix + "*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %v", err)
+	}
+
+	var backups []Backup
+	for _, match := range matches {
+		number, err := strconv.Atoi(strings.TrimPrefix(match, path+BackupSuffix))
+		if err != nil || number < 1 {
+			continue // Not one of our backups
+		}
+		info, err := os.Stat(match)
+		if err != nil {
+			continue
+		}
+		backup := Backup{File: path, Number: number, ModTime: info.ModTime(), Events: -1}
+		if events, err := LoadEventsJSON(match); err == nil {
+			backup.Events = len(events)
+		}
+		backups = append(backups, backup)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Number < backups[j].Number
+	})
+	return backups, nil
+}
+
+// RestoreBackup replaces the events file at path with its backup number, after backing
+// up the current file like BackupFile, so the restore can be undone in turn. A backup
+// that is not a readable events file is refused.
+func RestoreBackup(path string, number, keep int) error {
+	backup := Backup{File: path, Number: number}
+	if _, err := os.Stat(backup.Path()); err != nil {
+		return fmt.Errorf("no backup %d of %s", number, path)
+	}
+	if _, err := LoadEventsJSON(backup.Path()); err != nil {
+		return fmt.Errorf("backup %d cannot be restored: %v", number, err)
+	}
+	// Read before the rotation moves the backup
+	data, err := os.ReadFile(backup.Path())
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %v", err)
+	}
+
+	if err := BackupFile(path, keep); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to restore backup: %v", err)
+	}
+	return nil
+}
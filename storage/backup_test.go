@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+func TestBackupFile_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.json")
+	if err := BackupFile(path, 3); err != nil {
+		t.Fatalf("BackupFile() of a missing file failed: %v", err)
+	}
+
+	save := func(descriptions ...string) {
+		var events []models.Event
+		for _, description := range descriptions {
+			events = append(events, models.Event{Date: time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local), Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: description})
+		}
+		if err := SaveEventsJSON(events, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Each save is backed up before the next one, so backup 1 holds the previous save
+	for i := 1; i <= 5; i++ {
+		descriptions := make([]string, i)
+		for j := range descriptions {
+			descriptions[j] = "Event"
+		}
+		if i > 1 {
+			if err := BackupFile(path, 3); err != nil {
+				t.Fatalf("BackupFile() failed: %v", err)
+			}
+		}
+		save(descriptions...)
+	}
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		t.Fatalf("ListBackups() failed: %v", err)
+	}
+	if len(backups) != 3 {
+		t.Fatalf("ListBackups() = %v, want the three kept", backups)
+	}
+	for i, backup := range backups {
+		if backup.Number != i+1 || backup.Events != 4-i {
+			t.Errorf("Backup %d = number %d with %d events, want number %d with %d", i, backup.Number, backup.Events, i+1, 4-i)
+		}
+	}
+	if _, err := os.Stat(path + ".bak.4"); !os.IsNotExist(err) {
+		t.Error("Backups beyond the kept count should be dropped")
+	}
+
+	if err := BackupFile(path, 0); err != nil {
+		t.Fatalf("BackupFile() with no backups kept failed: %v", err)
+	}
+	if backups, _ := ListBackups(path); backups[0].Events != 4 {
+		t.Error("BackupFile() with no backups kept should leave the backups alone")
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.json")
+	event := models.Event{Date: time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local), Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Standup"}
+	if err := SaveEventsJSON([]models.Event{event}, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := BackupFile(path, 5); err != nil {
+		t.Fatal(err)
+	}
+	// A botched write leaves an unreadable file
+	if err := os.WriteFile(path, []byte(`{"events": [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RestoreBackup(path, 1, 5); err != nil {
+		t.Fatalf("RestoreBackup() failed: %v", err)
+	}
+	if events, err := LoadEventsJSON(path); err != nil || len(events) != 1 || events[0].Description != "Standup" {
+		t.Errorf("Restored file = %v, %v; want the standup", events, err)
+	}
+
+	// The replaced file became backup 1, and being unreadable it cannot be restored
+	backups, _ := ListBackups(path)
+	if len(backups) != 2 || backups[0].Events != -1 || backups[1].Events != 1 {
+		t.Fatalf("ListBackups() after the restore = %v, want the replaced file first", backups)
+	}
+	if err := RestoreBackup(path, 1, 5); err == nil {
+		t.Error("RestoreBackup() of an unreadable backup should fail")
+	}
+	if err := RestoreBackup(path, 9, 5); err == nil {
+		t.Error("RestoreBackup() of a missing backup should fail")
+	}
+}
@@ -2,8 +2,10 @@ package storage
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -21,6 +23,21 @@ type JSONEvent struct {
 	Date        string `json:"date"` // YYYY-MM-DD format
 	Time        string `json:"time"` // HH:MM format
 	Description string `json:"description"`
+	Category    string `json:"category,omitempty"`
+	Command     string `json:"command,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Flagged     bool   `json:"flagged,omitempty"`
+	Duration    int    `json:"duration,omitempty"` // Minutes
+	TimeChecked bool   `json:"time_checked,omitempty"`
+	EndDate     string `json:"end_date,omitempty"` // YYYY-MM-DD, last day of a multi-day event
+	AllDay      bool   `json:"all_day,omitempty"`
+	Unreviewed  bool   `json:"unreviewed,omitempty"`
+	ImportBase  string `json:"import_base,omitempty"`
+	Reminder    int    `json:"reminder,omitempty"` // Minutes before the start
+	Notes       string `json:"notes,omitempty"`
+
+	Conflict *JSONEvent `json:"conflict,omitempty"` // Version from the import source waiting to be resolved
 }
 
 // JSONEventStore represents the root structure of the JSON events file
@@ -43,7 +60,7 @@ func LoadEventsJSON(filename string) ([]models.Event, error) {
 	defer file.Close()
 
 	var store JSONEventStore
-	decoder := json.NewDecoder(file)
+	decoder := json.NewDecoder(skipBOM(file))
 	if err := decoder.Decode(&store); err != nil {
 		return nil, fmt.Errorf("failed to decode JSON events file: %v", err)
 	}
@@ -79,18 +96,17 @@ func SaveEventsJSON(events []models.Event, filename string) error {
 		return fmt.Errorf("failed to create directory: %v", err)
 	}
 
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create events JSON file: %v", err)
-	}
-	defer file.Close()
-
-	encoder := json.NewEncoder(file)
+	// Encode before touching the file, so an encoding error leaves it as it was
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
 	encoder.SetIndent("", "  ") // Pretty print JSON
 	if err := encoder.Encode(store); err != nil {
 		return fmt.Errorf("failed to encode events to JSON: %v", err)
 	}
 
+	if err := writeFileAtomic(filename, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write events JSON file: %v", err)
+	}
 	return nil
 }
 
@@ -131,23 +147,206 @@ func convertJSONToEvent(jsonEvent JSONEvent) (models.Event, error) {
 	if strings.TrimSpace(jsonEvent.Description) == "" {
 		return models.Event{}, fmt.Errorf("description cannot be empty")
 	}
+	if jsonEvent.Duration < 0 {
+		return models.Event{}, fmt.Errorf("invalid duration %d: minutes cannot be negative", jsonEvent.Duration)
+	}
+	if jsonEvent.Reminder < 0 {
+		return models.Event{}, fmt.Errorf("invalid reminder %d: minutes cannot be negative", jsonEvent.Reminder)
+	}
+
+	var endDate time.Time
+	if jsonEvent.EndDate != "" {
+		endDate, err = time.ParseInLocation("2006-01-02", jsonEvent.EndDate, time.Local)
+		if err != nil {
+			return models.Event{}, fmt.Errorf("invalid end date format '%s': %v", jsonEvent.EndDate, err)
+		}
+		if endDate.Before(eventDate) {
+			return models.Event{}, fmt.Errorf("end date %s is before the date %s", jsonEvent.EndDate, jsonEvent.Date)
+		}
+	}
+
+	var conflict *models.Event
+	if jsonEvent.Conflict != nil {
+		remote, err := convertJSONToEvent(*jsonEvent.Conflict)
+		if err != nil {
+			return models.Event{}, fmt.Errorf("invalid conflicting version: %v", err)
+		}
+		conflict = &remote
+	}
 
 	return models.Event{
 		Date:        eventDate,
 		Time:        eventTime,
 		Description: jsonEvent.Description,
+		Category:    jsonEvent.Category,
+		Command:     jsonEvent.Command,
+		Priority:    jsonEvent.Priority,
+		Source:      jsonEvent.Source,
+		Flagged:     jsonEvent.Flagged,
+		Duration:    time.Duration(jsonEvent.Duration) * time.Minute,
+		TimeChecked: jsonEvent.TimeChecked,
+		EndDate:     endDate,
+		AllDay:      jsonEvent.AllDay,
+		Unreviewed:  jsonEvent.Unreviewed,
+		ImportBase:  jsonEvent.ImportBase,
+		Conflict:    conflict,
+		Reminder:    time.Duration(jsonEvent.Reminder) * time.Minute,
+		Notes:       jsonEvent.Notes,
 	}, nil
 }
 
 // convertEventToJSON converts a models.Event to a JSONEvent
 func convertEventToJSON(event models.Event) JSONEvent {
+	var endDate string
+	if event.IsMultiDay() {
+		endDate = event.EndDate.Format("2006-01-02")
+	}
+	var conflict *JSONEvent
+	if event.Conflict != nil {
+		remote := convertEventToJSON(*event.Conflict)
+		conflict = &remote
+	}
 	return JSONEvent{
 		Date:        event.Date.Format("2006-01-02"),
 		Time:        event.Time.Format("15:04"),
 		Description: event.Description,
+		Category:    event.Category,
+		Command:     event.Command,
+		Priority:    event.Priority,
+		Source:      event.Source,
+		Flagged:     event.Flagged,
+		Duration:    int(event.Duration / time.Minute),
+		TimeChecked: event.TimeChecked,
+		EndDate:     endDate,
+		AllDay:      event.AllDay,
+		Unreviewed:  event.Unreviewed,
+		ImportBase:  event.ImportBase,
+		Conflict:    conflict,
+		Reminder:    int(event.Reminder / time.Minute),
+		Notes:       event.Notes,
 	}
 }
 
+// JSONEventChange records an event before and after a batch modification
+type JSONEventChange struct {
+	Before JSONEvent `json:"before"`
+	After  JSONEvent `json:"after"`
+}
+
+// SaveEventChangesJSON writes pairs of events before and after a batch modification,
+// so the modification can be undone later. before and after must have the same length.
+func SaveEventChangesJSON(before, after []models.Event, filename string) error {
+	if len(before) != len(after) {
+		return fmt.Errorf("mismatched change lists: %d before, %d after", len(before), len(after))
+	}
+
+	changes := make([]JSONEventChange, len(before))
+	for i := range before {
+		changes[i] = JSONEventChange{Before: convertEventToJSON(before[i]), After: convertEventToJSON(after[i])}
+	}
+
+	data, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode changes: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	if err := writeFileAtomic(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write changes file: %v", err)
+	}
+	return nil
+}
+
+// LoadEventChangesJSON reads pairs written by SaveEventChangesJSON
+func LoadEventChangesJSON(filename string) (before, after []models.Event, err error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read changes file: %v", err)
+	}
+
+	var changes []JSONEventChange
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode changes file: %v", err)
+	}
+
+	for _, change := range changes {
+		beforeEvent, err := convertJSONToEvent(change.Before)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid change: %v", err)
+		}
+		afterEvent, err := convertJSONToEvent(change.After)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid change: %v", err)
+		}
+		before = append(before, beforeEvent)
+		after = append(after, afterEvent)
+	}
+	return before, after, nil
+}
+
+// TrashEntry is an event removed by the retention policy, kept restorable for a while
+type TrashEntry struct {
+	PurgedAt time.Time
+	Event    models.Event
+}
+
+// JSONTrashEntry represents a trash entry in JSON format
+type JSONTrashEntry struct {
+	PurgedAt string    `json:"purged_at"` // RFC 3339 timestamp
+	Event    JSONEvent `json:"event"`
+}
+
+// SaveTrashJSON writes the trash entries, replacing the file
+func SaveTrashJSON(entries []TrashEntry, filename string) error {
+	jsonEntries := make([]JSONTrashEntry, len(entries))
+	for i, entry := range entries {
+		jsonEntries[i] = JSONTrashEntry{PurgedAt: entry.PurgedAt.Format(time.RFC3339), Event: convertEventToJSON(entry.Event)}
+	}
+
+	data, err := json.MarshalIndent(jsonEntries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trash: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	if err := writeFileAtomic(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write trash file: %v", err)
+	}
+	return nil
+}
+
+// LoadTrashJSON reads entries written by SaveTrashJSON; a missing file is an empty trash
+func LoadTrashJSON(filename string) ([]TrashEntry, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash file: %v", err)
+	}
+
+	var jsonEntries []JSONTrashEntry
+	if err := json.Unmarshal(data, &jsonEntries); err != nil {
+		return nil, fmt.Errorf("failed to decode trash file: %v", err)
+	}
+
+	entries := make([]TrashEntry, 0, len(jsonEntries))
+	for _, jsonEntry := range jsonEntries {
+		purgedAt, err := time.Parse(time.RFC3339, jsonEntry.PurgedAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid purge time %q: %v", jsonEntry.PurgedAt, err)
+		}
+		event, err := convertJSONToEvent(jsonEntry.Event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trash entry: %v", err)
+		}
+		entries = append(entries, TrashEntry{PurgedAt: purgedAt, Event: event})
+	}
+	return entries, nil
+}
+
 // MigrateToJSON migrates events from old text format to new JSON format
 func MigrateToJSON(oldTextFile, newJSONFile string) error {
 	// Load events from old text format
@@ -170,6 +369,19 @@ func MigrateToJSON(oldTextFile, newJSONFile string) error {
 	return nil
 }
 
+// PendingMigration returns the legacy text file that loading eventsFilePath would
+// migrate to JSON, and whether such a migration is pending
+func PendingMigration(eventsFilePath string) (string, bool) {
+	if _, err := os.Stat(eventsFilePath); err == nil {
+		return "", false
+	}
+	oldTextFile := "events.txt"
+	if _, err := os.Stat(oldTextFile); err != nil {
+		return "", false
+	}
+	return oldTextFile, true
+}
+
 // LoadEventsWithConfig loads events using configuration, with automatic migration
 func LoadEventsWithConfig(eventsFilePath string) ([]models.Event, error) {
 	// Check if the configured JSON file exists
@@ -179,8 +391,7 @@ func LoadEventsWithConfig(eventsFilePath string) ([]models.Event, error) {
 	}
 
 	// JSON file doesn't exist, check for old text format file
-	oldTextFile := "events.txt"
-	if _, err := os.Stat(oldTextFile); err == nil {
+	if oldTextFile, ok := PendingMigration(eventsFilePath); ok {
 		fmt.Printf("Found old events.txt file, migrating to JSON format...\n")
 
 		// Migrate from old format
@@ -196,6 +407,32 @@ func LoadEventsWithConfig(eventsFilePath string) ([]models.Event, error) {
 	return []models.Event{}, nil
 }
 
+// skipBOM returns a reader positioned after a leading UTF-8 byte order mark, if present
+func skipBOM(r io.Reader) io.Reader {
+	reader := bufio.NewReader(r)
+	if bom, err := reader.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		reader.Discard(3)
+	}
+	return reader
+}
+
+// LoadImportFile loads events from a file to be imported.
+// Files ending in .txt are read in the legacy text format, files ending in .ics as
+// iCalendar, everything else as JSON.
+func LoadImportFile(filename string) ([]models.Event, error) {
+	if _, err := os.Stat(filename); err != nil {
+		return nil, fmt.Errorf("cannot read import file: %v", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".txt":
+		return LoadEventsFromFile(filename)
+	case ".ics":
+		return LoadICSFile(filename)
+	}
+	return LoadEventsJSON(filename)
+}
+
 // SaveEventWithConfig saves a single event using configuration
 func SaveEventWithConfig(event models.Event, eventsFilePath string) error {
 	return SaveEventJSON(event, eventsFilePath)
@@ -284,7 +521,9 @@ func LoadEventsFromFile(filename string) ([]models.Event, error) {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	// Files edited on Windows may start with a byte order mark and use CRLF line endings;
+	// the BOM is skipped here and the trailing \r is removed by TrimSpace below
+	scanner := bufio.NewScanner(skipBOM(file))
 	lineNum := 0
 
 	for scanner.Scan() {
@@ -320,16 +559,9 @@ func SaveEvent(event models.Event) error {
 
 // SaveEventToFile appends a new event to a specified file (for testing)
 func SaveEventToFile(event models.Event, filename string) error {
-	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to open events file for writing: %v", err)
-	}
-	defer file.Close()
-
 	// Write event in the format: YYYY-MM-DD|HH:MM|description
 	eventLine := event.String()
-	_, err = file.WriteString(eventLine + "\n")
-	if err != nil {
+	if err := appendFileAtomic(filename, []byte(eventLine+"\n"), 0644); err != nil {
 		return fmt.Errorf("failed to write event to file: %v", err)
 	}
 
@@ -353,8 +585,8 @@ func ParseEventLine(line string) (models.Event, error) {
 		return models.Event{}, fmt.Errorf("description cannot be empty")
 	}
 
-	// Parse date
-	eventDate, err := calendar.ParseDate(dateStr)
+	// Parse date in local timezone, like the JSON format, so events compare equal across formats
+	eventDate, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
 	if err != nil {
 		return models.Event{}, fmt.Errorf("invalid date format '%s': %v", dateStr, err)
 	}
@@ -389,6 +621,17 @@ func ValidateEvent(event models.Event) error {
 		return fmt.Errorf("invalid time format: %s", timeStr)
 	}
 
+	if event.Duration < 0 {
+		return fmt.Errorf("event duration cannot be negative")
+	}
+	if event.Reminder < 0 {
+		return fmt.Errorf("event reminder cannot be negative")
+	}
+
+	if !event.EndDate.IsZero() && calendar.NormalizeDate(event.EndDate).Before(calendar.NormalizeDate(event.Date)) {
+		return fmt.Errorf("event cannot end before it starts")
+	}
+
 	return nil
 }
 
@@ -499,19 +742,13 @@ func UpdateEventInFile(oldEvent, newEvent models.Event, filename string) error {
 
 // SaveAllEventsToFile writes all events to a file, replacing the existing content
 func SaveAllEventsToFile(events []models.Event, filename string) error {
-	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to open events file for writing: %v", err)
-	}
-	defer file.Close()
-
+	var buf bytes.Buffer
 	for _, event := range events {
-		eventLine := event.String()
-		_, err = file.WriteString(eventLine + "\n")
-		if err != nil {
-			return fmt.Errorf("failed to write event to file: %v", err)
-		}
+		buf.WriteString(event.String() + "\n")
 	}
 
+	if err := writeFileAtomic(filename, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write events file: %v", err)
+	}
 	return nil
 }
@@ -2,6 +2,8 @@ package storage
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -41,6 +43,16 @@ func TestValidateEvent(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "Negative reminder",
+			event: models.Event{
+				Date:        time.Date(2025, time.August, 16, 0, 0, 0, 0, time.UTC),
+				Time:        time.Date(0, time.January, 1, 9, 30, 0, 0, time.UTC),
+				Description: "Team meeting",
+				Reminder:    -time.Minute,
+			},
+			expectErr: true,
+		},
 		{
 			name: "Valid event with spaces in description",
 			event: models.Event{
@@ -375,3 +387,119 @@ func TestCreateEventFileAtPath(t *testing.T) {
 		t.Errorf("CreateEventFileAtPath() should not error when file already exists: %v", err)
 	}
 }
+
+func TestSaveAndLoadEventsJSON_Category(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "storage_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	filename := filepath.Join(tempDir, "events.json")
+	events := []models.Event{
+		{Date: time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local), Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Standup", Category: "work"},
+		{Date: time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local), Time: time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC), Description: "Gym"},
+	}
+
+	if err := SaveEventsJSON(events, filename); err != nil {
+		t.Fatalf("SaveEventsJSON() failed: %v", err)
+	}
+
+	loaded, err := LoadEventsJSON(filename)
+	if err != nil {
+		t.Fatalf("LoadEventsJSON() failed: %v", err)
+	}
+
+	if len(loaded) != 2 {
+		t.Fatalf("Loaded %d events, want 2", len(loaded))
+	}
+	if loaded[0].Category != "work" {
+		t.Errorf("Category = %q, want 'work'", loaded[0].Category)
+	}
+	if loaded[1].Category != "" {
+		t.Errorf("Uncategorized event has category %q", loaded[1].Category)
+	}
+
+	// Uncategorized events must not carry an empty category key
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read events file: %v", err)
+	}
+	if strings.Count(string(data), "\"category\"") != 1 {
+		t.Errorf("Expected exactly one category key in JSON, got:\n%s", data)
+	}
+}
+
+func TestLoadEventsFromFile_WindowsLineEndings(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "storage_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// UTF-8 BOM and CRLF line endings as written by Windows editors
+	content := "\xEF\xBB\xBF2025-08-15|09:00|Standup\r\n2025-08-16|10:30|Review\r\n"
+	textPath := filepath.Join(tempDir, "events.txt")
+	if err := os.WriteFile(textPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	events, err := LoadEventsFromFile(textPath)
+	if err != nil {
+		t.Fatalf("LoadEventsFromFile() failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("LoadEventsFromFile() returned %d events, want 2", len(events))
+	}
+	if events[0].Description != "Standup" || events[1].Description != "Review" {
+		t.Errorf("Descriptions = %q, %q; want Standup, Review", events[0].Description, events[1].Description)
+	}
+
+	jsonPath := filepath.Join(tempDir, "events.json")
+	jsonContent := "\xEF\xBB\xBF{\r\n  \"events\": [{\"date\": \"2025-08-15\", \"time\": \"09:00\", \"description\": \"Standup\"}]\r\n}\r\n"
+	if err := os.WriteFile(jsonPath, []byte(jsonContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	jsonEvents, err := LoadEventsJSON(jsonPath)
+	if err != nil {
+		t.Fatalf("LoadEventsJSON() with BOM failed: %v", err)
+	}
+	if len(jsonEvents) != 1 {
+		t.Errorf("LoadEventsJSON() returned %d events, want 1", len(jsonEvents))
+	}
+}
+
+func TestSaveAndLoadEventChangesJSON(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "storage_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	date := time.Date(2025, 3, 30, 0, 0, 0, 0, time.Local)
+	before := []models.Event{{Date: date, Time: time.Date(0, 1, 1, 23, 30, 0, 0, time.UTC), Description: "Late call", Category: "work"}}
+	after := []models.Event{{Date: date.AddDate(0, 0, 1), Time: time.Date(0, 1, 1, 0, 30, 0, 0, time.UTC), Description: "Late call", Category: "work"}}
+
+	filename := filepath.Join(tempDir, "undo.json")
+	if err := SaveEventChangesJSON(before, after, filename); err != nil {
+		t.Fatalf("SaveEventChangesJSON() failed: %v", err)
+	}
+
+	loadedBefore, loadedAfter, err := LoadEventChangesJSON(filename)
+	if err != nil {
+		t.Fatalf("LoadEventChangesJSON() failed: %v", err)
+	}
+	if len(loadedBefore) != 1 || loadedBefore[0].GetTimeString() != "23:30" || loadedBefore[0].Category != "work" {
+		t.Errorf("Loaded before = %v", loadedBefore)
+	}
+	if len(loadedAfter) != 1 || loadedAfter[0].GetDateString() != "2025-03-31" || loadedAfter[0].GetTimeString() != "00:30" {
+		t.Errorf("Loaded after = %v", loadedAfter)
+	}
+
+	if err := SaveEventChangesJSON(before, nil, filename); err == nil {
+		t.Error("SaveEventChangesJSON() should reject lists of different lengths")
+	}
+	if _, _, err := LoadEventChangesJSON(filepath.Join(tempDir, "missing.json")); err == nil {
+		t.Error("LoadEventChangesJSON() should fail for a missing file")
+	}
+}
@@ -0,0 +1,407 @@
+package storage
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+// ICSSourcePrefix marks the Source of events imported from iCalendar files; the UID of
+// the VEVENT follows it and is written back on export
+const ICSSourcePrefix = "ics:"
+
+// ICSUntitled is the description of imported events without a SUMMARY
+const ICSUntitled = "(no title)"
+
+// icsProperty is one unfolded content line such as DTSTART;TZID=Europe/Berlin:20250811T090000
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// LoadICSFile loads the events of an iCalendar (.ics) file
+func LoadICSFile(filename string) ([]models.Event, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read iCalendar file: %v", err)
+	}
+	defer file.Close()
+	return ImportICS(file)
+}
+
+// ImportICS reads the VEVENTs of an iCalendar stream as events: DTSTART gives the date
+// and time, DTEND or DURATION the duration and end date and SUMMARY the description.
+// Times in UTC or with a TZID are converted to local time and DATE values make all-day
+// events. Cancelled events are skipped and recurring events are imported as their
+// first occurrence.
+func ImportICS(r io.Reader) ([]models.Event, error) {
+	var events []models.Event
+	var current []icsProperty
+	inEvent, depth, eventLine := false, 0, 0
+
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read iCalendar data: %v", err)
+	}
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseICSProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && !inEvent:
+			inEvent, depth, eventLine, current = true, 0, i+1, nil
+		case !inEvent:
+			// Properties of the calendar and of other components are ignored
+		case prop.name == "BEGIN":
+			// Components nested in the event, such as VALARM, have properties of their own
+			depth++
+		case prop.name == "END" && depth > 0:
+			depth--
+		case depth == 1 && prop.name == "TRIGGER":
+			// The trigger of an alarm becomes the reminder; only the first one is kept
+			current = append(current, prop)
+		case prop.name == "END":
+			inEvent = false
+			event, ok, err := icsEvent(current)
+			if err != nil {
+				return nil, fmt.Errorf("event at line %d: %v", eventLine, err)
+			}
+			if ok {
+				events = append(events, event)
+			}
+		case depth == 0:
+			current = append(current, prop)
+		}
+	}
+	if inEvent {
+		return nil, fmt.Errorf("event at line %d: missing END:VEVENT", eventLine)
+	}
+	return events, nil
+}
+
+// unfoldICSLines splits iCalendar data into content lines, joining continuation lines
+// that start with a space or tab to the line before them
+func unfoldICSLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(skipBOM(r))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseICSProperty splits a content line into its name, parameters and value.
+// Parameter values may be quoted and then contain ':' and ';'.
+func parseICSProperty(line string) (icsProperty, error) {
+	prop := icsProperty{params: map[string]string{}}
+	quoted := false
+	var fields []string
+	start := 0
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case r == ';':
+			fields = append(fields, line[start:i])
+			start = i + 1
+		case r == ':':
+			fields = append(fields, line[start:i])
+			prop.value = line[i+1:]
+			prop.name = strings.ToUpper(fields[0])
+			for _, param := range fields[1:] {
+				name, value, _ := strings.Cut(param, "=")
+				prop.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+			}
+			return prop, nil
+		}
+	}
+	return prop, fmt.Errorf("invalid content line %q: missing ':'", line)
+}
+
+// icsEvent converts the properties of a VEVENT into an event; ok is false for
+// cancelled events
+func icsEvent(props []icsProperty) (models.Event, bool, error) {
+	var event models.Event
+	var start, end *icsProperty
+	var duration string
+	uid, summary := "", ""
+	for i, prop := range props {
+		switch prop.name {
+		case "DTSTART":
+			start = &props[i]
+		case "DTEND":
+			end = &props[i]
+		case "DURATION":
+			duration = prop.value
+		case "SUMMARY":
+			summary = unescapeICSText(prop.value)
+		case "DESCRIPTION":
+			event.Notes = strings.TrimSpace(unescapeICSText(prop.value))
+		case "UID":
+			uid = prop.value
+		case "CATEGORIES":
+			category, _, _ := strings.Cut(prop.value, ",")
+			event.Category = unescapeICSText(category)
+		case "STATUS":
+			if strings.EqualFold(prop.value, "CANCELLED") {
+				return models.Event{}, false, nil
+			}
+		case "TRIGGER":
+			if event.Reminder == 0 {
+				event.Reminder = icsReminder(prop)
+			}
+		}
+	}
+	if start == nil {
+		return models.Event{}, false, fmt.Errorf("missing DTSTART")
+	}
+
+	startTime, allDay, err := parseICSTime(*start)
+	if err != nil {
+		return models.Event{}, false, fmt.Errorf("invalid DTSTART: %v", err)
+	}
+	endTime := startTime
+	switch {
+	case end != nil:
+		if endTime, _, err = parseICSTime(*end); err != nil {
+			return models.Event{}, false, fmt.Errorf("invalid DTEND: %v", err)
+		}
+	case duration != "":
+		length, err := parseICSDuration(duration)
+		if err != nil {
+			return models.Event{}, false, fmt.Errorf("invalid DURATION: %v", err)
+		}
+		endTime = startTime.Add(length)
+	}
+	if endTime.After(startTime) {
+		if allDay {
+			// The end of an all-day event is the day after its last day
+			event.AllDay = true
+			event.EndDate = icsDate(endTime.AddDate(0, 0, -1))
+		} else {
+			event.Duration = endTime.Sub(startTime)
+			// An event ending at midnight ends on the day before
+			event.EndDate = icsDate(endTime.Add(-time.Nanosecond))
+		}
+	} else if allDay {
+		event.AllDay = true
+	}
+	if !event.EndDate.After(icsDate(startTime)) {
+		event.EndDate = time.Time{}
+	}
+
+	event.Date = icsDate(startTime)
+	event.Time = time.Date(0, 1, 1, startTime.Hour(), startTime.Minute(), 0, 0, time.UTC)
+	// Events are single lines, so line breaks of the summary become spaces
+	event.Description = strings.Join(strings.Fields(summary), " ")
+	if event.Description == "" {
+		event.Description = ICSUntitled
+	}
+	if uid != "" {
+		event.Source = ICSSourcePrefix + uid
+	}
+	return event, true, nil
+}
+
+// icsReminder returns how long before the start an alarm trigger such as -PT15M goes
+// off; triggers at an absolute time or relative to the end are not supported and
+// give zero
+func icsReminder(prop icsProperty) time.Duration {
+	if strings.EqualFold(prop.params["VALUE"], "DATE-TIME") || strings.EqualFold(prop.params["RELATED"], "END") {
+		return 0
+	}
+	value, before := strings.CutPrefix(prop.value, "-")
+	if !before {
+		return 0
+	}
+	reminder, err := parseICSDuration(value)
+	if err != nil {
+		return 0
+	}
+	return reminder
+}
+
+// icsDate returns the local date of t
+func icsDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// parseICSTime parses a DATE or DATE-TIME value into local time. Floating times are
+// taken as local; allDay reports a DATE value.
+func parseICSTime(prop icsProperty) (t time.Time, allDay bool, err error) {
+	value := prop.value
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err = time.ParseInLocation("20060102", value, time.Local)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse("20060102T150405Z", value)
+		return t.Local(), false, err
+	}
+	location := time.Local
+	if tzid := prop.params["TZID"]; tzid != "" {
+		// Unknown zones, such as Outlook's Windows names, are treated as local time
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			location = loc
+		}
+	}
+	t, err = time.ParseInLocation("20060102T150405", value, location)
+	return t.Local(), false, err
+}
+
+// icsDurationPattern matches durations such as PT1H30M, P1D or P2W
+var icsDurationPattern = regexp.MustCompile(`^\+?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseICSDuration parses an iCalendar DURATION value
+func parseICSDuration(value string) (time.Duration, error) {
+	match := icsDurationPattern.FindStringSubmatch(value)
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("unsupported duration %q", value)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if match[i+1] != "" {
+			n, _ := strconv.Atoi(match[i+1])
+			duration += time.Duration(n) * unit
+		}
+	}
+	return duration, nil
+}
+
+// unescapeICSText reverses the escaping of iCalendar text values
+func unescapeICSText(text string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(text)
+}
+
+// escapeICSText escapes the characters iCalendar text values reserve
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
+
+// SaveICSFile writes events to an iCalendar (.ics) file, their times being in loc
+func SaveICSFile(events []models.Event, filename string, loc *time.Location) error {
+	err := WriteFileAtomic(filename, 0644, func(w io.Writer) error {
+		return ExportICS(w, events, time.Now(), loc)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", filename, err)
+	}
+	return nil
+}
+
+// ExportICS writes events as an iCalendar stream that calendar applications such as
+// Google Calendar and Outlook import. Times are taken to be in loc, the local zone when
+// it is nil, and written in UTC so that recipients in other zones see the same moment;
+// all-day events are written as dates, and stamp is the DTSTAMP of every event. Events
+// imported from iCalendar keep their UID; the others get one derived from their date,
+// time and description.
+func ExportICS(w io.Writer, events []models.Event, stamp time.Time, loc *time.Location) error {
+	if loc == nil {
+		loc = time.Local
+	}
+	bw := bufio.NewWriter(w)
+	line := func(text string) {
+		bw.WriteString(foldICSLine(text))
+		bw.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//go-ascii-calendar//EN")
+	line("CALSCALE:GREGORIAN")
+	const utc = "20060102T150405Z"
+	dtstamp := stamp.UTC().Format(utc)
+	for _, event := range events {
+		start := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), event.Time.Hour(), event.Time.Minute(), 0, 0, loc)
+		line("BEGIN:VEVENT")
+		line("UID:" + ICSUID(event))
+		line("DTSTAMP:" + dtstamp)
+		switch {
+		case event.AllDay:
+			line("DTSTART;VALUE=DATE:" + start.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + event.LastDate().AddDate(0, 0, 1).Format("20060102"))
+		case event.Duration > 0:
+			line("DTSTART:" + start.UTC().Format(utc))
+			line("DTEND:" + start.Add(event.Duration).UTC().Format(utc))
+		case event.IsMultiDay():
+			// Without a duration the event lasts until the end of its last day
+			last := event.LastDate()
+			line("DTSTART:" + start.UTC().Format(utc))
+			line("DTEND:" + time.Date(last.Year(), last.Month(), last.Day()+1, 0, 0, 0, 0, loc).UTC().Format(utc))
+		default:
+			line("DTSTART:" + start.UTC().Format(utc))
+		}
+		line("SUMMARY:" + escapeICSText(event.Description))
+		if event.Notes != "" {
+			line("DESCRIPTION:" + escapeICSText(event.Notes))
+		}
+		if event.Category != "" {
+			line("CATEGORIES:" + escapeICSText(event.Category))
+		}
+		if event.Reminder > 0 {
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line("DESCRIPTION:" + escapeICSText(event.Description))
+			line(fmt.Sprintf("TRIGGER:-PT%dM", int(event.Reminder/time.Minute)))
+			line("END:VALARM")
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write iCalendar data: %v", err)
+	}
+	return nil
+}
+
+// ICSUID returns the UID of an exported event: the UID it was imported with, or one
+// derived from its date, time and description
+func ICSUID(event models.Event) string {
+	if uid, ok := strings.CutPrefix(event.Source, ICSSourcePrefix); ok && uid != "" {
+		return uid
+	}
+	sum := sha1.Sum([]byte(event.String()))
+	return hex.EncodeToString(sum[:8]) + "@go-ascii-calendar"
+}
+
+// foldICSLine splits a content line longer than 75 octets into continuation lines
+// starting with a space, never inside a UTF-8 sequence
+func foldICSLine(text string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range text {
+		size := len(string(r))
+		if length+size > 75 {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	return b.String()
+}
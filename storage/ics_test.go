@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+const googleExport = "BEGIN:VCALENDAR\r\n" +
+	"PRODID:-//Google Inc//Google Calendar 70.9054//EN\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;TZID=\"UTC\":20250811T090000\r\n" +
+	"DTEND;TZID=\"UTC\":20250811T093000\r\n" +
+	"UID:standup-1@google.com\r\n" +
+	"SUMMARY:Standup\\, team\r\n" +
+	" \\; daily\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"SUMMARY:Reminder\r\n" +
+	"TRIGGER:-PT10M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20250815\r\n" +
+	"DTEND;VALUE=DATE:20250816\r\n" +
+	"SUMMARY:Holiday\r\n" +
+	"CATEGORIES:Personal,Travel\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20250812T140000\r\n" +
+	"DURATION:PT1H30M\r\n" +
+	"SUMMARY:Review\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20250813T100000\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"SUMMARY:Cancelled meeting\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20250820\r\n" +
+	"DTEND;VALUE=DATE:20250825\r\n" +
+	"SUMMARY:Trip to Lisbon\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20250822T180000\r\n" +
+	"DTEND:20250824T000000\r\n" +
+	"SUMMARY:Festival\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20250814T080000\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestImportICS(t *testing.T) {
+	events, err := ImportICS(strings.NewReader(googleExport))
+	if err != nil {
+		t.Fatalf("ImportICS() failed: %v", err)
+	}
+	if len(events) != 6 {
+		t.Fatalf("ImportICS() returned %d events, want 6: %v", len(events), events)
+	}
+
+	standupStart := time.Date(2025, 8, 11, 9, 0, 0, 0, time.UTC).Local()
+	tests := []struct {
+		date, time, description, category, source string
+		duration                                  time.Duration
+		allDay                                    bool
+		lastDate                                  string
+	}{
+		{standupStart.Format("2006-01-02"), standupStart.Format("15:04"), "Standup, team; daily", "", "ics:standup-1@google.com", 30 * time.Minute, false, standupStart.Format("2006-01-02")},
+		{"2025-08-15", "00:00", "Holiday", "Personal", "", 0, true, "2025-08-15"},
+		{"2025-08-12", "14:00", "Review", "", "", 90 * time.Minute, false, "2025-08-12"},
+		{"2025-08-20", "00:00", "Trip to Lisbon", "", "", 0, true, "2025-08-24"},
+		{"2025-08-22", "18:00", "Festival", "", "", 30 * time.Hour, false, "2025-08-23"},
+		{"2025-08-14", "08:00", ICSUntitled, "", "", 0, false, "2025-08-14"},
+	}
+	if events[0].Reminder != 10*time.Minute || events[1].Reminder != 0 {
+		t.Errorf("Reminders = %v and %v, want the 10 minutes of the alarm and none", events[0].Reminder, events[1].Reminder)
+	}
+	for i, tt := range tests {
+		got := events[i]
+		if got.GetDateString() != tt.date || got.GetTimeString() != tt.time || got.Description != tt.description {
+			t.Errorf("Event %d = %s, want %s|%s|%s", i, got.String(), tt.date, tt.time, tt.description)
+		}
+		if got.Category != tt.category || got.Source != tt.source || got.Duration != tt.duration {
+			t.Errorf("Event %d has category %q, source %q, duration %v; want %q, %q, %v",
+				i, got.Category, got.Source, got.Duration, tt.category, tt.source, tt.duration)
+		}
+		if got.AllDay != tt.allDay || got.LastDate().Format("2006-01-02") != tt.lastDate {
+			t.Errorf("Event %d has all day %v and last date %s; want %v and %s",
+				i, got.AllDay, got.LastDate().Format("2006-01-02"), tt.allDay, tt.lastDate)
+		}
+	}
+}
+
+func TestImportICS_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing DTSTART":    "BEGIN:VEVENT\nSUMMARY:No start\nEND:VEVENT\n",
+		"invalid DTSTART":    "BEGIN:VEVENT\nDTSTART:tomorrow\nSUMMARY:Bad\nEND:VEVENT\n",
+		"invalid DURATION":   "BEGIN:VEVENT\nDTSTART:20250811T090000\nDURATION:1h\nSUMMARY:Bad\nEND:VEVENT\n",
+		"missing END:VEVENT": "BEGIN:VEVENT\nDTSTART:20250811T090000\nSUMMARY:Open\n",
+		"invalid line":       "BEGIN:VEVENT\nnot a property\nEND:VEVENT\n",
+	}
+	for name, data := range tests {
+		if _, err := ImportICS(strings.NewReader(data)); err == nil {
+			t.Errorf("ImportICS() should fail for %s", name)
+		}
+	}
+}
+
+func TestParseICSDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT15M":     15 * time.Minute,
+		"PT1H30M":   90 * time.Minute,
+		"P1D":       24 * time.Hour,
+		"P1W":       7 * 24 * time.Hour,
+		"P1DT2H":    26 * time.Hour,
+		"PT1H0M30S": time.Hour + 30*time.Second,
+	}
+	for value, want := range tests {
+		if got, err := parseICSDuration(value); err != nil || got != want {
+			t.Errorf("parseICSDuration(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "P", "PT", "1H", "-PT1H", "PT1.5H"} {
+		if _, err := parseICSDuration(value); err == nil {
+			t.Errorf("parseICSDuration(%q) should fail", value)
+		}
+	}
+}
+
+func TestExportICS_RoundTrip(t *testing.T) {
+	original := []models.Event{
+		{
+			Date:        time.Date(2025, 8, 11, 0, 0, 0, 0, time.Local),
+			Time:        time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			Description: "Standup, team; daily \\ notes",
+			Duration:    15 * time.Minute,
+			Category:    "Work",
+			Source:      "ics:standup-1@google.com",
+			Reminder:    10 * time.Minute,
+		},
+		{
+			Date:        time.Date(2025, 8, 12, 0, 0, 0, 0, time.Local),
+			Time:        time.Date(0, 1, 1, 18, 30, 0, 0, time.UTC),
+			Description: "Dinner at the harbour with a description long enough to be folded, ünïcödé included",
+			Notes:       "Table for four; ask for the terrace\n\nMenu: https://example.com/menu",
+		},
+		{
+			Date:        time.Date(2025, 8, 20, 0, 0, 0, 0, time.Local),
+			Time:        time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC),
+			Description: "Trip to Lisbon",
+			EndDate:     time.Date(2025, 8, 24, 0, 0, 0, 0, time.Local),
+			AllDay:      true,
+		},
+	}
+
+	var buf bytes.Buffer
+	stamp := time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC)
+	if err := ExportICS(&buf, original, stamp, nil); err != nil {
+		t.Fatalf("ExportICS() failed: %v", err)
+	}
+	data := buf.String()
+	for _, want := range []string{"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n", "UID:standup-1@google.com\r\n", "DTSTAMP:20250810T120000Z\r\n", "DTEND:" + time.Date(2025, 8, 11, 9, 15, 0, 0, time.Local).UTC().Format("20060102T150405Z") + "\r\n", "DTSTART;VALUE=DATE:20250820\r\nDTEND;VALUE=DATE:20250825\r\n", "BEGIN:VALARM\r\nACTION:DISPLAY\r\n"} {
+		if !strings.Contains(data, want) {
+			t.Errorf("Exported data misses %q:\n%s", want, data)
+		}
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line %d is %d octets long, want at most 75: %q", i, len(line), line)
+		}
+	}
+
+	imported, err := ImportICS(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ImportICS() of the exported data failed: %v", err)
+	}
+	if len(imported) != len(original) {
+		t.Fatalf("Round trip returned %d events, want %d", len(imported), len(original))
+	}
+	for i := range original {
+		if imported[i].String() != original[i].String() || imported[i].Duration != original[i].Duration || imported[i].Category != original[i].Category ||
+			imported[i].Reminder != original[i].Reminder || imported[i].Notes != original[i].Notes || imported[i].AllDay != original[i].AllDay || !imported[i].EndDate.Equal(original[i].EndDate) {
+			t.Errorf("Event %d changed in the round trip: %+v, want %+v", i, imported[i], original[i])
+		}
+	}
+	if imported[0].Source != original[0].Source {
+		t.Errorf("Imported events should keep their UID, got source %q", imported[0].Source)
+	}
+}
+
+func TestExportICS_TimeZone(t *testing.T) {
+	events := []models.Event{
+		{Date: time.Date(2025, 8, 11, 0, 0, 0, 0, time.Local), Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Standup", Duration: 15 * time.Minute},
+		{Date: time.Date(2025, 8, 20, 0, 0, 0, 0, time.Local), Description: "Trip", AllDay: true},
+	}
+	tests := map[string][]string{
+		"Europe/Berlin":    {"DTSTART:20250811T070000Z\r\nDTEND:20250811T071500Z\r\n", "DTSTART;VALUE=DATE:20250820\r\n"},
+		"America/New_York": {"DTSTART:20250811T130000Z\r\nDTEND:20250811T131500Z\r\n", "DTSTART;VALUE=DATE:20250820\r\n"},
+	}
+
+	for name, wants := range tests {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Skipf("Time zone data unavailable: %v", err)
+		}
+		var buf bytes.Buffer
+		if err := ExportICS(&buf, events, time.Date(2025, 8, 10, 12, 0, 0, 0, time.UTC), loc); err != nil {
+			t.Fatalf("ExportICS() failed: %v", err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s: exported data misses %q:\n%s", name, want, buf.String())
+			}
+		}
+	}
+}
+
+func TestLoadImportFile_ICS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calendar.ICS")
+	if err := os.WriteFile(path, []byte(googleExport), 0644); err != nil {
+		t.Fatalf("Failed to write the calendar: %v", err)
+	}
+	events, err := LoadImportFile(path)
+	if err != nil || len(events) != 6 {
+		t.Errorf("LoadImportFile() = %d events, %v; want 6 events from the iCalendar file", len(events), err)
+	}
+}
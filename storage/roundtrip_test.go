@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata" // Zones for the round trips below, independent of the system's zoneinfo
+
+	"go-ascii-calendar/models"
+)
+
+// The golden files in testdata pin the exact bytes written for their events. After an
+// intended format change, regenerate them with: go test ./storage -run Golden -update
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenFormat loads and saves one events file format
+type goldenFormat struct {
+	file string
+	load func(filename string) ([]models.Event, error)
+	save func(events []models.Event, filename string) error
+}
+
+var goldenFormats = []goldenFormat{
+	{"events.golden.json", LoadEventsJSON, SaveEventsJSON},
+	{"events.golden.txt", LoadEventsFromFile, SaveAllEventsToFile},
+}
+
+// roundTripZones are the local time zones every round trip runs in: dates are parsed
+// in the local zone, so a zone change must never move an event to another day
+var roundTripZones = []string{"UTC", "America/New_York", "Europe/Berlin", "Asia/Kolkata", "Pacific/Auckland"}
+
+// inZone runs fn with time.Local set to the named zone
+func inZone(t *testing.T, name string, fn func()) {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("Failed to load zone %s: %v", name, err)
+	}
+	saved := time.Local
+	time.Local = location
+	defer func() { time.Local = saved }()
+	fn()
+}
+
+func TestGoldenRoundTrip(t *testing.T) {
+	for _, format := range goldenFormats {
+		for _, zone := range roundTripZones {
+			t.Run(format.file+"/"+zone, func(t *testing.T) {
+				inZone(t, zone, func() { checkGoldenRoundTrip(t, format) })
+			})
+		}
+	}
+}
+
+// checkGoldenRoundTrip loads a golden file, saves the events and checks that the bytes
+// written match the golden file and load back to the same events
+func checkGoldenRoundTrip(t *testing.T, format goldenFormat) {
+	golden := filepath.Join("testdata", format.file)
+	imported, err := format.load(golden)
+	if err != nil || len(imported) == 0 {
+		t.Fatalf("Loading %s = %d events, %v", golden, len(imported), err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "roundtrip_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	exported := filepath.Join(tempDir, format.file)
+	if err := format.save(imported, exported); err != nil {
+		t.Fatalf("Saving %d events failed: %v", len(imported), err)
+	}
+	written, err := os.ReadFile(exported)
+	if err != nil {
+		t.Fatalf("Failed to read the exported file: %v", err)
+	}
+	if *update {
+		if err := os.WriteFile(golden, written, 0644); err != nil {
+			t.Fatalf("Failed to update %s: %v", golden, err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", golden, err)
+	}
+	if !bytes.Equal(written, want) {
+		t.Errorf("Exported file differs from %s:\n%s\nwant:\n%s", golden, written, want)
+	}
+
+	reimported, err := format.load(exported)
+	if err != nil {
+		t.Fatalf("Reloading the exported file failed: %v", err)
+	}
+	if !reflect.DeepEqual(reimported, imported) {
+		t.Errorf("Reloaded events differ:\n%v\nwant:\n%v", reimported, imported)
+	}
+	for _, event := range imported {
+		if event.Date.Location() != time.Local || event.Date.Hour() != 0 {
+			t.Errorf("Event %q is dated %v, want local midnight", event.Description, event.Date)
+		}
+	}
+}
+
+func TestGoldenCrossFormat(t *testing.T) {
+	// The legacy text format only keeps the date, time and description, which must
+	// survive a trip from JSON through text and back; pipes in descriptions included
+	jsonEvents, err := LoadEventsJSON(filepath.Join("testdata", "events.golden.json"))
+	if err != nil {
+		t.Fatalf("Failed to load the JSON golden file: %v", err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "crossformat_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	textFile := filepath.Join(tempDir, "events.txt")
+	if err := SaveAllEventsToFile(jsonEvents, textFile); err != nil {
+		t.Fatalf("SaveAllEventsToFile() failed: %v", err)
+	}
+	if err := MigrateToJSON(textFile, filepath.Join(tempDir, "events.json")); err != nil {
+		t.Fatalf("MigrateToJSON() failed: %v", err)
+	}
+	migrated, err := LoadEventsJSON(filepath.Join(tempDir, "events.json"))
+	if err != nil {
+		t.Fatalf("Failed to load the migrated events: %v", err)
+	}
+
+	if len(migrated) != len(jsonEvents) {
+		t.Fatalf("Migrated %d events, want %d", len(migrated), len(jsonEvents))
+	}
+	for i, event := range migrated {
+		original := jsonEvents[i]
+		if event.String() != original.String() {
+			t.Errorf("Event %d = %q, want %q", i, event.String(), original.String())
+		}
+	}
+}
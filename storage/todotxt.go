@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/models"
+)
+
+// TodoSourcePrefix marks the Source of events imported from todo.txt files
+const TodoSourcePrefix = "todo:"
+
+// TodoDefaultTime is the event time of tasks without an at:HH:MM extension
+const TodoDefaultTime = "09:00"
+
+// todoPriority matches a leading todo.txt priority such as "(A) "
+var todoPriority = regexp.MustCompile(`^\(([A-Z])\)\s+`)
+
+// todoCreationDate matches a leading creation date such as "2025-08-01 "
+var todoCreationDate = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\s+`)
+
+// LoadTodoTxtFile loads the open tasks with a due: date from a todo.txt file as events
+func LoadTodoTxtFile(filename string) ([]models.Event, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read todo.txt file: %v", err)
+	}
+	defer file.Close()
+
+	var events []models.Event
+	scanner := bufio.NewScanner(skipBOM(file))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		event, ok, err := ParseTodoTxtLine(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		if ok {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read todo.txt file: %v", err)
+	}
+	return events, nil
+}
+
+// ParseTodoTxtLine converts one todo.txt task into an event dated by its due: tag.
+// Blank lines, completed tasks and tasks without a due date are skipped (ok is false).
+// The event's Source identifies the task by its text, so it stays the same when the
+// due date or priority changes.
+func ParseTodoTxtLine(line string) (models.Event, bool, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "x ") {
+		return models.Event{}, false, nil
+	}
+
+	var priority string
+	if match := todoPriority.FindStringSubmatch(line); match != nil {
+		priority = match[1]
+		line = line[len(match[0]):]
+	}
+	line = todoCreationDate.ReplaceAllString(line, "")
+
+	var due, at string
+	var words []string
+	for _, word := range strings.Fields(line) {
+		switch {
+		case strings.HasPrefix(word, "due:"):
+			due = strings.TrimPrefix(word, "due:")
+		case strings.HasPrefix(word, "at:"):
+			at = strings.TrimPrefix(word, "at:")
+		default:
+			words = append(words, word)
+		}
+	}
+	if due == "" {
+		return models.Event{}, false, nil
+	}
+
+	description := strings.Join(words, " ")
+	if description == "" {
+		return models.Event{}, false, fmt.Errorf("task without text")
+	}
+
+	// Parse in the local timezone like dates in the events file
+	date, err := time.ParseInLocation("2006-01-02", due, time.Local)
+	if err != nil {
+		return models.Event{}, false, fmt.Errorf("invalid due date '%s': %v", due, err)
+	}
+
+	if at == "" {
+		at = TodoDefaultTime
+	}
+	if !calendar.ValidateTimeString(at) {
+		return models.Event{}, false, fmt.Errorf("invalid time '%s': expected at:HH:MM", at)
+	}
+	eventTime, err := calendar.ParseTime(at)
+	if err != nil {
+		return models.Event{}, false, fmt.Errorf("failed to parse time '%s': %v", at, err)
+	}
+
+	return models.Event{
+		Date:        date,
+		Time:        eventTime,
+		Description: description,
+		Priority:    priority,
+		Source:      todoSource(description),
+	}, true, nil
+}
+
+// todoSource derives the identity of a task from its text
+func todoSource(description string) string {
+	sum := sha1.Sum([]byte(strings.ToLower(description)))
+	return TodoSourcePrefix + hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTodoTxtLine(t *testing.T) {
+	tests := []struct {
+		name         string
+		line         string
+		wantOK       bool
+		wantErr      bool
+		wantDate     string
+		wantTime     string
+		wantDesc     string
+		wantPriority string
+	}{
+		{"Due task", "Pay rent +home due:2025-09-01", true, false, "2025-09-01", "09:00", "Pay rent +home", ""},
+		{"Priority and time", "(A) Call dentist @phone due:2025-08-20 at:14:30", true, false, "2025-08-20", "14:30", "Call dentist @phone", "A"},
+		{"Creation date", "(B) 2025-08-01 Renew passport due:2025-10-01", true, false, "2025-10-01", "09:00", "Renew passport", "B"},
+		{"No due date", "(C) Someday learn Go", false, false, "", "", "", ""},
+		{"Completed", "x 2025-08-02 Pay rent due:2025-08-01", false, false, "", "", "", ""},
+		{"Blank", "   ", false, false, "", "", "", ""},
+		{"Invalid due date", "Pay rent due:next-week", false, true, "", "", "", ""},
+		{"Invalid time", "Pay rent due:2025-09-01 at:25:00", false, true, "", "", "", ""},
+		{"Only tags", "due:2025-09-01", false, true, "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, ok, err := ParseTodoTxtLine(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTodoTxtLine(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("ParseTodoTxtLine(%q) ok = %v, want %v", tt.line, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if event.GetDateString() != tt.wantDate || event.GetTimeString() != tt.wantTime ||
+				event.Description != tt.wantDesc || event.Priority != tt.wantPriority {
+				t.Errorf("ParseTodoTxtLine(%q) = %s %s %q (%s), want %s %s %q (%s)", tt.line,
+					event.GetDateString(), event.GetTimeString(), event.Description, event.Priority,
+					tt.wantDate, tt.wantTime, tt.wantDesc, tt.wantPriority)
+			}
+		})
+	}
+}
+
+func TestParseTodoTxtLine_SourceIgnoresDueDateAndPriority(t *testing.T) {
+	first, _, _ := ParseTodoTxtLine("(C) Pay rent +home due:2025-09-01")
+	moved, _, _ := ParseTodoTxtLine("(A) 2025-08-01 Pay rent +home due:2025-09-05 at:08:00")
+	other, _, _ := ParseTodoTxtLine("Pay gas bill due:2025-09-01")
+
+	if first.Source == "" || first.Source != moved.Source {
+		t.Errorf("Sources %q and %q should identify the same task", first.Source, moved.Source)
+	}
+	if first.Source == other.Source {
+		t.Errorf("Different tasks share source %q", first.Source)
+	}
+}
+
+func TestLoadTodoTxtFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "storage_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	filename := filepath.Join(tempDir, "todo.txt")
+	content := "(A) Call dentist due:2025-08-20\r\nLearn Go\r\nx Done task due:2025-08-01\r\nPay rent due:2025-09-01\r\n"
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write todo.txt: %v", err)
+	}
+
+	events, err := LoadTodoTxtFile(filename)
+	if err != nil {
+		t.Fatalf("LoadTodoTxtFile() failed: %v", err)
+	}
+	if len(events) != 2 || events[0].Description != "Call dentist" || events[1].Description != "Pay rent" {
+		t.Errorf("LoadTodoTxtFile() = %v, want the two open tasks with due dates", events)
+	}
+
+	if err := os.WriteFile(filename, []byte("Pay rent due:tomorrow\n"), 0644); err != nil {
+		t.Fatalf("Failed to write todo.txt: %v", err)
+	}
+	if _, err := LoadTodoTxtFile(filename); err == nil {
+		t.Error("LoadTodoTxtFile() should report an invalid due date")
+	}
+}
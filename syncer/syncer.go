@@ -0,0 +1,199 @@
+package syncer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RunFunc executes a sync command
+type RunFunc func(command string) error
+
+// Status describes the outcome of the most recent sync command
+type Status struct {
+	Operation string    // "pull" or "push"
+	At        time.Time // When the command finished
+	Err       error     // Failure of the command, nil on success
+}
+
+// Syncer runs user-configured pull and push commands to synchronize the data
+// directory with external storage, without implementing any protocol itself.
+type Syncer struct {
+	pullCmd string
+	pushCmd string
+	run     RunFunc
+	now     func() time.Time
+
+	mu      sync.Mutex // Serializes commands and guards the fields below
+	status  Status
+	pulled  bool // A pull succeeded since the last TakePulled call
+	stopped chan struct{}
+
+	// The round in progress in the background and whether the data directory was
+	// written locally since it started. Guarded by roundMu rather than mu, so noting a
+	// write never waits for a running command.
+	roundMu     sync.Mutex
+	inRound     bool
+	roundWrite  bool
+	overwritten bool // A pull of a round may have replaced files holding local writes
+}
+
+// New creates a syncer for the given commands; either may be empty to skip that direction
+func New(pullCmd, pushCmd string, run RunFunc) *Syncer {
+	return &Syncer{
+		pullCmd: pullCmd,
+		pushCmd: pushCmd,
+		run:     run,
+		now:     time.Now,
+	}
+}
+
+// IsEnabled reports whether any sync command is configured
+func (s *Syncer) IsEnabled() bool {
+	return s.pullCmd != "" || s.pushCmd != ""
+}
+
+// Pull runs the pull command, if configured
+func (s *Syncer) Pull() error {
+	return s.execute("pull", s.pullCmd)
+}
+
+// Push runs the push command, if configured
+func (s *Syncer) Push() error {
+	return s.execute("push", s.pushCmd)
+}
+
+// execute runs one command and records its status
+func (s *Syncer) execute(operation, command string) error {
+	if command == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.run(command)
+	if err != nil {
+		err = fmt.Errorf("sync %s failed: %v", operation, err)
+	}
+	s.status = Status{Operation: operation, At: s.now(), Err: err}
+	if err == nil && operation == "pull" {
+		s.pulled = true
+	}
+	return err
+}
+
+// NoteLocalWrite records that the data directory is about to be written locally. A
+// background round that has started pushing no longer knows whether the write was
+// pushed, so it skips its pull, or reports its pull with TakeOverwritten.
+func (s *Syncer) NoteLocalWrite() {
+	s.roundMu.Lock()
+	defer s.roundMu.Unlock()
+	if s.inRound {
+		s.roundWrite = true
+	}
+}
+
+// TakeOverwritten reports whether a background pull since the previous call may have
+// replaced files written locally while it ran. The data in memory is then newer than
+// the files and is to be written back rather than reloaded; the next round pushes it.
+func (s *Syncer) TakeOverwritten() bool {
+	s.roundMu.Lock()
+	defer s.roundMu.Unlock()
+
+	overwritten := s.overwritten
+	s.overwritten = false
+	return overwritten
+}
+
+// TakePulled reports whether a pull succeeded since the previous call, so callers
+// know to reload data changed by the pull
+func (s *Syncer) TakePulled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pulled := s.pulled
+	s.pulled = false
+	return pulled
+}
+
+// Status returns the outcome of the most recent command
+func (s *Syncer) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// StatusText returns a short status line, e.g. "sync: pulled 14:05"; empty when disabled
+func (s *Syncer) StatusText() string {
+	if !s.IsEnabled() {
+		return ""
+	}
+
+	status := s.Status()
+	switch {
+	case status.At.IsZero():
+		return "sync: pending"
+	case status.Err != nil:
+		return fmt.Sprintf("sync: %s failed %s", status.Operation, status.At.Format("15:04"))
+	default:
+		return fmt.Sprintf("sync: %sed %s", status.Operation, status.At.Format("15:04"))
+	}
+}
+
+// Start pushes and then pulls every interval in the background until Stop is called.
+// notify is called after each round, e.g. to wake up the UI so it can reload.
+func (s *Syncer) Start(interval time.Duration, notify func()) {
+	if !s.IsEnabled() || interval <= 0 || s.stopped != nil {
+		return
+	}
+
+	s.stopped = make(chan struct{})
+	go func(stop <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				s.round()
+				if notify != nil {
+					notify()
+				}
+			}
+		}
+	}(s.stopped)
+}
+
+// round pushes and then pulls, so the pull brings back what was pushed along with the
+// changes made elsewhere. Local writes during the round were not pushed: a write during
+// the push skips the pull, and one during the pull is reported by TakeOverwritten.
+func (s *Syncer) round() {
+	s.roundMu.Lock()
+	s.inRound, s.roundWrite = true, false
+	s.roundMu.Unlock()
+
+	_ = s.Push()
+
+	s.roundMu.Lock()
+	skip := s.roundWrite
+	s.roundMu.Unlock()
+	pulled := !skip && s.Pull() == nil && s.pullCmd != ""
+
+	s.roundMu.Lock()
+	defer s.roundMu.Unlock()
+	if pulled && s.roundWrite {
+		s.overwritten = true
+	}
+	s.inRound, s.roundWrite = false, false
+}
+
+// Stop ends background syncing started with Start
+func (s *Syncer) Stop() {
+	if s.stopped != nil {
+		close(s.stopped)
+		s.stopped = nil
+	}
+}
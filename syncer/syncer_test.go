@@ -0,0 +1,139 @@
+package syncer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncer_PullAndPush(t *testing.T) {
+	var commands []string
+	run := func(command string) error {
+		commands = append(commands, command)
+		if command == "broken-push" {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}
+
+	s := New("rclone copy remote: data", "broken-push", run)
+	s.now = func() time.Time { return time.Date(2025, 8, 15, 14, 5, 0, 0, time.Local) }
+
+	if text := s.StatusText(); text != "sync: pending" {
+		t.Errorf("StatusText() before any sync = %q, want 'sync: pending'", text)
+	}
+
+	if err := s.Pull(); err != nil {
+		t.Fatalf("Pull() failed: %v", err)
+	}
+	if text := s.StatusText(); text != "sync: pulled 14:05" {
+		t.Errorf("StatusText() after pull = %q, want 'sync: pulled 14:05'", text)
+	}
+	if !s.TakePulled() {
+		t.Error("TakePulled() should report the successful pull")
+	}
+	if s.TakePulled() {
+		t.Error("TakePulled() should only report a pull once")
+	}
+
+	if err := s.Push(); err == nil {
+		t.Error("Push() should report the failing command")
+	}
+	if text := s.StatusText(); text != "sync: push failed 14:05" {
+		t.Errorf("StatusText() after failed push = %q, want 'sync: push failed 14:05'", text)
+	}
+
+	if len(commands) != 2 || commands[0] != "rclone copy remote: data" {
+		t.Errorf("Executed commands = %v", commands)
+	}
+}
+
+func TestSyncer_Disabled(t *testing.T) {
+	s := New("", "", func(string) error {
+		t.Error("No command should run when sync is disabled")
+		return nil
+	})
+
+	if s.IsEnabled() {
+		t.Error("IsEnabled() should be false without commands")
+	}
+	if err := s.Pull(); err != nil {
+		t.Errorf("Pull() without command should not fail: %v", err)
+	}
+	if text := s.StatusText(); text != "" {
+		t.Errorf("StatusText() when disabled = %q, want empty", text)
+	}
+	s.Start(time.Millisecond, nil) // Must not start a goroutine
+	s.Stop()
+}
+
+func TestSyncer_Start(t *testing.T) {
+	var mu sync.Mutex
+	var commands []string
+	run := func(command string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, command)
+		return nil
+	}
+
+	s := New("pull", "push", run)
+	notified := make(chan struct{}, 1)
+	s.Start(5*time.Millisecond, func() {
+		select {
+		case notified <- struct{}{}:
+		default:
+		}
+	})
+
+	select {
+	case <-notified:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Background sync did not run")
+	}
+	s.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(commands) < 2 || commands[0] != "push" || commands[1] != "pull" {
+		t.Errorf("Background round = %v, want push before pull", commands)
+	}
+}
+
+func TestSyncer_RoundWithLocalWrites(t *testing.T) {
+	var s *Syncer
+	var commands []string
+	writeDuring := ""
+	s = New("pull", "push", func(command string) error {
+		commands = append(commands, command)
+		if command == writeDuring {
+			s.NoteLocalWrite()
+		}
+		return nil
+	})
+
+	// Writes outside a round change nothing
+	s.NoteLocalWrite()
+	s.round()
+	if len(commands) != 2 || !s.TakePulled() || s.TakeOverwritten() {
+		t.Errorf("Round without writes ran %v, want a push and a pull to reload", commands)
+	}
+
+	// A write during the push was not pushed, so the pull is skipped
+	commands, writeDuring = nil, "push"
+	s.round()
+	if len(commands) != 1 || s.TakePulled() || s.TakeOverwritten() {
+		t.Errorf("Round with a write during the push ran %v, want only the push", commands)
+	}
+
+	// A write during the pull may have been replaced by it
+	commands, writeDuring = nil, "pull"
+	s.round()
+	if len(commands) != 2 || !s.TakePulled() || !s.TakeOverwritten() {
+		t.Errorf("Round with a write during the pull ran %v, want it reported as overwritten", commands)
+	}
+	if s.TakeOverwritten() {
+		t.Error("TakeOverwritten() should only report a round once")
+	}
+}
@@ -0,0 +1,57 @@
+package terminal
+
+import (
+	"strings"
+
+	"go-ascii-calendar/config"
+
+	"github.com/nsf/termbox-go"
+)
+
+// Colors256Supported guesses from the environment whether the terminal draws the
+// 256-color palette; terminals announcing 24-bit colors draw it as well
+func Colors256Supported(getenv func(string) string) bool {
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return true
+	}
+	term := getenv("TERM")
+	return strings.Contains(term, "256color") || strings.HasSuffix(term, "-direct")
+}
+
+// SetColors256 chooses whether theme colors beyond the 16 basic ones, such as
+// "color208" or "#ff8700", are drawn as they are; otherwise they are drawn with the
+// closest basic color. The next flush redraws the whole screen.
+func (t *Terminal) SetColors256(enabled bool) {
+	t.colors256 = enabled
+	if _, ok := t.driver().(termboxScreen); ok {
+		if enabled {
+			termbox.SetOutputMode(termbox.Output256)
+		} else {
+			termbox.SetOutputMode(termbox.OutputNormal)
+		}
+	}
+	t.shown = nil
+}
+
+// outputColor returns the attribute to draw for attr on this terminal: colors of the
+// 256-color palette become the closest of the eight basic colors when the terminal
+// only draws those, keeping the other attributes
+func (t *Terminal) outputColor(attr termbox.Attribute) termbox.Attribute {
+	color := attr & 0x1ff
+	if t.colors256 || color <= termbox.ColorLightGray {
+		return attr
+	}
+	r, g, b := config.ColorRGB(int(color) - 1)
+	basic := termbox.ColorBlack
+	if r >= 128 {
+		basic += 1
+	}
+	if g >= 128 {
+		basic += 2
+	}
+	if b >= 128 {
+		basic += 4
+	}
+	return attr&^0x1ff | basic
+}
@@ -0,0 +1,65 @@
+package terminal
+
+import (
+	"bytes"
+	"testing"
+
+	"go-ascii-calendar/config"
+
+	"github.com/nsf/termbox-go"
+)
+
+func TestColors256Supported(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"xterm-256color", map[string]string{"TERM": "xterm-256color"}, true},
+		{"tmux", map[string]string{"TERM": "tmux-256color"}, true},
+		{"24-bit colors", map[string]string{"TERM": "xterm", "COLORTERM": "truecolor"}, true},
+		{"direct colors", map[string]string{"TERM": "xterm-direct"}, true},
+		{"plain xterm", map[string]string{"TERM": "xterm"}, false},
+		{"Linux console", map[string]string{"TERM": "linux"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := Colors256Supported(getenv); got != tt.want {
+				t.Errorf("Colors256Supported() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminal_FlushReducesColors(t *testing.T) {
+	orange, err := config.ParseColor("#ff8700|bold")
+	if err != nil {
+		t.Fatalf("ParseColor() failed: %v", err)
+	}
+	screen := NewHeadlessScreen(10, 2)
+	term := &Terminal{out: &bytes.Buffer{}, screen: screen}
+	term.updateSize()
+
+	draw := func() {
+		term.Clear()
+		term.Print(0, 0, "ab", orange, termbox.ColorBlue)
+		if err := term.Flush(); err != nil {
+			t.Fatalf("Flush() failed: %v", err)
+		}
+	}
+
+	// Without 256 colors the closest basic color is drawn, keeping the attributes
+	draw()
+	if cell := screen.Cell(0, 0); cell.Fg != termbox.ColorYellow|termbox.AttrBold || cell.Bg != termbox.ColorBlue {
+		t.Errorf("Cell colors = %v on %v, want bold yellow on blue", cell.Fg, cell.Bg)
+	}
+
+	// With them the color is drawn as it is
+	term.SetColors256(true)
+	draw()
+	if cell := screen.Cell(1, 0); cell.Fg != orange || cell.Bg != termbox.ColorBlue {
+		t.Errorf("Cell colors = %v on %v, want %v on blue", cell.Fg, cell.Bg, orange)
+	}
+}
@@ -0,0 +1,138 @@
+package terminal
+
+import (
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+
+	"github.com/nsf/termbox-go"
+)
+
+// datePickerLegend lists the keys of the date picker on the legend line
+const datePickerLegend = "h/j/k/l: move  B/N: month  Enter: pick date  Esc: back to typing"
+
+// datePickerLayout is the plain single month grid of the date picker
+var datePickerLayout = buildStackedLayout(config.Decorations{Borders: true}, 1, true, 1)
+
+// datePickerPosition places the picker's box below the input line at row y, or above
+// it when the box would reach the legend, keeping it inside the terminal
+func datePickerPosition(x, y, width, height int) (int, int) {
+	boxWidth := datePickerLayout.monthWidth
+	boxHeight := datePickerLayout.gridRows + 2
+
+	if x+boxWidth > width {
+		x = width - boxWidth
+	}
+	if x < 0 {
+		x = 0
+	}
+
+	top := y + 1
+	if top+boxHeight > height-2 {
+		top = y - boxHeight
+	}
+	if top < 0 {
+		top = 0
+	}
+	return x, top
+}
+
+// RenderDatePicker draws a bordered month grid with date selected next to the inline
+// input at (x, y), reusing the calendar's month rendering
+func (r *Renderer) RenderDatePicker(x, y int, date time.Time) error {
+	width, height := r.terminal.GetSize()
+	x, top := datePickerPosition(x, y, width, height)
+
+	// Blank the box so the view below does not show through
+	fg, bg := r.style(StyleText)
+	for j := top; j < top+datePickerLayout.gridRows+2; j++ {
+		for i := x; i < x+datePickerLayout.monthWidth; i++ {
+			r.terminal.SetCell(i, j, ' ', fg, bg)
+		}
+	}
+
+	r.renderMonthBorder(datePickerLayout, x, top)
+	selection := &models.Selection{SelectedDate: date}
+	if err := r.renderMonth(datePickerLayout, calendar.GetFirstDayOfMonth(date), x, top+1, selection); err != nil {
+		return err
+	}
+
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, height-2, ' ', fg, bg)
+	}
+	r.terminal.PrintCentered(height-2, datePickerLegend, fg, bg)
+
+	return r.terminal.Flush()
+}
+
+// PickDate shows the date picker for the inline input at (x, y), starting at date.
+// It returns the picked date, or false when the picker is closed with Esc.
+func (ih *InputHandler) PickDate(x, y int, date time.Time, renderer *Renderer) (time.Time, bool) {
+	date = calendar.NormalizeDate(date)
+	for {
+		renderer.RenderDatePicker(x, y, date)
+
+		event := ih.terminal.PollEvent()
+		if event.Type != termbox.EventKey {
+			continue
+		}
+
+		switch event.Key {
+		case termbox.KeyEsc:
+			return time.Time{}, false
+		case termbox.KeyEnter:
+			return date, true
+		}
+
+		if moved, ok := moveDatePickerDate(event, date); ok {
+			date = moved
+		} else {
+			renderer.Reject()
+		}
+	}
+}
+
+// moveDatePickerDate moves the picker's date for a navigation key: H/J/K/L and the
+// arrows by a day or a week, B/N and Page Up/Down by a month
+func moveDatePickerDate(event termbox.Event, date time.Time) (time.Time, bool) {
+	switch event.Key {
+	case termbox.KeyArrowLeft:
+		return date.AddDate(0, 0, -1), true
+	case termbox.KeyArrowRight:
+		return date.AddDate(0, 0, 1), true
+	case termbox.KeyArrowUp:
+		return date.AddDate(0, 0, -7), true
+	case termbox.KeyArrowDown:
+		return date.AddDate(0, 0, 7), true
+	case termbox.KeyPgup:
+		return addPickerMonths(date, -1), true
+	case termbox.KeyPgdn:
+		return addPickerMonths(date, 1), true
+	}
+
+	switch event.Ch {
+	case 'h', 'H':
+		return date.AddDate(0, 0, -1), true
+	case 'l', 'L':
+		return date.AddDate(0, 0, 1), true
+	case 'k', 'K':
+		return date.AddDate(0, 0, -7), true
+	case 'j', 'J':
+		return date.AddDate(0, 0, 7), true
+	case 'b', 'B':
+		return addPickerMonths(date, -1), true
+	case 'n', 'N':
+		return addPickerMonths(date, 1), true
+	}
+	return date, false
+}
+
+// addPickerMonths moves date by the given months, keeping the day of the month where
+// the target month has it and using its last day otherwise
+func addPickerMonths(date time.Time, months int) time.Time {
+	first := calendar.GetFirstDayOfMonth(date).AddDate(0, months, 0)
+	day := min(date.Day(), calendar.GetDaysInMonth(first))
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, date.Location())
+}
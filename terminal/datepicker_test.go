@@ -0,0 +1,63 @@
+package terminal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+func TestMoveDatePickerDate(t *testing.T) {
+	date := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	key := func(k termbox.Key) termbox.Event { return termbox.Event{Type: termbox.EventKey, Key: k} }
+	char := func(ch rune) termbox.Event { return termbox.Event{Type: termbox.EventKey, Ch: ch} }
+
+	tests := []struct {
+		name  string
+		event termbox.Event
+		want  time.Time
+		ok    bool
+	}{
+		{"h", char('h'), time.Date(2025, 1, 30, 0, 0, 0, 0, time.UTC), true},
+		{"L", char('L'), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), true},
+		{"k", char('k'), time.Date(2025, 1, 24, 0, 0, 0, 0, time.UTC), true},
+		{"down arrow", key(termbox.KeyArrowDown), time.Date(2025, 2, 7, 0, 0, 0, 0, time.UTC), true},
+		{"n keeps the last day", char('n'), time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), true},
+		{"page up", key(termbox.KeyPgup), time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), true},
+		{"unknown key", char('x'), date, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := moveDatePickerDate(tt.event, date)
+			if !got.Equal(tt.want) || ok != tt.ok {
+				t.Errorf("moveDatePickerDate() = %v, %v; want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestDatePickerPosition(t *testing.T) {
+	boxHeight := datePickerLayout.gridRows + 2
+
+	tests := []struct {
+		name          string
+		x, y          int
+		width, height int
+		wantX, wantY  int
+	}{
+		{"below the input", 10, 5, 80, 40, 10, 6},
+		{"above the input near the legend", 10, 30, 80, 40, 10, 30 - boxHeight},
+		{"kept inside the right edge", 70, 5, 80, 40, 80 - datePickerLayout.monthWidth, 6},
+		{"kept inside the top edge", 0, 5, 80, 12, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := datePickerPosition(tt.x, tt.y, tt.width, tt.height)
+			if x != tt.wantX || y != tt.wantY {
+				t.Errorf("datePickerPosition() = (%d, %d), want (%d, %d)", x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
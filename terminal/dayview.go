@@ -0,0 +1,149 @@
+package terminal
+
+import (
+	"fmt"
+	"time"
+
+	"go-ascii-calendar/models"
+
+	"github.com/nsf/termbox-go"
+)
+
+// DayViewHours is the number of hour slots of the day view
+const DayViewHours = 24
+
+const (
+	dayViewLabelWidth = 9 // Hour label and separator, e.g. " 09:00 | "
+	dayViewStartY     = 6 // Row of the first hour slot
+	maxDayViewLanes   = 4 // Side-by-side columns for overlapping events
+)
+
+// EventHours returns the first and last hour slot an event covers: one slot for an
+// event without a duration, up to the end of the day otherwise
+func EventHours(event models.Event) (int, int) {
+	first := event.Time.Hour()
+	if event.Duration <= 0 {
+		return first, first
+	}
+	endMinute := first*60 + event.Time.Minute() + int(event.Duration/time.Minute)
+	return first, min(max(first, (endMinute-1)/60), DayViewHours-1)
+}
+
+// DayLanes places events sorted by time into side-by-side lanes so that events
+// covering the same hour never share one. It returns the lane of each event and the
+// number of lanes, at most maxDayViewLanes; further overlapping events share the last.
+func DayLanes(events []models.Event) ([]int, int) {
+	lanes := make([]int, len(events))
+	var laneEnds []int // Last hour taken in each lane
+	for i, event := range events {
+		first, last := EventHours(event)
+		lane := 0
+		for lane < len(laneEnds) && laneEnds[lane] >= first {
+			lane++
+		}
+		if lane == maxDayViewLanes {
+			lane = maxDayViewLanes - 1
+		}
+		if lane == len(laneEnds) {
+			laneEnds = append(laneEnds, last)
+		} else {
+			laneEnds[lane] = max(laneEnds[lane], last)
+		}
+		lanes[i] = lane
+	}
+	return lanes, len(laneEnds)
+}
+
+// RenderDayView renders a date as an hour-by-hour timeline with its events drawn as
+// blocks as tall as their duration. While a new event is marked, anchorHour is the
+// hour where marking started and the hours through selectedHour form a block of
+// their own; otherwise anchorHour is negative.
+func (r *Renderer) RenderDayView(date time.Time, events []models.Event, selectedHour, anchorHour int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	_, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+	hourFg, _ := r.style(StyleEventTime)
+	selectedFg, selectedBg := r.style(StyleSelected)
+
+	r.terminal.PrintCentered(2, date.Format("Monday, January 2 2006"), titleFg, bg)
+	r.renderFilterHeader()
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	rows := max(1, height-4-dayViewStartY)
+	offset := scrollOffset(DayViewHours, rows, selectedHour)
+
+	markFirst, markLast := -1, -1
+	if anchorHour >= 0 {
+		markFirst, markLast = min(anchorHour, selectedHour), max(anchorHour, selectedHour)
+	}
+
+	for hour := offset; hour < DayViewHours && hour-offset < rows; hour++ {
+		labelFg, labelBg := hourFg, bg
+		if hour == selectedHour || (hour >= markFirst && hour <= markLast) {
+			labelFg, labelBg = selectedFg, selectedBg
+		}
+		y := dayViewStartY + hour - offset
+		r.terminal.Print(0, y, fmt.Sprintf(" %02d:00 ", hour), labelFg, labelBg)
+		r.terminal.Print(dayViewLabelWidth-2, y, "|", instrFg, bg)
+	}
+
+	// The block being marked gets a lane of its own to the right of the events
+	lanes, laneCount := DayLanes(events)
+	if anchorHour >= 0 {
+		laneCount++
+	}
+	laneWidth := (width - dayViewLabelWidth) / max(1, laneCount)
+
+	for i, event := range events {
+		first, last := EventHours(event)
+		eventFg, _ := r.style(StyleEventText)
+		eventFg = r.categoryColor(event, eventFg)
+		r.renderDayViewBlock(lanes[i], laneWidth, first, last, offset, rows,
+			eventTimeLabel(event)+" "+r.eventDescription(event), eventFg|termbox.AttrReverse, bg)
+	}
+	if anchorHour >= 0 {
+		text := fmt.Sprintf("New: %02d:00-%02d:00", markFirst, (markLast+1)%DayViewHours)
+		r.renderDayViewBlock(laneCount-1, laneWidth, markFirst, markLast, offset, rows, text, selectedFg, selectedBg)
+	}
+
+	if len(events) == 0 && anchorHour < 0 {
+		noEventsFg, _ := r.style(StyleNoEvents)
+		r.terminal.Print(dayViewLabelWidth, dayViewStartY+max(0, selectedHour-offset), "No events scheduled", noEventsFg, bg)
+	}
+
+	legend := "J/K: hour  H/L: day  Enter: mark new event  A: add  Esc: back to calendar"
+	if anchorHour >= 0 {
+		legend = "J/K: set the end  Enter: create event  Esc: cancel"
+	}
+	r.terminal.PrintCentered(height-3, legend, instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// renderDayViewBlock fills the rows of hours first through last in a lane with a
+// block, writing text on its first visible row
+func (r *Renderer) renderDayViewBlock(lane, laneWidth, first, last, offset, rows int, text string, fg, bg termbox.Attribute) {
+	x := dayViewLabelWidth + lane*laneWidth
+	blockWidth := laneWidth - 1
+	if blockWidth < 1 {
+		return
+	}
+
+	textRow := max(first, offset)
+	for hour := textRow; hour <= last && hour-offset < rows; hour++ {
+		y := dayViewStartY + hour - offset
+		r.terminal.FillRect(x, y, blockWidth, 1, ' ', fg, bg)
+		if hour == textRow {
+			runes := []rune(" " + text)
+			if len(runes) > blockWidth {
+				runes = runes[:blockWidth]
+			}
+			r.terminal.Print(x, y, string(runes), fg, bg)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package terminal
+
+import (
+	"testing"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+func dayEvent(hour, minute int, duration time.Duration) models.Event {
+	return models.Event{Time: time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC), Description: "Event", Duration: duration}
+}
+
+func TestEventHours(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       models.Event
+		first, last int
+	}{
+		{"no duration", dayEvent(9, 30, 0), 9, 9},
+		{"within the hour", dayEvent(9, 0, 30*time.Minute), 9, 9},
+		{"ending on the hour", dayEvent(9, 0, 2*time.Hour), 9, 10},
+		{"crossing the hour", dayEvent(9, 30, time.Hour), 9, 10},
+		{"past midnight", dayEvent(22, 0, 4*time.Hour), 22, 23},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last := EventHours(tt.event)
+			if first != tt.first || last != tt.last {
+				t.Errorf("EventHours() = %d, %d; want %d, %d", first, last, tt.first, tt.last)
+			}
+		})
+	}
+}
+
+func TestDayLanes(t *testing.T) {
+	events := []models.Event{
+		dayEvent(9, 0, 2*time.Hour), // 09-10
+		dayEvent(10, 0, time.Hour),  // Overlaps the first
+		dayEvent(11, 0, 0),          // Fits below the first again
+		dayEvent(11, 30, 0),         // Overlaps the third
+	}
+
+	lanes, count := DayLanes(events)
+	want := []int{0, 1, 0, 1}
+	for i := range want {
+		if lanes[i] != want[i] {
+			t.Errorf("Lane of event %d = %d, want %d", i, lanes[i], want[i])
+		}
+	}
+	if count != 2 {
+		t.Errorf("Lane count = %d, want 2", count)
+	}
+
+	// Overlapping events beyond the lane limit share the last lane
+	crowded := make([]models.Event, maxDayViewLanes+2)
+	for i := range crowded {
+		crowded[i] = dayEvent(14, 0, time.Hour)
+	}
+	lanes, count = DayLanes(crowded)
+	if count != maxDayViewLanes || lanes[len(lanes)-1] != maxDayViewLanes-1 {
+		t.Errorf("DayLanes() of crowded events = %v, %d lanes; want at most %d", lanes, count, maxDayViewLanes)
+	}
+}
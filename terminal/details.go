@@ -0,0 +1,162 @@
+package terminal
+
+import (
+	"strings"
+
+	"go-ascii-calendar/models"
+)
+
+// detailLabelWidth is the width of the label column of the event details
+const detailLabelWidth = 13
+
+// detailsStartY is the first row of the event details below the title
+const detailsStartY = 6
+
+// detailLine is a row of the event details: a field value with its label, which is
+// empty on the rows a value wraps onto, or a row of the notes across the whole width
+type detailLine struct {
+	label string
+	text  string
+	notes bool
+	muted bool
+}
+
+// wrapText breaks text into lines of at most width characters, wrapping at the last
+// space that fits and breaking words longer than a line. Line breaks and blank lines
+// of the text are kept, as are the spaces within a line.
+func wrapText(text string, width int) []string {
+	width = max(1, width)
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		runes := []rune(paragraph)
+		for len(runes) > width {
+			cut := width
+			for i := width; i > 0; i-- {
+				if runes[i] == ' ' {
+					cut = i
+					break
+				}
+			}
+			lines = append(lines, string(runes[:cut]))
+			if cut < len(runes) && runes[cut] == ' ' {
+				cut++
+			}
+			runes = runes[cut:]
+		}
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
+
+// detailLines returns the rows of the details of event for a screen width wide: its
+// fields, then its notes wrapped to the width
+func (r *Renderer) detailLines(event models.Event, width int) []detailLine {
+	valueWidth := max(1, width-4-detailLabelWidth)
+	var lines []detailLine
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		for i, text := range wrapText(value, valueWidth) {
+			if i > 0 {
+				label = ""
+			}
+			lines = append(lines, detailLine{label: label, text: text})
+		}
+	}
+
+	date := event.Date.Format("Mon ") + r.formatDate(event.Date)
+	if event.IsMultiDay() {
+		date += " - " + event.LastDate().Format("Mon ") + r.formatDate(event.LastDate())
+	}
+	field("Description", event.Description)
+	field("Date", date+" ("+r.countdownText(event.Date)+")")
+	field("Time", eventTimeLabel(event))
+	field("Category", event.Category)
+	field("Calendar", event.Calendar)
+	field("Priority", event.Priority)
+	if reminder := event.GetReminderLabel(); reminder != "" {
+		field("Reminder", reminder+" before")
+	}
+	if event.Flagged {
+		field("Follow-up", "flagged")
+	}
+	field("Command", event.Command)
+	field("Source", event.Source)
+
+	lines = append(lines, detailLine{})
+	if event.Notes == "" {
+		return append(lines, detailLine{label: "Notes", text: "none", muted: true})
+	}
+	lines = append(lines, detailLine{label: "Notes"})
+	for _, text := range wrapText(event.Notes, max(1, width-4)) {
+		lines = append(lines, detailLine{text: text, notes: true})
+	}
+	return lines
+}
+
+// detailRows returns how many rows of the event details fit on a screen height high
+func detailRows(height int) int {
+	return max(1, height-4-detailsStartY)
+}
+
+// ScrollEventDetails returns the offset of the event details scrolled by delta rows
+// from offset, kept within the details, and whether it moved
+func (r *Renderer) ScrollEventDetails(event models.Event, offset, delta int) (int, bool) {
+	width, height := r.terminal.GetSize()
+	limit := max(0, len(r.detailLines(event, width))-detailRows(height))
+	scrolled := min(max(offset+delta, 0), limit)
+	return scrolled, scrolled != offset
+}
+
+// RenderEventDetails renders all fields of an event and its notes, wrapped to the
+// screen and scrolled past offset rows
+func (r *Renderer) RenderEventDetails(event models.Event, offset int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+	labelFg, _ := r.style(StyleEventTime)
+	mutedFg, _ := r.style(StyleMuted)
+
+	r.terminal.PrintCentered(2, "Event details", titleFg, bg)
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	lines := r.detailLines(event, width)
+	rows := detailRows(height)
+	offset = min(max(offset, 0), max(0, len(lines)-rows))
+	for i := offset; i < len(lines) && i-offset < rows; i++ {
+		line := lines[i]
+		y := detailsStartY + i - offset
+		r.terminal.Print(2, y, line.label, labelFg, bg)
+
+		x, lineFg := 2+detailLabelWidth, fg
+		if line.notes {
+			x = 2
+		}
+		if line.muted {
+			lineFg = mutedFg
+		}
+		r.terminal.PrintLinked(x, y, line.text, lineFg, bg)
+	}
+
+	legend := "E: edit notes  Esc: back"
+	if len(lines) > rows {
+		legend = "J/K: scroll  " + legend
+	}
+	r.terminal.PrintCentered(height-3, legend, instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// GetNotesInput edits the notes of an event in a text box, starting from notes.
+// Ctrl+D saves them; Esc cancels.
+func (ih *InputHandler) GetNotesInput(event models.Event, notes string, renderer *Renderer) (string, bool) {
+	return ih.getTextBoxInput(notes, func(input string) {
+		renderer.renderTextBox("Notes: "+event.Description, input, "Enter: new line  Ctrl+D: save notes  Esc: cancel")
+	})
+}
@@ -0,0 +1,68 @@
+package terminal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{"fits", "Call Sam", 10, []string{"Call Sam"}},
+		{"at the last space", "Book the flight to Lisbon", 12, []string{"Book the", "flight to", "Lisbon"}},
+		{"space at the width", "Agenda item", 6, []string{"Agenda", "item"}},
+		{"long word", "https://example.com/a", 8, []string{"https://", "example.", "com/a"}},
+		{"line breaks and blank lines", "Agenda:\n\n- review", 20, []string{"Agenda:", "", "- review"}},
+		{"spaces within a line", "a  b ", 10, []string{"a  b "}},
+		{"empty", "", 10, []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapText(tt.text, tt.width); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wrapText(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderer_EventDetails(t *testing.T) {
+	f := newSnapshotFixture(t, 40, 16, nil)
+	event := models.Event{
+		Date:        snapshotDate,
+		Time:        time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+		Description: "Planning",
+		Notes:       strings.Repeat("Agenda item to discuss\n", 10) + "Last item",
+	}
+
+	if err := f.renderer.RenderEventDetails(event, 0); err != nil {
+		t.Fatalf("RenderEventDetails() failed: %v", err)
+	}
+	screen := f.screen.Text()
+	if !strings.Contains(screen, "Planning") || !strings.Contains(screen, "J/K: scroll") || strings.Contains(screen, "Last item") {
+		t.Errorf("Details should show the event and scroll to reach the end of the notes:\n%s", screen)
+	}
+
+	// Scrolling stops at the last line of the notes
+	offset, moved := f.renderer.ScrollEventDetails(event, 0, 100)
+	if !moved {
+		t.Fatal("ScrollEventDetails() should scroll long notes")
+	}
+	if _, moved := f.renderer.ScrollEventDetails(event, offset, 1); moved {
+		t.Error("ScrollEventDetails() should not scroll past the end")
+	}
+	if err := f.renderer.RenderEventDetails(event, offset); err != nil {
+		t.Fatalf("RenderEventDetails() failed: %v", err)
+	}
+	if screen := f.screen.Text(); !strings.Contains(screen, "Last item") {
+		t.Errorf("Details scrolled to the end should show the last line of the notes:\n%s", screen)
+	}
+}
@@ -0,0 +1,49 @@
+package terminal
+
+import (
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/config"
+)
+
+// SetFreeEvenings switches the highlighting of days with a free evening on or off
+func (r *Renderer) SetFreeEvenings(on bool) {
+	if r.freeEvenings != on {
+		r.freeEvenings = on
+		r.invalidateMonthCache()
+	}
+}
+
+// FreeEvenings reports whether days with a free evening are highlighted
+func (r *Renderer) FreeEvenings() bool {
+	return r.freeEvenings
+}
+
+// freeEveningFrom returns the configured start of the evening, 18:00 when it is not
+// a valid time
+func (r *Renderer) freeEveningFrom() string {
+	if r.config != nil && calendar.ValidateTimeString(r.config.FreeEveningFrom) {
+		return r.config.FreeEveningFrom
+	}
+	return config.DefaultFreeEveningFrom
+}
+
+// hasFreeEvening reports whether free evenings are highlighted and a day from today on
+// has no visible event at or after the start of the evening, counting events that
+// start earlier but last into it
+func (r *Renderer) hasFreeEvening(date time.Time) bool {
+	if !r.freeEvenings || date.Before(r.today()) {
+		return false
+	}
+
+	start, _ := calendar.ParseTime(r.freeEveningFrom())
+	evening := start.Hour()*60 + start.Minute()
+	for _, event := range r.eventManager.GetEventsForDate(date) {
+		begin := event.Time.Hour()*60 + event.Time.Minute()
+		if begin >= evening || begin+int(event.Duration/time.Minute) > evening {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+package terminal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-ascii-calendar/config"
+
+	"github.com/nsf/termbox-go"
+)
+
+// urlPattern finds web and file URLs in event text
+var urlPattern = regexp.MustCompile(`(?:https?|file)://[^\s<>"]+`)
+
+// hyperlink is a URL shown at a screen position, written as an OSC 8 link after the
+// cells termbox drew
+type hyperlink struct {
+	x, y   int
+	text   string
+	url    string
+	fg, bg termbox.Attribute
+}
+
+// hyperlinkTerminals are TERM_PROGRAM values of terminals known to support OSC 8
+var hyperlinkTerminals = []string{"iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper", "Tabby"}
+
+// HyperlinksSupported guesses from the environment whether the terminal supports
+// OSC 8 hyperlinks; terminals without support may print the escape sequences as text
+func HyperlinksSupported(getenv func(string) string) bool {
+	for _, program := range hyperlinkTerminals {
+		if getenv("TERM_PROGRAM") == program {
+			return true
+		}
+	}
+	if version, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && version >= 5000 {
+		return true // GNOME Terminal, Tilix and other VTE terminals since 0.50
+	}
+	if getenv("KITTY_WINDOW_ID") != "" || getenv("WT_SESSION") != "" {
+		return true
+	}
+	term := getenv("TERM")
+	return strings.Contains(term, "kitty") || strings.HasPrefix(term, "foot") || strings.Contains(term, "alacritty")
+}
+
+// SetHyperlinks chooses whether URLs printed with PrintLinked become clickable links:
+// always ("on"), never ("off") or when the terminal supports them ("auto")
+func (t *Terminal) SetHyperlinks(mode string, getenv func(string) string) {
+	switch mode {
+	case config.HyperlinksOn:
+		t.hyperlinks = true
+	case config.HyperlinksOff:
+		t.hyperlinks = false
+	default:
+		t.hyperlinks = HyperlinksSupported(getenv)
+	}
+}
+
+// PrintLinked prints text like Print and, when hyperlinks are enabled, makes the
+// URLs in it clickable. A URL cut short by a trailing "..." is left as plain text.
+func (t *Terminal) PrintLinked(x, y int, text string, fg, bg termbox.Attribute) {
+	t.Print(x, y, text, fg, bg)
+	if !t.hyperlinks {
+		return
+	}
+	for _, span := range findURLs(text) {
+		if x+span[1] > t.width {
+			continue
+		}
+		url := text[span[0]:span[1]]
+		t.links = append(t.links, hyperlink{x: x + span[0], y: y, text: url, url: url, fg: fg, bg: bg})
+	}
+}
+
+// findURLs returns the byte ranges of the complete URLs in text, without trailing
+// punctuation; a URL truncated with "..." at the end of text is skipped
+func findURLs(text string) [][2]int {
+	var spans [][2]int
+	for _, match := range urlPattern.FindAllStringIndex(text, -1) {
+		start, end := match[0], match[1]
+		if end == len(text) && strings.HasSuffix(text, "...") {
+			continue
+		}
+		end = start + len(strings.TrimRight(text[start:end], ".,;:!?)]'"))
+		spans = append(spans, [2]int{start, end})
+	}
+	return spans
+}
+
+// intactLinks returns the links whose text is still in the frame with their colors,
+// dropping those drawn over since they were printed
+func (t *Terminal) intactLinks(links []hyperlink) []hyperlink {
+	var intact []hyperlink
+	for _, link := range links {
+		ok := link.y >= 0 && link.y < t.height
+		for i, ch := range link.text {
+			if !ok {
+				break
+			}
+			x := link.x + i
+			ok = x >= 0 && x < t.width && t.frame[link.y*t.width+x] == termbox.Cell{Ch: ch, Fg: link.fg, Bg: link.bg}
+		}
+		if ok {
+			intact = append(intact, link)
+		}
+	}
+	return intact
+}
+
+// dirty reports whether any cell of the link is among the dirty cells of a screen width wide
+func (l hyperlink) dirty(dirty []bool, width int) bool {
+	for i := range l.text {
+		if dirty[l.y*width+l.x+i] {
+			return true
+		}
+	}
+	return false
+}
+
+// containsLink reports whether links holds link
+func containsLink(links []hyperlink, link hyperlink) bool {
+	for _, existing := range links {
+		if existing == link {
+			return true
+		}
+	}
+	return false
+}
+
+// writeLinks rewrites the recorded links over the cells termbox drew, wrapped in
+// OSC 8 sequences. The cursor and attributes are saved and restored around them so
+// termbox's next flush is not disturbed.
+func (t *Terminal) writeLinks() {
+	for _, link := range t.links {
+		writeHyperlink(t.out, link.x, link.y, link.url, sgr(t.outputColor(link.fg), t.outputColor(link.bg))+link.text)
+	}
+	t.links = t.links[:0]
+}
+
+// sgr returns the escape sequence selecting the colors and attributes of a cell
+func sgr(fg, bg termbox.Attribute) string {
+	codes := []string{"0"}
+	if fg&termbox.AttrBold != 0 {
+		codes = append(codes, "1")
+	}
+	if fg&termbox.AttrDim != 0 {
+		codes = append(codes, "2")
+	}
+	codes = append(codes, "4") // Links are always underlined
+	if fg&termbox.AttrReverse != 0 || bg&termbox.AttrReverse != 0 {
+		codes = append(codes, "7")
+	}
+	if code, ok := colorCode(fg, 30); ok {
+		codes = append(codes, code)
+	}
+	if code, ok := colorCode(bg, 40); ok {
+		codes = append(codes, code)
+	}
+	return fmt.Sprintf("\x1b[%sm", strings.Join(codes, ";"))
+}
+
+// colorCode returns the SGR code of the color in attr, counting from base for the
+// eight normal colors (30 for foreground, 40 for background) and from base+60 for the
+// bright ones, and from base+8 for the rest of the 256-color palette; the default color
+// has no code
+func colorCode(attr termbox.Attribute, base int) (string, bool) {
+	color := attr & 0x1ff
+	switch {
+	case color >= termbox.ColorBlack && color <= termbox.ColorWhite:
+		return strconv.Itoa(base + int(color-termbox.ColorBlack)), true
+	case color >= termbox.ColorDarkGray && color <= termbox.ColorLightGray:
+		return strconv.Itoa(base + 60 + int(color-termbox.ColorDarkGray)), true
+	case color > termbox.ColorLightGray:
+		return fmt.Sprintf("%d;5;%d", base+8, color-1), true
+	}
+	return "", false
+}
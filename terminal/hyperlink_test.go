@@ -0,0 +1,120 @@
+package terminal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go-ascii-calendar/config"
+
+	"github.com/nsf/termbox-go"
+)
+
+func TestHyperlinksSupported(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"plain xterm", map[string]string{"TERM": "xterm-256color"}, false},
+		{"iTerm2", map[string]string{"TERM_PROGRAM": "iTerm.app"}, true},
+		{"recent VTE", map[string]string{"VTE_VERSION": "7600"}, true},
+		{"old VTE", map[string]string{"VTE_VERSION": "4800"}, false},
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, true},
+		{"Windows Terminal", map[string]string{"WT_SESSION": "1234"}, true},
+		{"Apple Terminal", map[string]string{"TERM_PROGRAM": "Apple_Terminal"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := HyperlinksSupported(getenv); got != tt.want {
+				t.Errorf("HyperlinksSupported() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminal_SetHyperlinks(t *testing.T) {
+	unsupported := func(string) string { return "" }
+	term := &Terminal{}
+
+	term.SetHyperlinks(config.HyperlinksAuto, unsupported)
+	if term.hyperlinks {
+		t.Error("auto should disable links on an unknown terminal")
+	}
+	term.SetHyperlinks(config.HyperlinksOn, unsupported)
+	if !term.hyperlinks {
+		t.Error("on should enable links regardless of the terminal")
+	}
+	term.SetHyperlinks(config.HyperlinksOff, func(string) string { return "WezTerm" })
+	if term.hyperlinks {
+		t.Error("off should disable links regardless of the terminal")
+	}
+}
+
+func TestFindURLs(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"10:00 - Standup", nil},
+		{"10:00 - Call https://meet.example.com/abc", []string{"https://meet.example.com/abc"}},
+		{"Review (see https://example.com/pr/1).", []string{"https://example.com/pr/1"}},
+		{"Notes file:///home/me/notes.txt and http://wiki/x", []string{"file:///home/me/notes.txt", "http://wiki/x"}},
+		{"10:00 - Call https://meet.exam...", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, span := range findURLs(tt.text) {
+			got = append(got, tt.text[span[0]:span[1]])
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("findURLs(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTerminal_PrintLinked(t *testing.T) {
+	var out bytes.Buffer
+	term := &Terminal{out: &out, width: 80, height: 24}
+
+	term.PrintLinked(2, 5, "Call https://meet.example.com/abc", termbox.ColorDefault, termbox.ColorDefault)
+	if len(term.links) != 0 {
+		t.Fatal("PrintLinked should not record links while hyperlinks are off")
+	}
+
+	term.hyperlinks = true
+	term.PrintLinked(2, 5, "Call https://meet.example.com/abc", termbox.ColorGreen, termbox.ColorDefault)
+	if len(term.links) != 1 || term.links[0].x != 7 || term.links[0].url != "https://meet.example.com/abc" {
+		t.Fatalf("PrintLinked recorded %+v, want the URL at column 7", term.links)
+	}
+
+	term.writeLinks()
+	want := "\x1b7\x1b[6;8H\x1b]8;;https://meet.example.com/abc\x1b\\\x1b[0;4;32mhttps://meet.example.com/abc\x1b]8;;\x1b\\\x1b8"
+	if out.String() != want {
+		t.Errorf("writeLinks() wrote %q, want %q", out.String(), want)
+	}
+	if len(term.links) != 0 {
+		t.Error("writeLinks() should clear the written links")
+	}
+}
+
+func TestSGR(t *testing.T) {
+	tests := []struct {
+		fg, bg termbox.Attribute
+		want   string
+	}{
+		{termbox.ColorDefault, termbox.ColorDefault, "\x1b[0;4m"},
+		{termbox.ColorRed | termbox.AttrBold, termbox.ColorBlue, "\x1b[0;1;4;31;44m"},
+		{termbox.ColorLightCyan, termbox.ColorDefault | termbox.AttrReverse, "\x1b[0;4;7;96m"},
+		{termbox.Attribute(208 + 1), termbox.Attribute(236 + 1), "\x1b[0;4;38;5;208;48;5;236m"},
+	}
+
+	for _, tt := range tests {
+		if got := sgr(tt.fg, tt.bg); got != tt.want {
+			t.Errorf("sgr(%v, %v) = %q, want %q", tt.fg, tt.bg, got, tt.want)
+		}
+	}
+}
@@ -1,21 +1,55 @@
 package terminal
 
 import (
+	"fmt"
 	"strings"
+	"time"
+
+	"go-ascii-calendar/calendar"
 
 	"github.com/nsf/termbox-go"
 )
 
+// DefaultChordTimeout is how long the first key of a chord waits for the second one
+const DefaultChordTimeout = 500 * time.Millisecond
+
+// Chord is a two-key sequence bound to an action, e.g. 'g' 'g'
+type Chord struct {
+	Keys   [2]rune
+	Action KeyAction
+}
+
+// DefaultChords are the two-key sequences available in the calendar view
+var DefaultChords = []Chord{
+	{Keys: [2]rune{'g', 'g'}, Action: ActionResetCurrent},
+	{Keys: [2]rune{'d', 'd'}, Action: ActionDeleteSelected},
+}
+
 // InputHandler handles keyboard input processing
 type InputHandler struct {
 	terminal *Terminal
+	chords   []Chord
+	timeout  time.Duration
+	wake     func() // Wakes the event loop when a pending chord times out
+
+	pending      rune // First key of a chord waiting for its second key (0 = none)
+	pendingAt    time.Time
+	pendingTimer *time.Timer // Wakes the event loop when the pending key times out
+
+	view KeyView // Screen reading the keys, see ViewKeys
 }
 
 // NewInputHandler creates a new input handler
 func NewInputHandler(terminal *Terminal) *InputHandler {
-	return &InputHandler{
+	ih := &InputHandler{
 		terminal: terminal,
+		chords:   DefaultChords,
+		timeout:  DefaultChordTimeout,
 	}
+	if terminal != nil {
+		ih.wake = terminal.Interrupt
+	}
+	return ih
 }
 
 // KeyAction represents different types of actions that can be triggered by keys
@@ -37,13 +71,124 @@ const (
 	ActionBack
 	ActionResetCurrent
 	ActionSearch
+	ActionShowStats
+	ActionShowActivityLog
+	ActionUndo
+	ActionBookmark
+	ActionShowBookmarks
+	ActionDeleteSelected
+	ActionCycleTheme
+	ActionMoveEventDayLater
+	ActionMoveEventDayEarlier
+	ActionMoveEventWeekLater
+	ActionMoveEventWeekEarlier
+	ActionClearFilters
+	ActionToggleFlag
+	ActionShowFollowUps
+	ActionMarkRange
+	ActionCopyEvents
+	ActionShowHelp
+	ActionShowDayView
+	ActionToggleFreeEvenings
+	ActionGoToDate
+	ActionPasteEvents
+	ActionTogglePasteLine
+	ActionToggleFocus
+	ActionShowInbox
+	ActionSyncNow
+	ActionSetReminder
+	ActionShowCalendars
+	ActionScrollEventsUp
+	ActionScrollEventsDown
+	ActionShowMessages
+	ActionMoveEvent
+	ActionShowBackups
+	ActionToggleCalendar
+	// ActionQuickFilter1 toggles the quick filter bound to F1; the following seven actions
+	// stand for F2 to F8
+	ActionQuickFilter1
+	ActionQuickFilter8 = ActionQuickFilter1 + 7
+)
+
+// QuickFilterKey returns the function key, e.g. "F3", of a quick filter action
+func QuickFilterKey(action KeyAction) (string, bool) {
+	if action < ActionQuickFilter1 || action > ActionQuickFilter8 {
+		return "", false
+	}
+	return fmt.Sprintf("F%d", action-ActionQuickFilter1+1), true
+}
+
+// normalizeKeyEvent maps control characters that some terminals (notably Windows
+// ConHost and serial consoles) deliver as plain characters onto termbox special keys.
+// Backspace arrives as DEL (0x7F) on most Unix terminals and as BS (0x08, the same
+// code as Ctrl+H) on Windows; both are reported as KeyBackspace2.
+func normalizeKeyEvent(event termbox.Event) termbox.Event {
+	if event.Type != termbox.EventKey {
+		return event
+	}
+
+	switch event.Key {
+	case termbox.KeyBackspace: // Also Ctrl+H
+		event.Key = termbox.KeyBackspace2
+		return event
+	}
+
+	if event.Key != 0 {
+		return event
+	}
+
+	switch event.Ch {
+	case '\r', '\n':
+		event.Key, event.Ch = termbox.KeyEnter, 0
+	case 0x1b:
+		event.Key, event.Ch = termbox.KeyEsc, 0
+	case 0x08, 0x7f:
+		event.Key, event.Ch = termbox.KeyBackspace2, 0
+	case '\t':
+		event.Key, event.Ch = termbox.KeyTab, 0
+	}
+	return event
+}
+
+// ShiftedKeys maps the uppercase letters that do something else than their lowercase
+// letter to their action; every other letter works in either case
+var ShiftedKeys = map[rune]KeyAction{
+	'L': ActionShowActivityLog, // l moves right
+	'G': ActionShowBookmarks,   // g goes to a date
+	'D': ActionShowDayView,     // d deletes
+	'C': ActionShowCalendars,   // c goes back to today
+	'M': ActionMoveEvent,       // m bookmarks the selected date
+}
+
+// KeyView names the screens on which some letters mean something else than in the calendar
+type KeyView int
+
+const (
+	ViewCalendar KeyView = iota
+	ViewList             // Events list, bookmarks and import inbox, which have no day view
+	ViewActivityLog
+	ViewCalendars
 )
 
+// ViewKeys maps the letters that have an action of their own on a screen to that action;
+// they take precedence over ShiftedKeys and the calendar keys
+var ViewKeys = map[KeyView]map[rune]KeyAction{
+	ViewList:        {'D': ActionDeleteEvent},                         // Shift+D opens the day view in the calendar
+	ViewActivityLog: {'b': ActionShowBackups, 'B': ActionShowBackups}, // b is the previous month
+	ViewCalendars:   {'x': ActionToggleCalendar, 'X': ActionToggleCalendar},
+}
+
+// SetView tells ProcessKeyEvent which screen reads the following keys
+func (ih *InputHandler) SetView(view KeyView) {
+	ih.view = view
+}
+
 // ProcessKeyEvent processes a keyboard event and returns the corresponding action
 func (ih *InputHandler) ProcessKeyEvent(event termbox.Event) KeyAction {
 	if event.Type != termbox.EventKey {
 		return ActionNone
 	}
+	event = normalizeKeyEvent(event)
 
 	// Handle special keys first
 	switch event.Key {
@@ -63,6 +208,18 @@ func (ih *InputHandler) ProcessKeyEvent(event termbox.Event) KeyAction {
 		return ActionMoveUp
 	case termbox.KeyArrowDown:
 		return ActionMoveDown
+	case termbox.KeyPgup:
+		return ActionMonthPrev
+	case termbox.KeyF9:
+		return ActionClearFilters
+	case termbox.KeyF1, termbox.KeyF2, termbox.KeyF3, termbox.KeyF4,
+		termbox.KeyF5, termbox.KeyF6, termbox.KeyF7, termbox.KeyF8:
+		// Function key codes count down from F1
+		return ActionQuickFilter1 + KeyAction(termbox.KeyF1-event.Key)
+	case termbox.KeyPgdn:
+		return ActionMonthNext
+	case termbox.KeyHome:
+		return ActionResetCurrent
 	}
 
 	// Handle character keys (convert to lowercase for consistent processing)
@@ -71,6 +228,48 @@ func (ih *InputHandler) ProcessKeyEvent(event termbox.Event) KeyAction {
 		return ActionNone
 	}
 
+	// Letters with an action of their own on the current screen
+	if action, ok := ViewKeys[ih.view][ch]; ok {
+		return action
+	}
+	// The few uppercase letters with an action of their own
+	if action, ok := ShiftedKeys[ch]; ok {
+		return action
+	}
+	// ! flags the selected event for follow-up
+	if ch == '!' {
+		return ActionToggleFlag
+	}
+	if ch == '@' {
+		return ActionSetReminder
+	}
+	if ch == '?' {
+		return ActionShowHelp
+	}
+	if ch == ':' {
+		return ActionGoToDate
+	}
+	// " lists the latest status line messages
+	if ch == '"' {
+		return ActionShowMessages
+	}
+
+	// Rescheduling keys; = and the unshifted , . work without Shift
+	switch ch {
+	case '[':
+		return ActionScrollEventsUp
+	case ']':
+		return ActionScrollEventsDown
+	case '+', '=':
+		return ActionMoveEventDayLater
+	case '-':
+		return ActionMoveEventDayEarlier
+	case '>', '.':
+		return ActionMoveEventWeekLater
+	case '<', ',':
+		return ActionMoveEventWeekEarlier
+	}
+
 	// Convert to lowercase for case-insensitive processing
 	lowerCh := strings.ToLower(string(ch))[0]
 
@@ -99,12 +298,130 @@ func (ih *InputHandler) ProcessKeyEvent(event termbox.Event) KeyAction {
 		return ActionResetCurrent
 	case 'f':
 		return ActionSearch
+	case 's':
+		return ActionShowStats
+	case 'u':
+		return ActionUndo
+	case 'm':
+		return ActionBookmark
+	case 'g':
+		return ActionGoToDate
+	case 't':
+		return ActionCycleTheme
+	case 'o':
+		return ActionShowFollowUps
+	case 'v':
+		return ActionMarkRange
+	case 'y':
+		return ActionCopyEvents
+	case 'w':
+		return ActionToggleFreeEvenings
+	case 'p':
+		return ActionPasteEvents
+	case 'x':
+		return ActionTogglePasteLine
+	case 'z':
+		return ActionToggleFocus
+	case 'i':
+		return ActionShowInbox
+	case 'r':
+		return ActionSyncNow
 	default:
 		// Unrecognized key - could show a brief message
 		return ActionNone
 	}
 }
 
+// ProcessChordKeyEvent processes a keyboard event like ProcessKeyEvent, but also
+// recognizes chords. The first key of a chord returns ActionNone and stays pending
+// until the second key arrives; a different key cancels the chord and is processed
+// on its own. A pending key whose chord times out acts as a single key press, see
+// ExpirePendingChord.
+func (ih *InputHandler) ProcessChordKeyEvent(event termbox.Event, now time.Time) KeyAction {
+	if event.Type != termbox.EventKey {
+		return ActionNone
+	}
+
+	if ih.pending != 0 {
+		first := ih.pending
+		ih.CancelPendingChord()
+		if now.Sub(ih.pendingAt) <= ih.timeout {
+			if action, ok := ih.chordAction(first, event.Ch); ok {
+				return action
+			}
+		}
+	}
+
+	if event.Key == 0 && ih.isChordPrefix(event.Ch) {
+		ih.pending = event.Ch
+		ih.pendingAt = now
+		if ih.wake != nil {
+			ih.pendingTimer = time.AfterFunc(ih.timeout, ih.wake)
+		}
+		return ActionNone
+	}
+
+	return ih.ProcessKeyEvent(event)
+}
+
+// ExpirePendingChord returns the single-key action of a pending chord key once its
+// timeout has passed, clearing it; otherwise it returns ActionNone
+func (ih *InputHandler) ExpirePendingChord(now time.Time) KeyAction {
+	if ih.pending == 0 || now.Sub(ih.pendingAt) < ih.timeout {
+		return ActionNone
+	}
+
+	first := ih.pending
+	ih.CancelPendingChord()
+	return ih.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: first})
+}
+
+// CancelPendingChord discards a pending chord key and stops its timeout, so a prompt
+// opened by the next action is not woken by it
+func (ih *InputHandler) CancelPendingChord() {
+	ih.pending = 0
+	if ih.pendingTimer != nil {
+		ih.pendingTimer.Stop()
+		ih.pendingTimer = nil
+	}
+}
+
+// PendingKeys returns the keys typed so far of an unfinished chord, for the status bar
+func (ih *InputHandler) PendingKeys() string {
+	if ih.pending == 0 {
+		return ""
+	}
+	return string(ih.pending)
+}
+
+// isChordPrefix reports whether ch starts any chord
+func (ih *InputHandler) isChordPrefix(ch rune) bool {
+	for _, chord := range ih.chords {
+		if chord.Keys[0] == ch {
+			return true
+		}
+	}
+	return false
+}
+
+// chordAction returns the action bound to the sequence first, second
+func (ih *InputHandler) chordAction(first, second rune) (KeyAction, bool) {
+	for _, chord := range ih.chords {
+		if chord.Keys[0] == first && chord.Keys[1] == second {
+			return chord.Action, true
+		}
+	}
+	return ActionNone, false
+}
+
+// GetDigitKey returns the digit pressed in a key event, if any (used for category hotkeys)
+func (ih *InputHandler) GetDigitKey(event termbox.Event) (rune, bool) {
+	if event.Type != termbox.EventKey || event.Ch < '0' || event.Ch > '9' {
+		return 0, false
+	}
+	return event.Ch, true
+}
+
 // GetKeyDescription returns a human-readable description of the key action
 func (ih *InputHandler) GetKeyDescription(action KeyAction) string {
 	switch action {
@@ -136,7 +453,76 @@ func (ih *InputHandler) GetKeyDescription(action KeyAction) string {
 		return "Reset to current month/day"
 	case ActionSearch:
 		return "Search events"
+	case ActionShowStats:
+		return "Show usage statistics"
+	case ActionShowActivityLog:
+		return "Show activity log"
+	case ActionUndo:
+		return "Undo change"
+	case ActionBookmark:
+		return "Bookmark selected date"
+	case ActionShowBookmarks:
+		return "Show bookmarks"
+	case ActionDeleteSelected:
+		return "Delete selected event"
+	case ActionCycleTheme:
+		return "Switch to the next color theme"
+	case ActionMoveEventDayLater:
+		return "Move event one day later"
+	case ActionMoveEventDayEarlier:
+		return "Move event one day earlier"
+	case ActionMoveEventWeekLater:
+		return "Move event one week later"
+	case ActionMoveEventWeekEarlier:
+		return "Move event one week earlier"
+	case ActionClearFilters:
+		return "Clear quick filters"
+	case ActionToggleFlag:
+		return "Flag event for follow-up"
+	case ActionShowFollowUps:
+		return "Show follow-ups"
+	case ActionMarkRange:
+		return "Start or cancel a date range"
+	case ActionCopyEvents:
+		return "Copy events of the selected date or range"
+	case ActionShowHelp:
+		return "Show help"
+	case ActionShowDayView:
+		return "Show day view"
+	case ActionToggleFreeEvenings:
+		return "Highlight free evenings"
+	case ActionGoToDate:
+		return "Go to date"
+	case ActionPasteEvents:
+		return "Paste events"
+	case ActionTogglePasteLine:
+		return "Accept or reject pasted line"
+	case ActionToggleFocus:
+		return "Toggle focus mode"
+	case ActionShowInbox:
+		return "Review imported events"
+	case ActionSyncNow:
+		return "Sync with CalDAV"
+	case ActionSetReminder:
+		return "Set event reminder"
+	case ActionShowCalendars:
+		return "Show or hide calendars"
+	case ActionScrollEventsUp:
+		return "Scroll events up"
+	case ActionScrollEventsDown:
+		return "Scroll events down"
+	case ActionShowMessages:
+		return "Message history"
+	case ActionMoveEvent:
+		return "Move event to a date"
+	case ActionShowBackups:
+		return "Show backups"
+	case ActionToggleCalendar:
+		return "Show or hide a calendar"
 	default:
+		if key, ok := QuickFilterKey(action); ok {
+			return "Toggle quick filter " + key
+		}
 		return "Unknown action"
 	}
 }
@@ -244,6 +630,60 @@ func (ih *InputHandler) GetTextInputWithPrompt(prompt string, maxLength int, ren
 	}
 }
 
+// GetIncrementalInput handles text input on the prompt line pre-filled with
+// defaultValue, calling changed with the input first and after every edit so the view
+// behind the prompt follows it, e.g. search results updating while typing. Up and down
+// call move with -1 and 1 to move through what the view shows.
+func (ih *InputHandler) GetIncrementalInput(prompt, defaultValue string, maxLength int, renderer *Renderer, changed func(string), move func(int)) (string, bool) {
+	input := defaultValue
+	changed(input)
+
+	for {
+		renderer.RenderInputPrompt(prompt, input)
+
+		event := ih.terminal.PollEvent()
+		if event.Type != termbox.EventKey {
+			continue
+		}
+
+		edited := input
+		switch event.Key {
+		case termbox.KeyEsc:
+			return "", false // User cancelled
+
+		case termbox.KeyEnter:
+			return strings.TrimSpace(input), true // User confirmed
+
+		case termbox.KeyArrowUp:
+			move(-1)
+
+		case termbox.KeyArrowDown:
+			move(1)
+
+		case termbox.KeyBackspace, termbox.KeyBackspace2:
+			if len(edited) > 0 {
+				edited = edited[:len(edited)-1]
+			}
+
+		case termbox.KeySpace:
+			if len(edited) < maxLength {
+				edited += " "
+			}
+
+		default:
+			// Allow printable ASCII characters
+			if event.Ch >= 32 && event.Ch <= 126 && len(edited) < maxLength {
+				edited += string(event.Ch)
+			}
+		}
+
+		if edited != input {
+			input = edited
+			changed(input)
+		}
+	}
+}
+
 // GetInlineTextInput handles text input with inline rendering at specific coordinates
 func (ih *InputHandler) GetInlineTextInput(x, y int, prompt string, maxLength int, renderer *Renderer) (string, bool) {
 	var input strings.Builder
@@ -295,13 +735,89 @@ func (ih *InputHandler) GetInlineTextInput(x, y int, prompt string, maxLength in
 
 // GetInlineTextInputWithDefault handles text input with inline rendering and pre-filled default value
 func (ih *InputHandler) GetInlineTextInputWithDefault(x, y int, prompt string, maxLength int, defaultValue string, renderer *Renderer) (string, bool) {
+	return ih.inlineTextInput(x, y, prompt, maxLength, defaultValue, renderer, nil, nil)
+}
+
+// GetInlineDateInput handles inline input of a date expression pre-filled with
+// defaultValue, previewing the date it resolves to from base below the input line.
+// Tab completes a partly typed word such as "tom" to "tomorrow"; otherwise it opens the
+// date picker at the resolved date, and a picked date replaces the input. Redraw
+// restores the view below the picker.
+func (ih *InputHandler) GetInlineDateInput(x, y int, prompt string, defaultValue string, base time.Time, renderer *Renderer, redraw func() error) (string, bool) {
+	pick := func(input string) string {
+		if completed, ok := calendar.CompleteDateExpression(input); ok {
+			return completed
+		}
+		date, err := calendar.ParseRelativeDate(input, base)
+		if err != nil {
+			date = base
+		}
+		picked, ok := ih.PickDate(x, y, date, renderer)
+		redraw()
+		if !ok {
+			return input
+		}
+		return picked.Format("2006-01-02")
+	}
+	preview := func(input string) string {
+		return renderer.DatePreview(input, base)
+	}
+	return ih.inlineTextInput(x, y, prompt, dateInputLength, defaultValue, renderer, pick, preview)
+}
+
+// GetInlineChoiceInput handles inline input of one of choices pre-filled with
+// defaultValue, listing the choices below the input line. Tab completes a partly typed
+// choice, or replaces a complete one with the next.
+func (ih *InputHandler) GetInlineChoiceInput(x, y int, prompt string, choices []string, defaultValue string, renderer *Renderer) (string, bool) {
+	pick := func(input string) string {
+		return nextChoice(choices, input)
+	}
+	preview := func(string) string {
+		return "One of: " + strings.Join(choices, ", ")
+	}
+	return ih.inlineTextInput(x, y, prompt, dateInputLength, defaultValue, renderer, pick, preview)
+}
+
+// nextChoice returns the choice input is the start of, or the choice after it when it
+// names one in full, ignoring case; other input gives the first choice
+func nextChoice(choices []string, input string) string {
+	if len(choices) == 0 {
+		return input
+	}
+	lower := strings.ToLower(input)
+	for i, choice := range choices {
+		if strings.EqualFold(choice, input) {
+			return choices[(i+1)%len(choices)]
+		}
+	}
+	for _, choice := range choices {
+		if lower != "" && strings.HasPrefix(strings.ToLower(choice), lower) {
+			return choice
+		}
+	}
+	return choices[0]
+}
+
+// dateInputLength is the longest date expression accepted by the inline date input
+const dateInputLength = 20
+
+// inlineTextInput runs the inline text input; when pick is set, Tab replaces the input
+// with the value pick returns for it, and when preview is set, the text it returns for
+// the input is shown below the input line
+func (ih *InputHandler) inlineTextInput(x, y int, prompt string, maxLength int, defaultValue string, renderer *Renderer, pick, preview func(string) string) (string, bool) {
 	var input strings.Builder
 
 	// Pre-fill with default value
 	input.WriteString(defaultValue)
+	if preview != nil {
+		defer func() { renderer.inputHint = "" }()
+	}
 
 	for {
 		// Update display with current input using inline rendering
+		if preview != nil {
+			renderer.inputHint = preview(input.String())
+		}
 		renderer.RenderInlineInput(x, y, prompt, input.String())
 
 		event := ih.terminal.PollEvent()
@@ -318,6 +834,13 @@ func (ih *InputHandler) GetInlineTextInputWithDefault(x, y int, prompt string, m
 			result := strings.TrimSpace(input.String())
 			return result, true // User confirmed
 
+		case termbox.KeyTab:
+			if pick != nil {
+				picked := pick(input.String())
+				input.Reset()
+				input.WriteString(picked)
+			}
+
 		case termbox.KeyBackspace, termbox.KeyBackspace2:
 			if input.Len() > 0 {
 				// Remove last character
@@ -365,9 +888,8 @@ func (ih *InputHandler) GetTimeInput(prompt string, renderer *Renderer) (string,
 			return "", false // User cancelled
 
 		case termbox.KeyEnter:
-			result := ih.formatTimeDisplay(input.String())
-			if len(result) == 5 { // Must be exactly HH:MM
-				return result, true
+			if isCompleteTime(input.String()) { // Must be exactly HH:MM or HH:MM-HH:MM
+				return ih.formatTimeDisplay(input.String()), true
 			}
 			// Invalid length, continue waiting for input
 			continue
@@ -395,6 +917,17 @@ func (ih *InputHandler) GetTimeInput(prompt string, renderer *Renderer) (string,
 
 // GetInlineTimeInput handles time input with inline rendering and on-the-fly validation
 func (ih *InputHandler) GetInlineTimeInput(x, y int, prompt string, renderer *Renderer) (string, bool) {
+	return ih.inlineTimeInput(x, y, prompt, false, renderer)
+}
+
+// GetInlineEventTimeInput asks for an event time like GetInlineTimeInput, where Enter
+// without a time returns "" for an all-day event
+func (ih *InputHandler) GetInlineEventTimeInput(x, y int, prompt string, renderer *Renderer) (string, bool) {
+	return ih.inlineTimeInput(x, y, prompt, true, renderer)
+}
+
+// inlineTimeInput reads an HH:MM time inline; with allowEmpty Enter also accepts no time
+func (ih *InputHandler) inlineTimeInput(x, y int, prompt string, allowEmpty bool, renderer *Renderer) (string, bool) {
 	var input strings.Builder
 
 	for {
@@ -413,9 +946,8 @@ func (ih *InputHandler) GetInlineTimeInput(x, y int, prompt string, renderer *Re
 			return "", false // User cancelled
 
 		case termbox.KeyEnter:
-			result := ih.formatTimeDisplay(input.String())
-			if len(result) == 5 { // Must be exactly HH:MM
-				return result, true
+			if isCompleteTime(input.String()) || (allowEmpty && input.Len() == 0) { // Must be exactly HH:MM or HH:MM-HH:MM
+				return ih.formatTimeDisplay(input.String()), true
 			}
 			// Invalid length, continue waiting for input
 			continue
@@ -445,12 +977,8 @@ func (ih *InputHandler) GetInlineTimeInput(x, y int, prompt string, renderer *Re
 func (ih *InputHandler) GetInlineTimeInputWithDefault(x, y int, prompt string, defaultValue string, renderer *Renderer) (string, bool) {
 	var input strings.Builder
 
-	// Pre-fill with default value (strip colon for internal representation)
-	if len(defaultValue) == 5 && defaultValue[2] == ':' {
-		input.WriteString(defaultValue[:2] + defaultValue[3:])
-	} else {
-		input.WriteString(defaultValue)
-	}
+	// Pre-fill with default value (strip colons and the dash of a range for internal representation)
+	input.WriteString(strings.NewReplacer(":", "", "-", "").Replace(defaultValue))
 
 	for {
 		// Update display with current input and format with colon if needed
@@ -468,9 +996,8 @@ func (ih *InputHandler) GetInlineTimeInputWithDefault(x, y int, prompt string, d
 			return "", false // User cancelled
 
 		case termbox.KeyEnter:
-			result := ih.formatTimeDisplay(input.String())
-			if len(result) == 5 { // Must be exactly HH:MM
-				return result, true
+			if isCompleteTime(input.String()) { // Must be exactly HH:MM or HH:MM-HH:MM
+				return ih.formatTimeDisplay(input.String()), true
 			}
 			// Invalid length, continue waiting for input
 			continue
@@ -500,10 +1027,13 @@ func (ih *InputHandler) GetInlineTimeInputWithDefault(x, y int, prompt string, d
 func (ih *InputHandler) isValidTimeDigit(currentInput string, digit rune) bool {
 	inputLen := len(currentInput)
 
-	// Maximum 4 digits (HHMM without colon)
-	if inputLen >= 4 {
+	// Maximum 8 digits (HHMM without colon, then HHMM of an optional end time)
+	if inputLen >= 8 {
 		return false
 	}
+	if inputLen >= 4 {
+		return ih.isValidTimeDigit(currentInput[4:], digit)
+	}
 
 	switch inputLen {
 	case 0: // First hour digit
@@ -534,9 +1064,18 @@ func (ih *InputHandler) isValidTimeDigit(currentInput string, digit rune) bool {
 	}
 }
 
-// formatTimeDisplay formats the internal time representation for display (adds colon)
+// isCompleteTime reports whether the digits of a time input form HHMM or a range HHMMHHMM
+func isCompleteTime(input string) bool {
+	return len(input) == 4 || len(input) == 8
+}
+
+// formatTimeDisplay formats the internal time representation for display (adds colon,
+// and a dash before the digits of an end time)
 func (ih *InputHandler) formatTimeDisplay(input string) string {
 	inputLen := len(input)
+	if inputLen > 4 {
+		return ih.formatTimeDisplay(input[:4]) + "-" + ih.formatTimeDisplay(input[4:])
+	}
 
 	if inputLen == 0 {
 		return ""
@@ -2,6 +2,7 @@ package terminal
 
 import (
 	"testing"
+	"time"
 
 	"github.com/nsf/termbox-go"
 )
@@ -24,13 +25,44 @@ func TestProcessKeyEvent(t *testing.T) {
 		{"k key", termbox.Event{Type: termbox.EventKey, Ch: 'k'}, ActionMoveUp},
 		{"j key", termbox.Event{Type: termbox.EventKey, Ch: 'j'}, ActionMoveDown},
 		{"a key", termbox.Event{Type: termbox.EventKey, Ch: 'a'}, ActionAddEvent},
+		{"s key", termbox.Event{Type: termbox.EventKey, Ch: 's'}, ActionShowStats},
+		{"u key", termbox.Event{Type: termbox.EventKey, Ch: 'u'}, ActionUndo},
+		{"m key", termbox.Event{Type: termbox.EventKey, Ch: 'm'}, ActionBookmark},
+		{"g key", termbox.Event{Type: termbox.EventKey, Ch: 'g'}, ActionGoToDate},
+		{"G key", termbox.Event{Type: termbox.EventKey, Ch: 'G'}, ActionShowBookmarks},
+		{"t key", termbox.Event{Type: termbox.EventKey, Ch: 't'}, ActionCycleTheme},
+		{"+ key", termbox.Event{Type: termbox.EventKey, Ch: '+'}, ActionMoveEventDayLater},
+		{"- key", termbox.Event{Type: termbox.EventKey, Ch: '-'}, ActionMoveEventDayEarlier},
+		{"> key", termbox.Event{Type: termbox.EventKey, Ch: '>'}, ActionMoveEventWeekLater},
+		{", key", termbox.Event{Type: termbox.EventKey, Ch: ','}, ActionMoveEventWeekEarlier},
+		{"! key", termbox.Event{Type: termbox.EventKey, Ch: '!'}, ActionToggleFlag},
+		{"o key", termbox.Event{Type: termbox.EventKey, Ch: 'o'}, ActionShowFollowUps},
+		{"v key", termbox.Event{Type: termbox.EventKey, Ch: 'v'}, ActionMarkRange},
+		{"Y key", termbox.Event{Type: termbox.EventKey, Ch: 'Y'}, ActionCopyEvents},
+		{"W key", termbox.Event{Type: termbox.EventKey, Ch: 'W'}, ActionToggleFreeEvenings},
+		{"? key", termbox.Event{Type: termbox.EventKey, Ch: '?'}, ActionShowHelp},
+		{": key", termbox.Event{Type: termbox.EventKey, Ch: ':'}, ActionGoToDate},
+		{"Shift+D key", termbox.Event{Type: termbox.EventKey, Ch: 'D'}, ActionShowDayView},
+		{"c key", termbox.Event{Type: termbox.EventKey, Ch: 'c'}, ActionResetCurrent},
+		{"Shift+C key", termbox.Event{Type: termbox.EventKey, Ch: 'C'}, ActionShowCalendars},
+		{"[ key", termbox.Event{Type: termbox.EventKey, Ch: '['}, ActionScrollEventsUp},
+		{"] key", termbox.Event{Type: termbox.EventKey, Ch: ']'}, ActionScrollEventsDown},
+		{"\" key", termbox.Event{Type: termbox.EventKey, Ch: '"'}, ActionShowMessages},
+		{"p key", termbox.Event{Type: termbox.EventKey, Ch: 'p'}, ActionPasteEvents},
+		{"X key", termbox.Event{Type: termbox.EventKey, Ch: 'X'}, ActionTogglePasteLine},
+		{"Z key", termbox.Event{Type: termbox.EventKey, Ch: 'Z'}, ActionToggleFocus},
+		{"I key", termbox.Event{Type: termbox.EventKey, Ch: 'I'}, ActionShowInbox},
+		{"R key", termbox.Event{Type: termbox.EventKey, Ch: 'R'}, ActionSyncNow},
+		{"@ key", termbox.Event{Type: termbox.EventKey, Ch: '@'}, ActionSetReminder},
 
 		// Character keys - uppercase
 		{"Q key", termbox.Event{Type: termbox.EventKey, Ch: 'Q'}, ActionQuit},
 		{"B key", termbox.Event{Type: termbox.EventKey, Ch: 'B'}, ActionMonthPrev},
 		{"N key", termbox.Event{Type: termbox.EventKey, Ch: 'N'}, ActionMonthNext},
 		{"H key", termbox.Event{Type: termbox.EventKey, Ch: 'H'}, ActionMoveLeft},
-		{"L key", termbox.Event{Type: termbox.EventKey, Ch: 'L'}, ActionMoveRight},
+		// Shift+L has an action of its own, see ShiftedKeys; lowercase l moves right
+		{"L key", termbox.Event{Type: termbox.EventKey, Ch: 'L'}, ActionShowActivityLog},
+		{"l key", termbox.Event{Type: termbox.EventKey, Ch: 'l'}, ActionMoveRight},
 		{"K key", termbox.Event{Type: termbox.EventKey, Ch: 'K'}, ActionMoveUp},
 		{"J key", termbox.Event{Type: termbox.EventKey, Ch: 'J'}, ActionMoveDown},
 		{"A key", termbox.Event{Type: termbox.EventKey, Ch: 'A'}, ActionAddEvent},
@@ -42,9 +74,9 @@ func TestProcessKeyEvent(t *testing.T) {
 		{"Ctrl+C", termbox.Event{Type: termbox.EventKey, Key: termbox.KeyCtrlC}, ActionQuit},
 
 		// Invalid/unrecognized keys
-		{"x key", termbox.Event{Type: termbox.EventKey, Ch: 'x'}, ActionNone},
+		{"# key", termbox.Event{Type: termbox.EventKey, Ch: '#'}, ActionNone},
 		{"1 key", termbox.Event{Type: termbox.EventKey, Ch: '1'}, ActionNone},
-		{"@ key", termbox.Event{Type: termbox.EventKey, Ch: '@'}, ActionNone},
+		{"~ key", termbox.Event{Type: termbox.EventKey, Ch: '~'}, ActionNone},
 
 		// Non-key events
 		{"Mouse event", termbox.Event{Type: termbox.EventMouse}, ActionNone},
@@ -61,6 +93,105 @@ func TestProcessKeyEvent(t *testing.T) {
 	}
 }
 
+func TestProcessChordKeyEvent(t *testing.T) {
+	key := func(ch rune) termbox.Event { return termbox.Event{Type: termbox.EventKey, Ch: ch} }
+	start := time.Date(2025, 8, 15, 9, 0, 0, 0, time.Local)
+	quick := start.Add(100 * time.Millisecond)
+	late := start.Add(DefaultChordTimeout + time.Millisecond)
+
+	tests := []struct {
+		name     string
+		second   termbox.Event
+		at       time.Time
+		expected KeyAction
+	}{
+		{"gg jumps to today", key('g'), quick, ActionResetCurrent},
+		{"g then j cancels the chord", key('j'), quick, ActionMoveDown},
+		{"g g after timeout starts a new chord", key('g'), late, ActionNone},
+		{"g then Esc", termbox.Event{Type: termbox.EventKey, Key: termbox.KeyEsc}, quick, ActionBack},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ih := NewInputHandler(nil)
+
+			if action := ih.ProcessChordKeyEvent(key('g'), start); action != ActionNone {
+				t.Fatalf("First chord key returned %v, want ActionNone", action)
+			}
+			if keys := ih.PendingKeys(); keys != "g" {
+				t.Errorf("PendingKeys() = %q, want %q", keys, "g")
+			}
+
+			if action := ih.ProcessChordKeyEvent(tt.second, tt.at); action != tt.expected {
+				t.Errorf("Second key returned %v, want %v", action, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProcessChordKeyEvent_DeleteChord(t *testing.T) {
+	ih := NewInputHandler(nil)
+	now := time.Now()
+	d := termbox.Event{Type: termbox.EventKey, Ch: 'd'}
+
+	ih.ProcessChordKeyEvent(d, now)
+	if action := ih.ProcessChordKeyEvent(d, now); action != ActionDeleteSelected {
+		t.Errorf("dd returned %v, want ActionDeleteSelected", action)
+	}
+	if keys := ih.PendingKeys(); keys != "" {
+		t.Errorf("PendingKeys() after a chord = %q, want empty", keys)
+	}
+
+	// Keys that start no chord are processed immediately
+	if action := ih.ProcessChordKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: 'a'}, now); action != ActionAddEvent {
+		t.Errorf("a returned %v, want ActionAddEvent", action)
+	}
+}
+
+func TestExpirePendingChord(t *testing.T) {
+	ih := NewInputHandler(nil)
+	start := time.Now()
+
+	ih.ProcessChordKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: 'g'}, start)
+
+	if action := ih.ExpirePendingChord(start.Add(DefaultChordTimeout / 2)); action != ActionNone {
+		t.Errorf("ExpirePendingChord() before timeout = %v, want ActionNone", action)
+	}
+	if action := ih.ExpirePendingChord(start.Add(DefaultChordTimeout)); action != ActionGoToDate {
+		t.Errorf("ExpirePendingChord() after timeout = %v, want the single g action", action)
+	}
+	if action := ih.ExpirePendingChord(start.Add(2 * DefaultChordTimeout)); action != ActionNone {
+		t.Errorf("ExpirePendingChord() should only fire once, got %v", action)
+	}
+
+	ih.ProcessChordKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: 'd'}, start)
+	ih.CancelPendingChord()
+	if keys := ih.PendingKeys(); keys != "" {
+		t.Errorf("PendingKeys() after cancel = %q, want empty", keys)
+	}
+}
+
+func TestProcessChordKeyEvent_StopsTimeoutWhenResolved(t *testing.T) {
+	ih := NewInputHandler(nil)
+	ih.timeout = 20 * time.Millisecond
+	woken := make(chan struct{}, 2)
+	ih.wake = func() { woken <- struct{}{} }
+
+	// dd resolves the chord; its timeout must not wake the confirmation that follows
+	now := time.Now()
+	ih.ProcessChordKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: 'd'}, now)
+	if action := ih.ProcessChordKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: 'd'}, now); action != ActionDeleteSelected {
+		t.Fatalf("dd returned %v, want ActionDeleteSelected", action)
+	}
+	ih.ProcessChordKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: 'g'}, now)
+	ih.CancelPendingChord()
+
+	time.Sleep(3 * ih.timeout)
+	if len(woken) != 0 {
+		t.Errorf("Resolved and cancelled chords woke the event loop %d times, want none", len(woken))
+	}
+}
+
 func TestGetKeyDescription(t *testing.T) {
 	terminal := NewTerminal()
 	ih := NewInputHandler(terminal)
@@ -79,6 +210,11 @@ func TestGetKeyDescription(t *testing.T) {
 		{ActionShowEvents, "Show events for selected date"},
 		{ActionAddEvent, "Add new event"},
 		{ActionBack, "Back to previous view"},
+		{ActionSetReminder, "Set event reminder"},
+		{ActionShowCalendars, "Show or hide calendars"},
+		{ActionScrollEventsUp, "Scroll events up"},
+		{ActionScrollEventsDown, "Scroll events down"},
+		{ActionShowMessages, "Message history"},
 		{ActionNone, "Unknown action"},
 	}
 
@@ -208,7 +344,8 @@ func TestCaseInsensitiveProcessing(t *testing.T) {
 	terminal := NewTerminal()
 	ih := NewInputHandler(terminal)
 
-	// Test that uppercase and lowercase versions of keys produce the same action
+	// Test that uppercase and lowercase versions of keys produce the same action,
+	// except for the uppercase letters of ShiftedKeys, which have an action of their own
 	testKeys := []rune{'q', 'b', 'n', 'h', 'j', 'k', 'l', 'a'}
 
 	for _, key := range testKeys {
@@ -218,7 +355,12 @@ func TestCaseInsensitiveProcessing(t *testing.T) {
 		lowerAction := ih.ProcessKeyEvent(lowerEvent)
 		upperAction := ih.ProcessKeyEvent(upperEvent)
 
-		if lowerAction != upperAction {
+		if shifted, ok := ShiftedKeys[key-32]; ok {
+			if upperAction != shifted || lowerAction == shifted {
+				t.Errorf("Shifted key %c: lowercase=%v, uppercase=%v; want uppercase %v only",
+					key-32, lowerAction, upperAction, shifted)
+			}
+		} else if lowerAction != upperAction {
 			t.Errorf("Case insensitive processing failed for key %c: lowercase=%v, uppercase=%v",
 				key, lowerAction, upperAction)
 		}
@@ -230,6 +372,48 @@ func TestCaseInsensitiveProcessing(t *testing.T) {
 	}
 }
 
+// Test that ShiftedKeys, which the help screen lists, names every letter whose case matters
+func TestShiftedKeys_Complete(t *testing.T) {
+	ih := NewInputHandler(NewTerminal())
+	for lower := 'a'; lower <= 'z'; lower++ {
+		upper := lower - 'a' + 'A'
+		lowerAction := ih.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: lower})
+		upperAction := ih.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: upper})
+		if _, shifted := ShiftedKeys[upper]; shifted != (lowerAction != upperAction) {
+			t.Errorf("%c = %v and %c = %v, but ShiftedKeys lists %c: %v", lower, lowerAction, upper, upperAction, upper, shifted)
+		}
+	}
+}
+
+func TestProcessKeyEvent_View(t *testing.T) {
+	ih := NewInputHandler(NewTerminal())
+	tests := []struct {
+		view KeyView
+		ch   rune
+		want KeyAction
+	}{
+		{ViewCalendar, 'b', ActionMonthPrev},
+		{ViewCalendar, 'B', ActionMonthPrev},
+		{ViewCalendar, 'D', ActionShowDayView},
+		{ViewList, 'D', ActionDeleteEvent},
+		{ViewList, 'd', ActionDeleteEvent},
+		{ViewList, 'b', ActionMonthPrev},
+		{ViewActivityLog, 'b', ActionShowBackups},
+		{ViewActivityLog, 'B', ActionShowBackups},
+		{ViewActivityLog, 'u', ActionUndo},
+		{ViewCalendars, 'x', ActionToggleCalendar},
+		{ViewCalendars, 'X', ActionToggleCalendar},
+		{ViewCalendar, 'x', ActionTogglePasteLine},
+	}
+
+	for _, tt := range tests {
+		ih.SetView(tt.view)
+		if got := ih.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: tt.ch}); got != tt.want {
+			t.Errorf("%c in view %d = %v, want %v", tt.ch, tt.view, got, tt.want)
+		}
+	}
+}
+
 // Test special key handling
 func TestSpecialKeyHandling(t *testing.T) {
 	terminal := NewTerminal()
@@ -244,6 +428,11 @@ func TestSpecialKeyHandling(t *testing.T) {
 		{termbox.KeyEnter, ActionShowEvents},
 		{termbox.KeySpace, ActionNone},
 		{termbox.KeyCtrlC, ActionQuit},
+		{termbox.KeyF1, ActionQuickFilter1},
+		{termbox.KeyF5, ActionQuickFilter1 + 4},
+		{termbox.KeyF8, ActionQuickFilter8},
+		{termbox.KeyF9, ActionClearFilters},
+		{termbox.KeyF10, ActionNone},
 	}
 
 	for _, tt := range specialKeys {
@@ -317,3 +506,108 @@ func TestKeyDescriptionsComprehensive(t *testing.T) {
 		t.Errorf("Undefined action should return 'Unknown action', got '%s'", description)
 	}
 }
+
+func TestNormalizeKeyEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    termbox.Event
+		expected termbox.Key
+	}{
+		{"Windows backspace (BS)", termbox.Event{Type: termbox.EventKey, Key: termbox.KeyBackspace}, termbox.KeyBackspace2},
+		{"Unix backspace (DEL)", termbox.Event{Type: termbox.EventKey, Key: termbox.KeyBackspace2}, termbox.KeyBackspace2},
+		{"DEL as character", termbox.Event{Type: termbox.EventKey, Ch: 0x7f}, termbox.KeyBackspace2},
+		{"CR as character", termbox.Event{Type: termbox.EventKey, Ch: '\r'}, termbox.KeyEnter},
+		{"LF as character", termbox.Event{Type: termbox.EventKey, Ch: '\n'}, termbox.KeyEnter},
+		{"ESC as character", termbox.Event{Type: termbox.EventKey, Ch: 0x1b}, termbox.KeyEsc},
+		{"Arrow key untouched", termbox.Event{Type: termbox.EventKey, Key: termbox.KeyArrowUp}, termbox.KeyArrowUp},
+		{"Printable untouched", termbox.Event{Type: termbox.EventKey, Ch: 'a'}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := normalizeKeyEvent(tt.event)
+			if result.Key != tt.expected {
+				t.Errorf("normalizeKeyEvent() key = %v, want %v", result.Key, tt.expected)
+			}
+		})
+	}
+
+	// Normalized keys drive the same actions on every platform
+	ih := NewInputHandler(NewTerminal())
+	if action := ih.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Ch: '\r'}); action != ActionShowEvents {
+		t.Errorf("CR character action = %v, want ActionShowEvents", action)
+	}
+	if action := ih.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Key: termbox.KeyPgdn}); action != ActionMonthNext {
+		t.Errorf("Page Down action = %v, want ActionMonthNext", action)
+	}
+	if action := ih.ProcessKeyEvent(termbox.Event{Type: termbox.EventKey, Key: termbox.KeyHome}); action != ActionResetCurrent {
+		t.Errorf("Home action = %v, want ActionResetCurrent", action)
+	}
+}
+
+func TestQuickFilterKey(t *testing.T) {
+	tests := []struct {
+		action KeyAction
+		key    string
+		ok     bool
+	}{
+		{ActionQuickFilter1, "F1", true},
+		{ActionQuickFilter1 + 2, "F3", true},
+		{ActionQuickFilter8, "F8", true},
+		{ActionClearFilters, "", false},
+		{ActionQuit, "", false},
+	}
+
+	for _, tt := range tests {
+		key, ok := QuickFilterKey(tt.action)
+		if key != tt.key || ok != tt.ok {
+			t.Errorf("QuickFilterKey(%v) = %q, %v, want %q, %v", tt.action, key, ok, tt.key, tt.ok)
+		}
+	}
+}
+
+func TestTimeInput_Range(t *testing.T) {
+	ih := NewInputHandler(NewTerminal())
+
+	tests := map[string]string{
+		"14":       "14:__",
+		"1400":     "14:00",
+		"14001":    "14:00-1_",
+		"140015":   "14:00-15:__",
+		"14001530": "14:00-15:30",
+	}
+	for input, want := range tests {
+		if got := ih.formatTimeDisplay(input); got != want {
+			t.Errorf("formatTimeDisplay(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	if !ih.isValidTimeDigit("1400", '1') || ih.isValidTimeDigit("1400", '3') || ih.isValidTimeDigit("140015", '6') {
+		t.Error("The digits of an end time should be checked like those of the start")
+	}
+	if ih.isValidTimeDigit("14001530", '0') {
+		t.Error("No digit should be accepted after a complete range")
+	}
+	for input, want := range map[string]bool{"": false, "140": false, "1400": true, "140015": false, "14001530": true} {
+		if got := isCompleteTime(input); got != want {
+			t.Errorf("isCompleteTime(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestNextChoice(t *testing.T) {
+	choices := []string{"personal", "work", "family"}
+	tests := map[string]string{
+		"":         "personal",
+		"w":        "work",
+		"FA":       "family",
+		"personal": "work",
+		"Family":   "personal",
+		"school":   "personal",
+	}
+	for input, want := range tests {
+		if got := nextChoice(choices, input); got != want {
+			t.Errorf("nextChoice(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,432 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/models"
+)
+
+const (
+	calendarTopY  = 2 // Row of the month headers without decorations
+	legendRows    = 2 // Key legend and status line below the events panel
+	minPanelRows  = 4 // Events panel header plus a few events that must stay visible
+	weekTotalRows = 6 // One week total per week row of the tallest month
+	monthSpacing  = 2 // Columns between months
+	baseCellWidth = 3 // Columns of a day cell at scale 1: two digits and a marker
+)
+
+// MinWidth and MinHeight are the smallest terminal size the calendar view can be
+// drawn in: a single month stacked above the minimum events panel
+const (
+	MinWidth  = 24
+	MinHeight = 19
+)
+
+// calendarLayout holds the measured rows of the calendar view. Decorations that would
+// push the events panel below its minimum size are dropped before anything is drawn,
+// and a UI scale that does not fit falls back to 1. Terminals too narrow for three
+// months side by side get the months stacked top to bottom, two months side by side or
+// the selected month alone instead.
+type calendarLayout struct {
+	decorations config.Decorations // Decorations that fit
+	focus       bool               // Focus mode: no header rows above the months and no legend below the panel
+	scale       int                // UI scale that fits
+	vertical    bool               // Months stacked top to bottom instead of side by side
+	months      int                // Months shown: three, two side by side, or only the selected one
+	monthStep   int                // Rows from one stacked month to the next
+	cellWidth   int                // Columns of a day cell
+	rowStep     int                // Rows from one week to the next
+	monthWidth  int                // Columns of a month grid, including its padding
+	gridRows    int                // Month header, blank row, day names, separator and six weeks
+	bannerY     int                // First row of the month banner
+	monthsY     int                // Row of the month headers
+	weekTotalsY int                // First row of the week totals under the grids
+	separatorY  int                // Row of the separator below the calendar
+	panelY      int                // Row of the events panel header
+}
+
+// buildCalendarLayout stacks the calendar view's parts with the given decorations,
+// the three months side by side. At scale 2 day cells are twice as wide and weeks
+// are a blank row apart.
+func buildCalendarLayout(decorations config.Decorations, scale int) calendarLayout {
+	return buildStackedLayout(decorations, scale, false, 3)
+}
+
+// buildStackedLayout is buildCalendarLayout with the given number of months, stacked
+// top to bottom when vertical is set. Week totals are never shown in a stacked layout.
+func buildStackedLayout(decorations config.Decorations, scale int, vertical bool, months int) calendarLayout {
+	if scale < 1 {
+		scale = 1
+	}
+	if vertical {
+		decorations.WeekTotals = false
+	}
+	layout := calendarLayout{decorations: decorations, scale: scale, vertical: vertical, months: months}
+	layout.cellWidth = baseCellWidth * scale
+	layout.rowStep = scale
+	// Seven cells plus a column of padding on both sides; the day numbers are centered
+	// in wider cells, so the unused half of the first and last cell's padding is dropped
+	layout.monthWidth = 7*layout.cellWidth + 3 - (layout.cellWidth-baseCellWidth)/2
+	layout.gridRows = 4 + 5*layout.rowStep + 1
+
+	y := calendarTopY
+	if decorations.MonthBanner {
+		layout.bannerY = 1
+		y = layout.bannerY + len(BannerText("")) + 1
+		if decorations.Borders {
+			y++ // The top border needs a row of its own below the banner
+		}
+	}
+	layout.monthsY = y
+
+	// A stacked month is a blank row below the previous one, or below its border
+	layout.monthStep = layout.gridRows + 1
+	if decorations.Borders {
+		layout.monthStep++
+	}
+	end := y + layout.gridRows
+	if vertical {
+		end += (months - 1) * layout.monthStep
+	}
+	if decorations.Borders {
+		end++ // Bottom border
+	}
+	if decorations.WeekTotals {
+		layout.weekTotalsY = end
+		end += weekTotalRows
+	}
+	layout.separatorY = end
+	layout.panelY = end + 1
+	return layout
+}
+
+// totalWidth returns the columns taken by the months side by side and the space
+// between them, or by a single month when they are stacked
+func (l calendarLayout) totalWidth() int {
+	if l.vertical {
+		return l.monthWidth
+	}
+	return l.months*l.monthWidth + (l.months-1)*monthSpacing
+}
+
+// dayX returns the column of a day number in the grid of a month starting at column x
+func (l calendarLayout) dayX(x, dayIndex int) int {
+	return x + dayIndex*l.cellWidth + 1 + (l.cellWidth-baseCellWidth)/2
+}
+
+// fits reports whether the layout leaves room for the minimum events panel, the months
+// and the banner
+func (l calendarLayout) fits(width, height int) bool {
+	// The key legend and status line take the last two rows, except in focus mode
+	bottom := height - legendRows
+	if l.focus {
+		bottom = height
+	}
+	if l.panelY+minPanelRows > bottom || l.totalWidth() > width {
+		return false
+	}
+	return !l.decorations.MonthBanner || width >= maxBannerWidth()
+}
+
+// measureCalendarLayout returns the layout for the terminal size, dropping the
+// tallest decorations first until the events panel keeps its minimum size. The UI
+// scale is given up last, once no decoration is left to drop. Below the width of three
+// bare months the months are stacked instead.
+func measureCalendarLayout(width, height int, wanted config.Decorations, scale int) calendarLayout {
+	if width < buildCalendarLayout(config.Decorations{}, 1).totalWidth() {
+		return measureStackedLayout(width, height, wanted, scale)
+	}
+
+	layout := buildCalendarLayout(wanted, scale)
+	if !layout.fits(width, height) && wanted.WeekTotals {
+		wanted.WeekTotals = false
+		layout = buildCalendarLayout(wanted, scale)
+	}
+	if !layout.fits(width, height) && wanted.MonthBanner {
+		wanted.MonthBanner = false
+		layout = buildCalendarLayout(wanted, scale)
+	}
+	if !layout.fits(width, height) && wanted.Borders {
+		wanted.Borders = false
+		layout = buildCalendarLayout(wanted, scale)
+	}
+	if !layout.fits(width, height) && scale > 1 {
+		layout = measureCalendarLayout(width, height, wanted, 1)
+	}
+	return layout
+}
+
+// narrowLayouts are the arrangements of the months tried in turn on terminals too
+// narrow for three months side by side: three months stacked when the terminal is
+// tall enough, two side by side in a tmux split or a phone's SSH client, and the
+// selected month alone
+var narrowLayouts = []struct {
+	vertical bool
+	months   int
+}{{true, 3}, {false, 2}, {true, 1}}
+
+// measureStackedLayout returns the layout for a terminal too narrow for three months
+// side by side. Decorations are dropped before an arrangement gives way to the next
+// one of narrowLayouts, and the UI scale is given up last.
+func measureStackedLayout(width, height int, wanted config.Decorations, scale int) calendarLayout {
+	var layout calendarLayout
+	for _, arrangement := range narrowLayouts {
+		decorations := wanted
+		layout = buildStackedLayout(decorations, scale, arrangement.vertical, arrangement.months)
+		if !layout.fits(width, height) && decorations.WeekTotals {
+			decorations.WeekTotals = false
+			layout = buildStackedLayout(decorations, scale, arrangement.vertical, arrangement.months)
+		}
+		if !layout.fits(width, height) && decorations.MonthBanner {
+			decorations.MonthBanner = false
+			layout = buildStackedLayout(decorations, scale, arrangement.vertical, arrangement.months)
+		}
+		if !layout.fits(width, height) && decorations.Borders {
+			decorations.Borders = false
+			layout = buildStackedLayout(decorations, scale, arrangement.vertical, arrangement.months)
+		}
+		if layout.fits(width, height) {
+			return layout
+		}
+	}
+	if scale > 1 {
+		return measureStackedLayout(width, height, wanted, 1)
+	}
+	return layout
+}
+
+// measureFocusLayout returns the layout of focus mode: no decorations, the months on
+// the top row and the events panel down to the last row. That is the layout of a
+// terminal taller by the header and legend rows, moved up.
+func measureFocusLayout(width, height, scale int) calendarLayout {
+	layout := measureCalendarLayout(width, height+calendarTopY+legendRows, config.Decorations{}, scale)
+	layout.focus = true
+	layout.monthsY -= calendarTopY
+	layout.separatorY -= calendarTopY
+	layout.panelY -= calendarTopY
+	return layout
+}
+
+// tooSmall reports whether not even a single bare month fits the terminal
+func (r *Renderer) tooSmall() bool {
+	width, height := r.terminal.GetSize()
+	return !r.layout().fits(width, height)
+}
+
+// renderTooSmall replaces the view with a note asking for a larger terminal
+func (r *Renderer) renderTooSmall() error {
+	_, height := r.terminal.GetSize()
+	errorFg, errorBg := r.style(StyleError)
+	r.terminal.PrintCentered(height/2, fmt.Sprintf("Terminal too small! Minimum %dx%d required.", MinWidth, MinHeight), errorFg, errorBg)
+	return r.terminal.Flush()
+}
+
+// layout measures the calendar view for the current terminal size and configuration
+func (r *Renderer) layout() calendarLayout {
+	width, height := r.terminal.GetSize()
+	var decorations config.Decorations
+	scale := 1
+	if r.config != nil {
+		decorations = r.config.Decorations
+		scale = r.config.UIScale
+	}
+	if r.focus {
+		return measureFocusLayout(width, height, scale)
+	}
+	return measureCalendarLayout(width, height, decorations, scale)
+}
+
+// SetFocus switches focus mode on or off. Focus mode shows only the month grids and
+// the selected day's events, hiding the key legend, status bar, headers and
+// decorations, which leaves more of a small terminal to the events panel.
+func (r *Renderer) SetFocus(on bool) {
+	r.focus = on
+}
+
+// Focus reports whether focus mode is on
+func (r *Renderer) Focus() bool {
+	return r.focus
+}
+
+// renderMonths draws the months and decorations that fit the terminal
+func (r *Renderer) renderMonths(cal *models.Calendar, selection *models.Selection) error {
+	width, _ := r.terminal.GetSize()
+	layout := r.layout()
+
+	// Calculate starting positions for three months
+	totalWidth := layout.totalWidth()
+	startX := (width - totalWidth) / 2
+
+	if !r.focus {
+		r.renderGoalsHeader()
+		r.renderInboxHeader()
+		r.renderFilterHeader()
+	}
+
+	if layout.decorations.MonthBanner {
+		// The banner font only has ASCII letters, so the banner keeps the English name
+		bannerFg, bannerBg := r.style(StyleMonthHeader)
+		title := fmt.Sprintf("%s %d", calendar.GetMonthName(cal.CurrentMonth), cal.CurrentMonth.Year())
+		for i, line := range BannerText(title) {
+			r.terminal.PrintCentered(layout.bannerY+i, line, bannerFg, bannerBg)
+		}
+	}
+
+	months := []time.Time{cal.GetPreviousMonth(), cal.CurrentMonth, cal.GetNextMonth()}
+	switch layout.months {
+	case 2:
+		// The current and next month, unless the selection is in the previous one
+		if selection == nil || !selection.SelectedDate.Before(calendar.GetFirstDayOfMonth(cal.CurrentMonth)) {
+			months = months[1:]
+		} else {
+			months = months[:2]
+		}
+	case 1:
+		months = []time.Time{cal.CurrentMonth}
+		if selection != nil {
+			months[0] = calendar.GetFirstDayOfMonth(selection.SelectedDate)
+		}
+	}
+
+	// Render each month
+	for i, month := range months {
+		x := startX + i*(layout.monthWidth+monthSpacing)
+		y := layout.monthsY
+		if layout.vertical {
+			x, y = startX, y+i*layout.monthStep
+		}
+		if layout.decorations.Borders {
+			r.renderMonthBorder(layout, x, y-1)
+		}
+		if err := r.renderMonth(layout, month, x, y, selection); err != nil {
+			return err
+		}
+		if layout.decorations.WeekTotals {
+			r.renderWeekTotals(month, x+1, layout.weekTotalsY)
+		}
+	}
+
+	if layout.decorations.Separators {
+		separatorFg, separatorBg := r.style(StyleSeparator)
+		for i := 0; i < totalWidth; i++ {
+			r.terminal.SetCell(startX+i, layout.separatorY, '─', separatorFg, separatorBg)
+		}
+	}
+	return nil
+}
+
+// renderWeekTotals lists the number of events of each week row of a month grid,
+// one row per week in the same order, e.g. "wk36: 7 ev"
+func (r *Renderer) renderWeekTotals(month time.Time, x, y int) {
+	fg, bg := r.style(StyleText)
+	mutedFg, mutedBg := r.style(StyleMuted)
+
+	for i, start := range calendar.GetWeekStarts(month, int(r.config.WeekStartDay)) {
+		total := 0
+		for day := 0; day < 7; day++ {
+			total += len(r.eventManager.GetEventsForDate(start.AddDate(0, 0, day)))
+		}
+
+		// The middle of the row names the week, so Sunday-first rows get the ISO week of their Monday
+		text := fmt.Sprintf("wk%02d: %d ev", calendar.GetWeekOfYear(start.AddDate(0, 0, 3)), total)
+		if total == 0 {
+			r.terminal.Print(x, y+i, text, mutedFg, mutedBg)
+		} else {
+			r.terminal.Print(x, y+i, text, fg, bg)
+		}
+	}
+}
+
+// renderMonthBorder draws a box around a month grid whose top border is at row y
+func (r *Renderer) renderMonthBorder(layout calendarLayout, x, y int) {
+	fg, bg := r.style(StyleSeparator)
+	right := x + layout.monthWidth - 1
+	bottom := y + layout.gridRows + 1
+
+	for i := x + 1; i < right; i++ {
+		r.terminal.SetCell(i, y, '─', fg, bg)
+		r.terminal.SetCell(i, bottom, '─', fg, bg)
+	}
+	for j := y + 1; j < bottom; j++ {
+		r.terminal.SetCell(x, j, '│', fg, bg)
+		r.terminal.SetCell(right, j, '│', fg, bg)
+	}
+	r.terminal.SetCell(x, y, '┌', fg, bg)
+	r.terminal.SetCell(right, y, '┐', fg, bg)
+	r.terminal.SetCell(x, bottom, '└', fg, bg)
+	r.terminal.SetCell(right, bottom, '┘', fg, bg)
+}
+
+// bannerFont is a three-row ASCII-art font covering month names, digits and the colon
+// of the screensaver clock
+var bannerFont = map[rune][3]string{
+	'A': {" _ ", "|_|", "| |"},
+	'B': {" _ ", "|_)", "|_)"},
+	'C': {" _ ", "|  ", "|_ "},
+	'D': {" _ ", "| \\", "|_/"},
+	'E': {" _ ", "|_ ", "|_ "},
+	'F': {" _ ", "|_ ", "|  "},
+	'G': {" _ ", "| _", "|_|"},
+	'H': {"   ", "|_|", "| |"},
+	'I': {" ", "|", "|"},
+	'J': {"   ", "  |", "|_|"},
+	'L': {"   ", "|  ", "|_ "},
+	'M': {"    ", "|\\/|", "|  |"},
+	'N': {"    ", "|\\ |", "| \\|"},
+	'O': {" _ ", "| |", "|_|"},
+	'P': {" _ ", "|_)", "|  "},
+	'R': {" _ ", "|_)", "| \\"},
+	'S': {" _ ", "(_ ", " _)"},
+	'T': {"___", " | ", " | "},
+	'U': {"   ", "| |", "|_|"},
+	'V': {"    ", "\\  /", " \\/ "},
+	'Y': {"   ", "\\_/", " | "},
+	'0': {" _ ", "| |", "|_|"},
+	'1': {" ", "|", "|"},
+	'2': {" _ ", " _|", "|_ "},
+	'3': {" _ ", " _|", " _|"},
+	'4': {"   ", "|_|", "  |"},
+	'5': {" _ ", "|_ ", " _|"},
+	'6': {" _ ", "|_ ", "|_|"},
+	'7': {" _ ", "  |", "  |"},
+	'8': {" _ ", "|_|", "|_|"},
+	'9': {" _ ", "|_|", " _|"},
+	' ': {"  ", "  ", "  "},
+	':': {" ", ".", "."},
+}
+
+// BannerText renders text in the banner font, one string per row. Characters
+// without a glyph are left out.
+func BannerText(text string) [3]string {
+	var rows [3]strings.Builder
+	first := true
+	for _, ch := range strings.ToUpper(text) {
+		glyph, ok := bannerFont[ch]
+		if !ok {
+			continue
+		}
+		for i := range rows {
+			if !first {
+				rows[i].WriteByte(' ')
+			}
+			rows[i].WriteString(glyph[i])
+		}
+		first = false
+	}
+	return [3]string{rows[0].String(), rows[1].String(), rows[2].String()}
+}
+
+// maxBannerWidth is the width of the widest month banner
+func maxBannerWidth() int {
+	widest := 0
+	for month := time.January; month <= time.December; month++ {
+		if width := len(BannerText(month.String() + " 2000")[1]); width > widest {
+			widest = width
+		}
+	}
+	return widest
+}
@@ -0,0 +1,158 @@
+package terminal
+
+import (
+	"testing"
+
+	"go-ascii-calendar/config"
+)
+
+func TestMeasureCalendarLayout(t *testing.T) {
+	all := config.Decorations{MonthBanner: true, Borders: true, Separators: true}
+
+	tests := []struct {
+		name        string
+		width       int
+		height      int
+		wanted      config.Decorations
+		wantBanner  bool
+		wantBorders bool
+		wantMonthsY int
+		wantPanelY  int
+	}{
+		{"No decorations", 80, 24, config.Decorations{}, false, false, 2, 13},
+		{"Separators take the blank row", 80, 24, config.Decorations{Separators: true}, false, false, 2, 13},
+		{"Borders", 80, 24, config.Decorations{Borders: true}, false, true, 2, 14},
+		{"Banner", 80, 24, config.Decorations{MonthBanner: true}, true, false, 5, 16},
+		{"Everything fits", 80, 24, all, true, true, 6, 18},
+		{"Banner dropped first", 80, 22, all, false, true, 2, 14},
+		{"Borders dropped next", 80, 18, all, false, false, 2, 13},
+		{"Banner too wide", 40, 41, config.Decorations{MonthBanner: true}, false, false, 2, 35},
+		{"Week totals", 80, 30, config.Decorations{WeekTotals: true}, false, false, 2, 19},
+		{"Week totals dropped first", 80, 25, config.Decorations{WeekTotals: true, Borders: true}, false, true, 2, 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := measureCalendarLayout(tt.width, tt.height, tt.wanted, 1)
+			if layout.decorations.MonthBanner != tt.wantBanner || layout.decorations.Borders != tt.wantBorders {
+				t.Errorf("Decorations = %+v, want banner %v, borders %v", layout.decorations, tt.wantBanner, tt.wantBorders)
+			}
+			if layout.monthsY != tt.wantMonthsY || layout.panelY != tt.wantPanelY {
+				t.Errorf("monthsY, panelY = %d, %d; want %d, %d", layout.monthsY, layout.panelY, tt.wantMonthsY, tt.wantPanelY)
+			}
+			if layout.decorations.WeekTotals && layout.weekTotalsY != layout.separatorY-weekTotalRows {
+				t.Errorf("weekTotalsY = %d, want the %d rows above the separator at %d", layout.weekTotalsY, weekTotalRows, layout.separatorY)
+			}
+			if layout.decorations.Separators != tt.wanted.Separators {
+				t.Errorf("Separators = %v, want %v", layout.decorations.Separators, tt.wanted.Separators)
+			}
+		})
+	}
+}
+
+func TestMeasureCalendarLayout_Scale(t *testing.T) {
+	normal := measureCalendarLayout(80, 24, config.Decorations{}, 1)
+	if normal.monthWidth != 24 || normal.gridRows != 10 || normal.dayX(0, 1) != 4 {
+		t.Errorf("Scale 1 = width %d, rows %d, second day at %d; want 24, 10, 4", normal.monthWidth, normal.gridRows, normal.dayX(0, 1))
+	}
+
+	large := measureCalendarLayout(160, 40, config.Decorations{Borders: true}, 2)
+	if large.scale != 2 || large.cellWidth != 6 || large.rowStep != 2 {
+		t.Fatalf("Scale 2 layout = %+v, want double-width cells two rows apart", large)
+	}
+	if large.totalWidth() > 160 || large.dayX(0, 6)+2 >= large.monthWidth-1 {
+		t.Errorf("Scale 2 grid of width %d does not fit its days", large.monthWidth)
+	}
+	if !large.decorations.Borders {
+		t.Error("Decorations should be kept while the scale fits")
+	}
+
+	// Decorations go before the scale, and the scale falls back to 1 when even a bare
+	// grid does not fit
+	if layout := measureCalendarLayout(160, 24, config.Decorations{Borders: true}, 2); layout.scale != 2 || layout.decorations.Borders {
+		t.Errorf("Short terminal = scale %d, borders %v; want scale 2 without borders", layout.scale, layout.decorations.Borders)
+	}
+	if layout := measureCalendarLayout(80, 40, config.Decorations{}, 2); layout.scale != 1 || layout.monthWidth != 24 {
+		t.Errorf("Narrow terminal = scale %d, width %d; want the normal layout", layout.scale, layout.monthWidth)
+	}
+}
+
+func TestMeasureCalendarLayout_Stacked(t *testing.T) {
+	if layout := measureCalendarLayout(76, 24, config.Decorations{}, 1); layout.vertical {
+		t.Error("Three bare months fit side by side at 76 columns")
+	}
+
+	tests := []struct {
+		name         string
+		width        int
+		height       int
+		wanted       config.Decorations
+		wantVertical bool
+		wantMonths   int
+		wantBorders  bool
+		wantPanelY   int
+	}{
+		{"Three months with borders", 50, 60, config.Decorations{Borders: true, WeekTotals: true}, true, 3, true, 38},
+		{"Borders dropped before months", 50, 41, config.Decorations{Borders: true}, true, 3, false, 35},
+		{"Two months side by side", 50, 40, config.Decorations{Borders: true}, false, 2, true, 14},
+		{"Two months in a short split", 75, 24, config.Decorations{Borders: true, WeekTotals: true}, false, 2, true, 14},
+		{"Selected month only", 49, 40, config.Decorations{Borders: true}, true, 1, true, 14},
+		{"Smallest terminal", MinWidth, MinHeight, config.Decorations{}, true, 1, false, 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := measureCalendarLayout(tt.width, tt.height, tt.wanted, 1)
+			if layout.vertical != tt.wantVertical || layout.months != tt.wantMonths || layout.decorations.Borders != tt.wantBorders {
+				t.Errorf("Layout = vertical %v, %d months, borders %v; want vertical %v, %d months, borders %v",
+					layout.vertical, layout.months, layout.decorations.Borders, tt.wantVertical, tt.wantMonths, tt.wantBorders)
+			}
+			if layout.decorations.WeekTotals {
+				t.Error("Week totals should not be shown in a narrow layout")
+			}
+			if layout.panelY != tt.wantPanelY || !layout.fits(tt.width, tt.height) {
+				t.Errorf("panelY = %d, fits %v; want %d and fitting", layout.panelY, layout.fits(tt.width, tt.height), tt.wantPanelY)
+			}
+		})
+	}
+
+	for _, size := range [][2]int{{MinWidth - 1, MinHeight}, {MinWidth, MinHeight - 1}} {
+		if measureCalendarLayout(size[0], size[1], config.Decorations{}, 1).fits(size[0], size[1]) {
+			t.Errorf("A %dx%d terminal should be too small", size[0], size[1])
+		}
+	}
+}
+
+func TestMeasureFocusLayout(t *testing.T) {
+	layout := measureFocusLayout(80, 24, 1)
+	if layout.monthsY != 0 || layout.panelY != 11 || layout.decorations != (config.Decorations{}) {
+		t.Errorf("Focus layout = months at %d, panel at %d, decorations %+v; want 0, 11 and none",
+			layout.monthsY, layout.panelY, layout.decorations)
+	}
+
+	// Without the header and legend rows a single month fits four rows fewer
+	if !measureFocusLayout(MinWidth, MinHeight-4, 1).fits(MinWidth, MinHeight-4) {
+		t.Errorf("A %dx%d terminal should fit in focus mode", MinWidth, MinHeight-4)
+	}
+	if measureFocusLayout(MinWidth, MinHeight-5, 1).fits(MinWidth, MinHeight-5) {
+		t.Errorf("A %dx%d terminal should be too small even in focus mode", MinWidth, MinHeight-5)
+	}
+}
+
+func TestBannerText(t *testing.T) {
+	rows := BannerText("May 2025")
+	for i := range rows {
+		if len(rows[i]) != len(rows[0]) {
+			t.Errorf("Row %d has width %d, want %d", i, len(rows[i]), len(rows[0]))
+		}
+	}
+	if want := "|  | | |  |     |_  |_| |_   _|"; rows[2] != want {
+		t.Errorf("BannerText() bottom row = %q, want %q", rows[2], want)
+	}
+	if rows := BannerText("x?"); rows[0] != "" {
+		t.Errorf("BannerText() without glyphs = %q, want empty rows", rows)
+	}
+
+	if maxBannerWidth() > 80 {
+		t.Errorf("maxBannerWidth() = %d, should fit the minimum terminal width", maxBannerWidth())
+	}
+}
@@ -0,0 +1,142 @@
+package terminal
+
+import (
+	"time"
+
+	"go-ascii-calendar/annotations"
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/models"
+
+	"github.com/nsf/termbox-go"
+)
+
+// maxCachedMonths bounds the month cache; it is emptied when full
+const maxCachedMonths = 24
+
+// dayCell is the computed look of one cell of a month grid
+type dayCell struct {
+	text           string              // Day number, or blanks before and after the month
+	fg, bg         termbox.Attribute   // Colors of the day number
+	colors         []termbox.Attribute // Foreground of each character of a day mixing categories, nil otherwise
+	mark           string              // Annotation symbol or mixed-day marker after the day number
+	markFg, markBg termbox.Attribute
+	spanNext       bool              // An event spanning several days goes on to the next cell of the week
+	spanFg         termbox.Attribute // Color of the bar joining the cells of that event
+}
+
+// monthCacheKey holds everything the cells of a month grid depend on. The selection
+// and today only count when they fall in the month, so moving the selection within
+// one month leaves the other months cached.
+type monthCacheKey struct {
+	month      string
+	selected   string
+	marked     string // Marked date range, when it overlaps the month
+	today      string
+	events     uint64 // Event manager version
+	bookmarks  uint64 // Bookmark store version
+	generation uint64 // Renderer changes such as the theme and annotations
+	weekStart  int
+}
+
+// monthKey returns the cache key of a month grid for the current state
+func (r *Renderer) monthKey(month time.Time, selection *models.Selection) monthCacheKey {
+	inMonth := func(date time.Time) string {
+		if date.Year() != month.Year() || date.Month() != month.Month() {
+			return ""
+		}
+		return date.Format("2006-01-02")
+	}
+
+	key := monthCacheKey{
+		month:      month.Format("2006-01"),
+		selected:   inMonth(selection.SelectedDate),
+		today:      inMonth(r.today()),
+		events:     r.eventManager.Version(),
+		generation: r.cacheGeneration,
+		weekStart:  int(r.config.WeekStartDay),
+	}
+	if start, end := selection.Range(); selection.HasRange() &&
+		!end.Before(calendar.GetFirstDayOfMonth(month)) && !start.After(calendar.GetLastDayOfMonth(month)) {
+		key.marked = start.Format("2006-01-02") + ".." + end.Format("2006-01-02")
+	}
+	if r.bookmarks != nil {
+		key.bookmarks = r.bookmarks.Version()
+	}
+	return key
+}
+
+// monthCells returns the cells of a month grid, one row per week, computing them only
+// when an event, bookmark, the selection or today changed since they were cached
+func (r *Renderer) monthCells(month time.Time, selection *models.Selection) [][]dayCell {
+	key := r.monthKey(month, selection)
+	if cells, ok := r.monthCache[key]; ok {
+		return cells
+	}
+
+	cells := r.computeMonthCells(month, selection)
+	if r.monthCache == nil || len(r.monthCache) >= maxCachedMonths {
+		r.monthCache = make(map[monthCacheKey][][]dayCell)
+	}
+	r.monthCache[key] = cells
+	return cells
+}
+
+// invalidateMonthCache makes the next render compute all month grids again
+func (r *Renderer) invalidateMonthCache() {
+	r.cacheGeneration++
+}
+
+// computeMonthCells works out the text and colors of every cell of a month grid
+func (r *Renderer) computeMonthCells(month time.Time, selection *models.Selection) [][]dayCell {
+	fg, bg := r.style(StyleText)
+	weeks := calendar.GetCalendarWeeks(month, int(r.config.WeekStartDay))
+
+	cells := make([][]dayCell, len(weeks))
+	for weekIndex, week := range weeks {
+		cells[weekIndex] = make([]dayCell, len(week))
+		for dayIndex, dayNum := range week {
+			if dayNum == 0 {
+				// Empty cell
+				cells[weekIndex][dayIndex] = dayCell{text: "  ", fg: fg, bg: bg}
+				continue
+			}
+
+			// Determine display attributes
+			dayDate := time.Date(month.Year(), month.Month(), dayNum, 0, 0, 0, 0, month.Location())
+			cell := dayCell{}
+			cell.fg, cell.bg, cell.text = r.getDayAttributes(dayDate, selection)
+
+			// Days with events from several categories show both at a glance
+			var mixed bool
+			cell.text, cell.colors, mixed = r.mixedDayColors(dayDate, cell.text, cell.fg, selection)
+
+			// Mark annotated days in the gap after the day number
+			if notes := annotations.ForDate(r.annotations, dayDate); len(notes) > 0 {
+				cell.mark = firstRune(notes[0].Symbol)
+				cell.markFg, cell.markBg = r.annotationStyle(notes[0])
+			} else if mixed && !r.styles.color {
+				cell.mark, cell.markFg, cell.markBg = "+", cell.fg, cell.bg
+			}
+
+			// A bar joins the days of an event spanning several, up to the end of the week
+			if dayIndex+1 < len(week) && week[dayIndex+1] != 0 {
+				cell.spanFg, cell.spanNext = r.spanToNextDay(dayDate)
+			}
+			cells[weekIndex][dayIndex] = cell
+		}
+	}
+	return cells
+}
+
+// spanToNextDay reports whether an event spanning several days occurs on date and the
+// day after, returning the color of its bar
+func (r *Renderer) spanToNextDay(date time.Time) (termbox.Attribute, bool) {
+	next := date.AddDate(0, 0, 1)
+	for _, event := range r.eventManager.GetEventsForDate(date) {
+		if event.IsMultiDay() && event.OccursOn(next) {
+			fg, _ := r.style(StyleEventDay)
+			return r.categoryColor(event, fg), true
+		}
+	}
+	return 0, false
+}
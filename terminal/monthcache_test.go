@@ -0,0 +1,84 @@
+package terminal
+
+import (
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/events"
+	"go-ascii-calendar/models"
+)
+
+func TestRenderer_MonthCells(t *testing.T) {
+	manager := events.NewManagerWithConfig(&config.Config{Ephemeral: true})
+	if err := manager.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	renderer := NewRenderer(NewTerminal(), manager, config.DefaultConfig())
+
+	august := time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local)
+	september := time.Date(2025, 9, 1, 0, 0, 0, 0, time.Local)
+	selection := &models.Selection{SelectedDate: time.Date(2025, 8, 10, 0, 0, 0, 0, time.Local)}
+
+	first := renderer.monthCells(august, selection)
+	otherMonth := renderer.monthCells(september, selection)
+	if again := renderer.monthCells(august, selection); &again[0][0] != &first[0][0] {
+		t.Error("monthCells() should reuse the cached grid when nothing changed")
+	}
+
+	// Moving the selection within August recomputes August only
+	selection.SelectedDate = time.Date(2025, 8, 11, 0, 0, 0, 0, time.Local)
+	if moved := renderer.monthCells(august, selection); &moved[0][0] == &first[0][0] {
+		t.Error("monthCells() should recompute the month holding the selection")
+	}
+	if cached := renderer.monthCells(september, selection); &cached[0][0] != &otherMonth[0][0] {
+		t.Error("monthCells() should keep months the selection did not touch")
+	}
+
+	// A new event invalidates the grid and colors its day
+	if err := manager.AddEvent(time.Date(2025, 9, 3, 0, 0, 0, 0, time.Local), "09:00", "Dentist"); err != nil {
+		t.Fatalf("AddEvent() failed: %v", err)
+	}
+	updated := renderer.monthCells(september, selection)
+	if &updated[0][0] == &otherMonth[0][0] {
+		t.Fatal("monthCells() should recompute a month after an event was added")
+	}
+	eventFg, _ := renderer.style(StyleEventDay)
+	found := false
+	for _, week := range updated {
+		for _, cell := range week {
+			if cell.text == " 3" {
+				found = cell.fg == eventFg
+			}
+		}
+	}
+	if !found {
+		t.Error("The day of the new event should use the event day style")
+	}
+}
+
+func TestRenderer_MonthCells_MondayFirst(t *testing.T) {
+	manager := events.NewManagerWithConfig(&config.Config{Ephemeral: true})
+	cfg := config.DefaultConfig()
+	cfg.WeekStartDay = config.StartMonday
+	renderer := NewRenderer(NewTerminal(), manager, cfg)
+
+	// August 2025 starts on a Friday; the selected 17th is a Sunday
+	august := time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local)
+	selection := &models.Selection{SelectedDate: time.Date(2025, 8, 17, 0, 0, 0, 0, time.Local)}
+	cells := renderer.monthCells(august, selection)
+
+	if len(cells) != 5 || cells[0][4].text != " 1" || cells[0][3].text != "  " {
+		t.Fatalf("First week = %+v, want the 1st in the fifth column of five weeks", cells[0])
+	}
+	selectedFg, selectedBg := renderer.style(StyleSelected)
+	if cell := cells[2][6]; cell.text != "17" || cell.fg != selectedFg || cell.bg != selectedBg {
+		t.Errorf("Last cell of the third week = %+v, want the selected Sunday 17th", cell)
+	}
+
+	// Switching back to Sunday-first must not reuse the Monday-first grid
+	cfg.WeekStartDay = config.StartSunday
+	if cell := renderer.monthCells(august, selection)[3][0]; cell.text != "17" {
+		t.Errorf("First cell of the fourth Sunday-first week = %+v, want the 17th", cell)
+	}
+}
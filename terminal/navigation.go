@@ -45,69 +45,83 @@ func (nc *NavigationController) NavigateMonthForward() {
 	nc.adjustSelectionForMonthChange(selectedDay)
 }
 
-// NavigateDayLeft moves selection one day to the left (H key)
-func (nc *NavigationController) NavigateDayLeft() {
+// NavigateDayLeft moves selection one day to the left (H key).
+// It returns false when the move is blocked at the start of the visible range.
+func (nc *NavigationController) NavigateDayLeft() bool {
 	newDate := nc.selection.SelectedDate.AddDate(0, 0, -1)
 
 	// Check if the new date is within the visible three-month range
 	if nc.isDateInVisibleRange(newDate) {
 		nc.selection.SelectedDate = newDate
-	} else {
-		// Move to the previous month if we're at the beginning of a month
-		if nc.selection.SelectedDate.Day() == 1 {
-			// Try to move to the last day of the previous month if it's visible
-			prevMonth := nc.selection.SelectedDate.AddDate(0, -1, 0)
-			lastDayOfPrevMonth := calendar.GetLastDayOfMonth(prevMonth)
-
-			if nc.isDateInVisibleRange(lastDayOfPrevMonth) {
-				nc.selection.SelectedDate = lastDayOfPrevMonth
-			}
+		return true
+	}
+
+	// Move to the previous month if we're at the beginning of a month
+	if nc.selection.SelectedDate.Day() == 1 {
+		// Try to move to the last day of the previous month if it's visible
+		prevMonth := nc.selection.SelectedDate.AddDate(0, -1, 0)
+		lastDayOfPrevMonth := calendar.GetLastDayOfMonth(prevMonth)
+
+		if nc.isDateInVisibleRange(lastDayOfPrevMonth) {
+			nc.selection.SelectedDate = lastDayOfPrevMonth
+			return true
 		}
 	}
+	return false
 }
 
-// NavigateDayRight moves selection one day to the right (L key)
-func (nc *NavigationController) NavigateDayRight() {
+// NavigateDayRight moves selection one day to the right (L key).
+// It returns false when the move is blocked at the end of the visible range.
+func (nc *NavigationController) NavigateDayRight() bool {
 	newDate := nc.selection.SelectedDate.AddDate(0, 0, 1)
 
 	// Check if the new date is within the visible three-month range
 	if nc.isDateInVisibleRange(newDate) {
 		nc.selection.SelectedDate = newDate
-	} else {
-		// Move to the next month if we're at the end of a month
-		daysInCurrentMonth := calendar.GetDaysInMonth(nc.selection.SelectedDate)
-		if nc.selection.SelectedDate.Day() == daysInCurrentMonth {
-			// Try to move to the first day of the next month if it's visible
-			nextMonth := nc.selection.SelectedDate.AddDate(0, 1, 0)
-			firstDayOfNextMonth := time.Date(nextMonth.Year(), nextMonth.Month(), 1, 0, 0, 0, 0, nextMonth.Location())
-
-			if nc.isDateInVisibleRange(firstDayOfNextMonth) {
-				nc.selection.SelectedDate = firstDayOfNextMonth
-			}
+		return true
+	}
+
+	// Move to the next month if we're at the end of a month
+	daysInCurrentMonth := calendar.GetDaysInMonth(nc.selection.SelectedDate)
+	if nc.selection.SelectedDate.Day() == daysInCurrentMonth {
+		// Try to move to the first day of the next month if it's visible
+		nextMonth := nc.selection.SelectedDate.AddDate(0, 1, 0)
+		firstDayOfNextMonth := time.Date(nextMonth.Year(), nextMonth.Month(), 1, 0, 0, 0, 0, nextMonth.Location())
+
+		if nc.isDateInVisibleRange(firstDayOfNextMonth) {
+			nc.selection.SelectedDate = firstDayOfNextMonth
+			return true
 		}
 	}
+	return false
 }
 
-// NavigateDayUp moves selection one week up (K key)
-func (nc *NavigationController) NavigateDayUp() {
+// NavigateDayUp moves selection one week up (K key).
+// It returns false when the move is blocked at the edge of the visible range.
+func (nc *NavigationController) NavigateDayUp() bool {
 	newDate := nc.selection.SelectedDate.AddDate(0, 0, -7)
 
 	// Check if the new date is within the visible three-month range
 	if nc.isDateInVisibleRange(newDate) {
 		nc.selection.SelectedDate = newDate
+		return true
 	}
 	// If not in range, keep the current selection (boundary constraint)
+	return false
 }
 
-// NavigateDayDown moves selection one week down (J key)
-func (nc *NavigationController) NavigateDayDown() {
+// NavigateDayDown moves selection one week down (J key).
+// It returns false when the move is blocked at the edge of the visible range.
+func (nc *NavigationController) NavigateDayDown() bool {
 	newDate := nc.selection.SelectedDate.AddDate(0, 0, 7)
 
 	// Check if the new date is within the visible three-month range
 	if nc.isDateInVisibleRange(newDate) {
 		nc.selection.SelectedDate = newDate
+		return true
 	}
 	// If not in range, keep the current selection (boundary constraint)
+	return false
 }
 
 // adjustSelectionForMonthChange adjusts selection when the month window changes
@@ -190,3 +204,9 @@ func (nc *NavigationController) ResetToCurrent() {
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	nc.selection.SelectedDate = today
 }
+
+// JumpToDate centers the calendar on the month of date and selects it
+func (nc *NavigationController) JumpToDate(date time.Time) {
+	nc.calendar.CurrentMonth = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	nc.selection.SelectedDate = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
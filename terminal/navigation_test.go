@@ -228,6 +228,34 @@ func TestNavigateDayDown(t *testing.T) {
 	}
 }
 
+func TestNavigateDay_BlockedAtBoundary(t *testing.T) {
+	cal := models.NewCalendar()
+	cal.CurrentMonth = time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	sel := models.NewSelection(cal)
+	nc := NewNavigationController(cal, sel)
+
+	// July 1 is the first visible day
+	sel.SelectedDate = time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	if nc.NavigateDayLeft() {
+		t.Error("NavigateDayLeft() should report a blocked move at the first visible day")
+	}
+	if nc.NavigateDayUp() {
+		t.Error("NavigateDayUp() should report a blocked move in the first visible week")
+	}
+	if !nc.NavigateDayRight() {
+		t.Error("NavigateDayRight() should move away from the first visible day")
+	}
+
+	// September 30 is the last visible day
+	sel.SelectedDate = time.Date(2025, time.September, 30, 0, 0, 0, 0, time.UTC)
+	if nc.NavigateDayRight() || nc.NavigateDayDown() {
+		t.Error("Moves past the last visible day should be reported as blocked")
+	}
+	if !sel.SelectedDate.Equal(time.Date(2025, time.September, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Blocked moves should keep the selection, got %v", sel.SelectedDate)
+	}
+}
+
 func TestIsDateInVisibleRange(t *testing.T) {
 	cal := models.NewCalendar()
 	// Set calendar to August 2025 (shows July, August, September)
@@ -360,3 +388,22 @@ func TestGetVisibleDateRange(t *testing.T) {
 		t.Errorf("Expected end date %v, got %v", expectedEnd, end)
 	}
 }
+
+func TestJumpToDate(t *testing.T) {
+	cal := models.NewCalendar()
+	cal.CurrentMonth = time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	sel := models.NewSelection(cal)
+	nc := NewNavigationController(cal, sel)
+
+	// Dates outside the visible range move the calendar as well
+	target := time.Date(2026, time.March, 9, 0, 0, 0, 0, time.UTC)
+	nc.JumpToDate(target)
+
+	if !cal.CurrentMonth.Equal(time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected current month March 2026, got %v", cal.CurrentMonth)
+	}
+	if !nc.GetCurrentSelection().Equal(target) {
+		t.Errorf("Expected selected date %v, got %v", target, nc.GetCurrentSelection())
+	}
+}
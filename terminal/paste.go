@@ -0,0 +1,153 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+
+	"go-ascii-calendar/events"
+
+	"github.com/nsf/termbox-go"
+)
+
+// maxPasteLength bounds the text of the paste box
+const maxPasteLength = 20000
+
+// GetPasteInput collects text pasted or typed into the paste box, Enter starting a new
+// line. Ctrl+D finishes the text; Esc cancels.
+func (ih *InputHandler) GetPasteInput(renderer *Renderer) (string, bool) {
+	return ih.getTextBoxInput("", func(input string) { renderer.RenderPasteInput(input) })
+}
+
+// getTextBoxInput edits text of several lines starting from text, drawing it with
+// render after each key. Enter starts a new line, Ctrl+D finishes the text and Esc
+// cancels.
+func (ih *InputHandler) getTextBoxInput(text string, render func(input string)) (string, bool) {
+	input := []rune(text)
+	for {
+		render(string(input))
+
+		event := ih.terminal.PollEvent()
+		if event.Type != termbox.EventKey {
+			continue
+		}
+
+		switch event.Key {
+		case termbox.KeyEsc:
+			return "", false
+		case termbox.KeyCtrlD:
+			return string(input), true
+		case termbox.KeyEnter:
+			input = append(input, '\n')
+		case termbox.KeySpace, termbox.KeyTab:
+			input = append(input, ' ')
+		case termbox.KeyBackspace, termbox.KeyBackspace2:
+			if len(input) > 0 {
+				input = input[:len(input)-1]
+			}
+		default:
+			if event.Ch >= 32 {
+				input = append(input, event.Ch)
+			}
+		}
+		if len(input) > maxPasteLength {
+			input = input[:maxPasteLength]
+		}
+	}
+}
+
+// RenderPasteInput renders the paste box with the text entered so far
+func (r *Renderer) RenderPasteInput(input string) error {
+	return r.renderTextBox("Paste events, one per line, e.g. \"next fri 18:00 Dinner\"", input, "Ctrl+D: preview events  Esc: cancel")
+}
+
+// renderTextBox renders a box of text being typed under title, wrapping lines longer
+// than the box and showing the last lines when they do not fit, with the line count
+// and legend below it
+func (r *Renderer) renderTextBox(title, input, legend string) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	_, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+	inputFg, inputBg := r.style(StyleInput)
+
+	r.terminal.PrintCentered(2, fitText(title, width), titleFg, bg)
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	count := strings.Count(input, "\n") + 1
+	lines := wrapText(input+"_", max(1, width-4))
+	startY := 6
+	rows := max(1, height-4-startY)
+	for i := max(0, len(lines)-rows); i < len(lines); i++ {
+		y := startY + i - max(0, len(lines)-rows)
+		r.terminal.FillRect(2, y, max(0, width-4), 1, ' ', inputFg, inputBg)
+		r.terminal.Print(2, y, lines[i], inputFg, inputBg)
+	}
+
+	r.terminal.PrintCentered(height-3, fmt.Sprintf("%d lines  %s", count, legend), instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// RenderPastePreview lists the events read from pasted lines with whether each is
+// added, and why lines that cannot be added were rejected
+func (r *Renderer) RenderPastePreview(lines []events.PasteLine, selectedIndex int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+	dateFg, _ := r.style(StyleEventTime)
+	errorFg, _ := r.style(StyleError)
+	mutedFg, _ := r.style(StyleMuted)
+
+	accepted := 0
+	for _, line := range lines {
+		if line.Accepted {
+			accepted++
+		}
+	}
+	r.terminal.PrintCentered(2, fmt.Sprintf("Paste events: %d of %d lines accepted", accepted, len(lines)), titleFg, bg)
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	startY := 6
+	rows := max(1, height-4-startY)
+	offset := scrollOffset(len(lines), rows, selectedIndex)
+	for i := offset; i < len(lines) && i-offset < rows; i++ {
+		line := lines[i]
+		y := startY + i - offset
+
+		lineDateFg, lineFg, lineBg := dateFg, fg, bg
+		if !line.Accepted {
+			lineDateFg, lineFg = mutedFg, mutedFg
+		}
+		if i == selectedIndex {
+			lineFg, lineBg = r.style(StyleSelectedEvent)
+			lineDateFg = lineFg
+		}
+
+		mark := "[ ]"
+		if line.Accepted {
+			mark = "[x]"
+		}
+		if line.Err != nil {
+			r.terminal.Print(2, y, mark+" "+line.Text, lineFg, lineBg)
+			r.terminal.Print(min(width-1, 6+len([]rune(line.Text))), y, "  ! "+line.Err.Error(), errorFg, lineBg)
+			continue
+		}
+		event := line.Event
+		when := fmt.Sprintf("%s %s %s", mark, event.Date.Format("Mon ")+r.formatDate(event.Date), event.GetTimeString())
+		r.terminal.Print(2, y, when, lineDateFg, lineBg)
+		r.terminal.Print(3+len([]rune(when)), y, event.Description, lineFg, lineBg)
+	}
+
+	r.terminal.PrintCentered(height-3, "J/K: navigate  X: accept/reject  Enter: add accepted events  Esc: cancel", instrFg, bg)
+
+	return r.terminal.Flush()
+}
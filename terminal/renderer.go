@@ -2,12 +2,23 @@ package terminal
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"go-ascii-calendar/annotations"
+	"go-ascii-calendar/banner"
 	"go-ascii-calendar/calendar"
 	"go-ascii-calendar/config"
 	"go-ascii-calendar/events"
+	"go-ascii-calendar/holidays"
+	"go-ascii-calendar/messages"
 	"go-ascii-calendar/models"
+	"go-ascii-calendar/state"
+	"go-ascii-calendar/stats"
+	"go-ascii-calendar/storage"
 
 	"github.com/nsf/termbox-go"
 )
@@ -17,71 +28,373 @@ type Renderer struct {
 	terminal     *Terminal
 	eventManager *events.Manager
 	config       *config.Config
-	monthWidth   int // Width of each month display
-	monthSpacing int // Spacing between months
+	bookmarks    *state.Store
+	status       func() string // Background status shown in the status bar, e.g. sync state
+	styles       *StyleResolver
+	annotations  []annotations.Provider
+	holidays     *holidays.Calendar
+	inputError   string           // Error shown below the inline input line, e.g. a rejected field
+	inputHint    string           // Text shown below the inline input line, e.g. the date it resolves to
+	freeEvenings bool             // Highlight days with a free evening, see hasFreeEvening
+	focus        bool             // Focus mode: only the month grids and the events panel, see SetFocus
+	now          func() time.Time // Clock deciding which day is today; fixed in tests
+
+	// Events scrolled past at the top of the panel below the calendar with [ and ],
+	// kept while paneDate stays selected
+	paneDate   time.Time
+	paneOffset int
+
+	// Computed month grids, see monthCells
+	monthCache      map[monthCacheKey][][]dayCell
+	cacheGeneration uint64
 }
 
 // NewRenderer creates a new calendar renderer
 func NewRenderer(terminal *Terminal, eventManager *events.Manager, cfg *config.Config) *Renderer {
+	theme := config.DefaultTheme
+	if cfg != nil {
+		theme = cfg.CurrentTheme()
+	}
+
 	return &Renderer{
 		terminal:     terminal,
 		eventManager: eventManager,
 		config:       cfg,
-		monthWidth:   24, // Width for each month (includes padding)
-		monthSpacing: 2,  // Space between months
+		styles:       NewStyleResolver(theme, terminal.IsColorSupported()),
+		focus:        cfg != nil && cfg.FocusMode,
+		now:          time.Now,
+	}
+}
+
+// today returns the current day, highlighted in whichever month grid holds it
+func (r *Renderer) today() time.Time {
+	return calendar.NormalizeDate(r.now())
+}
+
+// countdownText tells how far date is from today, e.g. "in 12 days" or "3 days ago"
+func (r *Renderer) countdownText(date time.Time) string {
+	switch days := calendar.DaysBetween(r.today(), date); {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	case days == -1:
+		return "yesterday"
+	case days > 1:
+		return fmt.Sprintf("in %d days", days)
+	default:
+		return fmt.Sprintf("%d days ago", -days)
+	}
+}
+
+// SetTheme switches the color theme at runtime; the next render uses the new styles
+func (r *Renderer) SetTheme(theme config.ColorTheme) {
+	r.styles.SetTheme(theme)
+	r.invalidateMonthCache()
+}
+
+// Styles returns the resolver mapping semantic style names to attributes
+func (r *Renderer) Styles() *StyleResolver {
+	return r.styles
+}
+
+// style returns the foreground and background attributes of a semantic style
+func (r *Renderer) style(name StyleName) (termbox.Attribute, termbox.Attribute) {
+	style := r.styles.Style(name)
+	return style.Fg, style.Bg
+}
+
+// SetBookmarks sets the store used to mark bookmarked days
+func (r *Renderer) SetBookmarks(store *state.Store) {
+	r.bookmarks = store
+	r.invalidateMonthCache()
+}
+
+// SetAnnotations sets the providers of the notes marked next to day numbers
+func (r *Renderer) SetAnnotations(providers []annotations.Provider) {
+	r.annotations = providers
+	r.invalidateMonthCache()
+}
+
+// SetHolidays sets the public holidays shown in the holiday color
+func (r *Renderer) SetHolidays(calendar *holidays.Calendar) {
+	r.holidays = calendar
+	r.invalidateMonthCache()
+}
+
+// SetStatus sets the provider of the status bar text shown below the key legend
+func (r *Renderer) SetStatus(status func() string) {
+	r.status = status
+}
+
+// bookmarkFor returns the bookmark on a date, if bookmarks are available
+func (r *Renderer) bookmarkFor(date time.Time) (state.Bookmark, bool) {
+	if r.bookmarks == nil {
+		return state.Bookmark{}, false
+	}
+	return r.bookmarks.BookmarkFor(date)
+}
+
+// eventsPanelStartY returns the row of the selected-date events panel header,
+// below the calendar and its decorations
+func (r *Renderer) eventsPanelStartY() int {
+	return r.layout().panelY
+}
+
+// EventsHeaderY returns the row of the events panel header
+func (r *Renderer) EventsHeaderY() int {
+	return r.eventsPanelStartY()
+}
+
+// EventsLeftX returns the column of the events panel text, aligned with the
+// calendar's leftmost day column
+func (r *Renderer) EventsLeftX() int {
+	width, _ := r.terminal.GetSize()
+	return (width-r.layout().totalWidth())/2 + 1
+}
+
+// eventsPanelRows returns the number of rows between the panel header and the key legend
+func (r *Renderer) eventsPanelRows() int {
+	_, height := r.terminal.GetSize()
+	// The legend occupies height-2 and the status message height-1; focus mode has
+	// neither
+	rows := height - 3 - r.eventsPanelStartY()
+	if r.focus {
+		rows = height - 1 - r.eventsPanelStartY()
+	}
+	if rows < 0 {
+		return 0
+	}
+	return rows
+}
+
+// visibleEventCount returns how many of totalEvents fit in the selected-date panel.
+// reservedRows are taken by other panel content (such as the new-event row).
+// When not all events fit, one row is kept free for the "... and X more" line.
+func (r *Renderer) visibleEventCount(totalEvents, reservedRows int) int {
+	available := r.eventsPanelRows() - reservedRows
+	if available <= 0 {
+		return 0
+	}
+
+	limit := available
+	if r.config != nil && r.config.MaxEventsPerDay > 0 && r.config.MaxEventsPerDay < limit {
+		limit = r.config.MaxEventsPerDay
+	}
+
+	if totalEvents <= limit {
+		return totalEvents
+	}
+
+	// Some events are hidden: make sure the "more" line has a row of its own
+	if limit+1 > available {
+		limit = available - 1
+	}
+	return limit
+}
+
+// NewEventRowY returns the row of the highlighted "[New Event]" line in add mode
+func (r *Renderer) NewEventRowY(selectedDate time.Time) int {
+	events := r.eventManager.GetEventsForDate(selectedDate)
+	return r.eventsPanelStartY() + 1 + r.visibleEventCount(len(events), 1)
+}
+
+// scrollOffset returns the index of the first listed item so that selectedIndex
+// stays within a window of visible rows
+func scrollOffset(total, visible, selectedIndex int) int {
+	if visible <= 0 || total <= visible || selectedIndex < visible {
+		return 0
+	}
+	if selectedIndex >= total {
+		selectedIndex = total - 1
+	}
+	return selectedIndex - visible + 1
+}
+
+// ScrollEvents scrolls the events panel below the calendar by delta events while date
+// is selected, reporting whether there were events to scroll to in that direction.
+// Selecting another date starts its events from the top again.
+func (r *Renderer) ScrollEvents(date time.Time, delta int) bool {
+	total := len(r.eventManager.GetEventsForDate(date))
+	visible := r.visibleEventCount(total, 0)
+	offset := r.paneScroll(date, total, visible)
+	scrolled := min(max(offset+delta, 0), total-visible)
+	if scrolled == offset {
+		return false
+	}
+	r.paneDate, r.paneOffset = calendar.NormalizeDate(date), scrolled
+	return true
+}
+
+// paneScroll returns the events scrolled past in the panel below the calendar for
+// date, keeping visible of total events in view
+func (r *Renderer) paneScroll(date time.Time, total, visible int) int {
+	if !calendar.NormalizeDate(date).Equal(r.paneDate) {
+		return 0
+	}
+	return min(max(r.paneOffset, 0), max(total-visible, 0))
+}
+
+// moreEventsText tells how many events of a list are scrolled out of view above and
+// below it, e.g. "... and 3 more events" or "... 2 above, 3 more below"
+func moreEventsText(above, below int) string {
+	switch {
+	case above == 0:
+		return fmt.Sprintf("... and %d more events", below)
+	case below == 0:
+		return fmt.Sprintf("... %d more events above", above)
+	default:
+		return fmt.Sprintf("... %d above, %d more below", above, below)
+	}
+}
+
+// eventListStartY is the row of the first event in the full-screen event list
+const eventListStartY = 6
+
+// Section headings of the full-screen event list, in place of an event index
+const (
+	allDayHeadingRow = -1
+	timedHeadingRow  = -2
+)
+
+// eventListRows returns what each row of the full-screen event list shows: the index
+// of an event or a section heading. All-day events, which sort first, get a section
+// of their own when a day has any.
+func eventListRows(events []models.Event) []int {
+	var rows []int
+	for i, event := range events {
+		switch {
+		case i == 0 && event.AllDay:
+			rows = append(rows, allDayHeadingRow)
+		case i > 0 && events[i-1].AllDay && !event.AllDay:
+			rows = append(rows, timedHeadingRow)
+		}
+		rows = append(rows, i)
+	}
+	return rows
+}
+
+// eventListRow returns the row of the event at index among rows
+func eventListRow(rows []int, index int) int {
+	for row, i := range rows {
+		if i == index {
+			return row
+		}
 	}
+	return index
 }
 
-// getThemeColor safely parses a theme color with fallback to default
-func (r *Renderer) getThemeColor(colorStr string, fallback termbox.Attribute) termbox.Attribute {
+// EventListRowY returns the row of the selected event in the full-screen event list,
+// accounting for section headings and the list scrolling to keep it visible
+func (r *Renderer) EventListRowY(events []models.Event, selectedIndex int) int {
+	_, height := r.terminal.GetSize()
+	rows := eventListRows(events)
+	selectedRow := eventListRow(rows, selectedIndex)
+	return eventListStartY + selectedRow - scrollOffset(len(rows), height-4-eventListStartY, selectedRow)
+}
+
+// CalendarEventRowY returns the row of the selected event in the calendar events panel,
+// accounting for the panel scrolling to keep it visible
+func (r *Renderer) CalendarEventRowY(selectedDate time.Time, selectedIndex int) int {
+	events := r.eventManager.GetEventsForDate(selectedDate)
+	maxEvents := r.visibleEventCount(len(events), 0)
+	return r.eventsPanelStartY() + 1 + selectedIndex - scrollOffset(len(events), maxEvents, selectedIndex)
+}
+
+// formatDate formats a date with the configured date format
+func (r *Renderer) formatDate(date time.Time) string {
 	if r.config == nil {
-		return fallback
+		return calendar.FormatDate(date)
+	}
+	return calendar.FormatLocalDate(date, r.config.DateFormat)
+}
+
+// eventTimeLabel returns the time of an event with its end, e.g. "14:00-15:30", or
+// "all day"
+func eventTimeLabel(event models.Event) string {
+	label := event.GetTimeLabel()
+	if end := event.GetEndTimeString(); end != "" && !event.AllDay {
+		label += "-" + end
 	}
+	return label
+}
 
-	color, err := config.ParseColor(colorStr)
-	if err != nil {
-		return fallback
+// overlappingEvents reports for each of the events of a date whether it overlaps
+// another one
+func overlappingEvents(list []models.Event) []bool {
+	return events.Overlapping(list)
+}
+
+// overlapDescription returns the description of an event, marked when it overlaps
+// another event of its date
+func (r *Renderer) overlapDescription(event models.Event, overlaps bool) string {
+	if overlaps {
+		return r.eventDescription(event) + " (overlaps)"
 	}
+	return r.eventDescription(event)
+}
 
-	return color
+// eventDescription returns the event description prefixed with its follow-up flag,
+// priority and category tag, followed by the days of an event spanning several, its
+// reminder and a ¶ when it has notes
+func (r *Renderer) eventDescription(event models.Event) string {
+	description := event.Description
+	if event.IsMultiDay() {
+		description = fmt.Sprintf("%s (%s - %s)", description, event.Date.Format("Jan 2"), event.LastDate().Format("Jan 2"))
+	}
+	if reminder := event.GetReminderLabel(); reminder != "" {
+		description += " @" + reminder
+	}
+	if event.Notes != "" {
+		description += " ¶"
+	}
+	if event.Category != "" {
+		description = fmt.Sprintf("[%s] %s", event.Category, description)
+	}
+	if event.Priority != "" {
+		description = fmt.Sprintf("(%s) %s", event.Priority, description)
+	}
+	if event.Flagged {
+		description = "! " + description
+	}
+	return description
 }
 
-// getThemeColors returns foreground and background colors from theme strings
-func (r *Renderer) getThemeColors(fgStr, bgStr string, defaultFg, defaultBg termbox.Attribute) (termbox.Attribute, termbox.Attribute) {
-	fg := r.getThemeColor(fgStr, defaultFg)
-	bg := r.getThemeColor(bgStr, defaultBg)
-	return fg, bg
+// categoryColor returns the configured color of the event's category, or else of its
+// calendar, or fallback
+func (r *Renderer) categoryColor(event models.Event, fallback termbox.Attribute) termbox.Attribute {
+	if r.config == nil {
+		return fallback
+	}
+	if category, ok := r.config.GetCategory(event.Category); ok && event.Category != "" {
+		return r.styles.Color(category.Color, fallback)
+	}
+	if calendar, ok := r.config.GetCalendar(event.Calendar); ok && calendar.Color != "" {
+		return r.styles.Color(calendar.Color, fallback)
+	}
+	return fallback
 }
 
 // RenderCalendar renders the three-month calendar view
 func (r *Renderer) RenderCalendar(cal *models.Calendar, selection *models.Selection) error {
 	r.terminal.Clear()
 
-	// Get terminal size
-	width, height := r.terminal.GetSize()
-	if width < 80 || height < 24 {
-		r.terminal.PrintCentered(height/2, "Terminal too small! Minimum 80x24 required.",
-			termbox.ColorRed, termbox.ColorDefault)
-		return r.terminal.Flush()
+	if r.tooSmall() {
+		return r.renderTooSmall()
+	}
+	if err := r.renderCalendarView(cal, selection); err != nil {
+		return err
 	}
 
-	// Calculate starting positions for three months
-	totalWidth := 3*r.monthWidth + 2*r.monthSpacing
-	startX := (width - totalWidth) / 2
-
-	prevMonth := cal.GetPreviousMonth()
-	currentMonth := cal.CurrentMonth
-	nextMonth := cal.GetNextMonth()
-
-	months := []time.Time{prevMonth, currentMonth, nextMonth}
+	return r.terminal.Flush()
+}
 
-	// Render each month
-	for i, month := range months {
-		x := startX + i*(r.monthWidth+r.monthSpacing)
-		err := r.renderMonth(month, x, 2, selection)
-		if err != nil {
-			return err
-		}
+// renderCalendarView draws the calendar view without flushing, so overlays can be
+// drawn above it
+func (r *Renderer) renderCalendarView(cal *models.Calendar, selection *models.Selection) error {
+	// Render the three months with the decorations that fit
+	if err := r.renderMonths(cal, selection); err != nil {
+		return err
 	}
 
 	// Render events for selected date
@@ -90,38 +403,20 @@ func (r *Renderer) RenderCalendar(cal *models.Calendar, selection *models.Select
 	// Render key legend
 	r.renderKeyLegend()
 
-	return r.terminal.Flush()
+	return nil
 }
 
 // RenderCalendarWithEventSelection renders the calendar with event selection highlighting
 func (r *Renderer) RenderCalendarWithEventSelection(cal *models.Calendar, selection *models.Selection, selectedEventIndex int) error {
 	r.terminal.Clear()
 
-	// Get terminal size
-	width, height := r.terminal.GetSize()
-	if width < 80 || height < 24 {
-		r.terminal.PrintCentered(height/2, "Terminal too small! Minimum 80x24 required.",
-			termbox.ColorRed, termbox.ColorDefault)
-		return r.terminal.Flush()
+	if r.tooSmall() {
+		return r.renderTooSmall()
 	}
 
-	// Calculate starting positions for three months
-	totalWidth := 3*r.monthWidth + 2*r.monthSpacing
-	startX := (width - totalWidth) / 2
-
-	prevMonth := cal.GetPreviousMonth()
-	currentMonth := cal.CurrentMonth
-	nextMonth := cal.GetNextMonth()
-
-	months := []time.Time{prevMonth, currentMonth, nextMonth}
-
-	// Render each month
-	for i, month := range months {
-		x := startX + i*(r.monthWidth+r.monthSpacing)
-		err := r.renderMonth(month, x, 2, selection)
-		if err != nil {
-			return err
-		}
+	// Render the three months with the decorations that fit
+	if err := r.renderMonths(cal, selection); err != nil {
+		return err
 	}
 
 	// Render events for selected date with selection highlighting
@@ -137,31 +432,13 @@ func (r *Renderer) RenderCalendarWithEventSelection(cal *models.Calendar, select
 func (r *Renderer) RenderCalendarWithEventAdd(cal *models.Calendar, selection *models.Selection) error {
 	r.terminal.Clear()
 
-	// Get terminal size
-	width, height := r.terminal.GetSize()
-	if width < 80 || height < 24 {
-		r.terminal.PrintCentered(height/2, "Terminal too small! Minimum 80x24 required.",
-			termbox.ColorRed, termbox.ColorDefault)
-		return r.terminal.Flush()
+	if r.tooSmall() {
+		return r.renderTooSmall()
 	}
 
-	// Calculate starting positions for three months
-	totalWidth := 3*r.monthWidth + 2*r.monthSpacing
-	startX := (width - totalWidth) / 2
-
-	prevMonth := cal.GetPreviousMonth()
-	currentMonth := cal.CurrentMonth
-	nextMonth := cal.GetNextMonth()
-
-	months := []time.Time{prevMonth, currentMonth, nextMonth}
-
-	// Render each month
-	for i, month := range months {
-		x := startX + i*(r.monthWidth+r.monthSpacing)
-		err := r.renderMonth(month, x, 2, selection)
-		if err != nil {
-			return err
-		}
+	// Render the three months with the decorations that fit
+	if err := r.renderMonths(cal, selection); err != nil {
+		return err
 	}
 
 	// Render events for selected date with add mode highlighting
@@ -177,31 +454,13 @@ func (r *Renderer) RenderCalendarWithEventAdd(cal *models.Calendar, selection *m
 func (r *Renderer) RenderCalendarWithEventEdit(cal *models.Calendar, selection *models.Selection, selectedEventIndex int) error {
 	r.terminal.Clear()
 
-	// Get terminal size
-	width, height := r.terminal.GetSize()
-	if width < 80 || height < 24 {
-		r.terminal.PrintCentered(height/2, "Terminal too small! Minimum 80x24 required.",
-			termbox.ColorRed, termbox.ColorDefault)
-		return r.terminal.Flush()
+	if r.tooSmall() {
+		return r.renderTooSmall()
 	}
 
-	// Calculate starting positions for three months
-	totalWidth := 3*r.monthWidth + 2*r.monthSpacing
-	startX := (width - totalWidth) / 2
-
-	prevMonth := cal.GetPreviousMonth()
-	currentMonth := cal.CurrentMonth
-	nextMonth := cal.GetNextMonth()
-
-	months := []time.Time{prevMonth, currentMonth, nextMonth}
-
-	// Render each month
-	for i, month := range months {
-		x := startX + i*(r.monthWidth+r.monthSpacing)
-		err := r.renderMonth(month, x, 2, selection)
-		if err != nil {
-			return err
-		}
+	// Render the three months with the decorations that fit
+	if err := r.renderMonths(cal, selection); err != nil {
+		return err
 	}
 
 	// Render events for selected date with edit mode highlighting
@@ -214,78 +473,58 @@ func (r *Renderer) RenderCalendarWithEventEdit(cal *models.Calendar, selection *
 }
 
 // renderMonth renders a single month at the specified position
-func (r *Renderer) renderMonth(month time.Time, x, y int, selection *models.Selection) error {
-	fg, bg := r.terminal.GetDefaultColors()
-
-	// Render month header (month name and year) with color
-	monthHeader := fmt.Sprintf("%s %d", calendar.GetMonthName(month), month.Year())
-	headerX := x + (r.monthWidth-len(monthHeader))/2
-
-	var headerFg, headerBg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		// Use theme colors for month headers in color terminals
-		headerFg, headerBg = r.getThemeColors(
-			r.config.UITheme.MonthHeaderFg,
-			r.config.UITheme.MonthHeaderBg,
-			termbox.ColorMagenta|termbox.AttrBold,
-			termbox.ColorDefault,
-		)
-	} else {
-		headerFg = termbox.AttrBold
-		headerBg = termbox.ColorDefault
-	}
+// spanBar fills the gaps between the day cells of an event spanning several days
+const spanBar = '='
+
+func (r *Renderer) renderMonth(layout calendarLayout, month time.Time, x, y int, selection *models.Selection) error {
+	fg, bg := r.style(StyleText)
+
+	// Render month header (month name and year)
+	monthHeader := fmt.Sprintf("%s %d", calendar.GetLocalizedMonthName(r.config.MonthNames, month), month.Year())
+	headerX := x + (layout.monthWidth-utf8.RuneCountInString(monthHeader))/2
+
+	headerFg, headerBg := r.style(r.monthHeaderStyle(month, selection))
 	r.terminal.Print(headerX, y, monthHeader, headerFg, headerBg)
 
-	// Render day-of-week headers with color
-	dayHeaders := calendar.GetDayOfWeekHeaders(int(r.config.WeekStartDay))
+	// Render day-of-week headers
+	dayHeaders := calendar.GetLocalizedDayOfWeekHeaders(r.config.WeekdayNames, int(r.config.WeekStartDay))
 	headerY := y + 2
 
-	var dayHeaderFg, dayHeaderBg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		// Use theme colors for day-of-week headers in color terminals
-		dayHeaderFg, dayHeaderBg = r.getThemeColors(
-			r.config.UITheme.DayHeaderFg,
-			r.config.UITheme.DayHeaderBg,
-			termbox.ColorCyan,
-			termbox.ColorDefault,
-		)
-	} else {
-		dayHeaderFg = fg
-		dayHeaderBg = bg
-	}
+	dayHeaderFg, dayHeaderBg := r.style(StyleDayHeader)
 
 	for i, header := range dayHeaders {
-		headerX := x + i*3 + 1
-		r.terminal.Print(headerX, headerY, header, dayHeaderFg, dayHeaderBg)
+		r.terminal.Print(layout.dayX(x, i), headerY, header, dayHeaderFg, dayHeaderBg)
 	}
 
 	// Render separator line
 	separatorY := headerY + 1
-	for i := 0; i < r.monthWidth-2; i++ {
+	for i := 0; i < layout.monthWidth-2; i++ {
 		r.terminal.SetCell(x+1+i, separatorY, '-', fg, bg)
 	}
 
-	// Get calendar weeks for this month
-	weeks := calendar.GetCalendarWeeks(month, int(r.config.WeekStartDay))
-
-	// Render day grid
+	// Render day grid from the cached cells of this month
 	startY := separatorY + 1
-	for weekIndex, week := range weeks {
-		weekY := startY + weekIndex
-		for dayIndex, dayNum := range week {
-			dayX := x + dayIndex*3 + 1
-
-			if dayNum == 0 {
-				// Empty cell
-				r.terminal.Print(dayX, weekY, "  ", fg, bg)
+	for weekIndex, week := range r.monthCells(month, selection) {
+		weekY := startY + weekIndex*layout.rowStep
+		for dayIndex, cell := range week {
+			dayX := layout.dayX(x, dayIndex)
+
+			if cell.colors != nil {
+				for i, ch := range cell.text {
+					r.terminal.SetCell(dayX+i, weekY, ch, cell.colors[i], cell.bg)
+				}
 			} else {
-				// Create date for this day
-				dayDate := time.Date(month.Year(), month.Month(), dayNum, 0, 0, 0, 0, month.Location())
-
-				// Determine display attributes
-				dayFg, dayBg, dayText := r.getDayAttributes(dayDate, selection)
-
-				r.terminal.Print(dayX, weekY, dayText, dayFg, dayBg)
+				r.terminal.Print(dayX, weekY, cell.text, cell.fg, cell.bg)
+			}
+			barX := dayX + 2
+			if cell.mark != "" {
+				r.terminal.Print(dayX+2, weekY, cell.mark, cell.markFg, cell.markBg)
+				barX++
+			}
+			if cell.spanNext {
+				for ; barX < layout.dayX(x, dayIndex+1); barX++ {
+					r.terminal.SetCell(barX, weekY, spanBar, cell.spanFg, bg)
+				}
 			}
 		}
 	}
@@ -293,166 +532,215 @@ func (r *Renderer) renderMonth(month time.Time, x, y int, selection *models.Sele
 	return nil
 }
 
+// mixedDayColors splits the two characters of a plain event day cell between the colors
+// of the first two categories of its events; single-digit days get a "+" in the first
+// color. It returns the text, the color of each character and whether the day mixes
+// categories, so monochrome terminals, which cannot split colors, can mark the day instead.
+func (r *Renderer) mixedDayColors(date time.Time, text string, fg termbox.Attribute, selection *models.Selection) (string, []termbox.Attribute, bool) {
+	if calendar.IsSameDate(date, r.today()) || calendar.IsSameDate(date, selection.SelectedDate) || r.isDimmedPastDay(date, true) || r.hasFreeEvening(date) {
+		return text, nil, false // Their own highlighting takes precedence
+	}
+	categories := r.dayCategories(date)
+	if len(categories) < 2 {
+		return text, nil, false
+	}
+
+	if !r.styles.color {
+		return text, nil, true
+	}
+
+	// Single-digit days have no second digit to color, so their padding becomes a marker
+	if text[0] == ' ' {
+		text = "+" + text[1:]
+	}
+
+	attrs := fg & (termbox.AttrBold | termbox.AttrUnderline)
+	eventFg, _ := r.style(StyleEventDay)
+	colors := make([]termbox.Attribute, len(text))
+	for i := range text {
+		event := models.Event{Category: categories[i]}
+		colors[i] = r.categoryColor(event, eventFg&^attrs) | attrs
+	}
+	return text, colors, true
+}
+
+// dayCategories returns the distinct categories of the events on date in time order;
+// uncategorized events count as the empty category
+func (r *Renderer) dayCategories(date time.Time) []string {
+	var categories []string
+	seen := make(map[string]bool)
+	for _, event := range r.eventManager.GetEventsForDate(date) {
+		if !seen[event.Category] {
+			seen[event.Category] = true
+			categories = append(categories, event.Category)
+		}
+	}
+	return categories
+}
+
+// monthHeaderStyle highlights the header of the month holding the selection and
+// dims the others, so it is clear which grid the cursor is in
+func (r *Renderer) monthHeaderStyle(month time.Time, selection *models.Selection) StyleName {
+	if r.config == nil || !r.config.FocusMonth {
+		return StyleMonthHeader
+	}
+	selected := selection.SelectedDate
+	if selected.Year() == month.Year() && selected.Month() == month.Month() {
+		return StyleFocusedMonth
+	}
+	return StyleDimmedMonth
+}
+
+// annotationStyle returns the configured color of an annotation, or the muted style
+func (r *Renderer) annotationStyle(note annotations.Annotation) (termbox.Attribute, termbox.Attribute) {
+	fg, bg := r.style(StyleMuted)
+	return r.styles.Color(note.Color, fg), bg
+}
+
+// firstRune returns the first character of s, so a symbol never overflows its cell
+func firstRune(s string) string {
+	for _, ch := range s {
+		return string(ch)
+	}
+	return ""
+}
+
 // getDayAttributes determines the display attributes for a day cell
 func (r *Renderer) getDayAttributes(date time.Time, selection *models.Selection) (fg, bg termbox.Attribute, text string) {
 	dayNum := date.Day()
 	text = fmt.Sprintf("%2d", dayNum)
 
 	// Check various states
-	isToday := calendar.IsToday(date)
+	isToday := calendar.IsSameDate(date, r.today())
 	isSelected := calendar.IsSameDate(date, selection.SelectedDate)
 	hasEvents := r.eventManager.HasEventsForDate(date)
 
-	// Default colors using theme
-	if r.terminal.IsColorSupported() {
-		fg, bg = r.getThemeColors(
-			r.config.UITheme.RegularDayFg,
-			r.config.UITheme.RegularDayBg,
-			termbox.ColorDefault,
-			termbox.ColorDefault,
-		)
-	} else {
-		fg = termbox.ColorDefault
-		bg = termbox.ColorDefault
-	}
-
-	// Apply color themes based on state (with fallback for monochrome terminals)
-	if r.terminal.IsColorSupported() {
-		// Color terminal - use configurable theme colors
-		if isSelected && isToday {
-			// Selected + Today: use theme colors
-			fg, bg = r.getThemeColors(
-				r.config.UITheme.SelectedTodayFg,
-				r.config.UITheme.SelectedTodayBg,
-				termbox.ColorWhite|termbox.AttrBold,
-				termbox.ColorCyan,
-			)
-		} else if isSelected {
-			// Selected: use theme colors
-			fg, bg = r.getThemeColors(
-				r.config.UITheme.SelectedFg,
-				r.config.UITheme.SelectedBg,
-				termbox.ColorWhite|termbox.AttrBold,
-				termbox.ColorBlue,
-			)
-		} else if isToday {
-			// Today: use theme colors
-			fg, bg = r.getThemeColors(
-				r.config.UITheme.TodayFg,
-				r.config.UITheme.TodayBg,
-				termbox.ColorYellow|termbox.AttrBold,
-				termbox.ColorDefault,
-			)
-		} else if hasEvents {
-			// Days with events: use theme colors
-			fg, bg = r.getThemeColors(
-				r.config.UITheme.EventDayFg,
-				r.config.UITheme.EventDayBg,
-				termbox.ColorGreen,
-				termbox.ColorDefault,
-			)
-		}
-	} else {
-		// Monochrome terminal - use attribute-based styling
-		if isSelected && isToday {
-			// Selected + Today: reverse colors with bold
-			fg = termbox.ColorDefault | termbox.AttrBold | termbox.AttrReverse
-			bg = termbox.ColorDefault
-		} else if isSelected {
-			// Selected: reverse colors
-			fg = termbox.ColorDefault | termbox.AttrReverse
-			bg = termbox.ColorDefault
-		} else if isToday {
-			// Today: bold
-			fg = termbox.ColorDefault | termbox.AttrBold
-			bg = termbox.ColorDefault
-		}
+	// Pick the style for the day's state; monochrome terminals get attribute-based styles
+	switch {
+	case isSelected && isToday:
+		fg, bg = r.style(StyleSelectedToday)
+	case isSelected:
+		fg, bg = r.style(StyleSelected)
+	case isToday:
+		fg, bg = r.style(StyleToday)
+	case len(r.holidays.Names(date)) > 0:
+		fg, bg = r.style(StyleHoliday)
+	case r.hasFreeEvening(date):
+		fg, bg = r.style(StyleFreeEvening)
+	case r.isDimmedPastDay(date, hasEvents):
+		fg, bg = r.style(StylePastDay)
+	case hasEvents && r.eventManager.WorkloadWarning(date) != "":
+		fg, bg = r.style(StyleOverbooked)
+	case hasEvents:
+		fg, bg = r.style(StyleEventDay)
+	default:
+		fg, bg = r.style(StyleRegularDay)
 	}
 
 	// Note: Event indication is now handled purely through color coding
-	// No additional visual indicators (bullets, asterisks) are added
+	// Bookmarked days are underlined so the marker does not compete with event colors
+	if _, ok := r.bookmarkFor(date); ok {
+		fg |= termbox.AttrUnderline
+	}
+	// Days of a marked range are shown in reverse, keeping the colors of their state
+	if !isSelected && selection.InRange(date) {
+		fg |= termbox.AttrReverse
+	}
 
 	return fg, bg, text
 }
 
+// isDimmedPastDay reports whether past_days dims a day: a day before today, in the
+// current month unless every past day is dimmed, and without events unless the
+// dimming is strong
+func (r *Renderer) isDimmedPastDay(date time.Time, hasEvents bool) bool {
+	if r.config == nil {
+		return false
+	}
+	pastDays := r.config.PastDays
+	if pastDays.Dim != config.PastDaysMonth && pastDays.Dim != config.PastDaysAll {
+		return false
+	}
+	if hasEvents && pastDays.Intensity != config.PastDimStrong {
+		return false
+	}
+
+	today := r.today()
+	if !calendar.NormalizeDate(date).Before(today) {
+		return false
+	}
+	return pastDays.Dim == config.PastDaysAll || (date.Year() == today.Year() && date.Month() == today.Month())
+}
+
 // renderSelectedDateEvents renders events for the selected date below the calendar
 func (r *Renderer) renderSelectedDateEvents(selectedDate time.Time) {
-	fg, bg := r.terminal.GetDefaultColors()
 
 	// Calculate Y position for events section (after calendar, before key legend)
-	// Calendar starts at Y=2, month header + day headers + separator + 6 weeks = ~10 lines per month
-	eventsStartY := 13
+	eventsStartY := r.eventsPanelStartY()
 
 	// Calculate left alignment position to match calendar's left edge
 	width, _ := r.terminal.GetSize()
-	totalWidth := 3*r.monthWidth + 2*r.monthSpacing
+	totalWidth := r.layout().totalWidth()
 	startX := (width - totalWidth) / 2
 	eventsLeftX := startX + 1 // Align with calendar's leftmost day column
 
 	// Get events for the selected date
 	events := r.eventManager.GetEventsForDate(selectedDate)
 
-	// Render section header
-	dateStr := calendar.FormatDate(selectedDate)
-	headerText := fmt.Sprintf("Events for %s:", dateStr)
-
-	var headerFg, headerBg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		headerFg, headerBg = r.getThemeColors(
-			r.config.UITheme.EventHeaderFg,
-			r.config.UITheme.EventHeaderBg,
-			termbox.ColorYellow|termbox.AttrBold,
-			termbox.ColorDefault,
-		)
-	} else {
-		headerFg = termbox.AttrBold
-		headerBg = bg
+	// Render section header, with a subtle countdown from today after the date
+	dateStr := r.formatDate(selectedDate)
+	countdown := fmt.Sprintf(" (%s)", r.countdownText(selectedDate))
+	headerText := fmt.Sprintf("Events for %s%s:", dateStr, countdown)
+	if bookmark, ok := r.bookmarkFor(selectedDate); ok {
+		headerText = fmt.Sprintf("Events for %s%s (bookmark: %s):", dateStr, countdown, bookmark.Name)
 	}
 
+	headerFg, headerBg := r.style(StyleTitle)
 	r.terminal.Print(eventsLeftX, eventsStartY, headerText, headerFg, headerBg)
+	countdownFg, countdownBg := r.style(StyleMuted)
+	r.terminal.Print(eventsLeftX+len("Events for "+dateStr), eventsStartY, countdown, countdownFg, countdownBg)
+
+	// Follow the header with the day's holidays and annotations, each in its own color
+	noteX := eventsLeftX + len(headerText)
+	for _, name := range r.holidays.Names(selectedDate) {
+		holidayText := fmt.Sprintf(" [%s]", name)
+		holidayFg, holidayBg := r.style(StyleHoliday)
+		r.terminal.Print(noteX, eventsStartY, holidayText, holidayFg, holidayBg)
+		noteX += len(holidayText)
+	}
+	for _, note := range annotations.ForDate(r.annotations, selectedDate) {
+		noteText := fmt.Sprintf(" [%s]", note.Text)
+		noteFg, noteBg := r.annotationStyle(note)
+		r.terminal.Print(noteX, eventsStartY, noteText, noteFg, noteBg)
+		noteX += len(noteText)
+	}
+	if warning := r.eventManager.WorkloadWarning(selectedDate); warning != "" {
+		warningFg, warningBg := r.style(StyleOverbooked)
+		r.terminal.Print(noteX, eventsStartY, fmt.Sprintf(" [%s]", warning), warningFg, warningBg)
+	}
 
 	// Render events or "no events" message
 	if len(events) == 0 {
-		var noEventsFg, noEventsBg termbox.Attribute
-		if r.terminal.IsColorSupported() {
-			noEventsFg, noEventsBg = r.getThemeColors(
-				r.config.UITheme.NoEventsFg,
-				r.config.UITheme.NoEventsBg,
-				termbox.ColorWhite,
-				termbox.ColorDefault,
-			)
-		} else {
-			noEventsFg = fg
-			noEventsBg = bg
-		}
+		noEventsFg, noEventsBg := r.style(StyleNoEvents)
 		r.terminal.Print(eventsLeftX, eventsStartY+1, "No events scheduled", noEventsFg, noEventsBg)
 	} else {
-		// Show up to 10 events per date
-		maxEvents := 10
-		if len(events) > maxEvents {
-			maxEvents = 10
-		}
-
-		for i := 0; i < maxEvents && i < len(events); i++ {
+		// Show as many events as the panel and configuration allow, from where the
+		// panel was scrolled to with [ and ]
+		maxEvents := r.visibleEventCount(len(events), 0)
+		offset := r.paneScroll(selectedDate, len(events), maxEvents)
+		overlapping := overlappingEvents(events)
+
+		for row := 0; row < maxEvents && offset+row < len(events); row++ {
+			i := offset + row
 			event := events[i]
-			timeStr := event.GetTimeString()
-			description := event.Description
+			timeStr := eventTimeLabel(event)
+			description := r.overlapDescription(event, overlapping[i])
 
-			var eventFg, eventBg termbox.Attribute
-			if r.terminal.IsColorSupported() {
-				eventFg, eventBg = r.getThemeColors(
-					r.config.UITheme.EventTextFg,
-					r.config.UITheme.EventTextBg,
-					termbox.ColorWhite,
-					termbox.ColorDefault,
-				)
-			} else {
-				eventFg = fg
-				eventBg = bg
-			}
+			eventFg, eventBg := r.style(StyleEventText)
+			eventFg = r.categoryColor(event, eventFg)
 
 			// Render event as single line
-			eventY := eventsStartY + 1 + i
+			eventY := eventsStartY + 1 + row
 			eventText := fmt.Sprintf("%s - %s", timeStr, description)
 
 			// Calculate available width from left position to right margin
@@ -461,24 +749,16 @@ func (r *Renderer) renderSelectedDateEvents(selectedDate time.Time) {
 				eventText = eventText[:maxEventWidth-3] + "..."
 			}
 
-			r.terminal.Print(eventsLeftX, eventY, eventText, eventFg, eventBg)
+			r.terminal.PrintLinked(eventsLeftX, eventY, eventText, eventFg, eventBg)
 		}
 
-		// Show "and X more" if there are additional events
+		// Show how many events are scrolled out of view, and how to reach them
 		if len(events) > maxEvents {
-			moreText := fmt.Sprintf("... and %d more events", len(events)-maxEvents)
-			var moreFg, moreBg termbox.Attribute
-			if r.terminal.IsColorSupported() {
-				moreFg, moreBg = r.getThemeColors(
-					r.config.UITheme.MoreEventsFg,
-					r.config.UITheme.MoreEventsBg,
-					termbox.ColorMagenta,
-					termbox.ColorDefault,
-				)
-			} else {
-				moreFg = fg
-				moreBg = bg
+			moreText := moreEventsText(offset, len(events)-offset-maxEvents)
+			if hint := moreText + " ([/]: scroll)"; eventsLeftX+len(hint) <= width {
+				moreText = hint
 			}
+			moreFg, moreBg := r.style(StyleMoreEvents)
 			r.terminal.Print(eventsLeftX, eventsStartY+1+maxEvents, moreText, moreFg, moreBg)
 		}
 	}
@@ -486,15 +766,13 @@ func (r *Renderer) renderSelectedDateEvents(selectedDate time.Time) {
 
 // renderSelectedDateEventsWithSelection renders events for the selected date with selection highlighting
 func (r *Renderer) renderSelectedDateEventsWithSelection(selectedDate time.Time, selectedEventIndex int) {
-	fg, bg := r.terminal.GetDefaultColors()
 
 	// Calculate Y position for events section (after calendar, before key legend)
-	// Calendar starts at Y=2, month header + day headers + separator + 6 weeks = ~10 lines per month
-	eventsStartY := 13
+	eventsStartY := r.eventsPanelStartY()
 
 	// Calculate left alignment position to match calendar's left edge
 	width, _ := r.terminal.GetSize()
-	totalWidth := 3*r.monthWidth + 2*r.monthSpacing
+	totalWidth := r.layout().totalWidth()
 	startX := (width - totalWidth) / 2
 	eventsLeftX := startX + 1 // Align with calendar's leftmost day column
 
@@ -502,38 +780,28 @@ func (r *Renderer) renderSelectedDateEventsWithSelection(selectedDate time.Time,
 	events := r.eventManager.GetEventsForDate(selectedDate)
 
 	// Render section header
-	dateStr := calendar.FormatDate(selectedDate)
+	dateStr := r.formatDate(selectedDate)
 	headerText := fmt.Sprintf("Events for %s (Use ↑↓ to select, Enter to delete, Esc to cancel):", dateStr)
 
-	var headerFg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		headerFg = termbox.ColorYellow | termbox.AttrBold
-	} else {
-		headerFg = termbox.AttrBold
-	}
-
-	r.terminal.Print(eventsLeftX, eventsStartY, headerText, headerFg, bg)
+	headerFg, headerBg := r.style(StyleTitle)
+	r.terminal.Print(eventsLeftX, eventsStartY, headerText, headerFg, headerBg)
 
 	// Render events or "no events" message
 	if len(events) == 0 {
-		var noEventsFg termbox.Attribute
-		if r.terminal.IsColorSupported() {
-			noEventsFg = termbox.ColorWhite
-		} else {
-			noEventsFg = fg
-		}
-		r.terminal.Print(eventsLeftX, eventsStartY+1, "No events scheduled", noEventsFg, bg)
+		noEventsFg, noEventsBg := r.style(StyleNoEvents)
+		r.terminal.Print(eventsLeftX, eventsStartY+1, "No events scheduled", noEventsFg, noEventsBg)
 	} else {
-		// Show up to 10 events per date
-		maxEvents := 10
-		if len(events) > maxEvents {
-			maxEvents = 10
-		}
+		// Show as many events as the panel and configuration allow
+		maxEvents := r.visibleEventCount(len(events), 0)
 
-		for i := 0; i < maxEvents && i < len(events); i++ {
+		// Scroll the list so the selected event stays visible
+		offset := scrollOffset(len(events), maxEvents, selectedEventIndex)
+
+		for row := 0; row < maxEvents && offset+row < len(events); row++ {
+			i := offset + row
 			event := events[i]
-			timeStr := event.GetTimeString()
-			description := event.Description
+			timeStr := event.GetTimeLabel()
+			description := r.eventDescription(event)
 
 			// Check if this is the selected event
 			isSelected := i == selectedEventIndex
@@ -544,26 +812,16 @@ func (r *Renderer) renderSelectedDateEventsWithSelection(selectedDate time.Time,
 			if isSelected {
 				// Selected event: use highlighting
 				prefix = "> "
-				if r.terminal.IsColorSupported() {
-					eventFg = termbox.ColorBlack | termbox.AttrBold
-					eventBg = termbox.ColorYellow // Yellow background for selection
-				} else {
-					eventFg = termbox.ColorDefault | termbox.AttrReverse | termbox.AttrBold
-					eventBg = termbox.ColorDefault
-				}
+				eventFg, eventBg = r.style(StyleSelectedEvent)
 			} else {
 				// Normal event colors
 				prefix = "  "
-				eventBg = bg
-				if r.terminal.IsColorSupported() {
-					eventFg = termbox.ColorWhite
-				} else {
-					eventFg = fg
-				}
+				eventFg, eventBg = r.style(StyleEventText)
+				eventFg = r.categoryColor(event, eventFg)
 			}
 
 			// Render event as single line with selection indicator
-			eventY := eventsStartY + 1 + i
+			eventY := eventsStartY + 1 + row
 			eventText := fmt.Sprintf("%s%s - %s", prefix, timeStr, description)
 
 			// Calculate available width from left position to right margin
@@ -572,7 +830,7 @@ func (r *Renderer) renderSelectedDateEventsWithSelection(selectedDate time.Time,
 				eventText = eventText[:maxEventWidth-3] + "..."
 			}
 
-			r.terminal.Print(eventsLeftX, eventY, eventText, eventFg, eventBg)
+			r.terminal.PrintLinked(eventsLeftX, eventY, eventText, eventFg, eventBg)
 
 			// Fill the rest of the line with the background color for selected events
 			if isSelected {
@@ -582,31 +840,24 @@ func (r *Renderer) renderSelectedDateEventsWithSelection(selectedDate time.Time,
 			}
 		}
 
-		// Show "and X more" if there are additional events
+		// Show how many events are scrolled out of view
 		if len(events) > maxEvents {
-			moreText := fmt.Sprintf("... and %d more events", len(events)-maxEvents)
-			var moreFg termbox.Attribute
-			if r.terminal.IsColorSupported() {
-				moreFg = termbox.ColorMagenta
-			} else {
-				moreFg = fg
-			}
-			r.terminal.Print(eventsLeftX, eventsStartY+1+maxEvents, moreText, moreFg, bg)
+			moreText := moreEventsText(offset, len(events)-offset-maxEvents)
+			moreFg, moreBg := r.style(StyleMoreEvents)
+			r.terminal.Print(eventsLeftX, eventsStartY+1+maxEvents, moreText, moreFg, moreBg)
 		}
 	}
 }
 
 // renderSelectedDateEventsWithEditMode renders events for the selected date with edit mode highlighting
 func (r *Renderer) renderSelectedDateEventsWithEditMode(selectedDate time.Time, selectedEventIndex int) {
-	fg, bg := r.terminal.GetDefaultColors()
 
 	// Calculate Y position for events section (after calendar, before key legend)
-	// Calendar starts at Y=2, month header + day headers + separator + 6 weeks = ~10 lines per month
-	eventsStartY := 13
+	eventsStartY := r.eventsPanelStartY()
 
 	// Calculate left alignment position to match calendar's left edge
 	width, _ := r.terminal.GetSize()
-	totalWidth := 3*r.monthWidth + 2*r.monthSpacing
+	totalWidth := r.layout().totalWidth()
 	startX := (width - totalWidth) / 2
 	eventsLeftX := startX + 1 // Align with calendar's leftmost day column
 
@@ -614,38 +865,28 @@ func (r *Renderer) renderSelectedDateEventsWithEditMode(selectedDate time.Time,
 	events := r.eventManager.GetEventsForDate(selectedDate)
 
 	// Render section header
-	dateStr := calendar.FormatDate(selectedDate)
+	dateStr := r.formatDate(selectedDate)
 	headerText := fmt.Sprintf("Events for %s (Use ↑↓ to select, Enter to edit, Esc to cancel):", dateStr)
 
-	var headerFg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		headerFg = termbox.ColorYellow | termbox.AttrBold
-	} else {
-		headerFg = termbox.AttrBold
-	}
-
-	r.terminal.Print(eventsLeftX, eventsStartY, headerText, headerFg, bg)
+	headerFg, headerBg := r.style(StyleTitle)
+	r.terminal.Print(eventsLeftX, eventsStartY, headerText, headerFg, headerBg)
 
 	// Render events or "no events" message
 	if len(events) == 0 {
-		var noEventsFg termbox.Attribute
-		if r.terminal.IsColorSupported() {
-			noEventsFg = termbox.ColorWhite
-		} else {
-			noEventsFg = fg
-		}
-		r.terminal.Print(eventsLeftX, eventsStartY+1, "No events scheduled", noEventsFg, bg)
+		noEventsFg, noEventsBg := r.style(StyleNoEvents)
+		r.terminal.Print(eventsLeftX, eventsStartY+1, "No events scheduled", noEventsFg, noEventsBg)
 	} else {
-		// Show up to 10 events per date
-		maxEvents := 10
-		if len(events) > maxEvents {
-			maxEvents = 10
-		}
+		// Show as many events as the panel and configuration allow
+		maxEvents := r.visibleEventCount(len(events), 0)
+
+		// Scroll the list so the selected event stays visible
+		offset := scrollOffset(len(events), maxEvents, selectedEventIndex)
 
-		for i := 0; i < maxEvents && i < len(events); i++ {
+		for row := 0; row < maxEvents && offset+row < len(events); row++ {
+			i := offset + row
 			event := events[i]
-			timeStr := event.GetTimeString()
-			description := event.Description
+			timeStr := event.GetTimeLabel()
+			description := r.eventDescription(event)
 
 			// Check if this is the selected event
 			isSelected := i == selectedEventIndex
@@ -656,26 +897,16 @@ func (r *Renderer) renderSelectedDateEventsWithEditMode(selectedDate time.Time,
 			if isSelected {
 				// Selected event: use highlighting
 				prefix = "> "
-				if r.terminal.IsColorSupported() {
-					eventFg = termbox.ColorBlack | termbox.AttrBold
-					eventBg = termbox.ColorYellow // Yellow background for selection
-				} else {
-					eventFg = termbox.ColorDefault | termbox.AttrReverse | termbox.AttrBold
-					eventBg = termbox.ColorDefault
-				}
+				eventFg, eventBg = r.style(StyleSelectedEvent)
 			} else {
 				// Normal event colors
 				prefix = "  "
-				eventBg = bg
-				if r.terminal.IsColorSupported() {
-					eventFg = termbox.ColorWhite
-				} else {
-					eventFg = fg
-				}
+				eventFg, eventBg = r.style(StyleEventText)
+				eventFg = r.categoryColor(event, eventFg)
 			}
 
 			// Render event as single line with selection indicator
-			eventY := eventsStartY + 1 + i
+			eventY := eventsStartY + 1 + row
 			eventText := fmt.Sprintf("%s%s - %s", prefix, timeStr, description)
 
 			// Calculate available width from left position to right margin
@@ -684,7 +915,7 @@ func (r *Renderer) renderSelectedDateEventsWithEditMode(selectedDate time.Time,
 				eventText = eventText[:maxEventWidth-3] + "..."
 			}
 
-			r.terminal.Print(eventsLeftX, eventY, eventText, eventFg, eventBg)
+			r.terminal.PrintLinked(eventsLeftX, eventY, eventText, eventFg, eventBg)
 
 			// Fill the rest of the line with the background color for selected events
 			if isSelected {
@@ -694,31 +925,24 @@ func (r *Renderer) renderSelectedDateEventsWithEditMode(selectedDate time.Time,
 			}
 		}
 
-		// Show "and X more" if there are additional events
+		// Show how many events are scrolled out of view
 		if len(events) > maxEvents {
-			moreText := fmt.Sprintf("... and %d more events", len(events)-maxEvents)
-			var moreFg termbox.Attribute
-			if r.terminal.IsColorSupported() {
-				moreFg = termbox.ColorMagenta
-			} else {
-				moreFg = fg
-			}
-			r.terminal.Print(eventsLeftX, eventsStartY+1+maxEvents, moreText, moreFg, bg)
+			moreText := moreEventsText(offset, len(events)-offset-maxEvents)
+			moreFg, moreBg := r.style(StyleMoreEvents)
+			r.terminal.Print(eventsLeftX, eventsStartY+1+maxEvents, moreText, moreFg, moreBg)
 		}
 	}
 }
 
 // renderSelectedDateEventsWithAddMode renders events for the selected date with add mode highlighting
 func (r *Renderer) renderSelectedDateEventsWithAddMode(selectedDate time.Time) {
-	fg, bg := r.terminal.GetDefaultColors()
 
 	// Calculate Y position for events section (after calendar, before key legend)
-	// Calendar starts at Y=2, month header + day headers + separator + 6 weeks = ~10 lines per month
-	eventsStartY := 13
+	eventsStartY := r.eventsPanelStartY()
 
 	// Calculate left alignment position to match calendar's left edge
 	width, _ := r.terminal.GetSize()
-	totalWidth := 3*r.monthWidth + 2*r.monthSpacing
+	totalWidth := r.layout().totalWidth()
 	startX := (width - totalWidth) / 2
 	eventsLeftX := startX + 1 // Align with calendar's leftmost day column
 
@@ -726,35 +950,22 @@ func (r *Renderer) renderSelectedDateEventsWithAddMode(selectedDate time.Time) {
 	events := r.eventManager.GetEventsForDate(selectedDate)
 
 	// Render section header
-	dateStr := calendar.FormatDate(selectedDate)
+	dateStr := r.formatDate(selectedDate)
 	headerText := fmt.Sprintf("Add new event for %s (Enter to add, Esc to cancel):", dateStr)
 
-	var headerFg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		headerFg = termbox.ColorYellow | termbox.AttrBold
-	} else {
-		headerFg = termbox.AttrBold
-	}
-
-	r.terminal.Print(eventsLeftX, eventsStartY, headerText, headerFg, bg)
+	headerFg, headerBg := r.style(StyleTitle)
+	r.terminal.Print(eventsLeftX, eventsStartY, headerText, headerFg, headerBg)
 
-	// First render existing events (up to 9 to leave room for the new event row)
-	maxExistingEvents := 9
-	if len(events) > maxExistingEvents {
-		maxExistingEvents = 9
-	}
+	// First render existing events, leaving room for the new event row
+	maxExistingEvents := r.visibleEventCount(len(events), 1)
 
 	for i := 0; i < maxExistingEvents && i < len(events); i++ {
 		event := events[i]
-		timeStr := event.GetTimeString()
-		description := event.Description
+		timeStr := event.GetTimeLabel()
+		description := r.eventDescription(event)
 
-		var eventFg termbox.Attribute
-		if r.terminal.IsColorSupported() {
-			eventFg = termbox.ColorWhite
-		} else {
-			eventFg = fg
-		}
+		eventFg, eventBg := r.style(StyleEventText)
+		eventFg = r.categoryColor(event, eventFg)
 
 		// Render existing event as single line with normal formatting
 		eventY := eventsStartY + 1 + i
@@ -766,20 +977,13 @@ func (r *Renderer) renderSelectedDateEventsWithAddMode(selectedDate time.Time) {
 			eventText = eventText[:maxEventWidth-3] + "..."
 		}
 
-		r.terminal.Print(eventsLeftX, eventY, eventText, eventFg, bg)
+		r.terminal.PrintLinked(eventsLeftX, eventY, eventText, eventFg, eventBg)
 	}
 
 	// Now render the highlighted empty row for adding new event
-	addEventY := eventsStartY + 1 + maxExistingEvents
+	addEventY := r.NewEventRowY(selectedDate)
 
-	var addEventFg, addEventBg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		addEventFg = termbox.ColorBlack | termbox.AttrBold
-		addEventBg = termbox.ColorYellow // Yellow background for selection
-	} else {
-		addEventFg = termbox.ColorDefault | termbox.AttrReverse | termbox.AttrBold
-		addEventBg = termbox.ColorDefault
-	}
+	addEventFg, addEventBg := r.style(StyleSelectedEvent)
 
 	// Render the empty highlighted row for new event
 	newEventText := "> [New Event]"
@@ -793,33 +997,34 @@ func (r *Renderer) renderSelectedDateEventsWithAddMode(selectedDate time.Time) {
 	// Show "and X more" if there are additional existing events
 	if len(events) > maxExistingEvents {
 		moreText := fmt.Sprintf("... and %d more existing events", len(events)-maxExistingEvents)
-		var moreFg termbox.Attribute
-		if r.terminal.IsColorSupported() {
-			moreFg = termbox.ColorMagenta
-		} else {
-			moreFg = fg
-		}
-		r.terminal.Print(eventsLeftX, addEventY+1, moreText, moreFg, bg)
+		moreFg, moreBg := r.style(StyleMoreEvents)
+		r.terminal.Print(eventsLeftX, addEventY+1, moreText, moreFg, moreBg)
 	}
 }
 
 // renderEventSelectionKeyLegend renders the key bindings legend for event selection mode
 func (r *Renderer) renderEventSelectionKeyLegend() {
+	if r.focus {
+		return
+	}
 	_, height := r.terminal.GetSize()
 	legendY := height - 2
 
-	fg, bg := r.terminal.GetDefaultColors()
+	fg, bg := r.style(StyleText)
 
-	legend := "↑↓: select event  Enter: delete  Esc: cancel"
+	legend := "↑↓: select event  Enter: delete  1-9: category  0: clear  !: flag  Esc: cancel"
 	r.terminal.PrintCentered(legendY, legend, fg, bg)
 }
 
 // renderEventAddKeyLegend renders the key bindings legend for event add mode
 func (r *Renderer) renderEventAddKeyLegend() {
+	if r.focus {
+		return
+	}
 	_, height := r.terminal.GetSize()
 	legendY := height - 2
 
-	fg, bg := r.terminal.GetDefaultColors()
+	fg, bg := r.style(StyleText)
 
 	legend := "Enter: add event  Esc: cancel"
 	r.terminal.PrintCentered(legendY, legend, fg, bg)
@@ -827,111 +1032,175 @@ func (r *Renderer) renderEventAddKeyLegend() {
 
 // renderEventEditKeyLegend renders the key bindings legend for event edit mode
 func (r *Renderer) renderEventEditKeyLegend() {
+	if r.focus {
+		return
+	}
 	_, height := r.terminal.GetSize()
 	legendY := height - 2
 
-	fg, bg := r.terminal.GetDefaultColors()
+	fg, bg := r.style(StyleText)
 
-	legend := "↑↓: select event  Enter: edit  Esc: cancel"
+	legend := "↑↓: select event  Enter: edit  Shift+M: move  1-9, 0: category  !: flag  Esc: cancel"
 	r.terminal.PrintCentered(legendY, legend, fg, bg)
 }
 
 // renderKeyLegend renders the key bindings legend at the bottom
 func (r *Renderer) renderKeyLegend() {
+	if r.focus {
+		return
+	}
 	_, height := r.terminal.GetSize()
 	legendY := height - 2
 
-	fg, bg := r.terminal.GetDefaultColors()
-
-	legend := "B/N: month  H/J/K/L: move  Enter: events  A: add  D: delete  E: edit  C: current  F: search  Q: quit"
-	r.terminal.PrintCentered(legendY, legend, fg, bg)
-}
+	fg, bg := r.style(StyleText)
 
-// RenderEventList renders the event list for a selected date with selection highlighting
-func (r *Renderer) RenderEventList(date time.Time, events []models.Event, selectedIndex int) error {
-	r.terminal.Clear()
+	width, _ := r.terminal.GetSize()
+	r.terminal.PrintCentered(legendY, fitLegend(calendarLegend, "?: help  Q: quit", width), fg, bg)
 
-	width, height := r.terminal.GetSize()
-	fg, bg := r.terminal.GetDefaultColors()
+	r.renderStatusBar()
+}
 
-	// Title with color
-	dateStr := calendar.FormatDate(date)
-	title := fmt.Sprintf("Events for %s", dateStr)
+// calendarLegend are the keys of the calendar legend, most useful first
+var calendarLegend = []string{
+	"B/N: month", "h/j/k/l: move", "Enter: events", "A: add", "d/dd: delete", "E: edit",
+	"Shift+D: day", "F: search", "c/gg: today", "g/:: go to", "Shift+C: calendars",
+	"S: stats", "M: bookmark", "G: bookmarks", "O: follow-ups", "I: inbox", "V: range",
+	"Y: copy", "P: paste", "W: free evenings", "T: theme", "Z: focus", "Shift+L: log",
+}
 
-	var titleFg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		// Use yellow for the title in color terminals
-		titleFg = termbox.ColorYellow | termbox.AttrBold
-	} else {
-		titleFg = termbox.AttrBold
+// fitLegend joins as many of the keys as fit into width next to tail, which is always
+// shown; the help screen lists the keys left out
+func fitLegend(keys []string, tail string, width int) string {
+	legend := ""
+	for _, key := range keys {
+		if len(legend)+len(key)+2+len(tail) > width {
+			break
+		}
+		legend += key + "  "
 	}
-	r.terminal.PrintCentered(2, title, titleFg, bg)
+	return legend + tail
+}
 
-	// Draw separator with color
-	separatorY := 4
-	var separatorFg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		separatorFg = termbox.ColorCyan
-	} else {
-		separatorFg = fg
+// renderFilterHeader names the active quick filters and the free evenings highlight
+// right-aligned on the top line
+func (r *Renderer) renderFilterHeader() {
+	active := r.eventManager.ActiveFilters()
+	if len(active) == 0 && !r.freeEvenings {
+		return
+	}
+	names := make([]string, len(active))
+	for i, filter := range active {
+		names[i] = filter.Name
+		if names[i] == "" {
+			names[i] = filter.Key
+		}
+	}
+	if r.freeEvenings {
+		names = append(names, "free evenings from "+r.freeEveningFrom())
+	}
+
+	fg, bg := r.style(StyleInstructions)
+	r.terminal.PrintRight(0, fmt.Sprintf("Filters: %s (F9: clear)", strings.Join(names, ", ")), fg, bg)
+}
+
+// renderStatusBar shows the background status right-aligned on the last line
+func (r *Renderer) renderStatusBar() {
+	if r.status == nil {
+		return
+	}
+	status := r.status()
+	if status == "" {
+		return
+	}
+
+	_, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleInstructions)
+	r.terminal.PrintRight(height-1, status, fg, bg)
+}
+
+// RenderEventList renders the event list for a selected date with selection highlighting
+func (r *Renderer) RenderEventList(date time.Time, events []models.Event, selectedIndex int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+
+	// Title with color
+	dateStr := r.formatDate(date)
+	title := fmt.Sprintf("Events for %s (%s)", dateStr, r.countdownText(date))
+
+	titleFg, titleBg := r.style(StyleTitle)
+	r.terminal.PrintCentered(2, title, titleFg, titleBg)
+	if names := r.holidays.Names(date); len(names) > 0 {
+		holidayFg, holidayBg := r.style(StyleHoliday)
+		r.terminal.PrintCentered(3, strings.Join(names, ", "), holidayFg, holidayBg)
 	}
+	r.renderFilterHeader()
+
+	// Draw separator with color
+	separatorY := 4
+	separatorFg, separatorBg := r.style(StyleSeparator)
 	for i := 0; i < width; i++ {
-		r.terminal.SetCell(i, separatorY, '-', separatorFg, bg)
+		r.terminal.SetCell(i, separatorY, '-', separatorFg, separatorBg)
 	}
 
-	startY := 6
+	startY := eventListStartY
 	if len(events) == 0 {
-		var noEventsFg termbox.Attribute
-		if r.terminal.IsColorSupported() {
-			noEventsFg = termbox.ColorWhite
-		} else {
-			noEventsFg = fg
-		}
-		r.terminal.PrintCentered(startY, "No events scheduled for this date", noEventsFg, bg)
+		noEventsFg, noEventsBg := r.style(StyleNoEvents)
+		r.terminal.PrintCentered(startY, "No events scheduled for this date", noEventsFg, noEventsBg)
 	} else {
-		for i, event := range events {
-			if startY+i >= height-4 {
+		// Scroll the list so the selected event stays visible
+		rows := eventListRows(events)
+		overlapping := overlappingEvents(events)
+		offset := scrollOffset(len(rows), height-4-startY, eventListRow(rows, selectedIndex))
+
+		for row, i := range rows[offset:] {
+			if startY+row >= height-4 {
 				// Too many events to display
-				moreText := fmt.Sprintf("... and %d more events", len(events)-i)
-				var moreFg termbox.Attribute
-				if r.terminal.IsColorSupported() {
-					moreFg = termbox.ColorMagenta
-				} else {
-					moreFg = fg
+				above, hidden := 0, 0
+				for _, index := range rows[:offset] {
+					if index >= 0 {
+						above++
+					}
+				}
+				for _, index := range rows[offset+row:] {
+					if index >= 0 {
+						hidden++
+					}
 				}
-				r.terminal.PrintCentered(startY+i, moreText, moreFg, bg)
+				moreText := moreEventsText(above, hidden)
+				moreFg, moreBg := r.style(StyleMoreEvents)
+				r.terminal.PrintCentered(startY+row, moreText, moreFg, moreBg)
 				break
 			}
 
+			if i < 0 {
+				heading := "All day:"
+				if i == timedHeadingRow {
+					heading = "Scheduled:"
+				}
+				headingFg, headingBg := r.style(StyleTitle)
+				r.terminal.Print(2, startY+row, heading, headingFg, headingBg)
+				continue
+			}
+			event := events[i]
+
 			// Check if this is the selected event
 			isSelected := i == selectedIndex
 
 			// Color the time and description differently
-			timeStr := event.GetTimeString()
-			description := event.Description
+			timeStr := eventTimeLabel(event)
+			description := r.overlapDescription(event, overlapping[i])
 
 			var timeFg, descFg, eventBg termbox.Attribute
 			if isSelected {
 				// Selected event: use highlighting
-				if r.terminal.IsColorSupported() {
-					timeFg = termbox.ColorBlack | termbox.AttrBold
-					descFg = termbox.ColorBlack
-					eventBg = termbox.ColorYellow // Yellow background for selection
-				} else {
-					timeFg = termbox.ColorDefault | termbox.AttrReverse | termbox.AttrBold
-					descFg = termbox.ColorDefault | termbox.AttrReverse
-					eventBg = termbox.ColorDefault
-				}
+				timeFg, eventBg = r.style(StyleSelectedEvent)
+				descFg = timeFg
 			} else {
-				// Normal event colors
-				eventBg = bg
-				if r.terminal.IsColorSupported() {
-					timeFg = termbox.ColorGreen | termbox.AttrBold // Green for time
-					descFg = termbox.ColorWhite                    // White for description
-				} else {
-					timeFg = termbox.AttrBold
-					descFg = fg
-				}
+				// Normal event colors, with the category color for the description
+				timeFg, eventBg = r.style(StyleEventTime)
+				descFg, _ = r.style(StyleEventText)
+				descFg = r.categoryColor(event, descFg)
 			}
 
 			// Add selection indicator
@@ -943,28 +1212,28 @@ func (r *Renderer) RenderEventList(date time.Time, events []models.Event, select
 			}
 
 			// Print prefix (selection indicator)
-			r.terminal.Print(0, startY+i, prefix, timeFg, eventBg)
+			r.terminal.Print(0, startY+row, prefix, timeFg, eventBg)
 
 			// Print time
-			r.terminal.Print(2, startY+i, timeStr, timeFg, eventBg)
+			r.terminal.Print(2, startY+row, timeStr, timeFg, eventBg)
 
 			// Print separator
 			separator := " - "
-			r.terminal.Print(2+len(timeStr), startY+i, separator, timeFg, eventBg)
+			r.terminal.Print(2+len(timeStr), startY+row, separator, timeFg, eventBg)
 
 			// Print description (truncate if too long)
 			descriptionText := description
 			maxDescWidth := width - 4 - len(timeStr) - len(separator)
-			if len(descriptionText) > maxDescWidth {
-				descriptionText = descriptionText[:maxDescWidth-3] + "..."
+			if len([]rune(descriptionText)) > maxDescWidth {
+				descriptionText = fitText(descriptionText, maxDescWidth-3) + "..."
 			}
-			r.terminal.Print(2+len(timeStr)+len(separator), startY+i, descriptionText, descFg, eventBg)
+			r.terminal.PrintLinked(2+len(timeStr)+len(separator), startY+row, descriptionText, descFg, eventBg)
 
 			// Fill the rest of the line with the background color for selected events
 			if isSelected {
-				lineLength := 2 + len(timeStr) + len(separator) + len(descriptionText)
+				lineLength := 2 + len(timeStr) + len(separator) + len([]rune(descriptionText))
 				for x := lineLength; x < width; x++ {
-					r.terminal.SetCell(x, startY+i, ' ', timeFg, eventBg)
+					r.terminal.SetCell(x, startY+row, ' ', timeFg, eventBg)
 				}
 			}
 		}
@@ -972,38 +1241,93 @@ func (r *Renderer) RenderEventList(date time.Time, events []models.Event, select
 
 	// Instructions with color
 	instrY := height - 3
-	var instrFg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		instrFg = termbox.ColorCyan
-	} else {
-		instrFg = fg
-	}
-	r.terminal.PrintCentered(instrY, "J/K: navigate  A: add event  D: delete event  E: edit event  Esc: back to calendar", instrFg, bg)
+	instrFg, instrBg := r.style(StyleInstructions)
+	r.terminal.PrintCentered(instrY, "J/K: navigate  A: add  D: delete  E: edit  1-9: category  !: flag  Y: copy  Esc: back to calendar", instrFg, instrBg)
+	r.terminal.PrintCentered(instrY+1, "Enter: details  +/- >/<: move a day/week  Shift+M: move to a date  U: undo", instrFg, instrBg)
 
 	return r.terminal.Flush()
 }
 
+// flashDuration is how long the status bar stays inverted for the visual bell
+const flashDuration = 80 * time.Millisecond
+
+// Reject gives feedback for a rejected action, such as an unknown key or a move
+// past the visible months, with the configured audible or visual bell
+func (r *Renderer) Reject() {
+	bell := config.BellVisual
+	if r.config != nil {
+		bell = r.config.Bell
+	}
+
+	switch bell {
+	case config.BellAudible:
+		r.terminal.Bell()
+	case config.BellVisual:
+		r.flashStatusBar()
+	}
+}
+
+// Notify draws attention to a new notice in the status bar by flashing it, and with
+// bell by ringing the terminal bell too
+func (r *Renderer) Notify(bell bool) {
+	if bell {
+		r.terminal.Bell()
+	}
+	r.flashStatusBar()
+}
+
+// flashStatusBar briefly inverts the status bar line; the next render restores it
+func (r *Renderer) flashStatusBar() {
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleInstructions)
+	r.terminal.FillRect(0, height-1, width, 1, ' ', fg|termbox.AttrReverse, bg)
+	r.terminal.Flush()
+	time.Sleep(flashDuration)
+}
+
 // RenderMessage renders a status message at the bottom
 func (r *Renderer) RenderMessage(message string, isError bool) {
-	_, height := r.terminal.GetSize()
-	messageY := height - 1
-
-	var fg termbox.Attribute
+	styleName := StyleSuccess
 	if isError {
-		fg = termbox.ColorRed
-	} else {
-		fg = termbox.ColorGreen
+		styleName = StyleError
+	}
+	r.renderBottomLine(message, styleName)
+}
+
+// RenderPostedMessage renders a message of the status line queue on the bottom line, in
+// the color of its severity
+func (r *Renderer) RenderPostedMessage(message messages.Message) {
+	r.renderBottomLine(message.Text, severityStyle(message.Severity))
+}
+
+// severityStyle returns the style of messages of a severity
+func severityStyle(severity messages.Severity) StyleName {
+	switch severity {
+	case messages.Warning:
+		return StyleWarning
+	case messages.Error:
+		return StyleError
+	default:
+		return StyleSuccess
 	}
+}
+
+// renderBottomLine renders a message centered on the bottom line, which takes the
+// background of the message's style
+func (r *Renderer) renderBottomLine(message string, styleName StyleName) {
+	width, height := r.terminal.GetSize()
+	messageY := height - 1
+	fg, bg := r.style(styleName)
 
 	// Clear the line first
-	r.terminal.FillRect(0, messageY, 80, 1, ' ', termbox.ColorDefault, termbox.ColorDefault)
+	r.terminal.FillRect(0, messageY, width, 1, ' ', fg, bg)
 
 	// Display message (truncate if too long)
-	if len(message) > 78 {
-		message = message[:75] + "..."
+	if limit := width - 2; len(message) > limit {
+		message = fitText(message, limit-3) + "..."
 	}
 
-	r.terminal.PrintCentered(messageY, message, fg, termbox.ColorDefault)
+	r.terminal.PrintCentered(messageY, message, fg, bg)
 }
 
 // RenderInputPrompt renders an input prompt for adding events
@@ -1012,7 +1336,7 @@ func (r *Renderer) RenderInputPrompt(prompt, input string) error {
 	promptY := height - 4
 	inputY := height - 3
 
-	fg, bg := r.terminal.GetDefaultColors()
+	fg, bg := r.style(StyleText)
 
 	// Clear the input area
 	r.terminal.FillRect(0, promptY, 80, 3, ' ', fg, bg)
@@ -1029,16 +1353,27 @@ func (r *Renderer) RenderInputPrompt(prompt, input string) error {
 
 // RenderInlineInput renders input directly on the highlighted event line
 func (r *Renderer) RenderInlineInput(x, y int, prompt, input string) error {
-	width, _ := r.terminal.GetSize()
+	width, height := r.terminal.GetSize()
+	x, y = r.clampInlineInputPosition(x, y, width, height)
 
-	// Use highlighting colors similar to event selection
-	var inputFg, inputBg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		inputFg = termbox.ColorBlack | termbox.AttrBold
-		inputBg = termbox.ColorYellow // Yellow background for input
-	} else {
-		inputFg = termbox.ColorDefault | termbox.AttrReverse | termbox.AttrBold
-		inputBg = termbox.ColorDefault
+	// Use highlighting colors similar to event selection; a rejected field is shown as an error
+	inputFg, inputBg := r.style(StyleInput)
+	noteY := y + 1
+	if noteY >= height {
+		noteY = y - 1
+	}
+	if r.inputError != "" {
+		inputFg, inputBg = r.style(StyleError)
+		for i := x; i < width; i++ {
+			r.terminal.SetCell(i, noteY, ' ', inputFg, inputBg)
+		}
+		r.terminal.Print(x, noteY, "! "+r.inputError, inputFg, inputBg)
+	} else if r.inputHint != "" {
+		hintFg, hintBg := r.style(StyleInstructions)
+		for i := x; i < width; i++ {
+			r.terminal.SetCell(i, noteY, ' ', hintFg, hintBg)
+		}
+		r.terminal.Print(x, noteY, r.inputHint, hintFg, hintBg)
 	}
 
 	// Clear the entire line first
@@ -1046,54 +1381,104 @@ func (r *Renderer) RenderInlineInput(x, y int, prompt, input string) error {
 		r.terminal.SetCell(i, y, ' ', inputFg, inputBg)
 	}
 
-	// Create the display text with cursor
-	displayText := fmt.Sprintf("> %s %s_", prompt, input)
-
-	// Truncate if too long
-	maxWidth := width - x - 2
-	if len(displayText) > maxWidth {
-		displayText = displayText[:maxWidth-3] + "..."
-	}
-
-	// Display the input line
+	// Display the input line, scrolled so the cursor stays visible
+	displayText := inlineInputText(prompt, input, width-x-1)
 	r.terminal.Print(x, y, displayText, inputFg, inputBg)
 
 	return r.terminal.Flush()
 }
 
-// RenderCalendarWithSearch renders the calendar with search results
-func (r *Renderer) RenderCalendarWithSearch(cal *models.Calendar, selection *models.Selection, query string, results []models.Event, resultDates []string, selectedIndex int) error {
-	r.terminal.Clear()
+// DatePreview describes the date a date expression being typed resolves to from base,
+// e.g. "= Fri 22.08.2025", offering the completion of a partly typed word
+func (r *Renderer) DatePreview(input string, base time.Time) string {
+	if date, err := calendar.ParseRelativeDate(input, base); err == nil {
+		return "= " + date.Format("Mon ") + r.formatDate(date)
+	}
+	if completed, ok := calendar.CompleteDateExpression(input); ok {
+		if date, err := calendar.ParseRelativeDate(completed, base); err == nil {
+			return fmt.Sprintf("Tab: %s = %s %s", completed, date.Format("Mon"), r.formatDate(date))
+		}
+		return "Tab: " + completed
+	}
+	return "? e.g. tomorrow, fri, next week, eom or 2w"
+}
 
-	// Get terminal size
-	width, height := r.terminal.GetSize()
-	if width < 80 || height < 24 {
-		r.terminal.PrintCentered(height/2, "Terminal too small! Minimum 80x24 required.",
-			termbox.ColorRed, termbox.ColorDefault)
-		return r.terminal.Flush()
+// SetInputError shows message below the inline input line and highlights the line
+// until it is cleared with an empty message
+func (r *Renderer) SetInputError(message string) {
+	r.inputError = message
+}
+
+// minInlineInputWidth is the narrowest input line worth keeping at its requested column
+const minInlineInputWidth = 30
+
+// clampInlineInputPosition keeps an inline input on screen and above the key legend.
+// When too little room is left to the right of x, the input starts at the left edge.
+func (r *Renderer) clampInlineInputPosition(x, y, width, height int) (int, int) {
+	// The legend occupies height-2 and the status message height-1
+	lastRow := height - 3
+	if y > lastRow {
+		y = lastRow
+	}
+	if y < 0 {
+		y = 0
 	}
 
-	// Calculate starting positions for three months
-	totalWidth := 3*r.monthWidth + 2*r.monthSpacing
-	startX := (width - totalWidth) / 2
+	if x < 0 || width-x < minInlineInputWidth {
+		x = 0
+	}
+	return x, y
+}
 
-	prevMonth := cal.GetPreviousMonth()
-	currentMonth := cal.CurrentMonth
-	nextMonth := cal.GetNextMonth()
+// inlineInputText builds the inline input line for the given width.
+// Long input is scrolled horizontally so its end and the cursor remain visible.
+func inlineInputText(prompt, input string, maxWidth int) string {
+	if maxWidth <= 0 {
+		return ""
+	}
 
-	months := []time.Time{prevMonth, currentMonth, nextMonth}
+	label := fmt.Sprintf("> %s ", prompt)
+	text := label + input + "_"
+	if len(text) <= maxWidth {
+		return text
+	}
 
-	// Render each month
-	for i, month := range months {
-		x := startX + i*(r.monthWidth+r.monthSpacing)
-		err := r.renderMonth(month, x, 2, selection)
-		if err != nil {
-			return err
+	// Keep at least a few characters of input visible, shortening the prompt if needed
+	const ellipsis = "..."
+	minInput := len(ellipsis) + 4
+	if len(label)+minInput > maxWidth {
+		if maxWidth <= minInput {
+			label = ""
+		} else {
+			label = label[:maxWidth-minInput]
 		}
 	}
 
+	room := maxWidth - len(label) - 1 // One column for the cursor
+	if len(input) <= room {
+		return label + input + "_"
+	}
+	if room <= len(ellipsis) {
+		return label + input[len(input)-room:] + "_"
+	}
+	return label + ellipsis + input[len(input)-(room-len(ellipsis)):] + "_"
+}
+
+// RenderCalendarWithSearch renders the calendar with search results
+func (r *Renderer) RenderCalendarWithSearch(cal *models.Calendar, selection *models.Selection, query string, results []models.Event, resultDates []string, upcoming int, selectedIndex int) error {
+	r.terminal.Clear()
+
+	if r.tooSmall() {
+		return r.renderTooSmall()
+	}
+
+	// Render the three months with the decorations that fit
+	if err := r.renderMonths(cal, selection); err != nil {
+		return err
+	}
+
 	// Render search results under the calendar
-	r.renderSearchResults(query, results, selectedIndex)
+	r.renderSearchResults(query, results, upcoming, selectedIndex)
 
 	// Render search key legend
 	r.renderSearchKeyLegend()
@@ -1101,16 +1486,16 @@ func (r *Renderer) RenderCalendarWithSearch(cal *models.Calendar, selection *mod
 	return r.terminal.Flush()
 }
 
-// renderSearchResults renders search results grouped by date under the calendar
-func (r *Renderer) renderSearchResults(query string, results []models.Event, selectedIndex int) {
-	fg, bg := r.terminal.GetDefaultColors()
-
-	// Calculate Y position for search results section
-	searchStartY := 13
+// renderSearchResults renders search results grouped by date under the calendar. When
+// upcoming is not negative, the first upcoming results are listed under an "Upcoming"
+// separator and the rest under a "Past" one.
+func (r *Renderer) renderSearchResults(query string, results []models.Event, upcoming int, selectedIndex int) {
+	// Search results take the place of the events panel
+	searchStartY := r.eventsPanelStartY()
 
 	// Calculate left alignment position to match calendar's left edge
 	width, height := r.terminal.GetSize()
-	totalWidth := 3*r.monthWidth + 2*r.monthSpacing
+	totalWidth := r.layout().totalWidth()
 	startX := (width - totalWidth) / 2
 	searchLeftX := startX + 1
 
@@ -1120,24 +1505,13 @@ func (r *Renderer) renderSearchResults(query string, results []models.Event, sel
 		headerText = "Search results:"
 	}
 
-	var headerFg termbox.Attribute
-	if r.terminal.IsColorSupported() {
-		headerFg = termbox.ColorYellow | termbox.AttrBold
-	} else {
-		headerFg = termbox.AttrBold
-	}
-
-	r.terminal.Print(searchLeftX, searchStartY, headerText, headerFg, bg)
+	headerFg, headerBg := r.style(StyleTitle)
+	r.terminal.Print(searchLeftX, searchStartY, headerText, headerFg, headerBg)
 
 	// Render search results
 	if len(results) == 0 {
-		var noResultsFg termbox.Attribute
-		if r.terminal.IsColorSupported() {
-			noResultsFg = termbox.ColorWhite
-		} else {
-			noResultsFg = fg
-		}
-		r.terminal.Print(searchLeftX, searchStartY+1, "No events found matching your search", noResultsFg, bg)
+		noResultsFg, noResultsBg := r.style(StyleNoEvents)
+		r.terminal.Print(searchLeftX, searchStartY+1, "No events found matching your search", noResultsFg, noResultsBg)
 	} else {
 		// Group results by date and render
 		currentY := searchStartY + 1
@@ -1147,34 +1521,41 @@ func (r *Renderer) renderSearchResults(query string, results []models.Event, sel
 			if currentY >= height-4 {
 				// Too many results to display
 				moreText := fmt.Sprintf("... and %d more results", len(results)-i)
-				var moreFg termbox.Attribute
-				if r.terminal.IsColorSupported() {
-					moreFg = termbox.ColorMagenta
-				} else {
-					moreFg = fg
-				}
-				r.terminal.Print(searchLeftX, currentY, moreText, moreFg, bg)
+				moreFg, moreBg := r.style(StyleMoreEvents)
+				r.terminal.Print(searchLeftX, currentY, moreText, moreFg, moreBg)
 				break
 			}
 
 			eventDateStr := event.Date.Format("2006-01-02")
+			sectionStart := false
+
+			// Show a section separator where the upcoming and past results start
+			if upcoming >= 0 && (i == 0 || i == upcoming) {
+				section := "Upcoming"
+				if i == upcoming {
+					section = "Past"
+				}
+				if currentY > searchStartY+1 {
+					currentY++
+				}
+				sectionFg, sectionBg := r.style(StyleTitle)
+				r.terminal.Print(searchLeftX, currentY, fmt.Sprintf("-- %s --", section), sectionFg, sectionBg)
+				currentY++
+				currentDate = "" // Repeat the date header inside the new section
+				sectionStart = true
+			}
 
 			// Show date header if this is a new date
 			if eventDateStr != currentDate {
 				currentDate = eventDateStr
-				if currentY > searchStartY+1 {
+				if currentY > searchStartY+1 && !sectionStart {
 					currentY++ // Add space between date groups
 				}
 
 				// Format date header
 				dateHeader := event.Date.Format("Monday, January 2, 2006")
-				var dateFg termbox.Attribute
-				if r.terminal.IsColorSupported() {
-					dateFg = termbox.ColorCyan | termbox.AttrBold
-				} else {
-					dateFg = termbox.AttrBold
-				}
-				r.terminal.Print(searchLeftX, currentY, dateHeader, dateFg, bg)
+				dateFg, dateBg := r.style(StyleSection)
+				r.terminal.Print(searchLeftX, currentY, dateHeader, dateFg, dateBg)
 				currentY++
 			}
 
@@ -1187,27 +1568,17 @@ func (r *Renderer) renderSearchResults(query string, results []models.Event, sel
 			if isSelected {
 				// Selected result: use highlighting
 				prefix = "  > "
-				if r.terminal.IsColorSupported() {
-					eventFg = termbox.ColorBlack | termbox.AttrBold
-					eventBg = termbox.ColorYellow // Yellow background for selection
-				} else {
-					eventFg = termbox.ColorDefault | termbox.AttrReverse | termbox.AttrBold
-					eventBg = termbox.ColorDefault
-				}
+				eventFg, eventBg = r.style(StyleSelectedEvent)
 			} else {
 				// Normal result colors
 				prefix = "    "
-				eventBg = bg
-				if r.terminal.IsColorSupported() {
-					eventFg = termbox.ColorWhite
-				} else {
-					eventFg = fg
-				}
+				eventFg, eventBg = r.style(StyleSearchResult)
+				eventFg = r.categoryColor(event, eventFg)
 			}
 
 			// Render event as single line
-			timeStr := event.GetTimeString()
-			description := event.Description
+			timeStr := event.GetTimeLabel()
+			description := r.eventDescription(event)
 			eventText := fmt.Sprintf("%s%s - %s", prefix, timeStr, description)
 
 			// Calculate available width from left position to right margin
@@ -1216,7 +1587,7 @@ func (r *Renderer) renderSearchResults(query string, results []models.Event, sel
 				eventText = eventText[:maxEventWidth-3] + "..."
 			}
 
-			r.terminal.Print(searchLeftX, currentY, eventText, eventFg, eventBg)
+			r.terminal.PrintLinked(searchLeftX, currentY, eventText, eventFg, eventBg)
 
 			// Fill the rest of the line with the background color for selected results
 			if isSelected {
@@ -1235,8 +1606,694 @@ func (r *Renderer) renderSearchKeyLegend() {
 	_, height := r.terminal.GetSize()
 	legendY := height - 2
 
-	fg, bg := r.terminal.GetDefaultColors()
+	fg, bg := r.style(StyleText)
 
-	legend := "↑↓: navigate results  Enter: go to date  Esc: back to calendar  F: search"
+	legend := "↑↓: navigate results  Enter: go to date  A: add on that date  Esc: back to calendar  F: search"
 	r.terminal.PrintCentered(legendY, legend, fg, bg)
 }
+
+// RenderStats renders the local usage statistics view
+func (r *Renderer) RenderStats(tracker *stats.Tracker) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	sectionFg, _ := r.style(StyleSection)
+	instrFg, _ := r.style(StyleInstructions)
+
+	r.terminal.PrintCentered(2, "Usage Statistics", titleFg, bg)
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	leftX := 2
+	y := r.renderGoals(leftX, 6)
+
+	if !tracker.IsEnabled() {
+		r.terminal.PrintCentered(y, "Usage statistics are disabled.", fg, bg)
+		r.terminal.PrintCentered(y+1, "Set \"usage_stats\": true in the configuration file to enable them.", fg, bg)
+		r.terminal.PrintCentered(y+2, "Statistics are stored locally and never leave this machine.", fg, bg)
+		r.terminal.PrintCentered(height-3, "Esc: back to calendar", instrFg, bg)
+		return r.terminal.Flush()
+	}
+
+	// Weekly event activity, most recent week last
+	r.terminal.Print(leftX, y, "Events per week     Created  Edited  Deleted", sectionFg, bg)
+	y++
+	weeks, counts := tracker.RecentWeeks(time.Now(), 6)
+	for i, week := range weeks {
+		line := fmt.Sprintf("%-18s %8d %7d %8d", week, counts[i].Created, counts[i].Edited, counts[i].Deleted)
+		r.terminal.Print(leftX, y, line, fg, bg)
+		y++
+	}
+
+	// Most used keys and views side by side
+	y++
+	columnX := leftX + width/2
+	r.terminal.Print(leftX, y, "Most used keys", sectionFg, bg)
+	r.terminal.Print(columnX, y, "Most visited views", sectionFg, bg)
+	y++
+
+	rows := max(0, height-4-y)
+	keys := tracker.TopKeys(rows)
+	views := tracker.TopViews(rows)
+	for i := 0; i < rows && (i < len(keys) || i < len(views)); i++ {
+		if i < len(keys) {
+			r.terminal.Print(leftX, y+i, fmt.Sprintf("%5d  %s", keys[i].Value, keys[i].Name), fg, bg)
+		}
+		if i < len(views) {
+			r.terminal.Print(columnX, y+i, fmt.Sprintf("%5d  %s", views[i].Value, views[i].Name), fg, bg)
+		}
+	}
+
+	r.terminal.PrintCentered(height-3, "A: add event  Esc: back to calendar", instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// renderGoals lists the progress of the configured goals from row y, e.g.
+// "gym   2/3  this week  ##-", and returns the first row below the list
+func (r *Renderer) renderGoals(x, y int) int {
+	if len(r.config.Goals) == 0 {
+		return y
+	}
+	fg, bg := r.style(StyleText)
+	sectionFg, _ := r.style(StyleSection)
+	doneFg, _ := r.style(StyleToday)
+
+	r.terminal.Print(x, y, "Goals", sectionFg, bg)
+	y++
+	now := time.Now()
+	for _, goal := range r.config.Goals {
+		progress := r.eventManager.GoalProgress(goal, now)
+		period := "this week"
+		if goal.Period == config.GoalMonthly {
+			period = "this month"
+		}
+		line := fmt.Sprintf("%-18s %3d/%-3d %-10s  %s", goal.Name, progress.Done, goal.Target, period, goalBar(progress))
+		if progress.Reached() {
+			r.terminal.Print(x, y, line, doneFg, bg)
+		} else {
+			r.terminal.Print(x, y, line, fg, bg)
+		}
+		y++
+	}
+	return y + 1
+}
+
+// maxGoalBar is the longest goal progress bar; larger targets are cut off
+const maxGoalBar = 20
+
+// goalBar draws one character per event of the target: '#' for each one done
+func goalBar(progress events.GoalProgress) string {
+	target := min(progress.Goal.Target, maxGoalBar)
+	done := min(progress.Done, target)
+	return strings.Repeat("#", done) + strings.Repeat("-", target-done)
+}
+
+// renderGoalsHeader shows the progress of the goals on the top line, e.g.
+// "gym 2/3  reading 1/4", when the goals header is enabled
+func (r *Renderer) renderGoalsHeader() {
+	if !r.config.GoalsHeader || len(r.config.Goals) == 0 {
+		return
+	}
+	now := time.Now()
+	parts := make([]string, len(r.config.Goals))
+	for i, goal := range r.config.Goals {
+		progress := r.eventManager.GoalProgress(goal, now)
+		parts[i] = fmt.Sprintf("%s %d/%d", goal.Name, progress.Done, goal.Target)
+	}
+
+	fg, bg := r.style(StyleInstructions)
+	r.terminal.Print(1, 0, strings.Join(parts, "  "), fg, bg)
+}
+
+// renderInboxHeader shows the number of imported events waiting for review centered
+// on the top line, e.g. "Inbox: 3 (I: review)", and how many of them are in conflict
+func (r *Renderer) renderInboxHeader() {
+	count := len(r.eventManager.UnreviewedEvents())
+	if count == 0 {
+		return
+	}
+
+	fg, bg := r.style(StyleInstructions)
+	text := fmt.Sprintf("Inbox: %d (I: review)", count)
+	if conflicts := len(r.eventManager.Conflicts()); conflicts > 0 {
+		text = fmt.Sprintf("Inbox: %d, %d in conflict (I: review)", count, conflicts)
+	}
+	r.terminal.PrintCentered(0, text, fg, bg)
+}
+
+// RenderActivityLog renders the changes made this session, most recent first
+func (r *Renderer) RenderActivityLog(entries []events.Activity, selectedIndex int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+	undoneFg, _ := r.style(StyleMuted)
+
+	r.terminal.PrintCentered(2, "Activity Log (this session)", titleFg, bg)
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	if len(entries) == 0 {
+		r.terminal.PrintCentered(6, "No changes made in this session", fg, bg)
+		r.terminal.PrintCentered(height-3, "B: backups  Esc: back to calendar", instrFg, bg)
+		return r.terminal.Flush()
+	}
+
+	// Keep the selected entry visible when the log is longer than the screen
+	startY := 6
+	rows := height - 4 - startY
+	if rows < 1 {
+		rows = 1
+	}
+	offset := scrollOffset(len(entries), rows, selectedIndex)
+
+	for i := offset; i < len(entries) && i-offset < rows; i++ {
+		entry := entries[i]
+		event := entry.Subject()
+		line := fmt.Sprintf("%s  %-8s %s %s  %s",
+			entry.At.Format("15:04:05"), entry.Kind, event.GetDateString(), event.GetTimeString(), r.eventDescription(event))
+		if entry.Kind == events.ChangeEdited && entry.Before.Description != entry.After.Description {
+			line += fmt.Sprintf("  (was: %s)", entry.Before.Description)
+		}
+		if entry.Undone {
+			line += "  [undone]"
+		}
+
+		lineFg, lineBg := fg, bg
+		if entry.Undone {
+			lineFg = undoneFg
+		}
+		if i == selectedIndex {
+			lineFg, lineBg = r.style(StyleSelectedEvent)
+		}
+		r.terminal.Print(2, startY+i-offset, line, lineFg, lineBg)
+	}
+
+	r.terminal.PrintCentered(height-3, "J/K: navigate  U: undo selected change  A: add on that date  B: backups  Esc: back to calendar", instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// RenderBookmarks renders the bookmark picker with the selected bookmark highlighted
+func (r *Renderer) RenderBookmarks(bookmarks []state.Bookmark, selectedIndex int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+	dateFg, _ := r.style(StyleEventTime)
+
+	r.terminal.PrintCentered(2, "Bookmarks", titleFg, bg)
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	if len(bookmarks) == 0 {
+		r.terminal.PrintCentered(6, "No bookmarks yet - press M on a date to bookmark it", fg, bg)
+		r.terminal.PrintCentered(height-3, "Esc: back to calendar", instrFg, bg)
+		return r.terminal.Flush()
+	}
+
+	// Keep the selected bookmark visible when the list is longer than the screen
+	startY := 6
+	rows := height - 4 - startY
+	if rows < 1 {
+		rows = 1
+	}
+	offset := scrollOffset(len(bookmarks), rows, selectedIndex)
+
+	for i := offset; i < len(bookmarks) && i-offset < rows; i++ {
+		bookmark := bookmarks[i]
+		y := startY + i - offset
+
+		lineDateFg, lineFg, lineBg := dateFg, fg, bg
+		if i == selectedIndex {
+			lineFg, lineBg = r.style(StyleSelectedEvent)
+			lineDateFg = lineFg
+		}
+
+		r.terminal.Print(2, y, bookmark.Date, lineDateFg, lineBg)
+		r.terminal.Print(14, y, bookmark.Name, lineFg, lineBg)
+	}
+
+	r.terminal.PrintCentered(height-3, "J/K: navigate  Enter: jump to date  A: add on that date  D: delete  Esc: back to calendar", instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// RenderCalendars renders the calendars with whether each is shown, in its own color,
+// and the selected calendar highlighted
+func (r *Renderer) RenderCalendars(selectedIndex int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+
+	r.terminal.PrintCentered(2, "Calendars", titleFg, bg)
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	cfg := r.config
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	startY := 6
+	for i, name := range cfg.CalendarNames() {
+		key, _ := cfg.CalendarKey(name)
+		y := startY + i
+		if y >= height-4 {
+			break
+		}
+
+		mark := "[x]"
+		if r.eventManager.CalendarHidden(key) {
+			mark = "[ ]"
+		}
+		lineFg, lineBg := r.categoryColor(models.Event{Calendar: key}, fg), bg
+		if i == selectedIndex {
+			lineFg, lineBg = r.style(StyleSelectedEvent)
+		}
+		r.terminal.Print(2, y, fmt.Sprintf("%s %s", mark, name), lineFg, lineBg)
+		r.terminal.Print(28, y, cfg.CalendarFilePath(key), instrFg, bg)
+	}
+	if len(cfg.Calendars) == 0 {
+		r.terminal.PrintCentered(height-5, "Add further events files under \"calendars\" in the configuration file", fg, bg)
+	}
+
+	r.terminal.PrintCentered(height-3, "J/K: navigate  X/Enter: show or hide  Esc: back to calendar", instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// RenderBackups renders the backups of the events files with the selected one highlighted
+func (r *Renderer) RenderBackups(backups []storage.Backup, selectedIndex int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+	dateFg, _ := r.style(StyleEventTime)
+
+	r.terminal.PrintCentered(2, "Backups", titleFg, bg)
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	if len(backups) == 0 {
+		r.terminal.PrintCentered(6, "No backups yet - one is taken before each delete, edit or migration", fg, bg)
+		r.terminal.PrintCentered(height-3, "Esc: back to activity log", instrFg, bg)
+		return r.terminal.Flush()
+	}
+
+	// Keep the selected backup visible when the list is longer than the screen
+	startY := 6
+	rows := height - 4 - startY
+	if rows < 1 {
+		rows = 1
+	}
+	offset := scrollOffset(len(backups), rows, selectedIndex)
+
+	for i := offset; i < len(backups) && i-offset < rows; i++ {
+		backup := backups[i]
+		y := startY + i - offset
+
+		lineDateFg, lineFg, lineBg := dateFg, fg, bg
+		if i == selectedIndex {
+			lineFg, lineBg = r.style(StyleSelectedEvent)
+			lineDateFg = lineFg
+		}
+
+		count := "unreadable"
+		if backup.Events >= 0 {
+			count = fmt.Sprintf("%d events", backup.Events)
+		}
+		r.terminal.Print(2, y, backup.ModTime.Format("2006-01-02 15:04"), lineDateFg, lineBg)
+		r.terminal.Print(20, y, fmt.Sprintf("%-12s%s", count, filepath.Base(backup.Path())), lineFg, lineBg)
+	}
+
+	r.terminal.PrintCentered(height-3, "J/K: navigate  Enter: restore  Esc: back to activity log", instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// RenderMessageHistory renders the latest status line messages, most recent first
+func (r *Renderer) RenderMessageHistory(history []messages.Message, selectedIndex int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+	timeFg, _ := r.style(StyleEventTime)
+
+	r.terminal.PrintCentered(2, "Messages", titleFg, bg)
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	if len(history) == 0 {
+		r.terminal.PrintCentered(6, "No messages yet this session", fg, bg)
+		r.terminal.PrintCentered(height-3, "Esc: back to calendar", instrFg, bg)
+		return r.terminal.Flush()
+	}
+
+	// Keep the selected message visible when the list is longer than the screen
+	startY := 6
+	rows := height - 4 - startY
+	if rows < 1 {
+		rows = 1
+	}
+	offset := scrollOffset(len(history), rows, selectedIndex)
+
+	for i := offset; i < len(history) && i-offset < rows; i++ {
+		message := history[i]
+		y := startY + i - offset
+
+		lineTimeFg, lineFg, lineBg := timeFg, fg, bg
+		severityFg, _ := r.style(severityStyle(message.Severity))
+		if i == selectedIndex {
+			lineFg, lineBg = r.style(StyleSelectedEvent)
+			lineTimeFg, severityFg = lineFg, lineFg
+		}
+
+		r.terminal.Print(2, y, message.Posted.Format("15:04:05"), lineTimeFg, lineBg)
+		r.terminal.Print(12, y, fmt.Sprintf("%-8s", message.Severity), severityFg, lineBg)
+		r.terminal.Print(21, y, fitText(message.Text, width-23), lineFg, lineBg)
+	}
+
+	r.terminal.PrintCentered(height-3, "J/K: navigate  Esc: back to calendar", instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// RenderFollowUps renders the flagged events of all dates, oldest first
+func (r *Renderer) RenderFollowUps(flagged []models.Event, selectedIndex int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+	dateFg, _ := r.style(StyleEventTime)
+
+	r.terminal.PrintCentered(2, "Follow-ups", titleFg, bg)
+	r.renderFilterHeader()
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	if len(flagged) == 0 {
+		r.terminal.PrintCentered(6, "Nothing to follow up - press ! on a selected event to flag it", fg, bg)
+		r.terminal.PrintCentered(height-3, "Esc: back to calendar", instrFg, bg)
+		return r.terminal.Flush()
+	}
+
+	// Keep the selected event visible when the list is longer than the screen
+	startY := 6
+	rows := height - 4 - startY
+	if rows < 1 {
+		rows = 1
+	}
+	offset := scrollOffset(len(flagged), rows, selectedIndex)
+
+	for i := offset; i < len(flagged) && i-offset < rows; i++ {
+		event := flagged[i]
+		y := startY + i - offset
+
+		lineDateFg, lineFg, lineBg := dateFg, r.categoryColor(event, fg), bg
+		if i == selectedIndex {
+			lineFg, lineBg = r.style(StyleSelectedEvent)
+			lineDateFg = lineFg
+		}
+
+		r.terminal.Print(2, y, event.GetDateString()+" "+event.GetTimeString(), lineDateFg, lineBg)
+		r.terminal.PrintLinked(21, y, r.eventDescription(event), lineFg, lineBg)
+	}
+
+	r.terminal.PrintCentered(height-3, "J/K: navigate  Enter: open date  !: clear flag  A: add on that date  Esc: back to calendar", instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// RenderReviewQueue renders the imported events waiting for review, oldest first
+func (r *Renderer) RenderReviewQueue(unreviewed []models.Event, selectedIndex int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+	dateFg, _ := r.style(StyleEventTime)
+
+	r.terminal.PrintCentered(2, fmt.Sprintf("Inbox: %d to review", len(unreviewed)), titleFg, bg)
+	r.renderFilterHeader()
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	if len(unreviewed) == 0 {
+		r.terminal.PrintCentered(6, "Nothing to review - imported events show up here until accepted", fg, bg)
+		r.terminal.PrintCentered(height-3, "Esc: back to calendar", instrFg, bg)
+		return r.terminal.Flush()
+	}
+
+	// Keep the selected event visible when the queue is longer than the screen
+	startY := 6
+	rows := height - 4 - startY
+	if rows < 1 {
+		rows = 1
+	}
+	offset := scrollOffset(len(unreviewed), rows, selectedIndex)
+
+	for i := offset; i < len(unreviewed) && i-offset < rows; i++ {
+		event := unreviewed[i]
+		y := startY + i - offset
+
+		lineDateFg, lineFg, lineBg := dateFg, r.categoryColor(event, fg), bg
+		if i == selectedIndex {
+			lineFg, lineBg = r.style(StyleSelectedEvent)
+			lineDateFg = lineFg
+		}
+
+		description := r.eventDescription(event)
+		if event.Category != "" {
+			description += " [" + event.Category + "]"
+		}
+		if event.Conflict != nil {
+			description += " [conflict]"
+		}
+		r.terminal.Print(2, y, event.GetDateString()+" "+event.GetTimeLabel(), lineDateFg, lineBg)
+		r.terminal.PrintLinked(21, y, description, lineFg, lineBg)
+	}
+
+	r.terminal.PrintCentered(height-3, "J/K: navigate  Enter: accept or resolve  1-9: accept in category  0: accept without category  d: delete  Esc: back to calendar", instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// RenderConflict shows the calendar's and the source's version of a conflicted event
+// side by side, a row per field, marking the version each field is taken from
+func (r *Renderer) RenderConflict(event models.Event, selectedField int, fromSource map[events.ConflictField]bool) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+	mutedFg, _ := r.style(StyleMuted)
+
+	r.terminal.PrintCentered(2, "Conflict: "+event.Description, titleFg, bg)
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+	if event.Conflict == nil {
+		r.terminal.PrintCentered(6, "Nothing to resolve", fg, bg)
+		r.terminal.PrintCentered(height-3, "Esc: back to inbox", instrFg, bg)
+		return r.terminal.Flush()
+	}
+
+	// A label column, then half of the rest for each version
+	const labelX, labelWidth = 2, 13
+	columnWidth := (width - labelX - labelWidth - 2) / 2
+	if columnWidth < 4 {
+		columnWidth = 4
+	}
+	localX := labelX + labelWidth
+	sourceX := localX + columnWidth + 2
+	r.terminal.Print(localX, 6, "  Calendar", instrFg, bg)
+	r.terminal.Print(sourceX, 6, "  Source", instrFg, bg)
+
+	for i, field := range events.ConflictFields {
+		y := 8 + i
+		labelFg, labelBg := fg, bg
+		if i == selectedField {
+			labelFg, labelBg = r.style(StyleSelectedEvent)
+		}
+		r.terminal.Print(labelX, y, fitText(field.String(), labelWidth-1), labelFg, labelBg)
+
+		local, remote := field.Value(event), field.Value(*event.Conflict)
+		localMark, sourceMark := "* ", "  "
+		localFg, sourceFg := fg, mutedFg
+		if fromSource[field] {
+			localMark, sourceMark = "  ", "* "
+			localFg, sourceFg = mutedFg, fg
+		}
+		if local == remote {
+			localFg, sourceFg = mutedFg, mutedFg
+		}
+		r.terminal.Print(localX, y, fitText(localMark+local, columnWidth), localFg, bg)
+		r.terminal.Print(sourceX, y, fitText(sourceMark+remote, columnWidth), sourceFg, bg)
+	}
+
+	r.terminal.PrintCentered(height-3, "J/K: field  H/L: calendar/source  Enter: resolve  </>: keep all  Esc: back", instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// fitText cuts text to at most width characters
+func fitText(text string, width int) string {
+	runes := []rune(text)
+	if width < 0 || len(runes) <= width {
+		return text
+	}
+	return string(runes[:width])
+}
+
+// helpKeys lists the keys of the calendar view on the help screen
+var helpKeys = [][2]string{
+	{"h/j/k/l, arrows", "Move the selection"},
+	{"B/N, PgUp/PgDn", "Previous/next month"},
+	{"c, gg, Home", "Back to today"},
+	{"g, :", "Go to a date, e.g. 15 mar, next fri, +30d"},
+	{"Enter", "Events of the selected day"},
+	{"[, ]", "Scroll the events below the calendar"},
+	{"A", "Add an event"},
+	{"E", "Edit an event"},
+	{"d, dd", "Delete an event"},
+	{"Shift+D", "Hour-by-hour day view"},
+	{"1-9, 0", "Set or clear the category"},
+	{"!", "Flag an event for follow-up"},
+	{"@", "Remind before an event"},
+	{"O", "Follow-up list"},
+	{"I", "Review imported events"},
+	{"R", "Sync with CalDAV"},
+	{"+/-, >/<", "Move an event by a day/week"},
+	{"U", "Undo the latest change"},
+	{"F", "Search"},
+	{"F1-F8, F9", "Quick filters, clear them"},
+	{"W", "Highlight days with a free evening"},
+	{"m, Shift+G", "Bookmark a day, bookmarks"},
+	{"Shift+M", "Move an event to a date"},
+	{"V, Y", "Mark a range, copy events"},
+	{"P", "Paste events, one per line"},
+	{"S", "Statistics"},
+	{"Shift+L", "Activity log"},
+	{"Shift+C", "Show or hide calendars"},
+	{"\"", "Latest messages"},
+	{"T", "Next color theme (:theme NAME picks one)"},
+	{"Z", "Focus mode: only months and events"},
+	{"?", "This help"},
+	{"Q, Esc", "Quit"},
+}
+
+// RenderHelp renders the keys of the calendar view, in two columns when they fit
+func (r *Renderer) RenderHelp() error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	keyFg, _ := r.style(StyleEventTime)
+	instrFg, _ := r.style(StyleInstructions)
+
+	r.terminal.PrintCentered(2, "Keys", titleFg, bg)
+	if r.focus {
+		r.terminal.PrintCentered(3, "Focus mode is on: the legend and headers are hidden. Z shows them again", instrFg, bg)
+	}
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	const keyWidth, columnWidth = 18, 48
+	columns := 1
+	if width >= 2*columnWidth {
+		columns = 2
+	}
+	rows := (len(helpKeys) + columns - 1) / columns
+	leftX := max(2, (width-columns*columnWidth)/2)
+	for i, entry := range helpKeys {
+		x, y := leftX+(i/rows)*columnWidth, 6+i%rows
+		if y >= height-4 {
+			continue
+		}
+		r.terminal.Print(x, y, entry[0], keyFg, bg)
+		r.terminal.Print(x+keyWidth, y, entry[1], fg, bg)
+	}
+
+	r.terminal.PrintCentered(height-4, shiftedKeysText(), instrFg, bg)
+	r.terminal.PrintCentered(height-3, "Enter: take the tour  Esc: back to calendar", instrFg, bg)
+
+	return r.terminal.Flush()
+}
+
+// shiftedKeysText names the uppercase letters of ShiftedKeys, the only letters whose
+// case matters, e.g. "Letters work in either case except Shift+C, Shift+D and Shift+G"
+func shiftedKeysText() string {
+	var keys []string
+	for ch := range ShiftedKeys {
+		keys = append(keys, "Shift+"+string(ch))
+	}
+	sort.Strings(keys)
+	last := len(keys) - 1
+	return "Letters work in either case except " + strings.Join(keys[:last], ", ") + " and " + keys[last]
+}
+
+// RenderBanner renders the startup banner sections below a header with the current date and time
+func (r *Renderer) RenderBanner(sections []banner.Section, now time.Time) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	sectionFg, _ := r.style(StyleSection)
+	instrFg, _ := r.style(StyleInstructions)
+
+	r.terminal.PrintCentered(2, now.Format("Monday, January 2 2006   15:04"), titleFg, bg)
+	for i := 0; i < width; i++ {
+		r.terminal.SetCell(i, 4, '-', instrFg, bg)
+	}
+
+	leftX := 4
+	y := 6
+	lastRow := height - 4
+	for _, section := range sections {
+		if y > lastRow {
+			break
+		}
+		r.terminal.Print(leftX, y, section.Title, sectionFg, bg)
+		y++
+		for _, line := range section.Lines {
+			if y > lastRow {
+				break
+			}
+			r.terminal.Print(leftX+2, y, line, fg, bg)
+			y++
+		}
+		y++ // Blank line between sections
+	}
+
+	r.terminal.PrintCentered(height-2, "Press any key to open the calendar  A: add an event for today", instrFg, bg)
+	return r.terminal.Flush()
+}
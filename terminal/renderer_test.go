@@ -1,12 +1,20 @@
 package terminal
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"go-ascii-calendar/calendar"
 	"go-ascii-calendar/config"
 	"go-ascii-calendar/events"
+	"go-ascii-calendar/messages"
 	"go-ascii-calendar/models"
+	"go-ascii-calendar/stats"
+
+	"github.com/nsf/termbox-go"
 )
 
 func TestNewRenderer(t *testing.T) {
@@ -301,6 +309,34 @@ func TestRenderer_RenderInputPrompt(t *testing.T) {
 	}
 }
 
+func TestRenderer_DatePreview(t *testing.T) {
+	cfg := config.DefaultConfig()
+	renderer := NewRenderer(NewTerminal(), events.NewManager(), cfg)
+	base := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC) // A Friday
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"next fri", "= Fri 2025-08-22"},
+		{"eom", "= Sun 2025-08-31"},
+		{"tom", "Tab: tomorrow = Sat 2025-08-16"},
+	}
+	for _, tt := range tests {
+		if got := renderer.DatePreview(tt.input, base); got != tt.expected {
+			t.Errorf("DatePreview(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+	if got := renderer.DatePreview("someday", base); !strings.HasPrefix(got, "?") {
+		t.Errorf("DatePreview(someday) = %q, want a hint", got)
+	}
+
+	cfg.DateFormat = "DD.MM.YYYY"
+	if got := renderer.DatePreview("2w", base); got != "= Fri 29.08.2025" {
+		t.Errorf("DatePreview(2w) = %q, want the configured date format", got)
+	}
+}
+
 func TestRenderer_RenderInlineInput(t *testing.T) {
 	terminal := NewTerminal()
 	eventManager := events.NewManager()
@@ -323,12 +359,12 @@ func TestRenderer_RenderInlineInput(t *testing.T) {
 			expectPanic: false,
 		},
 		{
-			name:        "Top-left position (may panic)",
+			name:        "Top-left position",
 			x:           0,
 			y:           0,
 			prompt:      "Enter:",
 			input:       "Test",
-			expectPanic: true, // This appears to cause slice bounds issues
+			expectPanic: false,
 		},
 		{
 			name:        "Valid middle position",
@@ -339,20 +375,20 @@ func TestRenderer_RenderInlineInput(t *testing.T) {
 			expectPanic: false,
 		},
 		{
-			name:        "Negative coordinates (expected to panic)",
+			name:        "Negative coordinates (clamped)",
 			x:           -1,
 			y:           -1,
 			prompt:      "Test:",
 			input:       "Input",
-			expectPanic: true,
+			expectPanic: false,
 		},
 		{
-			name:        "Large coordinates (may panic)",
+			name:        "Large coordinates (clamped)",
 			x:           100,
 			y:           50,
 			prompt:      "Description:",
 			input:       "Event description",
-			expectPanic: true,
+			expectPanic: false,
 		},
 	}
 
@@ -483,7 +519,7 @@ func TestRenderer_SearchRendering(t *testing.T) {
 		}
 	}()
 
-	err := renderer.RenderCalendarWithSearch(cal, selection, "team", searchResults, resultDates, 0)
+	err := renderer.RenderCalendarWithSearch(cal, selection, "team", searchResults, resultDates, -1, 0)
 
 	if err != nil {
 		t.Errorf("RenderCalendarWithSearch() unexpected error: %v", err)
@@ -492,6 +528,430 @@ func TestRenderer_SearchRendering(t *testing.T) {
 	t.Log("RenderCalendarWithSearch() completed successfully")
 }
 
+func TestRenderer_VisibleEventCount(t *testing.T) {
+	tests := []struct {
+		name         string
+		height       int
+		maxPerDay    int
+		totalEvents  int
+		reservedRows int
+		expected     int
+	}{
+		{"Few events fit", 24, 10, 3, 0, 3},
+		{"Panel rows limit on 24 lines", 24, 10, 12, 0, 7},
+		{"Exactly fills panel", 24, 10, 8, 0, 8},
+		{"Config cap below panel rows", 40, 10, 15, 0, 10},
+		{"Config cap not exceeded", 40, 10, 10, 0, 10},
+		{"Unlimited config uses panel rows", 40, 0, 50, 0, 23},
+		{"Reserved new-event row", 24, 10, 12, 1, 6},
+		{"Reserved row with few events", 24, 10, 2, 1, 2},
+		{"Tiny terminal", 10, 10, 5, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terminal := NewTerminal()
+			terminal.width, terminal.height = 80, tt.height
+			cfg := config.DefaultConfig()
+			cfg.MaxEventsPerDay = tt.maxPerDay
+			renderer := NewRenderer(terminal, events.NewManager(), cfg)
+
+			result := renderer.visibleEventCount(tt.totalEvents, tt.reservedRows)
+			if result != tt.expected {
+				t.Errorf("visibleEventCount(%d, %d) = %d, want %d", tt.totalEvents, tt.reservedRows, result, tt.expected)
+			}
+
+			// Shown events plus the "more" line must never spill into the legend
+			used := result + tt.reservedRows
+			if result < tt.totalEvents {
+				used++
+			}
+			if used > renderer.eventsPanelRows() && tt.totalEvents > 0 && renderer.eventsPanelRows() > 0 {
+				t.Errorf("panel uses %d rows, only %d available", used, renderer.eventsPanelRows())
+			}
+		})
+	}
+}
+
+func TestRenderer_EventDescription(t *testing.T) {
+	renderer := NewRenderer(NewTerminal(), events.NewManager(), config.DefaultConfig())
+
+	tests := []struct {
+		event models.Event
+		want  string
+	}{
+		{models.Event{Description: "Standup"}, "Standup"},
+		{models.Event{Description: "Standup", Category: "work"}, "[work] Standup"},
+		{models.Event{Description: "Pay rent", Priority: "A"}, "(A) Pay rent"},
+		{models.Event{Description: "Pay rent", Category: "home", Priority: "B"}, "(B) [home] Pay rent"},
+	}
+
+	for _, tt := range tests {
+		if got := renderer.eventDescription(tt.event); got != tt.want {
+			t.Errorf("eventDescription() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestRenderer_MonthHeaderStyle(t *testing.T) {
+	cfg := config.DefaultConfig()
+	renderer := NewRenderer(NewTerminal(), events.NewManager(), cfg)
+
+	cal := models.NewCalendar()
+	cal.CurrentMonth = time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local)
+	selection := models.NewSelection(cal)
+	selection.SelectedDate = time.Date(2025, 7, 31, 0, 0, 0, 0, time.Local)
+
+	july := time.Date(2025, 7, 1, 0, 0, 0, 0, time.Local)
+	august := time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local)
+	if style := renderer.monthHeaderStyle(july, selection); style != StyleFocusedMonth {
+		t.Errorf("Month holding the selection = %s, want %s", style, StyleFocusedMonth)
+	}
+	if style := renderer.monthHeaderStyle(august, selection); style != StyleDimmedMonth {
+		t.Errorf("Other month = %s, want %s", style, StyleDimmedMonth)
+	}
+
+	cfg.FocusMonth = false
+	if style := renderer.monthHeaderStyle(july, selection); style != StyleMonthHeader {
+		t.Errorf("With focus_month off = %s, want %s", style, StyleMonthHeader)
+	}
+}
+
+func TestRenderer_IsDimmedPastDay(t *testing.T) {
+	cfg := config.DefaultConfig()
+	renderer := NewRenderer(NewTerminal(), events.NewManager(), cfg)
+
+	today := calendar.NormalizeDate(time.Now())
+	lastMonth := calendar.GetFirstDayOfMonth(today).AddDate(0, 0, -1)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	if renderer.isDimmedPastDay(lastMonth, false) {
+		t.Error("Past days should not be dimmed by default")
+	}
+
+	cfg.PastDays = config.PastDaysConfig{Dim: config.PastDaysMonth, Intensity: config.PastDimLight}
+	if today.Day() > 1 {
+		earlier := calendar.GetFirstDayOfMonth(today)
+		if !renderer.isDimmedPastDay(earlier, false) {
+			t.Errorf("%v should be dimmed in the current month", earlier)
+		}
+		if renderer.isDimmedPastDay(earlier, true) {
+			t.Error("Light dimming should leave past event days alone")
+		}
+	}
+	if renderer.isDimmedPastDay(lastMonth, false) {
+		t.Error("Days of the previous month should only be dimmed with \"all\"")
+	}
+	if renderer.isDimmedPastDay(today, false) || renderer.isDimmedPastDay(tomorrow, false) {
+		t.Error("Today and later days should never be dimmed")
+	}
+
+	cfg.PastDays = config.PastDaysConfig{Dim: config.PastDaysAll, Intensity: config.PastDimStrong}
+	if !renderer.isDimmedPastDay(lastMonth, true) {
+		t.Error("Strong dimming of all past days should dim a past event day of the previous month")
+	}
+}
+
+func TestRenderer_ScrollEvents(t *testing.T) {
+	terminal := NewTerminal()
+	terminal.width, terminal.height = 80, 24
+	manager := events.NewManagerWithConfig(&config.Config{Ephemeral: true})
+	renderer := NewRenderer(terminal, manager, config.DefaultConfig())
+
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	for hour := 8; hour < 20; hour++ {
+		manager.AddEvent(date, fmt.Sprintf("%02d:00", hour), "Meeting")
+	}
+	visible := renderer.visibleEventCount(12, 0)
+
+	if renderer.ScrollEvents(date, -1) {
+		t.Error("Scrolling up from the first event should be rejected")
+	}
+	for i := 0; i < 12-visible; i++ {
+		if !renderer.ScrollEvents(date, 1) {
+			t.Fatalf("Scrolling down %d should reach a hidden event", i+1)
+		}
+	}
+	if renderer.ScrollEvents(date, 1) {
+		t.Error("Scrolling down past the last event should be rejected")
+	}
+	if offset := renderer.paneScroll(date, 12, visible); offset != 12-visible {
+		t.Errorf("paneScroll() = %d, want %d", offset, 12-visible)
+	}
+
+	// Another date starts at the top
+	if offset := renderer.paneScroll(date.AddDate(0, 0, 1), 12, visible); offset != 0 {
+		t.Errorf("paneScroll() of another date = %d, want 0", offset)
+	}
+}
+
+func TestMoreEventsText(t *testing.T) {
+	tests := []struct {
+		above, below int
+		expected     string
+	}{
+		{0, 3, "... and 3 more events"},
+		{2, 3, "... 2 above, 3 more below"},
+		{4, 0, "... 4 more events above"},
+	}
+	for _, tt := range tests {
+		if got := moreEventsText(tt.above, tt.below); got != tt.expected {
+			t.Errorf("moreEventsText(%d, %d) = %q, want %q", tt.above, tt.below, got, tt.expected)
+		}
+	}
+}
+
+func TestFitLegend(t *testing.T) {
+	keys := []string{"B/N: month", "A: add", "E: edit"}
+	tests := []struct {
+		width    int
+		expected string
+	}{
+		{80, "B/N: month  A: add  E: edit  Q: quit"},
+		{27, "B/N: month  A: add  Q: quit"},
+		{26, "B/N: month  Q: quit"},
+		{5, "Q: quit"},
+	}
+	for _, tt := range tests {
+		if got := fitLegend(keys, "Q: quit", tt.width); got != tt.expected {
+			t.Errorf("fitLegend(%d) = %q, want %q", tt.width, got, tt.expected)
+		}
+	}
+	for _, width := range []int{60, 80, 120} {
+		if got := fitLegend(calendarLegend, "?: help  Q: quit", width); len(got) > width {
+			t.Errorf("Calendar legend at width %d is %d wide: %q", width, len(got), got)
+		}
+	}
+}
+
+func TestRenderer_CountdownText(t *testing.T) {
+	renderer := NewRenderer(NewTerminal(), events.NewManager(), config.DefaultConfig())
+	today := time.Date(2025, 8, 29, 22, 45, 0, 0, time.Local)
+	renderer.now = func() time.Time { return today }
+
+	tests := []struct {
+		date     time.Time
+		expected string
+	}{
+		{time.Date(2025, 8, 29, 0, 0, 0, 0, time.Local), "today"},
+		{time.Date(2025, 8, 30, 0, 0, 0, 0, time.Local), "tomorrow"},
+		{time.Date(2025, 8, 28, 0, 0, 0, 0, time.Local), "yesterday"},
+		{time.Date(2025, 9, 10, 0, 0, 0, 0, time.Local), "in 12 days"},
+		{time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local), "28 days ago"},
+	}
+	for _, tt := range tests {
+		if got := renderer.countdownText(tt.date); got != tt.expected {
+			t.Errorf("countdownText(%s) = %q, want %q", tt.date.Format("2006-01-02"), got, tt.expected)
+		}
+	}
+}
+
+func TestRenderer_TodayInOtherMonths(t *testing.T) {
+	renderer := NewRenderer(NewTerminal(), events.NewManager(), config.DefaultConfig())
+	selection := &models.Selection{SelectedDate: time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)}
+	todayFg, _ := renderer.style(StyleToday)
+
+	// Today is highlighted in the previous and next month grids, not only in the selected month
+	for _, today := range []time.Time{
+		time.Date(2025, 7, 20, 10, 0, 0, 0, time.Local),
+		time.Date(2025, 9, 5, 10, 0, 0, 0, time.Local),
+	} {
+		renderer.now = func() time.Time { return today }
+		month := calendar.GetFirstDayOfMonth(today)
+		found := false
+		for _, week := range renderer.monthCells(month, selection) {
+			for _, cell := range week {
+				if cell.text == fmt.Sprintf("%2d", today.Day()) {
+					found = true
+					if cell.fg != todayFg {
+						t.Errorf("Today %s in %s has colors %v, want those of today", today.Format("Jan 2"), month.Format("January"), cell.fg)
+					}
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Day %d not found in the grid of %s", today.Day(), month.Format("January"))
+		}
+	}
+}
+
+func TestRenderer_OverbookedDay(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Workload.MaxEvents = 1
+	manager := events.NewManagerWithConfig(&config.Config{Ephemeral: true, Workload: cfg.Workload})
+	renderer := NewRenderer(NewTerminal(), manager, cfg)
+
+	date := calendar.NormalizeDate(time.Now()).AddDate(0, 0, 2)
+	manager.AddEvent(date, "09:00", "Standup")
+	selection := &models.Selection{SelectedDate: date.AddDate(0, 0, 1)}
+
+	eventFg, _ := renderer.style(StyleEventDay)
+	if fg, _, _ := renderer.getDayAttributes(date, selection); fg != eventFg {
+		t.Errorf("A day within the workload limits should look like an event day")
+	}
+
+	manager.AddEvent(date, "12:00", "Lunch")
+	overbookedFg, _ := renderer.style(StyleOverbooked)
+	if fg, _, _ := renderer.getDayAttributes(date, selection); fg != overbookedFg {
+		t.Errorf("A day over the workload limits should get the overbooked style")
+	}
+}
+
+func TestRenderer_HasFreeEvening(t *testing.T) {
+	cfg := config.DefaultConfig()
+	manager := events.NewManagerWithConfig(&config.Config{Ephemeral: true})
+	renderer := NewRenderer(NewTerminal(), manager, cfg)
+
+	today := calendar.NormalizeDate(time.Now())
+	day := func(offset int) time.Time { return today.AddDate(0, 0, offset) }
+	manager.AddEvent(day(1), "12:00", "Lunch")
+	manager.AddEventWithDuration(day(2), "17:00", "Workshop", 2*time.Hour)
+	manager.AddEventWithDuration(day(3), "17:00", "Call", time.Hour)
+	manager.AddEvent(day(4), "19:30", "Dinner")
+
+	if renderer.hasFreeEvening(day(1)) {
+		t.Error("Free evenings should only be highlighted once switched on")
+	}
+
+	renderer.SetFreeEvenings(true)
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"no events", day(5), true},
+		{"lunch only", day(1), true},
+		{"event lasting into the evening", day(2), false},
+		{"event ending at the evening", day(3), true},
+		{"evening event", day(4), false},
+		{"past day", day(-1), false},
+	}
+	for _, tt := range tests {
+		if got := renderer.hasFreeEvening(tt.date); got != tt.want {
+			t.Errorf("hasFreeEvening(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	cfg.FreeEveningFrom = "20:00"
+	if !renderer.hasFreeEvening(day(2)) {
+		t.Error("With evenings from 20:00 an event ending at 19:00 should leave the evening free")
+	}
+	cfg.FreeEveningFrom = "late"
+	if renderer.hasFreeEvening(day(2)) {
+		t.Error("An invalid free_evening_from should fall back to 18:00")
+	}
+}
+
+func TestScrollOffset(t *testing.T) {
+	tests := []struct {
+		name          string
+		total         int
+		visible       int
+		selectedIndex int
+		expected      int
+	}{
+		{"Everything fits", 5, 10, 4, 0},
+		{"Selection in first window", 20, 7, 6, 0},
+		{"Selection just below window", 20, 7, 7, 1},
+		{"Selection at last event", 20, 7, 19, 13},
+		{"Selection past end is clamped", 20, 7, 25, 13},
+		{"No visible rows", 20, 0, 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := scrollOffset(tt.total, tt.visible, tt.selectedIndex); result != tt.expected {
+				t.Errorf("scrollOffset(%d, %d, %d) = %d, want %d", tt.total, tt.visible, tt.selectedIndex, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEventListRows(t *testing.T) {
+	allDay := models.Event{Description: "Trip", AllDay: true}
+	timed := models.Event{Description: "Standup"}
+
+	tests := []struct {
+		name     string
+		events   []models.Event
+		expected []int
+	}{
+		{"Timed events only", []models.Event{timed, timed}, []int{0, 1}},
+		{"All-day events only", []models.Event{allDay}, []int{allDayHeadingRow, 0}},
+		{"Both sections", []models.Event{allDay, allDay, timed}, []int{allDayHeadingRow, 0, 1, timedHeadingRow, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := eventListRows(tt.events)
+			if len(rows) != len(tt.expected) {
+				t.Fatalf("eventListRows() = %v, want %v", rows, tt.expected)
+			}
+			for i := range rows {
+				if rows[i] != tt.expected[i] {
+					t.Fatalf("eventListRows() = %v, want %v", rows, tt.expected)
+				}
+			}
+		})
+	}
+
+	if row := eventListRow([]int{allDayHeadingRow, 0, timedHeadingRow, 1}, 1); row != 3 {
+		t.Errorf("eventListRow() = %d, want 3", row)
+	}
+}
+
+func TestInlineInputText(t *testing.T) {
+	tests := []struct {
+		name     string
+		prompt   string
+		input    string
+		maxWidth int
+		expected string
+	}{
+		{"Fits", "Time:", "10:30", 40, "> Time: 10:30_"},
+		{"Scrolls long input", "Description:", "a very long description here", 30, "> Description: ...iption here_"},
+		{"Shortens prompt when cramped", "Description:", "abcdefghij", 12, "> Des...hij_"},
+		{"No room", "Time:", "10:30", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := inlineInputText(tt.prompt, tt.input, tt.maxWidth)
+			if result != tt.expected {
+				t.Errorf("inlineInputText() = %q, want %q", result, tt.expected)
+			}
+			if len(result) > tt.maxWidth && tt.maxWidth > 0 {
+				t.Errorf("inlineInputText() length %d exceeds width %d", len(result), tt.maxWidth)
+			}
+		})
+	}
+}
+
+func TestRenderer_ClampInlineInputPosition(t *testing.T) {
+	renderer := NewRenderer(NewTerminal(), events.NewManager(), config.DefaultConfig())
+
+	tests := []struct {
+		name      string
+		x, y      int
+		expectedX int
+		expectedY int
+	}{
+		{"Inside screen", 3, 14, 3, 14},
+		{"Below legend moves up", 3, 30, 3, 21},
+		{"Negative row", 3, -2, 3, 0},
+		{"Too close to right edge", 70, 14, 0, 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := renderer.clampInlineInputPosition(tt.x, tt.y, 80, 24)
+			if x != tt.expectedX || y != tt.expectedY {
+				t.Errorf("clampInlineInputPosition(%d, %d) = (%d, %d), want (%d, %d)", tt.x, tt.y, x, y, tt.expectedX, tt.expectedY)
+			}
+		})
+	}
+}
+
 // Benchmark tests for performance
 func BenchmarkRenderer_GetDayAttributes(b *testing.B) {
 	terminal := NewTerminal()
@@ -520,3 +980,99 @@ func BenchmarkRenderer_RenderMessage(b *testing.B) {
 		renderer.RenderMessage(message, false)
 	}
 }
+
+func TestRenderer_RejectAudible(t *testing.T) {
+	var out bytes.Buffer
+	cfg := config.DefaultConfig()
+	renderer := NewRenderer(&Terminal{out: &out}, events.NewManager(), cfg)
+
+	cfg.Bell = config.BellAudible
+	renderer.Reject()
+	if out.String() != "\a" {
+		t.Errorf("Reject() with audible bell wrote %q, want BEL", out.String())
+	}
+
+	out.Reset()
+	cfg.Bell = config.BellOff
+	renderer.Reject()
+	if out.Len() != 0 {
+		t.Errorf("Reject() with the bell off wrote %q, want nothing", out.String())
+	}
+}
+
+func TestRenderer_DayCategories(t *testing.T) {
+	manager := events.NewManagerWithConfig(&config.Config{Ephemeral: true})
+	if err := manager.LoadEvents(); err != nil {
+		t.Fatalf("LoadEvents() failed: %v", err)
+	}
+	date := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	imported := []models.Event{
+		{Date: date, Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Standup", Category: "Work"},
+		{Date: date, Time: time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC), Description: "Lunch"},
+		{Date: date, Time: time.Date(0, 1, 1, 14, 0, 0, 0, time.UTC), Description: "Review", Category: "Work"},
+		{Date: date, Time: time.Date(0, 1, 1, 18, 0, 0, 0, time.UTC), Description: "Gym", Category: "Personal"},
+	}
+	if _, err := manager.ImportEvents(imported); err != nil {
+		t.Fatalf("ImportEvents() failed: %v", err)
+	}
+
+	renderer := NewRenderer(NewTerminal(), manager, config.DefaultConfig())
+	got := renderer.dayCategories(date)
+	want := []string{"Work", "", "Personal"}
+	if len(got) != len(want) {
+		t.Fatalf("dayCategories() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("dayCategories()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if got := renderer.dayCategories(date.AddDate(0, 0, 1)); len(got) != 0 {
+		t.Errorf("dayCategories() without events = %q, want none", got)
+	}
+}
+
+func TestRenderer_PostedMessageTakesThemeColors(t *testing.T) {
+	f := newSnapshotFixture(t, 100, 24, func(cfg *config.Config) {
+		cfg.UITheme.WarningFg = "white|bold"
+		cfg.UITheme.WarningBg = "blue"
+	})
+	if err := f.renderer.RenderCalendar(f.cal, f.selection); err != nil {
+		t.Fatalf("RenderCalendar() failed: %v", err)
+	}
+
+	text := strings.Repeat("The events file is large ", 5)
+	f.renderer.RenderPostedMessage(messages.Message{Text: text, Severity: messages.Warning})
+	if err := f.renderer.terminal.Flush(); err != nil {
+		t.Fatalf("Flush() failed: %v", err)
+	}
+
+	// The whole line takes the warning colors, and the message is cut to fit it
+	for _, x := range []int{0, 50, 99} {
+		if cell := f.screen.Cell(x, 23); cell.Fg != termbox.ColorWhite|termbox.AttrBold || cell.Bg != termbox.ColorBlue {
+			t.Errorf("Cell %d of the status line = %v on %v, want bold white on blue", x, cell.Fg, cell.Bg)
+		}
+	}
+	lines := strings.Split(f.screen.Text(), "\n")
+	if line := strings.TrimSpace(lines[23]); len(line) != 98 || !strings.HasSuffix(line, "...") {
+		t.Errorf("Status line = %q, want the message cut to 98 characters", line)
+	}
+}
+
+func TestRenderer_RenderStats_ShortTerminal(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Goals = []config.Goal{{Name: "gym", Period: "week", Target: 3}, {Name: "reading", Period: "month", Target: 4}}
+	tracker := stats.NewTracker(t.TempDir(), true)
+	tracker.RecordKey("Next month")
+	tracker.RecordView("search")
+
+	screen := NewHeadlessScreen(80, 16)
+	renderer := NewRenderer(NewHeadlessTerminal(screen), events.NewManagerWithConfig(cfg), cfg)
+	if err := renderer.RenderStats(tracker); err != nil {
+		t.Fatalf("RenderStats() failed: %v", err)
+	}
+	if !strings.Contains(screen.Text(), "Usage Statistics") {
+		t.Errorf("Statistics should render on a short terminal:\n%s", screen.Text())
+	}
+}
@@ -0,0 +1,128 @@
+package terminal
+
+import (
+	"io"
+	"strings"
+
+	"github.com/nsf/termbox-go"
+)
+
+// ScreenDriver is the grid of cells the terminal draws on: termbox on a real terminal,
+// or a HeadlessScreen that renders without one
+type ScreenDriver interface {
+	SetCell(x, y int, ch rune, fg, bg termbox.Attribute)
+	Clear()
+	Flush() error
+	Size() (width, height int)
+}
+
+// termboxScreen draws on the real terminal through termbox
+type termboxScreen struct{}
+
+func (termboxScreen) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	termbox.SetCell(x, y, ch, fg, bg)
+}
+
+func (termboxScreen) Clear() {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+}
+
+func (termboxScreen) Flush() error {
+	return termbox.Flush()
+}
+
+func (termboxScreen) Size() (int, int) {
+	return termbox.Size()
+}
+
+// keySource is a screen that also delivers the keys typed on it
+type keySource interface {
+	PollEvent() termbox.Event
+}
+
+// HeadlessScreen keeps the cells of a fixed-size screen in memory, so views can be
+// rendered and read back as text without a terminal, e.g. for snapshot tests. Keys
+// queued with Type are read by the terminal's PollEvent.
+type HeadlessScreen struct {
+	width, height int
+	cells         []termbox.Cell
+	keys          []termbox.Event
+}
+
+// NewHeadlessScreen creates a blank headless screen of the given size
+func NewHeadlessScreen(width, height int) *HeadlessScreen {
+	s := &HeadlessScreen{width: width, height: height, cells: make([]termbox.Cell, width*height)}
+	s.Clear()
+	return s
+}
+
+// NewHeadlessTerminal creates a terminal drawing on a headless screen; escape
+// sequences such as window titles and links are discarded
+func NewHeadlessTerminal(screen *HeadlessScreen) *Terminal {
+	t := &Terminal{out: io.Discard, screen: screen}
+	t.updateSize()
+	return t
+}
+
+// SetCell sets a cell; cells outside the screen are ignored
+func (s *HeadlessScreen) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	if x < 0 || x >= s.width || y < 0 || y >= s.height {
+		return
+	}
+	s.cells[y*s.width+x] = termbox.Cell{Ch: ch, Fg: fg, Bg: bg}
+}
+
+// Clear blanks every cell
+func (s *HeadlessScreen) Clear() {
+	for i := range s.cells {
+		s.cells[i] = termbox.Cell{Ch: ' '}
+	}
+}
+
+// Flush does nothing; the cells are always current
+func (s *HeadlessScreen) Flush() error {
+	return nil
+}
+
+// Size returns the width and height of the screen
+func (s *HeadlessScreen) Size() (int, int) {
+	return s.width, s.height
+}
+
+// Type queues the characters of text as key presses; "\n" presses Enter, "\t" Tab and
+// "\x1b" Esc
+func (s *HeadlessScreen) Type(text string) {
+	for _, ch := range text {
+		s.keys = append(s.keys, termbox.Event{Type: termbox.EventKey, Ch: ch})
+	}
+}
+
+// PollEvent returns the next queued key. Once the queue is empty it returns Esc, so a
+// prompt waiting for more keys is cancelled instead of blocking.
+func (s *HeadlessScreen) PollEvent() termbox.Event {
+	if len(s.keys) == 0 {
+		return termbox.Event{Type: termbox.EventKey, Key: termbox.KeyEsc}
+	}
+	event := s.keys[0]
+	s.keys = s.keys[1:]
+	return event
+}
+
+// Cell returns the cell at x, y
+func (s *HeadlessScreen) Cell(x, y int) termbox.Cell {
+	return s.cells[y*s.width+x]
+}
+
+// Text returns the characters of the screen, one line per row without trailing spaces
+func (s *HeadlessScreen) Text() string {
+	var b strings.Builder
+	for y := 0; y < s.height; y++ {
+		row := make([]rune, s.width)
+		for x := range row {
+			row[x] = s.cells[y*s.width+x].Ch
+		}
+		b.WriteString(strings.TrimRight(string(row), " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
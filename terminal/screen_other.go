@@ -0,0 +1,34 @@
+//go:build !windows
+
+package terminal
+
+import (
+	"fmt"
+	"io"
+)
+
+// writeTitle sets the terminal window title with an OSC escape sequence
+func writeTitle(out io.Writer, title string) {
+	fmt.Fprintf(out, "\x1b]0;%s\x07", title)
+}
+
+// pushTitle saves the current window title on the terminal's title stack
+func pushTitle(out io.Writer) {
+	fmt.Fprint(out, "\x1b[22;0t")
+}
+
+// popTitle restores the window title saved by pushTitle
+func popTitle(out io.Writer) {
+	fmt.Fprint(out, "\x1b[23;0t")
+}
+
+// leaveAlternateScreen switches back to the main screen buffer that termbox left on Init
+func leaveAlternateScreen(out io.Writer) {
+	fmt.Fprint(out, "\x1b[?1049l")
+}
+
+// writeHyperlink writes text as an OSC 8 link to url at the cell (x, y), saving and
+// restoring the cursor position and attributes around it
+func writeHyperlink(out io.Writer, x, y int, url, text string) {
+	fmt.Fprintf(out, "\x1b7\x1b[%d;%dH\x1b]8;;%s\x1b\\%s\x1b]8;;\x1b\\\x1b8", y+1, x+1, url, text)
+}
@@ -0,0 +1,130 @@
+package terminal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nsf/termbox-go"
+)
+
+// countingScreen is a headless screen counting the cells passed to it
+type countingScreen struct {
+	*HeadlessScreen
+	set, clears int
+}
+
+func (s *countingScreen) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
+	s.set++
+	s.HeadlessScreen.SetCell(x, y, ch, fg, bg)
+}
+
+func (s *countingScreen) Clear() {
+	s.clears++
+	s.HeadlessScreen.Clear()
+}
+
+func TestTerminal_FlushOnlyChangedCells(t *testing.T) {
+	screen := &countingScreen{HeadlessScreen: NewHeadlessScreen(20, 5)}
+	term := &Terminal{out: &bytes.Buffer{}, screen: screen}
+	term.updateSize()
+
+	draw := func(selected int) {
+		term.Clear()
+		for day := 0; day < 5; day++ {
+			fg := termbox.ColorDefault
+			if day == selected {
+				fg = termbox.AttrReverse
+			}
+			term.Print(day*3, 1, "1"+string(rune('0'+day)), fg, termbox.ColorDefault)
+		}
+		if err := term.Flush(); err != nil {
+			t.Fatalf("Flush() failed: %v", err)
+		}
+	}
+
+	// The first frame is drawn in full
+	draw(0)
+	if screen.clears != 1 || screen.set != 100 {
+		t.Errorf("First flush cleared %d times and set %d cells, want 1 and all 100", screen.clears, screen.set)
+	}
+
+	// Moving the selection only redraws the cells of the two days
+	screen.set = 0
+	draw(1)
+	if screen.clears != 1 || screen.set != 4 {
+		t.Errorf("Moving the selection cleared %d times and set %d cells, want 1 and 4", screen.clears, screen.set)
+	}
+	if cell := screen.Cell(3, 1); cell.Fg != termbox.AttrReverse {
+		t.Errorf("Newly selected day has colors %v, want reversed", cell.Fg)
+	}
+	if text := screen.Text(); !strings.Contains(text, "10 11 12 13 14") {
+		t.Errorf("Screen = %q, want all days", text)
+	}
+
+	// An unchanged frame sets nothing, and a new screen is drawn in full again
+	screen.set = 0
+	draw(1)
+	if screen.set != 0 {
+		t.Errorf("Unchanged frame set %d cells, want none", screen.set)
+	}
+	term.shown = nil
+	draw(1)
+	if screen.clears != 2 || screen.set != 100 {
+		t.Errorf("Flush of an unknown screen cleared %d times and set %d cells, want 2 and 100", screen.clears, screen.set)
+	}
+}
+
+func TestTerminal_FlushRewritesChangedLinks(t *testing.T) {
+	var out bytes.Buffer
+	term := &Terminal{out: &out, screen: NewHeadlessScreen(40, 5), hyperlinks: true}
+	term.updateSize()
+
+	draw := func(status string) {
+		term.Clear()
+		term.PrintLinked(0, 0, "Call https://meet.example.com/abc", termbox.ColorDefault, termbox.ColorDefault)
+		term.Print(0, 4, status, termbox.ColorDefault, termbox.ColorDefault)
+		term.Flush()
+	}
+
+	draw("2 events")
+	if !strings.Contains(out.String(), "https://meet.example.com/abc") {
+		t.Fatalf("First flush wrote %q, want the link", out.String())
+	}
+
+	// A frame changing other cells leaves the link alone
+	out.Reset()
+	draw("3 events")
+	if out.Len() != 0 {
+		t.Errorf("Flush with the link unchanged wrote %q, want nothing", out.String())
+	}
+
+	// A message drawn over the link replaces it for good
+	term.Print(0, 0, "Saved all the changes", termbox.ColorDefault, termbox.ColorDefault)
+	term.Flush()
+	if out.Len() != 0 || len(term.shownLinks) != 0 {
+		t.Errorf("Flush of a message over the link wrote %q and kept %v, want neither", out.String(), term.shownLinks)
+	}
+
+	// The next frame draws the link again
+	draw("3 events")
+	if !strings.Contains(out.String(), "https://meet.example.com/abc") {
+		t.Errorf("Flush after the message wrote %q, want the link again", out.String())
+	}
+}
+
+func TestHeadlessScreen_Type(t *testing.T) {
+	screen := NewHeadlessScreen(10, 2)
+	term := NewHeadlessTerminal(screen)
+	screen.Type("a\n")
+
+	if event := term.PollEvent(); event.Ch != 'a' {
+		t.Errorf("First key = %+v, want a", event)
+	}
+	if event := term.PollEvent(); event.Key != termbox.KeyEnter {
+		t.Errorf("Second key = %+v, want Enter", event)
+	}
+	if event := term.PollEvent(); event.Key != termbox.KeyEsc {
+		t.Errorf("Key after the queue = %+v, want Esc", event)
+	}
+}
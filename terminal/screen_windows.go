@@ -0,0 +1,50 @@
+//go:build windows
+
+package terminal
+
+import (
+	"io"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32             = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleTitleW = kernel32.NewProc("SetConsoleTitleW")
+	procGetConsoleTitleW = kernel32.NewProc("GetConsoleTitleW")
+
+	// savedTitle holds the console title from before the first change
+	savedTitle []uint16
+)
+
+// writeTitle sets the console window title
+func writeTitle(out io.Writer, title string) {
+	ptr, err := syscall.UTF16PtrFromString(title)
+	if err != nil {
+		return
+	}
+	procSetConsoleTitleW.Call(uintptr(unsafe.Pointer(ptr)))
+}
+
+// pushTitle remembers the current console title
+func pushTitle(out io.Writer) {
+	buf := make([]uint16, 1024)
+	n, _, _ := procGetConsoleTitleW.Call(uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	savedTitle = buf[:n+1]
+}
+
+// popTitle restores the console title remembered by pushTitle
+func popTitle(out io.Writer) {
+	if len(savedTitle) == 0 {
+		return
+	}
+	procSetConsoleTitleW.Call(uintptr(unsafe.Pointer(&savedTitle[0])))
+}
+
+// leaveAlternateScreen is a no-op: termbox always draws into its own console
+// screen buffer on Windows, which is discarded when the application exits
+func leaveAlternateScreen(out io.Writer) {}
+
+// writeHyperlink is a no-op: termbox draws through the console API on Windows, which
+// has no hyperlinks
+func writeHyperlink(out io.Writer, x, y int, url, text string) {}
@@ -0,0 +1,83 @@
+package terminal
+
+import (
+	"strings"
+	"time"
+
+	"go-ascii-calendar/calendar"
+	"go-ascii-calendar/models"
+)
+
+// ScreensaverDays is how many days ahead the screensaver ticker looks for events
+const ScreensaverDays = 7
+
+// tickerSeparator separates the events of the ticker, and its end from its start
+const tickerSeparator = "   *   "
+
+// TickerText joins the events still to come at now into one line for the screensaver
+// ticker, e.g. "Today 18:00 Dinner   *   Mon 10:00 Quarterly review"; events that
+// already started today are left out
+func TickerText(upcoming []models.Event, now time.Time) string {
+	today := calendar.NormalizeDate(now)
+	var entries []string
+	for _, event := range upcoming {
+		date := calendar.NormalizeDate(event.Date)
+		if date.Equal(today) && !event.AllDay && event.Time.Hour()*60+event.Time.Minute() < now.Hour()*60+now.Minute() {
+			continue
+		}
+
+		day := date.Format("Mon")
+		switch calendar.DaysBetween(today, date) {
+		case 0:
+			day = "Today"
+		case 1:
+			day = "Tomorrow"
+		}
+		entries = append(entries, day+" "+event.GetTimeLabel()+" "+event.Description)
+	}
+	return strings.Join(entries, tickerSeparator)
+}
+
+// tickerWindow returns width characters of the endlessly repeated ticker text,
+// starting offset characters in
+func tickerWindow(text string, offset, width int) string {
+	loop := []rune(text + tickerSeparator)
+	window := make([]rune, width)
+	for i := range window {
+		window[i] = loop[(offset+i)%len(loop)]
+	}
+	return string(window)
+}
+
+// RenderScreensaver shows a large clock with the date below it and a ticker of the
+// upcoming events scrolled offset characters to the left; a text narrower than the
+// screen stands still
+func (r *Renderer) RenderScreensaver(now time.Time, upcoming []models.Event, offset int) error {
+	r.terminal.Clear()
+
+	width, height := r.terminal.GetSize()
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+
+	clock := BannerText(now.Format("15:04"))
+	top := max(1, height/2-4)
+	for i, row := range clock {
+		r.terminal.PrintCentered(top+i, row, titleFg, bg)
+	}
+	r.terminal.PrintCentered(top+4, now.Format("Monday, January 2 2006"), fg, bg)
+
+	ticker := TickerText(upcoming, now)
+	tickerY := min(top+7, height-4)
+	switch {
+	case ticker == "":
+		r.terminal.PrintCentered(tickerY, "No upcoming events", instrFg, bg)
+	case len([]rune(ticker)) <= width-2:
+		r.terminal.PrintCentered(tickerY, ticker, fg, bg)
+	default:
+		r.terminal.Print(0, tickerY, tickerWindow(ticker, offset, width), fg, bg)
+	}
+
+	r.terminal.PrintCentered(height-2, "Press any key to return", instrFg, bg)
+	return r.terminal.Flush()
+}
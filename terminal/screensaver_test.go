@@ -0,0 +1,46 @@
+package terminal
+
+import (
+	"testing"
+	"time"
+
+	"go-ascii-calendar/models"
+)
+
+func TestTickerText(t *testing.T) {
+	day := time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+	at := func(hour int) time.Time { return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC) }
+	upcoming := []models.Event{
+		{Date: day, Time: at(0), AllDay: true, Description: "Sailing"},
+		{Date: day, Time: at(9), Description: "Standup"},
+		{Date: day, Time: at(18), Description: "Dinner"},
+		{Date: day.AddDate(0, 0, 1), Time: at(8), Description: "Run"},
+		{Date: day.AddDate(0, 0, 3), Time: at(10), Description: "Review"},
+	}
+
+	got := TickerText(upcoming, day.Add(10*time.Hour))
+	want := "Today all day Sailing   *   Today 18:00 Dinner   *   Tomorrow 08:00 Run   *   Mon 10:00 Review"
+	if got != want {
+		t.Errorf("TickerText() = %q, want %q", got, want)
+	}
+	if got := TickerText(nil, day); got != "" {
+		t.Errorf("TickerText() without events = %q, want empty", got)
+	}
+}
+
+func TestTickerWindow(t *testing.T) {
+	tests := []struct {
+		offset int
+		want   string
+	}{
+		{0, "Run   *   R"},
+		{3, "   *   Run "},
+		{10, "Run   *   R"},
+		{12, "n   *   Run"},
+	}
+	for _, tt := range tests {
+		if got := tickerWindow("Run", tt.offset, 11); got != tt.want {
+			t.Errorf("tickerWindow(%d) = %q, want %q", tt.offset, got, tt.want)
+		}
+	}
+}
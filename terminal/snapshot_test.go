@@ -0,0 +1,223 @@
+package terminal
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ascii-calendar/config"
+	"go-ascii-calendar/events"
+	"go-ascii-calendar/holidays"
+	"go-ascii-calendar/models"
+)
+
+// The golden screens in testdata/snapshots pin the text of each view at several
+// terminal sizes. After an intended layout change, review the differences and
+// regenerate them with: go test ./terminal -run Snapshot -update
+var update = flag.Bool("update", false, "rewrite the golden screens in testdata/snapshots")
+
+// snapshotDate is the selected date of the fixture, a Friday
+var snapshotDate = time.Date(2025, 8, 15, 0, 0, 0, 0, time.Local)
+
+// snapshotToday is today in the fixture, so countdowns to the selected date stay put
+var snapshotToday = time.Date(2025, 8, 3, 9, 30, 0, 0, time.Local)
+
+// snapshotFixture holds a renderer over fixed events on a headless screen
+type snapshotFixture struct {
+	screen    *HeadlessScreen
+	renderer  *Renderer
+	manager   *events.Manager
+	cal       *models.Calendar
+	selection *models.Selection
+}
+
+// newSnapshotFixture renders on a headless screen of the given size with the default
+// configuration, changed by configure when it is not nil
+func newSnapshotFixture(t *testing.T, width, height int, configure func(*config.Config)) *snapshotFixture {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Ephemeral = true
+	if configure != nil {
+		configure(cfg)
+	}
+
+	manager := events.NewManagerWithConfig(cfg)
+	day := func(offset int) time.Time { return snapshotDate.AddDate(0, 0, offset) }
+	fixture := []struct {
+		date        time.Time
+		time        string
+		description string
+		duration    time.Duration
+	}{
+		{day(0), "09:00", "Standup", 15 * time.Minute},
+		{day(0), "12:30", "Lunch with Sam", time.Hour},
+		{day(0), "18:00", "Dinner at the harbour", 2 * time.Hour},
+		{day(-3), "14:00", "Dentist", 0},
+		{day(3), "10:00", "Quarterly review", 3 * time.Hour},
+		{day(20), "08:15", "Flight to Lisbon", 0},
+		{day(-30), "19:00", "Book club", 0},
+	}
+	for _, event := range fixture {
+		if err := manager.AddEventWithDuration(event.date, event.time, event.description, event.duration); err != nil {
+			t.Fatalf("AddEventWithDuration() failed: %v", err)
+		}
+	}
+	if _, err := manager.ToggleFlag(manager.GetEventsForDate(day(3))[0]); err != nil {
+		t.Fatalf("ToggleFlag() failed: %v", err)
+	}
+	if err := manager.AddMultiDayEvent(day(-1), day(1), "", "Sailing weekend", true); err != nil {
+		t.Fatalf("AddMultiDayEvent() failed: %v", err)
+	}
+	imported := models.Event{Date: day(5), Time: time.Date(0, 1, 1, 16, 0, 0, 0, time.UTC), Description: "Imported webinar", Unreviewed: true}
+	if _, err := manager.ImportEvents([]models.Event{imported}); err != nil {
+		t.Fatalf("ImportEvents() failed: %v", err)
+	}
+
+	screen := NewHeadlessScreen(width, height)
+	cal := &models.Calendar{CurrentMonth: time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local)}
+	renderer := NewRenderer(NewHeadlessTerminal(screen), manager, cfg)
+	renderer.now = func() time.Time { return snapshotToday }
+	return &snapshotFixture{
+		screen:    screen,
+		renderer:  renderer,
+		manager:   manager,
+		cal:       cal,
+		selection: &models.Selection{SelectedDate: snapshotDate, Calendar: cal},
+	}
+}
+
+// snapshotSizes are the terminal sizes most views are rendered at: a common default,
+// a large window and a tmux pane too narrow for three months
+var snapshotSizes = [][2]int{{80, 24}, {120, 40}, {40, 30}}
+
+func TestSnapshots(t *testing.T) {
+	tests := []struct {
+		name      string
+		sizes     [][2]int
+		configure func(*config.Config)
+		render    func(f *snapshotFixture) error
+	}{
+		{"calendar", append(snapshotSizes, [2]int{60, 24}, [2]int{MinWidth, MinHeight}, [2]int{MinWidth - 1, MinHeight}), nil,
+			func(f *snapshotFixture) error { return f.renderer.RenderCalendar(f.cal, f.selection) }},
+		{"calendar_decorated", [][2]int{{120, 40}, {80, 24}}, func(cfg *config.Config) {
+			cfg.Decorations = config.Decorations{MonthBanner: true, Borders: true, Separators: true, WeekTotals: true}
+		}, func(f *snapshotFixture) error { return f.renderer.RenderCalendar(f.cal, f.selection) }},
+		{"calendar_overbooked", [][2]int{{80, 24}}, func(cfg *config.Config) { cfg.Workload.MaxMinutes = 180 },
+			func(f *snapshotFixture) error { return f.renderer.RenderCalendar(f.cal, f.selection) }},
+		{"calendar_monday", [][2]int{{80, 24}}, func(cfg *config.Config) { cfg.WeekStartDay = config.StartMonday },
+			func(f *snapshotFixture) error { return f.renderer.RenderCalendar(f.cal, f.selection) }},
+		{"calendar_holidays", [][2]int{{80, 24}}, func(cfg *config.Config) { cfg.HolidayCountry = "FR" },
+			func(f *snapshotFixture) error {
+				holidayCalendar, err := holidays.Load(f.renderer.config)
+				if err != nil {
+					return err
+				}
+				f.renderer.SetHolidays(holidayCalendar)
+				return f.renderer.RenderCalendar(f.cal, f.selection)
+			}},
+		{"calendar_focus", [][2]int{{80, 24}, {MinWidth, MinHeight - 4}}, func(cfg *config.Config) { cfg.FocusMode = true },
+			func(f *snapshotFixture) error { return f.renderer.RenderCalendar(f.cal, f.selection) }},
+		{"event_selection", snapshotSizes, nil,
+			func(f *snapshotFixture) error {
+				return f.renderer.RenderCalendarWithEventSelection(f.cal, f.selection, 1)
+			}},
+		{"event_list", append(snapshotSizes, [2]int{60, 10}), nil, func(f *snapshotFixture) error {
+			return f.renderer.RenderEventList(snapshotDate, f.manager.GetEventsForDate(snapshotDate), 2)
+		}},
+		{"day_view", snapshotSizes, nil, func(f *snapshotFixture) error {
+			return f.renderer.RenderDayView(snapshotDate, f.manager.GetEventsForDate(snapshotDate), 12, -1)
+		}},
+		{"search", snapshotSizes, nil, func(f *snapshotFixture) error {
+			results := f.manager.SearchEvents("d")
+			var dates []string
+			for _, event := range results {
+				if date := event.GetDateString(); len(dates) == 0 || dates[len(dates)-1] != date {
+					dates = append(dates, date)
+				}
+			}
+			return f.renderer.RenderCalendarWithSearch(f.cal, f.selection, "d", results, dates, -1, 1)
+		}},
+		{"follow_ups", snapshotSizes, nil, func(f *snapshotFixture) error {
+			return f.renderer.RenderFollowUps(f.manager.FlaggedEvents(), 0)
+		}},
+		{"review_queue", snapshotSizes, nil, func(f *snapshotFixture) error {
+			return f.renderer.RenderReviewQueue(f.manager.UnreviewedEvents(), 0)
+		}},
+		{"conflict", snapshotSizes, nil, func(f *snapshotFixture) error {
+			remote := models.Event{Date: snapshotDate.AddDate(0, 0, 3), Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), Description: "Pay rent", Priority: "A"}
+			local := models.Event{Date: snapshotDate.AddDate(0, 0, 1), Time: remote.Time, Description: "Pay rent", Priority: "C", Conflict: &remote}
+			return f.renderer.RenderConflict(local, 3, map[events.ConflictField]bool{events.FieldPriority: true})
+		}},
+		{"event_details", snapshotSizes, nil, func(f *snapshotFixture) error {
+			event := f.manager.GetEventsForDate(snapshotDate)[2]
+			event.Category, event.Reminder = "Personal", 30*time.Minute
+			event.Notes = "Table for four, ask for the terrace when booking so we can watch the boats.\n\nMenu: https://example.com/harbour/menu"
+			return f.renderer.RenderEventDetails(event, 0)
+		}},
+		{"help", snapshotSizes, nil, func(f *snapshotFixture) error { return f.renderer.RenderHelp() }},
+		{"screensaver", snapshotSizes, nil, func(f *snapshotFixture) error {
+			now := snapshotDate.Add(10*time.Hour + 30*time.Minute)
+			return f.renderer.RenderScreensaver(now, f.manager.GetEventsInDateRange(snapshotDate, snapshotDate.AddDate(0, 0, ScreensaverDays)), 5)
+		}},
+		{"paste_preview", snapshotSizes, nil, func(f *snapshotFixture) error {
+			lines := f.manager.ParsePaste("mon 09:00 Planning\nnonsense\nfri 18:00 Dinner at the harbour\n+2w 10:00 Offsite", snapshotDate)
+			return f.renderer.RenderPastePreview(lines, 1)
+		}},
+	}
+
+	for _, tt := range tests {
+		for _, size := range tt.sizes {
+			name := fmt.Sprintf("%s_%dx%d", tt.name, size[0], size[1])
+			t.Run(name, func(t *testing.T) {
+				f := newSnapshotFixture(t, size[0], size[1], tt.configure)
+				if err := tt.render(f); err != nil {
+					t.Fatalf("Rendering failed: %v", err)
+				}
+				checkSnapshot(t, name, f.screen.Text())
+			})
+		}
+	}
+}
+
+// checkSnapshot compares a rendered screen with its golden file, or rewrites the file
+// with -update
+func checkSnapshot(t *testing.T, name, got string) {
+	t.Helper()
+	golden := filepath.Join("testdata", "snapshots", name+".txt")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatalf("Failed to create the snapshot directory: %v", err)
+		}
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", golden, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read %s (create it with -update): %v", golden, err)
+	}
+	if got == string(want) {
+		return
+	}
+
+	gotLines, wantLines := strings.Split(got, "\n"), strings.Split(string(want), "\n")
+	for i := 0; i < max(len(gotLines), len(wantLines)); i++ {
+		var gotLine, wantLine string
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if gotLine != wantLine {
+			t.Errorf("Screen differs from %s at row %d:\n got: %q\nwant: %q\n\nFull screen:\n%s", golden, i, gotLine, wantLine, got)
+			return
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package terminal
+
+import (
+	"go-ascii-calendar/config"
+
+	"github.com/nsf/termbox-go"
+)
+
+// StyleName identifies a semantic style, such as the look of today's date or of a title.
+// Renderers ask for styles by name, so themes can change without touching them.
+type StyleName string
+
+const (
+	StyleText          StyleName = "text"           // Plain text and key legends
+	StyleTitle         StyleName = "title"          // View titles and panel headers
+	StyleSection       StyleName = "section"        // Headings inside a view, e.g. search result dates
+	StyleSeparator     StyleName = "separator"      // Horizontal rules below titles
+	StyleInstructions  StyleName = "instructions"   // Key hints and the status bar
+	StyleMonthHeader   StyleName = "month_header"   // "August 2025"
+	StyleFocusedMonth  StyleName = "focused_month"  // Header of the month holding the selection
+	StyleDimmedMonth   StyleName = "dimmed_month"   // Headers of the other months
+	StyleDayHeader     StyleName = "day_header"     // "Su Mo Tu ..."
+	StyleRegularDay    StyleName = "regular_day"    // Day cells without special state
+	StyleToday         StyleName = "today"          // Today's day cell
+	StyleSelected      StyleName = "selected"       // Selected day cell
+	StyleSelectedToday StyleName = "selected_today" // Selected day cell that is also today
+	StyleEventDay      StyleName = "event_day"      // Day cells with events
+	StylePastDay       StyleName = "past_day"       // Dimmed day cells before today
+	StyleFreeEvening   StyleName = "free_evening"   // Day cells with a free evening, when highlighted
+	StyleOverbooked    StyleName = "overbooked"     // Day cells and notes of days over the workload limits
+	StyleHoliday       StyleName = "holiday"        // Day cells and names of public holidays
+	StyleEventTime     StyleName = "event_time"     // Event times in the event list
+	StyleEventText     StyleName = "event_text"     // Event lines
+	StyleSelectedEvent StyleName = "selected_event" // Highlighted event or list entry
+	StyleNoEvents      StyleName = "no_events"      // "No events scheduled"
+	StyleMoreEvents    StyleName = "more_events"    // "... and X more events"
+	StyleMuted         StyleName = "muted"          // De-emphasized entries, e.g. undone changes
+	StyleWarning       StyleName = "warning"        // Warning messages
+	StyleError         StyleName = "error"          // Error messages
+	StyleSuccess       StyleName = "success"        // Success messages
+	StyleInput         StyleName = "input"          // Text input lines
+	StyleSearchResult  StyleName = "search_result"  // Search result lines
+)
+
+// Style is the foreground and background attribute pair of a semantic style
+type Style struct {
+	Fg termbox.Attribute
+	Bg termbox.Attribute
+}
+
+// themeStyle builds a style from theme color strings, falling back to another pair
+type themeStyle struct {
+	fg, bg                 string
+	fallbackFg, fallbackBg string
+	extraFg                termbox.Attribute // Attributes added to the parsed foreground
+}
+
+// monochromeStyles are used when the terminal does not support colors
+var monochromeStyles = map[StyleName]Style{
+	StyleTitle:         {termbox.AttrBold, termbox.ColorDefault},
+	StyleSection:       {termbox.AttrBold, termbox.ColorDefault},
+	StyleMonthHeader:   {termbox.AttrBold, termbox.ColorDefault},
+	StyleToday:         {termbox.ColorDefault | termbox.AttrBold, termbox.ColorDefault},
+	StyleSelected:      {termbox.ColorDefault | termbox.AttrReverse, termbox.ColorDefault},
+	StyleSelectedToday: {termbox.ColorDefault | termbox.AttrBold | termbox.AttrReverse, termbox.ColorDefault},
+	StyleEventTime:     {termbox.AttrBold, termbox.ColorDefault},
+	StylePastDay:       {termbox.ColorDefault | termbox.AttrDim, termbox.ColorDefault},
+	StyleFreeEvening:   {termbox.ColorDefault | termbox.AttrReverse, termbox.ColorDefault},
+	StyleOverbooked:    {termbox.ColorDefault | termbox.AttrBold | termbox.AttrUnderline, termbox.ColorDefault},
+	StyleHoliday:       {termbox.ColorDefault | termbox.AttrCursive, termbox.ColorDefault},
+	StyleSelectedEvent: {termbox.ColorDefault | termbox.AttrReverse | termbox.AttrBold, termbox.ColorDefault},
+	StyleInput:         {termbox.ColorDefault | termbox.AttrReverse | termbox.AttrBold, termbox.ColorDefault},
+	StyleWarning:       {termbox.AttrBold, termbox.ColorDefault},
+}
+
+// StyleResolver maps semantic style names to terminal attributes for the current theme
+type StyleResolver struct {
+	color  bool
+	theme  config.ColorTheme // Resolves palette names in colors
+	styles map[StyleName]Style
+}
+
+// NewStyleResolver creates a resolver for a theme; without color support the
+// monochrome attribute styles are used regardless of the theme
+func NewStyleResolver(theme config.ColorTheme, color bool) *StyleResolver {
+	resolver := &StyleResolver{color: color}
+	resolver.SetTheme(theme)
+	return resolver
+}
+
+// SetTheme rebuilds all styles from a theme. Colors that fail to parse, or name a
+// palette entry that does not resolve, fall back to the default theme.
+func (s *StyleResolver) SetTheme(theme config.ColorTheme) {
+	s.theme = theme
+	d := config.DefaultTheme
+	// Themes written before holidays had a color of their own keep them distinct
+	holidayFg, holidayBg := theme.HolidayFg, theme.HolidayBg
+	if holidayFg == "" {
+		holidayFg, holidayBg = d.HolidayFg, d.HolidayBg
+	}
+	// Likewise for warnings and muted entries, which used fixed colors before
+	warningFg, warningBg := theme.WarningFg, theme.WarningBg
+	if warningFg == "" {
+		warningFg, warningBg = d.WarningFg, d.WarningBg
+	}
+	mutedFg, mutedBg := theme.MutedFg, theme.MutedBg
+	if mutedFg == "" {
+		mutedFg, mutedBg = d.MutedFg, d.MutedBg
+	}
+	definitions := map[StyleName]themeStyle{
+		StyleText:          {"default", "default", "default", "default", 0},
+		StyleTitle:         {theme.EventHeaderFg, theme.EventHeaderBg, d.EventHeaderFg, d.EventHeaderBg, 0},
+		StyleSection:       {theme.InstructionsFg, theme.InstructionsBg, d.InstructionsFg, d.InstructionsBg, termbox.AttrBold},
+		StyleSeparator:     {theme.InstructionsFg, theme.InstructionsBg, d.InstructionsFg, d.InstructionsBg, 0},
+		StyleInstructions:  {theme.InstructionsFg, theme.InstructionsBg, d.InstructionsFg, d.InstructionsBg, 0},
+		StyleMonthHeader:   {theme.MonthHeaderFg, theme.MonthHeaderBg, d.MonthHeaderFg, d.MonthHeaderBg, 0},
+		StyleDayHeader:     {theme.DayHeaderFg, theme.DayHeaderBg, d.DayHeaderFg, d.DayHeaderBg, 0},
+		StyleRegularDay:    {theme.RegularDayFg, theme.RegularDayBg, d.RegularDayFg, d.RegularDayBg, 0},
+		StyleToday:         {theme.TodayFg, theme.TodayBg, d.TodayFg, d.TodayBg, 0},
+		StyleSelected:      {theme.SelectedFg, theme.SelectedBg, d.SelectedFg, d.SelectedBg, 0},
+		StyleSelectedToday: {theme.SelectedTodayFg, theme.SelectedTodayBg, d.SelectedTodayFg, d.SelectedTodayBg, 0},
+		StyleEventDay:      {theme.EventDayFg, theme.EventDayBg, d.EventDayFg, d.EventDayBg, 0},
+		StylePastDay:       {theme.PastDayFg, theme.PastDayBg, d.PastDayFg, d.PastDayBg, 0},
+		StyleFreeEvening:   {theme.SuccessFg, theme.SuccessBg, d.SuccessFg, d.SuccessBg, termbox.AttrReverse},
+		StyleOverbooked:    {theme.ErrorFg, theme.ErrorBg, d.ErrorFg, d.ErrorBg, termbox.AttrBold},
+		StyleHoliday:       {holidayFg, holidayBg, d.HolidayFg, d.HolidayBg, 0},
+		StyleEventTime:     {theme.EventDayFg, theme.EventTextBg, d.EventDayFg, d.EventTextBg, termbox.AttrBold},
+		StyleEventText:     {theme.EventTextFg, theme.EventTextBg, d.EventTextFg, d.EventTextBg, 0},
+		StyleSelectedEvent: {theme.SelectedEventFg, theme.SelectedEventBg, d.SelectedEventFg, d.SelectedEventBg, 0},
+		StyleNoEvents:      {theme.NoEventsFg, theme.NoEventsBg, d.NoEventsFg, d.NoEventsBg, 0},
+		StyleMoreEvents:    {theme.MoreEventsFg, theme.MoreEventsBg, d.MoreEventsFg, d.MoreEventsBg, 0},
+		StyleMuted:         {mutedFg, mutedBg, d.MutedFg, d.MutedBg, 0},
+		StyleWarning:       {warningFg, warningBg, d.WarningFg, d.WarningBg, 0},
+		StyleError:         {theme.ErrorFg, theme.ErrorBg, d.ErrorFg, d.ErrorBg, 0},
+		StyleSuccess:       {theme.SuccessFg, theme.SuccessBg, d.SuccessFg, d.SuccessBg, 0},
+		StyleInput:         {theme.InputFg, theme.InputBg, d.InputFg, d.InputBg, 0},
+		StyleSearchResult:  {theme.SearchResultFg, theme.SearchResultBg, d.SearchResultFg, d.SearchResultBg, 0},
+	}
+
+	styles := make(map[StyleName]Style, len(definitions))
+	for name, definition := range definitions {
+		if !s.color {
+			style, ok := monochromeStyles[name]
+			if !ok {
+				style = Style{termbox.ColorDefault, termbox.ColorDefault}
+			}
+			styles[name] = style
+			continue
+		}
+
+		styles[name] = Style{
+			Fg: parseColorOr(s.resolve(definition.fg), definition.fallbackFg) | definition.extraFg,
+			Bg: parseColorOr(s.resolve(definition.bg), definition.fallbackBg),
+		}
+	}
+
+	// Focus styles are derived from the month header, so every theme gets them
+	header := styles[StyleMonthHeader]
+	styles[StyleFocusedMonth] = Style{header.Fg | termbox.AttrUnderline, header.Bg}
+	styles[StyleDimmedMonth] = Style{header.Fg&^termbox.AttrBold | termbox.AttrDim, header.Bg}
+
+	s.styles = styles
+}
+
+// Style returns the attributes of a semantic style; unknown names resolve to plain text
+func (s *StyleResolver) Style(name StyleName) Style {
+	if style, ok := s.styles[name]; ok {
+		return style
+	}
+	return Style{termbox.ColorDefault, termbox.ColorDefault}
+}
+
+// Color parses a color string such as a category color, which may use the theme's
+// palette, returning fallback when it is invalid or the terminal has no color support
+func (s *StyleResolver) Color(colorStr string, fallback termbox.Attribute) termbox.Attribute {
+	if !s.color {
+		return fallback
+	}
+	color, err := config.ParseColor(s.resolve(colorStr))
+	if err != nil {
+		return fallback
+	}
+	return color
+}
+
+// resolve replaces a palette name in a color string with its color; a palette cycle
+// gives an invalid color
+func (s *StyleResolver) resolve(colorStr string) string {
+	resolved, err := s.theme.ResolveColor(colorStr)
+	if err != nil {
+		return "invalid"
+	}
+	return resolved
+}
+
+// parseColorOr parses a color string, falling back to a second one and finally to the default color
+func parseColorOr(colorStr, fallback string) termbox.Attribute {
+	if color, err := config.ParseColor(colorStr); err == nil {
+		return color
+	}
+	if color, err := config.ParseColor(fallback); err == nil {
+		return color
+	}
+	return termbox.ColorDefault
+}
@@ -0,0 +1,132 @@
+package terminal
+
+import (
+	"testing"
+
+	"go-ascii-calendar/config"
+
+	"github.com/nsf/termbox-go"
+)
+
+func TestStyleResolver_Themes(t *testing.T) {
+	tests := []struct {
+		name   string
+		theme  config.ColorTheme
+		style  StyleName
+		wantFg termbox.Attribute
+		wantBg termbox.Attribute
+	}{
+		{"Default today", config.DefaultTheme, StyleToday, termbox.ColorYellow | termbox.AttrBold, termbox.ColorDefault},
+		{"Default selected today", config.DefaultTheme, StyleSelectedToday, termbox.ColorWhite | termbox.AttrBold, termbox.ColorCyan},
+		{"Light title", config.LightTheme, StyleTitle, termbox.ColorBlue | termbox.AttrBold, termbox.ColorDefault},
+		{"Light error", config.LightTheme, StyleError, termbox.ColorRed | termbox.AttrBold, termbox.ColorDefault},
+		{"Warning", config.DefaultTheme, StyleWarning, termbox.ColorYellow | termbox.AttrBold, termbox.ColorDefault},
+		{"Light warning", config.LightTheme, StyleWarning, termbox.ColorMagenta | termbox.AttrBold, termbox.ColorDefault},
+		{"Muted", config.DefaultTheme, StyleMuted, termbox.ColorBlack | termbox.AttrBold, termbox.ColorDefault},
+		{"Dark muted", config.DarkTheme, StyleMuted, termbox.ColorWhite | termbox.AttrDim, termbox.ColorDefault},
+		{"Theme without warning colors", config.ColorTheme{ErrorFg: "red"}, StyleWarning, termbox.ColorYellow | termbox.AttrBold, termbox.ColorDefault},
+		{"Section adds bold", config.DefaultTheme, StyleSection, termbox.ColorCyan | termbox.AttrBold, termbox.ColorDefault},
+		{"Unknown name is plain text", config.DefaultTheme, StyleName("unknown"), termbox.ColorDefault, termbox.ColorDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style := NewStyleResolver(tt.theme, true).Style(tt.style)
+			if style.Fg != tt.wantFg || style.Bg != tt.wantBg {
+				t.Errorf("Style(%s) = %v/%v, want %v/%v", tt.style, style.Fg, style.Bg, tt.wantFg, tt.wantBg)
+			}
+		})
+	}
+}
+
+func TestStyleResolver_InvalidColorFallsBack(t *testing.T) {
+	theme := config.DefaultTheme
+	theme.TodayFg = "chartreuse"
+
+	got := NewStyleResolver(theme, true).Style(StyleToday)
+	want := NewStyleResolver(config.DefaultTheme, true).Style(StyleToday)
+	if got != want {
+		t.Errorf("Style(today) with an invalid color = %v, want the default %v", got, want)
+	}
+}
+
+func TestStyleResolver_Palette(t *testing.T) {
+	theme := config.DefaultTheme
+	theme.Palette = map[string]string{"accent": "magenta|bold", "loop": "loop"}
+	theme.TodayFg = "accent|underline"
+	theme.ErrorFg = "loop"
+
+	resolver := NewStyleResolver(theme, true)
+	if style := resolver.Style(StyleToday); style.Fg != termbox.ColorMagenta|termbox.AttrBold|termbox.AttrUnderline {
+		t.Errorf("Style(today) with a palette color = %v, want bold underlined magenta", style.Fg)
+	}
+	if got, want := resolver.Style(StyleError), NewStyleResolver(config.DefaultTheme, true).Style(StyleError); got != want {
+		t.Errorf("Style(error) with a palette cycle = %v, want the default %v", got, want)
+	}
+	if color := resolver.Color("accent", termbox.ColorWhite); color != termbox.ColorMagenta|termbox.AttrBold {
+		t.Errorf("Color(accent) = %v, want the palette color", color)
+	}
+}
+
+func TestStyleResolver_Monochrome(t *testing.T) {
+	resolver := NewStyleResolver(config.DarkTheme, false)
+
+	if style := resolver.Style(StyleSelected); style.Fg != termbox.ColorDefault|termbox.AttrReverse {
+		t.Errorf("Monochrome selected style = %v, want reverse video", style)
+	}
+	if style := resolver.Style(StyleEventText); style != (Style{termbox.ColorDefault, termbox.ColorDefault}) {
+		t.Errorf("Monochrome event text = %v, want default colors", style)
+	}
+	if color := resolver.Color("red", termbox.ColorWhite); color != termbox.ColorWhite {
+		t.Errorf("Monochrome Color() = %v, want the fallback", color)
+	}
+}
+
+func TestStyleResolver_SetTheme(t *testing.T) {
+	resolver := NewStyleResolver(config.DefaultTheme, true)
+	before := resolver.Style(StyleToday)
+
+	resolver.SetTheme(config.LightTheme)
+	after := resolver.Style(StyleToday)
+	if after == before {
+		t.Error("SetTheme() should rebuild the styles")
+	}
+	if want := NewStyleResolver(config.LightTheme, true).Style(StyleToday); after != want {
+		t.Errorf("Style(today) after SetTheme() = %v, want %v", after, want)
+	}
+}
+
+func TestStyleResolver_Color(t *testing.T) {
+	resolver := NewStyleResolver(config.DefaultTheme, true)
+
+	if color := resolver.Color("green", termbox.ColorWhite); color != termbox.ColorGreen {
+		t.Errorf("Color(green) = %v, want green", color)
+	}
+	if color := resolver.Color("nope", termbox.ColorWhite); color != termbox.ColorWhite {
+		t.Errorf("Color(nope) = %v, want the fallback", color)
+	}
+}
+
+func TestStyleResolver_FocusStyles(t *testing.T) {
+	tests := []struct {
+		name        string
+		color       bool
+		wantFocused termbox.Attribute
+		wantDimmed  termbox.Attribute
+	}{
+		{"Color", true, termbox.ColorMagenta | termbox.AttrBold | termbox.AttrUnderline, termbox.ColorMagenta | termbox.AttrDim},
+		{"Monochrome", false, termbox.AttrBold | termbox.AttrUnderline, termbox.AttrDim},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewStyleResolver(config.DefaultTheme, tt.color)
+			if fg := resolver.Style(StyleFocusedMonth).Fg; fg != tt.wantFocused {
+				t.Errorf("Focused month fg = %v, want %v", fg, tt.wantFocused)
+			}
+			if fg := resolver.Style(StyleDimmedMonth).Fg; fg != tt.wantDimmed {
+				t.Errorf("Dimmed month fg = %v, want %v", fg, tt.wantDimmed)
+			}
+		})
+	}
+}
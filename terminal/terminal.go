@@ -1,7 +1,10 @@
 package terminal
 
 import (
+	"encoding/base64"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/nsf/termbox-go"
 )
@@ -10,11 +13,36 @@ import (
 type Terminal struct {
 	width  int
 	height int
+	out    io.Writer    // Destination for escape sequences termbox does not cover
+	screen ScreenDriver // Cells drawn on; termbox when nil
+	title  string       // Last window title set, empty when never changed
+
+	colors256 bool // Whether colors of the 256-color palette are drawn as they are
+
+	hyperlinks bool        // Whether PrintLinked makes URLs clickable
+	links      []hyperlink // Links printed since the last flush
+
+	// The frame being drawn and the one last flushed, nil when the screen content is
+	// unknown, so that a flush only passes the cells that changed to the screen. The
+	// links of the frame are written again only when they are new or their cells changed.
+	frame      []termbox.Cell
+	frameWidth int
+	shown      []termbox.Cell
+	frameLinks []hyperlink
+	shownLinks []hyperlink
 }
 
 // NewTerminal creates a new terminal handler
 func NewTerminal() *Terminal {
-	return &Terminal{}
+	return &Terminal{out: os.Stdout, screen: termboxScreen{}}
+}
+
+// driver returns the screen the terminal draws on
+func (t *Terminal) driver() ScreenDriver {
+	if t.screen == nil {
+		return termboxScreen{}
+	}
+	return t.screen
 }
 
 // Initialize initializes the terminal for raw input mode
@@ -30,22 +58,118 @@ func (t *Terminal) Initialize() error {
 	// Update terminal dimensions
 	t.updateSize()
 
+	// A new screen starts blank, so the next flush redraws everything
+	t.shown = nil
+
 	return nil
 }
 
 // Close cleans up and restores the terminal
 func (t *Terminal) Close() {
 	termbox.Close()
+
+	// Restore the window title from before the application started
+	if t.title != "" {
+		popTitle(t.out)
+		t.title = ""
+	}
+}
+
+// SetAlternateScreen chooses whether the calendar is drawn on the alternate screen.
+// termbox switches to the alternate screen on Initialize, so that quitting restores
+// the previous shell contents; passing false draws on the main screen instead.
+func (t *Terminal) SetAlternateScreen(enabled bool) {
+	if !enabled {
+		leaveAlternateScreen(t.out)
+	}
+}
+
+// SetTitle sets the terminal window title; unchanged titles are not rewritten
+func (t *Terminal) SetTitle(title string) {
+	if title == t.title {
+		return
+	}
+	if t.title == "" {
+		pushTitle(t.out) // Remember the original title for Close
+	}
+	writeTitle(t.out, title)
+	t.title = title
+}
+
+// Bell rings the terminal bell
+func (t *Terminal) Bell() {
+	fmt.Fprint(t.out, "\a")
+}
+
+// CopyToClipboard places text on the system clipboard with an OSC 52 escape sequence,
+// which the terminal forwards even over SSH when it supports clipboard writes
+func (t *Terminal) CopyToClipboard(text string) {
+	fmt.Fprintf(t.out, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
 }
 
-// Clear clears the entire screen
+// Clear starts a new frame with every cell blank; the screen only changes on Flush
 func (t *Terminal) Clear() {
-	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	t.ensureFrame()
+	for i := range t.frame {
+		t.frame[i] = termbox.Cell{Ch: ' '}
+	}
+	t.links = t.links[:0]
+	t.frameLinks = t.frameLinks[:0]
+}
+
+// ensureFrame sizes the frame to the terminal, blank and to be redrawn in full when
+// the size changed
+func (t *Terminal) ensureFrame() {
+	if t.frame != nil && t.frameWidth == t.width && len(t.frame) == t.width*t.height {
+		return
+	}
+	t.frame = make([]termbox.Cell, t.width*t.height)
+	t.frameWidth = t.width
+	for i := range t.frame {
+		t.frame[i] = termbox.Cell{Ch: ' '}
+	}
+	t.shown = nil
 }
 
-// Flush flushes all changes to the terminal
+// Flush passes the cells that changed since the last flush to the screen and flushes
+// it, then writes the links of the frame that are new or whose cells were redrawn
 func (t *Terminal) Flush() error {
-	return termbox.Flush()
+	t.ensureFrame()
+	screen := t.driver()
+
+	// Redraw everything when the screen content is unknown or the window was resized
+	// since the frame was sized
+	if width, height := screen.Size(); t.shown == nil || width != t.width || height != t.height {
+		screen.Clear()
+		t.shown = nil
+	}
+	dirty := make([]bool, len(t.frame))
+	for i, cell := range t.frame {
+		if t.shown != nil && t.shown[i] == cell {
+			continue
+		}
+		dirty[i] = true
+		screen.SetCell(i%t.width, i/t.width, cell.Ch, t.outputColor(cell.Fg), t.outputColor(cell.Bg))
+	}
+	if err := screen.Flush(); err != nil {
+		t.shown = nil
+		return err
+	}
+	if width, height := screen.Size(); width == t.width && height == t.height {
+		t.shown = append(t.shown[:0], t.frame...)
+	}
+
+	t.frameLinks = append(t.frameLinks, t.links...)
+	t.frameLinks = t.intactLinks(t.frameLinks)
+	t.links = t.links[:0]
+	for _, link := range t.frameLinks {
+		if link.dirty(dirty, t.width) || !containsLink(t.shownLinks, link) {
+			t.links = append(t.links, link)
+		}
+	}
+	t.shownLinks = append(t.shownLinks[:0], t.frameLinks...)
+	t.writeLinks()
+	return nil
 }
 
 // GetSize returns the current terminal dimensions
@@ -55,25 +179,30 @@ func (t *Terminal) GetSize() (width, height int) {
 
 // updateSize updates the stored terminal dimensions
 func (t *Terminal) updateSize() {
-	t.width, t.height = termbox.Size()
+	t.width, t.height = t.driver().Size()
 }
 
-// CheckSize checks if terminal is large enough (minimum 80x24)
+// CheckSize checks if terminal is large enough for the calendar view (MinWidth x MinHeight)
 func (t *Terminal) CheckSize() bool {
 	t.updateSize()
-	return t.width >= 80 && t.height >= 24
+	return t.width >= MinWidth && t.height >= MinHeight
 }
 
-// SetCell sets a character at the specified position with colors
+// SetCell sets a character at the specified position with colors; positions outside
+// the terminal are ignored
 func (t *Terminal) SetCell(x, y int, ch rune, fg, bg termbox.Attribute) {
-	termbox.SetCell(x, y, ch, fg, bg)
+	t.ensureFrame()
+	if x < 0 || x >= t.width || y < 0 || y >= t.height {
+		return
+	}
+	t.frame[y*t.width+x] = termbox.Cell{Ch: ch, Fg: fg, Bg: bg}
 }
 
 // Print prints a string at the specified position with colors
 func (t *Terminal) Print(x, y int, text string, fg, bg termbox.Attribute) {
 	for i, ch := range text {
 		if x+i < t.width {
-			termbox.SetCell(x+i, y, ch, fg, bg)
+			t.SetCell(x+i, y, ch, fg, bg)
 		}
 	}
 }
@@ -102,10 +231,10 @@ func (t *Terminal) DrawBox(x, y, width, height int, fg, bg termbox.Attribute) {
 	for i := 0; i < width; i++ {
 		if x+i < t.width {
 			if y >= 0 && y < t.height {
-				termbox.SetCell(x+i, y, '-', fg, bg)
+				t.SetCell(x+i, y, '-', fg, bg)
 			}
 			if y+height-1 >= 0 && y+height-1 < t.height {
-				termbox.SetCell(x+i, y+height-1, '-', fg, bg)
+				t.SetCell(x+i, y+height-1, '-', fg, bg)
 			}
 		}
 	}
@@ -114,26 +243,26 @@ func (t *Terminal) DrawBox(x, y, width, height int, fg, bg termbox.Attribute) {
 	for i := 0; i < height; i++ {
 		if y+i >= 0 && y+i < t.height {
 			if x >= 0 && x < t.width {
-				termbox.SetCell(x, y+i, '|', fg, bg)
+				t.SetCell(x, y+i, '|', fg, bg)
 			}
 			if x+width-1 >= 0 && x+width-1 < t.width {
-				termbox.SetCell(x+width-1, y+i, '|', fg, bg)
+				t.SetCell(x+width-1, y+i, '|', fg, bg)
 			}
 		}
 	}
 
 	// Corners
 	if x >= 0 && x < t.width && y >= 0 && y < t.height {
-		termbox.SetCell(x, y, '+', fg, bg) // Top-left
+		t.SetCell(x, y, '+', fg, bg) // Top-left
 	}
 	if x+width-1 >= 0 && x+width-1 < t.width && y >= 0 && y < t.height {
-		termbox.SetCell(x+width-1, y, '+', fg, bg) // Top-right
+		t.SetCell(x+width-1, y, '+', fg, bg) // Top-right
 	}
 	if x >= 0 && x < t.width && y+height-1 >= 0 && y+height-1 < t.height {
-		termbox.SetCell(x, y+height-1, '+', fg, bg) // Bottom-left
+		t.SetCell(x, y+height-1, '+', fg, bg) // Bottom-left
 	}
 	if x+width-1 >= 0 && x+width-1 < t.width && y+height-1 >= 0 && y+height-1 < t.height {
-		termbox.SetCell(x+width-1, y+height-1, '+', fg, bg) // Bottom-right
+		t.SetCell(x+width-1, y+height-1, '+', fg, bg) // Bottom-right
 	}
 }
 
@@ -142,15 +271,24 @@ func (t *Terminal) FillRect(x, y, width, height int, ch rune, fg, bg termbox.Att
 	for row := 0; row < height; row++ {
 		for col := 0; col < width; col++ {
 			if x+col >= 0 && x+col < t.width && y+row >= 0 && y+row < t.height {
-				termbox.SetCell(x+col, y+row, ch, fg, bg)
+				t.SetCell(x+col, y+row, ch, fg, bg)
 			}
 		}
 	}
 }
 
-// PollEvent waits for and returns the next keyboard event
+// PollEvent waits for and returns the next keyboard event, with platform-specific
+// key codes normalized (see normalizeKeyEvent)
 func (t *Terminal) PollEvent() termbox.Event {
-	return termbox.PollEvent()
+	if source, ok := t.screen.(keySource); ok {
+		return normalizeKeyEvent(source.PollEvent())
+	}
+	return normalizeKeyEvent(termbox.PollEvent())
+}
+
+// Interrupt wakes up a pending PollEvent, which then returns an EventInterrupt
+func (t *Terminal) Interrupt() {
+	termbox.Interrupt()
 }
 
 // IsColorSupported checks if the terminal supports colors
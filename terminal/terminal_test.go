@@ -0,0 +1,68 @@
+//go:build !windows
+
+package terminal
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTerminal_SetTitle(t *testing.T) {
+	var out bytes.Buffer
+	term := &Terminal{out: &out}
+
+	term.SetTitle("ascii-calendar — August 2025")
+	expected := "\x1b[22;0t\x1b]0;ascii-calendar — August 2025\x07"
+	if out.String() != expected {
+		t.Errorf("First SetTitle() wrote %q, want %q", out.String(), expected)
+	}
+
+	// Unchanged titles are not rewritten
+	out.Reset()
+	term.SetTitle("ascii-calendar — August 2025")
+	if out.Len() != 0 {
+		t.Errorf("Repeated SetTitle() wrote %q, want nothing", out.String())
+	}
+
+	// Later changes do not push the title again
+	term.SetTitle("ascii-calendar — September 2025")
+	if out.String() != "\x1b]0;ascii-calendar — September 2025\x07" {
+		t.Errorf("Second SetTitle() wrote %q", out.String())
+	}
+}
+
+func TestTerminal_SetAlternateScreen(t *testing.T) {
+	var out bytes.Buffer
+	term := &Terminal{out: &out}
+
+	term.SetAlternateScreen(true)
+	if out.Len() != 0 {
+		t.Errorf("SetAlternateScreen(true) wrote %q, want nothing", out.String())
+	}
+
+	term.SetAlternateScreen(false)
+	if out.String() != "\x1b[?1049l" {
+		t.Errorf("SetAlternateScreen(false) wrote %q, want the leave-alternate-screen sequence", out.String())
+	}
+}
+
+func TestTerminal_Bell(t *testing.T) {
+	var out bytes.Buffer
+	term := &Terminal{out: &out}
+
+	term.Bell()
+	if out.String() != "\a" {
+		t.Errorf("Bell() wrote %q, want BEL", out.String())
+	}
+}
+
+func TestTerminal_CopyToClipboard(t *testing.T) {
+	var out bytes.Buffer
+	term := &Terminal{out: &out}
+
+	term.CopyToClipboard("Mon Aug 11, 2025: free\n")
+	want := "\x1b]52;c;TW9uIEF1ZyAxMSwgMjAyNTogZnJlZQo=\x07"
+	if out.String() != want {
+		t.Errorf("CopyToClipboard() wrote %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,176 @@
+package terminal
+
+import (
+	"fmt"
+
+	"go-ascii-calendar/models"
+)
+
+// TourRegion is the part of the calendar view a tour step points at
+type TourRegion int
+
+const (
+	RegionNone        TourRegion = iota // No region; the callout is centered
+	RegionMonths                        // The month grids
+	RegionEventsPanel                   // The selected date's events below the months
+	RegionLegend                        // The key legend and status bar
+)
+
+// TourStep is one callout of the onboarding tour
+type TourStep struct {
+	Title  string
+	Lines  []string
+	Region TourRegion
+	// Practice is an action the step asks to try; performing it continues the tour.
+	// Steps with ActionNone only continue with Enter.
+	Practice KeyAction
+	Hint     string // How to perform the practice action
+}
+
+// Tour holds the steps of the onboarding tour shown on the first start
+var Tour = []TourStep{
+	{
+		Title: "Welcome to ASCII Calendar",
+		Lines: []string{
+			"This short tour points out the parts of the calendar",
+			"and lets you try a couple of keys.",
+		},
+	},
+	{
+		Title:  "Month grids",
+		Region: RegionMonths,
+		Lines: []string{
+			"The previous, current and next month. The selected day is",
+			"highlighted, today is marked and days with events are colored.",
+		},
+		Practice: ActionMoveRight,
+		Hint:     "Try it: press L or Right to select the next day",
+	},
+	{
+		Title:  "Changing months",
+		Region: RegionMonths,
+		Lines: []string{
+			"B and N (or Page Up/Down) move a whole month;",
+			"c or gg brings you back to today.",
+		},
+		Practice: ActionMonthNext,
+		Hint:     "Try it: press N to show the next month",
+	},
+	{
+		Title:  "Events panel",
+		Region: RegionEventsPanel,
+		Lines: []string{
+			"The events of the selected day. A adds one, E edits, d deletes,",
+			"Enter opens the full list and Shift+D an hour-by-hour view.",
+		},
+	},
+	{
+		Title:  "Key legend",
+		Region: RegionLegend,
+		Lines: []string{
+			"The keys of the current view are always listed here,",
+			"with sync and other status at the bottom right.",
+		},
+	},
+	{
+		Title: "That's it",
+		Lines: []string{
+			"Press ? at any time for the list of keys;",
+			"the help screen can also start this tour again.",
+		},
+	},
+}
+
+// RenderTour draws the calendar view with the callout of a tour step above it
+func (r *Renderer) RenderTour(cal *models.Calendar, selection *models.Selection, step int) error {
+	r.terminal.Clear()
+
+	if r.tooSmall() {
+		return r.renderTooSmall()
+	}
+	if err := r.renderCalendarView(cal, selection); err != nil {
+		return err
+	}
+	if step >= 0 && step < len(Tour) {
+		r.renderTourOverlay(Tour[step], step)
+	}
+
+	return r.terminal.Flush()
+}
+
+// renderTourOverlay frames the step's region and draws its callout box on the side of
+// the screen the region leaves free
+func (r *Renderer) renderTourOverlay(step TourStep, index int) {
+	width, height := r.terminal.GetSize()
+	highlightFg, highlightBg := r.style(StyleToday)
+	fg, bg := r.style(StyleText)
+	titleFg, _ := r.style(StyleTitle)
+	instrFg, _ := r.style(StyleInstructions)
+
+	footer := fmt.Sprintf("Step %d of %d   Enter: next   Esc: end tour", index+1, len(Tour))
+	if index == len(Tour)-1 {
+		footer = fmt.Sprintf("Step %d of %d   Enter: start using the calendar", index+1, len(Tour))
+	}
+	lines := append([]string{step.Title, ""}, step.Lines...)
+	if step.Hint != "" {
+		lines = append(lines, "", step.Hint)
+	}
+	lines = append(lines, "", footer)
+
+	boxWidth := 0
+	for _, line := range lines {
+		boxWidth = max(boxWidth, len(line)+4)
+	}
+	boxWidth = min(boxWidth, width)
+	boxHeight := len(lines) + 2
+
+	// Place the callout below a region in the top half of the screen and above one in
+	// the bottom half; without a region it is centered
+	boxY := (height - boxHeight) / 2
+	if x, y, w, h, ok := r.tourRegionRect(step.Region); ok {
+		r.terminal.DrawBox(x, y, w, h, highlightFg, highlightBg)
+		if y+h/2 < height/2 {
+			boxY = y + h
+		} else {
+			boxY = y - boxHeight
+		}
+	}
+	boxY = max(0, min(boxY, height-boxHeight))
+	boxX := max(0, (width-boxWidth)/2)
+
+	r.terminal.FillRect(boxX, boxY, boxWidth, boxHeight, ' ', fg, bg)
+	r.terminal.DrawBox(boxX, boxY, boxWidth, boxHeight, highlightFg, highlightBg)
+	for i, line := range lines {
+		lineFg := fg
+		switch {
+		case i == 0:
+			lineFg = titleFg
+		case line == step.Hint || line == footer:
+			lineFg = instrFg
+		}
+		if len(line) > boxWidth-4 {
+			line = line[:max(0, boxWidth-4)]
+		}
+		r.terminal.Print(boxX+2, boxY+1+i, line, lineFg, bg)
+	}
+}
+
+// tourRegionRect returns the frame around a region of the calendar view
+func (r *Renderer) tourRegionRect(region TourRegion) (x, y, w, h int, ok bool) {
+	width, height := r.terminal.GetSize()
+	layout := r.layout()
+
+	switch region {
+	case RegionMonths:
+		totalWidth := layout.totalWidth()
+		x = max(0, (width-totalWidth)/2-1)
+		y = max(0, layout.monthsY-1)
+		return x, y, min(totalWidth+2, width-x), layout.separatorY - y + 1, true
+	case RegionEventsPanel:
+		y = layout.separatorY
+		return 0, y, width, height - 2 - y, true
+	case RegionLegend:
+		return 0, height - 3, width, 3, true
+	}
+	return 0, 0, 0, 0, false
+}
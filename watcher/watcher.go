@@ -0,0 +1,119 @@
+package watcher
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// settleDelay groups the burst of notifications one save produces, such as a write to a
+// temporary file followed by a rename, into a single change
+const settleDelay = 200 * time.Millisecond
+
+// Watcher reports when files are written, replaced or removed, for example by a sync
+// tool or an editor. It watches the directories holding the files rather than the files
+// themselves, so a file replaced by a rename is still followed afterwards.
+type Watcher struct {
+	fs    *fsnotify.Watcher
+	files map[string]bool // Cleaned absolute paths of the watched files
+
+	mu      sync.Mutex // Guards the fields below
+	changed bool       // A watched file changed since the last TakeChanged call
+	settle  *time.Timer
+	done    chan struct{}
+}
+
+// New starts watching the files at paths; notify is called once the changes to them
+// have settled, e.g. to wake up the UI so it can reload. Files need not exist yet, but
+// their directories must.
+func New(paths []string, notify func()) (*Watcher, error) {
+	fs, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if notify == nil {
+		notify = func() {}
+	}
+	w := &Watcher{fs: fs, files: make(map[string]bool), done: make(chan struct{})}
+	dirs := make(map[string]bool)
+	for _, path := range paths {
+		path = cleanPath(path)
+		w.files[path] = true
+		dirs[filepath.Dir(path)] = true
+	}
+	for dir := range dirs {
+		if err := fs.Add(dir); err != nil {
+			fs.Close()
+			return nil, err
+		}
+	}
+
+	go w.run(notify)
+	return w, nil
+}
+
+// cleanPath makes path absolute and resolves symlinks, as notifications name the real file
+func cleanPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	}
+	return filepath.Clean(path)
+}
+
+// run handles notifications until Close
+func (w *Watcher) run(notify func()) {
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod || !w.files[filepath.Clean(event.Name)] {
+				continue
+			}
+			w.mu.Lock()
+			w.changed = true
+			if w.settle == nil {
+				w.settle = time.AfterFunc(settleDelay, notify)
+			} else {
+				w.settle.Reset(settleDelay)
+			}
+			w.mu.Unlock()
+		case _, ok := <-w.fs.Errors:
+			// A lost notification is caught up with by the next one
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// TakeChanged reports whether a watched file changed since the previous call, so callers
+// know to reload it
+func (w *Watcher) TakeChanged() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changed := w.changed
+	w.changed = false
+	return changed
+}
+
+// Close stops watching
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	if w.settle != nil {
+		w.settle.Stop()
+	}
+	w.mu.Unlock()
+	close(w.done)
+	return w.fs.Close()
+}
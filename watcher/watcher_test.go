@@ -0,0 +1,61 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.json")
+	if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	notified := make(chan struct{}, 10)
+	w, err := New([]string{path}, func() { notified <- struct{}{} })
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer w.Close()
+
+	wait := func() bool {
+		select {
+		case <-notified:
+			return true
+		case <-time.After(2 * time.Second):
+			return false
+		}
+	}
+
+	// Other files in the directory are not watched
+	if err := os.WriteFile(filepath.Join(dir, "bookmarks.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if wait() || w.TakeChanged() {
+		t.Error("A change to another file should not be reported")
+	}
+
+	// A file replaced by a rename, as sync tools and atomic writes do, is reported and
+	// still followed afterwards
+	for i := 0; i < 2; i++ {
+		temp := filepath.Join(dir, ".events.json.tmp")
+		if err := os.WriteFile(temp, []byte(`{"events": []}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(temp, path); err != nil {
+			t.Fatal(err)
+		}
+		if !wait() {
+			t.Fatalf("Replacement %d was not reported", i+1)
+		}
+		if !w.TakeChanged() {
+			t.Errorf("TakeChanged() after replacement %d = false, want true", i+1)
+		}
+		if w.TakeChanged() {
+			t.Error("TakeChanged() should only report a change once")
+		}
+	}
+}